@@ -1,27 +1,85 @@
 package commands
 
 import (
+	"errors"
 	"strings"
 
 	"github.com/ditrit/badaas/configuration"
+	"github.com/ditrit/badaas/persistence/models"
+	"github.com/ditrit/badaas/services/auth/protocols/basicauth"
+	"github.com/ditrit/badaas/services/rbacservice"
 	"github.com/ditrit/badaas/services/userservice"
 	"go.uber.org/zap"
 )
 
-// Create a super user
+// The email of the super admin user
+const superAdminEmail = "admin-no-reply@badaas.com"
+
+// Returned when the super admin uses the default password and this is refused
+var ErrDefaultAdminPassword = errors.New("the super admin uses the default password, change it or set " +
+	configuration.InitializationDefaultAdminPasswordKey)
+
+// Create a super user and grant it the superadmin role
+//
+// The password of the super admin is not checked against the password policy, but a warning is logged
+// (or the start is refused) while it is the default one.
 func createSuperUser(
 	config configuration.InitializationConfiguration,
+	passwordPolicyConfiguration configuration.PasswordPolicyConfiguration,
 	logger *zap.Logger,
 	userService userservice.UserService,
+	rbacService rbacservice.RBACService,
+	passwordHasher basicauth.PasswordHasher,
 ) error {
+	superAdminRole, herr := rbacService.EnsureSuperAdminRole()
+	if herr != nil {
+		logger.Sugar().Errorf("failed to create the superadmin role %w", herr)
+		return herr
+	}
 	// Create a super admin user and exit with code 1 on error
-	_, err := userService.NewUser("admin", "admin-no-reply@badaas.com", config.GetAdminPassword())
+	superAdmin, err := userService.NewSystemUser("admin", superAdminEmail, config.GetAdminPassword())
 	if err != nil {
 		if !strings.Contains(err.Error(), "already exist in database") {
 			logger.Sugar().Errorf("failed to save the super admin %w", err)
 			return err
 		}
 		logger.Sugar().Infof("The superadmin user already exists in database")
+		superAdmin, herr = userService.GetUserByEmail(superAdminEmail)
+		if herr != nil {
+			logger.Sugar().Errorf("failed to get the super admin %w", herr)
+			return herr
+		}
+	}
+	// the email of the super admin can't receive the verification email
+	herr = userService.MarkEmailVerified(superAdmin.ID)
+	if herr != nil {
+		logger.Sugar().Errorf("failed to verify the email of the super admin %w", herr)
+		return herr
+	}
+	herr = rbacService.AssignRole(superAdmin.ID, superAdminRole.ID)
+	if herr != nil {
+		logger.Sugar().Errorf("failed to grant the superadmin role to the super admin %w", herr)
+		return herr
+	}
+	return checkDefaultAdminPassword(passwordPolicyConfiguration, logger, passwordHasher, superAdmin)
+}
+
+// Log a warning, or return an error if configured, when the super admin uses the default password
+func checkDefaultAdminPassword(
+	passwordPolicyConfiguration configuration.PasswordPolicyConfiguration,
+	logger *zap.Logger,
+	passwordHasher basicauth.PasswordHasher,
+	superAdmin *models.User,
+) error {
+	// the hash of the super admin is checked directly, so that the check is not a login
+	// counted by the login throttling nor refused by the account checks
+	if !passwordHasher.Verify(superAdmin.Password, configuration.DefaultAdminPassword) {
+		return nil
+	}
+	if passwordPolicyConfiguration.GetRefuseDefaultAdminPassword() {
+		logger.Error(ErrDefaultAdminPassword.Error())
+		return ErrDefaultAdminPassword
 	}
+	logger.Warn("The super admin uses the default password, change it as soon as possible")
 	return nil
 }
@@ -0,0 +1,14 @@
+package commands
+
+import (
+	"github.com/ditrit/badaas/configuration"
+	"github.com/ditrit/verdeter"
+)
+
+// initialize the config keys of the authentication of the requests
+//
+// The accepted schemes can only be declared in the configuration file (key `authentication.schemes`).
+func initAuthenticationCommands(cfg *verdeter.VerdeterCommand) {
+	cfg.GKey(configuration.AuthenticationRealmKey, verdeter.IsStr, "", "The realm of the WWW-Authenticate challenges.")
+	cfg.SetDefault(configuration.AuthenticationRealmKey, "badaas")
+}
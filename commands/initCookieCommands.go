@@ -0,0 +1,39 @@
+package commands
+
+import (
+	"github.com/ditrit/badaas/configuration"
+	"github.com/ditrit/verdeter"
+)
+
+// initialize the config keys of the session cookies and of the csrf protection
+func initCookieCommands(cfg *verdeter.VerdeterCommand) {
+	cfg.GKey(configuration.CookieNameKey, verdeter.IsStr, "", "The name of the session cookie.")
+	cfg.SetDefault(configuration.CookieNameKey, "access_token")
+
+	cfg.GKey(configuration.CookieDomainKey, verdeter.IsStr, "",
+		"The domain of the cookies, empty to only send them to the host that set them.")
+	cfg.SetDefault(configuration.CookieDomainKey, "")
+
+	cfg.GKey(configuration.CookiePathKey, verdeter.IsStr, "", "The path of the cookies.")
+	cfg.SetDefault(configuration.CookiePathKey, "/")
+
+	cfg.GKey(configuration.CookieSecureKey, verdeter.IsBool, "", "Only send the cookies over https.")
+	cfg.SetDefault(configuration.CookieSecureKey, true)
+
+	cfg.GKey(configuration.CookieSameSiteKey, verdeter.IsStr, "", "The SameSite attribute of the cookies: strict, lax or none.")
+	cfg.SetDefault(configuration.CookieSameSiteKey, configuration.CookieSameSiteLax)
+
+	cfg.GKey(configuration.CookieHostPrefixKey, verdeter.IsBool, "",
+		"Prefix the names of the cookies with __Host-, the cookies are then secure, on the path / and without domain.")
+	cfg.SetDefault(configuration.CookieHostPrefixKey, false)
+
+	cfg.GKey(configuration.CSRFEnabledKey, verdeter.IsBool, "",
+		"Require a csrf token on the state-changing requests authenticated with the session cookie.")
+	cfg.SetDefault(configuration.CSRFEnabledKey, true)
+
+	cfg.GKey(configuration.CSRFCookieNameKey, verdeter.IsStr, "", "The name of the cookie holding the csrf token.")
+	cfg.SetDefault(configuration.CSRFCookieNameKey, "csrf_token")
+
+	cfg.GKey(configuration.CSRFHeaderNameKey, verdeter.IsStr, "", "The name of the header the csrf token is sent back in.")
+	cfg.SetDefault(configuration.CSRFHeaderNameKey, "X-CSRF-Token")
+}
@@ -0,0 +1,18 @@
+package commands
+
+import (
+	"github.com/ditrit/badaas/configuration"
+	"github.com/ditrit/verdeter"
+)
+
+// initialize email verification related config keys
+func initEmailVerificationCommands(cfg *verdeter.VerdeterCommand) {
+	cfg.GKey(configuration.EmailVerificationRequiredKey, verdeter.IsBool, "", "Refuse to log the users in until their email is verified.")
+	cfg.SetDefault(configuration.EmailVerificationRequiredKey, false)
+
+	cfg.GKey(configuration.EmailVerificationTokenDurationKey, verdeter.IsUint, "", "The duration in seconds during which a verification token can be used.")
+	cfg.SetDefault(configuration.EmailVerificationTokenDurationKey, uint(3600*24)) // 1 day by default
+
+	cfg.GKey(configuration.EmailVerificationResendIntervalKey, verdeter.IsUint, "", "The minimum interval in seconds between two verification emails sent to a user.")
+	cfg.SetDefault(configuration.EmailVerificationResendIntervalKey, uint(60)) // 1 minute by default
+}
@@ -9,5 +9,5 @@ func initInitialisationCommands(cfg *verdeter.VerdeterCommand) {
 
 	cfg.GKey(configuration.InitializationDefaultAdminPasswordKey, verdeter.IsStr, "",
 		"Set the default admin password is the admin user is not created yet.")
-	cfg.SetDefault(configuration.InitializationDefaultAdminPasswordKey, "admin")
+	cfg.SetDefault(configuration.InitializationDefaultAdminPasswordKey, configuration.DefaultAdminPassword)
 }
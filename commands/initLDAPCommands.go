@@ -0,0 +1,55 @@
+package commands
+
+import (
+	"github.com/ditrit/badaas/configuration"
+	"github.com/ditrit/badaas/services/auth/protocols/ldap"
+	"github.com/ditrit/verdeter"
+)
+
+// initialize LDAP related config keys
+//
+// The LDAP login is disabled while the url is empty.
+// The roles of the groups can only be declared in the configuration file (key `ldap.groupRoles`).
+func initLDAPCommands(cfg *verdeter.VerdeterCommand) {
+	cfg.GKey(configuration.LDAPURLKey, verdeter.IsStr, "", "The ldap:// or ldaps:// url of the directory, the LDAP login is disabled if empty.")
+	cfg.SetDefault(configuration.LDAPURLKey, "")
+
+	cfg.GKey(configuration.LDAPStartTLSKey, verdeter.IsBool, "", "Upgrade the ldap:// connection to TLS with StartTLS.")
+	cfg.SetDefault(configuration.LDAPStartTLSKey, false)
+
+	cfg.GKey(configuration.LDAPCACertificateKey, verdeter.IsStr, "", "The path of the PEM encoded certificates trusted for the directory, the system ones are used if empty.")
+	cfg.SetDefault(configuration.LDAPCACertificateKey, "")
+
+	cfg.GKey(configuration.LDAPBindDNKey, verdeter.IsStr, "", "The dn of the service account searching the users.")
+	cfg.SetDefault(configuration.LDAPBindDNKey, "")
+
+	cfg.GKey(configuration.LDAPBindPasswordKey, verdeter.IsStr, "", "The password of the service account.")
+	cfg.SetDefault(configuration.LDAPBindPasswordKey, "")
+
+	cfg.GKey(configuration.LDAPBaseDNKey, verdeter.IsStr, "", "The dn under which the users are searched.")
+	cfg.SetDefault(configuration.LDAPBaseDNKey, "")
+
+	cfg.GKey(configuration.LDAPUserFilterKey, verdeter.IsStr, "", "The filter finding a user, %s is replaced by the login.")
+	cfg.SetDefault(configuration.LDAPUserFilterKey, ldap.DefaultUserFilter)
+
+	cfg.GKey(configuration.LDAPEmailAttributeKey, verdeter.IsStr, "", "The name of the attribute holding the email of the user.")
+	cfg.SetDefault(configuration.LDAPEmailAttributeKey, "mail")
+
+	cfg.GKey(configuration.LDAPUsernameAttributeKey, verdeter.IsStr, "", "The name of the attribute holding the username of the user.")
+	cfg.SetDefault(configuration.LDAPUsernameAttributeKey, "uid")
+
+	cfg.GKey(configuration.LDAPGroupsAttributeKey, verdeter.IsStr, "", "The name of the attribute listing the groups of the user.")
+	cfg.SetDefault(configuration.LDAPGroupsAttributeKey, "memberOf")
+
+	cfg.GKey(configuration.LDAPGroupBaseDNKey, verdeter.IsStr, "", "The dn under which the groups of the user are searched, the groups are not searched if empty.")
+	cfg.SetDefault(configuration.LDAPGroupBaseDNKey, "")
+
+	cfg.GKey(configuration.LDAPGroupFilterKey, verdeter.IsStr, "", "The filter finding the groups of a user, %s is replaced by the dn of the user.")
+	cfg.SetDefault(configuration.LDAPGroupFilterKey, "(member=%s)")
+
+	cfg.GKey(configuration.LDAPProvisioningKey, verdeter.IsBool, "", "Create a user for the directory users unknown to badaas.")
+	cfg.SetDefault(configuration.LDAPProvisioningKey, true)
+
+	cfg.GKey(configuration.LDAPTimeoutKey, verdeter.IsUint, "", "The timeout in seconds of the connection and of the requests to the directory.")
+	cfg.SetDefault(configuration.LDAPTimeoutKey, uint(10))
+}
@@ -0,0 +1,30 @@
+package commands
+
+import (
+	"github.com/ditrit/badaas/configuration"
+	"github.com/ditrit/verdeter"
+)
+
+// initialize login throttling related config keys
+func initLoginThrottlingCommands(cfg *verdeter.VerdeterCommand) {
+	cfg.GKey(configuration.LoginThrottlingEnabledKey, verdeter.IsBool, "", "Throttle the failed login attempts and lock the accounts and ip addresses on too many failures.")
+	cfg.SetDefault(configuration.LoginThrottlingEnabledKey, true)
+
+	cfg.GKey(configuration.LoginThrottlingAccountMaxAttemptsKey, verdeter.IsUint, "", "The number of failed login attempts on an account before it is locked.")
+	cfg.SetDefault(configuration.LoginThrottlingAccountMaxAttemptsKey, uint(5))
+
+	cfg.GKey(configuration.LoginThrottlingIPMaxAttemptsKey, verdeter.IsUint, "", "The number of failed login attempts from an ip address before it is locked.")
+	cfg.SetDefault(configuration.LoginThrottlingIPMaxAttemptsKey, uint(50))
+
+	cfg.GKey(configuration.LoginThrottlingBaseDelayKey, verdeter.IsUint, "", "The delay in seconds imposed after the first failed login attempt, it doubles after each new failure.")
+	cfg.SetDefault(configuration.LoginThrottlingBaseDelayKey, uint(1))
+
+	cfg.GKey(configuration.LoginThrottlingMaxDelayKey, verdeter.IsUint, "", "The maximum delay in seconds imposed between two login attempts.")
+	cfg.SetDefault(configuration.LoginThrottlingMaxDelayKey, uint(30))
+
+	cfg.GKey(configuration.LoginThrottlingLockoutDurationKey, verdeter.IsUint, "", "The duration in seconds of a lockout.")
+	cfg.SetDefault(configuration.LoginThrottlingLockoutDurationKey, uint(900)) // 15 minutes by default
+
+	cfg.GKey(configuration.LoginThrottlingWindowKey, verdeter.IsUint, "", "The duration in seconds after which the failed login attempts are forgotten.")
+	cfg.SetDefault(configuration.LoginThrottlingWindowKey, uint(3600)) // 1 hour by default
+}
@@ -0,0 +1,45 @@
+package commands
+
+import (
+	"github.com/ditrit/badaas/configuration"
+	"github.com/ditrit/verdeter"
+)
+
+// initialize mail related config keys
+func initMailCommands(cfg *verdeter.VerdeterCommand) {
+	cfg.GKey(configuration.MailTransportKey, verdeter.IsStr, "", "The transport used to send the mails: log, file or smtp.")
+	cfg.SetDefault(configuration.MailTransportKey, configuration.MailTransportLog)
+
+	cfg.GKey(configuration.MailFromKey, verdeter.IsStr, "", "The sender address of the mails.")
+	cfg.SetDefault(configuration.MailFromKey, "no-reply@badaas.com")
+
+	cfg.GKey(configuration.MailDefaultLocaleKey, verdeter.IsStr, "", "The locale of the mails sent to the users without a locale.")
+	cfg.SetDefault(configuration.MailDefaultLocaleKey, "en")
+
+	cfg.GKey(configuration.MailTemplatesPathKey, verdeter.IsStr, "", "The directory of the templates overriding the default ones.")
+	cfg.SetDefault(configuration.MailTemplatesPathKey, "")
+
+	cfg.GKey(configuration.MailFilePathKey, verdeter.IsStr, "", "The directory where the file transport writes the mails.")
+	cfg.SetDefault(configuration.MailFilePathKey, "mails")
+
+	cfg.GKey(configuration.MailSMTPHostKey, verdeter.IsStr, "", "The host of the smtp server.")
+	cfg.SetDefault(configuration.MailSMTPHostKey, "localhost")
+
+	cfg.GKey(configuration.MailSMTPPortKey, verdeter.IsInt, "", "The port of the smtp server.")
+	cfg.SetDefault(configuration.MailSMTPPortKey, 587)
+
+	cfg.GKey(configuration.MailSMTPUsernameKey, verdeter.IsStr, "", "The username used to authenticate on the smtp server, no authentication if empty.")
+	cfg.SetDefault(configuration.MailSMTPUsernameKey, "")
+
+	cfg.GKey(configuration.MailSMTPPasswordKey, verdeter.IsStr, "", "The password used to authenticate on the smtp server.")
+	cfg.SetDefault(configuration.MailSMTPPasswordKey, "")
+
+	cfg.GKey(configuration.MailSMTPStartTLSKey, verdeter.IsBool, "", "Upgrade the connection to the smtp server with STARTTLS.")
+	cfg.SetDefault(configuration.MailSMTPStartTLSKey, true)
+
+	cfg.GKey(configuration.MailQueueIntervalKey, verdeter.IsUint, "", "The interval in seconds between two runs of the send queue.")
+	cfg.SetDefault(configuration.MailQueueIntervalKey, uint(5))
+
+	cfg.GKey(configuration.MailQueueMaxAttemptsKey, verdeter.IsUint, "", "The number of attempts to send a mail before giving up.")
+	cfg.SetDefault(configuration.MailQueueMaxAttemptsKey, uint(5))
+}
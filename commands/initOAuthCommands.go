@@ -0,0 +1,20 @@
+package commands
+
+import (
+	"github.com/ditrit/badaas/configuration"
+	"github.com/ditrit/verdeter"
+)
+
+// initialize OAuth 2.0 authorization server related config keys
+//
+// The scopes can only be declared in the configuration file (key `oauth.scopes`).
+func initOAuthCommands(cfg *verdeter.VerdeterCommand) {
+	cfg.GKey(configuration.OAuthCodeDurationKey, verdeter.IsUint, "", "The duration in seconds during which an authorization code can be exchanged.")
+	cfg.SetDefault(configuration.OAuthCodeDurationKey, uint(60)) // 1 minute by default
+
+	cfg.GKey(configuration.OAuthAccessTokenDurationKey, verdeter.IsUint, "", "The duration in seconds of validity of the OAuth access tokens.")
+	cfg.SetDefault(configuration.OAuthAccessTokenDurationKey, uint(3600)) // 1 hour by default
+
+	cfg.GKey(configuration.OAuthRefreshTokenDurationKey, verdeter.IsUint, "", "The duration in seconds of validity of the OAuth refresh tokens, renewed on each rotation.")
+	cfg.SetDefault(configuration.OAuthRefreshTokenDurationKey, uint(2592000)) // 30 days by default
+}
@@ -0,0 +1,23 @@
+package commands
+
+import (
+	"github.com/ditrit/badaas/configuration"
+	"github.com/ditrit/verdeter"
+)
+
+// initialize OpenID Connect related config keys
+//
+// The providers can only be declared in the configuration file (key `oidc.providers`).
+func initOIDCCommands(cfg *verdeter.VerdeterCommand) {
+	cfg.GKey(configuration.OIDCRequestDurationKey, verdeter.IsUint, "", "The duration in seconds during which the user can log in on the OpenID provider.")
+	cfg.SetDefault(configuration.OIDCRequestDurationKey, uint(600)) // 10 minutes by default
+
+	cfg.GKey(configuration.OIDCProvisioningKey, verdeter.IsBool, "", "Create a user for the unknown OpenID identities.")
+	cfg.SetDefault(configuration.OIDCProvisioningKey, true)
+
+	cfg.GKey(configuration.OIDCLinkByEmailKey, verdeter.IsBool, "", "Link an unknown OpenID identity to the user with the same verified email.")
+	cfg.SetDefault(configuration.OIDCLinkByEmailKey, true)
+
+	cfg.GKey(configuration.OIDCLoginRedirectURLKey, verdeter.IsStr, "", "The url the user is redirected to once logged in with an OpenID provider, the user is described in JSON if empty.")
+	cfg.SetDefault(configuration.OIDCLoginRedirectURLKey, "")
+}
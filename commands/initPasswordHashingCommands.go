@@ -0,0 +1,24 @@
+package commands
+
+import (
+	"github.com/ditrit/badaas/configuration"
+	"github.com/ditrit/verdeter"
+)
+
+// initialize password hashing related config keys
+func initPasswordHashingCommands(cfg *verdeter.VerdeterCommand) {
+	cfg.GKey(configuration.PasswordHashingAlgorithmKey, verdeter.IsStr, "", "The algorithm used to hash the passwords: argon2id or bcrypt.")
+	cfg.SetDefault(configuration.PasswordHashingAlgorithmKey, configuration.PasswordHashingArgon2id)
+
+	cfg.GKey(configuration.PasswordHashingArgon2idMemoryKey, verdeter.IsUint, "", "The memory in KiB used by argon2id.")
+	cfg.SetDefault(configuration.PasswordHashingArgon2idMemoryKey, uint(64*1024)) // 64 MiB by default
+
+	cfg.GKey(configuration.PasswordHashingArgon2idIterationsKey, verdeter.IsUint, "", "The number of passes over the memory of argon2id.")
+	cfg.SetDefault(configuration.PasswordHashingArgon2idIterationsKey, uint(3))
+
+	cfg.GKey(configuration.PasswordHashingArgon2idParallelismKey, verdeter.IsUint, "", "The number of threads used by argon2id.")
+	cfg.SetDefault(configuration.PasswordHashingArgon2idParallelismKey, uint(4))
+
+	cfg.GKey(configuration.PasswordHashingBcryptCostKey, verdeter.IsInt, "", "The cost of bcrypt, between 4 and 31.")
+	cfg.SetDefault(configuration.PasswordHashingBcryptCostKey, 10)
+}
@@ -0,0 +1,42 @@
+package commands
+
+import (
+	"github.com/ditrit/badaas/configuration"
+	"github.com/ditrit/verdeter"
+)
+
+// initialize password policy related config keys
+func initPasswordPolicyCommands(cfg *verdeter.VerdeterCommand) {
+	cfg.GKey(configuration.PasswordPolicyMinLengthKey, verdeter.IsUint, "", "The minimum number of characters of a password.")
+	cfg.SetDefault(configuration.PasswordPolicyMinLengthKey, uint(8))
+
+	cfg.GKey(configuration.PasswordPolicyMaxLengthKey, verdeter.IsUint, "", "The maximum number of characters of a password.")
+	cfg.SetDefault(configuration.PasswordPolicyMaxLengthKey, uint(128))
+
+	cfg.GKey(configuration.PasswordPolicyRequireLowercaseKey, verdeter.IsBool, "", "Require a lowercase letter in the passwords.")
+	cfg.SetDefault(configuration.PasswordPolicyRequireLowercaseKey, false)
+
+	cfg.GKey(configuration.PasswordPolicyRequireUppercaseKey, verdeter.IsBool, "", "Require an uppercase letter in the passwords.")
+	cfg.SetDefault(configuration.PasswordPolicyRequireUppercaseKey, false)
+
+	cfg.GKey(configuration.PasswordPolicyRequireDigitKey, verdeter.IsBool, "", "Require a digit in the passwords.")
+	cfg.SetDefault(configuration.PasswordPolicyRequireDigitKey, false)
+
+	cfg.GKey(configuration.PasswordPolicyRequireSymbolKey, verdeter.IsBool, "", "Require a character that is neither a letter nor a digit in the passwords.")
+	cfg.SetDefault(configuration.PasswordPolicyRequireSymbolKey, false)
+
+	cfg.GKey(configuration.PasswordPolicyForbidUserInfoKey, verdeter.IsBool, "", "Refuse the passwords containing the username or the email of the user.")
+	cfg.SetDefault(configuration.PasswordPolicyForbidUserInfoKey, true)
+
+	cfg.GKey(configuration.PasswordPolicyHistorySizeKey, verdeter.IsUint, "", "The number of last passwords of a user that can't be reused, including the current one.")
+	cfg.SetDefault(configuration.PasswordPolicyHistorySizeKey, uint(0))
+
+	cfg.GKey(configuration.PasswordPolicyBreachedEnabledKey, verdeter.IsBool, "", "Refuse the passwords found in the list of breached passwords.")
+	cfg.SetDefault(configuration.PasswordPolicyBreachedEnabledKey, true)
+
+	cfg.GKey(configuration.PasswordPolicyBreachedPathKey, verdeter.IsStr, "", "The path of the list of SHA-1 hashes of breached passwords, the bundled list is used if empty.")
+	cfg.SetDefault(configuration.PasswordPolicyBreachedPathKey, "")
+
+	cfg.GKey(configuration.PasswordPolicyRefuseDefaultAdminPasswordKey, verdeter.IsBool, "", "Refuse to start while the super admin uses the default password.")
+	cfg.SetDefault(configuration.PasswordPolicyRefuseDefaultAdminPasswordKey, false)
+}
@@ -0,0 +1,12 @@
+package commands
+
+import (
+	"github.com/ditrit/badaas/configuration"
+	"github.com/ditrit/verdeter"
+)
+
+// initialize password reset related config keys
+func initPasswordResetCommands(cfg *verdeter.VerdeterCommand) {
+	cfg.GKey(configuration.PasswordResetTokenDurationKey, verdeter.IsUint, "", "The duration in seconds during which a password reset token can be used.")
+	cfg.SetDefault(configuration.PasswordResetTokenDurationKey, uint(3600)) // 1 hour by default
+}
@@ -0,0 +1,14 @@
+package commands
+
+import (
+	"github.com/ditrit/badaas/configuration"
+	"github.com/ditrit/verdeter"
+)
+
+// initialize policy engine related config keys
+//
+// The policies themselves can only be declared in the configuration file (key `policy.policies`).
+func initPolicyCommands(cfg *verdeter.VerdeterCommand) {
+	cfg.GKey(configuration.PolicyExplainKey, verdeter.IsBool, "", "Log the evaluation of the policies and the reason why an access is denied.")
+	cfg.SetDefault(configuration.PolicyExplainKey, false)
+}
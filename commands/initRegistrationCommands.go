@@ -0,0 +1,18 @@
+package commands
+
+import (
+	"github.com/ditrit/badaas/configuration"
+	"github.com/ditrit/verdeter"
+)
+
+// initialize self-service registration related config keys
+//
+// The allowed domains and the invitation codes can only be declared in the configuration file
+// (keys `registration.allowedDomains` and `registration.invitationCodes`).
+func initRegistrationCommands(cfg *verdeter.VerdeterCommand) {
+	cfg.GKey(configuration.RegistrationEnabledKey, verdeter.IsBool, "", "Allow the users to create their account.")
+	cfg.SetDefault(configuration.RegistrationEnabledKey, false)
+
+	cfg.GKey(configuration.RegistrationAutoLoginKey, verdeter.IsBool, "", "Log the users in once registered.")
+	cfg.SetDefault(configuration.RegistrationAutoLoginKey, false)
+}
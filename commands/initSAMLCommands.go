@@ -0,0 +1,53 @@
+package commands
+
+import (
+	"github.com/ditrit/badaas/configuration"
+	"github.com/ditrit/verdeter"
+)
+
+// initialize SAML related config keys
+//
+// The SAML login is disabled while the entity id is empty.
+func initSAMLCommands(cfg *verdeter.VerdeterCommand) {
+	cfg.GKey(configuration.SAMLEntityIDKey, verdeter.IsStr, "", "The entity id of badaas as a SAML service provider, the SAML login is disabled if empty.")
+	cfg.SetDefault(configuration.SAMLEntityIDKey, "")
+
+	cfg.GKey(configuration.SAMLAssertionConsumerURLKey, verdeter.IsStr, "", "The url the identity provider posts its responses to.")
+	cfg.SetDefault(configuration.SAMLAssertionConsumerURLKey, "")
+
+	cfg.GKey(configuration.SAMLCertificateKey, verdeter.IsStr, "", "The path of the PEM encoded certificate of the service provider.")
+	cfg.SetDefault(configuration.SAMLCertificateKey, "")
+
+	cfg.GKey(configuration.SAMLPrivateKeyKey, verdeter.IsStr, "", "The path of the PEM encoded RSA key signing the AuthnRequests.")
+	cfg.SetDefault(configuration.SAMLPrivateKeyKey, "")
+
+	cfg.GKey(configuration.SAMLIdPEntityIDKey, verdeter.IsStr, "", "The entity id of the SAML identity provider.")
+	cfg.SetDefault(configuration.SAMLIdPEntityIDKey, "")
+
+	cfg.GKey(configuration.SAMLIdPSSOURLKey, verdeter.IsStr, "", "The url of the single sign on service of the identity provider.")
+	cfg.SetDefault(configuration.SAMLIdPSSOURLKey, "")
+
+	cfg.GKey(configuration.SAMLIdPCertificateKey, verdeter.IsStr, "", "The path of the PEM encoded certificates the identity provider signs with.")
+	cfg.SetDefault(configuration.SAMLIdPCertificateKey, "")
+
+	cfg.GKey(configuration.SAMLEmailAttributeKey, verdeter.IsStr, "", "The name of the attribute holding the email of the user.")
+	cfg.SetDefault(configuration.SAMLEmailAttributeKey, "email")
+
+	cfg.GKey(configuration.SAMLUsernameAttributeKey, verdeter.IsStr, "", "The name of the attribute holding the username of the user.")
+	cfg.SetDefault(configuration.SAMLUsernameAttributeKey, "username")
+
+	cfg.GKey(configuration.SAMLProvisioningKey, verdeter.IsBool, "", "Create a user for the unknown SAML identities.")
+	cfg.SetDefault(configuration.SAMLProvisioningKey, true)
+
+	cfg.GKey(configuration.SAMLLinkByEmailKey, verdeter.IsBool, "", "Link an unknown SAML identity to the user with the same email.")
+	cfg.SetDefault(configuration.SAMLLinkByEmailKey, false)
+
+	cfg.GKey(configuration.SAMLRequestDurationKey, verdeter.IsUint, "", "The duration in seconds during which the user can log in on the identity provider.")
+	cfg.SetDefault(configuration.SAMLRequestDurationKey, uint(600)) // 10 minutes by default
+
+	cfg.GKey(configuration.SAMLMaxClockSkewKey, verdeter.IsUint, "", "The tolerated difference in seconds between the clocks of the providers.")
+	cfg.SetDefault(configuration.SAMLMaxClockSkewKey, uint(180)) // 3 minutes by default
+
+	cfg.GKey(configuration.SAMLLoginRedirectURLKey, verdeter.IsStr, "", "The url the user is redirected to once logged in with SAML, the user is described in JSON if empty.")
+	cfg.SetDefault(configuration.SAMLLoginRedirectURLKey, "")
+}
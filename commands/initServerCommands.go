@@ -6,6 +6,9 @@ import (
 	"github.com/ditrit/verdeter/validators"
 )
 
+// initialize http server related config keys
+//
+// The trusted proxies can only be declared in the configuration file (key `server.trustedProxies`).
 func initServerCommands(cfg *verdeter.VerdeterCommand) {
 	cfg.GKey(configuration.ServerTimeoutKey, verdeter.IsInt, "", "Maximum timeout of the http server in second (default is 15s)")
 	cfg.SetDefault(configuration.ServerTimeoutKey, 15)
@@ -20,4 +23,8 @@ func initServerCommands(cfg *verdeter.VerdeterCommand) {
 	cfg.GKey(configuration.ServerPaginationMaxElemPerPage, verdeter.IsUint, "", "The max number of records returned per page")
 	cfg.SetDefault(configuration.ServerPaginationMaxElemPerPage, 100)
 
+	cfg.GKey(configuration.ServerTLSCertificateKey, verdeter.IsStr, "", "The path of the PEM encoded certificate of the server, plain http is served if empty.")
+	cfg.GKey(configuration.ServerTLSKeyKey, verdeter.IsStr, "", "The path of the PEM encoded private key of the server.")
+	cfg.GKey(configuration.ServerTLSClientCAsKey, verdeter.IsStr, "", "The path of the PEM encoded certificates trusted for the client certificates, they are not asked if empty.")
+
 }
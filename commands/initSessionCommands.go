@@ -14,6 +14,25 @@ func initSessionCommands(cfg *verdeter.VerdeterCommand) {
 		verdeter.IsUint, "", "The refresh interval in seconds. Badaas refresh it's internal session cache periodically.")
 	cfg.SetDefault(configuration.SessionPullIntervalKey, uint(30)) // 30 seconds by default
 
+	cfg.LKey(configuration.SessionRevocationPullIntervalKey, verdeter.IsUint, "",
+		"The interval in seconds between the pulls of the sessions changed or revoked by the other nodes.")
+	cfg.SetDefault(configuration.SessionRevocationPullIntervalKey, uint(5)) // 5 seconds by default
+
 	cfg.LKey(configuration.SessionRollIntervalKey, verdeter.IsUint, "", "The interval in which the user can renew it's session by making a request.")
 	cfg.SetDefault(configuration.SessionRollIntervalKey, uint(3600)) // 1 hour by default
+
+	cfg.LKey(configuration.SessionModeKey, verdeter.IsStr, "",
+		"How the requests are authenticated: database for the sessions stored in the database, jwt for signed access tokens.")
+	cfg.SetDefault(configuration.SessionModeKey, configuration.SessionModeDatabase)
+
+	cfg.LKey(configuration.SessionBindingKey, verdeter.IsStr, "",
+		"Refuse the sessions used from another client: none, device or deviceAndNetwork.")
+	cfg.SetDefault(configuration.SessionBindingKey, configuration.SessionBindingNone)
+
+	cfg.LKey(configuration.SessionJWTAlgorithmKey, verdeter.IsStr, "", "The algorithm signing the JWT access tokens.")
+	cfg.SetDefault(configuration.SessionJWTAlgorithmKey, "ES256")
+
+	cfg.LKey(configuration.SessionJWTAccessTokenDurationKey, verdeter.IsUint, "",
+		"The duration in seconds of validity of the JWT access tokens.")
+	cfg.SetDefault(configuration.SessionJWTAccessTokenDurationKey, uint(300)) // 5 minutes by default
 }
@@ -0,0 +1,23 @@
+package commands
+
+import (
+	"github.com/ditrit/badaas/configuration"
+	"github.com/ditrit/verdeter"
+)
+
+// initialize two-factor authentication related config keys
+//
+// The roles requiring a second factor can only be declared in the configuration file (key `twoFactor.requiredRoles`).
+func initTwoFactorCommands(cfg *verdeter.VerdeterCommand) {
+	cfg.GKey(configuration.TwoFactorIssuerKey, verdeter.IsStr, "", "The issuer displayed by the authenticator apps.")
+	cfg.SetDefault(configuration.TwoFactorIssuerKey, "badaas")
+
+	cfg.GKey(configuration.TwoFactorRecoveryCodesKey, verdeter.IsUint, "", "The number of recovery codes generated for a user.")
+	cfg.SetDefault(configuration.TwoFactorRecoveryCodesKey, uint(10))
+
+	cfg.GKey(configuration.TwoFactorChallengeDurationKey, verdeter.IsUint, "", "The duration in seconds during which the second factor can be given after the password.")
+	cfg.SetDefault(configuration.TwoFactorChallengeDurationKey, uint(300)) // 5 minutes by default
+
+	cfg.GKey(configuration.TwoFactorChallengeAttemptsKey, verdeter.IsUint, "", "The number of wrong codes after which the login has to be started again.")
+	cfg.SetDefault(configuration.TwoFactorChallengeAttemptsKey, uint(5))
+}
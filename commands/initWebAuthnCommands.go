@@ -0,0 +1,23 @@
+package commands
+
+import (
+	"github.com/ditrit/badaas/configuration"
+	"github.com/ditrit/verdeter"
+)
+
+// initialize WebAuthn related config keys
+//
+// The origins allowed to run the ceremonies can only be declared in the configuration file (key `webauthn.origins`).
+func initWebAuthnCommands(cfg *verdeter.VerdeterCommand) {
+	cfg.GKey(configuration.WebAuthnRelyingPartyIDKey, verdeter.IsStr, "", "The domain the WebAuthn credentials are bound to.")
+	cfg.SetDefault(configuration.WebAuthnRelyingPartyIDKey, "localhost")
+
+	cfg.GKey(configuration.WebAuthnRelyingPartyNameKey, verdeter.IsStr, "", "The name displayed by the authenticators.")
+	cfg.SetDefault(configuration.WebAuthnRelyingPartyNameKey, "badaas")
+
+	cfg.GKey(configuration.WebAuthnTimeoutKey, verdeter.IsUint, "", "The duration in seconds during which a WebAuthn ceremony can be completed.")
+	cfg.SetDefault(configuration.WebAuthnTimeoutKey, uint(300)) // 5 minutes by default
+
+	cfg.GKey(configuration.WebAuthnUserVerificationKey, verdeter.IsStr, "", "The user verification asked for the registrations and the second factors: required, preferred or discouraged.")
+	cfg.SetDefault(configuration.WebAuthnUserVerificationKey, "preferred")
+}
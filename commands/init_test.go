@@ -4,14 +4,32 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/ditrit/badaas/httperrors"
 	mocks "github.com/ditrit/badaas/mocks/configuration"
+	mockBasicAuth "github.com/ditrit/badaas/mocks/services/auth/protocols/basicauth"
+	mockRBACServices "github.com/ditrit/badaas/mocks/services/rbacservice"
 	mockUserServices "github.com/ditrit/badaas/mocks/services/userservice"
+	"github.com/ditrit/badaas/persistence/models"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zaptest/observer"
 )
 
+var (
+	superAdmin = &models.User{
+		BaseModel: models.BaseModel{ID: uuid.New()},
+		Username:  "admin",
+		Email:     "admin-no-reply@badaas.com",
+		Password:  []byte("hash"),
+	}
+	superAdminRole = &models.Role{
+		BaseModel: models.BaseModel{ID: uuid.New()},
+		Name:      "superadmin",
+	}
+)
+
 func TestCreateSuperUser(t *testing.T) {
 	core, _ := observer.New(zap.DebugLevel)
 	logger := zap.New(core)
@@ -19,12 +37,21 @@ func TestCreateSuperUser(t *testing.T) {
 	initializationConfig.On("GetAdminPassword").Return("adminpassword")
 	userService := mockUserServices.NewUserService(t)
 	userService.
-		On("NewUser", "admin", "admin-no-reply@badaas.com", "adminpassword").
-		Return(nil, nil)
+		On("NewSystemUser", "admin", "admin-no-reply@badaas.com", "adminpassword").
+		Return(superAdmin, nil)
+	rbacService := mockRBACServices.NewRBACService(t)
+	rbacService.On("EnsureSuperAdminRole").Return(superAdminRole, nil)
+	userService.On("MarkEmailVerified", superAdmin.ID).Return(nil)
+	rbacService.On("AssignRole", superAdmin.ID, superAdminRole.ID).Return(nil)
+	passwordHasher := mockBasicAuth.NewPasswordHasher(t)
+	passwordHasher.On("Verify", superAdmin.Password, "admin").Return(false)
 	err := createSuperUser(
 		initializationConfig,
+		mocks.NewPasswordPolicyConfiguration(t),
 		logger,
 		userService,
+		rbacService,
+		passwordHasher,
 	)
 	assert.NoError(t, err)
 }
@@ -36,12 +63,24 @@ func TestCreateSuperUser_UserExists(t *testing.T) {
 	initializationConfig.On("GetAdminPassword").Return("adminpassword")
 	userService := mockUserServices.NewUserService(t)
 	userService.
-		On("NewUser", "admin", "admin-no-reply@badaas.com", "adminpassword").
+		On("NewSystemUser", "admin", "admin-no-reply@badaas.com", "adminpassword").
 		Return(nil, errors.New("user already exist in database"))
+	userService.
+		On("GetUserByEmail", "admin-no-reply@badaas.com").
+		Return(superAdmin, nil)
+	rbacService := mockRBACServices.NewRBACService(t)
+	rbacService.On("EnsureSuperAdminRole").Return(superAdminRole, nil)
+	userService.On("MarkEmailVerified", superAdmin.ID).Return(nil)
+	rbacService.On("AssignRole", superAdmin.ID, superAdminRole.ID).Return(nil)
+	passwordHasher := mockBasicAuth.NewPasswordHasher(t)
+	passwordHasher.On("Verify", superAdmin.Password, "admin").Return(false)
 	err := createSuperUser(
 		initializationConfig,
+		mocks.NewPasswordPolicyConfiguration(t),
 		logger,
 		userService,
+		rbacService,
+		passwordHasher,
 	)
 	assert.NoError(t, err)
 
@@ -55,14 +94,81 @@ func TestCreateSuperUser_UserServiceError(t *testing.T) {
 	initializationConfig.On("GetAdminPassword").Return("adminpassword")
 	userService := mockUserServices.NewUserService(t)
 	userService.
-		On("NewUser", "admin", "admin-no-reply@badaas.com", "adminpassword").
+		On("NewSystemUser", "admin", "admin-no-reply@badaas.com", "adminpassword").
 		Return(nil, errors.New("email not valid"))
+	rbacService := mockRBACServices.NewRBACService(t)
+	rbacService.On("EnsureSuperAdminRole").Return(superAdminRole, nil)
+	err := createSuperUser(
+		initializationConfig,
+		mocks.NewPasswordPolicyConfiguration(t),
+		logger,
+		userService,
+		rbacService,
+		mockBasicAuth.NewPasswordHasher(t),
+	)
+	assert.Error(t, err)
+
+	require.Equal(t, 1, logs.Len())
+}
+
+func TestCreateSuperUser_RoleError(t *testing.T) {
+	core, logs := observer.New(zap.DebugLevel)
+	logger := zap.New(core)
+	initializationConfig := mocks.NewInitializationConfiguration(t)
+	userService := mockUserServices.NewUserService(t)
+	rbacService := mockRBACServices.NewRBACService(t)
+	rbacService.On("EnsureSuperAdminRole").Return(nil, httperrors.AnError)
 	err := createSuperUser(
 		initializationConfig,
+		mocks.NewPasswordPolicyConfiguration(t),
 		logger,
 		userService,
+		rbacService,
+		mockBasicAuth.NewPasswordHasher(t),
 	)
 	assert.Error(t, err)
 
 	require.Equal(t, 1, logs.Len())
 }
+
+func TestCreateSuperUser_DefaultPassword(t *testing.T) {
+	core, logs := observer.New(zap.DebugLevel)
+	logger := zap.New(core)
+	initializationConfig := mocks.NewInitializationConfiguration(t)
+	initializationConfig.On("GetAdminPassword").Return("admin")
+	passwordPolicyConfiguration := mocks.NewPasswordPolicyConfiguration(t)
+	passwordPolicyConfiguration.On("GetRefuseDefaultAdminPassword").Return(false)
+	userService := mockUserServices.NewUserService(t)
+	userService.On("NewSystemUser", "admin", "admin-no-reply@badaas.com", "admin").Return(superAdmin, nil)
+	userService.On("MarkEmailVerified", superAdmin.ID).Return(nil)
+	passwordHasher := mockBasicAuth.NewPasswordHasher(t)
+	passwordHasher.On("Verify", superAdmin.Password, "admin").Return(true)
+	rbacService := mockRBACServices.NewRBACService(t)
+	rbacService.On("EnsureSuperAdminRole").Return(superAdminRole, nil)
+	rbacService.On("AssignRole", superAdmin.ID, superAdminRole.ID).Return(nil)
+
+	err := createSuperUser(initializationConfig, passwordPolicyConfiguration, logger, userService, rbacService, passwordHasher)
+	assert.NoError(t, err)
+	require.Equal(t, 1, logs.Len())
+	assert.Equal(t, zap.WarnLevel, logs.All()[0].Level)
+}
+
+func TestCreateSuperUser_DefaultPasswordRefused(t *testing.T) {
+	core, _ := observer.New(zap.DebugLevel)
+	logger := zap.New(core)
+	initializationConfig := mocks.NewInitializationConfiguration(t)
+	initializationConfig.On("GetAdminPassword").Return("admin")
+	passwordPolicyConfiguration := mocks.NewPasswordPolicyConfiguration(t)
+	passwordPolicyConfiguration.On("GetRefuseDefaultAdminPassword").Return(true)
+	userService := mockUserServices.NewUserService(t)
+	userService.On("NewSystemUser", "admin", "admin-no-reply@badaas.com", "admin").Return(superAdmin, nil)
+	userService.On("MarkEmailVerified", superAdmin.ID).Return(nil)
+	passwordHasher := mockBasicAuth.NewPasswordHasher(t)
+	passwordHasher.On("Verify", superAdmin.Password, "admin").Return(true)
+	rbacService := mockRBACServices.NewRBACService(t)
+	rbacService.On("EnsureSuperAdminRole").Return(superAdminRole, nil)
+	rbacService.On("AssignRole", superAdmin.ID, superAdminRole.ID).Return(nil)
+
+	err := createSuperUser(initializationConfig, passwordPolicyConfiguration, logger, userService, rbacService, passwordHasher)
+	assert.ErrorIs(t, err, ErrDefaultAdminPassword)
+}
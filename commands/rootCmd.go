@@ -9,8 +9,23 @@ import (
 	"github.com/ditrit/badaas/persistence"
 	"github.com/ditrit/badaas/resources"
 	"github.com/ditrit/badaas/router"
+	"github.com/ditrit/badaas/services/apikeyservice"
+	"github.com/ditrit/badaas/services/auth/protocols/basicauth"
+	"github.com/ditrit/badaas/services/groupservice"
+	"github.com/ditrit/badaas/services/ldapservice"
+	"github.com/ditrit/badaas/services/loginthrottlingservice"
+	"github.com/ditrit/badaas/services/mailservice"
+	"github.com/ditrit/badaas/services/oauthservice"
+	"github.com/ditrit/badaas/services/oidcservice"
+	"github.com/ditrit/badaas/services/passwordpolicyservice"
+	"github.com/ditrit/badaas/services/policyservice"
+	"github.com/ditrit/badaas/services/rbacservice"
+	"github.com/ditrit/badaas/services/registrationservice"
+	"github.com/ditrit/badaas/services/samlservice"
 	"github.com/ditrit/badaas/services/sessionservice"
+	"github.com/ditrit/badaas/services/twofactorservice"
 	"github.com/ditrit/badaas/services/userservice"
+	"github.com/ditrit/badaas/services/webauthnservice"
 	"github.com/ditrit/verdeter"
 	"github.com/spf13/cobra"
 	"go.uber.org/fx"
@@ -30,6 +45,23 @@ func runHTTPServer(cmd *cobra.Command, args []string) {
 
 		fx.Provide(userservice.NewUserService),
 		fx.Provide(sessionservice.NewSessionService),
+		fx.Provide(groupservice.NewGroupService),
+		fx.Provide(rbacservice.NewRBACService),
+		fx.Provide(policyservice.NewPolicyService),
+		fx.Provide(registrationservice.NewRegistrationService),
+		fx.Provide(mailservice.NewMailSender),
+		fx.Provide(mailservice.NewTemplateRenderer),
+		fx.Provide(mailservice.NewMailer),
+		fx.Provide(basicauth.NewPasswordHasher),
+		fx.Provide(passwordpolicyservice.NewPasswordPolicyService),
+		fx.Provide(loginthrottlingservice.NewLoginThrottlingService),
+		fx.Provide(twofactorservice.NewTwoFactorService),
+		fx.Provide(webauthnservice.NewWebAuthnService),
+		fx.Provide(oidcservice.NewOIDCService),
+		fx.Provide(samlservice.NewSAMLService),
+		fx.Provide(ldapservice.NewLDAPService),
+		fx.Provide(oauthservice.NewOAuthService),
+		fx.Provide(apikeyservice.NewAPIKeyService),
 		// logger for fx
 		fx.WithLogger(func(logger *zap.Logger) fxevent.Logger {
 			return &fxevent.ZapLogger{Logger: logger}
@@ -67,4 +99,20 @@ func init() {
 	initDatabaseCommands(rootCfg)
 	initInitialisationCommands(rootCfg)
 	initSessionCommands(rootCfg)
+	initPolicyCommands(rootCfg)
+	initRegistrationCommands(rootCfg)
+	initEmailVerificationCommands(rootCfg)
+	initPasswordResetCommands(rootCfg)
+	initMailCommands(rootCfg)
+	initPasswordHashingCommands(rootCfg)
+	initPasswordPolicyCommands(rootCfg)
+	initLoginThrottlingCommands(rootCfg)
+	initTwoFactorCommands(rootCfg)
+	initWebAuthnCommands(rootCfg)
+	initOIDCCommands(rootCfg)
+	initSAMLCommands(rootCfg)
+	initLDAPCommands(rootCfg)
+	initOAuthCommands(rootCfg)
+	initAuthenticationCommands(rootCfg)
+	initCookieCommands(rootCfg)
 }
@@ -4,9 +4,13 @@ package commands
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
+	"os"
 	"time"
 
 	"go.uber.org/fx"
@@ -34,6 +38,32 @@ func createServer(router http.Handler, address string, writeTimeout, readTimeout
 	return srv
 }
 
+// Create the TLS configuration of the server, nil if it serves plain http
+//
+// The client certificates are verified if they are given, the requests without one are authenticated with other schemes.
+func createTLSConfig(httpServerConfig configuration.HTTPServerConfiguration) (*tls.Config, error) {
+	if httpServerConfig.GetTLSCertificate() == "" {
+		if httpServerConfig.GetTLSClientCAs() != "" {
+			return nil, errors.New("the client certificates need the server to serve https")
+		}
+		return nil, nil
+	}
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if httpServerConfig.GetTLSClientCAs() != "" {
+		pemCerts, err := os.ReadFile(httpServerConfig.GetTLSClientCAs())
+		if err != nil {
+			return nil, err
+		}
+		clientCAs := x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(pemCerts) {
+			return nil, fmt.Errorf("no certificate found in %q", httpServerConfig.GetTLSClientCAs())
+		}
+		tlsConfig.ClientCAs = clientCAs
+		tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+	return tlsConfig, nil
+}
+
 // Create the addr string for the http.Server
 // returns "<host>:<port>"
 func addrFromConf(host string, port int) string {
@@ -49,8 +79,13 @@ func NewHTTPServer(
 	logger *zap.Logger,
 	router http.Handler,
 	httpServerConfig configuration.HTTPServerConfiguration,
-) *http.Server {
+) (*http.Server, error) {
 	srv := createServerFromConfigurationHolder(router, httpServerConfig)
+	tlsConfig, err := createTLSConfig(httpServerConfig)
+	if err != nil {
+		return nil, err
+	}
+	srv.TLSConfig = tlsConfig
 	lc.Append(fx.Hook{
 		OnStart: func(ctx context.Context) error {
 			ln, err := net.Listen("tcp", srv.Addr)
@@ -58,6 +93,10 @@ func NewHTTPServer(
 				return err
 			}
 			logger.Sugar().Infof("Ready to serve at %s", srv.Addr)
+			if srv.TLSConfig != nil {
+				go srv.ServeTLS(ln, httpServerConfig.GetTLSCertificate(), httpServerConfig.GetTLSKey())
+				return nil
+			}
 			go srv.Serve(ln)
 			return nil
 		},
@@ -67,5 +106,5 @@ func NewHTTPServer(
 			return srv.Shutdown(ctx)
 		},
 	})
-	return srv
+	return srv, nil
 }
@@ -3,12 +3,24 @@ package commands
 // This files holds the tests for the commands/server.go file.
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
 	"net/http"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/ditrit/badaas/configuration"
+	configurationmocks "github.com/ditrit/badaas/mocks/configuration"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func Test_addrFromConf(t *testing.T) {
@@ -34,3 +46,47 @@ func TestCreateServerFromConfigurationHolder(t *testing.T) {
 	assert.NotNil(t, srv)
 
 }
+
+func TestCreateTLSConfigPlainHTTP(t *testing.T) {
+	httpServerConfig := configurationmocks.NewHTTPServerConfiguration(t)
+	httpServerConfig.On("GetTLSCertificate").Return("")
+	httpServerConfig.On("GetTLSClientCAs").Return("")
+
+	tlsConfig, err := createTLSConfig(httpServerConfig)
+	assert.NoError(t, err)
+	assert.Nil(t, tlsConfig)
+}
+
+func TestCreateTLSConfigClientCAsWithoutCertificate(t *testing.T) {
+	httpServerConfig := configurationmocks.NewHTTPServerConfiguration(t)
+	httpServerConfig.On("GetTLSCertificate").Return("")
+	httpServerConfig.On("GetTLSClientCAs").Return("/etc/badaas/clients-ca.pem")
+
+	_, err := createTLSConfig(httpServerConfig)
+	assert.Error(t, err)
+}
+
+func TestCreateTLSConfigClientCertificates(t *testing.T) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "clients CA"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, privateKey.Public(), privateKey)
+	require.NoError(t, err)
+	clientCAsPath := filepath.Join(t.TempDir(), "clients-ca.pem")
+	require.NoError(t, os.WriteFile(clientCAsPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	httpServerConfig := configurationmocks.NewHTTPServerConfiguration(t)
+	httpServerConfig.On("GetTLSCertificate").Return("/etc/badaas/server.pem")
+	httpServerConfig.On("GetTLSClientCAs").Return(clientCAsPath)
+
+	tlsConfig, err := createTLSConfig(httpServerConfig)
+	require.NoError(t, err)
+	assert.Equal(t, tls.VerifyClientCertIfGiven, tlsConfig.ClientAuth)
+	assert.NotNil(t, tlsConfig.ClientCAs)
+}
@@ -0,0 +1,74 @@
+package configuration
+
+import (
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// The config keys regarding the authentication of the requests
+const (
+	AuthenticationSchemesKey string = "authentication.schemes"
+	AuthenticationRealmKey   string = "authentication.realm"
+)
+
+// The authentication schemes
+const (
+	// The access token sent in the session cookie
+	AuthenticationSchemeCookie string = "cookie"
+	// The API key or the access token sent as a bearer token (RFC 6750)
+	AuthenticationSchemeBearer string = "bearer"
+	// The email and the password of the user (RFC 7617)
+	AuthenticationSchemeBasic string = "basic"
+	// The TLS client certificate of the user
+	AuthenticationSchemeClientCertificate string = "clientCertificate"
+)
+
+// The schemes accepted if none is configured
+var defaultAuthenticationSchemes = []string{AuthenticationSchemeBearer, AuthenticationSchemeCookie}
+
+// Hold the configuration values for the authentication of the requests
+type AuthenticationConfiguration interface {
+	ConfigurationHolder
+	GetSchemes() []string
+	GetRealm() string
+}
+
+// Concrete implementation of the AuthenticationConfiguration interface
+type authenticationConfigurationImpl struct {
+	schemes []string
+	realm   string
+}
+
+// Instantiate a new configuration holder for the authentication of the requests
+func NewAuthenticationConfiguration() AuthenticationConfiguration {
+	authenticationConfiguration := new(authenticationConfigurationImpl)
+	authenticationConfiguration.Reload()
+	return authenticationConfiguration
+}
+
+// Return the accepted schemes, in the order they are tried
+func (authenticationConfiguration *authenticationConfigurationImpl) GetSchemes() []string {
+	return authenticationConfiguration.schemes
+}
+
+// Return the realm of the WWW-Authenticate challenges
+func (authenticationConfiguration *authenticationConfigurationImpl) GetRealm() string {
+	return authenticationConfiguration.realm
+}
+
+// Reload authentication configuration
+func (authenticationConfiguration *authenticationConfigurationImpl) Reload() {
+	authenticationConfiguration.schemes = viper.GetStringSlice(AuthenticationSchemesKey)
+	if len(authenticationConfiguration.schemes) == 0 {
+		authenticationConfiguration.schemes = defaultAuthenticationSchemes
+	}
+	authenticationConfiguration.realm = viper.GetString(AuthenticationRealmKey)
+}
+
+// Log the values provided by the configuration holder
+func (authenticationConfiguration *authenticationConfigurationImpl) Log(logger *zap.Logger) {
+	logger.Info("Authentication configuration",
+		zap.Strings("schemes", authenticationConfiguration.schemes),
+		zap.String("realm", authenticationConfiguration.realm),
+	)
+}
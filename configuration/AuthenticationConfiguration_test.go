@@ -0,0 +1,55 @@
+package configuration_test
+
+import (
+	"testing"
+
+	"github.com/ditrit/badaas/configuration"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+var AuthenticationConfigurationString = `authentication:
+  realm: example
+  schemes:
+    - clientCertificate
+    - bearer
+    - basic
+`
+
+func TestAuthenticationConfigurationNewAuthenticationConfiguration(t *testing.T) {
+	assert.NotNil(t, configuration.NewAuthenticationConfiguration(), "the contructor for AuthenticationConfiguration should not return a nil value")
+}
+
+func TestAuthenticationConfigurationGetSchemes(t *testing.T) {
+	setupViperEnvironment(AuthenticationConfigurationString)
+	authenticationConfiguration := configuration.NewAuthenticationConfiguration()
+	assert.Equal(t, []string{"clientCertificate", "bearer", "basic"}, authenticationConfiguration.GetSchemes())
+	assert.Equal(t, "example", authenticationConfiguration.GetRealm())
+}
+
+func TestAuthenticationConfigurationDefaultSchemes(t *testing.T) {
+	setupViperEnvironment("")
+	authenticationConfiguration := configuration.NewAuthenticationConfiguration()
+	assert.Equal(t, []string{"bearer", "cookie"}, authenticationConfiguration.GetSchemes())
+}
+
+func TestAuthenticationConfigurationLog(t *testing.T) {
+	setupViperEnvironment(AuthenticationConfigurationString)
+	observedZapCore, observedLogs := observer.New(zap.DebugLevel)
+	observedLogger := zap.New(observedZapCore)
+
+	authenticationConfiguration := configuration.NewAuthenticationConfiguration()
+	authenticationConfiguration.Log(observedLogger)
+
+	require.Equal(t, 1, observedLogs.Len())
+	log := observedLogs.All()[0]
+	assert.Equal(t, "Authentication configuration", log.Message)
+	require.Len(t, log.Context, 2)
+	assert.ElementsMatch(t, []zap.Field{
+		zap.Strings("schemes", []string{"clientCertificate", "bearer", "basic"}),
+		{Key: "realm", Type: zapcore.StringType, String: "example"},
+	}, log.Context)
+}
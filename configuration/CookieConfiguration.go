@@ -0,0 +1,148 @@
+package configuration
+
+import (
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// The config keys regarding the session cookies and the csrf protection
+const (
+	CookieNameKey       string = "cookie.name"
+	CookieDomainKey     string = "cookie.domain"
+	CookiePathKey       string = "cookie.path"
+	CookieSecureKey     string = "cookie.secure"
+	CookieSameSiteKey   string = "cookie.sameSite"
+	CookieHostPrefixKey string = "cookie.hostPrefix"
+
+	CSRFEnabledKey    string = "csrf.enabled"
+	CSRFCookieNameKey string = "csrf.cookieName"
+	CSRFHeaderNameKey string = "csrf.headerName"
+)
+
+// The values of the SameSite attribute of the cookies
+const (
+	CookieSameSiteStrict string = "strict"
+	CookieSameSiteLax    string = "lax"
+	CookieSameSiteNone   string = "none"
+)
+
+// The prefix of the cookies only sent to the host that set them, over https and on every path
+const CookieHostPrefix = "__Host-"
+
+// Hold the configuration values of the session cookies and of the csrf protection
+type CookieConfiguration interface {
+	ConfigurationHolder
+	// Return the name of the session cookie, with the __Host- prefix if it is enabled
+	GetName() string
+	GetDomain() string
+	GetPath() string
+	GetSecure() bool
+	GetSameSite() string
+	GetHostPrefix() bool
+	GetCSRFEnabled() bool
+	// Return the name of the csrf cookie, with the __Host- prefix if it is enabled
+	GetCSRFCookieName() string
+	GetCSRFHeaderName() string
+}
+
+// Concrete implementation of the CookieConfiguration interface
+type cookieConfigurationImpl struct {
+	name       string
+	domain     string
+	path       string
+	secure     bool
+	sameSite   string
+	hostPrefix bool
+
+	csrfEnabled    bool
+	csrfCookieName string
+	csrfHeaderName string
+}
+
+// Instantiate a new configuration holder for the session cookies
+func NewCookieConfiguration() CookieConfiguration {
+	cookieConfiguration := new(cookieConfigurationImpl)
+	cookieConfiguration.Reload()
+	return cookieConfiguration
+}
+
+// Return the name of the session cookie, with the __Host- prefix if it is enabled
+func (cookieConfiguration *cookieConfigurationImpl) GetName() string {
+	return cookieConfiguration.prefix(cookieConfiguration.name)
+}
+
+// Return the domain of the cookies, empty for the host that set them
+func (cookieConfiguration *cookieConfigurationImpl) GetDomain() string {
+	return cookieConfiguration.domain
+}
+
+// Return the path of the cookies
+func (cookieConfiguration *cookieConfigurationImpl) GetPath() string {
+	return cookieConfiguration.path
+}
+
+// Return true if the cookies are only sent over https
+func (cookieConfiguration *cookieConfigurationImpl) GetSecure() bool {
+	return cookieConfiguration.secure
+}
+
+// Return the SameSite attribute of the cookies: strict, lax or none
+func (cookieConfiguration *cookieConfigurationImpl) GetSameSite() string {
+	return cookieConfiguration.sameSite
+}
+
+// Return true if the names of the cookies have the __Host- prefix
+func (cookieConfiguration *cookieConfigurationImpl) GetHostPrefix() bool {
+	return cookieConfiguration.hostPrefix
+}
+
+// Return true if the state-changing requests authenticated with the session cookie need a csrf token
+func (cookieConfiguration *cookieConfigurationImpl) GetCSRFEnabled() bool {
+	return cookieConfiguration.csrfEnabled
+}
+
+// Return the name of the csrf cookie, with the __Host- prefix if it is enabled
+func (cookieConfiguration *cookieConfigurationImpl) GetCSRFCookieName() string {
+	return cookieConfiguration.prefix(cookieConfiguration.csrfCookieName)
+}
+
+// Return the name of the header the csrf token is sent back in
+func (cookieConfiguration *cookieConfigurationImpl) GetCSRFHeaderName() string {
+	return cookieConfiguration.csrfHeaderName
+}
+
+// Add the __Host- prefix to a cookie name if it is enabled
+func (cookieConfiguration *cookieConfigurationImpl) prefix(name string) string {
+	if cookieConfiguration.hostPrefix {
+		return CookieHostPrefix + name
+	}
+	return name
+}
+
+// Reload cookie configuration
+func (cookieConfiguration *cookieConfigurationImpl) Reload() {
+	cookieConfiguration.name = viper.GetString(CookieNameKey)
+	cookieConfiguration.domain = viper.GetString(CookieDomainKey)
+	cookieConfiguration.path = viper.GetString(CookiePathKey)
+	cookieConfiguration.secure = viper.GetBool(CookieSecureKey)
+	cookieConfiguration.sameSite = viper.GetString(CookieSameSiteKey)
+	cookieConfiguration.hostPrefix = viper.GetBool(CookieHostPrefixKey)
+	cookieConfiguration.csrfEnabled = viper.GetBool(CSRFEnabledKey)
+	cookieConfiguration.csrfCookieName = viper.GetString(CSRFCookieNameKey)
+	cookieConfiguration.csrfHeaderName = viper.GetString(CSRFHeaderNameKey)
+}
+
+// Log the values provided by the configuration holder
+func (cookieConfiguration *cookieConfigurationImpl) Log(logger *zap.Logger) {
+	logger.Info("Cookie configuration",
+		zap.String("name", cookieConfiguration.GetName()),
+		zap.String("domain", cookieConfiguration.domain),
+		zap.String("path", cookieConfiguration.path),
+		zap.Bool("secure", cookieConfiguration.secure),
+		zap.String("sameSite", cookieConfiguration.sameSite),
+		zap.Bool("hostPrefix", cookieConfiguration.hostPrefix),
+		zap.Bool("csrfEnabled", cookieConfiguration.csrfEnabled),
+		zap.String("csrfCookieName", cookieConfiguration.GetCSRFCookieName()),
+		zap.String("csrfHeaderName", cookieConfiguration.csrfHeaderName),
+	)
+}
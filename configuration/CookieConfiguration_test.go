@@ -0,0 +1,80 @@
+package configuration_test
+
+import (
+	"testing"
+
+	"github.com/ditrit/badaas/configuration"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+var CookieConfigurationString = `cookie:
+  name: session
+  domain: example.com
+  path: /api
+  secure: true
+  sameSite: strict
+  hostPrefix: false
+csrf:
+  enabled: true
+  cookieName: xsrf
+  headerName: X-XSRF-Token
+`
+
+func TestCookieConfigurationNewCookieConfiguration(t *testing.T) {
+	assert.NotNil(t, configuration.NewCookieConfiguration(), "the contructor for CookieConfiguration should not return a nil value")
+}
+
+func TestCookieConfigurationGetters(t *testing.T) {
+	setupViperEnvironment(CookieConfigurationString)
+	cookieConfiguration := configuration.NewCookieConfiguration()
+	assert.Equal(t, "session", cookieConfiguration.GetName())
+	assert.Equal(t, "example.com", cookieConfiguration.GetDomain())
+	assert.Equal(t, "/api", cookieConfiguration.GetPath())
+	assert.True(t, cookieConfiguration.GetSecure())
+	assert.Equal(t, configuration.CookieSameSiteStrict, cookieConfiguration.GetSameSite())
+	assert.False(t, cookieConfiguration.GetHostPrefix())
+	assert.True(t, cookieConfiguration.GetCSRFEnabled())
+	assert.Equal(t, "xsrf", cookieConfiguration.GetCSRFCookieName())
+	assert.Equal(t, "X-XSRF-Token", cookieConfiguration.GetCSRFHeaderName())
+}
+
+func TestCookieConfigurationHostPrefix(t *testing.T) {
+	setupViperEnvironment(`cookie:
+  name: session
+  hostPrefix: true
+csrf:
+  cookieName: xsrf
+`)
+	cookieConfiguration := configuration.NewCookieConfiguration()
+	assert.Equal(t, "__Host-session", cookieConfiguration.GetName())
+	assert.Equal(t, "__Host-xsrf", cookieConfiguration.GetCSRFCookieName())
+}
+
+func TestCookieConfigurationLog(t *testing.T) {
+	setupViperEnvironment(CookieConfigurationString)
+	observedZapCore, observedLogs := observer.New(zap.DebugLevel)
+	observedLogger := zap.New(observedZapCore)
+
+	cookieConfiguration := configuration.NewCookieConfiguration()
+	cookieConfiguration.Log(observedLogger)
+
+	require.Equal(t, 1, observedLogs.Len())
+	log := observedLogs.All()[0]
+	assert.Equal(t, "Cookie configuration", log.Message)
+	require.Len(t, log.Context, 9)
+	assert.ElementsMatch(t, []zap.Field{
+		{Key: "name", Type: zapcore.StringType, String: "session"},
+		{Key: "domain", Type: zapcore.StringType, String: "example.com"},
+		{Key: "path", Type: zapcore.StringType, String: "/api"},
+		{Key: "secure", Type: zapcore.BoolType, Integer: 1},
+		{Key: "sameSite", Type: zapcore.StringType, String: "strict"},
+		{Key: "hostPrefix", Type: zapcore.BoolType, Integer: 0},
+		{Key: "csrfEnabled", Type: zapcore.BoolType, Integer: 1},
+		{Key: "csrfCookieName", Type: zapcore.StringType, String: "xsrf"},
+		{Key: "csrfHeaderName", Type: zapcore.StringType, String: "X-XSRF-Token"},
+	}, log.Context)
+}
@@ -0,0 +1,68 @@
+package configuration
+
+import (
+	"time"
+
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// The config keys regarding the email verification settings
+const (
+	EmailVerificationRequiredKey       string = "emailVerification.required"
+	EmailVerificationTokenDurationKey  string = "emailVerification.tokenDuration"
+	EmailVerificationResendIntervalKey string = "emailVerification.resendInterval"
+)
+
+// Hold the configuration values for the verification of the emails of the users
+type EmailVerificationConfiguration interface {
+	ConfigurationHolder
+	GetRequired() bool
+	GetTokenDuration() time.Duration
+	GetResendInterval() time.Duration
+}
+
+// Concrete implementation of the EmailVerificationConfiguration interface
+type emailVerificationConfigurationImpl struct {
+	required       bool
+	tokenDuration  time.Duration
+	resendInterval time.Duration
+}
+
+// Instantiate a new configuration holder for the verification of the emails
+func NewEmailVerificationConfiguration() EmailVerificationConfiguration {
+	emailVerificationConfiguration := new(emailVerificationConfigurationImpl)
+	emailVerificationConfiguration.Reload()
+	return emailVerificationConfiguration
+}
+
+// Return true if the users can't log in until their email is verified
+func (emailVerificationConfiguration *emailVerificationConfigurationImpl) GetRequired() bool {
+	return emailVerificationConfiguration.required
+}
+
+// Return the duration during which a verification token can be used
+func (emailVerificationConfiguration *emailVerificationConfigurationImpl) GetTokenDuration() time.Duration {
+	return emailVerificationConfiguration.tokenDuration
+}
+
+// Return the minimum duration between two verification emails sent to a user
+func (emailVerificationConfiguration *emailVerificationConfigurationImpl) GetResendInterval() time.Duration {
+	return emailVerificationConfiguration.resendInterval
+}
+
+// Reload email verification configuration
+func (emailVerificationConfiguration *emailVerificationConfigurationImpl) Reload() {
+	emailVerificationConfiguration.required = viper.GetBool(EmailVerificationRequiredKey)
+	emailVerificationConfiguration.tokenDuration = intToSecond(int(viper.GetUint(EmailVerificationTokenDurationKey)))
+	emailVerificationConfiguration.resendInterval = intToSecond(int(viper.GetUint(EmailVerificationResendIntervalKey)))
+}
+
+// Log the values provided by the configuration holder
+func (emailVerificationConfiguration *emailVerificationConfigurationImpl) Log(logger *zap.Logger) {
+	logger.Info("Email verification configuration",
+		zap.Bool("required", emailVerificationConfiguration.required),
+		zap.Duration("tokenDuration", emailVerificationConfiguration.tokenDuration),
+		zap.Duration("resendInterval", emailVerificationConfiguration.resendInterval),
+	)
+}
@@ -0,0 +1,50 @@
+package configuration_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ditrit/badaas/configuration"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+var EmailVerificationConfigurationString = `emailVerification:
+  required: true
+  tokenDuration: 3600 # one hour
+  resendInterval: 60 # one minute`
+
+func TestEmailVerificationConfigurationNewEmailVerificationConfiguration(t *testing.T) {
+	assert.NotNil(t, configuration.NewEmailVerificationConfiguration(), "the contructor for EmailVerificationConfiguration should not return a nil value")
+}
+
+func TestEmailVerificationConfigurationGetters(t *testing.T) {
+	setupViperEnvironment(EmailVerificationConfigurationString)
+	emailVerificationConfiguration := configuration.NewEmailVerificationConfiguration()
+	assert.True(t, emailVerificationConfiguration.GetRequired())
+	assert.Equal(t, time.Hour, emailVerificationConfiguration.GetTokenDuration())
+	assert.Equal(t, time.Minute, emailVerificationConfiguration.GetResendInterval())
+}
+
+func TestEmailVerificationConfigurationLog(t *testing.T) {
+	setupViperEnvironment(EmailVerificationConfigurationString)
+	// creating logger
+	observedZapCore, observedLogs := observer.New(zap.DebugLevel)
+	observedLogger := zap.New(observedZapCore)
+
+	emailVerificationConfiguration := configuration.NewEmailVerificationConfiguration()
+	emailVerificationConfiguration.Log(observedLogger)
+
+	require.Equal(t, 1, observedLogs.Len())
+	log := observedLogs.All()[0]
+	assert.Equal(t, "Email verification configuration", log.Message)
+	require.Len(t, log.Context, 3)
+	assert.ElementsMatch(t, []zap.Field{
+		{Key: "required", Type: zapcore.BoolType, Integer: 1},
+		{Key: "tokenDuration", Type: zapcore.DurationType, Integer: int64(time.Hour)},
+		{Key: "resendInterval", Type: zapcore.DurationType, Integer: int64(time.Minute)},
+	}, log.Context)
+}
@@ -1,6 +1,9 @@
 package configuration
 
 import (
+	"fmt"
+	"net"
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
@@ -13,6 +16,10 @@ const (
 	ServerHostKey                  string = "server.host"
 	ServerPortKey                  string = "server.port"
 	ServerPaginationMaxElemPerPage string = "server.pagination.page.max"
+	ServerTLSCertificateKey        string = "server.tls.certificate"
+	ServerTLSKeyKey                string = "server.tls.key"
+	ServerTLSClientCAsKey          string = "server.tls.clientCAs"
+	ServerTrustedProxiesKey        string = "server.trustedProxies"
 )
 
 // Hold the configuration values for the http server
@@ -21,6 +28,10 @@ type HTTPServerConfiguration interface {
 	GetHost() string
 	GetPort() int
 	GetMaxTimeout() time.Duration
+	GetTLSCertificate() string
+	GetTLSKey() string
+	GetTLSClientCAs() string
+	GetTrustedProxies() []*net.IPNet
 }
 
 // Concrete implementation of the HTTPServerConfiguration interface
@@ -28,6 +39,12 @@ type hTTPServerConfigurationImpl struct {
 	host    string
 	port    int
 	timeout time.Duration
+
+	tlsCertificate string
+	tlsKey         string
+	tlsClientCAs   string
+
+	trustedProxies []*net.IPNet
 }
 
 // Instantiate a new configuration holder for the http server
@@ -42,6 +59,37 @@ func (httpServerConfiguration *hTTPServerConfigurationImpl) Reload() {
 	httpServerConfiguration.host = viper.GetString(ServerHostKey)
 	httpServerConfiguration.port = viper.GetInt(ServerPortKey)
 	httpServerConfiguration.timeout = intToSecond(viper.GetInt(ServerTimeoutKey))
+	httpServerConfiguration.tlsCertificate = viper.GetString(ServerTLSCertificateKey)
+	httpServerConfiguration.tlsKey = viper.GetString(ServerTLSKeyKey)
+	httpServerConfiguration.tlsClientCAs = viper.GetString(ServerTLSClientCAsKey)
+	httpServerConfiguration.trustedProxies = []*net.IPNet{}
+	for _, trustedProxy := range viper.GetStringSlice(ServerTrustedProxiesKey) {
+		network, err := parseNetwork(trustedProxy)
+		if err != nil {
+			panic(err)
+		}
+		httpServerConfiguration.trustedProxies = append(httpServerConfiguration.trustedProxies, network)
+	}
+}
+
+// Parse an ip address or a CIDR network, an ip address is a network of one address
+func parseNetwork(value string) (*net.IPNet, error) {
+	value = strings.TrimSpace(value)
+	if !strings.Contains(value, "/") {
+		ip := net.ParseIP(value)
+		if ip == nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q", value)
+		}
+		if ipv4 := ip.To4(); ipv4 != nil {
+			return &net.IPNet{IP: ipv4, Mask: net.CIDRMask(32, 32)}, nil
+		}
+		return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}, nil
+	}
+	_, network, err := net.ParseCIDR(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid trusted proxy %q", value)
+	}
+	return network, nil
 }
 
 // Return the host addr
@@ -59,11 +107,36 @@ func (httpServerConfiguration *hTTPServerConfigurationImpl) GetMaxTimeout() time
 	return httpServerConfiguration.timeout
 }
 
+// Return the path of the PEM encoded certificate of the server, the server serves plain http if empty
+func (httpServerConfiguration *hTTPServerConfigurationImpl) GetTLSCertificate() string {
+	return httpServerConfiguration.tlsCertificate
+}
+
+// Return the path of the PEM encoded private key of the server
+func (httpServerConfiguration *hTTPServerConfigurationImpl) GetTLSKey() string {
+	return httpServerConfiguration.tlsKey
+}
+
+// Return the path of the PEM encoded certificates trusted for the client certificates,
+// the client certificates are not asked if empty
+func (httpServerConfiguration *hTTPServerConfigurationImpl) GetTLSClientCAs() string {
+	return httpServerConfiguration.tlsClientCAs
+}
+
+// Return the networks of the reverse proxies whose X-Forwarded-For header is trusted,
+// the address of the connection is the address of the client if empty
+func (httpServerConfiguration *hTTPServerConfigurationImpl) GetTrustedProxies() []*net.IPNet {
+	return httpServerConfiguration.trustedProxies
+}
+
 // Log the values provided by the configuration holder
 func (httpServerConfiguration *hTTPServerConfigurationImpl) Log(logger *zap.Logger) {
 	logger.Info("HTTP Server configuration",
 		zap.String("host", httpServerConfiguration.host),
 		zap.Int("port", httpServerConfiguration.port),
 		zap.Duration("timeout", httpServerConfiguration.timeout),
+		zap.String("tlsCertificate", httpServerConfiguration.tlsCertificate),
+		zap.String("tlsClientCAs", httpServerConfiguration.tlsClientCAs),
+		zap.Stringers("trustedProxies", httpServerConfiguration.trustedProxies),
 	)
 }
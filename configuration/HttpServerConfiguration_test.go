@@ -16,6 +16,13 @@ var HTTPServerConfigurationString = `server:
   port: 8000
   host: "0.0.0.0" # listening on all interfaces
   timeout: 15 # in seconds
+  tls:
+    certificate: /etc/badaas/server.pem
+    key: /etc/badaas/server-key.pem
+    clientCAs: /etc/badaas/clients-ca.pem
+  trustedProxies:
+    - 10.0.0.0/8
+    - 192.0.2.1
 `
 
 func TestHTTPServerConfigurationNewHttpServerConfiguration(t *testing.T) {
@@ -39,6 +46,33 @@ func TestHTTPServerConfigurationGetMaxTimeout(t *testing.T) {
 	assert.Equal(t, time.Duration(15*time.Second), HTTPServerConfiguration.GetMaxTimeout())
 }
 
+func TestHTTPServerConfigurationGetTLS(t *testing.T) {
+	setupViperEnvironment(HTTPServerConfigurationString)
+	HTTPServerConfiguration := configuration.NewHTTPServerConfiguration()
+	assert.Equal(t, "/etc/badaas/server.pem", HTTPServerConfiguration.GetTLSCertificate())
+	assert.Equal(t, "/etc/badaas/server-key.pem", HTTPServerConfiguration.GetTLSKey())
+	assert.Equal(t, "/etc/badaas/clients-ca.pem", HTTPServerConfiguration.GetTLSClientCAs())
+}
+
+func TestHTTPServerConfigurationGetTrustedProxies(t *testing.T) {
+	setupViperEnvironment(HTTPServerConfigurationString)
+	HTTPServerConfiguration := configuration.NewHTTPServerConfiguration()
+	trustedProxies := HTTPServerConfiguration.GetTrustedProxies()
+	require.Len(t, trustedProxies, 2)
+	assert.Equal(t, "10.0.0.0/8", trustedProxies[0].String())
+	assert.Equal(t, "192.0.2.1/32", trustedProxies[1].String())
+}
+
+func TestHTTPServerConfigurationInvalidTrustedProxy(t *testing.T) {
+	setupViperEnvironment(`server:
+  trustedProxies:
+    - proxy.example.com
+`)
+	assert.PanicsWithError(t, `invalid trusted proxy "proxy.example.com"`, func() {
+		configuration.NewHTTPServerConfiguration()
+	})
+}
+
 func TestHTTPServerConfigurationLog(t *testing.T) {
 	setupViperEnvironment(HTTPServerConfigurationString)
 	// creating logger
@@ -51,10 +85,13 @@ func TestHTTPServerConfigurationLog(t *testing.T) {
 	require.Equal(t, 1, observedLogs.Len())
 	log := observedLogs.All()[0]
 	assert.Equal(t, "HTTP Server configuration", log.Message)
-	require.Len(t, log.Context, 3)
+	require.Len(t, log.Context, 6)
 	assert.ElementsMatch(t, []zap.Field{
 		{Key: "port", Type: zapcore.Int64Type, Integer: 8000},
 		{Key: "host", Type: zapcore.StringType, String: "0.0.0.0"},
 		{Key: "timeout", Type: zapcore.DurationType, Integer: int64(time.Duration(time.Second * 15))},
+		{Key: "tlsCertificate", Type: zapcore.StringType, String: "/etc/badaas/server.pem"},
+		{Key: "tlsClientCAs", Type: zapcore.StringType, String: "/etc/badaas/clients-ca.pem"},
+		zap.Stringers("trustedProxies", HTTPServerConfiguration.GetTrustedProxies()),
 	}, log.Context)
 }
@@ -10,6 +10,9 @@ const (
 	InitializationDefaultAdminPasswordKey string = "default.admin.password"
 )
 
+// The password of the super admin if none is configured
+const DefaultAdminPassword string = "admin"
+
 // Hold the configuration values for the initialization
 type InitializationConfiguration interface {
 	ConfigurationHolder
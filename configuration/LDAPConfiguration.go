@@ -0,0 +1,210 @@
+package configuration
+
+import (
+	"time"
+
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// The config keys regarding the LDAP settings
+const (
+	LDAPURLKey               string = "ldap.url"
+	LDAPStartTLSKey          string = "ldap.startTLS"
+	LDAPCACertificateKey     string = "ldap.caCertificate"
+	LDAPBindDNKey            string = "ldap.bindDN"
+	LDAPBindPasswordKey      string = "ldap.bindPassword"
+	LDAPBaseDNKey            string = "ldap.baseDN"
+	LDAPUserFilterKey        string = "ldap.userFilter"
+	LDAPEmailAttributeKey    string = "ldap.attributes.email"
+	LDAPUsernameAttributeKey string = "ldap.attributes.username"
+	LDAPGroupsAttributeKey   string = "ldap.attributes.groups"
+	LDAPGroupBaseDNKey       string = "ldap.groupSearch.baseDN"
+	LDAPGroupFilterKey       string = "ldap.groupSearch.filter"
+	LDAPGroupRolesKey        string = "ldap.groupRoles"
+	LDAPProvisioningKey      string = "ldap.provisioning"
+	LDAPTimeoutKey           string = "ldap.timeout"
+)
+
+// The roles given to the members of a directory group
+type LDAPGroupRoles struct {
+	// The distinguished name of the group
+	Group string   `mapstructure:"group"`
+	Roles []string `mapstructure:"roles"`
+}
+
+// Hold the configuration values for the LDAP login
+type LDAPConfiguration interface {
+	ConfigurationHolder
+	// Return true if a directory is configured
+	IsEnabled() bool
+	GetURL() string
+	GetStartTLS() bool
+	GetCACertificate() string
+	GetBindDN() string
+	GetBindPassword() string
+	GetBaseDN() string
+	GetUserFilter() string
+	GetEmailAttribute() string
+	GetUsernameAttribute() string
+	GetGroupsAttribute() string
+	GetGroupBaseDN() string
+	GetGroupFilter() string
+	GetGroupRoles() []LDAPGroupRoles
+	GetProvisioning() bool
+	GetTimeout() time.Duration
+}
+
+// Concrete implementation of the LDAPConfiguration interface
+type ldapConfigurationImpl struct {
+	url               string
+	startTLS          bool
+	caCertificate     string
+	bindDN            string
+	bindPassword      string
+	baseDN            string
+	userFilter        string
+	emailAttribute    string
+	usernameAttribute string
+	groupsAttribute   string
+	groupBaseDN       string
+	groupFilter       string
+	groupRoles        []LDAPGroupRoles
+	provisioning      bool
+	timeout           time.Duration
+}
+
+// Instantiate a new configuration holder for the LDAP login
+func NewLDAPConfiguration() LDAPConfiguration {
+	ldapConfiguration := new(ldapConfigurationImpl)
+	ldapConfiguration.Reload()
+	return ldapConfiguration
+}
+
+// Return true if a directory is configured
+func (ldapConfiguration *ldapConfigurationImpl) IsEnabled() bool {
+	return ldapConfiguration.url != ""
+}
+
+// Return the ldap:// or ldaps:// url of the directory
+func (ldapConfiguration *ldapConfigurationImpl) GetURL() string {
+	return ldapConfiguration.url
+}
+
+// Return true if the ldap:// connection is upgraded to TLS
+func (ldapConfiguration *ldapConfigurationImpl) GetStartTLS() bool {
+	return ldapConfiguration.startTLS
+}
+
+// Return the path of the PEM encoded certificates trusted for the directory, the system ones are used if empty
+func (ldapConfiguration *ldapConfigurationImpl) GetCACertificate() string {
+	return ldapConfiguration.caCertificate
+}
+
+// Return the dn of the service account searching the users
+func (ldapConfiguration *ldapConfigurationImpl) GetBindDN() string {
+	return ldapConfiguration.bindDN
+}
+
+// Return the password of the service account
+func (ldapConfiguration *ldapConfigurationImpl) GetBindPassword() string {
+	return ldapConfiguration.bindPassword
+}
+
+// Return the dn under which the users are searched
+func (ldapConfiguration *ldapConfigurationImpl) GetBaseDN() string {
+	return ldapConfiguration.baseDN
+}
+
+// Return the filter finding a user, %s is replaced by the login
+func (ldapConfiguration *ldapConfigurationImpl) GetUserFilter() string {
+	return ldapConfiguration.userFilter
+}
+
+// Return the name of the attribute holding the email of the user
+func (ldapConfiguration *ldapConfigurationImpl) GetEmailAttribute() string {
+	return ldapConfiguration.emailAttribute
+}
+
+// Return the name of the attribute holding the username of the user
+func (ldapConfiguration *ldapConfigurationImpl) GetUsernameAttribute() string {
+	return ldapConfiguration.usernameAttribute
+}
+
+// Return the name of the attribute listing the groups of the user
+func (ldapConfiguration *ldapConfigurationImpl) GetGroupsAttribute() string {
+	return ldapConfiguration.groupsAttribute
+}
+
+// Return the dn under which the groups are searched, the groups are not searched if empty
+func (ldapConfiguration *ldapConfigurationImpl) GetGroupBaseDN() string {
+	return ldapConfiguration.groupBaseDN
+}
+
+// Return the filter finding the groups of a user, %s is replaced by the dn of the user
+func (ldapConfiguration *ldapConfigurationImpl) GetGroupFilter() string {
+	return ldapConfiguration.groupFilter
+}
+
+// Return the roles given to the members of the groups
+func (ldapConfiguration *ldapConfigurationImpl) GetGroupRoles() []LDAPGroupRoles {
+	return ldapConfiguration.groupRoles
+}
+
+// Return true if a user is created for the unknown directory users
+func (ldapConfiguration *ldapConfigurationImpl) GetProvisioning() bool {
+	return ldapConfiguration.provisioning
+}
+
+// Return the timeout of the connection and of the requests to the directory
+func (ldapConfiguration *ldapConfigurationImpl) GetTimeout() time.Duration {
+	return ldapConfiguration.timeout
+}
+
+// Reload LDAP configuration
+func (ldapConfiguration *ldapConfigurationImpl) Reload() {
+	groupRoles := []LDAPGroupRoles{}
+	err := viper.UnmarshalKey(LDAPGroupRolesKey, &groupRoles)
+	if err != nil {
+		panic(err)
+	}
+	ldapConfiguration.groupRoles = groupRoles
+	ldapConfiguration.url = viper.GetString(LDAPURLKey)
+	ldapConfiguration.startTLS = viper.GetBool(LDAPStartTLSKey)
+	ldapConfiguration.caCertificate = viper.GetString(LDAPCACertificateKey)
+	ldapConfiguration.bindDN = viper.GetString(LDAPBindDNKey)
+	ldapConfiguration.bindPassword = viper.GetString(LDAPBindPasswordKey)
+	ldapConfiguration.baseDN = viper.GetString(LDAPBaseDNKey)
+	ldapConfiguration.userFilter = viper.GetString(LDAPUserFilterKey)
+	ldapConfiguration.emailAttribute = viper.GetString(LDAPEmailAttributeKey)
+	ldapConfiguration.usernameAttribute = viper.GetString(LDAPUsernameAttributeKey)
+	ldapConfiguration.groupsAttribute = viper.GetString(LDAPGroupsAttributeKey)
+	ldapConfiguration.groupBaseDN = viper.GetString(LDAPGroupBaseDNKey)
+	ldapConfiguration.groupFilter = viper.GetString(LDAPGroupFilterKey)
+	ldapConfiguration.provisioning = viper.GetBool(LDAPProvisioningKey)
+	ldapConfiguration.timeout = intToSecond(int(viper.GetUint(LDAPTimeoutKey)))
+}
+
+// Log the values provided by the configuration holder, the password of the service account is not logged
+func (ldapConfiguration *ldapConfigurationImpl) Log(logger *zap.Logger) {
+	groups := make([]string, 0, len(ldapConfiguration.groupRoles))
+	for _, groupRoles := range ldapConfiguration.groupRoles {
+		groups = append(groups, groupRoles.Group)
+	}
+	logger.Info("LDAP configuration",
+		zap.String("url", ldapConfiguration.url),
+		zap.Bool("startTLS", ldapConfiguration.startTLS),
+		zap.String("caCertificate", ldapConfiguration.caCertificate),
+		zap.String("bindDN", ldapConfiguration.bindDN),
+		zap.String("baseDN", ldapConfiguration.baseDN),
+		zap.String("userFilter", ldapConfiguration.userFilter),
+		zap.String("emailAttribute", ldapConfiguration.emailAttribute),
+		zap.String("usernameAttribute", ldapConfiguration.usernameAttribute),
+		zap.String("groupsAttribute", ldapConfiguration.groupsAttribute),
+		zap.String("groupBaseDN", ldapConfiguration.groupBaseDN),
+		zap.String("groupFilter", ldapConfiguration.groupFilter),
+		zap.Strings("groupRoles", groups),
+		zap.Bool("provisioning", ldapConfiguration.provisioning),
+		zap.Duration("timeout", ldapConfiguration.timeout),
+	)
+}
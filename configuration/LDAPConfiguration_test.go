@@ -0,0 +1,80 @@
+package configuration_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ditrit/badaas/configuration"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+var LDAPConfigurationString = `ldap:
+  url: ldap://ldap.example.com
+  startTLS: true
+  caCertificate: /etc/badaas/ldap-ca.crt
+  bindDN: cn=badaas,ou=services,dc=example,dc=com
+  bindPassword: secret
+  baseDN: ou=people,dc=example,dc=com
+  userFilter: (&(objectClass=person)(mail=%s))
+  attributes:
+    email: mail
+    username: sAMAccountName
+    groups: memberOf
+  groupSearch:
+    baseDN: ou=groups,dc=example,dc=com
+    filter: (member=%s)
+  groupRoles:
+    - group: cn=admins,ou=groups,dc=example,dc=com
+      roles: [admin, auditor]
+  provisioning: false
+  timeout: 5`
+
+func TestLDAPConfigurationNewLDAPConfiguration(t *testing.T) {
+	assert.NotNil(t, configuration.NewLDAPConfiguration(), "the contructor for LDAPConfiguration should not return a nil value")
+}
+
+func TestLDAPConfigurationGetters(t *testing.T) {
+	setupViperEnvironment(LDAPConfigurationString)
+	ldapConfiguration := configuration.NewLDAPConfiguration()
+	assert.True(t, ldapConfiguration.IsEnabled())
+	assert.Equal(t, "ldap://ldap.example.com", ldapConfiguration.GetURL())
+	assert.True(t, ldapConfiguration.GetStartTLS())
+	assert.Equal(t, "/etc/badaas/ldap-ca.crt", ldapConfiguration.GetCACertificate())
+	assert.Equal(t, "cn=badaas,ou=services,dc=example,dc=com", ldapConfiguration.GetBindDN())
+	assert.Equal(t, "secret", ldapConfiguration.GetBindPassword())
+	assert.Equal(t, "ou=people,dc=example,dc=com", ldapConfiguration.GetBaseDN())
+	assert.Equal(t, "(&(objectClass=person)(mail=%s))", ldapConfiguration.GetUserFilter())
+	assert.Equal(t, "mail", ldapConfiguration.GetEmailAttribute())
+	assert.Equal(t, "sAMAccountName", ldapConfiguration.GetUsernameAttribute())
+	assert.Equal(t, "memberOf", ldapConfiguration.GetGroupsAttribute())
+	assert.Equal(t, "ou=groups,dc=example,dc=com", ldapConfiguration.GetGroupBaseDN())
+	assert.Equal(t, "(member=%s)", ldapConfiguration.GetGroupFilter())
+	assert.Equal(t, []configuration.LDAPGroupRoles{
+		{Group: "cn=admins,ou=groups,dc=example,dc=com", Roles: []string{"admin", "auditor"}},
+	}, ldapConfiguration.GetGroupRoles())
+	assert.False(t, ldapConfiguration.GetProvisioning())
+	assert.Equal(t, 5*time.Second, ldapConfiguration.GetTimeout())
+}
+
+func TestLDAPConfigurationLog(t *testing.T) {
+	setupViperEnvironment(LDAPConfigurationString)
+	// creating logger
+	observedZapCore, observedLogs := observer.New(zap.DebugLevel)
+	observedLogger := zap.New(observedZapCore)
+
+	ldapConfiguration := configuration.NewLDAPConfiguration()
+	ldapConfiguration.Log(observedLogger)
+
+	require.Equal(t, 1, observedLogs.Len())
+	log := observedLogs.All()[0]
+	assert.Equal(t, "LDAP configuration", log.Message)
+	require.Len(t, log.Context, 14)
+	assert.Equal(t, zap.String("url", "ldap://ldap.example.com"), log.Context[0])
+	assert.Equal(t, zap.Strings("groupRoles", []string{"cn=admins,ou=groups,dc=example,dc=com"}), log.Context[11])
+	for _, field := range log.Context {
+		assert.NotEqual(t, "secret", field.String)
+	}
+}
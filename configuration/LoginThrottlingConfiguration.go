@@ -0,0 +1,108 @@
+package configuration
+
+import (
+	"time"
+
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// The config keys regarding the protection of the login against brute-force attacks
+const (
+	LoginThrottlingEnabledKey            string = "loginThrottling.enabled"
+	LoginThrottlingAccountMaxAttemptsKey string = "loginThrottling.account.maxAttempts"
+	LoginThrottlingIPMaxAttemptsKey      string = "loginThrottling.ip.maxAttempts"
+	LoginThrottlingBaseDelayKey          string = "loginThrottling.baseDelay"
+	LoginThrottlingMaxDelayKey           string = "loginThrottling.maxDelay"
+	LoginThrottlingLockoutDurationKey    string = "loginThrottling.lockoutDuration"
+	LoginThrottlingWindowKey             string = "loginThrottling.window"
+)
+
+// Hold the configuration values for the throttling of the failed login attempts
+type LoginThrottlingConfiguration interface {
+	ConfigurationHolder
+	GetEnabled() bool
+	GetAccountMaxAttempts() uint
+	GetIPMaxAttempts() uint
+	GetBaseDelay() time.Duration
+	GetMaxDelay() time.Duration
+	GetLockoutDuration() time.Duration
+	GetWindow() time.Duration
+}
+
+// Concrete implementation of the LoginThrottlingConfiguration interface
+type loginThrottlingConfigurationImpl struct {
+	enabled            bool
+	accountMaxAttempts uint
+	ipMaxAttempts      uint
+	baseDelay          time.Duration
+	maxDelay           time.Duration
+	lockoutDuration    time.Duration
+	window             time.Duration
+}
+
+// Instantiate a new configuration holder for the throttling of the failed login attempts
+func NewLoginThrottlingConfiguration() LoginThrottlingConfiguration {
+	loginThrottlingConfiguration := new(loginThrottlingConfigurationImpl)
+	loginThrottlingConfiguration.Reload()
+	return loginThrottlingConfiguration
+}
+
+// Return true if the failed login attempts are throttled
+func (loginThrottlingConfiguration *loginThrottlingConfigurationImpl) GetEnabled() bool {
+	return loginThrottlingConfiguration.enabled
+}
+
+// Return the number of failed attempts on an account before it is locked
+func (loginThrottlingConfiguration *loginThrottlingConfigurationImpl) GetAccountMaxAttempts() uint {
+	return loginThrottlingConfiguration.accountMaxAttempts
+}
+
+// Return the number of failed attempts from an ip address before it is locked
+func (loginThrottlingConfiguration *loginThrottlingConfigurationImpl) GetIPMaxAttempts() uint {
+	return loginThrottlingConfiguration.ipMaxAttempts
+}
+
+// Return the delay imposed after the first failed attempt, it doubles after each new failure
+func (loginThrottlingConfiguration *loginThrottlingConfigurationImpl) GetBaseDelay() time.Duration {
+	return loginThrottlingConfiguration.baseDelay
+}
+
+// Return the maximum delay imposed between two attempts
+func (loginThrottlingConfiguration *loginThrottlingConfigurationImpl) GetMaxDelay() time.Duration {
+	return loginThrottlingConfiguration.maxDelay
+}
+
+// Return the duration of a lockout
+func (loginThrottlingConfiguration *loginThrottlingConfigurationImpl) GetLockoutDuration() time.Duration {
+	return loginThrottlingConfiguration.lockoutDuration
+}
+
+// Return the duration after which the failed attempts are forgotten
+func (loginThrottlingConfiguration *loginThrottlingConfigurationImpl) GetWindow() time.Duration {
+	return loginThrottlingConfiguration.window
+}
+
+// Reload login throttling configuration
+func (loginThrottlingConfiguration *loginThrottlingConfigurationImpl) Reload() {
+	loginThrottlingConfiguration.enabled = viper.GetBool(LoginThrottlingEnabledKey)
+	loginThrottlingConfiguration.accountMaxAttempts = viper.GetUint(LoginThrottlingAccountMaxAttemptsKey)
+	loginThrottlingConfiguration.ipMaxAttempts = viper.GetUint(LoginThrottlingIPMaxAttemptsKey)
+	loginThrottlingConfiguration.baseDelay = intToSecond(int(viper.GetUint(LoginThrottlingBaseDelayKey)))
+	loginThrottlingConfiguration.maxDelay = intToSecond(int(viper.GetUint(LoginThrottlingMaxDelayKey)))
+	loginThrottlingConfiguration.lockoutDuration = intToSecond(int(viper.GetUint(LoginThrottlingLockoutDurationKey)))
+	loginThrottlingConfiguration.window = intToSecond(int(viper.GetUint(LoginThrottlingWindowKey)))
+}
+
+// Log the values provided by the configuration holder
+func (loginThrottlingConfiguration *loginThrottlingConfigurationImpl) Log(logger *zap.Logger) {
+	logger.Info("Login throttling configuration",
+		zap.Bool("enabled", loginThrottlingConfiguration.enabled),
+		zap.Uint("accountMaxAttempts", loginThrottlingConfiguration.accountMaxAttempts),
+		zap.Uint("ipMaxAttempts", loginThrottlingConfiguration.ipMaxAttempts),
+		zap.Duration("baseDelay", loginThrottlingConfiguration.baseDelay),
+		zap.Duration("maxDelay", loginThrottlingConfiguration.maxDelay),
+		zap.Duration("lockoutDuration", loginThrottlingConfiguration.lockoutDuration),
+		zap.Duration("window", loginThrottlingConfiguration.window),
+	)
+}
@@ -0,0 +1,63 @@
+package configuration_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ditrit/badaas/configuration"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+var LoginThrottlingConfigurationString = `loginThrottling:
+  enabled: true
+  account:
+    maxAttempts: 5
+  ip:
+    maxAttempts: 50
+  baseDelay: 1
+  maxDelay: 30
+  lockoutDuration: 900
+  window: 3600`
+
+func TestLoginThrottlingConfigurationNewLoginThrottlingConfiguration(t *testing.T) {
+	assert.NotNil(t, configuration.NewLoginThrottlingConfiguration(), "the contructor for LoginThrottlingConfiguration should not return a nil value")
+}
+
+func TestLoginThrottlingConfigurationGetters(t *testing.T) {
+	setupViperEnvironment(LoginThrottlingConfigurationString)
+	loginThrottlingConfiguration := configuration.NewLoginThrottlingConfiguration()
+	assert.True(t, loginThrottlingConfiguration.GetEnabled())
+	assert.Equal(t, uint(5), loginThrottlingConfiguration.GetAccountMaxAttempts())
+	assert.Equal(t, uint(50), loginThrottlingConfiguration.GetIPMaxAttempts())
+	assert.Equal(t, time.Second, loginThrottlingConfiguration.GetBaseDelay())
+	assert.Equal(t, 30*time.Second, loginThrottlingConfiguration.GetMaxDelay())
+	assert.Equal(t, 15*time.Minute, loginThrottlingConfiguration.GetLockoutDuration())
+	assert.Equal(t, time.Hour, loginThrottlingConfiguration.GetWindow())
+}
+
+func TestLoginThrottlingConfigurationLog(t *testing.T) {
+	setupViperEnvironment(LoginThrottlingConfigurationString)
+	// creating logger
+	observedZapCore, observedLogs := observer.New(zap.DebugLevel)
+	observedLogger := zap.New(observedZapCore)
+
+	loginThrottlingConfiguration := configuration.NewLoginThrottlingConfiguration()
+	loginThrottlingConfiguration.Log(observedLogger)
+
+	require.Equal(t, 1, observedLogs.Len())
+	log := observedLogs.All()[0]
+	assert.Equal(t, "Login throttling configuration", log.Message)
+	assert.Equal(t, []zap.Field{
+		{Key: "enabled", Type: zapcore.BoolType, Integer: 1},
+		{Key: "accountMaxAttempts", Type: zapcore.Uint64Type, Integer: 5},
+		{Key: "ipMaxAttempts", Type: zapcore.Uint64Type, Integer: 50},
+		{Key: "baseDelay", Type: zapcore.DurationType, Integer: int64(time.Second)},
+		{Key: "maxDelay", Type: zapcore.DurationType, Integer: int64(30 * time.Second)},
+		{Key: "lockoutDuration", Type: zapcore.DurationType, Integer: int64(15 * time.Minute)},
+		{Key: "window", Type: zapcore.DurationType, Integer: int64(time.Hour)},
+	}, log.Context)
+}
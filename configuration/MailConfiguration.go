@@ -0,0 +1,164 @@
+package configuration
+
+import (
+	"time"
+
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// The config keys regarding the mail settings
+const (
+	MailTransportKey        string = "mail.transport"
+	MailFromKey             string = "mail.from"
+	MailDefaultLocaleKey    string = "mail.defaultLocale"
+	MailTemplatesPathKey    string = "mail.templatesPath"
+	MailFilePathKey         string = "mail.file.path"
+	MailSMTPHostKey         string = "mail.smtp.host"
+	MailSMTPPortKey         string = "mail.smtp.port"
+	MailSMTPUsernameKey     string = "mail.smtp.username"
+	MailSMTPPasswordKey     string = "mail.smtp.password"
+	MailSMTPStartTLSKey     string = "mail.smtp.startTLS"
+	MailQueueIntervalKey    string = "mail.queue.interval"
+	MailQueueMaxAttemptsKey string = "mail.queue.maxAttempts"
+)
+
+// The transports available to send the mails
+const (
+	MailTransportLog  string = "log"
+	MailTransportFile string = "file"
+	MailTransportSMTP string = "smtp"
+)
+
+// Hold the configuration values to send the mails
+type MailConfiguration interface {
+	ConfigurationHolder
+	GetTransport() string
+	GetFrom() string
+	GetDefaultLocale() string
+	GetTemplatesPath() string
+	GetFilePath() string
+	GetSMTPHost() string
+	GetSMTPPort() int
+	GetSMTPUsername() string
+	GetSMTPPassword() string
+	GetSMTPStartTLS() bool
+	GetQueueInterval() time.Duration
+	GetQueueMaxAttempts() uint
+}
+
+// Concrete implementation of the MailConfiguration interface
+type mailConfigurationImpl struct {
+	transport        string
+	from             string
+	defaultLocale    string
+	templatesPath    string
+	filePath         string
+	smtpHost         string
+	smtpPort         int
+	smtpUsername     string
+	smtpPassword     string
+	smtpStartTLS     bool
+	queueInterval    time.Duration
+	queueMaxAttempts uint
+}
+
+// Instantiate a new configuration holder for the mails
+func NewMailConfiguration() MailConfiguration {
+	mailConfiguration := new(mailConfigurationImpl)
+	mailConfiguration.Reload()
+	return mailConfiguration
+}
+
+// Return the transport used to send the mails (log, file or smtp)
+func (mailConfiguration *mailConfigurationImpl) GetTransport() string {
+	return mailConfiguration.transport
+}
+
+// Return the sender address of the mails
+func (mailConfiguration *mailConfigurationImpl) GetFrom() string {
+	return mailConfiguration.from
+}
+
+// Return the locale of the mails sent to the users without a locale
+func (mailConfiguration *mailConfigurationImpl) GetDefaultLocale() string {
+	return mailConfiguration.defaultLocale
+}
+
+// Return the directory of the templates overriding the default ones, empty if not set
+func (mailConfiguration *mailConfigurationImpl) GetTemplatesPath() string {
+	return mailConfiguration.templatesPath
+}
+
+// Return the directory where the file transport writes the mails
+func (mailConfiguration *mailConfigurationImpl) GetFilePath() string {
+	return mailConfiguration.filePath
+}
+
+// Return the host of the smtp server
+func (mailConfiguration *mailConfigurationImpl) GetSMTPHost() string {
+	return mailConfiguration.smtpHost
+}
+
+// Return the port of the smtp server
+func (mailConfiguration *mailConfigurationImpl) GetSMTPPort() int {
+	return mailConfiguration.smtpPort
+}
+
+// Return the username used to authenticate on the smtp server, no authentication if empty
+func (mailConfiguration *mailConfigurationImpl) GetSMTPUsername() string {
+	return mailConfiguration.smtpUsername
+}
+
+// Return the password used to authenticate on the smtp server
+func (mailConfiguration *mailConfigurationImpl) GetSMTPPassword() string {
+	return mailConfiguration.smtpPassword
+}
+
+// Return true if the connection to the smtp server must be upgraded with STARTTLS
+func (mailConfiguration *mailConfigurationImpl) GetSMTPStartTLS() bool {
+	return mailConfiguration.smtpStartTLS
+}
+
+// Return the interval between two runs of the send queue
+func (mailConfiguration *mailConfigurationImpl) GetQueueInterval() time.Duration {
+	return mailConfiguration.queueInterval
+}
+
+// Return the number of attempts to send a mail before giving up
+func (mailConfiguration *mailConfigurationImpl) GetQueueMaxAttempts() uint {
+	return mailConfiguration.queueMaxAttempts
+}
+
+// Reload mail configuration
+func (mailConfiguration *mailConfigurationImpl) Reload() {
+	mailConfiguration.transport = viper.GetString(MailTransportKey)
+	mailConfiguration.from = viper.GetString(MailFromKey)
+	mailConfiguration.defaultLocale = viper.GetString(MailDefaultLocaleKey)
+	mailConfiguration.templatesPath = viper.GetString(MailTemplatesPathKey)
+	mailConfiguration.filePath = viper.GetString(MailFilePathKey)
+	mailConfiguration.smtpHost = viper.GetString(MailSMTPHostKey)
+	mailConfiguration.smtpPort = viper.GetInt(MailSMTPPortKey)
+	mailConfiguration.smtpUsername = viper.GetString(MailSMTPUsernameKey)
+	mailConfiguration.smtpPassword = viper.GetString(MailSMTPPasswordKey)
+	mailConfiguration.smtpStartTLS = viper.GetBool(MailSMTPStartTLSKey)
+	mailConfiguration.queueInterval = intToSecond(int(viper.GetUint(MailQueueIntervalKey)))
+	mailConfiguration.queueMaxAttempts = viper.GetUint(MailQueueMaxAttemptsKey)
+}
+
+// Log the values provided by the configuration holder
+func (mailConfiguration *mailConfigurationImpl) Log(logger *zap.Logger) {
+	logger.Info("Mail configuration",
+		zap.String("transport", mailConfiguration.transport),
+		zap.String("from", mailConfiguration.from),
+		zap.String("defaultLocale", mailConfiguration.defaultLocale),
+		zap.String("templatesPath", mailConfiguration.templatesPath),
+		zap.String("filePath", mailConfiguration.filePath),
+		zap.String("smtpHost", mailConfiguration.smtpHost),
+		zap.Int("smtpPort", mailConfiguration.smtpPort),
+		zap.String("smtpUsername", mailConfiguration.smtpUsername),
+		zap.Bool("smtpStartTLS", mailConfiguration.smtpStartTLS),
+		zap.Duration("queueInterval", mailConfiguration.queueInterval),
+		zap.Uint("queueMaxAttempts", mailConfiguration.queueMaxAttempts),
+	)
+}
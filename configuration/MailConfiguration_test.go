@@ -0,0 +1,67 @@
+package configuration_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ditrit/badaas/configuration"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+var MailConfigurationString = `mail:
+  transport: smtp
+  from: no-reply@badaas.com
+  defaultLocale: fr
+  templatesPath: /etc/badaas/templates
+  file:
+    path: /tmp/mails
+  smtp:
+    host: smtp.badaas.com
+    port: 587
+    username: badaas
+    password: secret
+    startTLS: true
+  queue:
+    interval: 10
+    maxAttempts: 3`
+
+func TestMailConfigurationNewMailConfiguration(t *testing.T) {
+	assert.NotNil(t, configuration.NewMailConfiguration(), "the contructor for MailConfiguration should not return a nil value")
+}
+
+func TestMailConfigurationGetters(t *testing.T) {
+	setupViperEnvironment(MailConfigurationString)
+	mailConfiguration := configuration.NewMailConfiguration()
+	assert.Equal(t, configuration.MailTransportSMTP, mailConfiguration.GetTransport())
+	assert.Equal(t, "no-reply@badaas.com", mailConfiguration.GetFrom())
+	assert.Equal(t, "fr", mailConfiguration.GetDefaultLocale())
+	assert.Equal(t, "/etc/badaas/templates", mailConfiguration.GetTemplatesPath())
+	assert.Equal(t, "/tmp/mails", mailConfiguration.GetFilePath())
+	assert.Equal(t, "smtp.badaas.com", mailConfiguration.GetSMTPHost())
+	assert.Equal(t, 587, mailConfiguration.GetSMTPPort())
+	assert.Equal(t, "badaas", mailConfiguration.GetSMTPUsername())
+	assert.Equal(t, "secret", mailConfiguration.GetSMTPPassword())
+	assert.True(t, mailConfiguration.GetSMTPStartTLS())
+	assert.Equal(t, 10*time.Second, mailConfiguration.GetQueueInterval())
+	assert.Equal(t, uint(3), mailConfiguration.GetQueueMaxAttempts())
+}
+
+func TestMailConfigurationLog(t *testing.T) {
+	setupViperEnvironment(MailConfigurationString)
+	// creating logger
+	observedZapCore, observedLogs := observer.New(zap.DebugLevel)
+	observedLogger := zap.New(observedZapCore)
+
+	mailConfiguration := configuration.NewMailConfiguration()
+	mailConfiguration.Log(observedLogger)
+
+	require.Equal(t, 1, observedLogs.Len())
+	log := observedLogs.All()[0]
+	assert.Equal(t, "Mail configuration", log.Message)
+	require.Len(t, log.Context, 11)
+	// the password is secret
+	assert.NotContains(t, log.ContextMap(), "smtpPassword")
+}
@@ -11,4 +11,20 @@ var ConfigurationModule = fx.Module(
 	fx.Provide(NewPaginationConfiguration),
 	fx.Provide(NewInitializationConfiguration),
 	fx.Provide(NewSessionConfiguration),
+	fx.Provide(NewPolicyConfiguration),
+	fx.Provide(NewRegistrationConfiguration),
+	fx.Provide(NewEmailVerificationConfiguration),
+	fx.Provide(NewPasswordResetConfiguration),
+	fx.Provide(NewMailConfiguration),
+	fx.Provide(NewPasswordHashingConfiguration),
+	fx.Provide(NewPasswordPolicyConfiguration),
+	fx.Provide(NewLoginThrottlingConfiguration),
+	fx.Provide(NewTwoFactorConfiguration),
+	fx.Provide(NewWebAuthnConfiguration),
+	fx.Provide(NewOIDCConfiguration),
+	fx.Provide(NewSAMLConfiguration),
+	fx.Provide(NewLDAPConfiguration),
+	fx.Provide(NewOAuthConfiguration),
+	fx.Provide(NewAuthenticationConfiguration),
+	fx.Provide(NewCookieConfiguration),
 )
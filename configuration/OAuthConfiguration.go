@@ -0,0 +1,93 @@
+package configuration
+
+import (
+	"time"
+
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// The config keys regarding the OAuth 2.0 authorization server settings
+const (
+	OAuthScopesKey               string = "oauth.scopes"
+	OAuthCodeDurationKey         string = "oauth.codeDuration"
+	OAuthAccessTokenDurationKey  string = "oauth.accessTokenDuration"
+	OAuthRefreshTokenDurationKey string = "oauth.refreshTokenDuration"
+)
+
+// A scope the clients can ask, its description is shown to the user on the consent screen
+type OAuthScope struct {
+	Name        string `mapstructure:"name"`
+	Description string `mapstructure:"description"`
+}
+
+// Hold the configuration values for the OAuth 2.0 authorization server
+type OAuthConfiguration interface {
+	ConfigurationHolder
+	GetScopes() []OAuthScope
+	GetCodeDuration() time.Duration
+	GetAccessTokenDuration() time.Duration
+	GetRefreshTokenDuration() time.Duration
+}
+
+// Concrete implementation of the OAuthConfiguration interface
+type oauthConfigurationImpl struct {
+	scopes               []OAuthScope
+	codeDuration         time.Duration
+	accessTokenDuration  time.Duration
+	refreshTokenDuration time.Duration
+}
+
+// Instantiate a new configuration holder for the OAuth 2.0 authorization server
+func NewOAuthConfiguration() OAuthConfiguration {
+	oauthConfiguration := new(oauthConfigurationImpl)
+	oauthConfiguration.Reload()
+	return oauthConfiguration
+}
+
+// Return the scopes the clients can ask
+func (oauthConfiguration *oauthConfigurationImpl) GetScopes() []OAuthScope {
+	return oauthConfiguration.scopes
+}
+
+// Return the duration during which an authorization code can be exchanged
+func (oauthConfiguration *oauthConfigurationImpl) GetCodeDuration() time.Duration {
+	return oauthConfiguration.codeDuration
+}
+
+// Return the duration of validity of the access tokens
+func (oauthConfiguration *oauthConfigurationImpl) GetAccessTokenDuration() time.Duration {
+	return oauthConfiguration.accessTokenDuration
+}
+
+// Return the duration of validity of the refresh tokens, renewed on each rotation
+func (oauthConfiguration *oauthConfigurationImpl) GetRefreshTokenDuration() time.Duration {
+	return oauthConfiguration.refreshTokenDuration
+}
+
+// Reload OAuth configuration
+func (oauthConfiguration *oauthConfigurationImpl) Reload() {
+	scopes := []OAuthScope{}
+	err := viper.UnmarshalKey(OAuthScopesKey, &scopes)
+	if err != nil {
+		panic(err)
+	}
+	oauthConfiguration.scopes = scopes
+	oauthConfiguration.codeDuration = intToSecond(int(viper.GetUint(OAuthCodeDurationKey)))
+	oauthConfiguration.accessTokenDuration = intToSecond(int(viper.GetUint(OAuthAccessTokenDurationKey)))
+	oauthConfiguration.refreshTokenDuration = intToSecond(int(viper.GetUint(OAuthRefreshTokenDurationKey)))
+}
+
+// Log the values provided by the configuration holder
+func (oauthConfiguration *oauthConfigurationImpl) Log(logger *zap.Logger) {
+	scopes := make([]string, 0, len(oauthConfiguration.scopes))
+	for _, scope := range oauthConfiguration.scopes {
+		scopes = append(scopes, scope.Name)
+	}
+	logger.Info("OAuth configuration",
+		zap.Strings("scopes", scopes),
+		zap.Duration("codeDuration", oauthConfiguration.codeDuration),
+		zap.Duration("accessTokenDuration", oauthConfiguration.accessTokenDuration),
+		zap.Duration("refreshTokenDuration", oauthConfiguration.refreshTokenDuration),
+	)
+}
@@ -0,0 +1,55 @@
+package configuration_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ditrit/badaas/configuration"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+var OAuthConfigurationString = `oauth:
+  scopes:
+    - name: profile
+      description: Read your profile
+    - name: orders:read
+      description: Read your orders
+  codeDuration: 30
+  accessTokenDuration: 600
+  refreshTokenDuration: 86400`
+
+func TestOAuthConfigurationNewOAuthConfiguration(t *testing.T) {
+	assert.NotNil(t, configuration.NewOAuthConfiguration(), "the contructor for OAuthConfiguration should not return a nil value")
+}
+
+func TestOAuthConfigurationGetters(t *testing.T) {
+	setupViperEnvironment(OAuthConfigurationString)
+	oauthConfiguration := configuration.NewOAuthConfiguration()
+	assert.Equal(t, []configuration.OAuthScope{
+		{Name: "profile", Description: "Read your profile"},
+		{Name: "orders:read", Description: "Read your orders"},
+	}, oauthConfiguration.GetScopes())
+	assert.Equal(t, 30*time.Second, oauthConfiguration.GetCodeDuration())
+	assert.Equal(t, 10*time.Minute, oauthConfiguration.GetAccessTokenDuration())
+	assert.Equal(t, 24*time.Hour, oauthConfiguration.GetRefreshTokenDuration())
+}
+
+func TestOAuthConfigurationLog(t *testing.T) {
+	setupViperEnvironment(OAuthConfigurationString)
+	// creating logger
+	observedZapCore, observedLogs := observer.New(zap.DebugLevel)
+	observedLogger := zap.New(observedZapCore)
+
+	oauthConfiguration := configuration.NewOAuthConfiguration()
+	oauthConfiguration.Log(observedLogger)
+
+	require.Equal(t, 1, observedLogs.Len())
+	log := observedLogs.All()[0]
+	assert.Equal(t, "OAuth configuration", log.Message)
+	require.Len(t, log.Context, 4)
+	assert.Equal(t, zap.Strings("scopes", []string{"profile", "orders:read"}), log.Context[0])
+	assert.Equal(t, zap.Duration("accessTokenDuration", 10*time.Minute), log.Context[2])
+}
@@ -0,0 +1,108 @@
+package configuration
+
+import (
+	"time"
+
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// The config keys regarding the OpenID Connect settings
+const (
+	OIDCProvidersKey        string = "oidc.providers"
+	OIDCRequestDurationKey  string = "oidc.requestDuration"
+	OIDCProvisioningKey     string = "oidc.provisioning"
+	OIDCLinkByEmailKey      string = "oidc.linkByEmail"
+	OIDCLoginRedirectURLKey string = "oidc.loginRedirectURL"
+)
+
+// An OpenID provider the users can log in with
+type OIDCProvider struct {
+	// The name of the provider in the urls of the login
+	Name         string   `mapstructure:"name"`
+	Issuer       string   `mapstructure:"issuer"`
+	ClientID     string   `mapstructure:"clientID"`
+	ClientSecret string   `mapstructure:"clientSecret"`
+	RedirectURL  string   `mapstructure:"redirectURL"`
+	Scopes       []string `mapstructure:"scopes"`
+}
+
+// Hold the configuration values for the OpenID Connect login
+type OIDCConfiguration interface {
+	ConfigurationHolder
+	GetProviders() []OIDCProvider
+	GetRequestDuration() time.Duration
+	GetProvisioning() bool
+	GetLinkByEmail() bool
+	GetLoginRedirectURL() string
+}
+
+// Concrete implementation of the OIDCConfiguration interface
+type oidcConfigurationImpl struct {
+	providers        []OIDCProvider
+	requestDuration  time.Duration
+	provisioning     bool
+	linkByEmail      bool
+	loginRedirectURL string
+}
+
+// Instantiate a new configuration holder for the OpenID Connect login
+func NewOIDCConfiguration() OIDCConfiguration {
+	oidcConfiguration := new(oidcConfigurationImpl)
+	oidcConfiguration.Reload()
+	return oidcConfiguration
+}
+
+// Return the configured providers
+func (oidcConfiguration *oidcConfigurationImpl) GetProviders() []OIDCProvider {
+	return oidcConfiguration.providers
+}
+
+// Return the duration during which the user can log in on the provider
+func (oidcConfiguration *oidcConfigurationImpl) GetRequestDuration() time.Duration {
+	return oidcConfiguration.requestDuration
+}
+
+// Return true if a user is created for the unknown identities
+func (oidcConfiguration *oidcConfigurationImpl) GetProvisioning() bool {
+	return oidcConfiguration.provisioning
+}
+
+// Return true if an unknown identity is linked to the user with the same verified email
+func (oidcConfiguration *oidcConfigurationImpl) GetLinkByEmail() bool {
+	return oidcConfiguration.linkByEmail
+}
+
+// Return the url the user is redirected to once logged in, the user is described in JSON if empty
+func (oidcConfiguration *oidcConfigurationImpl) GetLoginRedirectURL() string {
+	return oidcConfiguration.loginRedirectURL
+}
+
+// Reload OpenID Connect configuration
+func (oidcConfiguration *oidcConfigurationImpl) Reload() {
+	providers := []OIDCProvider{}
+	err := viper.UnmarshalKey(OIDCProvidersKey, &providers)
+	if err != nil {
+		panic(err)
+	}
+	oidcConfiguration.providers = providers
+	oidcConfiguration.requestDuration = intToSecond(int(viper.GetUint(OIDCRequestDurationKey)))
+	oidcConfiguration.provisioning = viper.GetBool(OIDCProvisioningKey)
+	oidcConfiguration.linkByEmail = viper.GetBool(OIDCLinkByEmailKey)
+	oidcConfiguration.loginRedirectURL = viper.GetString(OIDCLoginRedirectURLKey)
+}
+
+// Log the values provided by the configuration holder, the client secrets are not logged
+func (oidcConfiguration *oidcConfigurationImpl) Log(logger *zap.Logger) {
+	providers := make([]string, 0, len(oidcConfiguration.providers))
+	for _, provider := range oidcConfiguration.providers {
+		providers = append(providers, provider.Name+" ("+provider.Issuer+")")
+	}
+	logger.Info("OIDC configuration",
+		zap.Strings("providers", providers),
+		zap.Duration("requestDuration", oidcConfiguration.requestDuration),
+		zap.Bool("provisioning", oidcConfiguration.provisioning),
+		zap.Bool("linkByEmail", oidcConfiguration.linkByEmail),
+		zap.String("loginRedirectURL", oidcConfiguration.loginRedirectURL),
+	)
+}
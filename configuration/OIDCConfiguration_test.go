@@ -0,0 +1,69 @@
+package configuration_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ditrit/badaas/configuration"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+var OIDCConfigurationString = `oidc:
+  providers:
+    - name: google
+      issuer: https://accounts.google.com
+      clientID: badaas
+      clientSecret: secret
+      redirectURL: https://badaas.example.com/login/oidc/google/callback
+      scopes:
+        - openid
+        - email
+  requestDuration: 600
+  provisioning: false
+  linkByEmail: true
+  loginRedirectURL: https://app.example.com`
+
+func TestOIDCConfigurationNewOIDCConfiguration(t *testing.T) {
+	assert.NotNil(t, configuration.NewOIDCConfiguration(), "the contructor for OIDCConfiguration should not return a nil value")
+}
+
+func TestOIDCConfigurationGetters(t *testing.T) {
+	setupViperEnvironment(OIDCConfigurationString)
+	oidcConfiguration := configuration.NewOIDCConfiguration()
+	assert.Equal(t, []configuration.OIDCProvider{{
+		Name:         "google",
+		Issuer:       "https://accounts.google.com",
+		ClientID:     "badaas",
+		ClientSecret: "secret",
+		RedirectURL:  "https://badaas.example.com/login/oidc/google/callback",
+		Scopes:       []string{"openid", "email"},
+	}}, oidcConfiguration.GetProviders())
+	assert.Equal(t, 10*time.Minute, oidcConfiguration.GetRequestDuration())
+	assert.False(t, oidcConfiguration.GetProvisioning())
+	assert.True(t, oidcConfiguration.GetLinkByEmail())
+	assert.Equal(t, "https://app.example.com", oidcConfiguration.GetLoginRedirectURL())
+}
+
+func TestOIDCConfigurationLog(t *testing.T) {
+	setupViperEnvironment(OIDCConfigurationString)
+	// creating logger
+	observedZapCore, observedLogs := observer.New(zap.DebugLevel)
+	observedLogger := zap.New(observedZapCore)
+
+	oidcConfiguration := configuration.NewOIDCConfiguration()
+	oidcConfiguration.Log(observedLogger)
+
+	require.Equal(t, 1, observedLogs.Len())
+	log := observedLogs.All()[0]
+	assert.Equal(t, "OIDC configuration", log.Message)
+	require.Len(t, log.Context, 5)
+	assert.Equal(t, "providers", log.Context[0].Key)
+	assert.NotContains(t, log.Context[0].Interface, "secret")
+	assert.Equal(t, zap.Duration("requestDuration", 10*time.Minute), log.Context[1])
+	assert.Equal(t, zap.Bool("provisioning", false), log.Context[2])
+	assert.Equal(t, zap.Bool("linkByEmail", true), log.Context[3])
+	assert.Equal(t, zap.String("loginRedirectURL", "https://app.example.com"), log.Context[4])
+}
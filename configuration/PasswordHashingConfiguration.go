@@ -0,0 +1,92 @@
+package configuration
+
+import (
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// The config keys regarding the password hashing settings
+const (
+	PasswordHashingAlgorithmKey           string = "passwordHashing.algorithm"
+	PasswordHashingArgon2idMemoryKey      string = "passwordHashing.argon2id.memory"
+	PasswordHashingArgon2idIterationsKey  string = "passwordHashing.argon2id.iterations"
+	PasswordHashingArgon2idParallelismKey string = "passwordHashing.argon2id.parallelism"
+	PasswordHashingBcryptCostKey          string = "passwordHashing.bcrypt.cost"
+)
+
+// The algorithms available to hash the passwords
+const (
+	PasswordHashingArgon2id string = "argon2id"
+	PasswordHashingBcrypt   string = "bcrypt"
+)
+
+// Hold the configuration values to hash the passwords
+type PasswordHashingConfiguration interface {
+	ConfigurationHolder
+	GetAlgorithm() string
+	GetArgon2idMemory() uint32
+	GetArgon2idIterations() uint32
+	GetArgon2idParallelism() uint8
+	GetBcryptCost() int
+}
+
+// Concrete implementation of the PasswordHashingConfiguration interface
+type passwordHashingConfigurationImpl struct {
+	algorithm           string
+	argon2idMemory      uint32
+	argon2idIterations  uint32
+	argon2idParallelism uint8
+	bcryptCost          int
+}
+
+// Instantiate a new configuration holder for the hashing of the passwords
+func NewPasswordHashingConfiguration() PasswordHashingConfiguration {
+	passwordHashingConfiguration := new(passwordHashingConfigurationImpl)
+	passwordHashingConfiguration.Reload()
+	return passwordHashingConfiguration
+}
+
+// Return the algorithm used to hash the new passwords (argon2id or bcrypt)
+func (passwordHashingConfiguration *passwordHashingConfigurationImpl) GetAlgorithm() string {
+	return passwordHashingConfiguration.algorithm
+}
+
+// Return the memory in KiB used by argon2id
+func (passwordHashingConfiguration *passwordHashingConfigurationImpl) GetArgon2idMemory() uint32 {
+	return passwordHashingConfiguration.argon2idMemory
+}
+
+// Return the number of passes over the memory of argon2id
+func (passwordHashingConfiguration *passwordHashingConfigurationImpl) GetArgon2idIterations() uint32 {
+	return passwordHashingConfiguration.argon2idIterations
+}
+
+// Return the number of threads used by argon2id
+func (passwordHashingConfiguration *passwordHashingConfigurationImpl) GetArgon2idParallelism() uint8 {
+	return passwordHashingConfiguration.argon2idParallelism
+}
+
+// Return the cost of bcrypt
+func (passwordHashingConfiguration *passwordHashingConfigurationImpl) GetBcryptCost() int {
+	return passwordHashingConfiguration.bcryptCost
+}
+
+// Reload password hashing configuration
+func (passwordHashingConfiguration *passwordHashingConfigurationImpl) Reload() {
+	passwordHashingConfiguration.algorithm = viper.GetString(PasswordHashingAlgorithmKey)
+	passwordHashingConfiguration.argon2idMemory = viper.GetUint32(PasswordHashingArgon2idMemoryKey)
+	passwordHashingConfiguration.argon2idIterations = viper.GetUint32(PasswordHashingArgon2idIterationsKey)
+	passwordHashingConfiguration.argon2idParallelism = uint8(viper.GetUint(PasswordHashingArgon2idParallelismKey))
+	passwordHashingConfiguration.bcryptCost = viper.GetInt(PasswordHashingBcryptCostKey)
+}
+
+// Log the values provided by the configuration holder
+func (passwordHashingConfiguration *passwordHashingConfigurationImpl) Log(logger *zap.Logger) {
+	logger.Info("Password hashing configuration",
+		zap.String("algorithm", passwordHashingConfiguration.algorithm),
+		zap.Uint32("argon2idMemory", passwordHashingConfiguration.argon2idMemory),
+		zap.Uint32("argon2idIterations", passwordHashingConfiguration.argon2idIterations),
+		zap.Uint8("argon2idParallelism", passwordHashingConfiguration.argon2idParallelism),
+		zap.Int("bcryptCost", passwordHashingConfiguration.bcryptCost),
+	)
+}
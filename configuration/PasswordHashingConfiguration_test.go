@@ -0,0 +1,56 @@
+package configuration_test
+
+import (
+	"testing"
+
+	"github.com/ditrit/badaas/configuration"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+var PasswordHashingConfigurationString = `passwordHashing:
+  algorithm: bcrypt
+  argon2id:
+    memory: 19456
+    iterations: 2
+    parallelism: 1
+  bcrypt:
+    cost: 12`
+
+func TestPasswordHashingConfigurationNewPasswordHashingConfiguration(t *testing.T) {
+	assert.NotNil(t, configuration.NewPasswordHashingConfiguration(), "the contructor for PasswordHashingConfiguration should not return a nil value")
+}
+
+func TestPasswordHashingConfigurationGetters(t *testing.T) {
+	setupViperEnvironment(PasswordHashingConfigurationString)
+	passwordHashingConfiguration := configuration.NewPasswordHashingConfiguration()
+	assert.Equal(t, configuration.PasswordHashingBcrypt, passwordHashingConfiguration.GetAlgorithm())
+	assert.Equal(t, uint32(19456), passwordHashingConfiguration.GetArgon2idMemory())
+	assert.Equal(t, uint32(2), passwordHashingConfiguration.GetArgon2idIterations())
+	assert.Equal(t, uint8(1), passwordHashingConfiguration.GetArgon2idParallelism())
+	assert.Equal(t, 12, passwordHashingConfiguration.GetBcryptCost())
+}
+
+func TestPasswordHashingConfigurationLog(t *testing.T) {
+	setupViperEnvironment(PasswordHashingConfigurationString)
+	// creating logger
+	observedZapCore, observedLogs := observer.New(zap.DebugLevel)
+	observedLogger := zap.New(observedZapCore)
+
+	passwordHashingConfiguration := configuration.NewPasswordHashingConfiguration()
+	passwordHashingConfiguration.Log(observedLogger)
+
+	require.Equal(t, 1, observedLogs.Len())
+	log := observedLogs.All()[0]
+	assert.Equal(t, "Password hashing configuration", log.Message)
+	assert.Equal(t, []zap.Field{
+		{Key: "algorithm", Type: zapcore.StringType, String: "bcrypt"},
+		{Key: "argon2idMemory", Type: zapcore.Uint32Type, Integer: 19456},
+		{Key: "argon2idIterations", Type: zapcore.Uint32Type, Integer: 2},
+		{Key: "argon2idParallelism", Type: zapcore.Uint8Type, Integer: 1},
+		{Key: "bcryptCost", Type: zapcore.Int64Type, Integer: 12},
+	}, log.Context)
+}
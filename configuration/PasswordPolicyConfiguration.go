@@ -0,0 +1,146 @@
+package configuration
+
+import (
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// The config keys regarding the password policy settings
+const (
+	PasswordPolicyMinLengthKey                  string = "passwordPolicy.minLength"
+	PasswordPolicyMaxLengthKey                  string = "passwordPolicy.maxLength"
+	PasswordPolicyRequireLowercaseKey           string = "passwordPolicy.requireLowercase"
+	PasswordPolicyRequireUppercaseKey           string = "passwordPolicy.requireUppercase"
+	PasswordPolicyRequireDigitKey               string = "passwordPolicy.requireDigit"
+	PasswordPolicyRequireSymbolKey              string = "passwordPolicy.requireSymbol"
+	PasswordPolicyForbidUserInfoKey             string = "passwordPolicy.forbidUserInfo"
+	PasswordPolicyHistorySizeKey                string = "passwordPolicy.historySize"
+	PasswordPolicyBreachedEnabledKey            string = "passwordPolicy.breached.enabled"
+	PasswordPolicyBreachedPathKey               string = "passwordPolicy.breached.path"
+	PasswordPolicyRefuseDefaultAdminPasswordKey string = "passwordPolicy.refuseDefaultAdminPassword"
+)
+
+// Hold the configuration values of the password policy
+type PasswordPolicyConfiguration interface {
+	ConfigurationHolder
+	GetMinLength() uint
+	GetMaxLength() uint
+	GetRequireLowercase() bool
+	GetRequireUppercase() bool
+	GetRequireDigit() bool
+	GetRequireSymbol() bool
+	GetForbidUserInfo() bool
+	GetHistorySize() uint
+	GetBreachedEnabled() bool
+	GetBreachedPath() string
+	GetRefuseDefaultAdminPassword() bool
+}
+
+// Concrete implementation of the PasswordPolicyConfiguration interface
+type passwordPolicyConfigurationImpl struct {
+	minLength                  uint
+	maxLength                  uint
+	requireLowercase           bool
+	requireUppercase           bool
+	requireDigit               bool
+	requireSymbol              bool
+	forbidUserInfo             bool
+	historySize                uint
+	breachedEnabled            bool
+	breachedPath               string
+	refuseDefaultAdminPassword bool
+}
+
+// Instantiate a new configuration holder for the password policy
+func NewPasswordPolicyConfiguration() PasswordPolicyConfiguration {
+	passwordPolicyConfiguration := new(passwordPolicyConfigurationImpl)
+	passwordPolicyConfiguration.Reload()
+	return passwordPolicyConfiguration
+}
+
+// Return the minimum number of characters of a password
+func (passwordPolicyConfiguration *passwordPolicyConfigurationImpl) GetMinLength() uint {
+	return passwordPolicyConfiguration.minLength
+}
+
+// Return the maximum number of characters of a password
+func (passwordPolicyConfiguration *passwordPolicyConfigurationImpl) GetMaxLength() uint {
+	return passwordPolicyConfiguration.maxLength
+}
+
+// Return true if a password must contain a lowercase letter
+func (passwordPolicyConfiguration *passwordPolicyConfigurationImpl) GetRequireLowercase() bool {
+	return passwordPolicyConfiguration.requireLowercase
+}
+
+// Return true if a password must contain an uppercase letter
+func (passwordPolicyConfiguration *passwordPolicyConfigurationImpl) GetRequireUppercase() bool {
+	return passwordPolicyConfiguration.requireUppercase
+}
+
+// Return true if a password must contain a digit
+func (passwordPolicyConfiguration *passwordPolicyConfigurationImpl) GetRequireDigit() bool {
+	return passwordPolicyConfiguration.requireDigit
+}
+
+// Return true if a password must contain a character that is neither a letter nor a digit
+func (passwordPolicyConfiguration *passwordPolicyConfigurationImpl) GetRequireSymbol() bool {
+	return passwordPolicyConfiguration.requireSymbol
+}
+
+// Return true if a password can't contain the username or the email of the user
+func (passwordPolicyConfiguration *passwordPolicyConfigurationImpl) GetForbidUserInfo() bool {
+	return passwordPolicyConfiguration.forbidUserInfo
+}
+
+// Return the number of last passwords of a user that can't be reused, including the current one
+func (passwordPolicyConfiguration *passwordPolicyConfigurationImpl) GetHistorySize() uint {
+	return passwordPolicyConfiguration.historySize
+}
+
+// Return true if the passwords are checked against the list of breached passwords
+func (passwordPolicyConfiguration *passwordPolicyConfigurationImpl) GetBreachedEnabled() bool {
+	return passwordPolicyConfiguration.breachedEnabled
+}
+
+// Return the path of the list of breached passwords, the bundled list is used if empty
+func (passwordPolicyConfiguration *passwordPolicyConfigurationImpl) GetBreachedPath() string {
+	return passwordPolicyConfiguration.breachedPath
+}
+
+// Return true if the server must refuse to start while the super admin uses the default password
+func (passwordPolicyConfiguration *passwordPolicyConfigurationImpl) GetRefuseDefaultAdminPassword() bool {
+	return passwordPolicyConfiguration.refuseDefaultAdminPassword
+}
+
+// Reload password policy configuration
+func (passwordPolicyConfiguration *passwordPolicyConfigurationImpl) Reload() {
+	passwordPolicyConfiguration.minLength = viper.GetUint(PasswordPolicyMinLengthKey)
+	passwordPolicyConfiguration.maxLength = viper.GetUint(PasswordPolicyMaxLengthKey)
+	passwordPolicyConfiguration.requireLowercase = viper.GetBool(PasswordPolicyRequireLowercaseKey)
+	passwordPolicyConfiguration.requireUppercase = viper.GetBool(PasswordPolicyRequireUppercaseKey)
+	passwordPolicyConfiguration.requireDigit = viper.GetBool(PasswordPolicyRequireDigitKey)
+	passwordPolicyConfiguration.requireSymbol = viper.GetBool(PasswordPolicyRequireSymbolKey)
+	passwordPolicyConfiguration.forbidUserInfo = viper.GetBool(PasswordPolicyForbidUserInfoKey)
+	passwordPolicyConfiguration.historySize = viper.GetUint(PasswordPolicyHistorySizeKey)
+	passwordPolicyConfiguration.breachedEnabled = viper.GetBool(PasswordPolicyBreachedEnabledKey)
+	passwordPolicyConfiguration.breachedPath = viper.GetString(PasswordPolicyBreachedPathKey)
+	passwordPolicyConfiguration.refuseDefaultAdminPassword = viper.GetBool(PasswordPolicyRefuseDefaultAdminPasswordKey)
+}
+
+// Log the values provided by the configuration holder
+func (passwordPolicyConfiguration *passwordPolicyConfigurationImpl) Log(logger *zap.Logger) {
+	logger.Info("Password policy configuration",
+		zap.Uint("minLength", passwordPolicyConfiguration.minLength),
+		zap.Uint("maxLength", passwordPolicyConfiguration.maxLength),
+		zap.Bool("requireLowercase", passwordPolicyConfiguration.requireLowercase),
+		zap.Bool("requireUppercase", passwordPolicyConfiguration.requireUppercase),
+		zap.Bool("requireDigit", passwordPolicyConfiguration.requireDigit),
+		zap.Bool("requireSymbol", passwordPolicyConfiguration.requireSymbol),
+		zap.Bool("forbidUserInfo", passwordPolicyConfiguration.forbidUserInfo),
+		zap.Uint("historySize", passwordPolicyConfiguration.historySize),
+		zap.Bool("breachedEnabled", passwordPolicyConfiguration.breachedEnabled),
+		zap.String("breachedPath", passwordPolicyConfiguration.breachedPath),
+		zap.Bool("refuseDefaultAdminPassword", passwordPolicyConfiguration.refuseDefaultAdminPassword),
+	)
+}
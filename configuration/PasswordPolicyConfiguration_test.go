@@ -0,0 +1,60 @@
+package configuration_test
+
+import (
+	"testing"
+
+	"github.com/ditrit/badaas/configuration"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+var PasswordPolicyConfigurationString = `passwordPolicy:
+  minLength: 12
+  maxLength: 64
+  requireLowercase: true
+  requireUppercase: true
+  requireDigit: true
+  requireSymbol: true
+  forbidUserInfo: true
+  historySize: 5
+  breached:
+    enabled: true
+    path: /etc/badaas/breached.txt
+  refuseDefaultAdminPassword: true`
+
+func TestPasswordPolicyConfigurationNewPasswordPolicyConfiguration(t *testing.T) {
+	assert.NotNil(t, configuration.NewPasswordPolicyConfiguration(), "the contructor for PasswordPolicyConfiguration should not return a nil value")
+}
+
+func TestPasswordPolicyConfigurationGetters(t *testing.T) {
+	setupViperEnvironment(PasswordPolicyConfigurationString)
+	passwordPolicyConfiguration := configuration.NewPasswordPolicyConfiguration()
+	assert.Equal(t, uint(12), passwordPolicyConfiguration.GetMinLength())
+	assert.Equal(t, uint(64), passwordPolicyConfiguration.GetMaxLength())
+	assert.True(t, passwordPolicyConfiguration.GetRequireLowercase())
+	assert.True(t, passwordPolicyConfiguration.GetRequireUppercase())
+	assert.True(t, passwordPolicyConfiguration.GetRequireDigit())
+	assert.True(t, passwordPolicyConfiguration.GetRequireSymbol())
+	assert.True(t, passwordPolicyConfiguration.GetForbidUserInfo())
+	assert.Equal(t, uint(5), passwordPolicyConfiguration.GetHistorySize())
+	assert.True(t, passwordPolicyConfiguration.GetBreachedEnabled())
+	assert.Equal(t, "/etc/badaas/breached.txt", passwordPolicyConfiguration.GetBreachedPath())
+	assert.True(t, passwordPolicyConfiguration.GetRefuseDefaultAdminPassword())
+}
+
+func TestPasswordPolicyConfigurationLog(t *testing.T) {
+	setupViperEnvironment(PasswordPolicyConfigurationString)
+	// creating logger
+	observedZapCore, observedLogs := observer.New(zap.DebugLevel)
+	observedLogger := zap.New(observedZapCore)
+
+	passwordPolicyConfiguration := configuration.NewPasswordPolicyConfiguration()
+	passwordPolicyConfiguration.Log(observedLogger)
+
+	require.Equal(t, 1, observedLogs.Len())
+	log := observedLogs.All()[0]
+	assert.Equal(t, "Password policy configuration", log.Message)
+	assert.Len(t, log.Context, 11)
+}
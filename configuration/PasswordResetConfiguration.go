@@ -0,0 +1,48 @@
+package configuration
+
+import (
+	"time"
+
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// The config keys regarding the password reset settings
+const (
+	PasswordResetTokenDurationKey string = "passwordReset.tokenDuration"
+)
+
+// Hold the configuration values for the reset of the passwords
+type PasswordResetConfiguration interface {
+	ConfigurationHolder
+	GetTokenDuration() time.Duration
+}
+
+// Concrete implementation of the PasswordResetConfiguration interface
+type passwordResetConfigurationImpl struct {
+	tokenDuration time.Duration
+}
+
+// Instantiate a new configuration holder for the reset of the passwords
+func NewPasswordResetConfiguration() PasswordResetConfiguration {
+	passwordResetConfiguration := new(passwordResetConfigurationImpl)
+	passwordResetConfiguration.Reload()
+	return passwordResetConfiguration
+}
+
+// Return the duration during which a reset token can be used
+func (passwordResetConfiguration *passwordResetConfigurationImpl) GetTokenDuration() time.Duration {
+	return passwordResetConfiguration.tokenDuration
+}
+
+// Reload password reset configuration
+func (passwordResetConfiguration *passwordResetConfigurationImpl) Reload() {
+	passwordResetConfiguration.tokenDuration = intToSecond(int(viper.GetUint(PasswordResetTokenDurationKey)))
+}
+
+// Log the values provided by the configuration holder
+func (passwordResetConfiguration *passwordResetConfigurationImpl) Log(logger *zap.Logger) {
+	logger.Info("Password reset configuration",
+		zap.Duration("tokenDuration", passwordResetConfiguration.tokenDuration),
+	)
+}
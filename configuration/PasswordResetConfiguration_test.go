@@ -0,0 +1,43 @@
+package configuration_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ditrit/badaas/configuration"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+var PasswordResetConfigurationString = `passwordReset:
+  tokenDuration: 900 # 15 minutes`
+
+func TestPasswordResetConfigurationNewPasswordResetConfiguration(t *testing.T) {
+	assert.NotNil(t, configuration.NewPasswordResetConfiguration(), "the contructor for PasswordResetConfiguration should not return a nil value")
+}
+
+func TestPasswordResetConfigurationGetTokenDuration(t *testing.T) {
+	setupViperEnvironment(PasswordResetConfigurationString)
+	passwordResetConfiguration := configuration.NewPasswordResetConfiguration()
+	assert.Equal(t, 15*time.Minute, passwordResetConfiguration.GetTokenDuration())
+}
+
+func TestPasswordResetConfigurationLog(t *testing.T) {
+	setupViperEnvironment(PasswordResetConfigurationString)
+	// creating logger
+	observedZapCore, observedLogs := observer.New(zap.DebugLevel)
+	observedLogger := zap.New(observedZapCore)
+
+	passwordResetConfiguration := configuration.NewPasswordResetConfiguration()
+	passwordResetConfiguration.Log(observedLogger)
+
+	require.Equal(t, 1, observedLogs.Len())
+	log := observedLogs.All()[0]
+	assert.Equal(t, "Password reset configuration", log.Message)
+	assert.Equal(t, []zap.Field{
+		{Key: "tokenDuration", Type: zapcore.DurationType, Integer: int64(15 * time.Minute)},
+	}, log.Context)
+}
@@ -0,0 +1,71 @@
+package configuration
+
+import (
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// The config keys regarding the policy engine settings
+const (
+	PolicyExplainKey  string = "policy.explain"
+	PolicyPoliciesKey string = "policy.policies"
+)
+
+// A policy declared in the configuration
+type PolicyDefinition struct {
+	Name        string   `mapstructure:"name"`
+	Description string   `mapstructure:"description"`
+	Effect      string   `mapstructure:"effect"`
+	Actions     []string `mapstructure:"actions"`
+	Resources   []string `mapstructure:"resources"`
+	Condition   string   `mapstructure:"condition"`
+}
+
+// Hold the configuration values for the policy engine
+type PolicyConfiguration interface {
+	ConfigurationHolder
+	GetExplain() bool
+	GetPolicies() []PolicyDefinition
+}
+
+// Concrete implementation of the PolicyConfiguration interface
+type policyConfigurationImpl struct {
+	explain  bool
+	policies []PolicyDefinition
+}
+
+// Instantiate a new configuration holder for the policy engine
+func NewPolicyConfiguration() PolicyConfiguration {
+	policyConfiguration := new(policyConfigurationImpl)
+	policyConfiguration.Reload()
+	return policyConfiguration
+}
+
+// Return true if the policy engine must log why an access is denied
+func (policyConfiguration *policyConfigurationImpl) GetExplain() bool {
+	return policyConfiguration.explain
+}
+
+// Return the policies declared in the configuration
+func (policyConfiguration *policyConfigurationImpl) GetPolicies() []PolicyDefinition {
+	return policyConfiguration.policies
+}
+
+// Reload policy configuration
+func (policyConfiguration *policyConfigurationImpl) Reload() {
+	policyConfiguration.explain = viper.GetBool(PolicyExplainKey)
+	policies := []PolicyDefinition{}
+	err := viper.UnmarshalKey(PolicyPoliciesKey, &policies)
+	if err != nil {
+		panic(err)
+	}
+	policyConfiguration.policies = policies
+}
+
+// Log the values provided by the configuration holder
+func (policyConfiguration *policyConfigurationImpl) Log(logger *zap.Logger) {
+	logger.Info("Policy configuration",
+		zap.Bool("explain", policyConfiguration.explain),
+		zap.Int("policiesCount", len(policyConfiguration.policies)),
+	)
+}
@@ -0,0 +1,77 @@
+package configuration_test
+
+import (
+	"testing"
+
+	"github.com/ditrit/badaas/configuration"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+var PolicyConfigurationString = `policy:
+  explain: true
+  policies:
+    - name: owner-can-edit
+      effect: allow
+      actions: ["posts:update", "posts:delete"]
+      resources: ["post"]
+      condition: "subject.id == resource.owner"
+    - name: no-archived-edit
+      effect: deny
+      actions: ["posts:*"]
+      resources: ["post"]
+      condition: "resource.state == 'archived'"`
+
+func TestPolicyConfigurationNewPolicyConfiguration(t *testing.T) {
+	assert.NotNil(t, configuration.NewPolicyConfiguration(), "the contructor for PolicyConfiguration should not return a nil value")
+}
+
+func TestPolicyConfigurationGetExplain(t *testing.T) {
+	setupViperEnvironment(PolicyConfigurationString)
+	policyConfiguration := configuration.NewPolicyConfiguration()
+	assert.True(t, policyConfiguration.GetExplain())
+}
+
+func TestPolicyConfigurationGetPolicies(t *testing.T) {
+	setupViperEnvironment(PolicyConfigurationString)
+	policyConfiguration := configuration.NewPolicyConfiguration()
+	policies := policyConfiguration.GetPolicies()
+	require.Len(t, policies, 2)
+	assert.Equal(t, configuration.PolicyDefinition{
+		Name:      "owner-can-edit",
+		Effect:    "allow",
+		Actions:   []string{"posts:update", "posts:delete"},
+		Resources: []string{"post"},
+		Condition: "subject.id == resource.owner",
+	}, policies[0])
+	assert.Equal(t, "deny", policies[1].Effect)
+}
+
+func TestPolicyConfigurationNoPolicies(t *testing.T) {
+	setupViperEnvironment("")
+	policyConfiguration := configuration.NewPolicyConfiguration()
+	assert.False(t, policyConfiguration.GetExplain())
+	assert.Empty(t, policyConfiguration.GetPolicies())
+}
+
+func TestPolicyConfigurationLog(t *testing.T) {
+	setupViperEnvironment(PolicyConfigurationString)
+	// creating logger
+	observedZapCore, observedLogs := observer.New(zap.DebugLevel)
+	observedLogger := zap.New(observedZapCore)
+
+	policyConfiguration := configuration.NewPolicyConfiguration()
+	policyConfiguration.Log(observedLogger)
+
+	require.Equal(t, 1, observedLogs.Len())
+	log := observedLogs.All()[0]
+	assert.Equal(t, "Policy configuration", log.Message)
+	require.Len(t, log.Context, 2)
+	assert.ElementsMatch(t, []zap.Field{
+		{Key: "explain", Type: zapcore.BoolType, Integer: 1},
+		{Key: "policiesCount", Type: zapcore.Int64Type, Integer: 2},
+	}, log.Context)
+}
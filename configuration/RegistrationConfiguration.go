@@ -0,0 +1,84 @@
+package configuration
+
+import (
+	"strings"
+
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// The config keys regarding the self-service registration settings
+const (
+	RegistrationEnabledKey         string = "registration.enabled"
+	RegistrationAllowedDomainsKey  string = "registration.allowedDomains"
+	RegistrationInvitationCodesKey string = "registration.invitationCodes"
+	RegistrationAutoLoginKey       string = "registration.autoLogin"
+)
+
+// Hold the configuration values for the self-service registration
+type RegistrationConfiguration interface {
+	ConfigurationHolder
+	GetEnabled() bool
+	GetAllowedDomains() []string
+	GetInvitationCodes() []string
+	GetAutoLogin() bool
+}
+
+// Concrete implementation of the RegistrationConfiguration interface
+type registrationConfigurationImpl struct {
+	enabled         bool
+	allowedDomains  []string
+	invitationCodes []string
+	autoLogin       bool
+}
+
+// Instantiate a new configuration holder for the self-service registration
+func NewRegistrationConfiguration() RegistrationConfiguration {
+	registrationConfiguration := new(registrationConfigurationImpl)
+	registrationConfiguration.Reload()
+	return registrationConfiguration
+}
+
+// Return true if the users can create their account
+func (registrationConfiguration *registrationConfigurationImpl) GetEnabled() bool {
+	return registrationConfiguration.enabled
+}
+
+// Return the lowercased email domains allowed to register, any domain is allowed if empty
+func (registrationConfiguration *registrationConfigurationImpl) GetAllowedDomains() []string {
+	return registrationConfiguration.allowedDomains
+}
+
+// Return the invitation codes, one of them is required to register if not empty
+func (registrationConfiguration *registrationConfigurationImpl) GetInvitationCodes() []string {
+	return registrationConfiguration.invitationCodes
+}
+
+// Return true if the users are logged in once registered
+func (registrationConfiguration *registrationConfigurationImpl) GetAutoLogin() bool {
+	return registrationConfiguration.autoLogin
+}
+
+// Reload registration configuration
+func (registrationConfiguration *registrationConfigurationImpl) Reload() {
+	registrationConfiguration.enabled = viper.GetBool(RegistrationEnabledKey)
+	registrationConfiguration.allowedDomains = []string{}
+	for _, domain := range viper.GetStringSlice(RegistrationAllowedDomainsKey) {
+		registrationConfiguration.allowedDomains = append(
+			registrationConfiguration.allowedDomains,
+			strings.ToLower(strings.TrimSpace(domain)),
+		)
+	}
+	registrationConfiguration.invitationCodes = viper.GetStringSlice(RegistrationInvitationCodesKey)
+	registrationConfiguration.autoLogin = viper.GetBool(RegistrationAutoLoginKey)
+}
+
+// Log the values provided by the configuration holder
+func (registrationConfiguration *registrationConfigurationImpl) Log(logger *zap.Logger) {
+	logger.Info("Registration configuration",
+		zap.Bool("enabled", registrationConfiguration.enabled),
+		zap.Strings("allowedDomains", registrationConfiguration.allowedDomains),
+		zap.Bool("invitationRequired", len(registrationConfiguration.invitationCodes) > 0),
+		zap.Bool("autoLogin", registrationConfiguration.autoLogin),
+	)
+}
@@ -0,0 +1,57 @@
+package configuration_test
+
+import (
+	"testing"
+
+	"github.com/ditrit/badaas/configuration"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+var RegistrationConfigurationString = `registration:
+  enabled: true
+  allowedDomains: ["Example.com", " example.org"]
+  invitationCodes: ["welcome"]
+  autoLogin: true`
+
+func TestRegistrationConfigurationNewRegistrationConfiguration(t *testing.T) {
+	assert.NotNil(t, configuration.NewRegistrationConfiguration(), "the contructor for RegistrationConfiguration should not return a nil value")
+}
+
+func TestRegistrationConfigurationGetters(t *testing.T) {
+	setupViperEnvironment(RegistrationConfigurationString)
+	registrationConfiguration := configuration.NewRegistrationConfiguration()
+	assert.True(t, registrationConfiguration.GetEnabled())
+	assert.Equal(t, []string{"example.com", "example.org"}, registrationConfiguration.GetAllowedDomains())
+	assert.Equal(t, []string{"welcome"}, registrationConfiguration.GetInvitationCodes())
+	assert.True(t, registrationConfiguration.GetAutoLogin())
+}
+
+func TestRegistrationConfigurationDefaults(t *testing.T) {
+	setupViperEnvironment("")
+	registrationConfiguration := configuration.NewRegistrationConfiguration()
+	assert.False(t, registrationConfiguration.GetEnabled())
+	assert.Empty(t, registrationConfiguration.GetAllowedDomains())
+	assert.Empty(t, registrationConfiguration.GetInvitationCodes())
+	assert.False(t, registrationConfiguration.GetAutoLogin())
+}
+
+func TestRegistrationConfigurationLog(t *testing.T) {
+	setupViperEnvironment(RegistrationConfigurationString)
+	// creating logger
+	observedZapCore, observedLogs := observer.New(zap.DebugLevel)
+	observedLogger := zap.New(observedZapCore)
+
+	registrationConfiguration := configuration.NewRegistrationConfiguration()
+	registrationConfiguration.Log(observedLogger)
+
+	require.Equal(t, 1, observedLogs.Len())
+	log := observedLogs.All()[0]
+	assert.Equal(t, "Registration configuration", log.Message)
+	require.Len(t, log.Context, 4)
+	// the invitation codes are secret
+	assert.NotContains(t, log.ContextMap(), "invitationCodes")
+	assert.Equal(t, true, log.ContextMap()["invitationRequired"])
+}
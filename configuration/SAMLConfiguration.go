@@ -0,0 +1,185 @@
+package configuration
+
+import (
+	"time"
+
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// The config keys regarding the SAML settings
+const (
+	SAMLEntityIDKey             string = "saml.entityID"
+	SAMLAssertionConsumerURLKey string = "saml.assertionConsumerServiceURL"
+	SAMLCertificateKey          string = "saml.certificate"
+	SAMLPrivateKeyKey           string = "saml.privateKey"
+	SAMLIdPEntityIDKey          string = "saml.idp.entityID"
+	SAMLIdPSSOURLKey            string = "saml.idp.ssoURL"
+	SAMLIdPCertificateKey       string = "saml.idp.certificate"
+	SAMLEmailAttributeKey       string = "saml.attributes.email"
+	SAMLUsernameAttributeKey    string = "saml.attributes.username"
+	SAMLProvisioningKey         string = "saml.provisioning"
+	SAMLLinkByEmailKey          string = "saml.linkByEmail"
+	SAMLRequestDurationKey      string = "saml.requestDuration"
+	SAMLMaxClockSkewKey         string = "saml.maxClockSkew"
+	SAMLLoginRedirectURLKey     string = "saml.loginRedirectURL"
+)
+
+// Hold the configuration values for the SAML login
+type SAMLConfiguration interface {
+	ConfigurationHolder
+	// Return true if the service provider is configured
+	IsEnabled() bool
+	GetEntityID() string
+	GetAssertionConsumerServiceURL() string
+	GetCertificate() string
+	GetPrivateKey() string
+	GetIdPEntityID() string
+	GetIdPSSOURL() string
+	GetIdPCertificate() string
+	GetEmailAttribute() string
+	GetUsernameAttribute() string
+	GetProvisioning() bool
+	GetLinkByEmail() bool
+	GetRequestDuration() time.Duration
+	GetMaxClockSkew() time.Duration
+	GetLoginRedirectURL() string
+}
+
+// Concrete implementation of the SAMLConfiguration interface
+type samlConfigurationImpl struct {
+	entityID                    string
+	assertionConsumerServiceURL string
+	certificate                 string
+	privateKey                  string
+	idpEntityID                 string
+	idpSSOURL                   string
+	idpCertificate              string
+	emailAttribute              string
+	usernameAttribute           string
+	provisioning                bool
+	linkByEmail                 bool
+	requestDuration             time.Duration
+	maxClockSkew                time.Duration
+	loginRedirectURL            string
+}
+
+// Instantiate a new configuration holder for the SAML login
+func NewSAMLConfiguration() SAMLConfiguration {
+	samlConfiguration := new(samlConfigurationImpl)
+	samlConfiguration.Reload()
+	return samlConfiguration
+}
+
+// Return true if the service provider is configured
+func (samlConfiguration *samlConfigurationImpl) IsEnabled() bool {
+	return samlConfiguration.entityID != ""
+}
+
+// Return the entity id of the service provider
+func (samlConfiguration *samlConfigurationImpl) GetEntityID() string {
+	return samlConfiguration.entityID
+}
+
+// Return the url the identity provider posts its responses to
+func (samlConfiguration *samlConfigurationImpl) GetAssertionConsumerServiceURL() string {
+	return samlConfiguration.assertionConsumerServiceURL
+}
+
+// Return the path of the PEM encoded certificate of the service provider
+func (samlConfiguration *samlConfigurationImpl) GetCertificate() string {
+	return samlConfiguration.certificate
+}
+
+// Return the path of the PEM encoded RSA key signing the AuthnRequests
+func (samlConfiguration *samlConfigurationImpl) GetPrivateKey() string {
+	return samlConfiguration.privateKey
+}
+
+// Return the entity id of the identity provider
+func (samlConfiguration *samlConfigurationImpl) GetIdPEntityID() string {
+	return samlConfiguration.idpEntityID
+}
+
+// Return the url of the single sign on service of the identity provider
+func (samlConfiguration *samlConfigurationImpl) GetIdPSSOURL() string {
+	return samlConfiguration.idpSSOURL
+}
+
+// Return the path of the PEM encoded certificates of the identity provider
+func (samlConfiguration *samlConfigurationImpl) GetIdPCertificate() string {
+	return samlConfiguration.idpCertificate
+}
+
+// Return the name of the attribute holding the email of the user
+func (samlConfiguration *samlConfigurationImpl) GetEmailAttribute() string {
+	return samlConfiguration.emailAttribute
+}
+
+// Return the name of the attribute holding the username of the user
+func (samlConfiguration *samlConfigurationImpl) GetUsernameAttribute() string {
+	return samlConfiguration.usernameAttribute
+}
+
+// Return true if a user is created for the unknown identities
+func (samlConfiguration *samlConfigurationImpl) GetProvisioning() bool {
+	return samlConfiguration.provisioning
+}
+
+// Return true if an unknown identity is linked to the user with the same email
+func (samlConfiguration *samlConfigurationImpl) GetLinkByEmail() bool {
+	return samlConfiguration.linkByEmail
+}
+
+// Return the duration during which the user can log in on the identity provider
+func (samlConfiguration *samlConfigurationImpl) GetRequestDuration() time.Duration {
+	return samlConfiguration.requestDuration
+}
+
+// Return the tolerated difference between the clocks of the providers
+func (samlConfiguration *samlConfigurationImpl) GetMaxClockSkew() time.Duration {
+	return samlConfiguration.maxClockSkew
+}
+
+// Return the url the user is redirected to once logged in, the user is described in JSON if empty
+func (samlConfiguration *samlConfigurationImpl) GetLoginRedirectURL() string {
+	return samlConfiguration.loginRedirectURL
+}
+
+// Reload SAML configuration
+func (samlConfiguration *samlConfigurationImpl) Reload() {
+	samlConfiguration.entityID = viper.GetString(SAMLEntityIDKey)
+	samlConfiguration.assertionConsumerServiceURL = viper.GetString(SAMLAssertionConsumerURLKey)
+	samlConfiguration.certificate = viper.GetString(SAMLCertificateKey)
+	samlConfiguration.privateKey = viper.GetString(SAMLPrivateKeyKey)
+	samlConfiguration.idpEntityID = viper.GetString(SAMLIdPEntityIDKey)
+	samlConfiguration.idpSSOURL = viper.GetString(SAMLIdPSSOURLKey)
+	samlConfiguration.idpCertificate = viper.GetString(SAMLIdPCertificateKey)
+	samlConfiguration.emailAttribute = viper.GetString(SAMLEmailAttributeKey)
+	samlConfiguration.usernameAttribute = viper.GetString(SAMLUsernameAttributeKey)
+	samlConfiguration.provisioning = viper.GetBool(SAMLProvisioningKey)
+	samlConfiguration.linkByEmail = viper.GetBool(SAMLLinkByEmailKey)
+	samlConfiguration.requestDuration = intToSecond(int(viper.GetUint(SAMLRequestDurationKey)))
+	samlConfiguration.maxClockSkew = intToSecond(int(viper.GetUint(SAMLMaxClockSkewKey)))
+	samlConfiguration.loginRedirectURL = viper.GetString(SAMLLoginRedirectURLKey)
+}
+
+// Log the values provided by the configuration holder
+func (samlConfiguration *samlConfigurationImpl) Log(logger *zap.Logger) {
+	logger.Info("SAML configuration",
+		zap.String("entityID", samlConfiguration.entityID),
+		zap.String("assertionConsumerServiceURL", samlConfiguration.assertionConsumerServiceURL),
+		zap.String("certificate", samlConfiguration.certificate),
+		zap.String("privateKey", samlConfiguration.privateKey),
+		zap.String("idpEntityID", samlConfiguration.idpEntityID),
+		zap.String("idpSSOURL", samlConfiguration.idpSSOURL),
+		zap.String("idpCertificate", samlConfiguration.idpCertificate),
+		zap.String("emailAttribute", samlConfiguration.emailAttribute),
+		zap.String("usernameAttribute", samlConfiguration.usernameAttribute),
+		zap.Bool("provisioning", samlConfiguration.provisioning),
+		zap.Bool("linkByEmail", samlConfiguration.linkByEmail),
+		zap.Duration("requestDuration", samlConfiguration.requestDuration),
+		zap.Duration("maxClockSkew", samlConfiguration.maxClockSkew),
+		zap.String("loginRedirectURL", samlConfiguration.loginRedirectURL),
+	)
+}
@@ -0,0 +1,73 @@
+package configuration_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ditrit/badaas/configuration"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+var SAMLConfigurationString = `saml:
+  entityID: https://badaas.example.com/saml/metadata
+  assertionConsumerServiceURL: https://badaas.example.com/login/saml/acs
+  certificate: /etc/badaas/saml.crt
+  privateKey: /etc/badaas/saml.key
+  idp:
+    entityID: https://idp.example.com
+    ssoURL: https://idp.example.com/sso
+    certificate: /etc/badaas/idp.crt
+  attributes:
+    email: mail
+    username: uid
+  provisioning: false
+  linkByEmail: true
+  requestDuration: 600
+  maxClockSkew: 60
+  loginRedirectURL: https://app.example.com`
+
+func TestSAMLConfigurationNewSAMLConfiguration(t *testing.T) {
+	assert.NotNil(t, configuration.NewSAMLConfiguration(), "the contructor for SAMLConfiguration should not return a nil value")
+}
+
+func TestSAMLConfigurationGetters(t *testing.T) {
+	setupViperEnvironment(SAMLConfigurationString)
+	samlConfiguration := configuration.NewSAMLConfiguration()
+	assert.True(t, samlConfiguration.IsEnabled())
+	assert.Equal(t, "https://badaas.example.com/saml/metadata", samlConfiguration.GetEntityID())
+	assert.Equal(t, "https://badaas.example.com/login/saml/acs", samlConfiguration.GetAssertionConsumerServiceURL())
+	assert.Equal(t, "/etc/badaas/saml.crt", samlConfiguration.GetCertificate())
+	assert.Equal(t, "/etc/badaas/saml.key", samlConfiguration.GetPrivateKey())
+	assert.Equal(t, "https://idp.example.com", samlConfiguration.GetIdPEntityID())
+	assert.Equal(t, "https://idp.example.com/sso", samlConfiguration.GetIdPSSOURL())
+	assert.Equal(t, "/etc/badaas/idp.crt", samlConfiguration.GetIdPCertificate())
+	assert.Equal(t, "mail", samlConfiguration.GetEmailAttribute())
+	assert.Equal(t, "uid", samlConfiguration.GetUsernameAttribute())
+	assert.False(t, samlConfiguration.GetProvisioning())
+	assert.True(t, samlConfiguration.GetLinkByEmail())
+	assert.Equal(t, 10*time.Minute, samlConfiguration.GetRequestDuration())
+	assert.Equal(t, time.Minute, samlConfiguration.GetMaxClockSkew())
+	assert.Equal(t, "https://app.example.com", samlConfiguration.GetLoginRedirectURL())
+}
+
+func TestSAMLConfigurationLog(t *testing.T) {
+	setupViperEnvironment(SAMLConfigurationString)
+	// creating logger
+	observedZapCore, observedLogs := observer.New(zap.DebugLevel)
+	observedLogger := zap.New(observedZapCore)
+
+	samlConfiguration := configuration.NewSAMLConfiguration()
+	samlConfiguration.Log(observedLogger)
+
+	require.Equal(t, 1, observedLogs.Len())
+	log := observedLogs.All()[0]
+	assert.Equal(t, "SAML configuration", log.Message)
+	require.Len(t, log.Context, 14)
+	assert.Equal(t, zap.String("entityID", "https://badaas.example.com/saml/metadata"), log.Context[0])
+	assert.Equal(t, zap.Bool("provisioning", false), log.Context[9])
+	assert.Equal(t, zap.Bool("linkByEmail", true), log.Context[10])
+	assert.Equal(t, zap.Duration("maxClockSkew", time.Minute), log.Context[12])
+}
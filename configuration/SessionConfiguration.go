@@ -12,14 +12,64 @@ const (
 	SessionDurationKey     string = "session.duration"
 	SessionPullIntervalKey string = "session.pullInterval"
 	SessionRollIntervalKey string = "session.rollDuration"
+	SessionModeKey         string = "session.mode"
+	SessionBindingKey      string = "session.binding"
+	SessionSigningKeysKey  string = "session.signingKeys"
+
+	SessionRevocationPullIntervalKey string = "session.revocationPullInterval"
+
+	SessionJWTAlgorithmKey           string = "session.jwt.algorithm"
+	SessionJWTAccessTokenDurationKey string = "session.jwt.accessTokenDuration"
+	SessionJWTKeysKey                string = "session.jwt.keys"
+)
+
+// The session modes
+const (
+	// The sessions are stored in the database and cached by every node
+	SessionModeDatabase string = "database"
+	// The requests are authenticated with signed JWT access tokens, only the refresh tokens are stored
+	SessionModeJWT string = "jwt"
+)
+
+// The bindings of the sessions to the client that created them
+const (
+	// The sessions can be used from any client
+	SessionBindingNone string = "none"
+	// The sessions are refused from another browser or operating system
+	SessionBindingDevice string = "device"
+	// The sessions are also refused from another network
+	SessionBindingDeviceAndNetwork string = "deviceAndNetwork"
 )
 
+// A key signing the session tokens
+type SessionSigningKey struct {
+	// The id of the key, written in the signed tokens
+	ID string `mapstructure:"id"`
+	// The secret of the HMAC, at least 32 bytes
+	Secret string `mapstructure:"secret"`
+}
+
+// A key signing the JWT access tokens
+type SessionJWTKey struct {
+	// The kid of the key, published in the JWKS
+	ID string `mapstructure:"id"`
+	// The path of the PEM encoded private key
+	PrivateKey string `mapstructure:"privateKey"`
+}
+
 // Hold the configuration values to handle the sessions
 type SessionConfiguration interface {
 	ConfigurationHolder
 	GetSessionDuration() time.Duration
 	GetPullInterval() time.Duration
+	GetRevocationPullInterval() time.Duration
 	GetRollDuration() time.Duration
+	GetMode() string
+	GetBinding() string
+	GetSigningKeys() []SessionSigningKey
+	GetJWTAlgorithm() string
+	GetJWTAccessTokenDuration() time.Duration
+	GetJWTKeys() []SessionJWTKey
 }
 
 // Concrete implementation of the SessionConfiguration interface
@@ -27,6 +77,15 @@ type sessionConfigurationImpl struct {
 	sessionDuration time.Duration
 	pullInterval    time.Duration
 	rollDuration    time.Duration
+	mode            string
+	binding         string
+	signingKeys     []SessionSigningKey
+
+	revocationPullInterval time.Duration
+
+	jwtAlgorithm           string
+	jwtAccessTokenDuration time.Duration
+	jwtKeys                []SessionJWTKey
 }
 
 // Instantiate a new configuration holder for the session management
@@ -46,16 +105,68 @@ func (sessionConfiguration *sessionConfigurationImpl) GetPullInterval() time.Dur
 	return sessionConfiguration.pullInterval
 }
 
+// Return the interval between the pulls of the sessions changed or revoked by the other nodes
+func (sessionConfiguration *sessionConfigurationImpl) GetRevocationPullInterval() time.Duration {
+	return sessionConfiguration.revocationPullInterval
+}
+
 // Return the roll interval
 func (sessionConfiguration *sessionConfigurationImpl) GetRollDuration() time.Duration {
 	return sessionConfiguration.rollDuration
 }
 
+// Return the session mode, database or jwt
+func (sessionConfiguration *sessionConfigurationImpl) GetMode() string {
+	return sessionConfiguration.mode
+}
+
+// Return the binding of the sessions to their client
+func (sessionConfiguration *sessionConfigurationImpl) GetBinding() string {
+	return sessionConfiguration.binding
+}
+
+// Return the keys signing the session tokens, the first one signs the new tokens
+func (sessionConfiguration *sessionConfigurationImpl) GetSigningKeys() []SessionSigningKey {
+	return sessionConfiguration.signingKeys
+}
+
+// Return the algorithm signing the JWT access tokens
+func (sessionConfiguration *sessionConfigurationImpl) GetJWTAlgorithm() string {
+	return sessionConfiguration.jwtAlgorithm
+}
+
+// Return the duration of validity of the JWT access tokens
+func (sessionConfiguration *sessionConfigurationImpl) GetJWTAccessTokenDuration() time.Duration {
+	return sessionConfiguration.jwtAccessTokenDuration
+}
+
+// Return the keys signing the JWT access tokens, the first one signs the new tokens
+func (sessionConfiguration *sessionConfigurationImpl) GetJWTKeys() []SessionJWTKey {
+	return sessionConfiguration.jwtKeys
+}
+
 // Reload session configuration
 func (sessionConfiguration *sessionConfigurationImpl) Reload() {
 	sessionConfiguration.sessionDuration = intToSecond(int(viper.GetUint(SessionDurationKey)))
 	sessionConfiguration.pullInterval = intToSecond(int(viper.GetUint(SessionPullIntervalKey)))
+	sessionConfiguration.revocationPullInterval = intToSecond(int(viper.GetUint(SessionRevocationPullIntervalKey)))
 	sessionConfiguration.rollDuration = intToSecond(int(viper.GetUint(SessionRollIntervalKey)))
+	sessionConfiguration.mode = viper.GetString(SessionModeKey)
+	sessionConfiguration.binding = viper.GetString(SessionBindingKey)
+	signingKeys := []SessionSigningKey{}
+	err := viper.UnmarshalKey(SessionSigningKeysKey, &signingKeys)
+	if err != nil {
+		panic(err)
+	}
+	sessionConfiguration.signingKeys = signingKeys
+	sessionConfiguration.jwtAlgorithm = viper.GetString(SessionJWTAlgorithmKey)
+	sessionConfiguration.jwtAccessTokenDuration = intToSecond(int(viper.GetUint(SessionJWTAccessTokenDurationKey)))
+	jwtKeys := []SessionJWTKey{}
+	err = viper.UnmarshalKey(SessionJWTKeysKey, &jwtKeys)
+	if err != nil {
+		panic(err)
+	}
+	sessionConfiguration.jwtKeys = jwtKeys
 }
 
 // Log the values provided by the configuration holder
@@ -63,6 +174,31 @@ func (sessionConfiguration *sessionConfigurationImpl) Log(logger *zap.Logger) {
 	logger.Info("Session configuration",
 		zap.Duration("sessionDuration", sessionConfiguration.sessionDuration),
 		zap.Duration("pullInterval", sessionConfiguration.pullInterval),
+		zap.Duration("revocationPullInterval", sessionConfiguration.revocationPullInterval),
 		zap.Duration("rollDuration", sessionConfiguration.rollDuration),
+		zap.String("mode", sessionConfiguration.mode),
+		zap.String("binding", sessionConfiguration.binding),
+		zap.Strings("signingKeyIDs", sessionConfiguration.getSigningKeyIDs()),
+		zap.String("jwtAlgorithm", sessionConfiguration.jwtAlgorithm),
+		zap.Duration("jwtAccessTokenDuration", sessionConfiguration.jwtAccessTokenDuration),
+		zap.Strings("jwtKeyIDs", sessionConfiguration.getJWTKeyIDs()),
 	)
 }
+
+// Return the ids of the signing keys, the secrets are not logged
+func (sessionConfiguration *sessionConfigurationImpl) getSigningKeyIDs() []string {
+	keyIDs := make([]string, 0, len(sessionConfiguration.signingKeys))
+	for _, key := range sessionConfiguration.signingKeys {
+		keyIDs = append(keyIDs, key.ID)
+	}
+	return keyIDs
+}
+
+// Return the ids of the JWT keys, the paths of the keys are not logged
+func (sessionConfiguration *sessionConfigurationImpl) getJWTKeyIDs() []string {
+	keyIDs := make([]string, 0, len(sessionConfiguration.jwtKeys))
+	for _, key := range sessionConfiguration.jwtKeys {
+		keyIDs = append(keyIDs, key.ID)
+	}
+	return keyIDs
+}
@@ -15,7 +15,21 @@ import (
 var SessionConfigurationString = `session:
   duration: 3600 # one hour
   pullInterval: 30 # 30 seconds
-  rollDuration: 10 # 10 seconds`
+  revocationPullInterval: 2
+  rollDuration: 10 # 10 seconds
+  mode: jwt
+  binding: deviceAndNetwork
+  signingKeys:
+    - id: "2024"
+      secret: "a secret of at least thirty two bytes"
+  jwt:
+    algorithm: EdDSA
+    accessTokenDuration: 300
+    keys:
+      - id: "2024-01"
+        privateKey: "/etc/badaas/jwt-2024-01.pem"
+      - id: "2023-12"
+        privateKey: "/etc/badaas/jwt-2023-12.pem"`
 
 func TestSessionConfigurationNewSessionConfiguration(t *testing.T) {
 	assert.NotNil(t, configuration.NewSessionConfiguration(), "the contructor for PaginationConfiguration should not return a nil value")
@@ -33,12 +47,44 @@ func TestSessionConfigurationGetPullIntervall(t *testing.T) {
 	assert.Equal(t, time.Duration(time.Second*30), SessionConfiguration.GetPullInterval())
 }
 
+func TestSessionConfigurationGetRevocationPullInterval(t *testing.T) {
+	setupViperEnvironment(SessionConfigurationString)
+	SessionConfiguration := configuration.NewSessionConfiguration()
+	assert.Equal(t, 2*time.Second, SessionConfiguration.GetRevocationPullInterval())
+}
+
 func TestSessionConfigurationGetRollIntervall(t *testing.T) {
 	setupViperEnvironment(SessionConfigurationString)
 	SessionConfiguration := configuration.NewSessionConfiguration()
 	assert.Equal(t, time.Duration(time.Second*10), SessionConfiguration.GetRollDuration())
 }
 
+func TestSessionConfigurationGetBinding(t *testing.T) {
+	setupViperEnvironment(SessionConfigurationString)
+	SessionConfiguration := configuration.NewSessionConfiguration()
+	assert.Equal(t, configuration.SessionBindingDeviceAndNetwork, SessionConfiguration.GetBinding())
+}
+
+func TestSessionConfigurationGetSigningKeys(t *testing.T) {
+	setupViperEnvironment(SessionConfigurationString)
+	SessionConfiguration := configuration.NewSessionConfiguration()
+	assert.Equal(t, []configuration.SessionSigningKey{
+		{ID: "2024", Secret: "a secret of at least thirty two bytes"},
+	}, SessionConfiguration.GetSigningKeys())
+}
+
+func TestSessionConfigurationJWT(t *testing.T) {
+	setupViperEnvironment(SessionConfigurationString)
+	SessionConfiguration := configuration.NewSessionConfiguration()
+	assert.Equal(t, configuration.SessionModeJWT, SessionConfiguration.GetMode())
+	assert.Equal(t, "EdDSA", SessionConfiguration.GetJWTAlgorithm())
+	assert.Equal(t, 5*time.Minute, SessionConfiguration.GetJWTAccessTokenDuration())
+	assert.Equal(t, []configuration.SessionJWTKey{
+		{ID: "2024-01", PrivateKey: "/etc/badaas/jwt-2024-01.pem"},
+		{ID: "2023-12", PrivateKey: "/etc/badaas/jwt-2023-12.pem"},
+	}, SessionConfiguration.GetJWTKeys())
+}
+
 func TestSessionConfigurationLog(t *testing.T) {
 	setupViperEnvironment(SessionConfigurationString)
 	// creating logger
@@ -51,10 +97,17 @@ func TestSessionConfigurationLog(t *testing.T) {
 	require.Equal(t, 1, observedLogs.Len())
 	log := observedLogs.All()[0]
 	assert.Equal(t, "Session configuration", log.Message)
-	require.Len(t, log.Context, 3)
+	require.Len(t, log.Context, 10)
 	assert.ElementsMatch(t, []zap.Field{
 		{Key: "sessionDuration", Type: zapcore.DurationType, Integer: int64(time.Duration(time.Hour))},
 		{Key: "pullInterval", Type: zapcore.DurationType, Integer: int64(time.Duration(time.Second * 30))},
+		{Key: "revocationPullInterval", Type: zapcore.DurationType, Integer: int64(2 * time.Second)},
 		{Key: "rollDuration", Type: zapcore.DurationType, Integer: int64(time.Duration(time.Second * 10))},
+		{Key: "mode", Type: zapcore.StringType, String: "jwt"},
+		{Key: "binding", Type: zapcore.StringType, String: "deviceAndNetwork"},
+		zap.Strings("signingKeyIDs", []string{"2024"}),
+		{Key: "jwtAlgorithm", Type: zapcore.StringType, String: "EdDSA"},
+		{Key: "jwtAccessTokenDuration", Type: zapcore.DurationType, Integer: int64(5 * time.Minute)},
+		zap.Strings("jwtKeyIDs", []string{"2024-01", "2023-12"}),
 	}, log.Context)
 }
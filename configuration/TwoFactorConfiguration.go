@@ -0,0 +1,88 @@
+package configuration
+
+import (
+	"time"
+
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// The config keys regarding the two-factor authentication settings
+const (
+	TwoFactorIssuerKey            string = "twoFactor.issuer"
+	TwoFactorRecoveryCodesKey     string = "twoFactor.recoveryCodes"
+	TwoFactorChallengeDurationKey string = "twoFactor.challengeDuration"
+	TwoFactorChallengeAttemptsKey string = "twoFactor.challengeAttempts"
+	TwoFactorRequiredRolesKey     string = "twoFactor.requiredRoles"
+)
+
+// Hold the configuration values for the two-factor authentication
+type TwoFactorConfiguration interface {
+	ConfigurationHolder
+	GetIssuer() string
+	GetRecoveryCodes() uint
+	GetChallengeDuration() time.Duration
+	GetChallengeAttempts() uint
+	GetRequiredRoles() []string
+}
+
+// Concrete implementation of the TwoFactorConfiguration interface
+type twoFactorConfigurationImpl struct {
+	issuer            string
+	recoveryCodes     uint
+	challengeDuration time.Duration
+	challengeAttempts uint
+	requiredRoles     []string
+}
+
+// Instantiate a new configuration holder for the two-factor authentication
+func NewTwoFactorConfiguration() TwoFactorConfiguration {
+	twoFactorConfiguration := new(twoFactorConfigurationImpl)
+	twoFactorConfiguration.Reload()
+	return twoFactorConfiguration
+}
+
+// Return the issuer displayed by the authenticator apps
+func (twoFactorConfiguration *twoFactorConfigurationImpl) GetIssuer() string {
+	return twoFactorConfiguration.issuer
+}
+
+// Return the number of recovery codes generated for a user
+func (twoFactorConfiguration *twoFactorConfigurationImpl) GetRecoveryCodes() uint {
+	return twoFactorConfiguration.recoveryCodes
+}
+
+// Return the duration during which the second factor can be given after the password
+func (twoFactorConfiguration *twoFactorConfigurationImpl) GetChallengeDuration() time.Duration {
+	return twoFactorConfiguration.challengeDuration
+}
+
+// Return the number of wrong codes after which a login challenge can't be used anymore
+func (twoFactorConfiguration *twoFactorConfigurationImpl) GetChallengeAttempts() uint {
+	return twoFactorConfiguration.challengeAttempts
+}
+
+// Return the names of the roles whose users must use a second factor
+func (twoFactorConfiguration *twoFactorConfigurationImpl) GetRequiredRoles() []string {
+	return twoFactorConfiguration.requiredRoles
+}
+
+// Reload two-factor configuration
+func (twoFactorConfiguration *twoFactorConfigurationImpl) Reload() {
+	twoFactorConfiguration.issuer = viper.GetString(TwoFactorIssuerKey)
+	twoFactorConfiguration.recoveryCodes = viper.GetUint(TwoFactorRecoveryCodesKey)
+	twoFactorConfiguration.challengeDuration = intToSecond(int(viper.GetUint(TwoFactorChallengeDurationKey)))
+	twoFactorConfiguration.challengeAttempts = viper.GetUint(TwoFactorChallengeAttemptsKey)
+	twoFactorConfiguration.requiredRoles = viper.GetStringSlice(TwoFactorRequiredRolesKey)
+}
+
+// Log the values provided by the configuration holder
+func (twoFactorConfiguration *twoFactorConfigurationImpl) Log(logger *zap.Logger) {
+	logger.Info("Two-factor configuration",
+		zap.String("issuer", twoFactorConfiguration.issuer),
+		zap.Uint("recoveryCodes", twoFactorConfiguration.recoveryCodes),
+		zap.Duration("challengeDuration", twoFactorConfiguration.challengeDuration),
+		zap.Uint("challengeAttempts", twoFactorConfiguration.challengeAttempts),
+		zap.Strings("requiredRoles", twoFactorConfiguration.requiredRoles),
+	)
+}
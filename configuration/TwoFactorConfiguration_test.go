@@ -0,0 +1,55 @@
+package configuration_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ditrit/badaas/configuration"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+var TwoFactorConfigurationString = `twoFactor:
+  issuer: "My app"
+  recoveryCodes: 8
+  challengeDuration: 300
+  challengeAttempts: 5
+  requiredRoles:
+    - super-admin
+    - operator`
+
+func TestTwoFactorConfigurationNewTwoFactorConfiguration(t *testing.T) {
+	assert.NotNil(t, configuration.NewTwoFactorConfiguration(), "the contructor for TwoFactorConfiguration should not return a nil value")
+}
+
+func TestTwoFactorConfigurationGetters(t *testing.T) {
+	setupViperEnvironment(TwoFactorConfigurationString)
+	twoFactorConfiguration := configuration.NewTwoFactorConfiguration()
+	assert.Equal(t, "My app", twoFactorConfiguration.GetIssuer())
+	assert.Equal(t, uint(8), twoFactorConfiguration.GetRecoveryCodes())
+	assert.Equal(t, 5*time.Minute, twoFactorConfiguration.GetChallengeDuration())
+	assert.Equal(t, uint(5), twoFactorConfiguration.GetChallengeAttempts())
+	assert.Equal(t, []string{"super-admin", "operator"}, twoFactorConfiguration.GetRequiredRoles())
+}
+
+func TestTwoFactorConfigurationLog(t *testing.T) {
+	setupViperEnvironment(TwoFactorConfigurationString)
+	// creating logger
+	observedZapCore, observedLogs := observer.New(zap.DebugLevel)
+	observedLogger := zap.New(observedZapCore)
+
+	twoFactorConfiguration := configuration.NewTwoFactorConfiguration()
+	twoFactorConfiguration.Log(observedLogger)
+
+	require.Equal(t, 1, observedLogs.Len())
+	log := observedLogs.All()[0]
+	assert.Equal(t, "Two-factor configuration", log.Message)
+	require.Len(t, log.Context, 5)
+	assert.Equal(t, zap.String("issuer", "My app"), log.Context[0])
+	assert.Equal(t, zap.Uint("recoveryCodes", 8), log.Context[1])
+	assert.Equal(t, zap.Duration("challengeDuration", 5*time.Minute), log.Context[2])
+	assert.Equal(t, zap.Uint("challengeAttempts", 5), log.Context[3])
+	assert.Equal(t, "requiredRoles", log.Context[4].Key)
+}
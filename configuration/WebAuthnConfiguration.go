@@ -0,0 +1,92 @@
+package configuration
+
+import (
+	"time"
+
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// The config keys regarding the WebAuthn settings
+const (
+	WebAuthnRelyingPartyIDKey   string = "webauthn.relyingPartyID"
+	WebAuthnRelyingPartyNameKey string = "webauthn.relyingPartyName"
+	WebAuthnOriginsKey          string = "webauthn.origins"
+	WebAuthnTimeoutKey          string = "webauthn.timeout"
+	WebAuthnUserVerificationKey string = "webauthn.userVerification"
+)
+
+// Hold the configuration values for the WebAuthn authentication
+type WebAuthnConfiguration interface {
+	ConfigurationHolder
+	GetRelyingPartyID() string
+	GetRelyingPartyName() string
+	GetOrigins() []string
+	GetTimeout() time.Duration
+	GetUserVerification() string
+}
+
+// Concrete implementation of the WebAuthnConfiguration interface
+type webAuthnConfigurationImpl struct {
+	relyingPartyID   string
+	relyingPartyName string
+	origins          []string
+	timeout          time.Duration
+	userVerification string
+}
+
+// Instantiate a new configuration holder for the WebAuthn authentication
+func NewWebAuthnConfiguration() WebAuthnConfiguration {
+	webAuthnConfiguration := new(webAuthnConfigurationImpl)
+	webAuthnConfiguration.Reload()
+	return webAuthnConfiguration
+}
+
+// Return the domain the credentials are bound to
+func (webAuthnConfiguration *webAuthnConfigurationImpl) GetRelyingPartyID() string {
+	return webAuthnConfiguration.relyingPartyID
+}
+
+// Return the name displayed by the authenticators
+func (webAuthnConfiguration *webAuthnConfigurationImpl) GetRelyingPartyName() string {
+	return webAuthnConfiguration.relyingPartyName
+}
+
+// Return the origins of the pages allowed to run the ceremonies,
+// https://<relying party id> if none is configured
+func (webAuthnConfiguration *webAuthnConfigurationImpl) GetOrigins() []string {
+	return webAuthnConfiguration.origins
+}
+
+// Return the duration during which a ceremony can be completed
+func (webAuthnConfiguration *webAuthnConfigurationImpl) GetTimeout() time.Duration {
+	return webAuthnConfiguration.timeout
+}
+
+// Return the user verification asked for the registrations and the second factors (required, preferred or discouraged)
+func (webAuthnConfiguration *webAuthnConfigurationImpl) GetUserVerification() string {
+	return webAuthnConfiguration.userVerification
+}
+
+// Reload WebAuthn configuration
+func (webAuthnConfiguration *webAuthnConfigurationImpl) Reload() {
+	webAuthnConfiguration.relyingPartyID = viper.GetString(WebAuthnRelyingPartyIDKey)
+	webAuthnConfiguration.relyingPartyName = viper.GetString(WebAuthnRelyingPartyNameKey)
+	webAuthnConfiguration.origins = viper.GetStringSlice(WebAuthnOriginsKey)
+	if len(webAuthnConfiguration.origins) == 0 {
+		webAuthnConfiguration.origins = []string{"https://" + webAuthnConfiguration.relyingPartyID}
+	}
+	webAuthnConfiguration.timeout = intToSecond(int(viper.GetUint(WebAuthnTimeoutKey)))
+	webAuthnConfiguration.userVerification = viper.GetString(WebAuthnUserVerificationKey)
+}
+
+// Log the values provided by the configuration holder
+func (webAuthnConfiguration *webAuthnConfigurationImpl) Log(logger *zap.Logger) {
+	logger.Info("WebAuthn configuration",
+		zap.String("relyingPartyID", webAuthnConfiguration.relyingPartyID),
+		zap.String("relyingPartyName", webAuthnConfiguration.relyingPartyName),
+		zap.Strings("origins", webAuthnConfiguration.origins),
+		zap.Duration("timeout", webAuthnConfiguration.timeout),
+		zap.String("userVerification", webAuthnConfiguration.userVerification),
+	)
+}
@@ -0,0 +1,62 @@
+package configuration_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ditrit/badaas/configuration"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+var WebAuthnConfigurationString = `webauthn:
+  relyingPartyID: badaas.example.com
+  relyingPartyName: "My app"
+  origins:
+    - https://badaas.example.com
+    - https://app.badaas.example.com
+  timeout: 120
+  userVerification: required`
+
+func TestWebAuthnConfigurationNewWebAuthnConfiguration(t *testing.T) {
+	assert.NotNil(t, configuration.NewWebAuthnConfiguration(), "the contructor for WebAuthnConfiguration should not return a nil value")
+}
+
+func TestWebAuthnConfigurationGetters(t *testing.T) {
+	setupViperEnvironment(WebAuthnConfigurationString)
+	webAuthnConfiguration := configuration.NewWebAuthnConfiguration()
+	assert.Equal(t, "badaas.example.com", webAuthnConfiguration.GetRelyingPartyID())
+	assert.Equal(t, "My app", webAuthnConfiguration.GetRelyingPartyName())
+	assert.Equal(t, []string{"https://badaas.example.com", "https://app.badaas.example.com"}, webAuthnConfiguration.GetOrigins())
+	assert.Equal(t, 2*time.Minute, webAuthnConfiguration.GetTimeout())
+	assert.Equal(t, "required", webAuthnConfiguration.GetUserVerification())
+}
+
+func TestWebAuthnConfigurationDefaultOrigin(t *testing.T) {
+	setupViperEnvironment(`webauthn:
+  relyingPartyID: badaas.example.com`)
+	webAuthnConfiguration := configuration.NewWebAuthnConfiguration()
+	assert.Equal(t, []string{"https://badaas.example.com"}, webAuthnConfiguration.GetOrigins())
+}
+
+func TestWebAuthnConfigurationLog(t *testing.T) {
+	setupViperEnvironment(WebAuthnConfigurationString)
+	// creating logger
+	observedZapCore, observedLogs := observer.New(zap.DebugLevel)
+	observedLogger := zap.New(observedZapCore)
+
+	webAuthnConfiguration := configuration.NewWebAuthnConfiguration()
+	webAuthnConfiguration.Log(observedLogger)
+
+	require.Equal(t, 1, observedLogs.Len())
+	log := observedLogs.All()[0]
+	assert.Equal(t, "WebAuthn configuration", log.Message)
+	require.Len(t, log.Context, 5)
+	assert.Equal(t, zap.String("relyingPartyID", "badaas.example.com"), log.Context[0])
+	assert.Equal(t, zap.String("relyingPartyName", "My app"), log.Context[1])
+	assert.Equal(t, "origins", log.Context[2].Key)
+	assert.Equal(t, zap.Duration("timeout", 2*time.Minute), log.Context[3])
+	assert.Equal(t, zap.String("userVerification", "required"), log.Context[4])
+}
@@ -5,6 +5,22 @@ import "go.uber.org/fx"
 // ControllerModule for fx
 var ControllerModule = fx.Module(
 	"controllers",
+	fx.Provide(NewLoginGate),
 	fx.Provide(NewInfoController),
 	fx.Provide(NewBasicAuthentificationController),
+	fx.Provide(NewRBACController),
+	fx.Provide(NewPolicyController),
+	fx.Provide(NewGroupController),
+	fx.Provide(NewUserController),
+	fx.Provide(NewAccountController),
+	fx.Provide(NewRegistrationController),
+	fx.Provide(NewEmailVerificationController),
+	fx.Provide(NewPasswordResetController),
+	fx.Provide(NewTwoFactorController),
+	fx.Provide(NewWebAuthnController),
+	fx.Provide(NewOIDCController),
+	fx.Provide(NewSAMLController),
+	fx.Provide(NewOAuthController),
+	fx.Provide(NewAPIKeyController),
+	fx.Provide(NewSessionController),
 )
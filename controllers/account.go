@@ -0,0 +1,103 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/ditrit/badaas/httperrors"
+	"github.com/ditrit/badaas/persistence/models/dto"
+	"github.com/ditrit/badaas/services/sessionservice"
+	"github.com/ditrit/badaas/services/userservice"
+	"go.uber.org/zap"
+)
+
+// Self-service account Controller, every handler acts on the user of the session
+type AccountController interface {
+	GetMe(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	ChangePassword(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	ChangeEmail(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	ConfirmEmailChange(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+}
+
+// Check interface compliance
+var _ AccountController = (*accountController)(nil)
+
+// AccountController implementation
+type accountController struct {
+	logger         *zap.Logger
+	userService    userservice.UserService
+	sessionService sessionservice.SessionService
+}
+
+// AccountController constructor
+func NewAccountController(
+	logger *zap.Logger,
+	userService userservice.UserService,
+	sessionService sessionservice.SessionService,
+) AccountController {
+	return &accountController{
+		logger:         logger,
+		userService:    userService,
+		sessionService: sessionService,
+	}
+}
+
+// Return the current user
+func (accountController *accountController) GetMe(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	sessionClaims := sessionservice.GetSessionClaimsFromContext(r.Context())
+	user, herr := accountController.userService.GetUserByID(sessionClaims.UserID)
+	if herr != nil {
+		return nil, herr
+	}
+	return makeDTOUser(user), nil
+}
+
+// Change the password of the current user, its other sessions are revoked
+func (accountController *accountController) ChangePassword(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	var changePasswordDTO dto.DTOChangePassword
+	herr := decodeJSON(r, &changePasswordDTO)
+	if herr != nil {
+		return nil, herr
+	}
+	sessionClaims := sessionservice.GetSessionClaimsFromContext(r.Context())
+	herr = accountController.userService.ChangePassword(
+		sessionClaims.UserID,
+		sessionClaims.SessionUUID,
+		changePasswordDTO.CurrentPassword,
+		changePasswordDTO.NewPassword,
+	)
+	if herr != nil {
+		return nil, herr
+	}
+	return nil, accountController.sessionService.RotateSession(sessionClaims, w)
+}
+
+// Request the change of the email of the current user, it has to be confirmed with the token sent to the new email
+func (accountController *accountController) ChangeEmail(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	var changeEmailDTO dto.DTOChangeEmail
+	herr := decodeJSON(r, &changeEmailDTO)
+	if herr != nil {
+		return nil, herr
+	}
+	sessionClaims := sessionservice.GetSessionClaimsFromContext(r.Context())
+	herr = accountController.userService.RequestEmailChange(sessionClaims.UserID, changeEmailDTO.Email)
+	if herr != nil {
+		return nil, herr
+	}
+	w.WriteHeader(http.StatusAccepted)
+	return nil, nil
+}
+
+// Confirm the change of the email of the current user
+func (accountController *accountController) ConfirmEmailChange(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	var confirmEmailChangeDTO dto.DTOConfirmEmailChange
+	herr := decodeJSON(r, &confirmEmailChangeDTO)
+	if herr != nil {
+		return nil, herr
+	}
+	sessionClaims := sessionservice.GetSessionClaimsFromContext(r.Context())
+	user, herr := accountController.userService.ConfirmEmailChange(sessionClaims.UserID, confirmEmailChangeDTO.Token)
+	if herr != nil {
+		return nil, herr
+	}
+	return makeDTOUser(user), nil
+}
@@ -0,0 +1,94 @@
+package controllers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ditrit/badaas/controllers"
+	mocksSessionService "github.com/ditrit/badaas/mocks/services/sessionservice"
+	mocksUserService "github.com/ditrit/badaas/mocks/services/userservice"
+	"github.com/ditrit/badaas/persistence/models"
+	"github.com/ditrit/badaas/persistence/models/dto"
+	"github.com/ditrit/badaas/services/sessionservice"
+	"github.com/ditrit/badaas/services/userservice"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func Test_GetMe(t *testing.T) {
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Username: "bob", Email: "bob@email.com"}
+	userService := mocksUserService.NewUserService(t)
+	userService.On("GetUserByID", user.ID).Return(user, nil)
+
+	controller := controllers.NewAccountController(zap.L(), userService, mocksSessionService.NewSessionService(t))
+	request := makeAuthenticatedRequest(user.ID, "GET", "/me", "", nil)
+
+	payload, err := controller.GetMe(httptest.NewRecorder(), request)
+	assert.NoError(t, err)
+	assert.Equal(t, dto.DTOUser{ID: user.ID.String(), Username: "bob", Email: "bob@email.com"}, payload)
+}
+
+func Test_ChangePassword(t *testing.T) {
+	userID := uuid.New()
+	sessionUUID := uuid.New()
+	userService := mocksUserService.NewUserService(t)
+	userService.On("ChangePassword", userID, sessionUUID, "1234", "5678").Return(userservice.HERRWrongCurrentPassword)
+
+	controller := controllers.NewAccountController(zap.L(), userService, mocksSessionService.NewSessionService(t))
+	request := makeAuthenticatedRequest(userID, "POST", "/me/password",
+		`{"currentPassword": "1234", "newPassword": "5678"}`, nil)
+	request = request.WithContext(sessionservice.SetSessionClaimsContext(
+		request.Context(), &sessionservice.SessionClaims{UserID: userID, SessionUUID: sessionUUID}))
+
+	payload, err := controller.ChangePassword(httptest.NewRecorder(), request)
+	assert.Equal(t, userservice.HERRWrongCurrentPassword, err)
+	assert.Nil(t, payload)
+}
+
+func Test_ChangePassword_RotatesTheSession(t *testing.T) {
+	sessionClaims := &sessionservice.SessionClaims{UserID: uuid.New(), SessionUUID: uuid.New()}
+	userService := mocksUserService.NewUserService(t)
+	userService.On("ChangePassword", sessionClaims.UserID, sessionClaims.SessionUUID, "1234", "5678").Return(nil)
+	sessionService := mocksSessionService.NewSessionService(t)
+	response := httptest.NewRecorder()
+	sessionService.On("RotateSession", sessionClaims, response).Return(nil)
+
+	controller := controllers.NewAccountController(zap.L(), userService, sessionService)
+	request := httptest.NewRequest("POST", "/me/password", strings.NewReader(`{"currentPassword": "1234", "newPassword": "5678"}`))
+	request = request.WithContext(sessionservice.SetSessionClaimsContext(request.Context(), sessionClaims))
+
+	payload, err := controller.ChangePassword(response, request)
+	assert.NoError(t, err)
+	assert.Nil(t, payload)
+}
+
+func Test_ChangeEmail(t *testing.T) {
+	userID := uuid.New()
+	userService := mocksUserService.NewUserService(t)
+	userService.On("RequestEmailChange", userID, "alice@email.com").Return(nil)
+
+	controller := controllers.NewAccountController(zap.L(), userService, mocksSessionService.NewSessionService(t))
+	request := makeAuthenticatedRequest(userID, "POST", "/me/email", `{"email": "alice@email.com"}`, nil)
+	response := httptest.NewRecorder()
+
+	payload, err := controller.ChangeEmail(response, request)
+	assert.NoError(t, err)
+	assert.Nil(t, payload)
+	assert.Equal(t, http.StatusAccepted, response.Code)
+}
+
+func Test_ConfirmEmailChange(t *testing.T) {
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Username: "bob", Email: "alice@email.com"}
+	userService := mocksUserService.NewUserService(t)
+	userService.On("ConfirmEmailChange", user.ID, "token").Return(user, nil)
+
+	controller := controllers.NewAccountController(zap.L(), userService, mocksSessionService.NewSessionService(t))
+	request := makeAuthenticatedRequest(user.ID, "POST", "/me/email/confirm", `{"token": "token"}`, nil)
+
+	payload, err := controller.ConfirmEmailChange(httptest.NewRecorder(), request)
+	assert.NoError(t, err)
+	assert.Equal(t, dto.DTOUser{ID: user.ID.String(), Username: "bob", Email: "alice@email.com"}, payload)
+}
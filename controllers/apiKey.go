@@ -0,0 +1,98 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/ditrit/badaas/httperrors"
+	"github.com/ditrit/badaas/persistence/models"
+	"github.com/ditrit/badaas/persistence/models/dto"
+	"github.com/ditrit/badaas/services/apikeyservice"
+	"github.com/ditrit/badaas/services/sessionservice"
+	"go.uber.org/zap"
+)
+
+// API keys Controller, the personal access tokens of the current user
+type APIKeyController interface {
+	ListAPIKeys(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	CreateAPIKey(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	RevokeAPIKey(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+}
+
+// Check interface compliance
+var _ APIKeyController = (*apiKeyController)(nil)
+
+// APIKeyController implementation
+type apiKeyController struct {
+	logger        *zap.Logger
+	apiKeyService apikeyservice.APIKeyService
+}
+
+// APIKeyController constructor
+func NewAPIKeyController(
+	logger *zap.Logger,
+	apiKeyService apikeyservice.APIKeyService,
+) APIKeyController {
+	return &apiKeyController{
+		logger:        logger,
+		apiKeyService: apiKeyService,
+	}
+}
+
+// List the API keys of the current user
+func (apiKeyController *apiKeyController) ListAPIKeys(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	apiKeys, herr := apiKeyController.apiKeyService.GetAPIKeys(
+		sessionservice.GetSessionClaimsFromContext(r.Context()).UserID)
+	if herr != nil {
+		return nil, herr
+	}
+	dtoAPIKeys := make([]dto.DTOAPIKey, 0, len(apiKeys))
+	for _, apiKey := range apiKeys {
+		dtoAPIKeys = append(dtoAPIKeys, makeDTOAPIKey(apiKey))
+	}
+	return dtoAPIKeys, nil
+}
+
+// Create an API key for the current user, the key is only returned in the response
+//
+// The keys can only be created with a session.
+func (apiKeyController *apiKeyController) CreateAPIKey(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	sessionClaims := sessionservice.GetSessionClaimsFromContext(r.Context())
+	if sessionClaims.IsAPIKey() {
+		return nil, apikeyservice.HERRAPIKeyNotAllowed
+	}
+	var createAPIKeyDTO dto.DTOCreateAPIKey
+	herr := decodeJSON(r, &createAPIKeyDTO)
+	if herr != nil {
+		return nil, herr
+	}
+	apiKey, key, herr := apiKeyController.apiKeyService.CreateAPIKey(sessionClaims.UserID, createAPIKeyDTO)
+	if herr != nil {
+		return nil, herr
+	}
+	dtoAPIKey := makeDTOAPIKey(apiKey)
+	dtoAPIKey.Key = key
+	return dtoAPIKey, nil
+}
+
+// Revoke an API key of the current user
+func (apiKeyController *apiKeyController) RevokeAPIKey(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	apiKeyID, herr := getUUIDFromPath(r, "id")
+	if herr != nil {
+		return nil, herr
+	}
+	return nil, apiKeyController.apiKeyService.RevokeAPIKey(
+		sessionservice.GetSessionClaimsFromContext(r.Context()).UserID, apiKeyID)
+}
+
+// Create an API key DTO
+func makeDTOAPIKey(apiKey *models.APIKey) dto.DTOAPIKey {
+	return dto.DTOAPIKey{
+		ID:         apiKey.ID.String(),
+		Name:       apiKey.Name,
+		Prefix:     apiKey.Prefix,
+		Scopes:     apiKey.GetScopes(),
+		CreatedAt:  apiKey.CreatedAt,
+		ExpiresAt:  apiKey.ExpiresAt,
+		LastUsedAt: apiKey.LastUsedAt,
+	}
+}
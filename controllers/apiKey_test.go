@@ -0,0 +1,59 @@
+package controllers_test
+
+import (
+	"testing"
+
+	"github.com/ditrit/badaas/controllers"
+	mocksAPIKeyService "github.com/ditrit/badaas/mocks/services/apikeyservice"
+	"github.com/ditrit/badaas/persistence/models"
+	"github.com/ditrit/badaas/persistence/models/dto"
+	"github.com/ditrit/badaas/services/apikeyservice"
+	"github.com/ditrit/badaas/services/sessionservice"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func Test_CreateAPIKey(t *testing.T) {
+	userID := uuid.New()
+	apiKey := &models.APIKey{BaseModel: models.BaseModel{ID: uuid.New()}, Name: "ci", Prefix: "badaas_abcdef", Scope: "users:manage"}
+	apiKeyService := mocksAPIKeyService.NewAPIKeyService(t)
+	apiKeyService.On("CreateAPIKey", userID, dto.DTOCreateAPIKey{Name: "ci", Scopes: []string{"users:manage"}}).
+		Return(apiKey, "badaas_abcdefghij", nil)
+
+	controller := controllers.NewAPIKeyController(zap.L(), apiKeyService)
+	payload, herr := controller.CreateAPIKey(nil, makeAuthenticatedRequest(userID, "POST", "/me/api-keys",
+		`{"name": "ci", "scopes": ["users:manage"]}`, nil))
+	assert.Nil(t, herr)
+	assert.Equal(t, dto.DTOAPIKey{
+		ID:     apiKey.ID.String(),
+		Name:   "ci",
+		Prefix: "badaas_abcdef",
+		Scopes: []string{"users:manage"},
+		Key:    "badaas_abcdefghij",
+	}, payload)
+}
+
+func Test_CreateAPIKeyWithAPIKey(t *testing.T) {
+	controller := controllers.NewAPIKeyController(zap.L(), mocksAPIKeyService.NewAPIKeyService(t))
+	request := makeAuthenticatedRequest(uuid.New(), "POST", "/me/api-keys", `{"name": "ci", "scopes": ["*"]}`, nil)
+	request = request.WithContext(sessionservice.SetSessionClaimsContext(request.Context(),
+		&sessionservice.SessionClaims{UserID: uuid.New(), APIKeyID: uuid.New()}))
+
+	_, herr := controller.CreateAPIKey(nil, request)
+	assert.Equal(t, apikeyservice.HERRAPIKeyNotAllowed, herr)
+}
+
+func Test_ListAPIKeys(t *testing.T) {
+	userID := uuid.New()
+	apiKey := &models.APIKey{BaseModel: models.BaseModel{ID: uuid.New()}, Name: "ci", Prefix: "badaas_abcdef"}
+	apiKeyService := mocksAPIKeyService.NewAPIKeyService(t)
+	apiKeyService.On("GetAPIKeys", userID).Return([]*models.APIKey{apiKey}, nil)
+
+	controller := controllers.NewAPIKeyController(zap.L(), apiKeyService)
+	payload, herr := controller.ListAPIKeys(nil, makeAuthenticatedRequest(userID, "GET", "/me/api-keys", "", nil))
+	assert.Nil(t, herr)
+	assert.Equal(t, []dto.DTOAPIKey{
+		{ID: apiKey.ID.String(), Name: "ci", Prefix: "badaas_abcdef", Scopes: []string{}},
+	}, payload)
+}
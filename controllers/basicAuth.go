@@ -6,6 +6,7 @@ import (
 
 	"github.com/ditrit/badaas/httperrors"
 	"github.com/ditrit/badaas/persistence/models/dto"
+	"github.com/ditrit/badaas/services/ldapservice"
 	"github.com/ditrit/badaas/services/sessionservice"
 	"github.com/ditrit/badaas/services/userservice"
 	"go.uber.org/zap"
@@ -33,47 +34,49 @@ var _ BasicAuthentificationController = (*basicAuthentificationController)(nil)
 // BasicAuthentificationController implementation
 type basicAuthentificationController struct {
 	logger         *zap.Logger
-	userService    userservice.UserService
+	ldapService    ldapservice.LDAPService
 	sessionService sessionservice.SessionService
+	loginGate      LoginGate
 }
 
 // BasicAuthentificationController contructor
 func NewBasicAuthentificationController(
 	logger *zap.Logger,
-	userService userservice.UserService,
+	ldapService ldapservice.LDAPService,
 	sessionService sessionservice.SessionService,
+	loginGate LoginGate,
 ) BasicAuthentificationController {
 	return &basicAuthentificationController{
 		logger:         logger,
-		userService:    userService,
+		ldapService:    ldapService,
 		sessionService: sessionService,
+		loginGate:      loginGate,
 	}
 }
 
 // Log In with username and password
+//
+// The failed attempts are throttled per account and per ip address.
+// If the user has to give a second factor, a challenge is returned instead of creating the session.
 func (basicAuthController *basicAuthentificationController) BasicLoginHandler(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
 	var loginJSONStruct dto.UserLoginDTO
 	err := json.NewDecoder(r.Body).Decode(&loginJSONStruct)
 	if err != nil {
 		return nil, HTTPErrRequestMalformed
 	}
-	user, herr := basicAuthController.userService.GetUser(loginJSONStruct)
+	herr := basicAuthController.loginGate.CheckAttempt(loginJSONStruct.Email, r, w)
 	if herr != nil {
 		return nil, herr
 	}
-
-	// On valid password, generate a session and return it's uuid to the client
-	herr = basicAuthController.sessionService.LogUserIn(user, w)
+	user, herr := basicAuthController.ldapService.GetUser(loginJSONStruct)
+	if herr == userservice.HERRWrongPassword {
+		basicAuthController.loginGate.RecordFailure(loginJSONStruct.Email, r)
+		return nil, herr
+	}
 	if herr != nil {
 		return nil, herr
-
 	}
-
-	return dto.DTOLoginSuccess{
-		Email:    user.Email,
-		ID:       user.ID.String(),
-		Username: user.Username,
-	}, nil
+	return basicAuthController.loginGate.LogUserIn(user, r, w)
 }
 
 // Log Out the user
@@ -1,16 +1,23 @@
 package controllers_test
 
 import (
+	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/ditrit/badaas/controllers"
 	"github.com/ditrit/badaas/httperrors"
+	mocksLDAPService "github.com/ditrit/badaas/mocks/services/ldapservice"
+	mocksLoginThrottlingService "github.com/ditrit/badaas/mocks/services/loginthrottlingservice"
 	mocksSessionService "github.com/ditrit/badaas/mocks/services/sessionservice"
-	mocksUserService "github.com/ditrit/badaas/mocks/services/userservice"
+	mocksTwoFactorService "github.com/ditrit/badaas/mocks/services/twofactorservice"
+	mocksWebAuthnService "github.com/ditrit/badaas/mocks/services/webauthnservice"
 	"github.com/ditrit/badaas/persistence/models"
 	"github.com/ditrit/badaas/persistence/models/dto"
+	"github.com/ditrit/badaas/services/loginthrottlingservice"
+	"github.com/ditrit/badaas/services/userservice"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap"
@@ -21,13 +28,17 @@ func Test_BasicLoginHandler_MalformedRequest(t *testing.T) {
 	core, _ := observer.New(zap.DebugLevel)
 	logger := zap.New(core)
 
-	userService := mocksUserService.NewUserService(t)
+	ldapService := mocksLDAPService.NewLDAPService(t)
 	sessionService := mocksSessionService.NewSessionService(t)
+	loginThrottlingService := mocksLoginThrottlingService.NewLoginThrottlingService(t)
+	twoFactorService := mocksTwoFactorService.NewTwoFactorService(t)
+	webAuthnService := mocksWebAuthnService.NewWebAuthnService(t)
 
 	controller := controllers.NewBasicAuthentificationController(
 		logger,
-		userService,
+		ldapService,
 		sessionService,
+		controllers.NewLoginGate(zap.L(), sessionService, loginThrottlingService, twoFactorService, webAuthnService),
 	)
 	response := httptest.NewRecorder()
 	request := httptest.NewRequest(
@@ -49,16 +60,21 @@ func Test_BasicLoginHandler_UserNotFound(t *testing.T) {
 		Email:    "bob@email.com",
 		Password: "1234",
 	}
-	userService := mocksUserService.NewUserService(t)
-	userService.
+	ldapService := mocksLDAPService.NewLDAPService(t)
+	ldapService.
 		On("GetUser", loginJSONStruct).
 		Return(nil, httperrors.AnError)
 	sessionService := mocksSessionService.NewSessionService(t)
+	loginThrottlingService := mocksLoginThrottlingService.NewLoginThrottlingService(t)
+	twoFactorService := mocksTwoFactorService.NewTwoFactorService(t)
+	webAuthnService := mocksWebAuthnService.NewWebAuthnService(t)
+	loginThrottlingService.On("Check", "bob@email.com", "192.0.2.1").Return(time.Duration(0), nil)
 
 	controller := controllers.NewBasicAuthentificationController(
 		logger,
-		userService,
+		ldapService,
 		sessionService,
+		controllers.NewLoginGate(zap.L(), sessionService, loginThrottlingService, twoFactorService, webAuthnService),
 	)
 	response := httptest.NewRecorder()
 	request := httptest.NewRequest(
@@ -92,25 +108,33 @@ func Test_BasicLoginHandler_LoginFailed(t *testing.T) {
 			"password":"1234"
 		}`),
 	)
-	userService := mocksUserService.NewUserService(t)
+	ldapService := mocksLDAPService.NewLDAPService(t)
 	user := &models.User{
 		BaseModel: models.BaseModel{},
 		Username:  "bob",
 		Email:     "bob@email.com",
 		Password:  []byte("hash of 1234"),
 	}
-	userService.
+	ldapService.
 		On("GetUser", loginJSONStruct).
 		Return(user, nil)
 	sessionService := mocksSessionService.NewSessionService(t)
 	sessionService.
-		On("LogUserIn", user, response).
+		On("LogUserIn", user, request, response).
 		Return(httperrors.AnError)
+	loginThrottlingService := mocksLoginThrottlingService.NewLoginThrottlingService(t)
+	twoFactorService := mocksTwoFactorService.NewTwoFactorService(t)
+	webAuthnService := mocksWebAuthnService.NewWebAuthnService(t)
+	loginThrottlingService.On("Check", "bob@email.com", "192.0.2.1").Return(time.Duration(0), nil)
+	twoFactorService.On("IsEnabled", user.ID).Return(false, nil)
+	webAuthnService.On("HasCredentials", user.ID).Return(false, nil)
+	twoFactorService.On("IsRequired", user.ID).Return(false, nil)
 
 	controller := controllers.NewBasicAuthentificationController(
 		logger,
-		userService,
+		ldapService,
 		sessionService,
+		controllers.NewLoginGate(zap.L(), sessionService, loginThrottlingService, twoFactorService, webAuthnService),
 	)
 
 	payload, err := controller.BasicLoginHandler(response, request)
@@ -135,7 +159,7 @@ func Test_BasicLoginHandler_LoginSuccess(t *testing.T) {
 			"password":"1234"
 		}`),
 	)
-	userService := mocksUserService.NewUserService(t)
+	ldapService := mocksLDAPService.NewLDAPService(t)
 	user := &models.User{
 		BaseModel: models.BaseModel{
 			ID: uuid.Nil,
@@ -144,18 +168,27 @@ func Test_BasicLoginHandler_LoginSuccess(t *testing.T) {
 		Email:    "bob@email.com",
 		Password: []byte("hash of 1234"),
 	}
-	userService.
+	ldapService.
 		On("GetUser", loginJSONStruct).
 		Return(user, nil)
 	sessionService := mocksSessionService.NewSessionService(t)
 	sessionService.
-		On("LogUserIn", user, response).
+		On("LogUserIn", user, request, response).
 		Return(nil)
+	loginThrottlingService := mocksLoginThrottlingService.NewLoginThrottlingService(t)
+	twoFactorService := mocksTwoFactorService.NewTwoFactorService(t)
+	webAuthnService := mocksWebAuthnService.NewWebAuthnService(t)
+	loginThrottlingService.On("Check", "bob@email.com", "192.0.2.1").Return(time.Duration(0), nil)
+	loginThrottlingService.On("ResetAccount", "bob@email.com").Return(nil)
+	twoFactorService.On("IsEnabled", user.ID).Return(false, nil)
+	webAuthnService.On("HasCredentials", user.ID).Return(false, nil)
+	twoFactorService.On("IsRequired", user.ID).Return(false, nil)
 
 	controller := controllers.NewBasicAuthentificationController(
 		logger,
-		userService,
+		ldapService,
 		sessionService,
+		controllers.NewLoginGate(zap.L(), sessionService, loginThrottlingService, twoFactorService, webAuthnService),
 	)
 
 	payload, err := controller.BasicLoginHandler(response, request)
@@ -166,3 +199,163 @@ func Test_BasicLoginHandler_LoginSuccess(t *testing.T) {
 		Username: user.Username,
 	})
 }
+
+func Test_BasicLoginHandler_WrongPasswordRecorded(t *testing.T) {
+	loginJSONStruct := dto.UserLoginDTO{
+		Email:    "bob@email.com",
+		Password: "1234",
+	}
+	ldapService := mocksLDAPService.NewLDAPService(t)
+	ldapService.
+		On("GetUser", loginJSONStruct).
+		Return(nil, userservice.HERRWrongPassword)
+	loginThrottlingService := mocksLoginThrottlingService.NewLoginThrottlingService(t)
+	twoFactorService := mocksTwoFactorService.NewTwoFactorService(t)
+	webAuthnService := mocksWebAuthnService.NewWebAuthnService(t)
+	loginThrottlingService.On("Check", "bob@email.com", "192.0.2.1").Return(time.Duration(0), nil)
+	loginThrottlingService.On("RecordFailure", "bob@email.com", "192.0.2.1").Return(nil)
+
+	controller := controllers.NewBasicAuthentificationController(
+		zap.L(),
+		ldapService,
+		mocksSessionService.NewSessionService(t),
+		controllers.NewLoginGate(zap.L(), mocksSessionService.NewSessionService(t), loginThrottlingService, twoFactorService, webAuthnService),
+	)
+	request := httptest.NewRequest(
+		"POST",
+		"/v1/auth/basic/login",
+		strings.NewReader(`{"email": "bob@email.com", "password":"1234"}`),
+	)
+
+	payload, err := controller.BasicLoginHandler(httptest.NewRecorder(), request)
+	assert.Equal(t, userservice.HERRWrongPassword, err)
+	assert.Nil(t, payload)
+}
+
+func Test_BasicLoginHandler_Throttled(t *testing.T) {
+	loginThrottlingService := mocksLoginThrottlingService.NewLoginThrottlingService(t)
+	twoFactorService := mocksTwoFactorService.NewTwoFactorService(t)
+	webAuthnService := mocksWebAuthnService.NewWebAuthnService(t)
+	loginThrottlingService.
+		On("Check", "bob@email.com", "192.0.2.1").
+		Return(1500*time.Millisecond, loginthrottlingservice.HERRTooManyAttempts)
+
+	controller := controllers.NewBasicAuthentificationController(
+		zap.L(),
+		mocksLDAPService.NewLDAPService(t),
+		mocksSessionService.NewSessionService(t),
+		controllers.NewLoginGate(zap.L(), mocksSessionService.NewSessionService(t), loginThrottlingService, twoFactorService, webAuthnService),
+	)
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest(
+		"POST",
+		"/v1/auth/basic/login",
+		strings.NewReader(`{"email": "bob@email.com", "password":"1234"}`),
+	)
+
+	payload, err := controller.BasicLoginHandler(response, request)
+	assert.Equal(t, http.StatusTooManyRequests, err.(*httperrors.HTTPErrorImpl).Status)
+	assert.Equal(t, "2", response.Header().Get("Retry-After"))
+	assert.Nil(t, payload)
+}
+
+func Test_BasicLoginHandler_SecondFactorChallenge(t *testing.T) {
+	loginJSONStruct := dto.UserLoginDTO{
+		Email:    "bob@email.com",
+		Password: "1234",
+	}
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Username: "bob", Email: "bob@email.com"}
+	ldapService := mocksLDAPService.NewLDAPService(t)
+	ldapService.On("GetUser", loginJSONStruct).Return(user, nil)
+	loginThrottlingService := mocksLoginThrottlingService.NewLoginThrottlingService(t)
+	loginThrottlingService.On("Check", "bob@email.com", "192.0.2.1").Return(time.Duration(0), nil)
+	twoFactorService := mocksTwoFactorService.NewTwoFactorService(t)
+	webAuthnService := mocksWebAuthnService.NewWebAuthnService(t)
+	twoFactorService.On("IsEnabled", user.ID).Return(true, nil)
+	webAuthnService.On("HasCredentials", user.ID).Return(false, nil)
+	twoFactorService.On("CreateChallenge", user.ID, false).Return("challenge", nil)
+
+	// no session is created before the second factor
+	controller := controllers.NewBasicAuthentificationController(
+		zap.L(),
+		ldapService,
+		mocksSessionService.NewSessionService(t),
+		controllers.NewLoginGate(zap.L(), mocksSessionService.NewSessionService(t), loginThrottlingService, twoFactorService, webAuthnService),
+	)
+	request := httptest.NewRequest(
+		"POST",
+		"/v1/auth/basic/login",
+		strings.NewReader(`{"email": "bob@email.com", "password":"1234"}`),
+	)
+
+	payload, err := controller.BasicLoginHandler(httptest.NewRecorder(), request)
+	assert.Nil(t, err)
+	assert.Equal(t, &dto.DTOLoginChallenge{TwoFactorRequired: true, Methods: []string{"totp"}, Challenge: "challenge"}, payload)
+}
+
+func Test_BasicLoginHandler_WebAuthnSecondFactor(t *testing.T) {
+	loginJSONStruct := dto.UserLoginDTO{
+		Email:    "bob@email.com",
+		Password: "1234",
+	}
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Username: "bob", Email: "bob@email.com"}
+	ldapService := mocksLDAPService.NewLDAPService(t)
+	ldapService.On("GetUser", loginJSONStruct).Return(user, nil)
+	loginThrottlingService := mocksLoginThrottlingService.NewLoginThrottlingService(t)
+	loginThrottlingService.On("Check", "bob@email.com", "192.0.2.1").Return(time.Duration(0), nil)
+	twoFactorService := mocksTwoFactorService.NewTwoFactorService(t)
+	webAuthnService := mocksWebAuthnService.NewWebAuthnService(t)
+	twoFactorService.On("IsEnabled", user.ID).Return(false, nil)
+	webAuthnService.On("HasCredentials", user.ID).Return(true, nil)
+	twoFactorService.On("CreateChallenge", user.ID, false).Return("challenge", nil)
+
+	controller := controllers.NewBasicAuthentificationController(
+		zap.L(),
+		ldapService,
+		mocksSessionService.NewSessionService(t),
+		controllers.NewLoginGate(zap.L(), mocksSessionService.NewSessionService(t), loginThrottlingService, twoFactorService, webAuthnService),
+	)
+	request := httptest.NewRequest(
+		"POST",
+		"/v1/auth/basic/login",
+		strings.NewReader(`{"email": "bob@email.com", "password":"1234"}`),
+	)
+
+	payload, err := controller.BasicLoginHandler(httptest.NewRecorder(), request)
+	assert.Nil(t, err)
+	assert.Equal(t, &dto.DTOLoginChallenge{TwoFactorRequired: true, Methods: []string{"webauthn"}, Challenge: "challenge"}, payload)
+}
+
+func Test_BasicLoginHandler_EnrolmentRequired(t *testing.T) {
+	loginJSONStruct := dto.UserLoginDTO{
+		Email:    "bob@email.com",
+		Password: "1234",
+	}
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Username: "bob", Email: "bob@email.com"}
+	ldapService := mocksLDAPService.NewLDAPService(t)
+	ldapService.On("GetUser", loginJSONStruct).Return(user, nil)
+	loginThrottlingService := mocksLoginThrottlingService.NewLoginThrottlingService(t)
+	loginThrottlingService.On("Check", "bob@email.com", "192.0.2.1").Return(time.Duration(0), nil)
+	twoFactorService := mocksTwoFactorService.NewTwoFactorService(t)
+	webAuthnService := mocksWebAuthnService.NewWebAuthnService(t)
+	twoFactorService.On("IsEnabled", user.ID).Return(false, nil)
+	webAuthnService.On("HasCredentials", user.ID).Return(false, nil)
+	twoFactorService.On("IsRequired", user.ID).Return(true, nil)
+	twoFactorService.On("CreateChallenge", user.ID, true).Return("challenge", nil)
+
+	controller := controllers.NewBasicAuthentificationController(
+		zap.L(),
+		ldapService,
+		mocksSessionService.NewSessionService(t),
+		controllers.NewLoginGate(zap.L(), mocksSessionService.NewSessionService(t), loginThrottlingService, twoFactorService, webAuthnService),
+	)
+	request := httptest.NewRequest(
+		"POST",
+		"/v1/auth/basic/login",
+		strings.NewReader(`{"email": "bob@email.com", "password":"1234"}`),
+	)
+
+	payload, err := controller.BasicLoginHandler(httptest.NewRecorder(), request)
+	assert.Nil(t, err)
+	assert.Equal(t, &dto.DTOLoginChallenge{EnrolmentRequired: true, Methods: []string{}, Challenge: "challenge"}, payload)
+}
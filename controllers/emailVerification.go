@@ -0,0 +1,65 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/ditrit/badaas/httperrors"
+	"github.com/ditrit/badaas/persistence/models/dto"
+	"github.com/ditrit/badaas/services/userservice"
+	"go.uber.org/zap"
+)
+
+// Email verification Controller
+type EmailVerificationController interface {
+	VerifyEmail(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	ResendEmailVerification(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+}
+
+// Check interface compliance
+var _ EmailVerificationController = (*emailVerificationController)(nil)
+
+// EmailVerificationController implementation
+type emailVerificationController struct {
+	logger      *zap.Logger
+	userService userservice.UserService
+}
+
+// EmailVerificationController constructor
+func NewEmailVerificationController(
+	logger *zap.Logger,
+	userService userservice.UserService,
+) EmailVerificationController {
+	return &emailVerificationController{
+		logger:      logger,
+		userService: userService,
+	}
+}
+
+// Verify the email of the user the token was sent to
+func (emailVerificationController *emailVerificationController) VerifyEmail(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	var verifyEmailDTO dto.DTOVerifyEmail
+	herr := decodeJSON(r, &verifyEmailDTO)
+	if herr != nil {
+		return nil, herr
+	}
+	user, herr := emailVerificationController.userService.VerifyEmail(verifyEmailDTO.Token)
+	if herr != nil {
+		return nil, herr
+	}
+	return makeDTOUser(user), nil
+}
+
+// Send a new verification email, the response doesn't tell if an account uses the email
+func (emailVerificationController *emailVerificationController) ResendEmailVerification(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	var resendDTO dto.DTOResendEmailVerification
+	herr := decodeJSON(r, &resendDTO)
+	if herr != nil {
+		return nil, herr
+	}
+	herr = emailVerificationController.userService.ResendEmailVerification(resendDTO.Email)
+	if herr != nil {
+		return nil, herr
+	}
+	w.WriteHeader(http.StatusAccepted)
+	return nil, nil
+}
@@ -0,0 +1,52 @@
+package controllers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ditrit/badaas/controllers"
+	mocksUserService "github.com/ditrit/badaas/mocks/services/userservice"
+	"github.com/ditrit/badaas/persistence/models"
+	"github.com/ditrit/badaas/persistence/models/dto"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func Test_VerifyEmail(t *testing.T) {
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Username: "bob", Email: "bob@email.com", EmailVerified: true}
+	userService := mocksUserService.NewUserService(t)
+	userService.On("VerifyEmail", "token").Return(user, nil)
+
+	controller := controllers.NewEmailVerificationController(zap.L(), userService)
+	request := httptest.NewRequest("POST", "/verify-email", strings.NewReader(`{"token": "token"}`))
+
+	payload, err := controller.VerifyEmail(httptest.NewRecorder(), request)
+	assert.NoError(t, err)
+	assert.Equal(t, dto.DTOUser{ID: user.ID.String(), Username: "bob", Email: "bob@email.com", EmailVerified: true}, payload)
+}
+
+func Test_ResendEmailVerification(t *testing.T) {
+	userService := mocksUserService.NewUserService(t)
+	userService.On("ResendEmailVerification", "bob@email.com").Return(nil)
+
+	controller := controllers.NewEmailVerificationController(zap.L(), userService)
+	request := httptest.NewRequest("POST", "/verify-email/resend", strings.NewReader(`{"email": "bob@email.com"}`))
+	response := httptest.NewRecorder()
+
+	payload, err := controller.ResendEmailVerification(response, request)
+	assert.NoError(t, err)
+	assert.Nil(t, payload)
+	assert.Equal(t, http.StatusAccepted, response.Code)
+}
+
+func Test_ResendEmailVerification_MalformedBody(t *testing.T) {
+	controller := controllers.NewEmailVerificationController(zap.L(), mocksUserService.NewUserService(t))
+	request := httptest.NewRequest("POST", "/verify-email/resend", strings.NewReader(`{"email": `))
+
+	payload, err := controller.ResendEmailVerification(httptest.NewRecorder(), request)
+	assert.Error(t, err)
+	assert.Nil(t, payload)
+}
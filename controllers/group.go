@@ -0,0 +1,504 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/ditrit/badaas/httperrors"
+	"github.com/ditrit/badaas/persistence/models"
+	"github.com/ditrit/badaas/persistence/models/dto"
+	"github.com/ditrit/badaas/services/groupservice"
+	"github.com/ditrit/badaas/services/rbacservice"
+	"github.com/ditrit/badaas/services/sessionservice"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Organisations and groups Controller
+//
+// The users granted the "groups:manage" permission can manage every organisation and group,
+// the other users are limited by their membership roles.
+type GroupController interface {
+	ListOrganisations(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	CreateOrganisation(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	GetOrganisation(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	DeleteOrganisation(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	ListOrganisationMembers(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	AddOrganisationMember(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	RemoveOrganisationMember(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+
+	ListGroups(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	CreateGroup(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	GetGroup(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	SetGroupParent(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	DeleteGroup(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	ListGroupMembers(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	AddGroupMember(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	RemoveGroupMember(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+}
+
+// Check interface compliance
+var _ GroupController = (*groupController)(nil)
+
+// GroupController implementation
+type groupController struct {
+	logger       *zap.Logger
+	groupService groupservice.GroupService
+	rbacService  rbacservice.RBACService
+}
+
+// GroupController constructor
+func NewGroupController(
+	logger *zap.Logger,
+	groupService groupservice.GroupService,
+	rbacService rbacservice.RBACService,
+) GroupController {
+	return &groupController{
+		logger:       logger,
+		groupService: groupService,
+		rbacService:  rbacService,
+	}
+}
+
+// List all the organisations
+func (groupController *groupController) ListOrganisations(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	herr := groupController.authorize(r, "", nil)
+	if herr != nil {
+		return nil, herr
+	}
+	organisations, herr := groupController.groupService.GetOrganisations()
+	if herr != nil {
+		return nil, herr
+	}
+	dtoOrganisations := make([]dto.DTOOrganisation, 0, len(organisations))
+	for _, organisation := range organisations {
+		dtoOrganisations = append(dtoOrganisations, makeDTOOrganisation(organisation))
+	}
+	return dtoOrganisations, nil
+}
+
+// Create an organisation
+func (groupController *groupController) CreateOrganisation(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	herr := groupController.authorize(r, "", nil)
+	if herr != nil {
+		return nil, herr
+	}
+	var createOrganisationDTO dto.DTOCreateOrganisation
+	herr = decodeJSON(r, &createOrganisationDTO)
+	if herr != nil {
+		return nil, herr
+	}
+	organisation, herr := groupController.groupService.CreateOrganisation(
+		createOrganisationDTO.Name,
+		createOrganisationDTO.Description,
+	)
+	if herr != nil {
+		return nil, herr
+	}
+	return makeDTOOrganisation(organisation), nil
+}
+
+// Get an organisation, only for its members
+func (groupController *groupController) GetOrganisation(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	organisationID, herr := getUUIDFromPath(r, "id")
+	if herr != nil {
+		return nil, herr
+	}
+	herr = groupController.authorizeOnOrganisation(r, organisationID, models.MembershipRoleMember)
+	if herr != nil {
+		return nil, herr
+	}
+	organisation, herr := groupController.groupService.GetOrganisation(organisationID)
+	if herr != nil {
+		return nil, herr
+	}
+	return makeDTOOrganisation(organisation), nil
+}
+
+// Delete an organisation, only for its owners
+func (groupController *groupController) DeleteOrganisation(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	organisationID, herr := getUUIDFromPath(r, "id")
+	if herr != nil {
+		return nil, herr
+	}
+	herr = groupController.authorizeOnOrganisation(r, organisationID, models.MembershipRoleOwner)
+	if herr != nil {
+		return nil, herr
+	}
+	return nil, groupController.groupService.DeleteOrganisation(organisationID)
+}
+
+// List the members of an organisation, only for its members
+func (groupController *groupController) ListOrganisationMembers(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	organisationID, herr := getUUIDFromPath(r, "id")
+	if herr != nil {
+		return nil, herr
+	}
+	herr = groupController.authorizeOnOrganisation(r, organisationID, models.MembershipRoleMember)
+	if herr != nil {
+		return nil, herr
+	}
+	members, herr := groupController.groupService.GetOrganisationMembers(organisationID)
+	if herr != nil {
+		return nil, herr
+	}
+	dtoMembers := make([]dto.DTOMember, 0, len(members))
+	for _, member := range members {
+		dtoMembers = append(dtoMembers, dto.DTOMember{UserID: member.UserID.String(), Role: member.Role})
+	}
+	return dtoMembers, nil
+}
+
+// Add a member to an organisation or change its role
+//
+// The admins can manage the members and the admins, only the owners can manage the owners.
+func (groupController *groupController) AddOrganisationMember(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	organisationID, herr := getUUIDFromPath(r, "id")
+	if herr != nil {
+		return nil, herr
+	}
+	userID, role, herr := decodeMember(r)
+	if herr != nil {
+		return nil, herr
+	}
+	currentRole, herr := groupController.groupService.GetOrganisationRole(userID, organisationID)
+	if herr != nil {
+		return nil, herr
+	}
+	herr = groupController.authorizeOnOrganisation(r, organisationID, requiredRoleToManage(currentRole, role))
+	if herr != nil {
+		return nil, herr
+	}
+	return nil, groupController.groupService.AddOrganisationMember(organisationID, userID, role)
+}
+
+// Remove a member from an organisation
+func (groupController *groupController) RemoveOrganisationMember(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	organisationID, herr := getUUIDFromPath(r, "id")
+	if herr != nil {
+		return nil, herr
+	}
+	userID, herr := getUUIDFromPath(r, "userID")
+	if herr != nil {
+		return nil, herr
+	}
+	currentRole, herr := groupController.groupService.GetOrganisationRole(userID, organisationID)
+	if herr != nil {
+		return nil, herr
+	}
+	herr = groupController.authorizeOnOrganisation(r, organisationID, requiredRoleToManage(currentRole, ""))
+	if herr != nil {
+		return nil, herr
+	}
+	return nil, groupController.groupService.RemoveOrganisationMember(organisationID, userID)
+}
+
+// List the groups, or the groups of the organisation given in the "organisation" query parameter
+func (groupController *groupController) ListGroups(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	organisationID, herr := parseOptionalUUID(r.URL.Query().Get("organisation"))
+	if herr != nil {
+		return nil, herr
+	}
+	if organisationID != nil {
+		herr = groupController.authorizeOnOrganisation(r, *organisationID, models.MembershipRoleMember)
+	} else {
+		herr = groupController.authorize(r, "", nil)
+	}
+	if herr != nil {
+		return nil, herr
+	}
+	groups, herr := groupController.groupService.GetGroups(organisationID)
+	if herr != nil {
+		return nil, herr
+	}
+	dtoGroups := make([]dto.DTOGroup, 0, len(groups))
+	for _, group := range groups {
+		dtoGroups = append(dtoGroups, makeDTOGroup(group))
+	}
+	return dtoGroups, nil
+}
+
+// Create a group
+//
+// A subgroup can be created by the admins of the parent group,
+// a group of an organisation by the admins of the organisation.
+func (groupController *groupController) CreateGroup(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	var createGroupDTO dto.DTOCreateGroup
+	herr := decodeJSON(r, &createGroupDTO)
+	if herr != nil {
+		return nil, herr
+	}
+	organisationID, herr := parseOptionalUUID(createGroupDTO.OrganisationID)
+	if herr != nil {
+		return nil, herr
+	}
+	parentID, herr := parseOptionalUUID(createGroupDTO.ParentID)
+	if herr != nil {
+		return nil, herr
+	}
+	switch {
+	case parentID != nil:
+		herr = groupController.authorizeOnGroup(r, *parentID, models.MembershipRoleAdmin)
+	case organisationID != nil:
+		herr = groupController.authorizeOnOrganisation(r, *organisationID, models.MembershipRoleAdmin)
+	default:
+		herr = groupController.authorize(r, "", nil)
+	}
+	if herr != nil {
+		return nil, herr
+	}
+	group, herr := groupController.groupService.CreateGroup(
+		createGroupDTO.Name,
+		createGroupDTO.Description,
+		organisationID,
+		parentID,
+	)
+	if herr != nil {
+		return nil, herr
+	}
+	return makeDTOGroup(group), nil
+}
+
+// Get a group, only for its members
+func (groupController *groupController) GetGroup(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	groupID, herr := getUUIDFromPath(r, "id")
+	if herr != nil {
+		return nil, herr
+	}
+	herr = groupController.authorizeOnGroup(r, groupID, models.MembershipRoleMember)
+	if herr != nil {
+		return nil, herr
+	}
+	group, herr := groupController.groupService.GetGroup(groupID)
+	if herr != nil {
+		return nil, herr
+	}
+	return makeDTOGroup(group), nil
+}
+
+// Move a group, the user must be an owner of the group and an admin of the new parent
+func (groupController *groupController) SetGroupParent(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	groupID, herr := getUUIDFromPath(r, "id")
+	if herr != nil {
+		return nil, herr
+	}
+	var setGroupParentDTO dto.DTOSetGroupParent
+	herr = decodeJSON(r, &setGroupParentDTO)
+	if herr != nil {
+		return nil, herr
+	}
+	parentID, herr := parseOptionalUUID(setGroupParentDTO.ParentID)
+	if herr != nil {
+		return nil, herr
+	}
+	herr = groupController.authorizeOnGroup(r, groupID, models.MembershipRoleOwner)
+	if herr != nil {
+		return nil, herr
+	}
+	if parentID != nil {
+		herr = groupController.authorizeOnGroup(r, *parentID, models.MembershipRoleAdmin)
+		if herr != nil {
+			return nil, herr
+		}
+	}
+	return nil, groupController.groupService.SetGroupParent(groupID, parentID)
+}
+
+// Delete a group, only for its owners
+func (groupController *groupController) DeleteGroup(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	groupID, herr := getUUIDFromPath(r, "id")
+	if herr != nil {
+		return nil, herr
+	}
+	herr = groupController.authorizeOnGroup(r, groupID, models.MembershipRoleOwner)
+	if herr != nil {
+		return nil, herr
+	}
+	return nil, groupController.groupService.DeleteGroup(groupID)
+}
+
+// List the direct members of a group, only for its members
+func (groupController *groupController) ListGroupMembers(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	groupID, herr := getUUIDFromPath(r, "id")
+	if herr != nil {
+		return nil, herr
+	}
+	herr = groupController.authorizeOnGroup(r, groupID, models.MembershipRoleMember)
+	if herr != nil {
+		return nil, herr
+	}
+	members, herr := groupController.groupService.GetGroupMembers(groupID)
+	if herr != nil {
+		return nil, herr
+	}
+	dtoMembers := make([]dto.DTOMember, 0, len(members))
+	for _, member := range members {
+		dtoMembers = append(dtoMembers, dto.DTOMember{UserID: member.UserID.String(), Role: member.Role})
+	}
+	return dtoMembers, nil
+}
+
+// Add a member to a group or change its role
+//
+// The admins can manage the members and the admins, only the owners can manage the owners.
+func (groupController *groupController) AddGroupMember(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	groupID, herr := getUUIDFromPath(r, "id")
+	if herr != nil {
+		return nil, herr
+	}
+	userID, role, herr := decodeMember(r)
+	if herr != nil {
+		return nil, herr
+	}
+	currentRole, herr := groupController.getGroupMemberRole(groupID, userID)
+	if herr != nil {
+		return nil, herr
+	}
+	herr = groupController.authorizeOnGroup(r, groupID, requiredRoleToManage(currentRole, role))
+	if herr != nil {
+		return nil, herr
+	}
+	return nil, groupController.groupService.AddGroupMember(groupID, userID, role)
+}
+
+// Remove a member from a group
+func (groupController *groupController) RemoveGroupMember(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	groupID, herr := getUUIDFromPath(r, "id")
+	if herr != nil {
+		return nil, herr
+	}
+	userID, herr := getUUIDFromPath(r, "userID")
+	if herr != nil {
+		return nil, herr
+	}
+	currentRole, herr := groupController.getGroupMemberRole(groupID, userID)
+	if herr != nil {
+		return nil, herr
+	}
+	herr = groupController.authorizeOnGroup(r, groupID, requiredRoleToManage(currentRole, ""))
+	if herr != nil {
+		return nil, herr
+	}
+	return nil, groupController.groupService.RemoveGroupMember(groupID, userID)
+}
+
+// Return the role of a direct member of a group, empty if the user is not a direct member
+func (groupController *groupController) getGroupMemberRole(groupID, userID uuid.UUID) (string, httperrors.HTTPError) {
+	members, herr := groupController.groupService.GetGroupMembers(groupID)
+	if herr != nil {
+		return "", herr
+	}
+	for _, member := range members {
+		if member.UserID == userID {
+			return member.Role, nil
+		}
+	}
+	return "", nil
+}
+
+// Only let the user through if its membership role in the organisation includes the required role
+func (groupController *groupController) authorizeOnOrganisation(
+	r *http.Request,
+	organisationID uuid.UUID,
+	requiredRole string,
+) httperrors.HTTPError {
+	return groupController.authorize(r, requiredRole, func(userID uuid.UUID) (string, httperrors.HTTPError) {
+		return groupController.groupService.GetOrganisationRole(userID, organisationID)
+	})
+}
+
+// Only let the user through if its membership role on the group includes the required role
+func (groupController *groupController) authorizeOnGroup(
+	r *http.Request,
+	groupID uuid.UUID,
+	requiredRole string,
+) httperrors.HTTPError {
+	return groupController.authorize(r, requiredRole, func(userID uuid.UUID) (string, httperrors.HTTPError) {
+		return groupController.groupService.GetGroupRole(userID, groupID)
+	})
+}
+
+// Return nil if the user has been granted the "groups:manage" permission
+// or if the membership role returned by getRole includes the required role.
+//
+// Only the permission is checked if getRole is nil. The requests made with an API key
+// are refused unless one of its scopes allows the "groups:manage" permission.
+func (groupController *groupController) authorize(
+	r *http.Request,
+	requiredRole string,
+	getRole func(userID uuid.UUID) (string, httperrors.HTTPError),
+) httperrors.HTTPError {
+	sessionClaims := sessionservice.GetSessionClaimsFromContext(r.Context())
+	if !rbacservice.ClaimsAllow(sessionClaims, groupservice.PermissionGroupsManage) {
+		return rbacservice.HERRPermissionDenied
+	}
+	userID := sessionClaims.UserID
+	ok, herr := groupController.rbacService.HasPermission(userID, groupservice.PermissionGroupsManage)
+	if herr != nil {
+		return herr
+	}
+	if ok {
+		return nil
+	}
+	if getRole == nil {
+		return rbacservice.HERRPermissionDenied
+	}
+	role, herr := getRole(userID)
+	if herr != nil {
+		return herr
+	}
+	if !models.MembershipRoleIncludes(role, requiredRole) {
+		return rbacservice.HERRPermissionDenied
+	}
+	return nil
+}
+
+// Return the membership role needed to change the role of a member from currentRole to newRole,
+// an empty newRole meaning that the member is removed.
+func requiredRoleToManage(currentRole, newRole string) string {
+	if currentRole == models.MembershipRoleOwner || newRole == models.MembershipRoleOwner {
+		return models.MembershipRoleOwner
+	}
+	return models.MembershipRoleAdmin
+}
+
+// Decode a DTOMember from the body of the request
+func decodeMember(r *http.Request) (uuid.UUID, string, httperrors.HTTPError) {
+	var memberDTO dto.DTOMember
+	herr := decodeJSON(r, &memberDTO)
+	if herr != nil {
+		return uuid.Nil, "", herr
+	}
+	userID, err := uuid.Parse(memberDTO.UserID)
+	if err != nil {
+		return uuid.Nil, "", HTTPErrRequestMalformed
+	}
+	if memberDTO.Role == "" {
+		memberDTO.Role = models.MembershipRoleMember
+	}
+	return userID, memberDTO.Role, nil
+}
+
+// Create a DTOOrganisation from an organisation
+func makeDTOOrganisation(organisation *models.Organisation) dto.DTOOrganisation {
+	return dto.DTOOrganisation{
+		ID:          organisation.ID.String(),
+		Name:        organisation.Name,
+		Description: organisation.Description,
+	}
+}
+
+// Create a DTOGroup from a group
+func makeDTOGroup(group *models.Group) dto.DTOGroup {
+	dtoGroup := dto.DTOGroup{
+		ID:          group.ID.String(),
+		Name:        group.Name,
+		Description: group.Description,
+	}
+	if group.OrganisationID != nil {
+		dtoGroup.OrganisationID = group.OrganisationID.String()
+	}
+	if group.ParentID != nil {
+		dtoGroup.ParentID = group.ParentID.String()
+	}
+	return dtoGroup
+}
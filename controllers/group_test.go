@@ -0,0 +1,155 @@
+package controllers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ditrit/badaas/controllers"
+	mocksGroupService "github.com/ditrit/badaas/mocks/services/groupservice"
+	mocksRBACService "github.com/ditrit/badaas/mocks/services/rbacservice"
+	"github.com/ditrit/badaas/persistence/models"
+	"github.com/ditrit/badaas/persistence/models/dto"
+	"github.com/ditrit/badaas/services/groupservice"
+	"github.com/ditrit/badaas/services/rbacservice"
+	"github.com/ditrit/badaas/services/sessionservice"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// make a request sent by the user
+func makeAuthenticatedRequest(userID uuid.UUID, method, target, body string, vars map[string]string) *http.Request {
+	request := httptest.NewRequest(method, target, strings.NewReader(body))
+	request = mux.SetURLVars(request, vars)
+	return request.WithContext(sessionservice.SetSessionClaimsContext(
+		request.Context(), &sessionservice.SessionClaims{UserID: userID}))
+}
+
+func Test_ListOrganisations_PermissionDenied(t *testing.T) {
+	userID := uuid.New()
+	groupService := mocksGroupService.NewGroupService(t)
+	rbacService := mocksRBACService.NewRBACService(t)
+	rbacService.On("HasPermission", userID, groupservice.PermissionGroupsManage).Return(false, nil)
+
+	controller := controllers.NewGroupController(zap.L(), groupService, rbacService)
+	request := makeAuthenticatedRequest(userID, "GET", "/organisations", "", nil)
+
+	payload, err := controller.ListOrganisations(httptest.NewRecorder(), request)
+	assert.Equal(t, rbacservice.HERRPermissionDenied, err)
+	assert.Nil(t, payload)
+}
+
+func Test_GetGroup_APIKeyWithoutScope(t *testing.T) {
+	// the membership roles of the user are not enough for an API key
+	groupID := uuid.New()
+	controller := controllers.NewGroupController(zap.L(), mocksGroupService.NewGroupService(t), mocksRBACService.NewRBACService(t))
+	request := mux.SetURLVars(httptest.NewRequest("GET", "/groups/"+groupID.String(), nil), map[string]string{"id": groupID.String()})
+	request = request.WithContext(sessionservice.SetSessionClaimsContext(request.Context(),
+		&sessionservice.SessionClaims{UserID: uuid.New(), APIKeyID: uuid.New(), Scopes: []string{"users:manage"}}))
+
+	payload, err := controller.GetGroup(httptest.NewRecorder(), request)
+	assert.Equal(t, rbacservice.HERRPermissionDenied, err)
+	assert.Nil(t, payload)
+}
+
+func Test_CreateOrganisation(t *testing.T) {
+	userID := uuid.New()
+	organisation := &models.Organisation{BaseModel: models.BaseModel{ID: uuid.New()}, Name: "acme"}
+	groupService := mocksGroupService.NewGroupService(t)
+	groupService.On("CreateOrganisation", "acme", "").Return(organisation, nil)
+	rbacService := mocksRBACService.NewRBACService(t)
+	rbacService.On("HasPermission", userID, groupservice.PermissionGroupsManage).Return(true, nil)
+
+	controller := controllers.NewGroupController(zap.L(), groupService, rbacService)
+	request := makeAuthenticatedRequest(userID, "POST", "/organisations", `{"name": "acme"}`, nil)
+
+	payload, err := controller.CreateOrganisation(httptest.NewRecorder(), request)
+	assert.NoError(t, err)
+	assert.Equal(t, dto.DTOOrganisation{ID: organisation.ID.String(), Name: "acme"}, payload)
+}
+
+func Test_CreateGroup_AsOrganisationAdmin(t *testing.T) {
+	userID := uuid.New()
+	organisationID := uuid.New()
+	group := &models.Group{BaseModel: models.BaseModel{ID: uuid.New()}, Name: "team", OrganisationID: &organisationID}
+	groupService := mocksGroupService.NewGroupService(t)
+	groupService.On("GetOrganisationRole", userID, organisationID).Return(models.MembershipRoleAdmin, nil)
+	groupService.On("CreateGroup", "team", "", &organisationID, (*uuid.UUID)(nil)).Return(group, nil)
+	rbacService := mocksRBACService.NewRBACService(t)
+	rbacService.On("HasPermission", userID, groupservice.PermissionGroupsManage).Return(false, nil)
+
+	controller := controllers.NewGroupController(zap.L(), groupService, rbacService)
+	request := makeAuthenticatedRequest(userID, "POST", "/groups",
+		`{"name": "team", "organisationId": "`+organisationID.String()+`"}`, nil)
+
+	payload, err := controller.CreateGroup(httptest.NewRecorder(), request)
+	assert.NoError(t, err)
+	assert.Equal(t, dto.DTOGroup{
+		ID:             group.ID.String(),
+		Name:           "team",
+		OrganisationID: organisationID.String(),
+	}, payload)
+}
+
+func Test_AddGroupMember_AdminCannotAddOwner(t *testing.T) {
+	userID := uuid.New()
+	newMemberID := uuid.New()
+	groupID := uuid.New()
+	groupService := mocksGroupService.NewGroupService(t)
+	groupService.On("GetGroupMembers", groupID).Return([]*models.GroupMember{}, nil)
+	groupService.On("GetGroupRole", userID, groupID).Return(models.MembershipRoleAdmin, nil)
+	rbacService := mocksRBACService.NewRBACService(t)
+	rbacService.On("HasPermission", userID, groupservice.PermissionGroupsManage).Return(false, nil)
+
+	controller := controllers.NewGroupController(zap.L(), groupService, rbacService)
+	request := makeAuthenticatedRequest(userID, "POST", "/groups/"+groupID.String()+"/members",
+		`{"userId": "`+newMemberID.String()+`", "role": "owner"}`, map[string]string{"id": groupID.String()})
+
+	payload, err := controller.AddGroupMember(httptest.NewRecorder(), request)
+	assert.Equal(t, rbacservice.HERRPermissionDenied, err)
+	assert.Nil(t, payload)
+	groupService.AssertNotCalled(t, "AddGroupMember")
+}
+
+func Test_AddGroupMember(t *testing.T) {
+	userID := uuid.New()
+	newMemberID := uuid.New()
+	groupID := uuid.New()
+	groupService := mocksGroupService.NewGroupService(t)
+	groupService.On("GetGroupMembers", groupID).Return([]*models.GroupMember{}, nil)
+	groupService.On("GetGroupRole", userID, groupID).Return(models.MembershipRoleAdmin, nil)
+	groupService.On("AddGroupMember", groupID, newMemberID, models.MembershipRoleMember).Return(nil)
+	rbacService := mocksRBACService.NewRBACService(t)
+	rbacService.On("HasPermission", userID, groupservice.PermissionGroupsManage).Return(false, nil)
+
+	controller := controllers.NewGroupController(zap.L(), groupService, rbacService)
+	request := makeAuthenticatedRequest(userID, "POST", "/groups/"+groupID.String()+"/members",
+		`{"userId": "`+newMemberID.String()+`"}`, map[string]string{"id": groupID.String()})
+
+	payload, err := controller.AddGroupMember(httptest.NewRecorder(), request)
+	assert.NoError(t, err)
+	assert.Nil(t, payload)
+}
+
+func Test_RemoveGroupMember_MemberCannotRemove(t *testing.T) {
+	userID := uuid.New()
+	memberID := uuid.New()
+	groupID := uuid.New()
+	groupService := mocksGroupService.NewGroupService(t)
+	groupService.On("GetGroupMembers", groupID).Return([]*models.GroupMember{
+		{GroupID: groupID, UserID: memberID, Role: models.MembershipRoleMember},
+	}, nil)
+	groupService.On("GetGroupRole", userID, groupID).Return(models.MembershipRoleMember, nil)
+	rbacService := mocksRBACService.NewRBACService(t)
+	rbacService.On("HasPermission", userID, groupservice.PermissionGroupsManage).Return(false, nil)
+
+	controller := controllers.NewGroupController(zap.L(), groupService, rbacService)
+	request := makeAuthenticatedRequest(userID, "DELETE", "/groups/"+groupID.String()+"/members/"+memberID.String(),
+		"", map[string]string{"id": groupID.String(), "userID": memberID.String()})
+
+	_, err := controller.RemoveGroupMember(httptest.NewRecorder(), request)
+	assert.Equal(t, rbacservice.HERRPermissionDenied, err)
+}
@@ -0,0 +1,193 @@
+package controllers
+
+import (
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/ditrit/badaas/httperrors"
+	"github.com/ditrit/badaas/persistence/models"
+	"github.com/ditrit/badaas/persistence/models/dto"
+	"github.com/ditrit/badaas/services/loginthrottlingservice"
+	"github.com/ditrit/badaas/services/sessionservice"
+	"github.com/ditrit/badaas/services/twofactorservice"
+	"github.com/ditrit/badaas/services/webauthnservice"
+	"go.uber.org/zap"
+)
+
+// The second factors listed in the login challenges
+const (
+	secondFactorTOTP     = "totp"
+	secondFactorWebAuthn = "webauthn"
+)
+
+// Complete the logins of the users authenticated by a first factor, shared by the login controllers
+//
+// The users who have a second factor, or whose roles require one, get a challenge instead of a session.
+// The failed attempts on an account are only forgotten once its login is complete.
+type LoginGate interface {
+	// Log the user in, or return a challenge if a second factor is needed
+	LogUserIn(user *models.User, r *http.Request, w http.ResponseWriter) (any, httperrors.HTTPError)
+	// Create the session of a user whose factors have all been checked
+	CompleteLogin(user *models.User, r *http.Request, w http.ResponseWriter) (dto.DTOLoginSuccess, httperrors.HTTPError)
+	// Refuse a login attempt on the account from the client of the request while they are throttled
+	CheckAttempt(email string, r *http.Request, w http.ResponseWriter) httperrors.HTTPError
+	// Count a wrong password or second factor as a failed login attempt on the account
+	RecordFailure(email string, r *http.Request)
+}
+
+// Check interface compliance
+var _ LoginGate = (*loginGate)(nil)
+
+// LoginGate implementation
+type loginGate struct {
+	logger                 *zap.Logger
+	sessionService         sessionservice.SessionService
+	loginThrottlingService loginthrottlingservice.LoginThrottlingService
+	twoFactorService       twofactorservice.TwoFactorService
+	webAuthnService        webauthnservice.WebAuthnService
+}
+
+// LoginGate constructor
+func NewLoginGate(
+	logger *zap.Logger,
+	sessionService sessionservice.SessionService,
+	loginThrottlingService loginthrottlingservice.LoginThrottlingService,
+	twoFactorService twofactorservice.TwoFactorService,
+	webAuthnService webauthnservice.WebAuthnService,
+) LoginGate {
+	return &loginGate{
+		logger:                 logger,
+		sessionService:         sessionService,
+		loginThrottlingService: loginThrottlingService,
+		twoFactorService:       twoFactorService,
+		webAuthnService:        webAuthnService,
+	}
+}
+
+// Log the user in, or return a challenge if a second factor is needed
+func (loginGate *loginGate) LogUserIn(user *models.User, r *http.Request, w http.ResponseWriter) (any, httperrors.HTTPError) {
+	challenge, herr := loginGate.getSecondFactorChallenge(user)
+	if herr != nil {
+		return nil, herr
+	}
+	if challenge != nil {
+		return challenge, nil
+	}
+	loginSuccess, herr := loginGate.CompleteLogin(user, r, w)
+	if herr != nil {
+		return nil, herr
+	}
+	return loginSuccess, nil
+}
+
+// Create the session of a user whose factors have all been checked and forget the failed attempts on its account
+func (loginGate *loginGate) CompleteLogin(
+	user *models.User,
+	r *http.Request,
+	w http.ResponseWriter,
+) (dto.DTOLoginSuccess, httperrors.HTTPError) {
+	herr := loginGate.sessionService.LogUserIn(user, r, w)
+	if herr != nil {
+		return dto.DTOLoginSuccess{}, herr
+	}
+	herr = loginGate.loginThrottlingService.ResetAccount(user.Email)
+	if herr != nil {
+		loginGate.logger.Error("Failed to reset the failed login attempts", zap.Error(herr))
+	}
+	return makeDTOLoginSuccess(user), nil
+}
+
+// Refuse a login attempt on the account from the client of the request while they are throttled
+//
+// The Retry-After header tells when the next attempt is allowed.
+func (loginGate *loginGate) CheckAttempt(email string, r *http.Request, w http.ResponseWriter) httperrors.HTTPError {
+	retryAfter, herr := loginGate.loginThrottlingService.Check(email, sessionservice.GetClientIP(r))
+	if herr != nil {
+		if retryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		}
+		return herr
+	}
+	return nil
+}
+
+// Count a wrong password or second factor as a failed login attempt on the account
+func (loginGate *loginGate) RecordFailure(email string, r *http.Request) {
+	herr := loginGate.loginThrottlingService.RecordFailure(email, sessionservice.GetClientIP(r))
+	if herr != nil {
+		loginGate.logger.Error("Failed to record a failed login attempt", zap.Error(herr))
+	}
+}
+
+// Return a challenge if the user has to give a second factor, or nil
+//
+// The WebAuthn credentials of the user are accepted as second factors as well as its TOTP authenticator.
+func (loginGate *loginGate) getSecondFactorChallenge(user *models.User) (*dto.DTOLoginChallenge, httperrors.HTTPError) {
+	methods := []string{}
+	totpEnabled, herr := loginGate.twoFactorService.IsEnabled(user.ID)
+	if herr != nil {
+		return nil, herr
+	}
+	if totpEnabled {
+		methods = append(methods, secondFactorTOTP)
+	}
+	webAuthnEnabled, herr := loginGate.webAuthnService.HasCredentials(user.ID)
+	if herr != nil {
+		return nil, herr
+	}
+	if webAuthnEnabled {
+		methods = append(methods, secondFactorWebAuthn)
+	}
+	enabled := totpEnabled || webAuthnEnabled
+	required := false
+	if !enabled {
+		required, herr = loginGate.twoFactorService.IsRequired(user.ID)
+		if herr != nil {
+			return nil, herr
+		}
+	}
+	if !enabled && !required {
+		return nil, nil
+	}
+	token, herr := loginGate.twoFactorService.CreateChallenge(user.ID, !enabled)
+	if herr != nil {
+		return nil, herr
+	}
+	return &dto.DTOLoginChallenge{
+		TwoFactorRequired: enabled,
+		EnrolmentRequired: !enabled,
+		Methods:           methods,
+		Challenge:         token,
+	}, nil
+}
+
+// Redirect the user logged in by an external provider to the configured url, return the payload if empty
+//
+// A challenge is given in the fragment of the url, so that it is not sent to the servers.
+func redirectLogin(w http.ResponseWriter, r *http.Request, loginRedirectURL string, payload any) (any, httperrors.HTTPError) {
+	if loginRedirectURL == "" {
+		return payload, nil
+	}
+	if challenge, ok := payload.(*dto.DTOLoginChallenge); ok {
+		fragment := url.Values{}
+		fragment.Set("challenge", challenge.Challenge)
+		fragment.Set("twoFactorRequired", strconv.FormatBool(challenge.TwoFactorRequired))
+		fragment.Set("enrolmentRequired", strconv.FormatBool(challenge.EnrolmentRequired))
+		fragment.Set("methods", strings.Join(challenge.Methods, ","))
+		loginRedirectURL += "#" + fragment.Encode()
+	}
+	http.Redirect(w, r, loginRedirectURL, http.StatusSeeOther)
+	return nil, nil
+}
+
+// Describe the user of a new session
+func makeDTOLoginSuccess(user *models.User) dto.DTOLoginSuccess {
+	return dto.DTOLoginSuccess{
+		Email:    user.Email,
+		ID:       user.ID.String(),
+		Username: user.Username,
+	}
+}
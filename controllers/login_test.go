@@ -0,0 +1,64 @@
+package controllers_test
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ditrit/badaas/controllers"
+	mocksLoginThrottlingService "github.com/ditrit/badaas/mocks/services/loginthrottlingservice"
+	mocksSessionService "github.com/ditrit/badaas/mocks/services/sessionservice"
+	mocksTwoFactorService "github.com/ditrit/badaas/mocks/services/twofactorservice"
+	mocksWebAuthnService "github.com/ditrit/badaas/mocks/services/webauthnservice"
+	"github.com/ditrit/badaas/persistence/models"
+	"github.com/ditrit/badaas/persistence/models/dto"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func Test_LoginGate_CompleteLoginResetsTheAccount(t *testing.T) {
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Username: "bob", Email: "bob@email.com"}
+	sessionService := mocksSessionService.NewSessionService(t)
+	sessionService.On("LogUserIn", user, mock.Anything, mock.Anything).Return(nil)
+	loginThrottlingService := mocksLoginThrottlingService.NewLoginThrottlingService(t)
+	loginThrottlingService.On("ResetAccount", "bob@email.com").Return(nil)
+
+	loginGate := controllers.NewLoginGate(zap.L(), sessionService, loginThrottlingService,
+		mocksTwoFactorService.NewTwoFactorService(t), mocksWebAuthnService.NewWebAuthnService(t))
+
+	payload, err := loginGate.CompleteLogin(user, httptest.NewRequest("POST", "/login", nil), httptest.NewRecorder())
+	assert.NoError(t, err)
+	assert.Equal(t, dto.DTOLoginSuccess{ID: user.ID.String(), Username: "bob", Email: "bob@email.com"}, payload)
+}
+
+func Test_LoginGate_ChallengeKeepsTheFailedAttempts(t *testing.T) {
+	// the failed attempts must not be forgotten before the second factor is checked
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Email: "bob@email.com"}
+	twoFactorService := mocksTwoFactorService.NewTwoFactorService(t)
+	twoFactorService.On("IsEnabled", user.ID).Return(true, nil)
+	twoFactorService.On("CreateChallenge", user.ID, false).Return("challenge", nil)
+	webAuthnService := mocksWebAuthnService.NewWebAuthnService(t)
+	webAuthnService.On("HasCredentials", user.ID).Return(false, nil)
+
+	loginGate := controllers.NewLoginGate(zap.L(), mocksSessionService.NewSessionService(t),
+		mocksLoginThrottlingService.NewLoginThrottlingService(t), twoFactorService, webAuthnService)
+
+	payload, err := loginGate.LogUserIn(user, httptest.NewRequest("POST", "/login", nil), httptest.NewRecorder())
+	assert.NoError(t, err)
+	assert.Equal(t, &dto.DTOLoginChallenge{
+		TwoFactorRequired: true,
+		Methods:           []string{"totp"},
+		Challenge:         "challenge",
+	}, payload)
+}
+
+func Test_LoginGate_RecordFailure(t *testing.T) {
+	loginThrottlingService := mocksLoginThrottlingService.NewLoginThrottlingService(t)
+	loginThrottlingService.On("RecordFailure", "bob@email.com", "192.0.2.1").Return(nil)
+
+	loginGate := controllers.NewLoginGate(zap.L(), mocksSessionService.NewSessionService(t),
+		loginThrottlingService, mocksTwoFactorService.NewTwoFactorService(t), mocksWebAuthnService.NewWebAuthnService(t))
+
+	loginGate.RecordFailure("bob@email.com", httptest.NewRequest("POST", "/login/2fa", nil))
+}
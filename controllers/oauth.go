@@ -0,0 +1,228 @@
+package controllers
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/ditrit/badaas/httperrors"
+	"github.com/ditrit/badaas/persistence/models"
+	"github.com/ditrit/badaas/persistence/models/dto"
+	"github.com/ditrit/badaas/services/auth/protocols/oauth2"
+	"github.com/ditrit/badaas/services/oauthservice"
+	"github.com/ditrit/badaas/services/sessionservice"
+	"go.uber.org/zap"
+)
+
+// OAuth 2.0 authorization server Controller
+//
+// Authorize and Consent are called by the consent screen of the frontend, with the session of the user.
+// Token, Introspect and Revoke are called by the clients, authenticated with their credentials.
+type OAuthController interface {
+	Authorize(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	Consent(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	Token(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	Introspect(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	Revoke(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	ListClients(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	CreateClient(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	GetClient(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	DeleteClient(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+}
+
+// Check interface compliance
+var _ OAuthController = (*oauthController)(nil)
+
+// OAuthController implementation
+type oauthController struct {
+	logger       *zap.Logger
+	oauthService oauthservice.OAuthService
+}
+
+// OAuthController constructor
+func NewOAuthController(
+	logger *zap.Logger,
+	oauthService oauthservice.OAuthService,
+) OAuthController {
+	return &oauthController{
+		logger:       logger,
+		oauthService: oauthService,
+	}
+}
+
+// Check an authorization request
+//
+// The user agent is redirected to the client if the user already consented to the scopes or if the request is wrong,
+// the consent to ask is returned otherwise.
+func (oauthController *oauthController) Authorize(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	request := getAuthorizationRequest(r.URL.Query())
+	userID := sessionservice.GetSessionClaimsFromContext(r.Context()).UserID
+	authorization, herr := oauthController.oauthService.Authorize(userID, request)
+	if herr != nil {
+		return nil, herr
+	}
+	if authorization.RedirectURL != "" {
+		http.Redirect(w, r, authorization.RedirectURL, http.StatusFound)
+		return nil, nil
+	}
+	scopes := make([]dto.DTOOAuthScope, 0, len(authorization.Scopes))
+	for _, scope := range authorization.Scopes {
+		scopes = append(scopes, dto.DTOOAuthScope{Name: scope.Name, Description: scope.Description})
+	}
+	return dto.DTOOAuthConsent{
+		ClientID:   authorization.Client.ID.String(),
+		ClientName: authorization.Client.Name,
+		Scopes:     scopes,
+		Request:    request,
+	}, nil
+}
+
+// Record the decision of the user on the consent screen, return the url the user agent is redirected to
+func (oauthController *oauthController) Consent(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	var decision dto.DTOOAuthConsentDecision
+	herr := decodeJSON(r, &decision)
+	if herr != nil {
+		return nil, herr
+	}
+	userID := sessionservice.GetSessionClaimsFromContext(r.Context()).UserID
+	redirectURL, herr := oauthController.oauthService.Consent(userID, decision.Request, decision.Approved)
+	if herr != nil {
+		return nil, herr
+	}
+	return dto.DTOOAuthRedirect{RedirectTo: redirectURL}, nil
+}
+
+// Exchange a grant for tokens (RFC 6749 section 3.2)
+func (oauthController *oauthController) Token(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	credentials, herr := getClientRequest(r)
+	if herr != nil {
+		return nil, herr
+	}
+	token, herr := oauthController.oauthService.Token(credentials, dto.DTOOAuthTokenRequest{
+		GrantType:    r.PostForm.Get("grant_type"),
+		Code:         r.PostForm.Get("code"),
+		RedirectURI:  r.PostForm.Get("redirect_uri"),
+		CodeVerifier: r.PostForm.Get("code_verifier"),
+		RefreshToken: r.PostForm.Get("refresh_token"),
+		Scope:        r.PostForm.Get("scope"),
+	})
+	if herr != nil {
+		return nil, herr
+	}
+	// the tokens must not be cached (RFC 6749 section 5.1)
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Pragma", "no-cache")
+	return token, nil
+}
+
+// Describe a token to a confidential client (RFC 7662)
+func (oauthController *oauthController) Introspect(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	credentials, herr := getClientRequest(r)
+	if herr != nil {
+		return nil, herr
+	}
+	introspection, herr := oauthController.oauthService.Introspect(credentials, r.PostForm.Get("token"))
+	if herr != nil {
+		return nil, herr
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	return introspection, nil
+}
+
+// Revoke a token of the client (RFC 7009)
+func (oauthController *oauthController) Revoke(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	credentials, herr := getClientRequest(r)
+	if herr != nil {
+		return nil, herr
+	}
+	return nil, oauthController.oauthService.Revoke(credentials, r.PostForm.Get("token"))
+}
+
+// List the registered clients
+func (oauthController *oauthController) ListClients(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	clients, herr := oauthController.oauthService.GetClients()
+	if herr != nil {
+		return nil, herr
+	}
+	dtoClients := make([]dto.DTOOAuthClient, 0, len(clients))
+	for _, client := range clients {
+		dtoClients = append(dtoClients, makeDTOOAuthClient(client))
+	}
+	return dtoClients, nil
+}
+
+// Register a client, its secret is only returned in the response
+func (oauthController *oauthController) CreateClient(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	var createClientDTO dto.DTOCreateOAuthClient
+	herr := decodeJSON(r, &createClientDTO)
+	if herr != nil {
+		return nil, herr
+	}
+	client, secret, herr := oauthController.oauthService.CreateClient(createClientDTO)
+	if herr != nil {
+		return nil, herr
+	}
+	dtoClient := makeDTOOAuthClient(client)
+	dtoClient.ClientSecret = secret
+	return dtoClient, nil
+}
+
+// Return a client
+func (oauthController *oauthController) GetClient(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	clientID, herr := getUUIDFromPath(r, "id")
+	if herr != nil {
+		return nil, herr
+	}
+	client, herr := oauthController.oauthService.GetClient(clientID)
+	if herr != nil {
+		return nil, herr
+	}
+	return makeDTOOAuthClient(client), nil
+}
+
+// Delete a client, its tokens are revoked
+func (oauthController *oauthController) DeleteClient(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	clientID, herr := getUUIDFromPath(r, "id")
+	if herr != nil {
+		return nil, herr
+	}
+	return nil, oauthController.oauthService.DeleteClient(clientID)
+}
+
+// Read the parameters of an authorization request
+func getAuthorizationRequest(query url.Values) dto.DTOOAuthAuthorizationRequest {
+	return dto.DTOOAuthAuthorizationRequest{
+		ResponseType:        query.Get("response_type"),
+		ClientID:            query.Get("client_id"),
+		RedirectURI:         query.Get("redirect_uri"),
+		Scope:               query.Get("scope"),
+		State:               query.Get("state"),
+		CodeChallenge:       query.Get("code_challenge"),
+		CodeChallengeMethod: query.Get("code_challenge_method"),
+	}
+}
+
+// Parse the form of a request of a client, return the client credentials
+func getClientRequest(r *http.Request) (oauth2.ClientCredentials, httperrors.HTTPError) {
+	err := r.ParseForm()
+	if err != nil {
+		return oauth2.ClientCredentials{}, oauthservice.NewOAuthError(oauth2.ErrorInvalidRequest, "the form is malformed")
+	}
+	credentials, err := oauth2.GetClientCredentials(r)
+	if err != nil {
+		return oauth2.ClientCredentials{}, oauthservice.NewOAuthError(oauth2.ErrorInvalidClient,
+			"the client must authenticate with a single method")
+	}
+	return credentials, nil
+}
+
+// Create an OAuth client DTO
+func makeDTOOAuthClient(client *models.OAuthClient) dto.DTOOAuthClient {
+	return dto.DTOOAuthClient{
+		ID:           client.ID.String(),
+		Name:         client.Name,
+		Public:       client.IsPublic(),
+		RedirectURIs: client.GetRedirectURIs(),
+		GrantTypes:   oauth2.ParseScope(client.GrantTypes),
+		Scopes:       oauth2.ParseScope(client.Scope),
+	}
+}
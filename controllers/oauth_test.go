@@ -0,0 +1,153 @@
+package controllers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ditrit/badaas/configuration"
+	"github.com/ditrit/badaas/controllers"
+	mocksOAuthService "github.com/ditrit/badaas/mocks/services/oauthservice"
+	"github.com/ditrit/badaas/persistence/models"
+	"github.com/ditrit/badaas/persistence/models/dto"
+	"github.com/ditrit/badaas/services/auth/protocols/oauth2"
+	"github.com/ditrit/badaas/services/oauthservice"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func Test_OAuthAuthorizeAsksConsent(t *testing.T) {
+	userID := uuid.New()
+	client := &models.OAuthClient{BaseModel: models.BaseModel{ID: uuid.New()}, Name: "web app"}
+	request := dto.DTOOAuthAuthorizationRequest{
+		ResponseType: "code", ClientID: client.ID.String(), Scope: "profile", State: "xyz",
+	}
+	oauthService := mocksOAuthService.NewOAuthService(t)
+	oauthService.On("Authorize", userID, request).Return(&oauthservice.Authorization{
+		Client: client,
+		Scopes: []configuration.OAuthScope{{Name: "profile", Description: "Read your profile"}},
+	}, nil)
+
+	controller := controllers.NewOAuthController(zap.L(), oauthService)
+	payload, herr := controller.Authorize(httptest.NewRecorder(), makeAuthenticatedRequest(userID, "GET",
+		"/oauth/authorize?response_type=code&client_id="+client.ID.String()+"&scope=profile&state=xyz", "", nil))
+	assert.Nil(t, herr)
+	assert.Equal(t, dto.DTOOAuthConsent{
+		ClientID:   client.ID.String(),
+		ClientName: "web app",
+		Scopes:     []dto.DTOOAuthScope{{Name: "profile", Description: "Read your profile"}},
+		Request:    request,
+	}, payload)
+}
+
+func Test_OAuthAuthorizeRedirects(t *testing.T) {
+	userID := uuid.New()
+	oauthService := mocksOAuthService.NewOAuthService(t)
+	oauthService.On("Authorize", userID, dto.DTOOAuthAuthorizationRequest{ClientID: "client"}).
+		Return(&oauthservice.Authorization{RedirectURL: "https://app.example.com/callback?code=abc"}, nil)
+
+	controller := controllers.NewOAuthController(zap.L(), oauthService)
+	response := httptest.NewRecorder()
+	payload, herr := controller.Authorize(response, makeAuthenticatedRequest(userID, "GET",
+		"/oauth/authorize?client_id=client", "", nil))
+	assert.Nil(t, herr)
+	assert.Nil(t, payload)
+	assert.Equal(t, http.StatusFound, response.Code)
+	assert.Equal(t, "https://app.example.com/callback?code=abc", response.Header().Get("Location"))
+}
+
+func Test_OAuthConsent(t *testing.T) {
+	userID := uuid.New()
+	oauthService := mocksOAuthService.NewOAuthService(t)
+	oauthService.On("Consent", userID, dto.DTOOAuthAuthorizationRequest{ResponseType: "code", ClientID: "client"}, true).
+		Return("https://app.example.com/callback?code=abc", nil)
+
+	controller := controllers.NewOAuthController(zap.L(), oauthService)
+	payload, herr := controller.Consent(httptest.NewRecorder(), makeAuthenticatedRequest(userID, "POST",
+		"/oauth/authorize", `{"request": {"response_type": "code", "client_id": "client"}, "approved": true}`, nil))
+	assert.Nil(t, herr)
+	assert.Equal(t, dto.DTOOAuthRedirect{RedirectTo: "https://app.example.com/callback?code=abc"}, payload)
+}
+
+func Test_OAuthToken(t *testing.T) {
+	token := &dto.DTOOAuthToken{AccessToken: "access", TokenType: "Bearer", ExpiresIn: 3600}
+	oauthService := mocksOAuthService.NewOAuthService(t)
+	oauthService.On("Token",
+		oauth2.ClientCredentials{ClientID: "client", ClientSecret: "secret", Basic: true},
+		dto.DTOOAuthTokenRequest{GrantType: "authorization_code", Code: "abc", CodeVerifier: "verifier"},
+	).Return(token, nil)
+
+	controller := controllers.NewOAuthController(zap.L(), oauthService)
+	request := httptest.NewRequest("POST", "/oauth/token",
+		strings.NewReader("grant_type=authorization_code&code=abc&code_verifier=verifier"))
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.SetBasicAuth("client", "secret")
+	response := httptest.NewRecorder()
+
+	payload, herr := controller.Token(response, request)
+	assert.Nil(t, herr)
+	assert.Equal(t, token, payload)
+	assert.Equal(t, "no-store", response.Header().Get("Cache-Control"))
+}
+
+func Test_OAuthTokenTwoAuthenticationMethods(t *testing.T) {
+	controller := controllers.NewOAuthController(zap.L(), mocksOAuthService.NewOAuthService(t))
+	request := httptest.NewRequest("POST", "/oauth/token",
+		strings.NewReader("grant_type=client_credentials&client_secret=secret"))
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.SetBasicAuth("client", "secret")
+	response := httptest.NewRecorder()
+
+	_, herr := controller.Token(response, request)
+	require.NotNil(t, herr)
+	herr.Write(response, zap.L())
+	assert.Equal(t, http.StatusUnauthorized, response.Code)
+	assert.Equal(t, `Basic realm="badaas"`, response.Header().Get("WWW-Authenticate"))
+	assert.JSONEq(t, `{"error": "invalid_client", "error_description": "the client must authenticate with a single method"}`,
+		response.Body.String())
+}
+
+func Test_OAuthRevoke(t *testing.T) {
+	oauthService := mocksOAuthService.NewOAuthService(t)
+	oauthService.On("Revoke", oauth2.ClientCredentials{ClientID: "client"}, "token").Return(nil)
+
+	controller := controllers.NewOAuthController(zap.L(), oauthService)
+	request := httptest.NewRequest("POST", "/oauth/revoke", strings.NewReader("client_id=client&token=token"))
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	payload, herr := controller.Revoke(httptest.NewRecorder(), request)
+	assert.Nil(t, herr)
+	assert.Nil(t, payload)
+}
+
+func Test_CreateOAuthClient(t *testing.T) {
+	client := &models.OAuthClient{
+		BaseModel:  models.BaseModel{ID: uuid.New()},
+		Name:       "batch",
+		SecretHash: "hash",
+		GrantTypes: "client_credentials",
+		Scope:      "profile",
+	}
+	oauthService := mocksOAuthService.NewOAuthService(t)
+	oauthService.On("CreateClient", dto.DTOCreateOAuthClient{
+		Name: "batch", GrantTypes: []string{"client_credentials"}, Scopes: []string{"profile"},
+	}).Return(client, "secret", nil)
+
+	controller := controllers.NewOAuthController(zap.L(), oauthService)
+	request := httptest.NewRequest("POST", "/oauth/clients",
+		strings.NewReader(`{"name": "batch", "grantTypes": ["client_credentials"], "scopes": ["profile"]}`))
+
+	payload, herr := controller.CreateClient(httptest.NewRecorder(), request)
+	assert.Nil(t, herr)
+	assert.Equal(t, dto.DTOOAuthClient{
+		ID:           client.ID.String(),
+		Name:         "batch",
+		RedirectURIs: []string{},
+		GrantTypes:   []string{"client_credentials"},
+		Scopes:       []string{"profile"},
+		ClientSecret: "secret",
+	}, payload)
+}
@@ -0,0 +1,123 @@
+package controllers
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/ditrit/badaas/configuration"
+	"github.com/ditrit/badaas/httperrors"
+	"github.com/ditrit/badaas/persistence/models/dto"
+	"github.com/ditrit/badaas/services/oidcservice"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// The cookie binding an OpenID login to the browser which started it
+const oidcStateCookieName = "oidc_state"
+
+// The path of the OpenID logins, the state cookie is only sent to it
+const oidcLoginPath = "/login/oidc"
+
+// Errors
+var HERRLoginRefused = httperrors.NewUnauthorizedError("login refused", "the login was refused by the OpenID provider")
+
+// OpenID Connect Controller
+//
+// Login redirects the user to its provider, which sends it back to Callback once logged in.
+// The second factor of the user, if any, is then asked like after a password.
+type OIDCController interface {
+	ListProviders(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	Login(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	Callback(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+}
+
+// Check interface compliance
+var _ OIDCController = (*oidcController)(nil)
+
+// OIDCController implementation
+type oidcController struct {
+	logger              *zap.Logger
+	oidcService         oidcservice.OIDCService
+	loginGate           LoginGate
+	oidcConfiguration   configuration.OIDCConfiguration
+	cookieConfiguration configuration.CookieConfiguration
+}
+
+// OIDCController constructor
+func NewOIDCController(
+	logger *zap.Logger,
+	oidcService oidcservice.OIDCService,
+	loginGate LoginGate,
+	oidcConfiguration configuration.OIDCConfiguration,
+	cookieConfiguration configuration.CookieConfiguration,
+) OIDCController {
+	return &oidcController{
+		logger:              logger,
+		oidcService:         oidcService,
+		loginGate:           loginGate,
+		oidcConfiguration:   oidcConfiguration,
+		cookieConfiguration: cookieConfiguration,
+	}
+}
+
+// Return the names of the providers the users can log in with
+func (oidcController *oidcController) ListProviders(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	return dto.DTOOIDCProviders{Providers: oidcController.oidcService.GetProviders()}, nil
+}
+
+// Redirect the user to the provider
+//
+// The state of the login is also set in a cookie, so that the callback can't be completed in another browser.
+func (oidcController *oidcController) Login(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	authorizationURL, state, herr := oidcController.oidcService.BeginLogin(mux.Vars(r)["provider"])
+	if herr != nil {
+		return nil, herr
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcStateCookieName,
+		Value:    state,
+		Path:     oidcLoginPath,
+		MaxAge:   int(oidcController.oidcConfiguration.GetRequestDuration().Seconds()),
+		HttpOnly: true,
+		Secure:   oidcController.cookieConfiguration.GetSecure(),
+		// sent on the redirection of the provider to the callback
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.Redirect(w, r, authorizationURL, http.StatusFound)
+	return nil, nil
+}
+
+// Log the user in once back from the provider, or return a challenge if a second factor is needed
+//
+// The user is redirected to the configured url if any, it is described in JSON otherwise.
+func (oidcController *oidcController) Callback(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	query := r.URL.Query()
+	state := query.Get("state")
+	cookie, err := r.Cookie(oidcStateCookieName)
+	if err != nil || state == "" || subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(state)) != 1 {
+		return nil, oidcservice.HERRInvalidState
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcStateCookieName,
+		Path:     oidcLoginPath,
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   oidcController.cookieConfiguration.GetSecure(),
+		SameSite: http.SameSiteLaxMode,
+	})
+	provider := mux.Vars(r)["provider"]
+	if providerError := query.Get("error"); providerError != "" {
+		oidcController.logger.Info("The OpenID provider refused a login",
+			zap.String("provider", provider), zap.String("error", providerError))
+		return nil, HERRLoginRefused
+	}
+	user, herr := oidcController.oidcService.FinishLogin(provider, state, query.Get("code"))
+	if herr != nil {
+		return nil, herr
+	}
+	payload, herr := oidcController.loginGate.LogUserIn(user, r, w)
+	if herr != nil {
+		return nil, herr
+	}
+	return redirectLogin(w, r, oidcController.oidcConfiguration.GetLoginRedirectURL(), payload)
+}
@@ -0,0 +1,151 @@
+package controllers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ditrit/badaas/controllers"
+	mocksConfiguration "github.com/ditrit/badaas/mocks/configuration"
+	mocksControllers "github.com/ditrit/badaas/mocks/controllers"
+	mocksOIDCService "github.com/ditrit/badaas/mocks/services/oidcservice"
+	"github.com/ditrit/badaas/persistence/models"
+	"github.com/ditrit/badaas/persistence/models/dto"
+	"github.com/ditrit/badaas/services/oidcservice"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// Return a callback request of the provider, with the state cookie if not empty
+func makeOIDCCallbackRequest(query, stateCookie string) *http.Request {
+	request := httptest.NewRequest("GET", "/login/oidc/google/callback?"+query, nil)
+	if stateCookie != "" {
+		request.AddCookie(&http.Cookie{Name: "oidc_state", Value: stateCookie})
+	}
+	return mux.SetURLVars(request, map[string]string{"provider": "google"})
+}
+
+// Return a cookie configuration of secure cookies
+func newSecureCookieConfiguration(t *testing.T) *mocksConfiguration.CookieConfiguration {
+	cookieConfiguration := mocksConfiguration.NewCookieConfiguration(t)
+	cookieConfiguration.On("GetSecure").Return(true).Maybe()
+	return cookieConfiguration
+}
+
+func Test_OIDCLogin(t *testing.T) {
+	oidcService := mocksOIDCService.NewOIDCService(t)
+	oidcService.On("BeginLogin", "google").Return("https://accounts.google.com/authorize?state=state", "state", nil)
+	oidcConfiguration := mocksConfiguration.NewOIDCConfiguration(t)
+	oidcConfiguration.On("GetRequestDuration").Return(10 * time.Minute)
+
+	controller := controllers.NewOIDCController(zap.L(), oidcService, mocksControllers.NewLoginGate(t), oidcConfiguration,
+		newSecureCookieConfiguration(t))
+	request := mux.SetURLVars(httptest.NewRequest("GET", "/login/oidc/google", nil), map[string]string{"provider": "google"})
+	response := httptest.NewRecorder()
+
+	payload, err := controller.Login(response, request)
+	assert.Nil(t, err)
+	assert.Nil(t, payload)
+	assert.Equal(t, http.StatusFound, response.Code)
+	assert.Equal(t, "https://accounts.google.com/authorize?state=state", response.Header().Get("Location"))
+	cookies := response.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.Equal(t, "oidc_state", cookies[0].Name)
+	assert.Equal(t, "state", cookies[0].Value)
+	assert.True(t, cookies[0].HttpOnly)
+	assert.True(t, cookies[0].Secure)
+}
+
+func Test_OIDCCallback(t *testing.T) {
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Username: "bob", Email: "bob@email.com"}
+	oidcService := mocksOIDCService.NewOIDCService(t)
+	oidcService.On("FinishLogin", "google", "state", "code").Return(user, nil)
+	loginGate := mocksControllers.NewLoginGate(t)
+	loginGate.On("LogUserIn", user, mock.Anything, mock.Anything).Return(dto.DTOLoginSuccess{ID: user.ID.String(), Username: user.Username, Email: user.Email}, nil)
+	oidcConfiguration := mocksConfiguration.NewOIDCConfiguration(t)
+	oidcConfiguration.On("GetLoginRedirectURL").Return("")
+
+	controller := controllers.NewOIDCController(zap.L(), oidcService, loginGate, oidcConfiguration, newSecureCookieConfiguration(t))
+	response := httptest.NewRecorder()
+
+	payload, err := controller.Callback(response, makeOIDCCallbackRequest("code=code&state=state", "state"))
+	assert.Nil(t, err)
+	assert.Equal(t, dto.DTOLoginSuccess{ID: user.ID.String(), Username: "bob", Email: "bob@email.com"}, payload)
+	// the state cookie is deleted with the same attributes
+	cookies := response.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.Equal(t, "oidc_state", cookies[0].Name)
+	assert.Equal(t, -1, cookies[0].MaxAge)
+	assert.True(t, cookies[0].Secure)
+}
+
+func Test_OIDCCallback_Redirect(t *testing.T) {
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}}
+	oidcService := mocksOIDCService.NewOIDCService(t)
+	oidcService.On("FinishLogin", "google", "state", "code").Return(user, nil)
+	loginGate := mocksControllers.NewLoginGate(t)
+	loginGate.On("LogUserIn", user, mock.Anything, mock.Anything).Return(dto.DTOLoginSuccess{ID: user.ID.String(), Username: user.Username, Email: user.Email}, nil)
+	oidcConfiguration := mocksConfiguration.NewOIDCConfiguration(t)
+	oidcConfiguration.On("GetLoginRedirectURL").Return("https://app.example.com")
+
+	controller := controllers.NewOIDCController(zap.L(), oidcService, loginGate, oidcConfiguration, newSecureCookieConfiguration(t))
+	response := httptest.NewRecorder()
+
+	payload, err := controller.Callback(response, makeOIDCCallbackRequest("code=code&state=state", "state"))
+	assert.Nil(t, err)
+	assert.Nil(t, payload)
+	assert.Equal(t, http.StatusSeeOther, response.Code)
+	assert.Equal(t, "https://app.example.com", response.Header().Get("Location"))
+}
+
+func Test_OIDCCallback_SecondFactor(t *testing.T) {
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}}
+	oidcService := mocksOIDCService.NewOIDCService(t)
+	oidcService.On("FinishLogin", "google", "state", "code").Return(user, nil)
+	loginGate := mocksControllers.NewLoginGate(t)
+	loginGate.On("LogUserIn", user, mock.Anything, mock.Anything).Return(&dto.DTOLoginChallenge{
+		TwoFactorRequired: true,
+		Methods:           []string{"totp", "webauthn"},
+		Challenge:         "challenge",
+	}, nil)
+	oidcConfiguration := mocksConfiguration.NewOIDCConfiguration(t)
+	oidcConfiguration.On("GetLoginRedirectURL").Return("https://app.example.com")
+
+	controller := controllers.NewOIDCController(zap.L(), oidcService, loginGate, oidcConfiguration, newSecureCookieConfiguration(t))
+	response := httptest.NewRecorder()
+
+	payload, err := controller.Callback(response, makeOIDCCallbackRequest("code=code&state=state", "state"))
+	assert.Nil(t, err)
+	assert.Nil(t, payload)
+	assert.Equal(t, http.StatusSeeOther, response.Code)
+	assert.Equal(t,
+		"https://app.example.com#challenge=challenge&enrolmentRequired=false&methods=totp%2Cwebauthn&twoFactorRequired=true",
+		response.Header().Get("Location"))
+}
+
+func Test_OIDCCallback_StateOfAnotherBrowser(t *testing.T) {
+	controller := controllers.NewOIDCController(zap.L(), mocksOIDCService.NewOIDCService(t),
+		mocksControllers.NewLoginGate(t), mocksConfiguration.NewOIDCConfiguration(t), newSecureCookieConfiguration(t))
+
+	payload, err := controller.Callback(httptest.NewRecorder(), makeOIDCCallbackRequest("code=code&state=state", "other"))
+	assert.Equal(t, oidcservice.HERRInvalidState, err)
+	assert.Nil(t, payload)
+
+	payload, err = controller.Callback(httptest.NewRecorder(), makeOIDCCallbackRequest("code=code&state=state", ""))
+	assert.Equal(t, oidcservice.HERRInvalidState, err)
+	assert.Nil(t, payload)
+}
+
+func Test_OIDCCallback_ProviderError(t *testing.T) {
+	controller := controllers.NewOIDCController(zap.L(), mocksOIDCService.NewOIDCService(t),
+		mocksControllers.NewLoginGate(t), mocksConfiguration.NewOIDCConfiguration(t), newSecureCookieConfiguration(t))
+
+	payload, err := controller.Callback(httptest.NewRecorder(), makeOIDCCallbackRequest("error=access_denied&state=state", "state"))
+	assert.Equal(t, controllers.HERRLoginRefused, err)
+	assert.Nil(t, payload)
+}
@@ -0,0 +1,61 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/ditrit/badaas/httperrors"
+	"github.com/ditrit/badaas/persistence/models/dto"
+	"github.com/ditrit/badaas/services/userservice"
+	"go.uber.org/zap"
+)
+
+// Password reset Controller
+type PasswordResetController interface {
+	ForgotPassword(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	ResetPassword(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+}
+
+// Check interface compliance
+var _ PasswordResetController = (*passwordResetController)(nil)
+
+// PasswordResetController implementation
+type passwordResetController struct {
+	logger      *zap.Logger
+	userService userservice.UserService
+}
+
+// PasswordResetController constructor
+func NewPasswordResetController(
+	logger *zap.Logger,
+	userService userservice.UserService,
+) PasswordResetController {
+	return &passwordResetController{
+		logger:      logger,
+		userService: userService,
+	}
+}
+
+// Send a password reset token, the response doesn't tell if an account uses the email
+func (passwordResetController *passwordResetController) ForgotPassword(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	var forgotPasswordDTO dto.DTOForgotPassword
+	herr := decodeJSON(r, &forgotPasswordDTO)
+	if herr != nil {
+		return nil, herr
+	}
+	herr = passwordResetController.userService.RequestPasswordReset(forgotPasswordDTO.Email)
+	if herr != nil {
+		return nil, herr
+	}
+	w.WriteHeader(http.StatusAccepted)
+	return nil, nil
+}
+
+// Change the password of the user the token was sent to
+func (passwordResetController *passwordResetController) ResetPassword(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	var resetPasswordDTO dto.DTOResetPassword
+	herr := decodeJSON(r, &resetPasswordDTO)
+	if herr != nil {
+		return nil, herr
+	}
+	return nil, passwordResetController.userService.ResetPassword(resetPasswordDTO.Token, resetPasswordDTO.Password)
+}
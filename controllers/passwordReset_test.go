@@ -0,0 +1,40 @@
+package controllers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ditrit/badaas/controllers"
+	mocksUserService "github.com/ditrit/badaas/mocks/services/userservice"
+	"github.com/ditrit/badaas/services/userservice"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func Test_ForgotPassword(t *testing.T) {
+	userService := mocksUserService.NewUserService(t)
+	userService.On("RequestPasswordReset", "bob@email.com").Return(nil)
+
+	controller := controllers.NewPasswordResetController(zap.L(), userService)
+	request := httptest.NewRequest("POST", "/password/forgot", strings.NewReader(`{"email": "bob@email.com"}`))
+	response := httptest.NewRecorder()
+
+	payload, err := controller.ForgotPassword(response, request)
+	assert.NoError(t, err)
+	assert.Nil(t, payload)
+	assert.Equal(t, http.StatusAccepted, response.Code)
+}
+
+func Test_ResetPassword_InvalidToken(t *testing.T) {
+	userService := mocksUserService.NewUserService(t)
+	userService.On("ResetPassword", "token", "5678").Return(userservice.HERRInvalidResetToken)
+
+	controller := controllers.NewPasswordResetController(zap.L(), userService)
+	request := httptest.NewRequest("POST", "/password/reset", strings.NewReader(`{"token": "token", "password": "5678"}`))
+
+	payload, err := controller.ResetPassword(httptest.NewRecorder(), request)
+	assert.Equal(t, userservice.HERRInvalidResetToken, err)
+	assert.Nil(t, payload)
+}
@@ -0,0 +1,102 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/ditrit/badaas/httperrors"
+	"github.com/ditrit/badaas/persistence/models"
+	"github.com/ditrit/badaas/persistence/models/dto"
+	"github.com/ditrit/badaas/services/policyservice"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Attribute based access control policies Controller
+type PolicyController interface {
+	ListPolicies(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	CreatePolicy(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	DeletePolicy(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+}
+
+// Check interface compliance
+var _ PolicyController = (*policyController)(nil)
+
+// PolicyController implementation
+type policyController struct {
+	logger        *zap.Logger
+	policyService policyservice.PolicyService
+}
+
+// PolicyController constructor
+func NewPolicyController(
+	logger *zap.Logger,
+	policyService policyservice.PolicyService,
+) PolicyController {
+	return &policyController{
+		logger:        logger,
+		policyService: policyService,
+	}
+}
+
+// List the policies declared in the configuration and stored in the database
+func (policyController *policyController) ListPolicies(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	policies, herr := policyController.policyService.GetPolicies()
+	if herr != nil {
+		return nil, herr
+	}
+	dtoPolicies := make([]dto.DTOPolicy, 0, len(policies))
+	for _, policy := range policies {
+		dtoPolicies = append(dtoPolicies, makeDTOPolicy(policy))
+	}
+	return dtoPolicies, nil
+}
+
+// Store a new policy in the database
+func (policyController *policyController) CreatePolicy(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	var createPolicyDTO dto.DTOCreatePolicy
+	herr := decodeJSON(r, &createPolicyDTO)
+	if herr != nil {
+		return nil, herr
+	}
+	policy := &models.Policy{
+		Name:        createPolicyDTO.Name,
+		Description: createPolicyDTO.Description,
+		Effect:      createPolicyDTO.Effect,
+		Actions:     strings.Join(createPolicyDTO.Actions, ","),
+		Resources:   strings.Join(createPolicyDTO.Resources, ","),
+		Condition:   createPolicyDTO.Condition,
+	}
+	herr = policyController.policyService.CreatePolicy(policy)
+	if herr != nil {
+		return nil, herr
+	}
+	return makeDTOPolicy(policy), nil
+}
+
+// Delete a policy stored in the database
+func (policyController *policyController) DeletePolicy(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	policyID, herr := getUUIDFromPath(r, "id")
+	if herr != nil {
+		return nil, herr
+	}
+	return nil, policyController.policyService.DeletePolicy(policyID)
+}
+
+// Create a policy DTO
+func makeDTOPolicy(policy *models.Policy) dto.DTOPolicy {
+	dtoPolicy := dto.DTOPolicy{
+		Name:        policy.Name,
+		Description: policy.Description,
+		Effect:      policy.Effect,
+		Actions:     policy.GetActions(),
+		Resources:   policy.GetResources(),
+		Condition:   policy.Condition,
+		Source:      policyservice.PolicySourceConfiguration,
+	}
+	if policy.ID != uuid.Nil {
+		dtoPolicy.ID = policy.ID.String()
+		dtoPolicy.Source = policyservice.PolicySourceDatabase
+	}
+	return dtoPolicy
+}
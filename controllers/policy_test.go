@@ -0,0 +1,104 @@
+package controllers_test
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ditrit/badaas/controllers"
+	mocksPolicyService "github.com/ditrit/badaas/mocks/services/policyservice"
+	"github.com/ditrit/badaas/persistence/models"
+	"github.com/ditrit/badaas/persistence/models/dto"
+	"github.com/ditrit/badaas/services/policyservice"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func Test_ListPolicies(t *testing.T) {
+	core, _ := observer.New(zap.DebugLevel)
+	logger := zap.New(core)
+	policyID := uuid.New()
+	policyService := mocksPolicyService.NewPolicyService(t)
+	policyService.On("GetPolicies").Return([]*models.Policy{
+		{Name: "from-config", Effect: "allow", Actions: "posts:read", Resources: "post"},
+		{BaseModel: models.BaseModel{ID: policyID}, Name: "from-db", Effect: "deny", Actions: "posts:*", Resources: "post,comment"},
+	}, nil)
+
+	controller := controllers.NewPolicyController(logger, policyService)
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest("GET", "/policies", nil)
+
+	payload, err := controller.ListPolicies(response, request)
+	assert.NoError(t, err)
+	assert.Equal(t, []dto.DTOPolicy{
+		{
+			Name:      "from-config",
+			Effect:    "allow",
+			Actions:   []string{"posts:read"},
+			Resources: []string{"post"},
+			Source:    policyservice.PolicySourceConfiguration,
+		},
+		{
+			ID:        policyID.String(),
+			Name:      "from-db",
+			Effect:    "deny",
+			Actions:   []string{"posts:*"},
+			Resources: []string{"post", "comment"},
+			Source:    policyservice.PolicySourceDatabase,
+		},
+	}, payload)
+}
+
+func Test_CreatePolicy(t *testing.T) {
+	core, _ := observer.New(zap.DebugLevel)
+	logger := zap.New(core)
+	policyService := mocksPolicyService.NewPolicyService(t)
+	policyService.On("CreatePolicy", mock.MatchedBy(func(policy *models.Policy) bool {
+		return policy.Name == "readers" && policy.Actions == "posts:read,comments:read"
+	})).Return(nil)
+
+	controller := controllers.NewPolicyController(logger, policyService)
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest("POST", "/policies", strings.NewReader(
+		`{"name": "readers", "effect": "allow", "actions": ["posts:read", "comments:read"], "resources": ["*"]}`,
+	))
+
+	payload, err := controller.CreatePolicy(response, request)
+	assert.NoError(t, err)
+	assert.Equal(t, "readers", payload.(dto.DTOPolicy).Name)
+}
+
+func Test_CreatePolicy_MalformedRequest(t *testing.T) {
+	core, _ := observer.New(zap.DebugLevel)
+	logger := zap.New(core)
+	policyService := mocksPolicyService.NewPolicyService(t)
+
+	controller := controllers.NewPolicyController(logger, policyService)
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest("POST", "/policies", strings.NewReader("qsdqsdqsd"))
+
+	payload, err := controller.CreatePolicy(response, request)
+	assert.Equal(t, controllers.HTTPErrRequestMalformed, err)
+	assert.Nil(t, payload)
+}
+
+func Test_DeletePolicy(t *testing.T) {
+	core, _ := observer.New(zap.DebugLevel)
+	logger := zap.New(core)
+	policyID := uuid.New()
+	policyService := mocksPolicyService.NewPolicyService(t)
+	policyService.On("DeletePolicy", policyID).Return(nil)
+
+	controller := controllers.NewPolicyController(logger, policyService)
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest("DELETE", "/policies/"+policyID.String(), nil)
+	request = mux.SetURLVars(request, map[string]string{"id": policyID.String()})
+
+	payload, err := controller.DeletePolicy(response, request)
+	assert.NoError(t, err)
+	assert.Nil(t, payload)
+}
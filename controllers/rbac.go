@@ -0,0 +1,252 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/ditrit/badaas/httperrors"
+	"github.com/ditrit/badaas/persistence/models"
+	"github.com/ditrit/badaas/persistence/models/dto"
+	"github.com/ditrit/badaas/services/rbacservice"
+	"github.com/ditrit/badaas/services/sessionservice"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// Role based access control Controller
+type RBACController interface {
+	ListRoles(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	CreateRole(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	GetRole(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	DeleteRole(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	GrantPermission(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	RevokePermission(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	GetUserRoles(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	AssignRole(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	UnassignRole(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	GetGroupRoles(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	AssignGroupRole(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	UnassignGroupRole(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+}
+
+// Check interface compliance
+var _ RBACController = (*rbacController)(nil)
+
+// RBACController implementation
+type rbacController struct {
+	logger         *zap.Logger
+	rbacService    rbacservice.RBACService
+	sessionService sessionservice.SessionService
+}
+
+// RBACController constructor
+func NewRBACController(
+	logger *zap.Logger,
+	rbacService rbacservice.RBACService,
+	sessionService sessionservice.SessionService,
+) RBACController {
+	return &rbacController{
+		logger:         logger,
+		rbacService:    rbacService,
+		sessionService: sessionService,
+	}
+}
+
+// List all the roles
+func (rbacController *rbacController) ListRoles(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	roles, herr := rbacController.rbacService.GetRoles()
+	if herr != nil {
+		return nil, herr
+	}
+	dtoRoles := make([]dto.DTORole, 0, len(roles))
+	for _, role := range roles {
+		dtoRoles = append(dtoRoles, makeDTORole(role, nil))
+	}
+	return dtoRoles, nil
+}
+
+// Create a role
+func (rbacController *rbacController) CreateRole(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	var createRoleDTO dto.DTOCreateRole
+	herr := decodeJSON(r, &createRoleDTO)
+	if herr != nil {
+		return nil, herr
+	}
+	role, herr := rbacController.rbacService.CreateRole(createRoleDTO.Name, createRoleDTO.Description)
+	if herr != nil {
+		return nil, herr
+	}
+	return makeDTORole(role, nil), nil
+}
+
+// Get a role and its permissions
+func (rbacController *rbacController) GetRole(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	roleID, herr := getUUIDFromPath(r, "id")
+	if herr != nil {
+		return nil, herr
+	}
+	role, herr := rbacController.rbacService.GetRole(roleID)
+	if herr != nil {
+		return nil, herr
+	}
+	permissions, herr := rbacController.rbacService.GetRolePermissions(roleID)
+	if herr != nil {
+		return nil, herr
+	}
+	return makeDTORole(role, permissions), nil
+}
+
+// Delete a role
+func (rbacController *rbacController) DeleteRole(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	roleID, herr := getUUIDFromPath(r, "id")
+	if herr != nil {
+		return nil, herr
+	}
+	return nil, rbacController.rbacService.DeleteRole(roleID)
+}
+
+// Grant a permission to a role
+func (rbacController *rbacController) GrantPermission(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	roleID, herr := getUUIDFromPath(r, "id")
+	if herr != nil {
+		return nil, herr
+	}
+	var grantPermissionDTO dto.DTOGrantPermission
+	herr = decodeJSON(r, &grantPermissionDTO)
+	if herr != nil {
+		return nil, herr
+	}
+	return nil, rbacController.rbacService.GrantPermission(roleID, grantPermissionDTO.Permission)
+}
+
+// Revoke a permission from a role
+func (rbacController *rbacController) RevokePermission(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	roleID, herr := getUUIDFromPath(r, "id")
+	if herr != nil {
+		return nil, herr
+	}
+	return nil, rbacController.rbacService.RevokePermission(roleID, mux.Vars(r)["permission"])
+}
+
+// List the roles assigned to a user
+func (rbacController *rbacController) GetUserRoles(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	userID, herr := getUUIDFromPath(r, "id")
+	if herr != nil {
+		return nil, herr
+	}
+	roles, herr := rbacController.rbacService.GetUserRoles(userID)
+	if herr != nil {
+		return nil, herr
+	}
+	dtoRoles := make([]dto.DTORole, 0, len(roles))
+	for _, role := range roles {
+		dtoRoles = append(dtoRoles, makeDTORole(role, nil))
+	}
+	return dtoRoles, nil
+}
+
+// Assign a role to a user
+func (rbacController *rbacController) AssignRole(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	userID, herr := getUUIDFromPath(r, "id")
+	if herr != nil {
+		return nil, herr
+	}
+	var assignRoleDTO dto.DTOAssignRole
+	herr = decodeJSON(r, &assignRoleDTO)
+	if herr != nil {
+		return nil, herr
+	}
+	roleID, err := uuid.Parse(assignRoleDTO.RoleID)
+	if err != nil {
+		return nil, HTTPErrRequestMalformed
+	}
+	herr = rbacController.rbacService.AssignRole(userID, roleID)
+	if herr != nil {
+		return nil, herr
+	}
+	return nil, rbacController.rotateOwnSession(userID, w, r)
+}
+
+// Remove a role from a user
+func (rbacController *rbacController) UnassignRole(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	userID, herr := getUUIDFromPath(r, "id")
+	if herr != nil {
+		return nil, herr
+	}
+	roleID, herr := getUUIDFromPath(r, "roleID")
+	if herr != nil {
+		return nil, herr
+	}
+	herr = rbacController.rbacService.UnassignRole(userID, roleID)
+	if herr != nil {
+		return nil, herr
+	}
+	return nil, rbacController.rotateOwnSession(userID, w, r)
+}
+
+// Rotate the session of the request if the roles changed are the ones of its user
+func (rbacController *rbacController) rotateOwnSession(userID uuid.UUID, w http.ResponseWriter, r *http.Request) httperrors.HTTPError {
+	sessionClaims := sessionservice.GetSessionClaimsFromContext(r.Context())
+	if sessionClaims.UserID != userID {
+		return nil
+	}
+	return rbacController.sessionService.RotateSession(sessionClaims, w)
+}
+
+// List the roles assigned to a group
+func (rbacController *rbacController) GetGroupRoles(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	groupID, herr := getUUIDFromPath(r, "id")
+	if herr != nil {
+		return nil, herr
+	}
+	roles, herr := rbacController.rbacService.GetGroupRoles(groupID)
+	if herr != nil {
+		return nil, herr
+	}
+	dtoRoles := make([]dto.DTORole, 0, len(roles))
+	for _, role := range roles {
+		dtoRoles = append(dtoRoles, makeDTORole(role, nil))
+	}
+	return dtoRoles, nil
+}
+
+// Assign a role to a group
+func (rbacController *rbacController) AssignGroupRole(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	groupID, herr := getUUIDFromPath(r, "id")
+	if herr != nil {
+		return nil, herr
+	}
+	var assignRoleDTO dto.DTOAssignRole
+	herr = decodeJSON(r, &assignRoleDTO)
+	if herr != nil {
+		return nil, herr
+	}
+	roleID, err := uuid.Parse(assignRoleDTO.RoleID)
+	if err != nil {
+		return nil, HTTPErrRequestMalformed
+	}
+	return nil, rbacController.rbacService.AssignGroupRole(groupID, roleID)
+}
+
+// Remove a role from a group
+func (rbacController *rbacController) UnassignGroupRole(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	groupID, herr := getUUIDFromPath(r, "id")
+	if herr != nil {
+		return nil, herr
+	}
+	roleID, herr := getUUIDFromPath(r, "roleID")
+	if herr != nil {
+		return nil, herr
+	}
+	return nil, rbacController.rbacService.UnassignGroupRole(groupID, roleID)
+}
+
+// Create a DTORole from a role and its permissions
+func makeDTORole(role *models.Role, permissions []string) dto.DTORole {
+	return dto.DTORole{
+		ID:          role.ID.String(),
+		Name:        role.Name,
+		Description: role.Description,
+		Permissions: permissions,
+	}
+}
@@ -0,0 +1,155 @@
+package controllers_test
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ditrit/badaas/controllers"
+	mocksRBACService "github.com/ditrit/badaas/mocks/services/rbacservice"
+	mocksSessionService "github.com/ditrit/badaas/mocks/services/sessionservice"
+	"github.com/ditrit/badaas/persistence/models"
+	"github.com/ditrit/badaas/persistence/models/dto"
+	"github.com/ditrit/badaas/services/sessionservice"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func Test_ListRoles(t *testing.T) {
+	core, _ := observer.New(zap.DebugLevel)
+	logger := zap.New(core)
+	role := &models.Role{
+		BaseModel:   models.BaseModel{ID: uuid.New()},
+		Name:        "editor",
+		Description: "can edit",
+	}
+	rbacService := mocksRBACService.NewRBACService(t)
+	rbacService.On("GetRoles").Return([]*models.Role{role}, nil)
+
+	controller := controllers.NewRBACController(logger, rbacService, mocksSessionService.NewSessionService(t))
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest("GET", "/roles", nil)
+
+	payload, err := controller.ListRoles(response, request)
+	assert.NoError(t, err)
+	assert.Equal(t, []dto.DTORole{{
+		ID:          role.ID.String(),
+		Name:        "editor",
+		Description: "can edit",
+	}}, payload)
+}
+
+func Test_CreateRole_MalformedRequest(t *testing.T) {
+	core, _ := observer.New(zap.DebugLevel)
+	logger := zap.New(core)
+	rbacService := mocksRBACService.NewRBACService(t)
+
+	controller := controllers.NewRBACController(logger, rbacService, mocksSessionService.NewSessionService(t))
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest("POST", "/roles", strings.NewReader("qsdqsdqsd"))
+
+	payload, err := controller.CreateRole(response, request)
+	assert.Equal(t, controllers.HTTPErrRequestMalformed, err)
+	assert.Nil(t, payload)
+}
+
+func Test_GetRole(t *testing.T) {
+	core, _ := observer.New(zap.DebugLevel)
+	logger := zap.New(core)
+	role := &models.Role{
+		BaseModel: models.BaseModel{ID: uuid.New()},
+		Name:      "editor",
+	}
+	rbacService := mocksRBACService.NewRBACService(t)
+	rbacService.On("GetRole", role.ID).Return(role, nil)
+	rbacService.On("GetRolePermissions", role.ID).Return([]string{"posts:write"}, nil)
+
+	controller := controllers.NewRBACController(logger, rbacService, mocksSessionService.NewSessionService(t))
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest("GET", "/roles/"+role.ID.String(), nil)
+	request = mux.SetURLVars(request, map[string]string{"id": role.ID.String()})
+
+	payload, err := controller.GetRole(response, request)
+	assert.NoError(t, err)
+	assert.Equal(t, dto.DTORole{
+		ID:          role.ID.String(),
+		Name:        "editor",
+		Permissions: []string{"posts:write"},
+	}, payload)
+}
+
+func Test_GetRole_InvalidID(t *testing.T) {
+	core, _ := observer.New(zap.DebugLevel)
+	logger := zap.New(core)
+	rbacService := mocksRBACService.NewRBACService(t)
+
+	controller := controllers.NewRBACController(logger, rbacService, mocksSessionService.NewSessionService(t))
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest("GET", "/roles/notanuuid", nil)
+	request = mux.SetURLVars(request, map[string]string{"id": "notanuuid"})
+
+	payload, err := controller.GetRole(response, request)
+	assert.Error(t, err)
+	assert.Nil(t, payload)
+}
+
+func Test_AssignRole(t *testing.T) {
+	core, _ := observer.New(zap.DebugLevel)
+	logger := zap.New(core)
+	userID := uuid.New()
+	roleID := uuid.New()
+	rbacService := mocksRBACService.NewRBACService(t)
+	rbacService.On("AssignRole", userID, roleID).Return(nil)
+
+	controller := controllers.NewRBACController(logger, rbacService, mocksSessionService.NewSessionService(t))
+	response := httptest.NewRecorder()
+	request := makeAuthenticatedRequest(uuid.New(), "POST", "/users/"+userID.String()+"/roles",
+		`{"roleId": "`+roleID.String()+`"}`, map[string]string{"id": userID.String()})
+
+	payload, err := controller.AssignRole(response, request)
+	assert.NoError(t, err)
+	assert.Nil(t, payload)
+}
+
+func Test_AssignRole_OwnRolesRotateTheSession(t *testing.T) {
+	core, _ := observer.New(zap.DebugLevel)
+	logger := zap.New(core)
+	userID := uuid.New()
+	roleID := uuid.New()
+	rbacService := mocksRBACService.NewRBACService(t)
+	rbacService.On("AssignRole", userID, roleID).Return(nil)
+	sessionService := mocksSessionService.NewSessionService(t)
+	response := httptest.NewRecorder()
+	sessionService.On("RotateSession", &sessionservice.SessionClaims{UserID: userID}, response).Return(nil)
+
+	controller := controllers.NewRBACController(logger, rbacService, sessionService)
+	request := makeAuthenticatedRequest(userID, "POST", "/users/"+userID.String()+"/roles",
+		`{"roleId": "`+roleID.String()+`"}`, map[string]string{"id": userID.String()})
+
+	payload, err := controller.AssignRole(response, request)
+	assert.NoError(t, err)
+	assert.Nil(t, payload)
+}
+
+func Test_AssignRole_InvalidRoleID(t *testing.T) {
+	core, _ := observer.New(zap.DebugLevel)
+	logger := zap.New(core)
+	userID := uuid.New()
+	rbacService := mocksRBACService.NewRBACService(t)
+
+	controller := controllers.NewRBACController(logger, rbacService, mocksSessionService.NewSessionService(t))
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest(
+		"POST",
+		"/users/"+userID.String()+"/roles",
+		strings.NewReader(`{"roleId": "admin"}`),
+	)
+	request = mux.SetURLVars(request, map[string]string{"id": userID.String()})
+
+	payload, err := controller.AssignRole(response, request)
+	assert.Equal(t, controllers.HTTPErrRequestMalformed, err)
+	assert.Nil(t, payload)
+}
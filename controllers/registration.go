@@ -0,0 +1,72 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/ditrit/badaas/configuration"
+	"github.com/ditrit/badaas/httperrors"
+	"github.com/ditrit/badaas/persistence/models/dto"
+	"github.com/ditrit/badaas/services/registrationservice"
+	"go.uber.org/zap"
+)
+
+// Self-service registration Controller
+type RegistrationController interface {
+	Register(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+}
+
+// Check interface compliance
+var _ RegistrationController = (*registrationController)(nil)
+
+// RegistrationController implementation
+type registrationController struct {
+	logger                         *zap.Logger
+	registrationService            registrationservice.RegistrationService
+	loginGate                      LoginGate
+	registrationConfiguration      configuration.RegistrationConfiguration
+	emailVerificationConfiguration configuration.EmailVerificationConfiguration
+}
+
+// RegistrationController constructor
+func NewRegistrationController(
+	logger *zap.Logger,
+	registrationService registrationservice.RegistrationService,
+	loginGate LoginGate,
+	registrationConfiguration configuration.RegistrationConfiguration,
+	emailVerificationConfiguration configuration.EmailVerificationConfiguration,
+) RegistrationController {
+	return &registrationController{
+		logger:                         logger,
+		registrationService:            registrationService,
+		loginGate:                      loginGate,
+		registrationConfiguration:      registrationConfiguration,
+		emailVerificationConfiguration: emailVerificationConfiguration,
+	}
+}
+
+// Create the account of a new user
+//
+// The user is logged in if the automatic login is enabled, unless it has to verify its email first.
+// A user whose roles require a second factor gets the challenge of its enrolment instead.
+func (registrationController *registrationController) Register(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	var registerDTO dto.DTORegister
+	herr := decodeJSON(r, &registerDTO)
+	if herr != nil {
+		return nil, herr
+	}
+	user, herr := registrationController.registrationService.Register(registerDTO)
+	if herr != nil {
+		return nil, herr
+	}
+	if registrationController.registrationConfiguration.GetAutoLogin() &&
+		!registrationController.emailVerificationConfiguration.GetRequired() {
+		payload, herr := registrationController.loginGate.LogUserIn(user, r, w)
+		if herr != nil {
+			return nil, herr
+		}
+		if challenge, ok := payload.(*dto.DTOLoginChallenge); ok {
+			return challenge, nil
+		}
+	}
+	return makeDTOUser(user), nil
+}
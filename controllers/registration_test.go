@@ -0,0 +1,98 @@
+package controllers_test
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ditrit/badaas/controllers"
+	mocksConfiguration "github.com/ditrit/badaas/mocks/configuration"
+	mocksControllers "github.com/ditrit/badaas/mocks/controllers"
+	mocksRegistrationService "github.com/ditrit/badaas/mocks/services/registrationservice"
+	"github.com/ditrit/badaas/persistence/models"
+	"github.com/ditrit/badaas/persistence/models/dto"
+	"github.com/ditrit/badaas/services/registrationservice"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func Test_Register_AutoLogin(t *testing.T) {
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Username: "bob", Email: "bob@email.com"}
+	registerDTO := dto.DTORegister{Username: "bob", Email: "bob@email.com", Password: "1234"}
+	registrationService := mocksRegistrationService.NewRegistrationService(t)
+	registrationService.On("Register", registerDTO).Return(user, nil)
+	loginGate := mocksControllers.NewLoginGate(t)
+	loginGate.On("LogUserIn", user, mock.Anything, mock.Anything).
+		Return(dto.DTOLoginSuccess{ID: user.ID.String(), Username: "bob", Email: "bob@email.com"}, nil)
+	registrationConfiguration := mocksConfiguration.NewRegistrationConfiguration(t)
+	registrationConfiguration.On("GetAutoLogin").Return(true)
+	emailVerificationConfiguration := mocksConfiguration.NewEmailVerificationConfiguration(t)
+	emailVerificationConfiguration.On("GetRequired").Return(false)
+
+	controller := controllers.NewRegistrationController(zap.L(), registrationService, loginGate,
+		registrationConfiguration, emailVerificationConfiguration)
+	request := httptest.NewRequest("POST", "/register",
+		strings.NewReader(`{"username": "bob", "email": "bob@email.com", "password": "1234"}`))
+
+	payload, err := controller.Register(httptest.NewRecorder(), request)
+	assert.NoError(t, err)
+	assert.Equal(t, dto.DTOUser{ID: user.ID.String(), Username: "bob", Email: "bob@email.com"}, payload)
+}
+
+func Test_Register_AutoLoginSecondFactorRequired(t *testing.T) {
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Username: "bob", Email: "bob@email.com"}
+	challenge := &dto.DTOLoginChallenge{EnrolmentRequired: true, Methods: []string{}, Challenge: "challenge"}
+	registrationService := mocksRegistrationService.NewRegistrationService(t)
+	registrationService.On("Register", mock.Anything).Return(user, nil)
+	loginGate := mocksControllers.NewLoginGate(t)
+	loginGate.On("LogUserIn", user, mock.Anything, mock.Anything).Return(challenge, nil)
+	registrationConfiguration := mocksConfiguration.NewRegistrationConfiguration(t)
+	registrationConfiguration.On("GetAutoLogin").Return(true)
+	emailVerificationConfiguration := mocksConfiguration.NewEmailVerificationConfiguration(t)
+	emailVerificationConfiguration.On("GetRequired").Return(false)
+
+	controller := controllers.NewRegistrationController(zap.L(), registrationService, loginGate,
+		registrationConfiguration, emailVerificationConfiguration)
+	request := httptest.NewRequest("POST", "/register",
+		strings.NewReader(`{"username": "bob", "email": "bob@email.com", "password": "1234"}`))
+
+	payload, err := controller.Register(httptest.NewRecorder(), request)
+	assert.NoError(t, err)
+	assert.Equal(t, challenge, payload)
+}
+
+func Test_Register_Disabled(t *testing.T) {
+	registrationService := mocksRegistrationService.NewRegistrationService(t)
+	registrationService.On("Register", mock.Anything).Return(nil, registrationservice.HERRRegistrationDisabled)
+
+	controller := controllers.NewRegistrationController(zap.L(), registrationService,
+		mocksControllers.NewLoginGate(t), mocksConfiguration.NewRegistrationConfiguration(t),
+		mocksConfiguration.NewEmailVerificationConfiguration(t))
+	request := httptest.NewRequest("POST", "/register", strings.NewReader(`{"username": "bob"}`))
+
+	payload, err := controller.Register(httptest.NewRecorder(), request)
+	assert.Equal(t, registrationservice.HERRRegistrationDisabled, err)
+	assert.Nil(t, payload)
+}
+
+func Test_Register_AutoLoginEmailVerificationRequired(t *testing.T) {
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Username: "bob", Email: "bob@email.com"}
+	registrationService := mocksRegistrationService.NewRegistrationService(t)
+	registrationService.On("Register", mock.Anything).Return(user, nil)
+	loginGate := mocksControllers.NewLoginGate(t)
+	registrationConfiguration := mocksConfiguration.NewRegistrationConfiguration(t)
+	registrationConfiguration.On("GetAutoLogin").Return(true)
+	emailVerificationConfiguration := mocksConfiguration.NewEmailVerificationConfiguration(t)
+	emailVerificationConfiguration.On("GetRequired").Return(true)
+
+	controller := controllers.NewRegistrationController(zap.L(), registrationService, loginGate,
+		registrationConfiguration, emailVerificationConfiguration)
+	request := httptest.NewRequest("POST", "/register",
+		strings.NewReader(`{"username": "bob", "email": "bob@email.com", "password": "1234"}`))
+
+	_, err := controller.Register(httptest.NewRecorder(), request)
+	assert.NoError(t, err)
+	loginGate.AssertNotCalled(t, "LogUserIn", mock.Anything, mock.Anything, mock.Anything)
+}
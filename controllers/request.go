@@ -0,0 +1,71 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/ditrit/badaas/httperrors"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// Decode the json body of the request into the value pointed by v
+func decodeJSON(r *http.Request, v any) httperrors.HTTPError {
+	err := json.NewDecoder(r.Body).Decode(v)
+	if err != nil {
+		return HTTPErrRequestMalformed
+	}
+	return nil
+}
+
+// Extract an uuid from the path parameters of the request
+func getUUIDFromPath(r *http.Request, name string) (uuid.UUID, httperrors.HTTPError) {
+	value, ok := mux.Vars(r)[name]
+	if !ok {
+		return uuid.Nil, HTTPErrRequestMalformed
+	}
+	parsedUUID, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, httperrors.NewHTTPError(
+			http.StatusBadRequest,
+			"Request malformed",
+			fmt.Sprintf("%q is not a valid uuid", value),
+			nil,
+			false,
+		)
+	}
+	return parsedUUID, nil
+}
+
+// Parse an optional uuid, return nil if the value is empty
+func parseOptionalUUID(value string) (*uuid.UUID, httperrors.HTTPError) {
+	if value == "" {
+		return nil, nil
+	}
+	parsedUUID, err := uuid.Parse(value)
+	if err != nil {
+		return nil, HTTPErrRequestMalformed
+	}
+	return &parsedUUID, nil
+}
+
+// Extract an unsigned integer from the query parameters of the request, return the default value if missing
+func getUintFromQuery(r *http.Request, name string, defaultValue uint) (uint, httperrors.HTTPError) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return defaultValue, nil
+	}
+	parsedValue, err := strconv.ParseUint(value, 10, 0)
+	if err != nil {
+		return 0, httperrors.NewHTTPError(
+			http.StatusBadRequest,
+			"Request malformed",
+			fmt.Sprintf("%q is not a valid value for %q", value, name),
+			nil,
+			false,
+		)
+	}
+	return uint(parsedValue), nil
+}
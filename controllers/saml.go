@@ -0,0 +1,126 @@
+package controllers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ditrit/badaas/configuration"
+	"github.com/ditrit/badaas/httperrors"
+	"github.com/ditrit/badaas/services/samlservice"
+	"go.uber.org/zap"
+)
+
+// The cookie binding a SAML login to the browser which started it
+const samlRequestCookieName = "saml_request"
+
+// The path of the SAML logins, the request cookie is only sent to it
+const samlLoginPath = "/login/saml"
+
+// SAML Controller
+//
+// Login redirects the user to the identity provider, which posts its response to AssertionConsumerService.
+// The second factor of the user, if any, is then asked like after a password.
+type SAMLController interface {
+	Metadata(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	Login(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	AssertionConsumerService(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+}
+
+// Check interface compliance
+var _ SAMLController = (*samlController)(nil)
+
+// SAMLController implementation
+type samlController struct {
+	logger              *zap.Logger
+	samlService         samlservice.SAMLService
+	loginGate           LoginGate
+	samlConfiguration   configuration.SAMLConfiguration
+	cookieConfiguration configuration.CookieConfiguration
+}
+
+// SAMLController constructor
+func NewSAMLController(
+	logger *zap.Logger,
+	samlService samlservice.SAMLService,
+	loginGate LoginGate,
+	samlConfiguration configuration.SAMLConfiguration,
+	cookieConfiguration configuration.CookieConfiguration,
+) SAMLController {
+	return &samlController{
+		logger:              logger,
+		samlService:         samlService,
+		loginGate:           loginGate,
+		samlConfiguration:   samlConfiguration,
+		cookieConfiguration: cookieConfiguration,
+	}
+}
+
+// Return the metadata of the service provider, to be given to the identity provider
+func (samlController *samlController) Metadata(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	metadata, herr := samlController.samlService.GetMetadata()
+	if herr != nil {
+		return nil, herr
+	}
+	w.Header().Set("Content-Type", "application/samlmetadata+xml")
+	_, err := w.Write(metadata)
+	if err != nil {
+		samlController.logger.Error("Failed to write the SAML metadata", zap.Error(err))
+	}
+	return nil, nil
+}
+
+// Redirect the user to the identity provider
+//
+// The id of the AuthnRequest is also set in a cookie, so that the response can't be posted from another browser.
+func (samlController *samlController) Login(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	redirectURL, requestID, herr := samlController.samlService.BeginLogin()
+	if herr != nil {
+		return nil, herr
+	}
+	samlController.setRequestCookie(w, requestID, samlController.samlConfiguration.GetRequestDuration())
+	http.Redirect(w, r, redirectURL, http.StatusFound)
+	return nil, nil
+}
+
+// Log the user in with the response posted by the identity provider, or return a challenge if a second factor is needed
+//
+// The user is redirected to the configured url if any, it is described in JSON otherwise.
+func (samlController *samlController) AssertionConsumerService(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	err := r.ParseForm()
+	if err != nil {
+		return nil, HTTPErrRequestMalformed
+	}
+	encodedResponse := r.PostForm.Get("SAMLResponse")
+	if encodedResponse == "" {
+		return nil, HTTPErrRequestMalformed
+	}
+	cookie, err := r.Cookie(samlRequestCookieName)
+	if err != nil || cookie.Value == "" {
+		return nil, samlservice.HERRInvalidState
+	}
+	samlController.setRequestCookie(w, "", -time.Second)
+	user, herr := samlController.samlService.FinishLogin(encodedResponse, cookie.Value)
+	if herr != nil {
+		return nil, herr
+	}
+	payload, herr := samlController.loginGate.LogUserIn(user, r, w)
+	if herr != nil {
+		return nil, herr
+	}
+	return redirectLogin(w, r, samlController.samlConfiguration.GetLoginRedirectURL(), payload)
+}
+
+// Set the cookie holding the id of the AuthnRequest, it is deleted if the duration is negative
+func (samlController *samlController) setRequestCookie(w http.ResponseWriter, requestID string, duration time.Duration) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     samlRequestCookieName,
+		Value:    requestID,
+		Path:     samlLoginPath,
+		MaxAge:   int(duration.Seconds()),
+		HttpOnly: true,
+		Secure:   samlController.cookieConfiguration.GetSecure(),
+		// sent on the cross-site post of the identity provider to the assertion consumer service,
+		// the browsers require the cookie to be secure
+		SameSite: http.SameSiteNoneMode,
+	})
+}
@@ -0,0 +1,167 @@
+package controllers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ditrit/badaas/controllers"
+	mocksConfiguration "github.com/ditrit/badaas/mocks/configuration"
+	mocksControllers "github.com/ditrit/badaas/mocks/controllers"
+	mocksSAMLService "github.com/ditrit/badaas/mocks/services/samlservice"
+	"github.com/ditrit/badaas/persistence/models"
+	"github.com/ditrit/badaas/persistence/models/dto"
+	"github.com/ditrit/badaas/services/samlservice"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// Return the request posting the response of the identity provider, from the browser which started the request
+func makeSAMLResponseRequest(encodedResponse string) *http.Request {
+	request := httptest.NewRequest("POST", "/login/saml/acs",
+		strings.NewReader(url.Values{"SAMLResponse": {encodedResponse}}.Encode()))
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.AddCookie(&http.Cookie{Name: "saml_request", Value: "request-id"})
+	return request
+}
+
+func Test_SAMLMetadata(t *testing.T) {
+	samlService := mocksSAMLService.NewSAMLService(t)
+	samlService.On("GetMetadata").Return([]byte("<md:EntityDescriptor/>"), nil)
+
+	controller := controllers.NewSAMLController(zap.L(), samlService,
+		mocksControllers.NewLoginGate(t), mocksConfiguration.NewSAMLConfiguration(t), newSecureCookieConfiguration(t))
+	response := httptest.NewRecorder()
+
+	payload, err := controller.Metadata(response, httptest.NewRequest("GET", "/saml/metadata", nil))
+	assert.Nil(t, err)
+	assert.Nil(t, payload)
+	assert.Equal(t, "application/samlmetadata+xml", response.Header().Get("Content-Type"))
+	assert.Equal(t, "<md:EntityDescriptor/>", response.Body.String())
+}
+
+func Test_SAMLLogin(t *testing.T) {
+	samlService := mocksSAMLService.NewSAMLService(t)
+	samlService.On("BeginLogin").Return("https://idp.example.com/sso?SAMLRequest=request", "request-id", nil)
+	samlConfiguration := mocksConfiguration.NewSAMLConfiguration(t)
+	samlConfiguration.On("GetRequestDuration").Return(10 * time.Minute)
+
+	controller := controllers.NewSAMLController(zap.L(), samlService,
+		mocksControllers.NewLoginGate(t), samlConfiguration, newSecureCookieConfiguration(t))
+	response := httptest.NewRecorder()
+
+	payload, err := controller.Login(response, httptest.NewRequest("GET", "/login/saml", nil))
+	assert.Nil(t, err)
+	assert.Nil(t, payload)
+	assert.Equal(t, http.StatusFound, response.Code)
+	assert.Equal(t, "https://idp.example.com/sso?SAMLRequest=request", response.Header().Get("Location"))
+	cookies := response.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.Equal(t, "saml_request", cookies[0].Name)
+	assert.Equal(t, "request-id", cookies[0].Value)
+	assert.Equal(t, "/login/saml", cookies[0].Path)
+	assert.Equal(t, 600, cookies[0].MaxAge)
+	assert.True(t, cookies[0].HttpOnly)
+	assert.True(t, cookies[0].Secure)
+}
+
+func Test_SAMLAssertionConsumerService(t *testing.T) {
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Username: "bob", Email: "bob@email.com"}
+	samlService := mocksSAMLService.NewSAMLService(t)
+	samlService.On("FinishLogin", "response", "request-id").Return(user, nil)
+	loginGate := mocksControllers.NewLoginGate(t)
+	loginGate.On("LogUserIn", user, mock.Anything, mock.Anything).Return(dto.DTOLoginSuccess{ID: user.ID.String(), Username: user.Username, Email: user.Email}, nil)
+	samlConfiguration := mocksConfiguration.NewSAMLConfiguration(t)
+	samlConfiguration.On("GetLoginRedirectURL").Return("")
+
+	controller := controllers.NewSAMLController(zap.L(), samlService, loginGate, samlConfiguration,
+		newSecureCookieConfiguration(t))
+	response := httptest.NewRecorder()
+
+	payload, err := controller.AssertionConsumerService(response, makeSAMLResponseRequest("response"))
+	assert.Nil(t, err)
+	assert.Equal(t, dto.DTOLoginSuccess{ID: user.ID.String(), Username: "bob", Email: "bob@email.com"}, payload)
+	// the request cookie is deleted
+	cookies := response.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.Equal(t, "saml_request", cookies[0].Name)
+	assert.Equal(t, -1, cookies[0].MaxAge)
+	assert.True(t, cookies[0].Secure)
+}
+
+func Test_SAMLAssertionConsumerService_Redirect(t *testing.T) {
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}}
+	samlService := mocksSAMLService.NewSAMLService(t)
+	samlService.On("FinishLogin", "response", "request-id").Return(user, nil)
+	loginGate := mocksControllers.NewLoginGate(t)
+	loginGate.On("LogUserIn", user, mock.Anything, mock.Anything).Return(dto.DTOLoginSuccess{ID: user.ID.String(), Username: user.Username, Email: user.Email}, nil)
+	samlConfiguration := mocksConfiguration.NewSAMLConfiguration(t)
+	samlConfiguration.On("GetLoginRedirectURL").Return("https://app.example.com")
+
+	controller := controllers.NewSAMLController(zap.L(), samlService, loginGate, samlConfiguration,
+		newSecureCookieConfiguration(t))
+	response := httptest.NewRecorder()
+
+	payload, err := controller.AssertionConsumerService(response, makeSAMLResponseRequest("response"))
+	assert.Nil(t, err)
+	assert.Nil(t, payload)
+	assert.Equal(t, http.StatusSeeOther, response.Code)
+	assert.Equal(t, "https://app.example.com", response.Header().Get("Location"))
+}
+
+func Test_SAMLAssertionConsumerService_SecondFactor(t *testing.T) {
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}}
+	challenge := &dto.DTOLoginChallenge{EnrolmentRequired: true, Methods: []string{}, Challenge: "challenge"}
+	samlService := mocksSAMLService.NewSAMLService(t)
+	samlService.On("FinishLogin", "response", "request-id").Return(user, nil)
+	loginGate := mocksControllers.NewLoginGate(t)
+	loginGate.On("LogUserIn", user, mock.Anything, mock.Anything).Return(challenge, nil)
+	samlConfiguration := mocksConfiguration.NewSAMLConfiguration(t)
+	samlConfiguration.On("GetLoginRedirectURL").Return("")
+
+	controller := controllers.NewSAMLController(zap.L(), samlService, loginGate, samlConfiguration,
+		newSecureCookieConfiguration(t))
+
+	payload, err := controller.AssertionConsumerService(httptest.NewRecorder(), makeSAMLResponseRequest("response"))
+	assert.Nil(t, err)
+	assert.Equal(t, challenge, payload)
+}
+
+func Test_SAMLAssertionConsumerService_InvalidAssertion(t *testing.T) {
+	samlService := mocksSAMLService.NewSAMLService(t)
+	samlService.On("FinishLogin", "forged", "request-id").Return(nil, samlservice.HERRInvalidAssertion)
+
+	controller := controllers.NewSAMLController(zap.L(), samlService,
+		mocksControllers.NewLoginGate(t), mocksConfiguration.NewSAMLConfiguration(t), newSecureCookieConfiguration(t))
+
+	payload, err := controller.AssertionConsumerService(httptest.NewRecorder(), makeSAMLResponseRequest("forged"))
+	assert.Equal(t, samlservice.HERRInvalidAssertion, err)
+	assert.Nil(t, payload)
+}
+
+func Test_SAMLAssertionConsumerService_NoResponse(t *testing.T) {
+	controller := controllers.NewSAMLController(zap.L(), mocksSAMLService.NewSAMLService(t),
+		mocksControllers.NewLoginGate(t), mocksConfiguration.NewSAMLConfiguration(t), newSecureCookieConfiguration(t))
+
+	payload, err := controller.AssertionConsumerService(httptest.NewRecorder(), makeSAMLResponseRequest(""))
+	assert.Equal(t, controllers.HTTPErrRequestMalformed, err)
+	assert.Nil(t, payload)
+}
+
+func Test_SAMLAssertionConsumerService_NoRequestCookie(t *testing.T) {
+	controller := controllers.NewSAMLController(zap.L(), mocksSAMLService.NewSAMLService(t),
+		mocksControllers.NewLoginGate(t), mocksConfiguration.NewSAMLConfiguration(t), newSecureCookieConfiguration(t))
+	request := httptest.NewRequest("POST", "/login/saml/acs",
+		strings.NewReader(url.Values{"SAMLResponse": {"response"}}.Encode()))
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	payload, err := controller.AssertionConsumerService(httptest.NewRecorder(), request)
+	assert.Equal(t, samlservice.HERRInvalidState, err)
+	assert.Nil(t, payload)
+}
@@ -0,0 +1,140 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/ditrit/badaas/httperrors"
+	"github.com/ditrit/badaas/persistence/models"
+	"github.com/ditrit/badaas/persistence/models/dto"
+	"github.com/ditrit/badaas/services/sessionservice"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Session Controller
+//
+// The sessions of the current user are managed on /me/sessions, the ones of any user by the users managers.
+type SessionController interface {
+	Refresh(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	JWKS(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	ListSessions(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	RevokeSession(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	RevokeOtherSessions(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	ListUserSessions(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	RevokeUserSession(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	RevokeUserSessions(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+}
+
+// Check interface compliance
+var _ SessionController = (*sessionController)(nil)
+
+// SessionController implementation
+type sessionController struct {
+	logger         *zap.Logger
+	sessionService sessionservice.SessionService
+}
+
+// SessionController constructor
+func NewSessionController(
+	logger *zap.Logger,
+	sessionService sessionservice.SessionService,
+) SessionController {
+	return &sessionController{
+		logger:         logger,
+		sessionService: sessionService,
+	}
+}
+
+// Exchange the refresh token cookie for a new access token, in the jwt session mode
+func (sessionController *sessionController) Refresh(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	refreshTokenCookie, err := r.Cookie(sessionservice.RefreshTokenCookieName)
+	if err != nil {
+		return nil, sessionservice.HERRNotAuthenticated
+	}
+	return nil, sessionController.sessionService.Refresh(refreshTokenCookie.Value, w)
+}
+
+// Return the public keys verifying the access tokens (RFC 7517)
+func (sessionController *sessionController) JWKS(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	return sessionController.sessionService.GetJWKS(), nil
+}
+
+// List the active sessions of the current user
+func (sessionController *sessionController) ListSessions(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	sessionClaims := sessionservice.GetSessionClaimsFromContext(r.Context())
+	return sessionController.listSessions(sessionClaims.UserID, sessionClaims.SessionUUID)
+}
+
+// Revoke a session of the current user
+func (sessionController *sessionController) RevokeSession(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	sessionUUID, herr := getUUIDFromPath(r, "id")
+	if herr != nil {
+		return nil, herr
+	}
+	return nil, sessionController.sessionService.RevokeSession(
+		sessionservice.GetSessionClaimsFromContext(r.Context()).UserID, sessionUUID)
+}
+
+// Revoke all the sessions of the current user except the one of the request
+func (sessionController *sessionController) RevokeOtherSessions(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	sessionClaims := sessionservice.GetSessionClaimsFromContext(r.Context())
+	return nil, sessionController.sessionService.RevokeOtherUserSessions(sessionClaims.UserID, sessionClaims.SessionUUID)
+}
+
+// List the active sessions of a user
+func (sessionController *sessionController) ListUserSessions(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	userID, herr := getUUIDFromPath(r, "id")
+	if herr != nil {
+		return nil, herr
+	}
+	return sessionController.listSessions(userID, sessionservice.GetSessionClaimsFromContext(r.Context()).SessionUUID)
+}
+
+// Revoke a session of a user
+func (sessionController *sessionController) RevokeUserSession(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	userID, herr := getUUIDFromPath(r, "id")
+	if herr != nil {
+		return nil, herr
+	}
+	sessionUUID, herr := getUUIDFromPath(r, "sessionID")
+	if herr != nil {
+		return nil, herr
+	}
+	return nil, sessionController.sessionService.RevokeSession(userID, sessionUUID)
+}
+
+// Revoke all the sessions of a user
+func (sessionController *sessionController) RevokeUserSessions(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	userID, herr := getUUIDFromPath(r, "id")
+	if herr != nil {
+		return nil, herr
+	}
+	return nil, sessionController.sessionService.RevokeUserSessions(userID)
+}
+
+// List the active sessions of a user, the session of the request is marked as current
+func (sessionController *sessionController) listSessions(userID, currentSessionUUID uuid.UUID) (any, httperrors.HTTPError) {
+	sessions, herr := sessionController.sessionService.GetUserSessions(userID)
+	if herr != nil {
+		return nil, herr
+	}
+	dtoSessions := make([]dto.DTOSession, 0, len(sessions))
+	for _, session := range sessions {
+		dtoSessions = append(dtoSessions, makeDTOSession(session, currentSessionUUID))
+	}
+	return dtoSessions, nil
+}
+
+// Create a session DTO
+func makeDTOSession(session *models.Session, currentSessionUUID uuid.UUID) dto.DTOSession {
+	return dto.DTOSession{
+		ID:         session.ID.String(),
+		CreatedAt:  session.CreatedAt,
+		ExpiresAt:  session.ExpiresAt,
+		IPAddress:  session.IPAddress,
+		UserAgent:  session.UserAgent,
+		Device:     session.Device,
+		LastSeenAt: session.LastSeenAt,
+		Current:    session.ID == currentSessionUUID,
+	}
+}
@@ -0,0 +1,125 @@
+package controllers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"time"
+
+	"github.com/ditrit/badaas/controllers"
+	mocksSessionService "github.com/ditrit/badaas/mocks/services/sessionservice"
+	"github.com/ditrit/badaas/persistence/models"
+	"github.com/ditrit/badaas/persistence/models/dto"
+	"github.com/ditrit/badaas/services/sessionservice"
+	"github.com/go-jose/go-jose/v3"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func Test_RefreshSession(t *testing.T) {
+	response := httptest.NewRecorder()
+	sessionService := mocksSessionService.NewSessionService(t)
+	sessionService.On("Refresh", "refresh", response).Return(nil)
+
+	controller := controllers.NewSessionController(zap.L(), sessionService)
+	request := httptest.NewRequest("POST", "/session/refresh", nil)
+	request.AddCookie(&http.Cookie{Name: sessionservice.RefreshTokenCookieName, Value: "refresh"})
+
+	payload, herr := controller.Refresh(response, request)
+	assert.Nil(t, herr)
+	assert.Nil(t, payload)
+}
+
+func Test_RefreshSessionWithoutCookie(t *testing.T) {
+	controller := controllers.NewSessionController(zap.L(), mocksSessionService.NewSessionService(t))
+
+	_, herr := controller.Refresh(httptest.NewRecorder(), httptest.NewRequest("POST", "/session/refresh", nil))
+	assert.Equal(t, sessionservice.HERRNotAuthenticated, herr)
+}
+
+func Test_JWKS(t *testing.T) {
+	jwks := jose.JSONWebKeySet{Keys: []jose.JSONWebKey{{KeyID: "2024-01"}}}
+	sessionService := mocksSessionService.NewSessionService(t)
+	sessionService.On("GetJWKS").Return(jwks)
+
+	controller := controllers.NewSessionController(zap.L(), sessionService)
+	payload, herr := controller.JWKS(httptest.NewRecorder(), httptest.NewRequest("GET", "/.well-known/jwks.json", nil))
+	assert.Nil(t, herr)
+	assert.Equal(t, jwks, payload)
+}
+
+func Test_ListSessions(t *testing.T) {
+	userID := uuid.New()
+	createdAt := time.Now().Add(-time.Hour)
+	expiresAt := time.Now().Add(time.Hour)
+	currentSession := &models.Session{
+		BaseModel: models.BaseModel{ID: uuid.New(), CreatedAt: createdAt}, UserID: userID, ExpiresAt: expiresAt,
+		IPAddress: "192.0.2.1", UserAgent: "curl/8.0.1", Device: "curl", LastSeenAt: createdAt,
+	}
+	otherSession := &models.Session{BaseModel: models.BaseModel{ID: uuid.New(), CreatedAt: createdAt}, UserID: userID, ExpiresAt: expiresAt}
+	sessionService := mocksSessionService.NewSessionService(t)
+	sessionService.On("GetUserSessions", userID).Return([]*models.Session{currentSession, otherSession}, nil)
+
+	controller := controllers.NewSessionController(zap.L(), sessionService)
+	request := httptest.NewRequest("GET", "/me/sessions", nil)
+	request = request.WithContext(sessionservice.SetSessionClaimsContext(request.Context(),
+		&sessionservice.SessionClaims{UserID: userID, SessionUUID: currentSession.ID}))
+
+	payload, herr := controller.ListSessions(httptest.NewRecorder(), request)
+	assert.Nil(t, herr)
+	assert.Equal(t, []dto.DTOSession{
+		{
+			ID: currentSession.ID.String(), CreatedAt: createdAt, ExpiresAt: expiresAt,
+			IPAddress: "192.0.2.1", UserAgent: "curl/8.0.1", Device: "curl", LastSeenAt: createdAt, Current: true,
+		},
+		{ID: otherSession.ID.String(), CreatedAt: createdAt, ExpiresAt: expiresAt, Current: false},
+	}, payload)
+}
+
+func Test_RevokeOtherSessions(t *testing.T) {
+	userID := uuid.New()
+	sessionUUID := uuid.New()
+	sessionService := mocksSessionService.NewSessionService(t)
+	sessionService.On("RevokeOtherUserSessions", userID, sessionUUID).Return(nil)
+
+	controller := controllers.NewSessionController(zap.L(), sessionService)
+	request := httptest.NewRequest("DELETE", "/me/sessions", nil)
+	request = request.WithContext(sessionservice.SetSessionClaimsContext(request.Context(),
+		&sessionservice.SessionClaims{UserID: userID, SessionUUID: sessionUUID}))
+
+	payload, herr := controller.RevokeOtherSessions(httptest.NewRecorder(), request)
+	assert.Nil(t, herr)
+	assert.Nil(t, payload)
+}
+
+func Test_RevokeSessionOfAnotherUser(t *testing.T) {
+	userID := uuid.New()
+	sessionUUID := uuid.New()
+	sessionService := mocksSessionService.NewSessionService(t)
+	sessionService.On("RevokeSession", userID, sessionUUID).Return(sessionservice.HERRSessionNotFound)
+
+	controller := controllers.NewSessionController(zap.L(), sessionService)
+	request := makeAuthenticatedRequest(userID, "DELETE", "/me/sessions/"+sessionUUID.String(), "",
+		map[string]string{"id": sessionUUID.String()})
+
+	_, herr := controller.RevokeSession(httptest.NewRecorder(), request)
+	assert.Equal(t, sessionservice.HERRSessionNotFound, herr)
+}
+
+func Test_RevokeUserSession(t *testing.T) {
+	adminID := uuid.New()
+	userID := uuid.New()
+	sessionUUID := uuid.New()
+	sessionService := mocksSessionService.NewSessionService(t)
+	sessionService.On("RevokeSession", userID, sessionUUID).Return(nil)
+
+	controller := controllers.NewSessionController(zap.L(), sessionService)
+	request := makeAuthenticatedRequest(adminID, "DELETE", "/users/"+userID.String()+"/sessions/"+sessionUUID.String(), "",
+		map[string]string{"id": userID.String(), "sessionID": sessionUUID.String()})
+
+	payload, herr := controller.RevokeUserSession(httptest.NewRecorder(), request)
+	assert.Nil(t, herr)
+	assert.Nil(t, payload)
+}
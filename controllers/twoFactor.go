@@ -0,0 +1,273 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/ditrit/badaas/httperrors"
+	"github.com/ditrit/badaas/persistence/models/dto"
+	"github.com/ditrit/badaas/services/sessionservice"
+	"github.com/ditrit/badaas/services/twofactorservice"
+	"github.com/ditrit/badaas/services/userservice"
+	"github.com/ditrit/badaas/services/webauthnservice"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Two-factor authentication Controller
+//
+// The login handlers complete a login started by BasicLoginHandler with its challenge,
+// the other handlers act on the user of the session, except ResetTwoFactor reserved to the administrators.
+type TwoFactorController interface {
+	LoginWithCode(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	BeginLoginEnrolment(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	ConfirmLoginEnrolment(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	GetStatus(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	BeginEnrolment(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	ConfirmEnrolment(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	Disable(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	RegenerateRecoveryCodes(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	ResetTwoFactor(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+}
+
+// Check interface compliance
+var _ TwoFactorController = (*twoFactorController)(nil)
+
+// TwoFactorController implementation
+type twoFactorController struct {
+	logger           *zap.Logger
+	twoFactorService twofactorservice.TwoFactorService
+	userService      userservice.UserService
+	sessionService   sessionservice.SessionService
+	webAuthnService  webauthnservice.WebAuthnService
+	loginGate        LoginGate
+}
+
+// TwoFactorController constructor
+func NewTwoFactorController(
+	logger *zap.Logger,
+	twoFactorService twofactorservice.TwoFactorService,
+	userService userservice.UserService,
+	sessionService sessionservice.SessionService,
+	webAuthnService webauthnservice.WebAuthnService,
+	loginGate LoginGate,
+) TwoFactorController {
+	return &twoFactorController{
+		logger:           logger,
+		twoFactorService: twoFactorService,
+		userService:      userService,
+		sessionService:   sessionService,
+		webAuthnService:  webAuthnService,
+		loginGate:        loginGate,
+	}
+}
+
+// Complete a login with a TOTP code or a recovery code
+//
+// The wrong codes are throttled with the failed logins of the account.
+func (twoFactorController *twoFactorController) LoginWithCode(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	var loginCodeDTO dto.DTOLoginCode
+	herr := decodeJSON(r, &loginCodeDTO)
+	if herr != nil {
+		return nil, herr
+	}
+	userID, herr := twoFactorController.twoFactorService.GetChallengeUserID(loginCodeDTO.Challenge)
+	if herr != nil {
+		return nil, herr
+	}
+	user, herr := twoFactorController.userService.GetUserByID(userID)
+	if herr != nil {
+		return nil, herr
+	}
+	herr = twoFactorController.loginGate.CheckAttempt(user.Email, r, w)
+	if herr != nil {
+		return nil, herr
+	}
+	_, herr = twoFactorController.twoFactorService.VerifyChallenge(loginCodeDTO.Challenge, loginCodeDTO.Code)
+	if herr == twofactorservice.HERRInvalidCode {
+		twoFactorController.loginGate.RecordFailure(user.Email, r)
+		return nil, herr
+	}
+	if herr != nil {
+		return nil, herr
+	}
+	loginSuccess, herr := twoFactorController.loginGate.CompleteLogin(user, r, w)
+	if herr != nil {
+		return nil, herr
+	}
+	return loginSuccess, nil
+}
+
+// Generate the authenticator of a user who has to enable the two-factor authentication to log in
+func (twoFactorController *twoFactorController) BeginLoginEnrolment(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	var loginEnrolmentDTO dto.DTOLoginEnrolment
+	herr := decodeJSON(r, &loginEnrolmentDTO)
+	if herr != nil {
+		return nil, herr
+	}
+	userID, herr := twoFactorController.getEnrolmentUserID(loginEnrolmentDTO.Challenge)
+	if herr != nil {
+		return nil, herr
+	}
+	return twoFactorController.beginEnrolment(userID)
+}
+
+// Confirm the authenticator generated during the login and complete the login
+//
+// The wrong codes are throttled with the failed logins of the account.
+func (twoFactorController *twoFactorController) ConfirmLoginEnrolment(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	var loginCodeDTO dto.DTOLoginCode
+	herr := decodeJSON(r, &loginCodeDTO)
+	if herr != nil {
+		return nil, herr
+	}
+	userID, herr := twoFactorController.getEnrolmentUserID(loginCodeDTO.Challenge)
+	if herr != nil {
+		return nil, herr
+	}
+	user, herr := twoFactorController.userService.GetUserByID(userID)
+	if herr != nil {
+		return nil, herr
+	}
+	herr = twoFactorController.loginGate.CheckAttempt(user.Email, r, w)
+	if herr != nil {
+		return nil, herr
+	}
+	recoveryCodes, herr := twoFactorController.twoFactorService.ConfirmEnrolment(userID, loginCodeDTO.Code)
+	if herr == twofactorservice.HERRWrongCurrentCode {
+		twoFactorController.loginGate.RecordFailure(user.Email, r)
+		return nil, herr
+	}
+	if herr != nil {
+		return nil, herr
+	}
+	herr = twoFactorController.twoFactorService.DeleteChallenge(loginCodeDTO.Challenge)
+	if herr != nil {
+		return nil, herr
+	}
+	loginSuccess, herr := twoFactorController.loginGate.CompleteLogin(user, r, w)
+	if herr != nil {
+		return nil, herr
+	}
+	return dto.DTOLoginEnrolmentSuccess{
+		DTOLoginSuccess: loginSuccess,
+		RecoveryCodes:   recoveryCodes,
+	}, nil
+}
+
+// Return the two-factor status of the current user
+func (twoFactorController *twoFactorController) GetStatus(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	userID := sessionservice.GetSessionClaimsFromContext(r.Context()).UserID
+	enabled, herr := twoFactorController.twoFactorService.IsEnabled(userID)
+	if herr != nil {
+		return nil, herr
+	}
+	required, herr := twoFactorController.twoFactorService.IsRequired(userID)
+	if herr != nil {
+		return nil, herr
+	}
+	return dto.DTOTwoFactorStatus{
+		Enabled:  enabled,
+		Required: required,
+	}, nil
+}
+
+// Generate a new authenticator for the current user, it has to be confirmed with a code
+func (twoFactorController *twoFactorController) BeginEnrolment(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	return twoFactorController.beginEnrolment(sessionservice.GetSessionClaimsFromContext(r.Context()).UserID)
+}
+
+// Confirm the authenticator of the current user and return its recovery codes
+func (twoFactorController *twoFactorController) ConfirmEnrolment(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	var codeDTO dto.DTOTwoFactorCode
+	herr := decodeJSON(r, &codeDTO)
+	if herr != nil {
+		return nil, herr
+	}
+	sessionClaims := sessionservice.GetSessionClaimsFromContext(r.Context())
+	recoveryCodes, herr := twoFactorController.twoFactorService.ConfirmEnrolment(sessionClaims.UserID, codeDTO.Code)
+	if herr != nil {
+		return nil, herr
+	}
+	herr = twoFactorController.sessionService.RotateSession(sessionClaims, w)
+	if herr != nil {
+		return nil, herr
+	}
+	return dto.DTORecoveryCodes{RecoveryCodes: recoveryCodes}, nil
+}
+
+// Disable the two-factor authentication of the current user
+func (twoFactorController *twoFactorController) Disable(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	var codeDTO dto.DTOTwoFactorCode
+	herr := decodeJSON(r, &codeDTO)
+	if herr != nil {
+		return nil, herr
+	}
+	sessionClaims := sessionservice.GetSessionClaimsFromContext(r.Context())
+	herr = twoFactorController.twoFactorService.Disable(sessionClaims.UserID, codeDTO.Code)
+	if herr != nil {
+		return nil, herr
+	}
+	return nil, twoFactorController.sessionService.RotateSession(sessionClaims, w)
+}
+
+// Replace the recovery codes of the current user
+func (twoFactorController *twoFactorController) RegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	var codeDTO dto.DTOTwoFactorCode
+	herr := decodeJSON(r, &codeDTO)
+	if herr != nil {
+		return nil, herr
+	}
+	recoveryCodes, herr := twoFactorController.twoFactorService.RegenerateRecoveryCodes(
+		sessionservice.GetSessionClaimsFromContext(r.Context()).UserID, codeDTO.Code)
+	if herr != nil {
+		return nil, herr
+	}
+	return dto.DTORecoveryCodes{RecoveryCodes: recoveryCodes}, nil
+}
+
+// Remove the authenticator of a user who lost it and its recovery codes
+func (twoFactorController *twoFactorController) ResetTwoFactor(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	userID, herr := getUUIDFromPath(r, "id")
+	if herr != nil {
+		return nil, herr
+	}
+	_, herr = twoFactorController.userService.GetUserByID(userID)
+	if herr != nil {
+		return nil, herr
+	}
+	return nil, twoFactorController.twoFactorService.Reset(userID)
+}
+
+// Return the user of an enrolment challenge
+//
+// A user who has a second factor, TOTP authenticator or WebAuthn credential, has to log in with it.
+func (twoFactorController *twoFactorController) getEnrolmentUserID(challenge string) (uuid.UUID, httperrors.HTTPError) {
+	userID, herr := twoFactorController.twoFactorService.GetEnrolmentChallengeUserID(challenge)
+	if herr != nil {
+		return uuid.Nil, herr
+	}
+	totpEnabled, herr := twoFactorController.twoFactorService.IsEnabled(userID)
+	if herr != nil {
+		return uuid.Nil, herr
+	}
+	webAuthnEnabled, herr := twoFactorController.webAuthnService.HasCredentials(userID)
+	if herr != nil {
+		return uuid.Nil, herr
+	}
+	if totpEnabled || webAuthnEnabled {
+		return uuid.Nil, twofactorservice.HERRTwoFactorAlreadyEnabled
+	}
+	return userID, nil
+}
+
+// Generate a new authenticator for the user
+func (twoFactorController *twoFactorController) beginEnrolment(userID uuid.UUID) (any, httperrors.HTTPError) {
+	enrolment, herr := twoFactorController.twoFactorService.BeginEnrolment(userID)
+	if herr != nil {
+		return nil, herr
+	}
+	return dto.DTOTOTPEnrolment{
+		Secret: enrolment.Secret,
+		URI:    enrolment.URI,
+	}, nil
+}
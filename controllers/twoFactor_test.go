@@ -0,0 +1,169 @@
+package controllers_test
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ditrit/badaas/controllers"
+	mocksControllers "github.com/ditrit/badaas/mocks/controllers"
+	mocksSessionService "github.com/ditrit/badaas/mocks/services/sessionservice"
+	mocksTwoFactorService "github.com/ditrit/badaas/mocks/services/twofactorservice"
+	mocksUserService "github.com/ditrit/badaas/mocks/services/userservice"
+	mocksWebAuthnService "github.com/ditrit/badaas/mocks/services/webauthnservice"
+	"github.com/ditrit/badaas/persistence/models"
+	"github.com/ditrit/badaas/persistence/models/dto"
+	"github.com/ditrit/badaas/services/twofactorservice"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func Test_LoginWithCode(t *testing.T) {
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Username: "bob", Email: "bob@email.com"}
+	twoFactorService := mocksTwoFactorService.NewTwoFactorService(t)
+	twoFactorService.On("GetChallengeUserID", "challenge").Return(user.ID, nil)
+	twoFactorService.On("VerifyChallenge", "challenge", "123456").Return(user.ID, nil)
+	userService := mocksUserService.NewUserService(t)
+	userService.On("GetUserByID", user.ID).Return(user, nil)
+	loginGate := mocksControllers.NewLoginGate(t)
+	loginGate.On("CheckAttempt", "bob@email.com", mock.Anything, mock.Anything).Return(nil)
+	loginGate.On("CompleteLogin", user, mock.Anything, mock.Anything).
+		Return(dto.DTOLoginSuccess{ID: user.ID.String(), Username: "bob", Email: "bob@email.com"}, nil)
+
+	controller := controllers.NewTwoFactorController(zap.L(), twoFactorService, userService,
+		mocksSessionService.NewSessionService(t), mocksWebAuthnService.NewWebAuthnService(t), loginGate)
+	request := httptest.NewRequest("POST", "/login/2fa", strings.NewReader(`{"challenge": "challenge", "code": "123456"}`))
+
+	payload, err := controller.LoginWithCode(httptest.NewRecorder(), request)
+	assert.NoError(t, err)
+	assert.Equal(t, dto.DTOLoginSuccess{ID: user.ID.String(), Username: "bob", Email: "bob@email.com"}, payload)
+}
+
+func Test_LoginWithCode_InvalidCode(t *testing.T) {
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Email: "bob@email.com"}
+	twoFactorService := mocksTwoFactorService.NewTwoFactorService(t)
+	twoFactorService.On("GetChallengeUserID", "challenge").Return(user.ID, nil)
+	twoFactorService.On("VerifyChallenge", "challenge", "000000").Return(uuid.Nil, twofactorservice.HERRInvalidCode)
+	userService := mocksUserService.NewUserService(t)
+	userService.On("GetUserByID", user.ID).Return(user, nil)
+	loginGate := mocksControllers.NewLoginGate(t)
+	loginGate.On("CheckAttempt", "bob@email.com", mock.Anything, mock.Anything).Return(nil)
+	loginGate.On("RecordFailure", "bob@email.com", mock.Anything).Return()
+
+	controller := controllers.NewTwoFactorController(zap.L(), twoFactorService, userService,
+		mocksSessionService.NewSessionService(t), mocksWebAuthnService.NewWebAuthnService(t), loginGate)
+	request := httptest.NewRequest("POST", "/login/2fa", strings.NewReader(`{"challenge": "challenge", "code": "000000"}`))
+
+	payload, err := controller.LoginWithCode(httptest.NewRecorder(), request)
+	assert.Equal(t, twofactorservice.HERRInvalidCode, err)
+	assert.Nil(t, payload)
+}
+
+func Test_ConfirmLoginEnrolment(t *testing.T) {
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Username: "bob", Email: "bob@email.com"}
+	twoFactorService := mocksTwoFactorService.NewTwoFactorService(t)
+	twoFactorService.On("GetEnrolmentChallengeUserID", "challenge").Return(user.ID, nil)
+	twoFactorService.On("IsEnabled", user.ID).Return(false, nil)
+	twoFactorService.On("ConfirmEnrolment", user.ID, "123456").Return([]string{"aaaa-bbbb-cccc-dddd"}, nil)
+	webAuthnService := mocksWebAuthnService.NewWebAuthnService(t)
+	webAuthnService.On("HasCredentials", user.ID).Return(false, nil)
+	twoFactorService.On("DeleteChallenge", "challenge").Return(nil)
+	userService := mocksUserService.NewUserService(t)
+	userService.On("GetUserByID", user.ID).Return(user, nil)
+	loginGate := mocksControllers.NewLoginGate(t)
+	loginGate.On("CheckAttempt", "bob@email.com", mock.Anything, mock.Anything).Return(nil)
+	loginGate.On("CompleteLogin", user, mock.Anything, mock.Anything).
+		Return(dto.DTOLoginSuccess{ID: user.ID.String(), Username: "bob", Email: "bob@email.com"}, nil)
+
+	controller := controllers.NewTwoFactorController(zap.L(), twoFactorService, userService,
+		mocksSessionService.NewSessionService(t), webAuthnService, loginGate)
+	request := httptest.NewRequest("POST", "/login/2fa/enrol/confirm", strings.NewReader(`{"challenge": "challenge", "code": "123456"}`))
+
+	payload, err := controller.ConfirmLoginEnrolment(httptest.NewRecorder(), request)
+	assert.NoError(t, err)
+	assert.Equal(t, dto.DTOLoginEnrolmentSuccess{
+		DTOLoginSuccess: dto.DTOLoginSuccess{ID: user.ID.String(), Username: "bob", Email: "bob@email.com"},
+		RecoveryCodes:   []string{"aaaa-bbbb-cccc-dddd"},
+	}, payload)
+}
+
+func Test_LoginEnrolment_InvalidForVerificationChallenges(t *testing.T) {
+	// a WebAuthn-only user receives a verification challenge, which can't enrol a TOTP authenticator
+	twoFactorService := mocksTwoFactorService.NewTwoFactorService(t)
+	twoFactorService.On("GetEnrolmentChallengeUserID", "challenge").Return(uuid.Nil, twofactorservice.HERRInvalidChallenge)
+
+	controller := controllers.NewTwoFactorController(zap.L(), twoFactorService, mocksUserService.NewUserService(t),
+		mocksSessionService.NewSessionService(t), mocksWebAuthnService.NewWebAuthnService(t), mocksControllers.NewLoginGate(t))
+
+	payload, err := controller.BeginLoginEnrolment(httptest.NewRecorder(),
+		httptest.NewRequest("POST", "/login/2fa/enrol", strings.NewReader(`{"challenge": "challenge"}`)))
+	assert.Equal(t, twofactorservice.HERRInvalidChallenge, err)
+	assert.Nil(t, payload)
+	payload, err = controller.ConfirmLoginEnrolment(httptest.NewRecorder(),
+		httptest.NewRequest("POST", "/login/2fa/enrol/confirm", strings.NewReader(`{"challenge": "challenge", "code": "123456"}`)))
+	assert.Equal(t, twofactorservice.HERRInvalidChallenge, err)
+	assert.Nil(t, payload)
+}
+
+func Test_LoginEnrolment_WebAuthnUser(t *testing.T) {
+	// the user registered a passkey since the enrolment challenge was created
+	userID := uuid.New()
+	twoFactorService := mocksTwoFactorService.NewTwoFactorService(t)
+	twoFactorService.On("GetEnrolmentChallengeUserID", "challenge").Return(userID, nil)
+	twoFactorService.On("IsEnabled", userID).Return(false, nil)
+	webAuthnService := mocksWebAuthnService.NewWebAuthnService(t)
+	webAuthnService.On("HasCredentials", userID).Return(true, nil)
+
+	controller := controllers.NewTwoFactorController(zap.L(), twoFactorService, mocksUserService.NewUserService(t),
+		mocksSessionService.NewSessionService(t), webAuthnService, mocksControllers.NewLoginGate(t))
+
+	payload, err := controller.BeginLoginEnrolment(httptest.NewRecorder(),
+		httptest.NewRequest("POST", "/login/2fa/enrol", strings.NewReader(`{"challenge": "challenge"}`)))
+	assert.Equal(t, twofactorservice.HERRTwoFactorAlreadyEnabled, err)
+	assert.Nil(t, payload)
+}
+
+func Test_BeginEnrolment(t *testing.T) {
+	userID := uuid.New()
+	twoFactorService := mocksTwoFactorService.NewTwoFactorService(t)
+	twoFactorService.On("BeginEnrolment", userID).Return(&twofactorservice.TOTPEnrolment{Secret: "SECRET", URI: "otpauth://totp/x"}, nil)
+
+	controller := controllers.NewTwoFactorController(zap.L(), twoFactorService,
+		mocksUserService.NewUserService(t), mocksSessionService.NewSessionService(t), mocksWebAuthnService.NewWebAuthnService(t), mocksControllers.NewLoginGate(t))
+	request := makeAuthenticatedRequest(userID, "POST", "/me/2fa", "", nil)
+
+	payload, err := controller.BeginEnrolment(httptest.NewRecorder(), request)
+	assert.NoError(t, err)
+	assert.Equal(t, dto.DTOTOTPEnrolment{Secret: "SECRET", URI: "otpauth://totp/x"}, payload)
+}
+
+func Test_DisableTwoFactor_Required(t *testing.T) {
+	userID := uuid.New()
+	twoFactorService := mocksTwoFactorService.NewTwoFactorService(t)
+	twoFactorService.On("Disable", userID, "123456").Return(twofactorservice.HERRTwoFactorRequired)
+
+	controller := controllers.NewTwoFactorController(zap.L(), twoFactorService,
+		mocksUserService.NewUserService(t), mocksSessionService.NewSessionService(t), mocksWebAuthnService.NewWebAuthnService(t), mocksControllers.NewLoginGate(t))
+	request := makeAuthenticatedRequest(userID, "POST", "/me/2fa/disable", `{"code": "123456"}`, nil)
+
+	payload, err := controller.Disable(httptest.NewRecorder(), request)
+	assert.Equal(t, twofactorservice.HERRTwoFactorRequired, err)
+	assert.Nil(t, payload)
+}
+
+func Test_ResetTwoFactor(t *testing.T) {
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}}
+	userService := mocksUserService.NewUserService(t)
+	userService.On("GetUserByID", user.ID).Return(user, nil)
+	twoFactorService := mocksTwoFactorService.NewTwoFactorService(t)
+	twoFactorService.On("Reset", user.ID).Return(nil)
+
+	controller := controllers.NewTwoFactorController(zap.L(), twoFactorService, userService, mocksSessionService.NewSessionService(t), mocksWebAuthnService.NewWebAuthnService(t), mocksControllers.NewLoginGate(t))
+	request := makeAuthenticatedRequest(uuid.New(), "DELETE", "/users/"+user.ID.String()+"/2fa", "", map[string]string{"id": user.ID.String()})
+
+	payload, err := controller.ResetTwoFactor(httptest.NewRecorder(), request)
+	assert.NoError(t, err)
+	assert.Nil(t, payload)
+}
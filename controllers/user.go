@@ -0,0 +1,227 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/ditrit/badaas/configuration"
+	"github.com/ditrit/badaas/httperrors"
+	"github.com/ditrit/badaas/persistence/models"
+	"github.com/ditrit/badaas/persistence/models/dto"
+	"github.com/ditrit/badaas/persistence/pagination"
+	"github.com/ditrit/badaas/services/loginthrottlingservice"
+	"github.com/ditrit/badaas/services/sessionservice"
+	"github.com/ditrit/badaas/services/userservice"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Users administration Controller
+type UserController interface {
+	ListUsers(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	GetUser(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	CreateUser(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	UpdateUser(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	SetPassword(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	DisableUser(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	EnableUser(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	UnlockUser(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	DeleteUser(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+}
+
+// Check interface compliance
+var _ UserController = (*userController)(nil)
+
+// UserController implementation
+type userController struct {
+	logger                  *zap.Logger
+	userService             userservice.UserService
+	paginationConfiguration configuration.PaginationConfiguration
+	loginThrottlingService  loginthrottlingservice.LoginThrottlingService
+}
+
+// UserController constructor
+func NewUserController(
+	logger *zap.Logger,
+	userService userservice.UserService,
+	paginationConfiguration configuration.PaginationConfiguration,
+	loginThrottlingService loginthrottlingservice.LoginThrottlingService,
+) UserController {
+	return &userController{
+		logger:                  logger,
+		userService:             userService,
+		paginationConfiguration: paginationConfiguration,
+		loginThrottlingService:  loginThrottlingService,
+	}
+}
+
+// List the users
+//
+// Query parameters:
+//   - q: only return the users whose username or email contains this value
+//   - page: the page number, starting at 1
+//   - limit: the number of users per page, capped by the server configuration
+func (userController *userController) ListUsers(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	page, herr := getUintFromQuery(r, "page", 1)
+	if herr != nil {
+		return nil, herr
+	}
+	maxLimit := userController.paginationConfiguration.GetMaxElemPerPage()
+	limit, herr := getUintFromQuery(r, "limit", maxLimit)
+	if herr != nil {
+		return nil, herr
+	}
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+	users, herr := userController.userService.GetUsers(r.URL.Query().Get("q"), pagination.NewPaginator(page, limit))
+	if herr != nil {
+		return nil, herr
+	}
+	dtoUsers := make([]dto.DTOUser, 0, len(users.Ressources))
+	for _, user := range users.Ressources {
+		dtoUsers = append(dtoUsers, makeDTOUser(user))
+	}
+	return dto.DTOUserPage{
+		Users:       dtoUsers,
+		Page:        users.Offset,
+		Limit:       users.Limit,
+		Total:       users.Total,
+		HasNextPage: users.Offset*users.Limit < users.Total,
+	}, nil
+}
+
+// Get a user
+func (userController *userController) GetUser(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	userID, herr := getUUIDFromPath(r, "id")
+	if herr != nil {
+		return nil, herr
+	}
+	user, herr := userController.userService.GetUserByID(userID)
+	if herr != nil {
+		return nil, herr
+	}
+	return makeDTOUser(user), nil
+}
+
+// Create a user
+func (userController *userController) CreateUser(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	var createUserDTO dto.DTOCreateUser
+	herr := decodeJSON(r, &createUserDTO)
+	if herr != nil {
+		return nil, herr
+	}
+	if createUserDTO.Username == "" || createUserDTO.Password == "" {
+		return nil, httperrors.NewHTTPError(http.StatusBadRequest, "invalid user",
+			"the username and the password can't be empty", nil, false)
+	}
+	user, err := userController.userService.NewUser(createUserDTO.Username, createUserDTO.Email, createUserDTO.Password)
+	if err != nil {
+		if herr, ok := err.(httperrors.HTTPError); ok {
+			return nil, herr
+		}
+		return nil, httperrors.NewHTTPError(http.StatusBadRequest, "invalid user", err.Error(), nil, false)
+	}
+	return makeDTOUser(user), nil
+}
+
+// Update the username and the email of a user
+func (userController *userController) UpdateUser(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	userID, herr := getUUIDFromPath(r, "id")
+	if herr != nil {
+		return nil, herr
+	}
+	var updateUserDTO dto.DTOUpdateUser
+	herr = decodeJSON(r, &updateUserDTO)
+	if herr != nil {
+		return nil, herr
+	}
+	user, herr := userController.userService.UpdateUser(userID, updateUserDTO.Username, updateUserDTO.Email)
+	if herr != nil {
+		return nil, herr
+	}
+	return makeDTOUser(user), nil
+}
+
+// Reset the password of a user
+func (userController *userController) SetPassword(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	userID, herr := getUUIDFromPath(r, "id")
+	if herr != nil {
+		return nil, herr
+	}
+	var setPasswordDTO dto.DTOSetPassword
+	herr = decodeJSON(r, &setPasswordDTO)
+	if herr != nil {
+		return nil, herr
+	}
+	return nil, userController.userService.SetPassword(userID, setPasswordDTO.Password)
+}
+
+// Disable a user, an administrator can't disable its own account
+func (userController *userController) DisableUser(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	userID, herr := userController.getOtherUserID(r)
+	if herr != nil {
+		return nil, herr
+	}
+	return nil, userController.userService.SetDisabled(userID, true)
+}
+
+// Enable a user
+func (userController *userController) EnableUser(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	userID, herr := getUUIDFromPath(r, "id")
+	if herr != nil {
+		return nil, herr
+	}
+	return nil, userController.userService.SetDisabled(userID, false)
+}
+
+// Forget the failed login attempts on the account of a user and unlock it
+func (userController *userController) UnlockUser(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	userID, herr := getUUIDFromPath(r, "id")
+	if herr != nil {
+		return nil, herr
+	}
+	user, herr := userController.userService.GetUserByID(userID)
+	if herr != nil {
+		return nil, herr
+	}
+	herr = userController.loginThrottlingService.ResetAccount(user.Email)
+	if herr != nil {
+		return nil, herr
+	}
+	userController.logger.Info("Unlocked the login of the user", zap.String("userID", userID.String()))
+	return nil, nil
+}
+
+// Delete a user, an administrator can't delete its own account
+func (userController *userController) DeleteUser(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	userID, herr := userController.getOtherUserID(r)
+	if herr != nil {
+		return nil, herr
+	}
+	return nil, userController.userService.DeleteUser(userID)
+}
+
+// Return the id of the user in the path, or an error if it is the user making the request
+func (userController *userController) getOtherUserID(r *http.Request) (uuid.UUID, httperrors.HTTPError) {
+	userID, herr := getUUIDFromPath(r, "id")
+	if herr != nil {
+		return uuid.Nil, herr
+	}
+	if userID == sessionservice.GetSessionClaimsFromContext(r.Context()).UserID {
+		return uuid.Nil, httperrors.NewHTTPError(http.StatusBadRequest, "user error",
+			"this action can't be performed on your own account", nil, false)
+	}
+	return userID, nil
+}
+
+// Create a DTOUser from a user
+func makeDTOUser(user *models.User) dto.DTOUser {
+	return dto.DTOUser{
+		ID:            user.ID.String(),
+		Username:      user.Username,
+		Email:         user.Email,
+		Disabled:      user.Disabled,
+		EmailVerified: user.EmailVerified,
+		CreatedAt:     user.CreatedAt,
+	}
+}
@@ -0,0 +1,156 @@
+package controllers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ditrit/badaas/controllers"
+	"github.com/ditrit/badaas/httperrors"
+	mocksConfiguration "github.com/ditrit/badaas/mocks/configuration"
+	mocksLoginThrottlingService "github.com/ditrit/badaas/mocks/services/loginthrottlingservice"
+	mocksUserService "github.com/ditrit/badaas/mocks/services/userservice"
+	"github.com/ditrit/badaas/persistence/models"
+	"github.com/ditrit/badaas/persistence/models/dto"
+	"github.com/ditrit/badaas/persistence/pagination"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func Test_ListUsers(t *testing.T) {
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Username: "bob", Email: "bob@email.com"}
+	userService := mocksUserService.NewUserService(t)
+	userService.On("GetUsers", "bob", mock.Anything).Return(
+		pagination.NewPage([]*models.User{user}, 2, 10, 11), nil)
+	paginationConfiguration := mocksConfiguration.NewPaginationConfiguration(t)
+	paginationConfiguration.On("GetMaxElemPerPage").Return(uint(10))
+
+	controller := controllers.NewUserController(zap.L(), userService, paginationConfiguration, mocksLoginThrottlingService.NewLoginThrottlingService(t))
+	request := makeAuthenticatedRequest(uuid.New(), "GET", "/users?q=bob&page=2&limit=50", "", nil)
+
+	payload, err := controller.ListUsers(httptest.NewRecorder(), request)
+	assert.NoError(t, err)
+	assert.Equal(t, dto.DTOUserPage{
+		Users:       []dto.DTOUser{{ID: user.ID.String(), Username: "bob", Email: "bob@email.com"}},
+		Page:        2,
+		Limit:       10,
+		Total:       11,
+		HasNextPage: false,
+	}, payload)
+	paginator := userService.Calls[0].Arguments.Get(1).(pagination.Paginator)
+	assert.Equal(t, uint(2), paginator.Offset())
+	assert.Equal(t, uint(10), paginator.Limit())
+}
+
+func Test_ListUsers_InvalidPage(t *testing.T) {
+	userService := mocksUserService.NewUserService(t)
+	paginationConfiguration := mocksConfiguration.NewPaginationConfiguration(t)
+
+	controller := controllers.NewUserController(zap.L(), userService, paginationConfiguration, mocksLoginThrottlingService.NewLoginThrottlingService(t))
+	request := makeAuthenticatedRequest(uuid.New(), "GET", "/users?page=abc", "", nil)
+
+	payload, err := controller.ListUsers(httptest.NewRecorder(), request)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, err.(*httperrors.HTTPErrorImpl).Status)
+	assert.Nil(t, payload)
+}
+
+func Test_CreateUser(t *testing.T) {
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Username: "bob", Email: "bob@email.com"}
+	userService := mocksUserService.NewUserService(t)
+	userService.On("NewUser", "bob", "bob@email.com", "1234").Return(user, nil)
+
+	controller := controllers.NewUserController(zap.L(), userService, mocksConfiguration.NewPaginationConfiguration(t), mocksLoginThrottlingService.NewLoginThrottlingService(t))
+	request := makeAuthenticatedRequest(uuid.New(), "POST", "/users",
+		`{"username": "bob", "email": "bob@email.com", "password": "1234"}`, nil)
+
+	payload, err := controller.CreateUser(httptest.NewRecorder(), request)
+	assert.NoError(t, err)
+	assert.Equal(t, dto.DTOUser{ID: user.ID.String(), Username: "bob", Email: "bob@email.com"}, payload)
+}
+
+func Test_CreateUser_MissingPassword(t *testing.T) {
+	userService := mocksUserService.NewUserService(t)
+
+	controller := controllers.NewUserController(zap.L(), userService, mocksConfiguration.NewPaginationConfiguration(t), mocksLoginThrottlingService.NewLoginThrottlingService(t))
+	request := makeAuthenticatedRequest(uuid.New(), "POST", "/users", `{"username": "bob", "email": "bob@email.com"}`, nil)
+
+	payload, err := controller.CreateUser(httptest.NewRecorder(), request)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, err.(*httperrors.HTTPErrorImpl).Status)
+	assert.Nil(t, payload)
+}
+
+func Test_DisableUser(t *testing.T) {
+	userID := uuid.New()
+	userService := mocksUserService.NewUserService(t)
+	userService.On("SetDisabled", userID, true).Return(nil)
+
+	controller := controllers.NewUserController(zap.L(), userService, mocksConfiguration.NewPaginationConfiguration(t), mocksLoginThrottlingService.NewLoginThrottlingService(t))
+	request := makeAuthenticatedRequest(uuid.New(), "POST", "/users/"+userID.String()+"/disable", "",
+		map[string]string{"id": userID.String()})
+
+	payload, err := controller.DisableUser(httptest.NewRecorder(), request)
+	assert.NoError(t, err)
+	assert.Nil(t, payload)
+}
+
+func Test_DisableUser_Self(t *testing.T) {
+	userID := uuid.New()
+	userService := mocksUserService.NewUserService(t)
+
+	controller := controllers.NewUserController(zap.L(), userService, mocksConfiguration.NewPaginationConfiguration(t), mocksLoginThrottlingService.NewLoginThrottlingService(t))
+	request := makeAuthenticatedRequest(userID, "POST", "/users/"+userID.String()+"/disable", "",
+		map[string]string{"id": userID.String()})
+
+	payload, err := controller.DisableUser(httptest.NewRecorder(), request)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, err.(*httperrors.HTTPErrorImpl).Status)
+	assert.Nil(t, payload)
+}
+
+func Test_DeleteUser_Self(t *testing.T) {
+	userID := uuid.New()
+	userService := mocksUserService.NewUserService(t)
+
+	controller := controllers.NewUserController(zap.L(), userService, mocksConfiguration.NewPaginationConfiguration(t), mocksLoginThrottlingService.NewLoginThrottlingService(t))
+	request := makeAuthenticatedRequest(userID, "DELETE", "/users/"+userID.String(), "",
+		map[string]string{"id": userID.String()})
+
+	payload, err := controller.DeleteUser(httptest.NewRecorder(), request)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, err.(*httperrors.HTTPErrorImpl).Status)
+	assert.Nil(t, payload)
+}
+
+func Test_UnlockUser(t *testing.T) {
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Email: "bob@email.com"}
+	userService := mocksUserService.NewUserService(t)
+	userService.On("GetUserByID", user.ID).Return(user, nil)
+	loginThrottlingService := mocksLoginThrottlingService.NewLoginThrottlingService(t)
+	loginThrottlingService.On("ResetAccount", "bob@email.com").Return(nil)
+
+	controller := controllers.NewUserController(zap.L(), userService, mocksConfiguration.NewPaginationConfiguration(t), loginThrottlingService)
+	request := makeAuthenticatedRequest(uuid.New(), "POST", "/users/"+user.ID.String()+"/unlock", "", map[string]string{"id": user.ID.String()})
+
+	payload, err := controller.UnlockUser(httptest.NewRecorder(), request)
+	assert.NoError(t, err)
+	assert.Nil(t, payload)
+}
+
+func Test_UnlockUser_NotFound(t *testing.T) {
+	userID := uuid.New()
+	userService := mocksUserService.NewUserService(t)
+	userService.On("GetUserByID", userID).Return(nil, httperrors.NewErrorNotFound("user", "not found"))
+
+	controller := controllers.NewUserController(zap.L(), userService,
+		mocksConfiguration.NewPaginationConfiguration(t), mocksLoginThrottlingService.NewLoginThrottlingService(t))
+	request := makeAuthenticatedRequest(uuid.New(), "POST", "/users/"+userID.String()+"/unlock", "", map[string]string{"id": userID.String()})
+
+	payload, err := controller.UnlockUser(httptest.NewRecorder(), request)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusNotFound, err.(*httperrors.HTTPErrorImpl).Status)
+	assert.Nil(t, payload)
+}
@@ -0,0 +1,204 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/ditrit/badaas/httperrors"
+	"github.com/ditrit/badaas/persistence/models"
+	"github.com/ditrit/badaas/persistence/models/dto"
+	"github.com/ditrit/badaas/services/sessionservice"
+	"github.com/ditrit/badaas/services/twofactorservice"
+	"github.com/ditrit/badaas/services/userservice"
+	"github.com/ditrit/badaas/services/webauthnservice"
+	"go.uber.org/zap"
+)
+
+// WebAuthn Controller
+//
+// The options handlers start a ceremony whose response is sent to the matching handler.
+// A passkey logs the user in on its own, or completes a login started by BasicLoginHandler with its challenge.
+type WebAuthnController interface {
+	BeginLogin(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	Login(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	BeginSecondFactorLogin(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	SecondFactorLogin(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	ListCredentials(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	BeginRegistration(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	Register(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+	DeleteCredential(http.ResponseWriter, *http.Request) (any, httperrors.HTTPError)
+}
+
+// Check interface compliance
+var _ WebAuthnController = (*webAuthnController)(nil)
+
+// WebAuthnController implementation
+type webAuthnController struct {
+	logger           *zap.Logger
+	webAuthnService  webauthnservice.WebAuthnService
+	twoFactorService twofactorservice.TwoFactorService
+	userService      userservice.UserService
+	loginGate        LoginGate
+}
+
+// WebAuthnController constructor
+func NewWebAuthnController(
+	logger *zap.Logger,
+	webAuthnService webauthnservice.WebAuthnService,
+	twoFactorService twofactorservice.TwoFactorService,
+	userService userservice.UserService,
+	loginGate LoginGate,
+) WebAuthnController {
+	return &webAuthnController{
+		logger:           logger,
+		webAuthnService:  webAuthnService,
+		twoFactorService: twoFactorService,
+		userService:      userService,
+		loginGate:        loginGate,
+	}
+}
+
+// Start a login with a passkey
+func (webAuthnController *webAuthnController) BeginLogin(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	options, herr := webAuthnController.webAuthnService.BeginLogin(nil)
+	if herr != nil {
+		return nil, herr
+	}
+	return dto.DTOWebAuthnRequestOptions{PublicKey: options}, nil
+}
+
+// Log in with a passkey, no password nor second factor is needed
+func (webAuthnController *webAuthnController) Login(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	var loginDTO dto.DTOWebAuthnLogin
+	herr := decodeJSON(r, &loginDTO)
+	if herr != nil {
+		return nil, herr
+	}
+	userID, herr := webAuthnController.webAuthnService.FinishLogin(&loginDTO.Credential)
+	if herr != nil {
+		return nil, herr
+	}
+	user, herr := webAuthnController.userService.GetUserByID(userID)
+	if herr != nil {
+		return nil, herr
+	}
+	herr = webAuthnController.userService.CheckCanLogIn(user)
+	if herr != nil {
+		return nil, herr
+	}
+	loginSuccess, herr := webAuthnController.loginGate.CompleteLogin(user, r, w)
+	if herr != nil {
+		return nil, herr
+	}
+	return loginSuccess, nil
+}
+
+// Start the second step of a login with a WebAuthn credential of the user of the challenge
+func (webAuthnController *webAuthnController) BeginSecondFactorLogin(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	var optionsDTO dto.DTOLoginWebAuthnOptions
+	herr := decodeJSON(r, &optionsDTO)
+	if herr != nil {
+		return nil, herr
+	}
+	userID, herr := webAuthnController.twoFactorService.GetChallengeUserID(optionsDTO.Challenge)
+	if herr != nil {
+		return nil, herr
+	}
+	options, herr := webAuthnController.webAuthnService.BeginLogin(&userID)
+	if herr != nil {
+		return nil, herr
+	}
+	return dto.DTOWebAuthnRequestOptions{PublicKey: options}, nil
+}
+
+// Complete a login with a WebAuthn credential of the user of the challenge
+func (webAuthnController *webAuthnController) SecondFactorLogin(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	var loginDTO dto.DTOLoginWebAuthn
+	herr := decodeJSON(r, &loginDTO)
+	if herr != nil {
+		return nil, herr
+	}
+	challengeUserID, herr := webAuthnController.twoFactorService.GetChallengeUserID(loginDTO.Challenge)
+	if herr != nil {
+		return nil, herr
+	}
+	userID, herr := webAuthnController.webAuthnService.FinishLogin(&loginDTO.Credential)
+	if herr != nil {
+		return nil, herr
+	}
+	if userID != challengeUserID {
+		return nil, webauthnservice.HERRInvalidCredential
+	}
+	herr = webAuthnController.twoFactorService.DeleteChallenge(loginDTO.Challenge)
+	if herr != nil {
+		return nil, herr
+	}
+	user, herr := webAuthnController.userService.GetUserByID(userID)
+	if herr != nil {
+		return nil, herr
+	}
+	loginSuccess, herr := webAuthnController.loginGate.CompleteLogin(user, r, w)
+	if herr != nil {
+		return nil, herr
+	}
+	return loginSuccess, nil
+}
+
+// Return the WebAuthn credentials of the current user
+func (webAuthnController *webAuthnController) ListCredentials(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	credentials, herr := webAuthnController.webAuthnService.GetCredentials(
+		sessionservice.GetSessionClaimsFromContext(r.Context()).UserID)
+	if herr != nil {
+		return nil, herr
+	}
+	dtoCredentials := make([]dto.DTOWebAuthnCredential, 0, len(credentials))
+	for _, credential := range credentials {
+		dtoCredentials = append(dtoCredentials, makeDTOWebAuthnCredential(credential))
+	}
+	return dtoCredentials, nil
+}
+
+// Start the registration of a WebAuthn credential for the current user
+func (webAuthnController *webAuthnController) BeginRegistration(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	options, herr := webAuthnController.webAuthnService.BeginRegistration(
+		sessionservice.GetSessionClaimsFromContext(r.Context()).UserID)
+	if herr != nil {
+		return nil, herr
+	}
+	return dto.DTOWebAuthnCreationOptions{PublicKey: options}, nil
+}
+
+// Register a WebAuthn credential for the current user
+func (webAuthnController *webAuthnController) Register(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	var registrationDTO dto.DTOWebAuthnRegistration
+	herr := decodeJSON(r, &registrationDTO)
+	if herr != nil {
+		return nil, herr
+	}
+	credential, herr := webAuthnController.webAuthnService.FinishRegistration(
+		sessionservice.GetSessionClaimsFromContext(r.Context()).UserID,
+		registrationDTO.Name, &registrationDTO.Credential)
+	if herr != nil {
+		return nil, herr
+	}
+	return makeDTOWebAuthnCredential(credential), nil
+}
+
+// Delete a WebAuthn credential of the current user
+func (webAuthnController *webAuthnController) DeleteCredential(w http.ResponseWriter, r *http.Request) (any, httperrors.HTTPError) {
+	credentialID, herr := getUUIDFromPath(r, "id")
+	if herr != nil {
+		return nil, herr
+	}
+	return nil, webAuthnController.webAuthnService.DeleteCredential(
+		sessionservice.GetSessionClaimsFromContext(r.Context()).UserID, credentialID)
+}
+
+// Create a DTOWebAuthnCredential from a credential
+func makeDTOWebAuthnCredential(credential *models.WebAuthnCredential) dto.DTOWebAuthnCredential {
+	return dto.DTOWebAuthnCredential{
+		ID:         credential.ID.String(),
+		Name:       credential.Name,
+		CreatedAt:  credential.CreatedAt,
+		LastUsedAt: credential.LastUsedAt,
+	}
+}
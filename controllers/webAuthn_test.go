@@ -0,0 +1,116 @@
+package controllers_test
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ditrit/badaas/controllers"
+	mocksControllers "github.com/ditrit/badaas/mocks/controllers"
+	mocksTwoFactorService "github.com/ditrit/badaas/mocks/services/twofactorservice"
+	mocksUserService "github.com/ditrit/badaas/mocks/services/userservice"
+	mocksWebAuthnService "github.com/ditrit/badaas/mocks/services/webauthnservice"
+	"github.com/ditrit/badaas/persistence/models"
+	"github.com/ditrit/badaas/persistence/models/dto"
+	"github.com/ditrit/badaas/services/auth/protocols/webauthn"
+	"github.com/ditrit/badaas/services/userservice"
+	"github.com/ditrit/badaas/services/webauthnservice"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+const assertionJSON = `{"credential": {"id": "AQI", "rawId": "AQI", "type": "public-key", "response": {}}}`
+
+func Test_WebAuthnLogin(t *testing.T) {
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Username: "bob", Email: "bob@email.com"}
+	webAuthnService := mocksWebAuthnService.NewWebAuthnService(t)
+	webAuthnService.On("FinishLogin", mock.MatchedBy(func(response *webauthn.CredentialAssertionResponse) bool {
+		return string(response.RawID) == "\x01\x02"
+	})).Return(user.ID, nil)
+	userService := mocksUserService.NewUserService(t)
+	userService.On("GetUserByID", user.ID).Return(user, nil)
+	userService.On("CheckCanLogIn", user).Return(nil)
+	loginGate := mocksControllers.NewLoginGate(t)
+	loginGate.On("CompleteLogin", user, mock.Anything, mock.Anything).Return(dto.DTOLoginSuccess{ID: user.ID.String(), Username: user.Username, Email: user.Email}, nil)
+
+	controller := controllers.NewWebAuthnController(zap.L(), webAuthnService,
+		mocksTwoFactorService.NewTwoFactorService(t), userService, loginGate)
+	request := httptest.NewRequest("POST", "/login/webauthn", strings.NewReader(assertionJSON))
+
+	payload, err := controller.Login(httptest.NewRecorder(), request)
+	assert.NoError(t, err)
+	assert.Equal(t, dto.DTOLoginSuccess{ID: user.ID.String(), Username: "bob", Email: "bob@email.com"}, payload)
+}
+
+func Test_WebAuthnLogin_UserDisabled(t *testing.T) {
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Disabled: true}
+	webAuthnService := mocksWebAuthnService.NewWebAuthnService(t)
+	webAuthnService.On("FinishLogin", mock.Anything).Return(user.ID, nil)
+	userService := mocksUserService.NewUserService(t)
+	userService.On("GetUserByID", user.ID).Return(user, nil)
+	userService.On("CheckCanLogIn", user).Return(userservice.HERRUserDisabled)
+
+	controller := controllers.NewWebAuthnController(zap.L(), webAuthnService,
+		mocksTwoFactorService.NewTwoFactorService(t), userService, mocksControllers.NewLoginGate(t))
+	request := httptest.NewRequest("POST", "/login/webauthn", strings.NewReader(assertionJSON))
+
+	payload, err := controller.Login(httptest.NewRecorder(), request)
+	assert.Equal(t, userservice.HERRUserDisabled, err)
+	assert.Nil(t, payload)
+}
+
+func Test_WebAuthnSecondFactorLogin(t *testing.T) {
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Username: "bob", Email: "bob@email.com"}
+	twoFactorService := mocksTwoFactorService.NewTwoFactorService(t)
+	twoFactorService.On("GetChallengeUserID", "challenge").Return(user.ID, nil)
+	twoFactorService.On("DeleteChallenge", "challenge").Return(nil)
+	webAuthnService := mocksWebAuthnService.NewWebAuthnService(t)
+	webAuthnService.On("FinishLogin", mock.Anything).Return(user.ID, nil)
+	userService := mocksUserService.NewUserService(t)
+	userService.On("GetUserByID", user.ID).Return(user, nil)
+	loginGate := mocksControllers.NewLoginGate(t)
+	loginGate.On("CompleteLogin", user, mock.Anything, mock.Anything).Return(dto.DTOLoginSuccess{ID: user.ID.String(), Username: user.Username, Email: user.Email}, nil)
+
+	controller := controllers.NewWebAuthnController(zap.L(), webAuthnService, twoFactorService, userService, loginGate)
+	request := httptest.NewRequest("POST", "/login/2fa/webauthn", strings.NewReader(
+		`{"challenge": "challenge", "credential": {"id": "AQI", "rawId": "AQI", "type": "public-key", "response": {}}}`))
+
+	payload, err := controller.SecondFactorLogin(httptest.NewRecorder(), request)
+	assert.NoError(t, err)
+	assert.Equal(t, dto.DTOLoginSuccess{ID: user.ID.String(), Username: "bob", Email: "bob@email.com"}, payload)
+}
+
+func Test_WebAuthnSecondFactorLogin_OtherUser(t *testing.T) {
+	twoFactorService := mocksTwoFactorService.NewTwoFactorService(t)
+	twoFactorService.On("GetChallengeUserID", "challenge").Return(uuid.New(), nil)
+	webAuthnService := mocksWebAuthnService.NewWebAuthnService(t)
+	webAuthnService.On("FinishLogin", mock.Anything).Return(uuid.New(), nil)
+
+	controller := controllers.NewWebAuthnController(zap.L(), webAuthnService, twoFactorService,
+		mocksUserService.NewUserService(t), mocksControllers.NewLoginGate(t))
+	request := httptest.NewRequest("POST", "/login/2fa/webauthn", strings.NewReader(
+		`{"challenge": "challenge", "credential": {"id": "AQI", "rawId": "AQI", "type": "public-key", "response": {}}}`))
+
+	payload, err := controller.SecondFactorLogin(httptest.NewRecorder(), request)
+	assert.Equal(t, webauthnservice.HERRInvalidCredential, err)
+	assert.Nil(t, payload)
+}
+
+func Test_WebAuthnRegister(t *testing.T) {
+	userID := uuid.New()
+	credential := &models.WebAuthnCredential{BaseModel: models.BaseModel{ID: uuid.New()}, UserID: userID, Name: "My phone"}
+	webAuthnService := mocksWebAuthnService.NewWebAuthnService(t)
+	webAuthnService.On("FinishRegistration", userID, "My phone", mock.AnythingOfType("*webauthn.CredentialCreationResponse")).
+		Return(credential, nil)
+
+	controller := controllers.NewWebAuthnController(zap.L(), webAuthnService, mocksTwoFactorService.NewTwoFactorService(t),
+		mocksUserService.NewUserService(t), mocksControllers.NewLoginGate(t))
+	request := makeAuthenticatedRequest(userID, "POST", "/me/webauthn",
+		`{"name": "My phone", "credential": {"id": "AQI", "rawId": "AQI", "type": "public-key", "response": {}}}`, nil)
+
+	payload, err := controller.Register(httptest.NewRecorder(), request)
+	assert.NoError(t, err)
+	assert.Equal(t, dto.DTOWebAuthnCredential{ID: credential.ID.String(), Name: "My phone"}, payload)
+}
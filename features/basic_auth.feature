@@ -14,7 +14,7 @@ Feature: Login as superadmin using the basic authentification
       | password | wrongpassword             | string |
     Then I expect status code is "401"
     And I expect response field "err" is "wrong password"
-    And I expect response field "msg" is "the provided password is incorrect"
+    And I expect response field "msg" is "the provided email or password is incorrect"
     And I expect response field "status" is "Unauthorized"
 
   Scenario: Should be a success if we logout after a successful login
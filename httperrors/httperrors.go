@@ -42,6 +42,8 @@ type HTTPErrorImpl struct {
 	Message     string
 	GolangError error
 	toLog       bool
+	// The validation errors of the fields of the request, by field name
+	Fields map[string]string
 }
 
 // Convert an HTTPError to a json string
@@ -50,6 +52,7 @@ func (httpError *HTTPErrorImpl) ToJSON() string {
 		Error:   httpError.Err,
 		Message: httpError.Message,
 		Status:  http.StatusText(httpError.Status),
+		Fields:  httpError.Fields,
 	}
 	payload, _ := json.Marshal(dto)
 	return string(payload)
@@ -125,3 +128,25 @@ func NewUnauthorizedError(errorName string, msg string) HTTPError {
 		true,
 	)
 }
+
+// A contructor for an HttpError "Forbidden"
+func NewForbiddenError(errorName string, msg string) HTTPError {
+	return NewHTTPError(
+		http.StatusForbidden,
+		errorName,
+		msg,
+		nil,
+		true,
+	)
+}
+
+// A contructor for an HttpError "Bad Request" describing the invalid fields of the request
+func NewValidationError(msg string, fields map[string]string) HTTPError {
+	return &HTTPErrorImpl{
+		Status:  http.StatusBadRequest,
+		Err:     "validation error",
+		Message: msg,
+		Fields:  fields,
+		toLog:   false,
+	}
+}
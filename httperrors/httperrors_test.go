@@ -117,3 +117,24 @@ func TestNewUnauthorizedError(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, http.StatusText(http.StatusUnauthorized), dto.Status)
 }
+
+func TestNewForbiddenError(t *testing.T) {
+	error := httperrors.NewForbiddenError("permission denied", "missing permission roles:manage")
+	assert.NotNil(t, error)
+	assert.True(t, error.Log())
+	dto := new(dto.DTOHTTPError)
+	err := json.Unmarshal([]byte(error.ToJSON()), &dto)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusText(http.StatusForbidden), dto.Status)
+	assert.Equal(t, "permission denied", dto.Error)
+}
+
+func TestNewValidationError(t *testing.T) {
+	error := httperrors.NewValidationError("invalid user", map[string]string{"email": "the email is not valid"})
+	assert.False(t, error.Log())
+
+	var content map[string]any
+	json.Unmarshal([]byte(error.ToJSON()), &content)
+	assert.Equal(t, http.StatusText(http.StatusBadRequest), content["status"])
+	assert.Equal(t, map[string]any{"email": "the email is not valid"}, content["fields"])
+}
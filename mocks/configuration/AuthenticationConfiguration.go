@@ -0,0 +1,68 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	mock "github.com/stretchr/testify/mock"
+	zap "go.uber.org/zap"
+)
+
+// AuthenticationConfiguration is an autogenerated mock type for the AuthenticationConfiguration type
+type AuthenticationConfiguration struct {
+	mock.Mock
+}
+
+// GetRealm provides a mock function with given fields:
+func (_m *AuthenticationConfiguration) GetRealm() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// GetSchemes provides a mock function with given fields:
+func (_m *AuthenticationConfiguration) GetSchemes() []string {
+	ret := _m.Called()
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func() []string); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	return r0
+}
+
+// Log provides a mock function with given fields: logger
+func (_m *AuthenticationConfiguration) Log(logger *zap.Logger) {
+	_m.Called(logger)
+}
+
+// Reload provides a mock function with given fields:
+func (_m *AuthenticationConfiguration) Reload() {
+	_m.Called()
+}
+
+type mockConstructorTestingTNewAuthenticationConfiguration interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewAuthenticationConfiguration creates a new instance of AuthenticationConfiguration. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewAuthenticationConfiguration(t mockConstructorTestingTNewAuthenticationConfiguration) *AuthenticationConfiguration {
+	mock := &AuthenticationConfiguration{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,164 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	mock "github.com/stretchr/testify/mock"
+	zap "go.uber.org/zap"
+)
+
+// CookieConfiguration is an autogenerated mock type for the CookieConfiguration type
+type CookieConfiguration struct {
+	mock.Mock
+}
+
+// GetCSRFCookieName provides a mock function with given fields:
+func (_m *CookieConfiguration) GetCSRFCookieName() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// GetCSRFEnabled provides a mock function with given fields:
+func (_m *CookieConfiguration) GetCSRFEnabled() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// GetCSRFHeaderName provides a mock function with given fields:
+func (_m *CookieConfiguration) GetCSRFHeaderName() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// GetDomain provides a mock function with given fields:
+func (_m *CookieConfiguration) GetDomain() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// GetHostPrefix provides a mock function with given fields:
+func (_m *CookieConfiguration) GetHostPrefix() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// GetName provides a mock function with given fields:
+func (_m *CookieConfiguration) GetName() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// GetPath provides a mock function with given fields:
+func (_m *CookieConfiguration) GetPath() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// GetSameSite provides a mock function with given fields:
+func (_m *CookieConfiguration) GetSameSite() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// GetSecure provides a mock function with given fields:
+func (_m *CookieConfiguration) GetSecure() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// Log provides a mock function with given fields: logger
+func (_m *CookieConfiguration) Log(logger *zap.Logger) {
+	_m.Called(logger)
+}
+
+// Reload provides a mock function with given fields:
+func (_m *CookieConfiguration) Reload() {
+	_m.Called()
+}
+
+type mockConstructorTestingTNewCookieConfiguration interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewCookieConfiguration creates a new instance of CookieConfiguration. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewCookieConfiguration(t mockConstructorTestingTNewCookieConfiguration) *CookieConfiguration {
+	mock := &CookieConfiguration{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
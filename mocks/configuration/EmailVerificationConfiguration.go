@@ -0,0 +1,83 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	time "time"
+
+	mock "github.com/stretchr/testify/mock"
+
+	zap "go.uber.org/zap"
+)
+
+// EmailVerificationConfiguration is an autogenerated mock type for the EmailVerificationConfiguration type
+type EmailVerificationConfiguration struct {
+	mock.Mock
+}
+
+// GetRequired provides a mock function with given fields:
+func (_m *EmailVerificationConfiguration) GetRequired() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// GetResendInterval provides a mock function with given fields:
+func (_m *EmailVerificationConfiguration) GetResendInterval() time.Duration {
+	ret := _m.Called()
+
+	var r0 time.Duration
+	if rf, ok := ret.Get(0).(func() time.Duration); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(time.Duration)
+	}
+
+	return r0
+}
+
+// GetTokenDuration provides a mock function with given fields:
+func (_m *EmailVerificationConfiguration) GetTokenDuration() time.Duration {
+	ret := _m.Called()
+
+	var r0 time.Duration
+	if rf, ok := ret.Get(0).(func() time.Duration); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(time.Duration)
+	}
+
+	return r0
+}
+
+// Log provides a mock function with given fields: logger
+func (_m *EmailVerificationConfiguration) Log(logger *zap.Logger) {
+	_m.Called(logger)
+}
+
+// Reload provides a mock function with given fields:
+func (_m *EmailVerificationConfiguration) Reload() {
+	_m.Called()
+}
+
+type mockConstructorTestingTNewEmailVerificationConfiguration interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewEmailVerificationConfiguration creates a new instance of EmailVerificationConfiguration. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewEmailVerificationConfiguration(t mockConstructorTestingTNewEmailVerificationConfiguration) *EmailVerificationConfiguration {
+	mock := &EmailVerificationConfiguration{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
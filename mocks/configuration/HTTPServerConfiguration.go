@@ -3,10 +3,12 @@
 package mocks
 
 import (
-	time "time"
+	net "net"
 
 	mock "github.com/stretchr/testify/mock"
 
+	time "time"
+
 	zap "go.uber.org/zap"
 )
 
@@ -57,6 +59,64 @@ func (_m *HTTPServerConfiguration) GetPort() int {
 	return r0
 }
 
+// GetTLSCertificate provides a mock function with given fields:
+func (_m *HTTPServerConfiguration) GetTLSCertificate() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// GetTLSClientCAs provides a mock function with given fields:
+func (_m *HTTPServerConfiguration) GetTLSClientCAs() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// GetTLSKey provides a mock function with given fields:
+func (_m *HTTPServerConfiguration) GetTLSKey() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// GetTrustedProxies provides a mock function with given fields:
+func (_m *HTTPServerConfiguration) GetTrustedProxies() []*net.IPNet {
+	ret := _m.Called()
+
+	var r0 []*net.IPNet
+	if rf, ok := ret.Get(0).(func() []*net.IPNet); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*net.IPNet)
+		}
+	}
+
+	return r0
+}
+
 // Log provides a mock function with given fields: logger
 func (_m *HTTPServerConfiguration) Log(logger *zap.Logger) {
 	_m.Called(logger)
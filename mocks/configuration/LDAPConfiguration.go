@@ -0,0 +1,268 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	configuration "github.com/ditrit/badaas/configuration"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+
+	zap "go.uber.org/zap"
+)
+
+// LDAPConfiguration is an autogenerated mock type for the LDAPConfiguration type
+type LDAPConfiguration struct {
+	mock.Mock
+}
+
+// GetBaseDN provides a mock function with given fields:
+func (_m *LDAPConfiguration) GetBaseDN() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// GetBindDN provides a mock function with given fields:
+func (_m *LDAPConfiguration) GetBindDN() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// GetBindPassword provides a mock function with given fields:
+func (_m *LDAPConfiguration) GetBindPassword() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// GetCACertificate provides a mock function with given fields:
+func (_m *LDAPConfiguration) GetCACertificate() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// GetEmailAttribute provides a mock function with given fields:
+func (_m *LDAPConfiguration) GetEmailAttribute() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// GetGroupBaseDN provides a mock function with given fields:
+func (_m *LDAPConfiguration) GetGroupBaseDN() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// GetGroupFilter provides a mock function with given fields:
+func (_m *LDAPConfiguration) GetGroupFilter() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// GetGroupRoles provides a mock function with given fields:
+func (_m *LDAPConfiguration) GetGroupRoles() []configuration.LDAPGroupRoles {
+	ret := _m.Called()
+
+	var r0 []configuration.LDAPGroupRoles
+	if rf, ok := ret.Get(0).(func() []configuration.LDAPGroupRoles); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]configuration.LDAPGroupRoles)
+		}
+	}
+
+	return r0
+}
+
+// GetGroupsAttribute provides a mock function with given fields:
+func (_m *LDAPConfiguration) GetGroupsAttribute() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// GetProvisioning provides a mock function with given fields:
+func (_m *LDAPConfiguration) GetProvisioning() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// GetStartTLS provides a mock function with given fields:
+func (_m *LDAPConfiguration) GetStartTLS() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// GetTimeout provides a mock function with given fields:
+func (_m *LDAPConfiguration) GetTimeout() time.Duration {
+	ret := _m.Called()
+
+	var r0 time.Duration
+	if rf, ok := ret.Get(0).(func() time.Duration); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(time.Duration)
+	}
+
+	return r0
+}
+
+// GetURL provides a mock function with given fields:
+func (_m *LDAPConfiguration) GetURL() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// GetUserFilter provides a mock function with given fields:
+func (_m *LDAPConfiguration) GetUserFilter() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// GetUsernameAttribute provides a mock function with given fields:
+func (_m *LDAPConfiguration) GetUsernameAttribute() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// IsEnabled provides a mock function with given fields:
+func (_m *LDAPConfiguration) IsEnabled() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// Log provides a mock function with given fields: logger
+func (_m *LDAPConfiguration) Log(logger *zap.Logger) {
+	_m.Called(logger)
+}
+
+// Reload provides a mock function with given fields:
+func (_m *LDAPConfiguration) Reload() {
+	_m.Called()
+}
+
+type mockConstructorTestingTNewLDAPConfiguration interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewLDAPConfiguration creates a new instance of LDAPConfiguration. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewLDAPConfiguration(t mockConstructorTestingTNewLDAPConfiguration) *LDAPConfiguration {
+	mock := &LDAPConfiguration{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,139 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	time "time"
+
+	mock "github.com/stretchr/testify/mock"
+
+	zap "go.uber.org/zap"
+)
+
+// LoginThrottlingConfiguration is an autogenerated mock type for the LoginThrottlingConfiguration type
+type LoginThrottlingConfiguration struct {
+	mock.Mock
+}
+
+// GetAccountMaxAttempts provides a mock function with given fields:
+func (_m *LoginThrottlingConfiguration) GetAccountMaxAttempts() uint {
+	ret := _m.Called()
+
+	var r0 uint
+	if rf, ok := ret.Get(0).(func() uint); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(uint)
+	}
+
+	return r0
+}
+
+// GetBaseDelay provides a mock function with given fields:
+func (_m *LoginThrottlingConfiguration) GetBaseDelay() time.Duration {
+	ret := _m.Called()
+
+	var r0 time.Duration
+	if rf, ok := ret.Get(0).(func() time.Duration); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(time.Duration)
+	}
+
+	return r0
+}
+
+// GetEnabled provides a mock function with given fields:
+func (_m *LoginThrottlingConfiguration) GetEnabled() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// GetIPMaxAttempts provides a mock function with given fields:
+func (_m *LoginThrottlingConfiguration) GetIPMaxAttempts() uint {
+	ret := _m.Called()
+
+	var r0 uint
+	if rf, ok := ret.Get(0).(func() uint); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(uint)
+	}
+
+	return r0
+}
+
+// GetLockoutDuration provides a mock function with given fields:
+func (_m *LoginThrottlingConfiguration) GetLockoutDuration() time.Duration {
+	ret := _m.Called()
+
+	var r0 time.Duration
+	if rf, ok := ret.Get(0).(func() time.Duration); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(time.Duration)
+	}
+
+	return r0
+}
+
+// GetMaxDelay provides a mock function with given fields:
+func (_m *LoginThrottlingConfiguration) GetMaxDelay() time.Duration {
+	ret := _m.Called()
+
+	var r0 time.Duration
+	if rf, ok := ret.Get(0).(func() time.Duration); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(time.Duration)
+	}
+
+	return r0
+}
+
+// GetWindow provides a mock function with given fields:
+func (_m *LoginThrottlingConfiguration) GetWindow() time.Duration {
+	ret := _m.Called()
+
+	var r0 time.Duration
+	if rf, ok := ret.Get(0).(func() time.Duration); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(time.Duration)
+	}
+
+	return r0
+}
+
+// Log provides a mock function with given fields: logger
+func (_m *LoginThrottlingConfiguration) Log(logger *zap.Logger) {
+	_m.Called(logger)
+}
+
+// Reload provides a mock function with given fields:
+func (_m *LoginThrottlingConfiguration) Reload() {
+	_m.Called()
+}
+
+type mockConstructorTestingTNewLoginThrottlingConfiguration interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewLoginThrottlingConfiguration creates a new instance of LoginThrottlingConfiguration. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewLoginThrottlingConfiguration(t mockConstructorTestingTNewLoginThrottlingConfiguration) *LoginThrottlingConfiguration {
+	mock := &LoginThrottlingConfiguration{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
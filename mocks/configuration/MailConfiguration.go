@@ -0,0 +1,209 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	time "time"
+
+	mock "github.com/stretchr/testify/mock"
+
+	zap "go.uber.org/zap"
+)
+
+// MailConfiguration is an autogenerated mock type for the MailConfiguration type
+type MailConfiguration struct {
+	mock.Mock
+}
+
+// GetDefaultLocale provides a mock function with given fields:
+func (_m *MailConfiguration) GetDefaultLocale() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// GetFilePath provides a mock function with given fields:
+func (_m *MailConfiguration) GetFilePath() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// GetFrom provides a mock function with given fields:
+func (_m *MailConfiguration) GetFrom() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// GetQueueInterval provides a mock function with given fields:
+func (_m *MailConfiguration) GetQueueInterval() time.Duration {
+	ret := _m.Called()
+
+	var r0 time.Duration
+	if rf, ok := ret.Get(0).(func() time.Duration); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(time.Duration)
+	}
+
+	return r0
+}
+
+// GetQueueMaxAttempts provides a mock function with given fields:
+func (_m *MailConfiguration) GetQueueMaxAttempts() uint {
+	ret := _m.Called()
+
+	var r0 uint
+	if rf, ok := ret.Get(0).(func() uint); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(uint)
+	}
+
+	return r0
+}
+
+// GetSMTPHost provides a mock function with given fields:
+func (_m *MailConfiguration) GetSMTPHost() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// GetSMTPPassword provides a mock function with given fields:
+func (_m *MailConfiguration) GetSMTPPassword() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// GetSMTPPort provides a mock function with given fields:
+func (_m *MailConfiguration) GetSMTPPort() int {
+	ret := _m.Called()
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func() int); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	return r0
+}
+
+// GetSMTPStartTLS provides a mock function with given fields:
+func (_m *MailConfiguration) GetSMTPStartTLS() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// GetSMTPUsername provides a mock function with given fields:
+func (_m *MailConfiguration) GetSMTPUsername() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// GetTemplatesPath provides a mock function with given fields:
+func (_m *MailConfiguration) GetTemplatesPath() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// GetTransport provides a mock function with given fields:
+func (_m *MailConfiguration) GetTransport() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// Log provides a mock function with given fields: logger
+func (_m *MailConfiguration) Log(logger *zap.Logger) {
+	_m.Called(logger)
+}
+
+// Reload provides a mock function with given fields:
+func (_m *MailConfiguration) Reload() {
+	_m.Called()
+}
+
+type mockConstructorTestingTNewMailConfiguration interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewMailConfiguration creates a new instance of MailConfiguration. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewMailConfiguration(t mockConstructorTestingTNewMailConfiguration) *MailConfiguration {
+	mock := &MailConfiguration{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,100 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	configuration "github.com/ditrit/badaas/configuration"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+
+	zap "go.uber.org/zap"
+)
+
+// OAuthConfiguration is an autogenerated mock type for the OAuthConfiguration type
+type OAuthConfiguration struct {
+	mock.Mock
+}
+
+// GetAccessTokenDuration provides a mock function with given fields:
+func (_m *OAuthConfiguration) GetAccessTokenDuration() time.Duration {
+	ret := _m.Called()
+
+	var r0 time.Duration
+	if rf, ok := ret.Get(0).(func() time.Duration); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(time.Duration)
+	}
+
+	return r0
+}
+
+// GetCodeDuration provides a mock function with given fields:
+func (_m *OAuthConfiguration) GetCodeDuration() time.Duration {
+	ret := _m.Called()
+
+	var r0 time.Duration
+	if rf, ok := ret.Get(0).(func() time.Duration); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(time.Duration)
+	}
+
+	return r0
+}
+
+// GetRefreshTokenDuration provides a mock function with given fields:
+func (_m *OAuthConfiguration) GetRefreshTokenDuration() time.Duration {
+	ret := _m.Called()
+
+	var r0 time.Duration
+	if rf, ok := ret.Get(0).(func() time.Duration); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(time.Duration)
+	}
+
+	return r0
+}
+
+// GetScopes provides a mock function with given fields:
+func (_m *OAuthConfiguration) GetScopes() []configuration.OAuthScope {
+	ret := _m.Called()
+
+	var r0 []configuration.OAuthScope
+	if rf, ok := ret.Get(0).(func() []configuration.OAuthScope); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]configuration.OAuthScope)
+		}
+	}
+
+	return r0
+}
+
+// Log provides a mock function with given fields: logger
+func (_m *OAuthConfiguration) Log(logger *zap.Logger) {
+	_m.Called(logger)
+}
+
+// Reload provides a mock function with given fields:
+func (_m *OAuthConfiguration) Reload() {
+	_m.Called()
+}
+
+type mockConstructorTestingTNewOAuthConfiguration interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewOAuthConfiguration creates a new instance of OAuthConfiguration. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewOAuthConfiguration(t mockConstructorTestingTNewOAuthConfiguration) *OAuthConfiguration {
+	mock := &OAuthConfiguration{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,114 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	configuration "github.com/ditrit/badaas/configuration"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+
+	zap "go.uber.org/zap"
+)
+
+// OIDCConfiguration is an autogenerated mock type for the OIDCConfiguration type
+type OIDCConfiguration struct {
+	mock.Mock
+}
+
+// GetLinkByEmail provides a mock function with given fields:
+func (_m *OIDCConfiguration) GetLinkByEmail() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// GetLoginRedirectURL provides a mock function with given fields:
+func (_m *OIDCConfiguration) GetLoginRedirectURL() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// GetProviders provides a mock function with given fields:
+func (_m *OIDCConfiguration) GetProviders() []configuration.OIDCProvider {
+	ret := _m.Called()
+
+	var r0 []configuration.OIDCProvider
+	if rf, ok := ret.Get(0).(func() []configuration.OIDCProvider); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]configuration.OIDCProvider)
+		}
+	}
+
+	return r0
+}
+
+// GetProvisioning provides a mock function with given fields:
+func (_m *OIDCConfiguration) GetProvisioning() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// GetRequestDuration provides a mock function with given fields:
+func (_m *OIDCConfiguration) GetRequestDuration() time.Duration {
+	ret := _m.Called()
+
+	var r0 time.Duration
+	if rf, ok := ret.Get(0).(func() time.Duration); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(time.Duration)
+	}
+
+	return r0
+}
+
+// Log provides a mock function with given fields: logger
+func (_m *OIDCConfiguration) Log(logger *zap.Logger) {
+	_m.Called(logger)
+}
+
+// Reload provides a mock function with given fields:
+func (_m *OIDCConfiguration) Reload() {
+	_m.Called()
+}
+
+type mockConstructorTestingTNewOIDCConfiguration interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewOIDCConfiguration creates a new instance of OIDCConfiguration. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewOIDCConfiguration(t mockConstructorTestingTNewOIDCConfiguration) *OIDCConfiguration {
+	mock := &OIDCConfiguration{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
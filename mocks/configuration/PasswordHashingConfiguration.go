@@ -0,0 +1,108 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	mock "github.com/stretchr/testify/mock"
+	zap "go.uber.org/zap"
+)
+
+// PasswordHashingConfiguration is an autogenerated mock type for the PasswordHashingConfiguration type
+type PasswordHashingConfiguration struct {
+	mock.Mock
+}
+
+// GetAlgorithm provides a mock function with given fields:
+func (_m *PasswordHashingConfiguration) GetAlgorithm() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// GetArgon2idIterations provides a mock function with given fields:
+func (_m *PasswordHashingConfiguration) GetArgon2idIterations() uint32 {
+	ret := _m.Called()
+
+	var r0 uint32
+	if rf, ok := ret.Get(0).(func() uint32); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(uint32)
+	}
+
+	return r0
+}
+
+// GetArgon2idMemory provides a mock function with given fields:
+func (_m *PasswordHashingConfiguration) GetArgon2idMemory() uint32 {
+	ret := _m.Called()
+
+	var r0 uint32
+	if rf, ok := ret.Get(0).(func() uint32); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(uint32)
+	}
+
+	return r0
+}
+
+// GetArgon2idParallelism provides a mock function with given fields:
+func (_m *PasswordHashingConfiguration) GetArgon2idParallelism() uint8 {
+	ret := _m.Called()
+
+	var r0 uint8
+	if rf, ok := ret.Get(0).(func() uint8); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(uint8)
+	}
+
+	return r0
+}
+
+// GetBcryptCost provides a mock function with given fields:
+func (_m *PasswordHashingConfiguration) GetBcryptCost() int {
+	ret := _m.Called()
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func() int); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	return r0
+}
+
+// Log provides a mock function with given fields: logger
+func (_m *PasswordHashingConfiguration) Log(logger *zap.Logger) {
+	_m.Called(logger)
+}
+
+// Reload provides a mock function with given fields:
+func (_m *PasswordHashingConfiguration) Reload() {
+	_m.Called()
+}
+
+type mockConstructorTestingTNewPasswordHashingConfiguration interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewPasswordHashingConfiguration creates a new instance of PasswordHashingConfiguration. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewPasswordHashingConfiguration(t mockConstructorTestingTNewPasswordHashingConfiguration) *PasswordHashingConfiguration {
+	mock := &PasswordHashingConfiguration{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,192 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	mock "github.com/stretchr/testify/mock"
+	zap "go.uber.org/zap"
+)
+
+// PasswordPolicyConfiguration is an autogenerated mock type for the PasswordPolicyConfiguration type
+type PasswordPolicyConfiguration struct {
+	mock.Mock
+}
+
+// GetBreachedEnabled provides a mock function with given fields:
+func (_m *PasswordPolicyConfiguration) GetBreachedEnabled() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// GetBreachedPath provides a mock function with given fields:
+func (_m *PasswordPolicyConfiguration) GetBreachedPath() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// GetForbidUserInfo provides a mock function with given fields:
+func (_m *PasswordPolicyConfiguration) GetForbidUserInfo() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// GetHistorySize provides a mock function with given fields:
+func (_m *PasswordPolicyConfiguration) GetHistorySize() uint {
+	ret := _m.Called()
+
+	var r0 uint
+	if rf, ok := ret.Get(0).(func() uint); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(uint)
+	}
+
+	return r0
+}
+
+// GetMaxLength provides a mock function with given fields:
+func (_m *PasswordPolicyConfiguration) GetMaxLength() uint {
+	ret := _m.Called()
+
+	var r0 uint
+	if rf, ok := ret.Get(0).(func() uint); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(uint)
+	}
+
+	return r0
+}
+
+// GetMinLength provides a mock function with given fields:
+func (_m *PasswordPolicyConfiguration) GetMinLength() uint {
+	ret := _m.Called()
+
+	var r0 uint
+	if rf, ok := ret.Get(0).(func() uint); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(uint)
+	}
+
+	return r0
+}
+
+// GetRefuseDefaultAdminPassword provides a mock function with given fields:
+func (_m *PasswordPolicyConfiguration) GetRefuseDefaultAdminPassword() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// GetRequireDigit provides a mock function with given fields:
+func (_m *PasswordPolicyConfiguration) GetRequireDigit() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// GetRequireLowercase provides a mock function with given fields:
+func (_m *PasswordPolicyConfiguration) GetRequireLowercase() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// GetRequireSymbol provides a mock function with given fields:
+func (_m *PasswordPolicyConfiguration) GetRequireSymbol() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// GetRequireUppercase provides a mock function with given fields:
+func (_m *PasswordPolicyConfiguration) GetRequireUppercase() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// Log provides a mock function with given fields: logger
+func (_m *PasswordPolicyConfiguration) Log(logger *zap.Logger) {
+	_m.Called(logger)
+}
+
+// Reload provides a mock function with given fields:
+func (_m *PasswordPolicyConfiguration) Reload() {
+	_m.Called()
+}
+
+type mockConstructorTestingTNewPasswordPolicyConfiguration interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewPasswordPolicyConfiguration creates a new instance of PasswordPolicyConfiguration. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewPasswordPolicyConfiguration(t mockConstructorTestingTNewPasswordPolicyConfiguration) *PasswordPolicyConfiguration {
+	mock := &PasswordPolicyConfiguration{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,55 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	time "time"
+
+	mock "github.com/stretchr/testify/mock"
+
+	zap "go.uber.org/zap"
+)
+
+// PasswordResetConfiguration is an autogenerated mock type for the PasswordResetConfiguration type
+type PasswordResetConfiguration struct {
+	mock.Mock
+}
+
+// GetTokenDuration provides a mock function with given fields:
+func (_m *PasswordResetConfiguration) GetTokenDuration() time.Duration {
+	ret := _m.Called()
+
+	var r0 time.Duration
+	if rf, ok := ret.Get(0).(func() time.Duration); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(time.Duration)
+	}
+
+	return r0
+}
+
+// Log provides a mock function with given fields: logger
+func (_m *PasswordResetConfiguration) Log(logger *zap.Logger) {
+	_m.Called(logger)
+}
+
+// Reload provides a mock function with given fields:
+func (_m *PasswordResetConfiguration) Reload() {
+	_m.Called()
+}
+
+type mockConstructorTestingTNewPasswordResetConfiguration interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewPasswordResetConfiguration creates a new instance of PasswordResetConfiguration. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewPasswordResetConfiguration(t mockConstructorTestingTNewPasswordResetConfiguration) *PasswordResetConfiguration {
+	mock := &PasswordResetConfiguration{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,70 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	configuration "github.com/ditrit/badaas/configuration"
+	mock "github.com/stretchr/testify/mock"
+
+	zap "go.uber.org/zap"
+)
+
+// PolicyConfiguration is an autogenerated mock type for the PolicyConfiguration type
+type PolicyConfiguration struct {
+	mock.Mock
+}
+
+// GetExplain provides a mock function with given fields:
+func (_m *PolicyConfiguration) GetExplain() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// GetPolicies provides a mock function with given fields:
+func (_m *PolicyConfiguration) GetPolicies() []configuration.PolicyDefinition {
+	ret := _m.Called()
+
+	var r0 []configuration.PolicyDefinition
+	if rf, ok := ret.Get(0).(func() []configuration.PolicyDefinition); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]configuration.PolicyDefinition)
+		}
+	}
+
+	return r0
+}
+
+// Log provides a mock function with given fields: logger
+func (_m *PolicyConfiguration) Log(logger *zap.Logger) {
+	_m.Called(logger)
+}
+
+// Reload provides a mock function with given fields:
+func (_m *PolicyConfiguration) Reload() {
+	_m.Called()
+}
+
+type mockConstructorTestingTNewPolicyConfiguration interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewPolicyConfiguration creates a new instance of PolicyConfiguration. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewPolicyConfiguration(t mockConstructorTestingTNewPolicyConfiguration) *PolicyConfiguration {
+	mock := &PolicyConfiguration{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
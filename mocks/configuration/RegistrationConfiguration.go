@@ -0,0 +1,98 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	mock "github.com/stretchr/testify/mock"
+	zap "go.uber.org/zap"
+)
+
+// RegistrationConfiguration is an autogenerated mock type for the RegistrationConfiguration type
+type RegistrationConfiguration struct {
+	mock.Mock
+}
+
+// GetAllowedDomains provides a mock function with given fields:
+func (_m *RegistrationConfiguration) GetAllowedDomains() []string {
+	ret := _m.Called()
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func() []string); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	return r0
+}
+
+// GetAutoLogin provides a mock function with given fields:
+func (_m *RegistrationConfiguration) GetAutoLogin() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// GetEnabled provides a mock function with given fields:
+func (_m *RegistrationConfiguration) GetEnabled() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// GetInvitationCodes provides a mock function with given fields:
+func (_m *RegistrationConfiguration) GetInvitationCodes() []string {
+	ret := _m.Called()
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func() []string); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	return r0
+}
+
+// Log provides a mock function with given fields: logger
+func (_m *RegistrationConfiguration) Log(logger *zap.Logger) {
+	_m.Called(logger)
+}
+
+// Reload provides a mock function with given fields:
+func (_m *RegistrationConfiguration) Reload() {
+	_m.Called()
+}
+
+type mockConstructorTestingTNewRegistrationConfiguration interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewRegistrationConfiguration creates a new instance of RegistrationConfiguration. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewRegistrationConfiguration(t mockConstructorTestingTNewRegistrationConfiguration) *RegistrationConfiguration {
+	mock := &RegistrationConfiguration{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,251 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	time "time"
+
+	mock "github.com/stretchr/testify/mock"
+
+	zap "go.uber.org/zap"
+)
+
+// SAMLConfiguration is an autogenerated mock type for the SAMLConfiguration type
+type SAMLConfiguration struct {
+	mock.Mock
+}
+
+// GetAssertionConsumerServiceURL provides a mock function with given fields:
+func (_m *SAMLConfiguration) GetAssertionConsumerServiceURL() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// GetCertificate provides a mock function with given fields:
+func (_m *SAMLConfiguration) GetCertificate() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// GetEmailAttribute provides a mock function with given fields:
+func (_m *SAMLConfiguration) GetEmailAttribute() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// GetEntityID provides a mock function with given fields:
+func (_m *SAMLConfiguration) GetEntityID() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// GetIdPCertificate provides a mock function with given fields:
+func (_m *SAMLConfiguration) GetIdPCertificate() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// GetIdPEntityID provides a mock function with given fields:
+func (_m *SAMLConfiguration) GetIdPEntityID() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// GetIdPSSOURL provides a mock function with given fields:
+func (_m *SAMLConfiguration) GetIdPSSOURL() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// GetLinkByEmail provides a mock function with given fields:
+func (_m *SAMLConfiguration) GetLinkByEmail() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// GetLoginRedirectURL provides a mock function with given fields:
+func (_m *SAMLConfiguration) GetLoginRedirectURL() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// GetMaxClockSkew provides a mock function with given fields:
+func (_m *SAMLConfiguration) GetMaxClockSkew() time.Duration {
+	ret := _m.Called()
+
+	var r0 time.Duration
+	if rf, ok := ret.Get(0).(func() time.Duration); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(time.Duration)
+	}
+
+	return r0
+}
+
+// GetPrivateKey provides a mock function with given fields:
+func (_m *SAMLConfiguration) GetPrivateKey() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// GetProvisioning provides a mock function with given fields:
+func (_m *SAMLConfiguration) GetProvisioning() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// GetRequestDuration provides a mock function with given fields:
+func (_m *SAMLConfiguration) GetRequestDuration() time.Duration {
+	ret := _m.Called()
+
+	var r0 time.Duration
+	if rf, ok := ret.Get(0).(func() time.Duration); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(time.Duration)
+	}
+
+	return r0
+}
+
+// GetUsernameAttribute provides a mock function with given fields:
+func (_m *SAMLConfiguration) GetUsernameAttribute() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// IsEnabled provides a mock function with given fields:
+func (_m *SAMLConfiguration) IsEnabled() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// Log provides a mock function with given fields: logger
+func (_m *SAMLConfiguration) Log(logger *zap.Logger) {
+	_m.Called(logger)
+}
+
+// Reload provides a mock function with given fields:
+func (_m *SAMLConfiguration) Reload() {
+	_m.Called()
+}
+
+type mockConstructorTestingTNewSAMLConfiguration interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewSAMLConfiguration creates a new instance of SAMLConfiguration. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewSAMLConfiguration(t mockConstructorTestingTNewSAMLConfiguration) *SAMLConfiguration {
+	mock := &SAMLConfiguration{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
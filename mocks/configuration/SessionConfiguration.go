@@ -3,10 +3,11 @@
 package mocks
 
 import (
-	time "time"
-
+	configuration "github.com/ditrit/badaas/configuration"
 	mock "github.com/stretchr/testify/mock"
 
+	time "time"
+
 	zap "go.uber.org/zap"
 )
 
@@ -15,6 +16,78 @@ type SessionConfiguration struct {
 	mock.Mock
 }
 
+// GetBinding provides a mock function with given fields:
+func (_m *SessionConfiguration) GetBinding() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// GetJWTAccessTokenDuration provides a mock function with given fields:
+func (_m *SessionConfiguration) GetJWTAccessTokenDuration() time.Duration {
+	ret := _m.Called()
+
+	var r0 time.Duration
+	if rf, ok := ret.Get(0).(func() time.Duration); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(time.Duration)
+	}
+
+	return r0
+}
+
+// GetJWTAlgorithm provides a mock function with given fields:
+func (_m *SessionConfiguration) GetJWTAlgorithm() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// GetJWTKeys provides a mock function with given fields:
+func (_m *SessionConfiguration) GetJWTKeys() []configuration.SessionJWTKey {
+	ret := _m.Called()
+
+	var r0 []configuration.SessionJWTKey
+	if rf, ok := ret.Get(0).(func() []configuration.SessionJWTKey); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]configuration.SessionJWTKey)
+		}
+	}
+
+	return r0
+}
+
+// GetMode provides a mock function with given fields:
+func (_m *SessionConfiguration) GetMode() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
 // GetPullInterval provides a mock function with given fields:
 func (_m *SessionConfiguration) GetPullInterval() time.Duration {
 	ret := _m.Called()
@@ -29,6 +102,20 @@ func (_m *SessionConfiguration) GetPullInterval() time.Duration {
 	return r0
 }
 
+// GetRevocationPullInterval provides a mock function with given fields:
+func (_m *SessionConfiguration) GetRevocationPullInterval() time.Duration {
+	ret := _m.Called()
+
+	var r0 time.Duration
+	if rf, ok := ret.Get(0).(func() time.Duration); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(time.Duration)
+	}
+
+	return r0
+}
+
 // GetRollDuration provides a mock function with given fields:
 func (_m *SessionConfiguration) GetRollDuration() time.Duration {
 	ret := _m.Called()
@@ -57,6 +144,22 @@ func (_m *SessionConfiguration) GetSessionDuration() time.Duration {
 	return r0
 }
 
+// GetSigningKeys provides a mock function with given fields:
+func (_m *SessionConfiguration) GetSigningKeys() []configuration.SessionSigningKey {
+	ret := _m.Called()
+
+	var r0 []configuration.SessionSigningKey
+	if rf, ok := ret.Get(0).(func() []configuration.SessionSigningKey); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]configuration.SessionSigningKey)
+		}
+	}
+
+	return r0
+}
+
 // Log provides a mock function with given fields: logger
 func (_m *SessionConfiguration) Log(logger *zap.Logger) {
 	_m.Called(logger)
@@ -0,0 +1,113 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	time "time"
+
+	mock "github.com/stretchr/testify/mock"
+
+	zap "go.uber.org/zap"
+)
+
+// TwoFactorConfiguration is an autogenerated mock type for the TwoFactorConfiguration type
+type TwoFactorConfiguration struct {
+	mock.Mock
+}
+
+// GetChallengeAttempts provides a mock function with given fields:
+func (_m *TwoFactorConfiguration) GetChallengeAttempts() uint {
+	ret := _m.Called()
+
+	var r0 uint
+	if rf, ok := ret.Get(0).(func() uint); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(uint)
+	}
+
+	return r0
+}
+
+// GetChallengeDuration provides a mock function with given fields:
+func (_m *TwoFactorConfiguration) GetChallengeDuration() time.Duration {
+	ret := _m.Called()
+
+	var r0 time.Duration
+	if rf, ok := ret.Get(0).(func() time.Duration); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(time.Duration)
+	}
+
+	return r0
+}
+
+// GetIssuer provides a mock function with given fields:
+func (_m *TwoFactorConfiguration) GetIssuer() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// GetRecoveryCodes provides a mock function with given fields:
+func (_m *TwoFactorConfiguration) GetRecoveryCodes() uint {
+	ret := _m.Called()
+
+	var r0 uint
+	if rf, ok := ret.Get(0).(func() uint); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(uint)
+	}
+
+	return r0
+}
+
+// GetRequiredRoles provides a mock function with given fields:
+func (_m *TwoFactorConfiguration) GetRequiredRoles() []string {
+	ret := _m.Called()
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func() []string); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	return r0
+}
+
+// Log provides a mock function with given fields: logger
+func (_m *TwoFactorConfiguration) Log(logger *zap.Logger) {
+	_m.Called(logger)
+}
+
+// Reload provides a mock function with given fields:
+func (_m *TwoFactorConfiguration) Reload() {
+	_m.Called()
+}
+
+type mockConstructorTestingTNewTwoFactorConfiguration interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewTwoFactorConfiguration creates a new instance of TwoFactorConfiguration. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewTwoFactorConfiguration(t mockConstructorTestingTNewTwoFactorConfiguration) *TwoFactorConfiguration {
+	mock := &TwoFactorConfiguration{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
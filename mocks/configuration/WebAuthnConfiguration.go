@@ -0,0 +1,113 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	time "time"
+
+	mock "github.com/stretchr/testify/mock"
+
+	zap "go.uber.org/zap"
+)
+
+// WebAuthnConfiguration is an autogenerated mock type for the WebAuthnConfiguration type
+type WebAuthnConfiguration struct {
+	mock.Mock
+}
+
+// GetOrigins provides a mock function with given fields:
+func (_m *WebAuthnConfiguration) GetOrigins() []string {
+	ret := _m.Called()
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func() []string); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	return r0
+}
+
+// GetRelyingPartyID provides a mock function with given fields:
+func (_m *WebAuthnConfiguration) GetRelyingPartyID() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// GetRelyingPartyName provides a mock function with given fields:
+func (_m *WebAuthnConfiguration) GetRelyingPartyName() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// GetTimeout provides a mock function with given fields:
+func (_m *WebAuthnConfiguration) GetTimeout() time.Duration {
+	ret := _m.Called()
+
+	var r0 time.Duration
+	if rf, ok := ret.Get(0).(func() time.Duration); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(time.Duration)
+	}
+
+	return r0
+}
+
+// GetUserVerification provides a mock function with given fields:
+func (_m *WebAuthnConfiguration) GetUserVerification() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// Log provides a mock function with given fields: logger
+func (_m *WebAuthnConfiguration) Log(logger *zap.Logger) {
+	_m.Called(logger)
+}
+
+// Reload provides a mock function with given fields:
+func (_m *WebAuthnConfiguration) Reload() {
+	_m.Called()
+}
+
+type mockConstructorTestingTNewWebAuthnConfiguration interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewWebAuthnConfiguration creates a new instance of WebAuthnConfiguration. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewWebAuthnConfiguration(t mockConstructorTestingTNewWebAuthnConfiguration) *WebAuthnConfiguration {
+	mock := &WebAuthnConfiguration{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,105 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	http "net/http"
+
+	httperrors "github.com/ditrit/badaas/httperrors"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// APIKeyController is an autogenerated mock type for the APIKeyController type
+type APIKeyController struct {
+	mock.Mock
+}
+
+// CreateAPIKey provides a mock function with given fields: _a0, _a1
+func (_m *APIKeyController) CreateAPIKey(_a0 http.ResponseWriter, _a1 *http.Request) (interface{}, httperrors.HTTPError) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 interface{}
+	if rf, ok := ret.Get(0).(func(http.ResponseWriter, *http.Request) interface{}); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(interface{})
+		}
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func(http.ResponseWriter, *http.Request) httperrors.HTTPError); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
+// ListAPIKeys provides a mock function with given fields: _a0, _a1
+func (_m *APIKeyController) ListAPIKeys(_a0 http.ResponseWriter, _a1 *http.Request) (interface{}, httperrors.HTTPError) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 interface{}
+	if rf, ok := ret.Get(0).(func(http.ResponseWriter, *http.Request) interface{}); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(interface{})
+		}
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func(http.ResponseWriter, *http.Request) httperrors.HTTPError); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
+// RevokeAPIKey provides a mock function with given fields: _a0, _a1
+func (_m *APIKeyController) RevokeAPIKey(_a0 http.ResponseWriter, _a1 *http.Request) (interface{}, httperrors.HTTPError) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 interface{}
+	if rf, ok := ret.Get(0).(func(http.ResponseWriter, *http.Request) interface{}); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(interface{})
+		}
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func(http.ResponseWriter, *http.Request) httperrors.HTTPError); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewAPIKeyController interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewAPIKeyController creates a new instance of APIKeyController. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewAPIKeyController(t mockConstructorTestingTNewAPIKeyController) *APIKeyController {
+	mock := &APIKeyController{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
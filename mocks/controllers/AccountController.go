@@ -0,0 +1,130 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	http "net/http"
+
+	httperrors "github.com/ditrit/badaas/httperrors"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// AccountController is an autogenerated mock type for the AccountController type
+type AccountController struct {
+	mock.Mock
+}
+
+// ChangeEmail provides a mock function with given fields: _a0, _a1
+func (_m *AccountController) ChangeEmail(_a0 http.ResponseWriter, _a1 *http.Request) (interface{}, httperrors.HTTPError) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 interface{}
+	if rf, ok := ret.Get(0).(func(http.ResponseWriter, *http.Request) interface{}); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(interface{})
+		}
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func(http.ResponseWriter, *http.Request) httperrors.HTTPError); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
+// ChangePassword provides a mock function with given fields: _a0, _a1
+func (_m *AccountController) ChangePassword(_a0 http.ResponseWriter, _a1 *http.Request) (interface{}, httperrors.HTTPError) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 interface{}
+	if rf, ok := ret.Get(0).(func(http.ResponseWriter, *http.Request) interface{}); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(interface{})
+		}
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func(http.ResponseWriter, *http.Request) httperrors.HTTPError); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
+// ConfirmEmailChange provides a mock function with given fields: _a0, _a1
+func (_m *AccountController) ConfirmEmailChange(_a0 http.ResponseWriter, _a1 *http.Request) (interface{}, httperrors.HTTPError) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 interface{}
+	if rf, ok := ret.Get(0).(func(http.ResponseWriter, *http.Request) interface{}); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(interface{})
+		}
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func(http.ResponseWriter, *http.Request) httperrors.HTTPError); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetMe provides a mock function with given fields: _a0, _a1
+func (_m *AccountController) GetMe(_a0 http.ResponseWriter, _a1 *http.Request) (interface{}, httperrors.HTTPError) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 interface{}
+	if rf, ok := ret.Get(0).(func(http.ResponseWriter, *http.Request) interface{}); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(interface{})
+		}
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func(http.ResponseWriter, *http.Request) httperrors.HTTPError); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewAccountController interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewAccountController creates a new instance of AccountController. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewAccountController(t mockConstructorTestingTNewAccountController) *AccountController {
+	mock := &AccountController{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
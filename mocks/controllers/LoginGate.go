@@ -0,0 +1,104 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	http "net/http"
+
+	dto "github.com/ditrit/badaas/persistence/models/dto"
+
+	httperrors "github.com/ditrit/badaas/httperrors"
+
+	mock "github.com/stretchr/testify/mock"
+
+	models "github.com/ditrit/badaas/persistence/models"
+)
+
+// LoginGate is an autogenerated mock type for the LoginGate type
+type LoginGate struct {
+	mock.Mock
+}
+
+// CheckAttempt provides a mock function with given fields: email, r, w
+func (_m *LoginGate) CheckAttempt(email string, r *http.Request, w http.ResponseWriter) httperrors.HTTPError {
+	ret := _m.Called(email, r, w)
+
+	var r0 httperrors.HTTPError
+	if rf, ok := ret.Get(0).(func(string, *http.Request, http.ResponseWriter) httperrors.HTTPError); ok {
+		r0 = rf(email, r, w)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httperrors.HTTPError)
+		}
+	}
+
+	return r0
+}
+
+// CompleteLogin provides a mock function with given fields: user, r, w
+func (_m *LoginGate) CompleteLogin(user *models.User, r *http.Request, w http.ResponseWriter) (dto.DTOLoginSuccess, httperrors.HTTPError) {
+	ret := _m.Called(user, r, w)
+
+	var r0 dto.DTOLoginSuccess
+	if rf, ok := ret.Get(0).(func(*models.User, *http.Request, http.ResponseWriter) dto.DTOLoginSuccess); ok {
+		r0 = rf(user, r, w)
+	} else {
+		r0 = ret.Get(0).(dto.DTOLoginSuccess)
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func(*models.User, *http.Request, http.ResponseWriter) httperrors.HTTPError); ok {
+		r1 = rf(user, r, w)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
+// LogUserIn provides a mock function with given fields: user, r, w
+func (_m *LoginGate) LogUserIn(user *models.User, r *http.Request, w http.ResponseWriter) (interface{}, httperrors.HTTPError) {
+	ret := _m.Called(user, r, w)
+
+	var r0 interface{}
+	if rf, ok := ret.Get(0).(func(*models.User, *http.Request, http.ResponseWriter) interface{}); ok {
+		r0 = rf(user, r, w)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(interface{})
+		}
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func(*models.User, *http.Request, http.ResponseWriter) httperrors.HTTPError); ok {
+		r1 = rf(user, r, w)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
+// RecordFailure provides a mock function with given fields: email, r
+func (_m *LoginGate) RecordFailure(email string, r *http.Request) {
+	_m.Called(email, r)
+}
+
+type mockConstructorTestingTNewLoginGate interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewLoginGate creates a new instance of LoginGate. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewLoginGate(t mockConstructorTestingTNewLoginGate) *LoginGate {
+	mock := &LoginGate{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -75,6 +75,45 @@ func (_m *CRUDRepository[T, ID]) Delete(_a0 *T) httperrors.HTTPError {
 	return r0
 }
 
+// DeleteDependents provides a mock function with given fields: filters, dependentModels
+func (_m *CRUDRepository[T, ID]) DeleteDependents(filters squirrel.Sqlizer, dependentModels []models.Tabler) httperrors.HTTPError {
+	ret := _m.Called(filters, dependentModels)
+
+	var r0 httperrors.HTTPError
+	if rf, ok := ret.Get(0).(func(squirrel.Sqlizer, []models.Tabler) httperrors.HTTPError); ok {
+		r0 = rf(filters, dependentModels)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httperrors.HTTPError)
+		}
+	}
+
+	return r0
+}
+
+// DeleteUnscoped provides a mock function with given fields: _a0
+func (_m *CRUDRepository[T, ID]) DeleteUnscoped(_a0 squirrel.Sqlizer) (uint, httperrors.HTTPError) {
+	ret := _m.Called(_a0)
+
+	var r0 uint
+	if rf, ok := ret.Get(0).(func(squirrel.Sqlizer) uint); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Get(0).(uint)
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func(squirrel.Sqlizer) httperrors.HTTPError); ok {
+		r1 = rf(_a0)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
 // Find provides a mock function with given fields: _a0, _a1, _a2
 func (_m *CRUDRepository[T, ID]) Find(_a0 squirrel.Sqlizer, _a1 pagination.Paginator, _a2 repository.SortOption) (*pagination.Page[T], httperrors.HTTPError) {
 	ret := _m.Called(_a0, _a1, _a2)
@@ -100,6 +139,31 @@ func (_m *CRUDRepository[T, ID]) Find(_a0 squirrel.Sqlizer, _a1 pagination.Pagin
 	return r0, r1
 }
 
+// FindUnscoped provides a mock function with given fields: _a0
+func (_m *CRUDRepository[T, ID]) FindUnscoped(_a0 squirrel.Sqlizer) ([]*T, httperrors.HTTPError) {
+	ret := _m.Called(_a0)
+
+	var r0 []*T
+	if rf, ok := ret.Get(0).(func(squirrel.Sqlizer) []*T); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*T)
+		}
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func(squirrel.Sqlizer) httperrors.HTTPError); ok {
+		r1 = rf(_a0)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
 // GetAll provides a mock function with given fields: _a0
 func (_m *CRUDRepository[T, ID]) GetAll(_a0 repository.SortOption) ([]*T, httperrors.HTTPError) {
 	ret := _m.Called(_a0)
@@ -191,6 +255,45 @@ func (_m *CRUDRepository[T, ID]) Transaction(fn func(repository.CRUDRepository[T
 	return r0, r1
 }
 
+// UpdateColumns provides a mock function with given fields: _a0, _a1
+func (_m *CRUDRepository[T, ID]) UpdateColumns(_a0 squirrel.Sqlizer, _a1 map[string]interface{}) (uint, httperrors.HTTPError) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 uint
+	if rf, ok := ret.Get(0).(func(squirrel.Sqlizer, map[string]interface{}) uint); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(uint)
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func(squirrel.Sqlizer, map[string]interface{}) httperrors.HTTPError); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
+// Upsert provides a mock function with given fields: entity, conflictColumns, assignments
+func (_m *CRUDRepository[T, ID]) Upsert(entity *T, conflictColumns []string, assignments map[string]interface{}) httperrors.HTTPError {
+	ret := _m.Called(entity, conflictColumns, assignments)
+
+	var r0 httperrors.HTTPError
+	if rf, ok := ret.Get(0).(func(*T, []string, map[string]interface{}) httperrors.HTTPError); ok {
+		r0 = rf(entity, conflictColumns, assignments)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httperrors.HTTPError)
+		}
+	}
+
+	return r0
+}
+
 type mockConstructorTestingTNewCRUDRepository interface {
 	mock.TestingT
 	Cleanup(func())
@@ -13,6 +13,28 @@ type AuthenticationMiddleware struct {
 	mock.Mock
 }
 
+// Accept provides a mock function with given fields: schemes
+func (_m *AuthenticationMiddleware) Accept(schemes ...string) func(http.Handler) http.Handler {
+	_va := make([]interface{}, len(schemes))
+	for _i := range schemes {
+		_va[_i] = schemes[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 func(http.Handler) http.Handler
+	if rf, ok := ret.Get(0).(func(...string) func(http.Handler) http.Handler); ok {
+		r0 = rf(schemes...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(func(http.Handler) http.Handler)
+		}
+	}
+
+	return r0
+}
+
 // Handle provides a mock function with given fields: next
 func (_m *AuthenticationMiddleware) Handle(next http.Handler) http.Handler {
 	ret := _m.Called(next)
@@ -0,0 +1,86 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	http "net/http"
+
+	httperrors "github.com/ditrit/badaas/httperrors"
+
+	mock "github.com/stretchr/testify/mock"
+
+	sessionservice "github.com/ditrit/badaas/services/sessionservice"
+)
+
+// Authenticator is an autogenerated mock type for the Authenticator type
+type Authenticator struct {
+	mock.Mock
+}
+
+// Authenticate provides a mock function with given fields: request
+func (_m *Authenticator) Authenticate(request *http.Request) (*sessionservice.SessionClaims, httperrors.HTTPError) {
+	ret := _m.Called(request)
+
+	var r0 *sessionservice.SessionClaims
+	if rf, ok := ret.Get(0).(func(*http.Request) *sessionservice.SessionClaims); ok {
+		r0 = rf(request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*sessionservice.SessionClaims)
+		}
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func(*http.Request) httperrors.HTTPError); ok {
+		r1 = rf(request)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
+// Challenge provides a mock function with given fields: invalid
+func (_m *Authenticator) Challenge(invalid bool) string {
+	ret := _m.Called(invalid)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(bool) string); ok {
+		r0 = rf(invalid)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// Scheme provides a mock function with given fields:
+func (_m *Authenticator) Scheme() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewAuthenticator interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewAuthenticator creates a new instance of Authenticator. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewAuthenticator(t mockConstructorTestingTNewAuthenticator) *Authenticator {
+	mock := &Authenticator{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
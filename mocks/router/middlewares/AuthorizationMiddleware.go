@@ -0,0 +1,78 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	http "net/http"
+
+	middlewares "github.com/ditrit/badaas/router/middlewares"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// AuthorizationMiddleware is an autogenerated mock type for the AuthorizationMiddleware type
+type AuthorizationMiddleware struct {
+	mock.Mock
+}
+
+// RefuseAPIKeys provides a mock function with given fields: next
+func (_m *AuthorizationMiddleware) RefuseAPIKeys(next http.Handler) http.Handler {
+	ret := _m.Called(next)
+
+	var r0 http.Handler
+	if rf, ok := ret.Get(0).(func(http.Handler) http.Handler); ok {
+		r0 = rf(next)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(http.Handler)
+		}
+	}
+
+	return r0
+}
+
+// RequirePermission provides a mock function with given fields: permission
+func (_m *AuthorizationMiddleware) RequirePermission(permission string) func(http.Handler) http.Handler {
+	ret := _m.Called(permission)
+
+	var r0 func(http.Handler) http.Handler
+	if rf, ok := ret.Get(0).(func(string) func(http.Handler) http.Handler); ok {
+		r0 = rf(permission)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(func(http.Handler) http.Handler)
+		}
+	}
+
+	return r0
+}
+
+// RequirePolicy provides a mock function with given fields: action, resourceResolver
+func (_m *AuthorizationMiddleware) RequirePolicy(action string, resourceResolver middlewares.ResourceResolver) func(http.Handler) http.Handler {
+	ret := _m.Called(action, resourceResolver)
+
+	var r0 func(http.Handler) http.Handler
+	if rf, ok := ret.Get(0).(func(string, middlewares.ResourceResolver) func(http.Handler) http.Handler); ok {
+		r0 = rf(action, resourceResolver)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(func(http.Handler) http.Handler)
+		}
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewAuthorizationMiddleware interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewAuthorizationMiddleware creates a new instance of AuthorizationMiddleware. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewAuthorizationMiddleware(t mockConstructorTestingTNewAuthorizationMiddleware) *AuthorizationMiddleware {
+	mock := &AuthorizationMiddleware{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
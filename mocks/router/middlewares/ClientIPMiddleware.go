@@ -0,0 +1,45 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	http "net/http"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ClientIPMiddleware is an autogenerated mock type for the ClientIPMiddleware type
+type ClientIPMiddleware struct {
+	mock.Mock
+}
+
+// Handle provides a mock function with given fields: next
+func (_m *ClientIPMiddleware) Handle(next http.Handler) http.Handler {
+	ret := _m.Called(next)
+
+	var r0 http.Handler
+	if rf, ok := ret.Get(0).(func(http.Handler) http.Handler); ok {
+		r0 = rf(next)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(http.Handler)
+		}
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewClientIPMiddleware interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewClientIPMiddleware creates a new instance of ClientIPMiddleware. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewClientIPMiddleware(t mockConstructorTestingTNewClientIPMiddleware) *ClientIPMiddleware {
+	mock := &ClientIPMiddleware{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,58 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	http "net/http"
+
+	httperrors "github.com/ditrit/badaas/httperrors"
+
+	mock "github.com/stretchr/testify/mock"
+
+	policyservice "github.com/ditrit/badaas/services/policyservice"
+)
+
+// ResourceResolver is an autogenerated mock type for the ResourceResolver type
+type ResourceResolver struct {
+	mock.Mock
+}
+
+// Execute provides a mock function with given fields: request
+func (_m *ResourceResolver) Execute(request *http.Request) (policyservice.Resource, httperrors.HTTPError) {
+	ret := _m.Called(request)
+
+	var r0 policyservice.Resource
+	if rf, ok := ret.Get(0).(func(*http.Request) policyservice.Resource); ok {
+		r0 = rf(request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(policyservice.Resource)
+		}
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func(*http.Request) httperrors.HTTPError); ok {
+		r1 = rf(request)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewResourceResolver interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewResourceResolver creates a new instance of ResourceResolver. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewResourceResolver(t mockConstructorTestingTNewResourceResolver) *ResourceResolver {
+	mock := &ResourceResolver{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
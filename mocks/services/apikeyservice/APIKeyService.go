@@ -0,0 +1,134 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	httperrors "github.com/ditrit/badaas/httperrors"
+	dto "github.com/ditrit/badaas/persistence/models/dto"
+
+	mock "github.com/stretchr/testify/mock"
+
+	models "github.com/ditrit/badaas/persistence/models"
+
+	sessionservice "github.com/ditrit/badaas/services/sessionservice"
+
+	uuid "github.com/google/uuid"
+)
+
+// APIKeyService is an autogenerated mock type for the APIKeyService type
+type APIKeyService struct {
+	mock.Mock
+}
+
+// Authenticate provides a mock function with given fields: key
+func (_m *APIKeyService) Authenticate(key string) (*sessionservice.SessionClaims, httperrors.HTTPError) {
+	ret := _m.Called(key)
+
+	var r0 *sessionservice.SessionClaims
+	if rf, ok := ret.Get(0).(func(string) *sessionservice.SessionClaims); ok {
+		r0 = rf(key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*sessionservice.SessionClaims)
+		}
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func(string) httperrors.HTTPError); ok {
+		r1 = rf(key)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
+// CreateAPIKey provides a mock function with given fields: userID, createAPIKeyDTO
+func (_m *APIKeyService) CreateAPIKey(userID uuid.UUID, createAPIKeyDTO dto.DTOCreateAPIKey) (*models.APIKey, string, httperrors.HTTPError) {
+	ret := _m.Called(userID, createAPIKeyDTO)
+
+	var r0 *models.APIKey
+	if rf, ok := ret.Get(0).(func(uuid.UUID, dto.DTOCreateAPIKey) *models.APIKey); ok {
+		r0 = rf(userID, createAPIKeyDTO)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.APIKey)
+		}
+	}
+
+	var r1 string
+	if rf, ok := ret.Get(1).(func(uuid.UUID, dto.DTOCreateAPIKey) string); ok {
+		r1 = rf(userID, createAPIKeyDTO)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	var r2 httperrors.HTTPError
+	if rf, ok := ret.Get(2).(func(uuid.UUID, dto.DTOCreateAPIKey) httperrors.HTTPError); ok {
+		r2 = rf(userID, createAPIKeyDTO)
+	} else {
+		if ret.Get(2) != nil {
+			r2 = ret.Get(2).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1, r2
+}
+
+// GetAPIKeys provides a mock function with given fields: userID
+func (_m *APIKeyService) GetAPIKeys(userID uuid.UUID) ([]*models.APIKey, httperrors.HTTPError) {
+	ret := _m.Called(userID)
+
+	var r0 []*models.APIKey
+	if rf, ok := ret.Get(0).(func(uuid.UUID) []*models.APIKey); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.APIKey)
+		}
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func(uuid.UUID) httperrors.HTTPError); ok {
+		r1 = rf(userID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
+// RevokeAPIKey provides a mock function with given fields: userID, apiKeyID
+func (_m *APIKeyService) RevokeAPIKey(userID uuid.UUID, apiKeyID uuid.UUID) httperrors.HTTPError {
+	ret := _m.Called(userID, apiKeyID)
+
+	var r0 httperrors.HTTPError
+	if rf, ok := ret.Get(0).(func(uuid.UUID, uuid.UUID) httperrors.HTTPError); ok {
+		r0 = rf(userID, apiKeyID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httperrors.HTTPError)
+		}
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewAPIKeyService interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewAPIKeyService creates a new instance of APIKeyService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewAPIKeyService(t mockConstructorTestingTNewAPIKeyService) *APIKeyService {
+	mock := &APIKeyService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
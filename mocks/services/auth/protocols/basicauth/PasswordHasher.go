@@ -0,0 +1,76 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package mocks
+
+import mock "github.com/stretchr/testify/mock"
+
+// PasswordHasher is an autogenerated mock type for the PasswordHasher type
+type PasswordHasher struct {
+	mock.Mock
+}
+
+// Hash provides a mock function with given fields: password
+func (_m *PasswordHasher) Hash(password string) ([]byte, error) {
+	ret := _m.Called(password)
+
+	var r0 []byte
+	if rf, ok := ret.Get(0).(func(string) []byte); ok {
+		r0 = rf(password)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(password)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NeedsRehash provides a mock function with given fields: hash
+func (_m *PasswordHasher) NeedsRehash(hash []byte) bool {
+	ret := _m.Called(hash)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func([]byte) bool); ok {
+		r0 = rf(hash)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// Verify provides a mock function with given fields: hash, password
+func (_m *PasswordHasher) Verify(hash []byte, password string) bool {
+	ret := _m.Called(hash, password)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func([]byte, string) bool); ok {
+		r0 = rf(hash, password)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewPasswordHasher interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewPasswordHasher creates a new instance of PasswordHasher. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewPasswordHasher(t mockConstructorTestingTNewPasswordHasher) *PasswordHasher {
+	mock := &PasswordHasher{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
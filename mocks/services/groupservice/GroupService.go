@@ -0,0 +1,465 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	httperrors "github.com/ditrit/badaas/httperrors"
+	mock "github.com/stretchr/testify/mock"
+
+	models "github.com/ditrit/badaas/persistence/models"
+
+	uuid "github.com/google/uuid"
+)
+
+// GroupService is an autogenerated mock type for the GroupService type
+type GroupService struct {
+	mock.Mock
+}
+
+// AddGroupMember provides a mock function with given fields: groupID, userID, role
+func (_m *GroupService) AddGroupMember(groupID uuid.UUID, userID uuid.UUID, role string) httperrors.HTTPError {
+	ret := _m.Called(groupID, userID, role)
+
+	var r0 httperrors.HTTPError
+	if rf, ok := ret.Get(0).(func(uuid.UUID, uuid.UUID, string) httperrors.HTTPError); ok {
+		r0 = rf(groupID, userID, role)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httperrors.HTTPError)
+		}
+	}
+
+	return r0
+}
+
+// AddOrganisationMember provides a mock function with given fields: organisationID, userID, role
+func (_m *GroupService) AddOrganisationMember(organisationID uuid.UUID, userID uuid.UUID, role string) httperrors.HTTPError {
+	ret := _m.Called(organisationID, userID, role)
+
+	var r0 httperrors.HTTPError
+	if rf, ok := ret.Get(0).(func(uuid.UUID, uuid.UUID, string) httperrors.HTTPError); ok {
+		r0 = rf(organisationID, userID, role)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httperrors.HTTPError)
+		}
+	}
+
+	return r0
+}
+
+// CreateGroup provides a mock function with given fields: name, description, organisationID, parentID
+func (_m *GroupService) CreateGroup(name string, description string, organisationID *uuid.UUID, parentID *uuid.UUID) (*models.Group, httperrors.HTTPError) {
+	ret := _m.Called(name, description, organisationID, parentID)
+
+	var r0 *models.Group
+	if rf, ok := ret.Get(0).(func(string, string, *uuid.UUID, *uuid.UUID) *models.Group); ok {
+		r0 = rf(name, description, organisationID, parentID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Group)
+		}
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func(string, string, *uuid.UUID, *uuid.UUID) httperrors.HTTPError); ok {
+		r1 = rf(name, description, organisationID, parentID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
+// CreateOrganisation provides a mock function with given fields: name, description
+func (_m *GroupService) CreateOrganisation(name string, description string) (*models.Organisation, httperrors.HTTPError) {
+	ret := _m.Called(name, description)
+
+	var r0 *models.Organisation
+	if rf, ok := ret.Get(0).(func(string, string) *models.Organisation); ok {
+		r0 = rf(name, description)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Organisation)
+		}
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func(string, string) httperrors.HTTPError); ok {
+		r1 = rf(name, description)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
+// DeleteGroup provides a mock function with given fields: groupID
+func (_m *GroupService) DeleteGroup(groupID uuid.UUID) httperrors.HTTPError {
+	ret := _m.Called(groupID)
+
+	var r0 httperrors.HTTPError
+	if rf, ok := ret.Get(0).(func(uuid.UUID) httperrors.HTTPError); ok {
+		r0 = rf(groupID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httperrors.HTTPError)
+		}
+	}
+
+	return r0
+}
+
+// DeleteOrganisation provides a mock function with given fields: organisationID
+func (_m *GroupService) DeleteOrganisation(organisationID uuid.UUID) httperrors.HTTPError {
+	ret := _m.Called(organisationID)
+
+	var r0 httperrors.HTTPError
+	if rf, ok := ret.Get(0).(func(uuid.UUID) httperrors.HTTPError); ok {
+		r0 = rf(organisationID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httperrors.HTTPError)
+		}
+	}
+
+	return r0
+}
+
+// GetGroup provides a mock function with given fields: groupID
+func (_m *GroupService) GetGroup(groupID uuid.UUID) (*models.Group, httperrors.HTTPError) {
+	ret := _m.Called(groupID)
+
+	var r0 *models.Group
+	if rf, ok := ret.Get(0).(func(uuid.UUID) *models.Group); ok {
+		r0 = rf(groupID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Group)
+		}
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func(uuid.UUID) httperrors.HTTPError); ok {
+		r1 = rf(groupID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetGroupMembers provides a mock function with given fields: groupID
+func (_m *GroupService) GetGroupMembers(groupID uuid.UUID) ([]*models.GroupMember, httperrors.HTTPError) {
+	ret := _m.Called(groupID)
+
+	var r0 []*models.GroupMember
+	if rf, ok := ret.Get(0).(func(uuid.UUID) []*models.GroupMember); ok {
+		r0 = rf(groupID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.GroupMember)
+		}
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func(uuid.UUID) httperrors.HTTPError); ok {
+		r1 = rf(groupID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetGroupRole provides a mock function with given fields: userID, groupID
+func (_m *GroupService) GetGroupRole(userID uuid.UUID, groupID uuid.UUID) (string, httperrors.HTTPError) {
+	ret := _m.Called(userID, groupID)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(uuid.UUID, uuid.UUID) string); ok {
+		r0 = rf(userID, groupID)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func(uuid.UUID, uuid.UUID) httperrors.HTTPError); ok {
+		r1 = rf(userID, groupID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetGroups provides a mock function with given fields: organisationID
+func (_m *GroupService) GetGroups(organisationID *uuid.UUID) ([]*models.Group, httperrors.HTTPError) {
+	ret := _m.Called(organisationID)
+
+	var r0 []*models.Group
+	if rf, ok := ret.Get(0).(func(*uuid.UUID) []*models.Group); ok {
+		r0 = rf(organisationID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Group)
+		}
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func(*uuid.UUID) httperrors.HTTPError); ok {
+		r1 = rf(organisationID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetOrganisation provides a mock function with given fields: organisationID
+func (_m *GroupService) GetOrganisation(organisationID uuid.UUID) (*models.Organisation, httperrors.HTTPError) {
+	ret := _m.Called(organisationID)
+
+	var r0 *models.Organisation
+	if rf, ok := ret.Get(0).(func(uuid.UUID) *models.Organisation); ok {
+		r0 = rf(organisationID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Organisation)
+		}
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func(uuid.UUID) httperrors.HTTPError); ok {
+		r1 = rf(organisationID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetOrganisationMembers provides a mock function with given fields: organisationID
+func (_m *GroupService) GetOrganisationMembers(organisationID uuid.UUID) ([]*models.OrganisationMember, httperrors.HTTPError) {
+	ret := _m.Called(organisationID)
+
+	var r0 []*models.OrganisationMember
+	if rf, ok := ret.Get(0).(func(uuid.UUID) []*models.OrganisationMember); ok {
+		r0 = rf(organisationID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.OrganisationMember)
+		}
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func(uuid.UUID) httperrors.HTTPError); ok {
+		r1 = rf(organisationID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetOrganisationRole provides a mock function with given fields: userID, organisationID
+func (_m *GroupService) GetOrganisationRole(userID uuid.UUID, organisationID uuid.UUID) (string, httperrors.HTTPError) {
+	ret := _m.Called(userID, organisationID)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(uuid.UUID, uuid.UUID) string); ok {
+		r0 = rf(userID, organisationID)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func(uuid.UUID, uuid.UUID) httperrors.HTTPError); ok {
+		r1 = rf(userID, organisationID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetOrganisations provides a mock function with given fields:
+func (_m *GroupService) GetOrganisations() ([]*models.Organisation, httperrors.HTTPError) {
+	ret := _m.Called()
+
+	var r0 []*models.Organisation
+	if rf, ok := ret.Get(0).(func() []*models.Organisation); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Organisation)
+		}
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func() httperrors.HTTPError); ok {
+		r1 = rf()
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetUserGroupIDs provides a mock function with given fields: userID
+func (_m *GroupService) GetUserGroupIDs(userID uuid.UUID) ([]string, httperrors.HTTPError) {
+	ret := _m.Called(userID)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(uuid.UUID) []string); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func(uuid.UUID) httperrors.HTTPError); ok {
+		r1 = rf(userID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetUserGroups provides a mock function with given fields: userID
+func (_m *GroupService) GetUserGroups(userID uuid.UUID) ([]*models.Group, httperrors.HTTPError) {
+	ret := _m.Called(userID)
+
+	var r0 []*models.Group
+	if rf, ok := ret.Get(0).(func(uuid.UUID) []*models.Group); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Group)
+		}
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func(uuid.UUID) httperrors.HTTPError); ok {
+		r1 = rf(userID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetUserOrganisationIDs provides a mock function with given fields: userID
+func (_m *GroupService) GetUserOrganisationIDs(userID uuid.UUID) ([]string, httperrors.HTTPError) {
+	ret := _m.Called(userID)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(uuid.UUID) []string); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func(uuid.UUID) httperrors.HTTPError); ok {
+		r1 = rf(userID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
+// RemoveGroupMember provides a mock function with given fields: groupID, userID
+func (_m *GroupService) RemoveGroupMember(groupID uuid.UUID, userID uuid.UUID) httperrors.HTTPError {
+	ret := _m.Called(groupID, userID)
+
+	var r0 httperrors.HTTPError
+	if rf, ok := ret.Get(0).(func(uuid.UUID, uuid.UUID) httperrors.HTTPError); ok {
+		r0 = rf(groupID, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httperrors.HTTPError)
+		}
+	}
+
+	return r0
+}
+
+// RemoveOrganisationMember provides a mock function with given fields: organisationID, userID
+func (_m *GroupService) RemoveOrganisationMember(organisationID uuid.UUID, userID uuid.UUID) httperrors.HTTPError {
+	ret := _m.Called(organisationID, userID)
+
+	var r0 httperrors.HTTPError
+	if rf, ok := ret.Get(0).(func(uuid.UUID, uuid.UUID) httperrors.HTTPError); ok {
+		r0 = rf(organisationID, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httperrors.HTTPError)
+		}
+	}
+
+	return r0
+}
+
+// SetGroupParent provides a mock function with given fields: groupID, parentID
+func (_m *GroupService) SetGroupParent(groupID uuid.UUID, parentID *uuid.UUID) httperrors.HTTPError {
+	ret := _m.Called(groupID, parentID)
+
+	var r0 httperrors.HTTPError
+	if rf, ok := ret.Get(0).(func(uuid.UUID, *uuid.UUID) httperrors.HTTPError); ok {
+		r0 = rf(groupID, parentID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httperrors.HTTPError)
+		}
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewGroupService interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewGroupService creates a new instance of GroupService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewGroupService(t mockConstructorTestingTNewGroupService) *GroupService {
+	mock := &GroupService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
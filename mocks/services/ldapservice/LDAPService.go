@@ -0,0 +1,57 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	httperrors "github.com/ditrit/badaas/httperrors"
+	dto "github.com/ditrit/badaas/persistence/models/dto"
+
+	mock "github.com/stretchr/testify/mock"
+
+	models "github.com/ditrit/badaas/persistence/models"
+)
+
+// LDAPService is an autogenerated mock type for the LDAPService type
+type LDAPService struct {
+	mock.Mock
+}
+
+// GetUser provides a mock function with given fields: userLoginDTO
+func (_m *LDAPService) GetUser(userLoginDTO dto.UserLoginDTO) (*models.User, httperrors.HTTPError) {
+	ret := _m.Called(userLoginDTO)
+
+	var r0 *models.User
+	if rf, ok := ret.Get(0).(func(dto.UserLoginDTO) *models.User); ok {
+		r0 = rf(userLoginDTO)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.User)
+		}
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func(dto.UserLoginDTO) httperrors.HTTPError); ok {
+		r1 = rf(userLoginDTO)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewLDAPService interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewLDAPService creates a new instance of LDAPService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewLDAPService(t mockConstructorTestingTNewLDAPService) *LDAPService {
+	mock := &LDAPService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
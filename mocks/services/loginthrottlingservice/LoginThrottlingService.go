@@ -0,0 +1,102 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	httperrors "github.com/ditrit/badaas/httperrors"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// LoginThrottlingService is an autogenerated mock type for the LoginThrottlingService type
+type LoginThrottlingService struct {
+	mock.Mock
+}
+
+// Check provides a mock function with given fields: email, ip
+func (_m *LoginThrottlingService) Check(email string, ip string) (time.Duration, httperrors.HTTPError) {
+	ret := _m.Called(email, ip)
+
+	var r0 time.Duration
+	if rf, ok := ret.Get(0).(func(string, string) time.Duration); ok {
+		r0 = rf(email, ip)
+	} else {
+		r0 = ret.Get(0).(time.Duration)
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func(string, string) httperrors.HTTPError); ok {
+		r1 = rf(email, ip)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
+// PurgeStale provides a mock function with given fields:
+func (_m *LoginThrottlingService) PurgeStale() httperrors.HTTPError {
+	ret := _m.Called()
+
+	var r0 httperrors.HTTPError
+	if rf, ok := ret.Get(0).(func() httperrors.HTTPError); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httperrors.HTTPError)
+		}
+	}
+
+	return r0
+}
+
+// RecordFailure provides a mock function with given fields: email, ip
+func (_m *LoginThrottlingService) RecordFailure(email string, ip string) httperrors.HTTPError {
+	ret := _m.Called(email, ip)
+
+	var r0 httperrors.HTTPError
+	if rf, ok := ret.Get(0).(func(string, string) httperrors.HTTPError); ok {
+		r0 = rf(email, ip)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httperrors.HTTPError)
+		}
+	}
+
+	return r0
+}
+
+// ResetAccount provides a mock function with given fields: email
+func (_m *LoginThrottlingService) ResetAccount(email string) httperrors.HTTPError {
+	ret := _m.Called(email)
+
+	var r0 httperrors.HTTPError
+	if rf, ok := ret.Get(0).(func(string) httperrors.HTTPError); ok {
+		r0 = rf(email)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httperrors.HTTPError)
+		}
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewLoginThrottlingService interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewLoginThrottlingService creates a new instance of LoginThrottlingService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewLoginThrottlingService(t mockConstructorTestingTNewLoginThrottlingService) *LoginThrottlingService {
+	mock := &LoginThrottlingService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
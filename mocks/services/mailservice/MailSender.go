@@ -0,0 +1,42 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	mailservice "github.com/ditrit/badaas/services/mailservice"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MailSender is an autogenerated mock type for the MailSender type
+type MailSender struct {
+	mock.Mock
+}
+
+// Send provides a mock function with given fields: mail
+func (_m *MailSender) Send(mail *mailservice.Mail) error {
+	ret := _m.Called(mail)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*mailservice.Mail) error); ok {
+		r0 = rf(mail)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewMailSender interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewMailSender creates a new instance of MailSender. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewMailSender(t mockConstructorTestingTNewMailSender) *MailSender {
+	mock := &MailSender{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,53 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package mocks
+
+import mock "github.com/stretchr/testify/mock"
+
+// Mailer is an autogenerated mock type for the Mailer type
+type Mailer struct {
+	mock.Mock
+}
+
+// ProcessQueue provides a mock function with given fields:
+func (_m *Mailer) ProcessQueue() error {
+	ret := _m.Called()
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SendTemplate provides a mock function with given fields: to, locale, templateName, data
+func (_m *Mailer) SendTemplate(to string, locale string, templateName string, data interface{}) error {
+	ret := _m.Called(to, locale, templateName, data)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string, interface{}) error); ok {
+		r0 = rf(to, locale, templateName, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewMailer interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewMailer creates a new instance of Mailer. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewMailer(t mockConstructorTestingTNewMailer) *Mailer {
+	mock := &Mailer{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
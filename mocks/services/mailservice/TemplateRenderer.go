@@ -0,0 +1,51 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	mailservice "github.com/ditrit/badaas/services/mailservice"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TemplateRenderer is an autogenerated mock type for the TemplateRenderer type
+type TemplateRenderer struct {
+	mock.Mock
+}
+
+// Render provides a mock function with given fields: locale, name, data
+func (_m *TemplateRenderer) Render(locale string, name string, data interface{}) (*mailservice.Mail, error) {
+	ret := _m.Called(locale, name, data)
+
+	var r0 *mailservice.Mail
+	if rf, ok := ret.Get(0).(func(string, string, interface{}) *mailservice.Mail); ok {
+		r0 = rf(locale, name, data)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*mailservice.Mail)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, interface{}) error); ok {
+		r1 = rf(locale, name, data)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewTemplateRenderer interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewTemplateRenderer creates a new instance of TemplateRenderer. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewTemplateRenderer(t mockConstructorTestingTNewTemplateRenderer) *TemplateRenderer {
+	mock := &TemplateRenderer{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
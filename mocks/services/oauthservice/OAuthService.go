@@ -0,0 +1,250 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	httperrors "github.com/ditrit/badaas/httperrors"
+	dto "github.com/ditrit/badaas/persistence/models/dto"
+
+	mock "github.com/stretchr/testify/mock"
+
+	models "github.com/ditrit/badaas/persistence/models"
+
+	oauth2 "github.com/ditrit/badaas/services/auth/protocols/oauth2"
+
+	oauthservice "github.com/ditrit/badaas/services/oauthservice"
+
+	uuid "github.com/google/uuid"
+)
+
+// OAuthService is an autogenerated mock type for the OAuthService type
+type OAuthService struct {
+	mock.Mock
+}
+
+// Authorize provides a mock function with given fields: userID, request
+func (_m *OAuthService) Authorize(userID uuid.UUID, request dto.DTOOAuthAuthorizationRequest) (*oauthservice.Authorization, httperrors.HTTPError) {
+	ret := _m.Called(userID, request)
+
+	var r0 *oauthservice.Authorization
+	if rf, ok := ret.Get(0).(func(uuid.UUID, dto.DTOOAuthAuthorizationRequest) *oauthservice.Authorization); ok {
+		r0 = rf(userID, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*oauthservice.Authorization)
+		}
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func(uuid.UUID, dto.DTOOAuthAuthorizationRequest) httperrors.HTTPError); ok {
+		r1 = rf(userID, request)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
+// Consent provides a mock function with given fields: userID, request, approved
+func (_m *OAuthService) Consent(userID uuid.UUID, request dto.DTOOAuthAuthorizationRequest, approved bool) (string, httperrors.HTTPError) {
+	ret := _m.Called(userID, request, approved)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(uuid.UUID, dto.DTOOAuthAuthorizationRequest, bool) string); ok {
+		r0 = rf(userID, request, approved)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func(uuid.UUID, dto.DTOOAuthAuthorizationRequest, bool) httperrors.HTTPError); ok {
+		r1 = rf(userID, request, approved)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
+// CreateClient provides a mock function with given fields: createClientDTO
+func (_m *OAuthService) CreateClient(createClientDTO dto.DTOCreateOAuthClient) (*models.OAuthClient, string, httperrors.HTTPError) {
+	ret := _m.Called(createClientDTO)
+
+	var r0 *models.OAuthClient
+	if rf, ok := ret.Get(0).(func(dto.DTOCreateOAuthClient) *models.OAuthClient); ok {
+		r0 = rf(createClientDTO)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.OAuthClient)
+		}
+	}
+
+	var r1 string
+	if rf, ok := ret.Get(1).(func(dto.DTOCreateOAuthClient) string); ok {
+		r1 = rf(createClientDTO)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	var r2 httperrors.HTTPError
+	if rf, ok := ret.Get(2).(func(dto.DTOCreateOAuthClient) httperrors.HTTPError); ok {
+		r2 = rf(createClientDTO)
+	} else {
+		if ret.Get(2) != nil {
+			r2 = ret.Get(2).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1, r2
+}
+
+// DeleteClient provides a mock function with given fields: clientID
+func (_m *OAuthService) DeleteClient(clientID uuid.UUID) httperrors.HTTPError {
+	ret := _m.Called(clientID)
+
+	var r0 httperrors.HTTPError
+	if rf, ok := ret.Get(0).(func(uuid.UUID) httperrors.HTTPError); ok {
+		r0 = rf(clientID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httperrors.HTTPError)
+		}
+	}
+
+	return r0
+}
+
+// GetClient provides a mock function with given fields: clientID
+func (_m *OAuthService) GetClient(clientID uuid.UUID) (*models.OAuthClient, httperrors.HTTPError) {
+	ret := _m.Called(clientID)
+
+	var r0 *models.OAuthClient
+	if rf, ok := ret.Get(0).(func(uuid.UUID) *models.OAuthClient); ok {
+		r0 = rf(clientID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.OAuthClient)
+		}
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func(uuid.UUID) httperrors.HTTPError); ok {
+		r1 = rf(clientID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetClients provides a mock function with given fields:
+func (_m *OAuthService) GetClients() ([]*models.OAuthClient, httperrors.HTTPError) {
+	ret := _m.Called()
+
+	var r0 []*models.OAuthClient
+	if rf, ok := ret.Get(0).(func() []*models.OAuthClient); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.OAuthClient)
+		}
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func() httperrors.HTTPError); ok {
+		r1 = rf()
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
+// Introspect provides a mock function with given fields: credentials, token
+func (_m *OAuthService) Introspect(credentials oauth2.ClientCredentials, token string) (*dto.DTOOAuthIntrospection, httperrors.HTTPError) {
+	ret := _m.Called(credentials, token)
+
+	var r0 *dto.DTOOAuthIntrospection
+	if rf, ok := ret.Get(0).(func(oauth2.ClientCredentials, string) *dto.DTOOAuthIntrospection); ok {
+		r0 = rf(credentials, token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.DTOOAuthIntrospection)
+		}
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func(oauth2.ClientCredentials, string) httperrors.HTTPError); ok {
+		r1 = rf(credentials, token)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
+// Revoke provides a mock function with given fields: credentials, token
+func (_m *OAuthService) Revoke(credentials oauth2.ClientCredentials, token string) httperrors.HTTPError {
+	ret := _m.Called(credentials, token)
+
+	var r0 httperrors.HTTPError
+	if rf, ok := ret.Get(0).(func(oauth2.ClientCredentials, string) httperrors.HTTPError); ok {
+		r0 = rf(credentials, token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httperrors.HTTPError)
+		}
+	}
+
+	return r0
+}
+
+// Token provides a mock function with given fields: credentials, request
+func (_m *OAuthService) Token(credentials oauth2.ClientCredentials, request dto.DTOOAuthTokenRequest) (*dto.DTOOAuthToken, httperrors.HTTPError) {
+	ret := _m.Called(credentials, request)
+
+	var r0 *dto.DTOOAuthToken
+	if rf, ok := ret.Get(0).(func(oauth2.ClientCredentials, dto.DTOOAuthTokenRequest) *dto.DTOOAuthToken); ok {
+		r0 = rf(credentials, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.DTOOAuthToken)
+		}
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func(oauth2.ClientCredentials, dto.DTOOAuthTokenRequest) httperrors.HTTPError); ok {
+		r1 = rf(credentials, request)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewOAuthService interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewOAuthService creates a new instance of OAuthService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewOAuthService(t mockConstructorTestingTNewOAuthService) *OAuthService {
+	mock := &OAuthService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,101 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	httperrors "github.com/ditrit/badaas/httperrors"
+	mock "github.com/stretchr/testify/mock"
+
+	models "github.com/ditrit/badaas/persistence/models"
+)
+
+// OIDCService is an autogenerated mock type for the OIDCService type
+type OIDCService struct {
+	mock.Mock
+}
+
+// BeginLogin provides a mock function with given fields: provider
+func (_m *OIDCService) BeginLogin(provider string) (string, string, httperrors.HTTPError) {
+	ret := _m.Called(provider)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(provider)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 string
+	if rf, ok := ret.Get(1).(func(string) string); ok {
+		r1 = rf(provider)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	var r2 httperrors.HTTPError
+	if rf, ok := ret.Get(2).(func(string) httperrors.HTTPError); ok {
+		r2 = rf(provider)
+	} else {
+		if ret.Get(2) != nil {
+			r2 = ret.Get(2).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1, r2
+}
+
+// FinishLogin provides a mock function with given fields: provider, state, code
+func (_m *OIDCService) FinishLogin(provider string, state string, code string) (*models.User, httperrors.HTTPError) {
+	ret := _m.Called(provider, state, code)
+
+	var r0 *models.User
+	if rf, ok := ret.Get(0).(func(string, string, string) *models.User); ok {
+		r0 = rf(provider, state, code)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.User)
+		}
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func(string, string, string) httperrors.HTTPError); ok {
+		r1 = rf(provider, state, code)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetProviders provides a mock function with given fields:
+func (_m *OIDCService) GetProviders() []string {
+	ret := _m.Called()
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func() []string); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewOIDCService interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewOIDCService creates a new instance of OIDCService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewOIDCService(t mockConstructorTestingTNewOIDCService) *OIDCService {
+	mock := &OIDCService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
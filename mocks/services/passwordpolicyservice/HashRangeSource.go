@@ -0,0 +1,48 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package mocks
+
+import mock "github.com/stretchr/testify/mock"
+
+// HashRangeSource is an autogenerated mock type for the HashRangeSource type
+type HashRangeSource struct {
+	mock.Mock
+}
+
+// GetSuffixes provides a mock function with given fields: prefix
+func (_m *HashRangeSource) GetSuffixes(prefix string) ([]string, error) {
+	ret := _m.Called(prefix)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(string) []string); ok {
+		r0 = rf(prefix)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(prefix)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewHashRangeSource interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewHashRangeSource creates a new instance of HashRangeSource. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewHashRangeSource(t mockConstructorTestingTNewHashRangeSource) *HashRangeSource {
+	mock := &HashRangeSource{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
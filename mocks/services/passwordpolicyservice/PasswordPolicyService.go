@@ -0,0 +1,62 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	httperrors "github.com/ditrit/badaas/httperrors"
+	mock "github.com/stretchr/testify/mock"
+
+	models "github.com/ditrit/badaas/persistence/models"
+)
+
+// PasswordPolicyService is an autogenerated mock type for the PasswordPolicyService type
+type PasswordPolicyService struct {
+	mock.Mock
+}
+
+// RecordPassword provides a mock function with given fields: user
+func (_m *PasswordPolicyService) RecordPassword(user *models.User) httperrors.HTTPError {
+	ret := _m.Called(user)
+
+	var r0 httperrors.HTTPError
+	if rf, ok := ret.Get(0).(func(*models.User) httperrors.HTTPError); ok {
+		r0 = rf(user)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httperrors.HTTPError)
+		}
+	}
+
+	return r0
+}
+
+// Validate provides a mock function with given fields: user, password
+func (_m *PasswordPolicyService) Validate(user *models.User, password string) httperrors.HTTPError {
+	ret := _m.Called(user, password)
+
+	var r0 httperrors.HTTPError
+	if rf, ok := ret.Get(0).(func(*models.User, string) httperrors.HTTPError); ok {
+		r0 = rf(user, password)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httperrors.HTTPError)
+		}
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewPasswordPolicyService interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewPasswordPolicyService creates a new instance of PasswordPolicyService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewPasswordPolicyService(t mockConstructorTestingTNewPasswordPolicyService) *PasswordPolicyService {
+	mock := &PasswordPolicyService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
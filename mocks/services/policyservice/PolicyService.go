@@ -0,0 +1,132 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	httperrors "github.com/ditrit/badaas/httperrors"
+	mock "github.com/stretchr/testify/mock"
+
+	models "github.com/ditrit/badaas/persistence/models"
+
+	policyservice "github.com/ditrit/badaas/services/policyservice"
+
+	uuid "github.com/google/uuid"
+)
+
+// PolicyService is an autogenerated mock type for the PolicyService type
+type PolicyService struct {
+	mock.Mock
+}
+
+// Authorize provides a mock function with given fields: accessRequest
+func (_m *PolicyService) Authorize(accessRequest policyservice.AccessRequest) httperrors.HTTPError {
+	ret := _m.Called(accessRequest)
+
+	var r0 httperrors.HTTPError
+	if rf, ok := ret.Get(0).(func(policyservice.AccessRequest) httperrors.HTTPError); ok {
+		r0 = rf(accessRequest)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httperrors.HTTPError)
+		}
+	}
+
+	return r0
+}
+
+// CreatePolicy provides a mock function with given fields: policy
+func (_m *PolicyService) CreatePolicy(policy *models.Policy) httperrors.HTTPError {
+	ret := _m.Called(policy)
+
+	var r0 httperrors.HTTPError
+	if rf, ok := ret.Get(0).(func(*models.Policy) httperrors.HTTPError); ok {
+		r0 = rf(policy)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httperrors.HTTPError)
+		}
+	}
+
+	return r0
+}
+
+// DeletePolicy provides a mock function with given fields: policyID
+func (_m *PolicyService) DeletePolicy(policyID uuid.UUID) httperrors.HTTPError {
+	ret := _m.Called(policyID)
+
+	var r0 httperrors.HTTPError
+	if rf, ok := ret.Get(0).(func(uuid.UUID) httperrors.HTTPError); ok {
+		r0 = rf(policyID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httperrors.HTTPError)
+		}
+	}
+
+	return r0
+}
+
+// Evaluate provides a mock function with given fields: accessRequest
+func (_m *PolicyService) Evaluate(accessRequest policyservice.AccessRequest) (*policyservice.Decision, httperrors.HTTPError) {
+	ret := _m.Called(accessRequest)
+
+	var r0 *policyservice.Decision
+	if rf, ok := ret.Get(0).(func(policyservice.AccessRequest) *policyservice.Decision); ok {
+		r0 = rf(accessRequest)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*policyservice.Decision)
+		}
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func(policyservice.AccessRequest) httperrors.HTTPError); ok {
+		r1 = rf(accessRequest)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetPolicies provides a mock function with given fields:
+func (_m *PolicyService) GetPolicies() ([]*models.Policy, httperrors.HTTPError) {
+	ret := _m.Called()
+
+	var r0 []*models.Policy
+	if rf, ok := ret.Get(0).(func() []*models.Policy); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Policy)
+		}
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func() httperrors.HTTPError); ok {
+		r1 = rf()
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewPolicyService interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewPolicyService creates a new instance of PolicyService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewPolicyService(t mockConstructorTestingTNewPolicyService) *PolicyService {
+	mock := &PolicyService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,55 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package mocks
+
+import mock "github.com/stretchr/testify/mock"
+
+// Resource is an autogenerated mock type for the Resource type
+type Resource struct {
+	mock.Mock
+}
+
+// ResourceAttributes provides a mock function with given fields:
+func (_m *Resource) ResourceAttributes() map[string]interface{} {
+	ret := _m.Called()
+
+	var r0 map[string]interface{}
+	if rf, ok := ret.Get(0).(func() map[string]interface{}); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]interface{})
+		}
+	}
+
+	return r0
+}
+
+// ResourceType provides a mock function with given fields:
+func (_m *Resource) ResourceType() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewResource interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewResource creates a new instance of Resource. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewResource(t mockConstructorTestingTNewResource) *Resource {
+	mock := &Resource{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,383 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	httperrors "github.com/ditrit/badaas/httperrors"
+	mock "github.com/stretchr/testify/mock"
+
+	models "github.com/ditrit/badaas/persistence/models"
+
+	uuid "github.com/google/uuid"
+)
+
+// RBACService is an autogenerated mock type for the RBACService type
+type RBACService struct {
+	mock.Mock
+}
+
+// AssignGroupRole provides a mock function with given fields: groupID, roleID
+func (_m *RBACService) AssignGroupRole(groupID uuid.UUID, roleID uuid.UUID) httperrors.HTTPError {
+	ret := _m.Called(groupID, roleID)
+
+	var r0 httperrors.HTTPError
+	if rf, ok := ret.Get(0).(func(uuid.UUID, uuid.UUID) httperrors.HTTPError); ok {
+		r0 = rf(groupID, roleID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httperrors.HTTPError)
+		}
+	}
+
+	return r0
+}
+
+// AssignRole provides a mock function with given fields: userID, roleID
+func (_m *RBACService) AssignRole(userID uuid.UUID, roleID uuid.UUID) httperrors.HTTPError {
+	ret := _m.Called(userID, roleID)
+
+	var r0 httperrors.HTTPError
+	if rf, ok := ret.Get(0).(func(uuid.UUID, uuid.UUID) httperrors.HTTPError); ok {
+		r0 = rf(userID, roleID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httperrors.HTTPError)
+		}
+	}
+
+	return r0
+}
+
+// CheckPermission provides a mock function with given fields: userID, permission
+func (_m *RBACService) CheckPermission(userID uuid.UUID, permission string) httperrors.HTTPError {
+	ret := _m.Called(userID, permission)
+
+	var r0 httperrors.HTTPError
+	if rf, ok := ret.Get(0).(func(uuid.UUID, string) httperrors.HTTPError); ok {
+		r0 = rf(userID, permission)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httperrors.HTTPError)
+		}
+	}
+
+	return r0
+}
+
+// CreateRole provides a mock function with given fields: name, description
+func (_m *RBACService) CreateRole(name string, description string) (*models.Role, httperrors.HTTPError) {
+	ret := _m.Called(name, description)
+
+	var r0 *models.Role
+	if rf, ok := ret.Get(0).(func(string, string) *models.Role); ok {
+		r0 = rf(name, description)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Role)
+		}
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func(string, string) httperrors.HTTPError); ok {
+		r1 = rf(name, description)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
+// DeleteRole provides a mock function with given fields: roleID
+func (_m *RBACService) DeleteRole(roleID uuid.UUID) httperrors.HTTPError {
+	ret := _m.Called(roleID)
+
+	var r0 httperrors.HTTPError
+	if rf, ok := ret.Get(0).(func(uuid.UUID) httperrors.HTTPError); ok {
+		r0 = rf(roleID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httperrors.HTTPError)
+		}
+	}
+
+	return r0
+}
+
+// EnsureSuperAdminRole provides a mock function with given fields:
+func (_m *RBACService) EnsureSuperAdminRole() (*models.Role, httperrors.HTTPError) {
+	ret := _m.Called()
+
+	var r0 *models.Role
+	if rf, ok := ret.Get(0).(func() *models.Role); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Role)
+		}
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func() httperrors.HTTPError); ok {
+		r1 = rf()
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetGroupRoles provides a mock function with given fields: groupID
+func (_m *RBACService) GetGroupRoles(groupID uuid.UUID) ([]*models.Role, httperrors.HTTPError) {
+	ret := _m.Called(groupID)
+
+	var r0 []*models.Role
+	if rf, ok := ret.Get(0).(func(uuid.UUID) []*models.Role); ok {
+		r0 = rf(groupID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Role)
+		}
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func(uuid.UUID) httperrors.HTTPError); ok {
+		r1 = rf(groupID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetRole provides a mock function with given fields: roleID
+func (_m *RBACService) GetRole(roleID uuid.UUID) (*models.Role, httperrors.HTTPError) {
+	ret := _m.Called(roleID)
+
+	var r0 *models.Role
+	if rf, ok := ret.Get(0).(func(uuid.UUID) *models.Role); ok {
+		r0 = rf(roleID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Role)
+		}
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func(uuid.UUID) httperrors.HTTPError); ok {
+		r1 = rf(roleID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetRolePermissions provides a mock function with given fields: roleID
+func (_m *RBACService) GetRolePermissions(roleID uuid.UUID) ([]string, httperrors.HTTPError) {
+	ret := _m.Called(roleID)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(uuid.UUID) []string); ok {
+		r0 = rf(roleID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func(uuid.UUID) httperrors.HTTPError); ok {
+		r1 = rf(roleID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetRoles provides a mock function with given fields:
+func (_m *RBACService) GetRoles() ([]*models.Role, httperrors.HTTPError) {
+	ret := _m.Called()
+
+	var r0 []*models.Role
+	if rf, ok := ret.Get(0).(func() []*models.Role); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Role)
+		}
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func() httperrors.HTTPError); ok {
+		r1 = rf()
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetUserPermissions provides a mock function with given fields: userID
+func (_m *RBACService) GetUserPermissions(userID uuid.UUID) ([]string, httperrors.HTTPError) {
+	ret := _m.Called(userID)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(uuid.UUID) []string); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func(uuid.UUID) httperrors.HTTPError); ok {
+		r1 = rf(userID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetUserRoles provides a mock function with given fields: userID
+func (_m *RBACService) GetUserRoles(userID uuid.UUID) ([]*models.Role, httperrors.HTTPError) {
+	ret := _m.Called(userID)
+
+	var r0 []*models.Role
+	if rf, ok := ret.Get(0).(func(uuid.UUID) []*models.Role); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Role)
+		}
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func(uuid.UUID) httperrors.HTTPError); ok {
+		r1 = rf(userID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
+// GrantPermission provides a mock function with given fields: roleID, permission
+func (_m *RBACService) GrantPermission(roleID uuid.UUID, permission string) httperrors.HTTPError {
+	ret := _m.Called(roleID, permission)
+
+	var r0 httperrors.HTTPError
+	if rf, ok := ret.Get(0).(func(uuid.UUID, string) httperrors.HTTPError); ok {
+		r0 = rf(roleID, permission)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httperrors.HTTPError)
+		}
+	}
+
+	return r0
+}
+
+// HasPermission provides a mock function with given fields: userID, permission
+func (_m *RBACService) HasPermission(userID uuid.UUID, permission string) (bool, httperrors.HTTPError) {
+	ret := _m.Called(userID, permission)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(uuid.UUID, string) bool); ok {
+		r0 = rf(userID, permission)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func(uuid.UUID, string) httperrors.HTTPError); ok {
+		r1 = rf(userID, permission)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
+// RevokePermission provides a mock function with given fields: roleID, permission
+func (_m *RBACService) RevokePermission(roleID uuid.UUID, permission string) httperrors.HTTPError {
+	ret := _m.Called(roleID, permission)
+
+	var r0 httperrors.HTTPError
+	if rf, ok := ret.Get(0).(func(uuid.UUID, string) httperrors.HTTPError); ok {
+		r0 = rf(roleID, permission)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httperrors.HTTPError)
+		}
+	}
+
+	return r0
+}
+
+// UnassignGroupRole provides a mock function with given fields: groupID, roleID
+func (_m *RBACService) UnassignGroupRole(groupID uuid.UUID, roleID uuid.UUID) httperrors.HTTPError {
+	ret := _m.Called(groupID, roleID)
+
+	var r0 httperrors.HTTPError
+	if rf, ok := ret.Get(0).(func(uuid.UUID, uuid.UUID) httperrors.HTTPError); ok {
+		r0 = rf(groupID, roleID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httperrors.HTTPError)
+		}
+	}
+
+	return r0
+}
+
+// UnassignRole provides a mock function with given fields: userID, roleID
+func (_m *RBACService) UnassignRole(userID uuid.UUID, roleID uuid.UUID) httperrors.HTTPError {
+	ret := _m.Called(userID, roleID)
+
+	var r0 httperrors.HTTPError
+	if rf, ok := ret.Get(0).(func(uuid.UUID, uuid.UUID) httperrors.HTTPError); ok {
+		r0 = rf(userID, roleID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httperrors.HTTPError)
+		}
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewRBACService interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewRBACService creates a new instance of RBACService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewRBACService(t mockConstructorTestingTNewRBACService) *RBACService {
+	mock := &RBACService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,57 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	httperrors "github.com/ditrit/badaas/httperrors"
+	dto "github.com/ditrit/badaas/persistence/models/dto"
+
+	mock "github.com/stretchr/testify/mock"
+
+	models "github.com/ditrit/badaas/persistence/models"
+)
+
+// RegistrationService is an autogenerated mock type for the RegistrationService type
+type RegistrationService struct {
+	mock.Mock
+}
+
+// Register provides a mock function with given fields: registerDTO
+func (_m *RegistrationService) Register(registerDTO dto.DTORegister) (*models.User, httperrors.HTTPError) {
+	ret := _m.Called(registerDTO)
+
+	var r0 *models.User
+	if rf, ok := ret.Get(0).(func(dto.DTORegister) *models.User); ok {
+		r0 = rf(registerDTO)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.User)
+		}
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func(dto.DTORegister) httperrors.HTTPError); ok {
+		r1 = rf(registerDTO)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewRegistrationService interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewRegistrationService creates a new instance of RegistrationService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewRegistrationService(t mockConstructorTestingTNewRegistrationService) *RegistrationService {
+	mock := &RegistrationService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
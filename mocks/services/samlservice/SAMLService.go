@@ -0,0 +1,110 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	httperrors "github.com/ditrit/badaas/httperrors"
+	mock "github.com/stretchr/testify/mock"
+
+	models "github.com/ditrit/badaas/persistence/models"
+)
+
+// SAMLService is an autogenerated mock type for the SAMLService type
+type SAMLService struct {
+	mock.Mock
+}
+
+// BeginLogin provides a mock function with given fields:
+func (_m *SAMLService) BeginLogin() (string, string, httperrors.HTTPError) {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 string
+	if rf, ok := ret.Get(1).(func() string); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	var r2 httperrors.HTTPError
+	if rf, ok := ret.Get(2).(func() httperrors.HTTPError); ok {
+		r2 = rf()
+	} else {
+		if ret.Get(2) != nil {
+			r2 = ret.Get(2).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1, r2
+}
+
+// FinishLogin provides a mock function with given fields: encodedResponse, requestID
+func (_m *SAMLService) FinishLogin(encodedResponse string, requestID string) (*models.User, httperrors.HTTPError) {
+	ret := _m.Called(encodedResponse, requestID)
+
+	var r0 *models.User
+	if rf, ok := ret.Get(0).(func(string, string) *models.User); ok {
+		r0 = rf(encodedResponse, requestID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.User)
+		}
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func(string, string) httperrors.HTTPError); ok {
+		r1 = rf(encodedResponse, requestID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetMetadata provides a mock function with given fields:
+func (_m *SAMLService) GetMetadata() ([]byte, httperrors.HTTPError) {
+	ret := _m.Called()
+
+	var r0 []byte
+	if rf, ok := ret.Get(0).(func() []byte); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func() httperrors.HTTPError); ok {
+		r1 = rf()
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewSAMLService interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewSAMLService creates a new instance of SAMLService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewSAMLService(t mockConstructorTestingTNewSAMLService) *SAMLService {
+	mock := &SAMLService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
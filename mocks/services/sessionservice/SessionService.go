@@ -6,6 +6,8 @@ import (
 	http "net/http"
 
 	httperrors "github.com/ditrit/badaas/httperrors"
+	jose "github.com/go-jose/go-jose/v3"
+
 	mock "github.com/stretchr/testify/mock"
 
 	models "github.com/ditrit/badaas/persistence/models"
@@ -20,36 +22,77 @@ type SessionService struct {
 	mock.Mock
 }
 
-// IsValid provides a mock function with given fields: sessionUUID
-func (_m *SessionService) IsValid(sessionUUID uuid.UUID) (bool, *sessionservice.SessionClaims) {
-	ret := _m.Called(sessionUUID)
+// Authenticate provides a mock function with given fields: accessToken
+func (_m *SessionService) Authenticate(accessToken string) (*sessionservice.SessionClaims, httperrors.HTTPError) {
+	ret := _m.Called(accessToken)
+
+	var r0 *sessionservice.SessionClaims
+	if rf, ok := ret.Get(0).(func(string) *sessionservice.SessionClaims); ok {
+		r0 = rf(accessToken)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*sessionservice.SessionClaims)
+		}
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func(string) httperrors.HTTPError); ok {
+		r1 = rf(accessToken)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetJWKS provides a mock function with given fields:
+func (_m *SessionService) GetJWKS() jose.JSONWebKeySet {
+	ret := _m.Called()
+
+	var r0 jose.JSONWebKeySet
+	if rf, ok := ret.Get(0).(func() jose.JSONWebKeySet); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(jose.JSONWebKeySet)
+	}
+
+	return r0
+}
+
+// GetUserSessions provides a mock function with given fields: userID
+func (_m *SessionService) GetUserSessions(userID uuid.UUID) ([]*models.Session, httperrors.HTTPError) {
+	ret := _m.Called(userID)
 
-	var r0 bool
-	if rf, ok := ret.Get(0).(func(uuid.UUID) bool); ok {
-		r0 = rf(sessionUUID)
+	var r0 []*models.Session
+	if rf, ok := ret.Get(0).(func(uuid.UUID) []*models.Session); ok {
+		r0 = rf(userID)
 	} else {
-		r0 = ret.Get(0).(bool)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Session)
+		}
 	}
 
-	var r1 *sessionservice.SessionClaims
-	if rf, ok := ret.Get(1).(func(uuid.UUID) *sessionservice.SessionClaims); ok {
-		r1 = rf(sessionUUID)
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func(uuid.UUID) httperrors.HTTPError); ok {
+		r1 = rf(userID)
 	} else {
 		if ret.Get(1) != nil {
-			r1 = ret.Get(1).(*sessionservice.SessionClaims)
+			r1 = ret.Get(1).(httperrors.HTTPError)
 		}
 	}
 
 	return r0, r1
 }
 
-// LogUserIn provides a mock function with given fields: user, response
-func (_m *SessionService) LogUserIn(user *models.User, response http.ResponseWriter) httperrors.HTTPError {
-	ret := _m.Called(user, response)
+// LogUserIn provides a mock function with given fields: user, request, response
+func (_m *SessionService) LogUserIn(user *models.User, request *http.Request, response http.ResponseWriter) httperrors.HTTPError {
+	ret := _m.Called(user, request, response)
 
 	var r0 httperrors.HTTPError
-	if rf, ok := ret.Get(0).(func(*models.User, http.ResponseWriter) httperrors.HTTPError); ok {
-		r0 = rf(user, response)
+	if rf, ok := ret.Get(0).(func(*models.User, *http.Request, http.ResponseWriter) httperrors.HTTPError); ok {
+		r0 = rf(user, request, response)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(httperrors.HTTPError)
@@ -75,6 +118,70 @@ func (_m *SessionService) LogUserOut(sessionClaims *sessionservice.SessionClaims
 	return r0
 }
 
+// Refresh provides a mock function with given fields: refreshToken, response
+func (_m *SessionService) Refresh(refreshToken string, response http.ResponseWriter) httperrors.HTTPError {
+	ret := _m.Called(refreshToken, response)
+
+	var r0 httperrors.HTTPError
+	if rf, ok := ret.Get(0).(func(string, http.ResponseWriter) httperrors.HTTPError); ok {
+		r0 = rf(refreshToken, response)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httperrors.HTTPError)
+		}
+	}
+
+	return r0
+}
+
+// RevokeOtherUserSessions provides a mock function with given fields: userID, keptSessionUUID
+func (_m *SessionService) RevokeOtherUserSessions(userID uuid.UUID, keptSessionUUID uuid.UUID) httperrors.HTTPError {
+	ret := _m.Called(userID, keptSessionUUID)
+
+	var r0 httperrors.HTTPError
+	if rf, ok := ret.Get(0).(func(uuid.UUID, uuid.UUID) httperrors.HTTPError); ok {
+		r0 = rf(userID, keptSessionUUID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httperrors.HTTPError)
+		}
+	}
+
+	return r0
+}
+
+// RevokeSession provides a mock function with given fields: userID, sessionUUID
+func (_m *SessionService) RevokeSession(userID uuid.UUID, sessionUUID uuid.UUID) httperrors.HTTPError {
+	ret := _m.Called(userID, sessionUUID)
+
+	var r0 httperrors.HTTPError
+	if rf, ok := ret.Get(0).(func(uuid.UUID, uuid.UUID) httperrors.HTTPError); ok {
+		r0 = rf(userID, sessionUUID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httperrors.HTTPError)
+		}
+	}
+
+	return r0
+}
+
+// RevokeUserSessions provides a mock function with given fields: userID
+func (_m *SessionService) RevokeUserSessions(userID uuid.UUID) httperrors.HTTPError {
+	ret := _m.Called(userID)
+
+	var r0 httperrors.HTTPError
+	if rf, ok := ret.Get(0).(func(uuid.UUID) httperrors.HTTPError); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httperrors.HTTPError)
+		}
+	}
+
+	return r0
+}
+
 // RollSession provides a mock function with given fields: _a0
 func (_m *SessionService) RollSession(_a0 uuid.UUID) httperrors.HTTPError {
 	ret := _m.Called(_a0)
@@ -91,6 +198,38 @@ func (_m *SessionService) RollSession(_a0 uuid.UUID) httperrors.HTTPError {
 	return r0
 }
 
+// RotateSession provides a mock function with given fields: sessionClaims, response
+func (_m *SessionService) RotateSession(sessionClaims *sessionservice.SessionClaims, response http.ResponseWriter) httperrors.HTTPError {
+	ret := _m.Called(sessionClaims, response)
+
+	var r0 httperrors.HTTPError
+	if rf, ok := ret.Get(0).(func(*sessionservice.SessionClaims, http.ResponseWriter) httperrors.HTTPError); ok {
+		r0 = rf(sessionClaims, response)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httperrors.HTTPError)
+		}
+	}
+
+	return r0
+}
+
+// Track provides a mock function with given fields: sessionClaims, request, response
+func (_m *SessionService) Track(sessionClaims *sessionservice.SessionClaims, request *http.Request, response http.ResponseWriter) httperrors.HTTPError {
+	ret := _m.Called(sessionClaims, request, response)
+
+	var r0 httperrors.HTTPError
+	if rf, ok := ret.Get(0).(func(*sessionservice.SessionClaims, *http.Request, http.ResponseWriter) httperrors.HTTPError); ok {
+		r0 = rf(sessionClaims, request, response)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httperrors.HTTPError)
+		}
+	}
+
+	return r0
+}
+
 type mockConstructorTestingTNewSessionService interface {
 	mock.TestingT
 	Cleanup(func())
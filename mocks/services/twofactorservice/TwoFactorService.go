@@ -0,0 +1,299 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	httperrors "github.com/ditrit/badaas/httperrors"
+	mock "github.com/stretchr/testify/mock"
+
+	twofactorservice "github.com/ditrit/badaas/services/twofactorservice"
+
+	uuid "github.com/google/uuid"
+)
+
+// TwoFactorService is an autogenerated mock type for the TwoFactorService type
+type TwoFactorService struct {
+	mock.Mock
+}
+
+// BeginEnrolment provides a mock function with given fields: userID
+func (_m *TwoFactorService) BeginEnrolment(userID uuid.UUID) (*twofactorservice.TOTPEnrolment, httperrors.HTTPError) {
+	ret := _m.Called(userID)
+
+	var r0 *twofactorservice.TOTPEnrolment
+	if rf, ok := ret.Get(0).(func(uuid.UUID) *twofactorservice.TOTPEnrolment); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*twofactorservice.TOTPEnrolment)
+		}
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func(uuid.UUID) httperrors.HTTPError); ok {
+		r1 = rf(userID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
+// ConfirmEnrolment provides a mock function with given fields: userID, code
+func (_m *TwoFactorService) ConfirmEnrolment(userID uuid.UUID, code string) ([]string, httperrors.HTTPError) {
+	ret := _m.Called(userID, code)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(uuid.UUID, string) []string); ok {
+		r0 = rf(userID, code)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func(uuid.UUID, string) httperrors.HTTPError); ok {
+		r1 = rf(userID, code)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
+// CreateChallenge provides a mock function with given fields: userID, enrolment
+func (_m *TwoFactorService) CreateChallenge(userID uuid.UUID, enrolment bool) (string, httperrors.HTTPError) {
+	ret := _m.Called(userID, enrolment)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(uuid.UUID, bool) string); ok {
+		r0 = rf(userID, enrolment)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func(uuid.UUID, bool) httperrors.HTTPError); ok {
+		r1 = rf(userID, enrolment)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
+// DeleteChallenge provides a mock function with given fields: token
+func (_m *TwoFactorService) DeleteChallenge(token string) httperrors.HTTPError {
+	ret := _m.Called(token)
+
+	var r0 httperrors.HTTPError
+	if rf, ok := ret.Get(0).(func(string) httperrors.HTTPError); ok {
+		r0 = rf(token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httperrors.HTTPError)
+		}
+	}
+
+	return r0
+}
+
+// Disable provides a mock function with given fields: userID, code
+func (_m *TwoFactorService) Disable(userID uuid.UUID, code string) httperrors.HTTPError {
+	ret := _m.Called(userID, code)
+
+	var r0 httperrors.HTTPError
+	if rf, ok := ret.Get(0).(func(uuid.UUID, string) httperrors.HTTPError); ok {
+		r0 = rf(userID, code)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httperrors.HTTPError)
+		}
+	}
+
+	return r0
+}
+
+// GetChallengeUserID provides a mock function with given fields: token
+func (_m *TwoFactorService) GetChallengeUserID(token string) (uuid.UUID, httperrors.HTTPError) {
+	ret := _m.Called(token)
+
+	var r0 uuid.UUID
+	if rf, ok := ret.Get(0).(func(string) uuid.UUID); ok {
+		r0 = rf(token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(uuid.UUID)
+		}
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func(string) httperrors.HTTPError); ok {
+		r1 = rf(token)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetEnrolmentChallengeUserID provides a mock function with given fields: token
+func (_m *TwoFactorService) GetEnrolmentChallengeUserID(token string) (uuid.UUID, httperrors.HTTPError) {
+	ret := _m.Called(token)
+
+	var r0 uuid.UUID
+	if rf, ok := ret.Get(0).(func(string) uuid.UUID); ok {
+		r0 = rf(token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(uuid.UUID)
+		}
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func(string) httperrors.HTTPError); ok {
+		r1 = rf(token)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
+// IsEnabled provides a mock function with given fields: userID
+func (_m *TwoFactorService) IsEnabled(userID uuid.UUID) (bool, httperrors.HTTPError) {
+	ret := _m.Called(userID)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(uuid.UUID) bool); ok {
+		r0 = rf(userID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func(uuid.UUID) httperrors.HTTPError); ok {
+		r1 = rf(userID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
+// IsRequired provides a mock function with given fields: userID
+func (_m *TwoFactorService) IsRequired(userID uuid.UUID) (bool, httperrors.HTTPError) {
+	ret := _m.Called(userID)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(uuid.UUID) bool); ok {
+		r0 = rf(userID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func(uuid.UUID) httperrors.HTTPError); ok {
+		r1 = rf(userID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
+// RegenerateRecoveryCodes provides a mock function with given fields: userID, code
+func (_m *TwoFactorService) RegenerateRecoveryCodes(userID uuid.UUID, code string) ([]string, httperrors.HTTPError) {
+	ret := _m.Called(userID, code)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(uuid.UUID, string) []string); ok {
+		r0 = rf(userID, code)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func(uuid.UUID, string) httperrors.HTTPError); ok {
+		r1 = rf(userID, code)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
+// Reset provides a mock function with given fields: userID
+func (_m *TwoFactorService) Reset(userID uuid.UUID) httperrors.HTTPError {
+	ret := _m.Called(userID)
+
+	var r0 httperrors.HTTPError
+	if rf, ok := ret.Get(0).(func(uuid.UUID) httperrors.HTTPError); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httperrors.HTTPError)
+		}
+	}
+
+	return r0
+}
+
+// VerifyChallenge provides a mock function with given fields: token, code
+func (_m *TwoFactorService) VerifyChallenge(token string, code string) (uuid.UUID, httperrors.HTTPError) {
+	ret := _m.Called(token, code)
+
+	var r0 uuid.UUID
+	if rf, ok := ret.Get(0).(func(string, string) uuid.UUID); ok {
+		r0 = rf(token, code)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(uuid.UUID)
+		}
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func(string, string) httperrors.HTTPError); ok {
+		r1 = rf(token, code)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewTwoFactorService interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewTwoFactorService creates a new instance of TwoFactorService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewTwoFactorService(t mockConstructorTestingTNewTwoFactorService) *TwoFactorService {
+	mock := &TwoFactorService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
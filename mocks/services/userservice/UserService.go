@@ -9,6 +9,10 @@ import (
 	mock "github.com/stretchr/testify/mock"
 
 	models "github.com/ditrit/badaas/persistence/models"
+
+	pagination "github.com/ditrit/badaas/persistence/pagination"
+
+	uuid "github.com/google/uuid"
 )
 
 // UserService is an autogenerated mock type for the UserService type
@@ -16,6 +20,79 @@ type UserService struct {
 	mock.Mock
 }
 
+// ChangePassword provides a mock function with given fields: userID, sessionUUID, currentPassword, newPassword
+func (_m *UserService) ChangePassword(userID uuid.UUID, sessionUUID uuid.UUID, currentPassword string, newPassword string) httperrors.HTTPError {
+	ret := _m.Called(userID, sessionUUID, currentPassword, newPassword)
+
+	var r0 httperrors.HTTPError
+	if rf, ok := ret.Get(0).(func(uuid.UUID, uuid.UUID, string, string) httperrors.HTTPError); ok {
+		r0 = rf(userID, sessionUUID, currentPassword, newPassword)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httperrors.HTTPError)
+		}
+	}
+
+	return r0
+}
+
+// CheckCanLogIn provides a mock function with given fields: user
+func (_m *UserService) CheckCanLogIn(user *models.User) httperrors.HTTPError {
+	ret := _m.Called(user)
+
+	var r0 httperrors.HTTPError
+	if rf, ok := ret.Get(0).(func(*models.User) httperrors.HTTPError); ok {
+		r0 = rf(user)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httperrors.HTTPError)
+		}
+	}
+
+	return r0
+}
+
+// ConfirmEmailChange provides a mock function with given fields: userID, token
+func (_m *UserService) ConfirmEmailChange(userID uuid.UUID, token string) (*models.User, httperrors.HTTPError) {
+	ret := _m.Called(userID, token)
+
+	var r0 *models.User
+	if rf, ok := ret.Get(0).(func(uuid.UUID, string) *models.User); ok {
+		r0 = rf(userID, token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.User)
+		}
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func(uuid.UUID, string) httperrors.HTTPError); ok {
+		r1 = rf(userID, token)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
+// DeleteUser provides a mock function with given fields: userID
+func (_m *UserService) DeleteUser(userID uuid.UUID) httperrors.HTTPError {
+	ret := _m.Called(userID)
+
+	var r0 httperrors.HTTPError
+	if rf, ok := ret.Get(0).(func(uuid.UUID) httperrors.HTTPError); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httperrors.HTTPError)
+		}
+	}
+
+	return r0
+}
+
 // GetUser provides a mock function with given fields: _a0
 func (_m *UserService) GetUser(_a0 dto.UserLoginDTO) (*models.User, httperrors.HTTPError) {
 	ret := _m.Called(_a0)
@@ -41,6 +118,120 @@ func (_m *UserService) GetUser(_a0 dto.UserLoginDTO) (*models.User, httperrors.H
 	return r0, r1
 }
 
+// GetUserByEmail provides a mock function with given fields: email
+func (_m *UserService) GetUserByEmail(email string) (*models.User, httperrors.HTTPError) {
+	ret := _m.Called(email)
+
+	var r0 *models.User
+	if rf, ok := ret.Get(0).(func(string) *models.User); ok {
+		r0 = rf(email)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.User)
+		}
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func(string) httperrors.HTTPError); ok {
+		r1 = rf(email)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetUserByID provides a mock function with given fields: userID
+func (_m *UserService) GetUserByID(userID uuid.UUID) (*models.User, httperrors.HTTPError) {
+	ret := _m.Called(userID)
+
+	var r0 *models.User
+	if rf, ok := ret.Get(0).(func(uuid.UUID) *models.User); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.User)
+		}
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func(uuid.UUID) httperrors.HTTPError); ok {
+		r1 = rf(userID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetUsers provides a mock function with given fields: search, paginator
+func (_m *UserService) GetUsers(search string, paginator pagination.Paginator) (*pagination.Page[models.User], httperrors.HTTPError) {
+	ret := _m.Called(search, paginator)
+
+	var r0 *pagination.Page[models.User]
+	if rf, ok := ret.Get(0).(func(string, pagination.Paginator) *pagination.Page[models.User]); ok {
+		r0 = rf(search, paginator)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*pagination.Page[models.User])
+		}
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func(string, pagination.Paginator) httperrors.HTTPError); ok {
+		r1 = rf(search, paginator)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
+// MarkEmailVerified provides a mock function with given fields: userID
+func (_m *UserService) MarkEmailVerified(userID uuid.UUID) httperrors.HTTPError {
+	ret := _m.Called(userID)
+
+	var r0 httperrors.HTTPError
+	if rf, ok := ret.Get(0).(func(uuid.UUID) httperrors.HTTPError); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httperrors.HTTPError)
+		}
+	}
+
+	return r0
+}
+
+// NewSystemUser provides a mock function with given fields: username, email, password
+func (_m *UserService) NewSystemUser(username string, email string, password string) (*models.User, error) {
+	ret := _m.Called(username, email, password)
+
+	var r0 *models.User
+	if rf, ok := ret.Get(0).(func(string, string, string) *models.User); ok {
+		r0 = rf(username, email, password)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, string) error); ok {
+		r1 = rf(username, email, password)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewUser provides a mock function with given fields: username, email, password
 func (_m *UserService) NewUser(username string, email string, password string) (*models.User, error) {
 	ret := _m.Called(username, email, password)
@@ -64,6 +255,184 @@ func (_m *UserService) NewUser(username string, email string, password string) (
 	return r0, r1
 }
 
+// RequestEmailChange provides a mock function with given fields: userID, email
+func (_m *UserService) RequestEmailChange(userID uuid.UUID, email string) httperrors.HTTPError {
+	ret := _m.Called(userID, email)
+
+	var r0 httperrors.HTTPError
+	if rf, ok := ret.Get(0).(func(uuid.UUID, string) httperrors.HTTPError); ok {
+		r0 = rf(userID, email)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httperrors.HTTPError)
+		}
+	}
+
+	return r0
+}
+
+// RequestPasswordReset provides a mock function with given fields: email
+func (_m *UserService) RequestPasswordReset(email string) httperrors.HTTPError {
+	ret := _m.Called(email)
+
+	var r0 httperrors.HTTPError
+	if rf, ok := ret.Get(0).(func(string) httperrors.HTTPError); ok {
+		r0 = rf(email)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httperrors.HTTPError)
+		}
+	}
+
+	return r0
+}
+
+// ResendEmailVerification provides a mock function with given fields: email
+func (_m *UserService) ResendEmailVerification(email string) httperrors.HTTPError {
+	ret := _m.Called(email)
+
+	var r0 httperrors.HTTPError
+	if rf, ok := ret.Get(0).(func(string) httperrors.HTTPError); ok {
+		r0 = rf(email)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httperrors.HTTPError)
+		}
+	}
+
+	return r0
+}
+
+// ResetPassword provides a mock function with given fields: token, password
+func (_m *UserService) ResetPassword(token string, password string) httperrors.HTTPError {
+	ret := _m.Called(token, password)
+
+	var r0 httperrors.HTTPError
+	if rf, ok := ret.Get(0).(func(string, string) httperrors.HTTPError); ok {
+		r0 = rf(token, password)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httperrors.HTTPError)
+		}
+	}
+
+	return r0
+}
+
+// SendEmailVerification provides a mock function with given fields: userID
+func (_m *UserService) SendEmailVerification(userID uuid.UUID) httperrors.HTTPError {
+	ret := _m.Called(userID)
+
+	var r0 httperrors.HTTPError
+	if rf, ok := ret.Get(0).(func(uuid.UUID) httperrors.HTTPError); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httperrors.HTTPError)
+		}
+	}
+
+	return r0
+}
+
+// SetDisabled provides a mock function with given fields: userID, disabled
+func (_m *UserService) SetDisabled(userID uuid.UUID, disabled bool) httperrors.HTTPError {
+	ret := _m.Called(userID, disabled)
+
+	var r0 httperrors.HTTPError
+	if rf, ok := ret.Get(0).(func(uuid.UUID, bool) httperrors.HTTPError); ok {
+		r0 = rf(userID, disabled)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httperrors.HTTPError)
+		}
+	}
+
+	return r0
+}
+
+// SetLocale provides a mock function with given fields: userID, locale
+func (_m *UserService) SetLocale(userID uuid.UUID, locale string) httperrors.HTTPError {
+	ret := _m.Called(userID, locale)
+
+	var r0 httperrors.HTTPError
+	if rf, ok := ret.Get(0).(func(uuid.UUID, string) httperrors.HTTPError); ok {
+		r0 = rf(userID, locale)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httperrors.HTTPError)
+		}
+	}
+
+	return r0
+}
+
+// SetPassword provides a mock function with given fields: userID, password
+func (_m *UserService) SetPassword(userID uuid.UUID, password string) httperrors.HTTPError {
+	ret := _m.Called(userID, password)
+
+	var r0 httperrors.HTTPError
+	if rf, ok := ret.Get(0).(func(uuid.UUID, string) httperrors.HTTPError); ok {
+		r0 = rf(userID, password)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httperrors.HTTPError)
+		}
+	}
+
+	return r0
+}
+
+// UpdateUser provides a mock function with given fields: userID, username, email
+func (_m *UserService) UpdateUser(userID uuid.UUID, username string, email string) (*models.User, httperrors.HTTPError) {
+	ret := _m.Called(userID, username, email)
+
+	var r0 *models.User
+	if rf, ok := ret.Get(0).(func(uuid.UUID, string, string) *models.User); ok {
+		r0 = rf(userID, username, email)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.User)
+		}
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func(uuid.UUID, string, string) httperrors.HTTPError); ok {
+		r1 = rf(userID, username, email)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
+// VerifyEmail provides a mock function with given fields: token
+func (_m *UserService) VerifyEmail(token string) (*models.User, httperrors.HTTPError) {
+	ret := _m.Called(token)
+
+	var r0 *models.User
+	if rf, ok := ret.Get(0).(func(string) *models.User); ok {
+		r0 = rf(token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.User)
+		}
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func(string) httperrors.HTTPError); ok {
+		r1 = rf(token)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
 type mockConstructorTestingTNewUserService interface {
 	mock.TestingT
 	Cleanup(func())
@@ -0,0 +1,198 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	httperrors "github.com/ditrit/badaas/httperrors"
+	mock "github.com/stretchr/testify/mock"
+
+	models "github.com/ditrit/badaas/persistence/models"
+
+	uuid "github.com/google/uuid"
+
+	webauthn "github.com/ditrit/badaas/services/auth/protocols/webauthn"
+)
+
+// WebAuthnService is an autogenerated mock type for the WebAuthnService type
+type WebAuthnService struct {
+	mock.Mock
+}
+
+// BeginLogin provides a mock function with given fields: userID
+func (_m *WebAuthnService) BeginLogin(userID *uuid.UUID) (*webauthn.RequestOptions, httperrors.HTTPError) {
+	ret := _m.Called(userID)
+
+	var r0 *webauthn.RequestOptions
+	if rf, ok := ret.Get(0).(func(*uuid.UUID) *webauthn.RequestOptions); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*webauthn.RequestOptions)
+		}
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func(*uuid.UUID) httperrors.HTTPError); ok {
+		r1 = rf(userID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
+// BeginRegistration provides a mock function with given fields: userID
+func (_m *WebAuthnService) BeginRegistration(userID uuid.UUID) (*webauthn.CreationOptions, httperrors.HTTPError) {
+	ret := _m.Called(userID)
+
+	var r0 *webauthn.CreationOptions
+	if rf, ok := ret.Get(0).(func(uuid.UUID) *webauthn.CreationOptions); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*webauthn.CreationOptions)
+		}
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func(uuid.UUID) httperrors.HTTPError); ok {
+		r1 = rf(userID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
+// DeleteCredential provides a mock function with given fields: userID, credentialID
+func (_m *WebAuthnService) DeleteCredential(userID uuid.UUID, credentialID uuid.UUID) httperrors.HTTPError {
+	ret := _m.Called(userID, credentialID)
+
+	var r0 httperrors.HTTPError
+	if rf, ok := ret.Get(0).(func(uuid.UUID, uuid.UUID) httperrors.HTTPError); ok {
+		r0 = rf(userID, credentialID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(httperrors.HTTPError)
+		}
+	}
+
+	return r0
+}
+
+// FinishLogin provides a mock function with given fields: response
+func (_m *WebAuthnService) FinishLogin(response *webauthn.CredentialAssertionResponse) (uuid.UUID, httperrors.HTTPError) {
+	ret := _m.Called(response)
+
+	var r0 uuid.UUID
+	if rf, ok := ret.Get(0).(func(*webauthn.CredentialAssertionResponse) uuid.UUID); ok {
+		r0 = rf(response)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(uuid.UUID)
+		}
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func(*webauthn.CredentialAssertionResponse) httperrors.HTTPError); ok {
+		r1 = rf(response)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
+// FinishRegistration provides a mock function with given fields: userID, name, response
+func (_m *WebAuthnService) FinishRegistration(userID uuid.UUID, name string, response *webauthn.CredentialCreationResponse) (*models.WebAuthnCredential, httperrors.HTTPError) {
+	ret := _m.Called(userID, name, response)
+
+	var r0 *models.WebAuthnCredential
+	if rf, ok := ret.Get(0).(func(uuid.UUID, string, *webauthn.CredentialCreationResponse) *models.WebAuthnCredential); ok {
+		r0 = rf(userID, name, response)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.WebAuthnCredential)
+		}
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func(uuid.UUID, string, *webauthn.CredentialCreationResponse) httperrors.HTTPError); ok {
+		r1 = rf(userID, name, response)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetCredentials provides a mock function with given fields: userID
+func (_m *WebAuthnService) GetCredentials(userID uuid.UUID) ([]*models.WebAuthnCredential, httperrors.HTTPError) {
+	ret := _m.Called(userID)
+
+	var r0 []*models.WebAuthnCredential
+	if rf, ok := ret.Get(0).(func(uuid.UUID) []*models.WebAuthnCredential); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.WebAuthnCredential)
+		}
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func(uuid.UUID) httperrors.HTTPError); ok {
+		r1 = rf(userID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
+// HasCredentials provides a mock function with given fields: userID
+func (_m *WebAuthnService) HasCredentials(userID uuid.UUID) (bool, httperrors.HTTPError) {
+	ret := _m.Called(userID)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(uuid.UUID) bool); ok {
+		r0 = rf(userID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 httperrors.HTTPError
+	if rf, ok := ret.Get(1).(func(uuid.UUID) httperrors.HTTPError); ok {
+		r1 = rf(userID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(httperrors.HTTPError)
+		}
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewWebAuthnService interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewWebAuthnService creates a new instance of WebAuthnService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewWebAuthnService(t mockConstructorTestingTNewWebAuthnService) *WebAuthnService {
+	mock := &WebAuthnService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
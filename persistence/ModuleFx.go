@@ -23,4 +23,35 @@ var PersistanceModule = fx.Module(
 	//repositories
 	fx.Provide(repository.NewCRUDRepository[models.Session, uuid.UUID]),
 	fx.Provide(repository.NewCRUDRepository[models.User, uuid.UUID]),
+	fx.Provide(repository.NewCRUDRepository[models.Role, uuid.UUID]),
+	fx.Provide(repository.NewCRUDRepository[models.RolePermission, uuid.UUID]),
+	fx.Provide(repository.NewCRUDRepository[models.UserRole, uuid.UUID]),
+	fx.Provide(repository.NewCRUDRepository[models.Policy, uuid.UUID]),
+	fx.Provide(repository.NewCRUDRepository[models.Organisation, uuid.UUID]),
+	fx.Provide(repository.NewCRUDRepository[models.Group, uuid.UUID]),
+	fx.Provide(repository.NewCRUDRepository[models.OrganisationMember, uuid.UUID]),
+	fx.Provide(repository.NewCRUDRepository[models.GroupMember, uuid.UUID]),
+	fx.Provide(repository.NewCRUDRepository[models.GroupRole, uuid.UUID]),
+	fx.Provide(repository.NewCRUDRepository[models.EmailChange, uuid.UUID]),
+	fx.Provide(repository.NewCRUDRepository[models.EmailVerificationToken, uuid.UUID]),
+	fx.Provide(repository.NewCRUDRepository[models.PasswordResetToken, uuid.UUID]),
+	fx.Provide(repository.NewCRUDRepository[models.MailMessage, uuid.UUID]),
+	fx.Provide(repository.NewCRUDRepository[models.PasswordHistory, uuid.UUID]),
+	fx.Provide(repository.NewCRUDRepository[models.LoginThrottle, uuid.UUID]),
+	fx.Provide(repository.NewCRUDRepository[models.TOTPDevice, uuid.UUID]),
+	fx.Provide(repository.NewCRUDRepository[models.RecoveryCode, uuid.UUID]),
+	fx.Provide(repository.NewCRUDRepository[models.LoginChallenge, uuid.UUID]),
+	fx.Provide(repository.NewCRUDRepository[models.WebAuthnCredential, uuid.UUID]),
+	fx.Provide(repository.NewCRUDRepository[models.WebAuthnCeremony, uuid.UUID]),
+	fx.Provide(repository.NewCRUDRepository[models.OIDCIdentity, uuid.UUID]),
+	fx.Provide(repository.NewCRUDRepository[models.OIDCAuthRequest, uuid.UUID]),
+	fx.Provide(repository.NewCRUDRepository[models.SAMLIdentity, uuid.UUID]),
+	fx.Provide(repository.NewCRUDRepository[models.SAMLAuthRequest, uuid.UUID]),
+	fx.Provide(repository.NewCRUDRepository[models.SAMLAssertion, uuid.UUID]),
+	fx.Provide(repository.NewCRUDRepository[models.LDAPIdentity, uuid.UUID]),
+	fx.Provide(repository.NewCRUDRepository[models.OAuthClient, uuid.UUID]),
+	fx.Provide(repository.NewCRUDRepository[models.OAuthAuthorizationCode, uuid.UUID]),
+	fx.Provide(repository.NewCRUDRepository[models.OAuthToken, uuid.UUID]),
+	fx.Provide(repository.NewCRUDRepository[models.OAuthConsent, uuid.UUID]),
+	fx.Provide(repository.NewCRUDRepository[models.APIKey, uuid.UUID]),
 )
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Represent a personal access token of a user, used by the scripts and the CI jobs instead of a session
+type APIKey struct {
+	BaseModel
+	UserID uuid.UUID `gorm:"not null;index"`
+	Name   string    `gorm:"not null"`
+	// The first characters of the key, shown to recognize it
+	Prefix  string `gorm:"not null"`
+	KeyHash string `gorm:"not null;uniqueIndex"`
+	// The space separated permissions the key is restricted to
+	Scope string
+	// The key never expires if nil
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+}
+
+// Return the permissions the key is restricted to
+func (apiKey *APIKey) GetScopes() []string {
+	return strings.Fields(apiKey.Scope)
+}
+
+// Return true if the key can't be used anymore
+func (apiKey *APIKey) IsExpired() bool {
+	return apiKey.ExpiresAt != nil && time.Now().After(*apiKey.ExpiresAt)
+}
+
+// Return the pluralized table name
+//
+// Satisfie the Tabler interface
+func (APIKey) TableName() string {
+	return "api_keys"
+}
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Represent a pending change of the email of a user
+//
+// The change is applied once the user confirms it with the token sent to the new email.
+type EmailChange struct {
+	BaseModel
+	UserID    uuid.UUID `gorm:"not null"`
+	Email     string    `gorm:"not null"`
+	TokenHash string    `gorm:"not null"`
+	ExpiresAt time.Time `gorm:"not null"`
+}
+
+// Return true if the email change can't be confirmed anymore
+func (emailChange *EmailChange) IsExpired() bool {
+	return time.Now().After(emailChange.ExpiresAt)
+}
+
+// Return the pluralized table name
+//
+// Satisfie the Tabler interface
+func (EmailChange) TableName() string {
+	return "email_changes"
+}
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Represent a token sent to a user to verify its email
+//
+// A token can only be used once.
+type EmailVerificationToken struct {
+	BaseModel
+	UserID    uuid.UUID `gorm:"not null"`
+	TokenHash string    `gorm:"not null"`
+	ExpiresAt time.Time `gorm:"not null"`
+}
+
+// Return true if the token can't be used anymore
+func (emailVerificationToken *EmailVerificationToken) IsExpired() bool {
+	return time.Now().After(emailVerificationToken.ExpiresAt)
+}
+
+// Return the pluralized table name
+//
+// Satisfie the Tabler interface
+func (EmailVerificationToken) TableName() string {
+	return "email_verification_tokens"
+}
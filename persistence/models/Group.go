@@ -0,0 +1,26 @@
+package models
+
+import "github.com/google/uuid"
+
+// Represents a group of users
+//
+// A group can belong to an organisation and can be nested in a parent group:
+// the members of a group are also members of its ancestors.
+type Group struct {
+	BaseModel
+	Name        string `gorm:"not null"`
+	Description string
+
+	// nil if the group does not belong to an organisation
+	OrganisationID *uuid.UUID `gorm:"index"`
+
+	// nil for a top level group
+	ParentID *uuid.UUID `gorm:"index"`
+}
+
+// Return the pluralized table name
+//
+// Satisfie the Tabler interface
+func (Group) TableName() string {
+	return "groups"
+}
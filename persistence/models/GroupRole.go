@@ -0,0 +1,17 @@
+package models
+
+import "github.com/google/uuid"
+
+// Represents the assignment of a role to a group, the role is granted to every member of the group
+type GroupRole struct {
+	BaseModel
+	GroupID uuid.UUID `gorm:"not null;index"`
+	RoleID  uuid.UUID `gorm:"not null;index"`
+}
+
+// Return the pluralized table name
+//
+// Satisfie the Tabler interface
+func (GroupRole) TableName() string {
+	return "group_roles"
+}
@@ -0,0 +1,18 @@
+package models
+
+import "github.com/google/uuid"
+
+// Represent the entry of a user in the LDAP directory, the user logs in with the password of the directory
+type LDAPIdentity struct {
+	BaseModel
+	UserID uuid.UUID `gorm:"not null;index"`
+	// The distinguished name of the entry on the last login
+	DN string `gorm:"not null;index"`
+}
+
+// Return the pluralized table name
+//
+// Satisfie the Tabler interface
+func (LDAPIdentity) TableName() string {
+	return "ldap_identities"
+}
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Represent a login waiting for its second factor, the password of the user has been checked
+type LoginChallenge struct {
+	BaseModel
+	UserID         uuid.UUID `gorm:"not null"`
+	TokenHash      string    `gorm:"not null"`
+	ExpiresAt      time.Time `gorm:"not null"`
+	FailedAttempts uint      `gorm:"not null;default:0"`
+	// Set if the user has no second factor and has to enable one to complete the login
+	Enrolment bool `gorm:"not null;default:false"`
+}
+
+// Return true if the challenge can't be used anymore
+func (loginChallenge *LoginChallenge) IsExpired() bool {
+	return time.Now().After(loginChallenge.ExpiresAt)
+}
+
+// Return the pluralized table name
+//
+// Satisfie the Tabler interface
+func (LoginChallenge) TableName() string {
+	return "login_challenges"
+}
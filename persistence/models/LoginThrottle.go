@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// Represent the failed login attempts on an account or from an ip address
+//
+// The rows are shared by all the nodes, so that the throttling can't be bypassed by changing of node.
+type LoginThrottle struct {
+	BaseModel
+	// "account:<email>" or "ip:<address>"
+	Key            string `gorm:"unique;not null"`
+	FailedAttempts uint   `gorm:"not null;default:0"`
+	LastFailureAt  time.Time
+	LockedUntil    time.Time
+}
+
+// Return true if no login attempt is allowed until the end of the lockout
+func (loginThrottle *LoginThrottle) IsLocked() bool {
+	return time.Now().Before(loginThrottle.LockedUntil)
+}
+
+// Return the pluralized table name
+//
+// Satisfie the Tabler interface
+func (LoginThrottle) TableName() string {
+	return "login_throttles"
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+)
+
+// The status of a mail in the send queue
+const (
+	MailStatusPending string = "pending"
+	MailStatusSent    string = "sent"
+	MailStatusFailed  string = "failed"
+)
+
+// Represent a mail waiting in the send queue
+//
+// The mails are kept once sent or failed to keep a trace of the outgoing mails,
+// without their text and html since they may hold tokens.
+type MailMessage struct {
+	BaseModel
+	From    string `gorm:"not null"`
+	To      string `gorm:"not null"`
+	Subject string `gorm:"not null"`
+	Text    string `gorm:"not null"`
+	HTML    string
+
+	Status        string    `gorm:"not null;index"`
+	Attempts      uint      `gorm:"not null;default:0"`
+	NextAttemptAt time.Time `gorm:"not null"`
+	LastError     string
+	SentAt        *time.Time
+}
+
+// Return the pluralized table name
+//
+// Satisfie the Tabler interface
+func (MailMessage) TableName() string {
+	return "mail_messages"
+}
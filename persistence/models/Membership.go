@@ -0,0 +1,66 @@
+package models
+
+import "github.com/google/uuid"
+
+// The roles a user can have in a group or in an organisation
+const (
+	// Can manage the members and the administrators
+	MembershipRoleOwner string = "owner"
+	// Can manage the members
+	MembershipRoleAdmin  string = "admin"
+	MembershipRoleMember string = "member"
+)
+
+// Return true if the membership role is valid
+func IsValidMembershipRole(role string) bool {
+	return role == MembershipRoleOwner || role == MembershipRoleAdmin || role == MembershipRoleMember
+}
+
+// Return true if the membership role allows to manage the members
+func CanManageMembers(role string) bool {
+	return MembershipRoleIncludes(role, MembershipRoleAdmin)
+}
+
+// The rank of the membership roles, from the weakest to the strongest
+var membershipRoleRanks = map[string]int{
+	MembershipRoleMember: 1,
+	MembershipRoleAdmin:  2,
+	MembershipRoleOwner:  3,
+}
+
+// Return true if the membership role is at least as strong as the required one
+//
+// An empty role (not a member) never includes a valid role.
+func MembershipRoleIncludes(role, requiredRole string) bool {
+	return membershipRoleRanks[role] > 0 && membershipRoleRanks[role] >= membershipRoleRanks[requiredRole]
+}
+
+// Represents the membership of a user in an organisation
+type OrganisationMember struct {
+	BaseModel
+	OrganisationID uuid.UUID `gorm:"not null;index"`
+	UserID         uuid.UUID `gorm:"not null;index"`
+	Role           string    `gorm:"not null"`
+}
+
+// Return the pluralized table name
+//
+// Satisfie the Tabler interface
+func (OrganisationMember) TableName() string {
+	return "organisation_members"
+}
+
+// Represents the membership of a user in a group
+type GroupMember struct {
+	BaseModel
+	GroupID uuid.UUID `gorm:"not null;index"`
+	UserID  uuid.UUID `gorm:"not null;index"`
+	Role    string    `gorm:"not null"`
+}
+
+// Return the pluralized table name
+//
+// Satisfie the Tabler interface
+func (GroupMember) TableName() string {
+	return "group_members"
+}
@@ -0,0 +1,28 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/ditrit/badaas/persistence/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsValidMembershipRole(t *testing.T) {
+	assert.True(t, models.IsValidMembershipRole(models.MembershipRoleOwner))
+	assert.True(t, models.IsValidMembershipRole(models.MembershipRoleAdmin))
+	assert.True(t, models.IsValidMembershipRole(models.MembershipRoleMember))
+	assert.False(t, models.IsValidMembershipRole("guest"))
+}
+
+func TestCanManageMembers(t *testing.T) {
+	assert.True(t, models.CanManageMembers(models.MembershipRoleOwner))
+	assert.True(t, models.CanManageMembers(models.MembershipRoleAdmin))
+	assert.False(t, models.CanManageMembers(models.MembershipRoleMember))
+}
+
+func TestMembershipRoleIncludes(t *testing.T) {
+	assert.True(t, models.MembershipRoleIncludes(models.MembershipRoleOwner, models.MembershipRoleAdmin))
+	assert.True(t, models.MembershipRoleIncludes(models.MembershipRoleMember, models.MembershipRoleMember))
+	assert.False(t, models.MembershipRoleIncludes(models.MembershipRoleAdmin, models.MembershipRoleOwner))
+	assert.False(t, models.MembershipRoleIncludes("", models.MembershipRoleMember))
+}
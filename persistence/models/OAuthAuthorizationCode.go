@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Represent an authorization code given to a client with the consent of the user
+//
+// A code can only be exchanged once, the tokens of its grant are revoked if it is presented again.
+type OAuthAuthorizationCode struct {
+	BaseModel
+	ClientID uuid.UUID `gorm:"not null"`
+	UserID   uuid.UUID `gorm:"not null"`
+	CodeHash string    `gorm:"not null;index"`
+	// The grant of the tokens issued for the code
+	GrantID     uuid.UUID `gorm:"not null"`
+	RedirectURI string    `gorm:"not null"`
+	// True if the authorization request carried the redirect uri, the token request must then carry the same
+	RedirectURIRequested bool `gorm:"not null;default:false"`
+	Scope                string
+	// The S256 code challenge (RFC 7636), empty if the client didn't send one
+	CodeChallenge string
+	ExpiresAt     time.Time `gorm:"not null"`
+	Used          bool      `gorm:"not null;default:false"`
+}
+
+// Return true if the code can't be exchanged anymore
+func (oauthAuthorizationCode *OAuthAuthorizationCode) IsExpired() bool {
+	return time.Now().After(oauthAuthorizationCode.ExpiresAt)
+}
+
+// Return the pluralized table name
+//
+// Satisfie the Tabler interface
+func (OAuthAuthorizationCode) TableName() string {
+	return "oauth_authorization_codes"
+}
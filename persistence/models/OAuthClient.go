@@ -0,0 +1,44 @@
+package models
+
+import "strings"
+
+// Represent a third-party application registered on the OAuth 2.0 authorization server, its id is the client id
+type OAuthClient struct {
+	BaseModel
+	Name string `gorm:"not null"`
+	// The hash of the secret of the confidential clients, empty for the public clients
+	SecretHash string
+	// The space separated redirect uris of the authorization code grant
+	RedirectURIs string
+	// The space separated grant types the client can use
+	GrantTypes string `gorm:"not null"`
+	// The space separated scopes the client can ask
+	Scope string
+}
+
+// Return true if the client can't keep a secret, like the mobile and the single page applications
+func (oauthClient *OAuthClient) IsPublic() bool {
+	return oauthClient.SecretHash == ""
+}
+
+// Return the registered redirect uris
+func (oauthClient *OAuthClient) GetRedirectURIs() []string {
+	return strings.Fields(oauthClient.RedirectURIs)
+}
+
+// Return true if the client can use the grant type
+func (oauthClient *OAuthClient) AllowsGrantType(grantType string) bool {
+	for _, allowedGrantType := range strings.Fields(oauthClient.GrantTypes) {
+		if allowedGrantType == grantType {
+			return true
+		}
+	}
+	return false
+}
+
+// Return the pluralized table name
+//
+// Satisfie the Tabler interface
+func (OAuthClient) TableName() string {
+	return "oauth_clients"
+}
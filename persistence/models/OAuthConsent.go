@@ -0,0 +1,19 @@
+package models
+
+import "github.com/google/uuid"
+
+// Represent the scopes a user authorized a client to access, the user is not asked again for them
+type OAuthConsent struct {
+	BaseModel
+	UserID   uuid.UUID `gorm:"not null;index"`
+	ClientID uuid.UUID `gorm:"not null"`
+	// The space separated scopes
+	Scope string
+}
+
+// Return the pluralized table name
+//
+// Satisfie the Tabler interface
+func (OAuthConsent) TableName() string {
+	return "oauth_consents"
+}
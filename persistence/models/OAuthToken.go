@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// The types of the OAuth 2.0 tokens
+const (
+	OAuthAccessToken  = "access_token"
+	OAuthRefreshToken = "refresh_token"
+)
+
+// Represent an access or a refresh token issued to an OAuth 2.0 client
+type OAuthToken struct {
+	BaseModel
+	ClientID uuid.UUID `gorm:"not null;index"`
+	// The user who authorized the client, nil for the client credentials grant
+	UserID    *uuid.UUID
+	TokenHash string `gorm:"not null;index"`
+	Type      string `gorm:"not null"`
+	Scope     string
+	// The tokens issued from the same authorization code share the grant, they are revoked together
+	GrantID   uuid.UUID `gorm:"not null;index"`
+	ExpiresAt time.Time `gorm:"not null"`
+	// Set when a refresh token is exchanged, presenting it again revokes the grant
+	RotatedAt *time.Time
+}
+
+// Return true if the token is expired
+func (oauthToken *OAuthToken) IsExpired() bool {
+	return time.Now().After(oauthToken.ExpiresAt)
+}
+
+// Return the pluralized table name
+//
+// Satisfie the Tabler interface
+func (OAuthToken) TableName() string {
+	return "oauth_tokens"
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// Represent a login on an OpenID provider waiting for its callback, identified by the hash of its state
+type OIDCAuthRequest struct {
+	BaseModel
+	Provider string `gorm:"not null"`
+	// The hex encoded SHA-256 hash of the state
+	StateHash    string    `gorm:"not null;index"`
+	Nonce        string    `gorm:"not null"`
+	CodeVerifier string    `gorm:"not null"`
+	ExpiresAt    time.Time `gorm:"not null"`
+}
+
+// Return true if the login can't be completed anymore
+func (oidcAuthRequest *OIDCAuthRequest) IsExpired() bool {
+	return time.Now().After(oidcAuthRequest.ExpiresAt)
+}
+
+// Return the pluralized table name
+//
+// Satisfie the Tabler interface
+func (OIDCAuthRequest) TableName() string {
+	return "oidc_auth_requests"
+}
@@ -0,0 +1,22 @@
+package models
+
+import "github.com/google/uuid"
+
+// Represent the identity of a user on an OpenID provider, the user can log in with it
+type OIDCIdentity struct {
+	BaseModel
+	UserID uuid.UUID `gorm:"not null"`
+	// The name of the provider in the configuration
+	Provider string `gorm:"not null;index:idx_oidc_identity"`
+	// The subject of the ID tokens, unique for a provider
+	Subject string `gorm:"not null;index:idx_oidc_identity"`
+	// The email given by the provider on the last login
+	Email string
+}
+
+// Return the pluralized table name
+//
+// Satisfie the Tabler interface
+func (OIDCIdentity) TableName() string {
+	return "oidc_identities"
+}
@@ -0,0 +1,15 @@
+package models
+
+// Represents an organisation, a set of users and groups
+type Organisation struct {
+	BaseModel
+	Name        string `gorm:"unique;not null"`
+	Description string
+}
+
+// Return the pluralized table name
+//
+// Satisfie the Tabler interface
+func (Organisation) TableName() string {
+	return "organisations"
+}
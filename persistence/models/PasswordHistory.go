@@ -0,0 +1,21 @@
+package models
+
+import (
+	"github.com/google/uuid"
+)
+
+// Represent a previous password of a user, kept to prevent its reuse
+type PasswordHistory struct {
+	BaseModel
+	UserID uuid.UUID `gorm:"not null;index"`
+
+	// password hash
+	Password []byte `gorm:"not null"`
+}
+
+// Return the pluralized table name
+//
+// Satisfie the Tabler interface
+func (PasswordHistory) TableName() string {
+	return "password_histories"
+}
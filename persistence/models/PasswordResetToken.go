@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Represent a token sent to a user to reset its password
+//
+// A token can only be used once.
+type PasswordResetToken struct {
+	BaseModel
+	UserID    uuid.UUID `gorm:"not null"`
+	TokenHash string    `gorm:"not null"`
+	ExpiresAt time.Time `gorm:"not null"`
+}
+
+// Return true if the token can't be used anymore
+func (passwordResetToken *PasswordResetToken) IsExpired() bool {
+	return time.Now().After(passwordResetToken.ExpiresAt)
+}
+
+// Return the pluralized table name
+//
+// Satisfie the Tabler interface
+func (PasswordResetToken) TableName() string {
+	return "password_reset_tokens"
+}
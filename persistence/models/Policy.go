@@ -0,0 +1,58 @@
+package models
+
+import "strings"
+
+// The effects a policy can have
+const (
+	PolicyEffectAllow string = "allow"
+	PolicyEffectDeny  string = "deny"
+)
+
+// Represents an attribute based access control policy
+//
+// A policy applies to a request if the action and the resource type of the request match
+// one of the patterns of the policy and if its condition evaluates to true.
+type Policy struct {
+	BaseModel
+	Name        string `gorm:"unique;not null"`
+	Description string
+	Effect      string `gorm:"not null"`
+
+	// comma separated list of action patterns (ex: "posts:update,posts:delete")
+	Actions string `gorm:"not null"`
+
+	// comma separated list of resource type patterns (ex: "post")
+	Resources string `gorm:"not null"`
+
+	// jinja expression evaluated against the request attributes
+	Condition string
+}
+
+// Return the action patterns of the policy
+func (policy *Policy) GetActions() []string {
+	return splitList(policy.Actions)
+}
+
+// Return the resource type patterns of the policy
+func (policy *Policy) GetResources() []string {
+	return splitList(policy.Resources)
+}
+
+// Return the pluralized table name
+//
+// Satisfie the Tabler interface
+func (Policy) TableName() string {
+	return "policies"
+}
+
+// Split a comma separated list, ignoring empty elements
+func splitList(list string) []string {
+	elements := []string{}
+	for _, element := range strings.Split(list, ",") {
+		element = strings.TrimSpace(element)
+		if element != "" {
+			elements = append(elements, element)
+		}
+	}
+	return elements
+}
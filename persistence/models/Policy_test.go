@@ -0,0 +1,20 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/ditrit/badaas/persistence/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolicyGetActions(t *testing.T) {
+	policy := &models.Policy{Actions: "posts:update, posts:delete,,"}
+	assert.Equal(t, []string{"posts:update", "posts:delete"}, policy.GetActions())
+}
+
+func TestPolicyGetResources(t *testing.T) {
+	policy := &models.Policy{Resources: ""}
+	assert.Empty(t, policy.GetResources())
+	policy.Resources = "post"
+	assert.Equal(t, []string{"post"}, policy.GetResources())
+}
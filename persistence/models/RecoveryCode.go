@@ -0,0 +1,19 @@
+package models
+
+import "github.com/google/uuid"
+
+// Represent a recovery code, replacing the TOTP code when the user lost its device
+//
+// A code can only be used once.
+type RecoveryCode struct {
+	BaseModel
+	UserID   uuid.UUID `gorm:"not null"`
+	CodeHash string    `gorm:"not null"`
+}
+
+// Return the pluralized table name
+//
+// Satisfie the Tabler interface
+func (RecoveryCode) TableName() string {
+	return "recovery_codes"
+}
@@ -0,0 +1,15 @@
+package models
+
+// Represents a role, a named set of permissions that can be assigned to users
+type Role struct {
+	BaseModel
+	Name        string `gorm:"unique;not null"`
+	Description string
+}
+
+// Return the pluralized table name
+//
+// Satisfie the Tabler interface
+func (Role) TableName() string {
+	return "roles"
+}
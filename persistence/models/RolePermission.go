@@ -0,0 +1,17 @@
+package models
+
+import "github.com/google/uuid"
+
+// Represents a permission granted to a role
+type RolePermission struct {
+	BaseModel
+	RoleID     uuid.UUID `gorm:"not null;index"`
+	Permission string    `gorm:"not null;index"`
+}
+
+// Return the pluralized table name
+//
+// Satisfie the Tabler interface
+func (RolePermission) TableName() string {
+	return "role_permissions"
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// Represent an assertion of the SAML identity provider already used to log in,
+// kept until it expires so that it can't be replayed
+type SAMLAssertion struct {
+	BaseModel
+	// The id given to the assertion by the identity provider
+	AssertionID string    `gorm:"unique;not null"`
+	ExpiresAt   time.Time `gorm:"not null"`
+}
+
+// Return the pluralized table name
+//
+// Satisfie the Tabler interface
+func (SAMLAssertion) TableName() string {
+	return "saml_assertions"
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// Represent an AuthnRequest sent to the SAML identity provider, waiting for its response
+type SAMLAuthRequest struct {
+	BaseModel
+	// The id of the AuthnRequest, given back in the response
+	RequestID string    `gorm:"not null;index"`
+	ExpiresAt time.Time `gorm:"not null"`
+}
+
+// Return true if the response can't be accepted anymore
+func (samlAuthRequest *SAMLAuthRequest) IsExpired() bool {
+	return time.Now().After(samlAuthRequest.ExpiresAt)
+}
+
+// Return the pluralized table name
+//
+// Satisfie the Tabler interface
+func (SAMLAuthRequest) TableName() string {
+	return "saml_auth_requests"
+}
@@ -0,0 +1,20 @@
+package models
+
+import "github.com/google/uuid"
+
+// Represent the identity of a user on the SAML identity provider, the user can log in with it
+type SAMLIdentity struct {
+	BaseModel
+	UserID uuid.UUID `gorm:"not null"`
+	// The name id asserted by the identity provider
+	NameID string `gorm:"not null;index"`
+	// The email asserted on the last login
+	Email string
+}
+
+// Return the pluralized table name
+//
+// Satisfie the Tabler interface
+func (SAMLIdentity) TableName() string {
+	return "saml_identities"
+}
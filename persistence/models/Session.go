@@ -11,6 +11,17 @@ type Session struct {
 	BaseModel
 	UserID    uuid.UUID `gorm:"not null"`
 	ExpiresAt time.Time `gorm:"not null"`
+	// The hash of the token of the session: the session token, or the refresh token in the jwt session mode
+	TokenHash string `gorm:"index"`
+	// The client that created the session
+	IPAddress string
+	UserAgent string
+	// The browser and operating system parsed from the user agent, ex: "Firefox on Linux"
+	Device string
+	// The hash of the client the session is bound to, empty if the session is not bound
+	Fingerprint string
+	// The last request made with the session, recorded at most once a minute
+	LastSeenAt time.Time
 }
 
 // Return true is expired
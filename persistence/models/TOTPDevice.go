@@ -0,0 +1,23 @@
+package models
+
+import "github.com/google/uuid"
+
+// Represent the TOTP authenticator of a user
+//
+// A user has at most one device, it is only used as a second factor once confirmed.
+type TOTPDevice struct {
+	BaseModel
+	UserID uuid.UUID `gorm:"not null"`
+	// The base32 secret shared with the authenticator app
+	Secret    string `gorm:"not null"`
+	Confirmed bool   `gorm:"not null;default:false"`
+	// The time step of the last accepted code, the codes can't be replayed
+	LastUsedStep int64
+}
+
+// Return the pluralized table name
+//
+// Satisfie the Tabler interface
+func (TOTPDevice) TableName() string {
+	return "totp_devices"
+}
@@ -3,6 +3,37 @@ package models
 var ListOfTables = []any{
 	User{},
 	Session{},
+	Role{},
+	RolePermission{},
+	UserRole{},
+	Policy{},
+	Organisation{},
+	Group{},
+	OrganisationMember{},
+	GroupMember{},
+	GroupRole{},
+	EmailChange{},
+	EmailVerificationToken{},
+	PasswordResetToken{},
+	MailMessage{},
+	PasswordHistory{},
+	LoginThrottle{},
+	TOTPDevice{},
+	RecoveryCode{},
+	LoginChallenge{},
+	WebAuthnCredential{},
+	WebAuthnCeremony{},
+	OIDCIdentity{},
+	OIDCAuthRequest{},
+	SAMLIdentity{},
+	SAMLAuthRequest{},
+	SAMLAssertion{},
+	LDAPIdentity{},
+	OAuthClient{},
+	OAuthAuthorizationCode{},
+	OAuthToken{},
+	OAuthConsent{},
+	APIKey{},
 }
 
 // The interface "type" need to implement to be considered models
@@ -8,6 +8,15 @@ type User struct {
 
 	// password hash
 	Password []byte `gorm:"not null"`
+
+	// a disabled user can't log in
+	Disabled bool `gorm:"not null;default:false"`
+
+	// true once the user proved it owns the email
+	EmailVerified bool `gorm:"not null;default:false"`
+
+	// the locale of the mails sent to the user, the default one if empty
+	Locale string
 }
 
 // Return the pluralized table name
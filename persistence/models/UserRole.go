@@ -0,0 +1,17 @@
+package models
+
+import "github.com/google/uuid"
+
+// Represents the assignment of a role to a user
+type UserRole struct {
+	BaseModel
+	UserID uuid.UUID `gorm:"not null;index"`
+	RoleID uuid.UUID `gorm:"not null;index"`
+}
+
+// Return the pluralized table name
+//
+// Satisfie the Tabler interface
+func (UserRole) TableName() string {
+	return "user_roles"
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// The kinds of WebAuthn ceremonies
+const (
+	WebAuthnRegistration = "registration"
+	WebAuthnLogin        = "login"
+)
+
+// Represent a WebAuthn ceremony waiting for the response of the authenticator, identified by its challenge
+type WebAuthnCeremony struct {
+	BaseModel
+	// Empty for a login with a passkey, the user is given by the credential
+	UserID *uuid.UUID
+	// The base64url encoded challenge
+	Challenge string    `gorm:"not null;index"`
+	Kind      string    `gorm:"not null"`
+	ExpiresAt time.Time `gorm:"not null"`
+}
+
+// Return true if the ceremony can't be completed anymore
+func (webAuthnCeremony *WebAuthnCeremony) IsExpired() bool {
+	return time.Now().After(webAuthnCeremony.ExpiresAt)
+}
+
+// Return the pluralized table name
+//
+// Satisfie the Tabler interface
+func (WebAuthnCeremony) TableName() string {
+	return "webauthn_ceremonies"
+}
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Represent a WebAuthn credential of a user, a passkey or a security key
+type WebAuthnCredential struct {
+	BaseModel
+	UserID       uuid.UUID `gorm:"not null"`
+	CredentialID []byte    `gorm:"not null;index"`
+	// The COSE encoded public key
+	PublicKey []byte `gorm:"not null"`
+	SignCount uint32 `gorm:"not null;default:0"`
+	AAGUID    []byte
+	// The comma separated transports reported by the authenticator, given back to the browsers
+	Transports string
+	Name       string `gorm:"not null"`
+	LastUsedAt *time.Time
+}
+
+// Return the pluralized table name
+//
+// Satisfie the Tabler interface
+func (WebAuthnCredential) TableName() string {
+	return "webauthn_credentials"
+}
@@ -0,0 +1,24 @@
+package dto
+
+import "time"
+
+// Describe an API key, the key itself is only returned on creation
+type DTOAPIKey struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	ExpiresAt  *time.Time `json:"expiresAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt"`
+	Key        string     `json:"key,omitempty"`
+}
+
+// API key creation DTO
+type DTOCreateAPIKey struct {
+	Name string `json:"name"`
+	// The permissions the key is restricted to, "*" for all the permissions of the user
+	Scopes []string `json:"scopes"`
+	// The key never expires if omitted
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
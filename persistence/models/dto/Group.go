@@ -0,0 +1,46 @@
+package dto
+
+// Data Transfert Object Package
+
+// Describe an organisation
+type DTOOrganisation struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// Organisation creation DTO
+type DTOCreateOrganisation struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// Describe a group
+type DTOGroup struct {
+	ID             string `json:"id"`
+	Name           string `json:"name"`
+	Description    string `json:"description"`
+	OrganisationID string `json:"organisationId,omitempty"`
+	ParentID       string `json:"parentId,omitempty"`
+}
+
+// Group creation DTO
+type DTOCreateGroup struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// optional
+	OrganisationID string `json:"organisationId"`
+	// optional
+	ParentID string `json:"parentId"`
+}
+
+// Group move DTO, an empty parent id moves the group at the top level
+type DTOSetGroupParent struct {
+	ParentID string `json:"parentId"`
+}
+
+// Describe the membership of a user in a group or an organisation
+type DTOMember struct {
+	UserID string `json:"userId"`
+	Role   string `json:"role"`
+}
@@ -7,4 +7,6 @@ type DTOHTTPError struct {
 	Error   string `json:"err"`
 	Message string `json:"msg"`
 	Status  string `json:"status"`
+	// The validation errors of the fields of the request, by field name
+	Fields map[string]string `json:"fields,omitempty"`
 }
@@ -5,4 +5,4 @@ type DTOLoginSuccess struct {
 	Email    string `json:"email"`
 	ID       string `json:"id"`
 	Username string `json:"username"`
-}
\ No newline at end of file
+}
@@ -0,0 +1,96 @@
+package dto
+
+// Describe an OAuth 2.0 client, the secret is only returned on creation
+type DTOOAuthClient struct {
+	// The client id
+	ID           string   `json:"id"`
+	Name         string   `json:"name"`
+	Public       bool     `json:"public"`
+	RedirectURIs []string `json:"redirectUris"`
+	GrantTypes   []string `json:"grantTypes"`
+	Scopes       []string `json:"scopes"`
+	ClientSecret string   `json:"clientSecret,omitempty"`
+}
+
+// OAuth 2.0 client creation DTO
+type DTOCreateOAuthClient struct {
+	Name string `json:"name"`
+	// A public client has no secret, it must use PKCE
+	Public       bool     `json:"public"`
+	RedirectURIs []string `json:"redirectUris"`
+	GrantTypes   []string `json:"grantTypes"`
+	Scopes       []string `json:"scopes"`
+}
+
+// The parameters of an authorization request (RFC 6749 section 4.1.1 and RFC 7636 section 4.3)
+type DTOOAuthAuthorizationRequest struct {
+	ResponseType        string `json:"response_type"`
+	ClientID            string `json:"client_id"`
+	RedirectURI         string `json:"redirect_uri,omitempty"`
+	Scope               string `json:"scope,omitempty"`
+	State               string `json:"state,omitempty"`
+	CodeChallenge       string `json:"code_challenge,omitempty"`
+	CodeChallengeMethod string `json:"code_challenge_method,omitempty"`
+}
+
+// A scope shown on the consent screen
+type DTOOAuthScope struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// The consent asked to the user, the request is posted back with the decision of the user
+type DTOOAuthConsent struct {
+	ClientID   string                       `json:"clientId"`
+	ClientName string                       `json:"clientName"`
+	Scopes     []DTOOAuthScope              `json:"scopes"`
+	Request    DTOOAuthAuthorizationRequest `json:"request"`
+}
+
+// The decision of the user on the consent screen
+type DTOOAuthConsentDecision struct {
+	Request  DTOOAuthAuthorizationRequest `json:"request"`
+	Approved bool                         `json:"approved"`
+}
+
+// The url the user agent is redirected to at the end of the authorization
+type DTOOAuthRedirect struct {
+	RedirectTo string `json:"redirectTo"`
+}
+
+// The parameters of a request to the token endpoint (RFC 6749 sections 4.1.3, 4.4.2 and 6)
+type DTOOAuthTokenRequest struct {
+	GrantType    string
+	Code         string
+	RedirectURI  string
+	CodeVerifier string
+	RefreshToken string
+	Scope        string
+}
+
+// The response of the token endpoint (RFC 6749 section 5.1)
+type DTOOAuthToken struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	Scope        string `json:"scope,omitempty"`
+}
+
+// The error of the token endpoint (RFC 6749 section 5.2)
+type DTOOAuthError struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description,omitempty"`
+}
+
+// The response of the introspection endpoint (RFC 7662 section 2.2), only active is set for the inactive tokens
+type DTOOAuthIntrospection struct {
+	Active    bool   `json:"active"`
+	Scope     string `json:"scope,omitempty"`
+	ClientID  string `json:"client_id,omitempty"`
+	Username  string `json:"username,omitempty"`
+	TokenType string `json:"token_type,omitempty"`
+	ExpiresAt int64  `json:"exp,omitempty"`
+	IssuedAt  int64  `json:"iat,omitempty"`
+	Subject   string `json:"sub,omitempty"`
+}
@@ -0,0 +1,6 @@
+package dto
+
+// The names of the OpenID providers the users can log in with
+type DTOOIDCProviders struct {
+	Providers []string `json:"providers"`
+}
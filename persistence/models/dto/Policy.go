@@ -0,0 +1,25 @@
+package dto
+
+// Data Transfert Object Package
+
+// Describe a policy
+type DTOPolicy struct {
+	ID          string   `json:"id,omitempty"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Effect      string   `json:"effect"`
+	Actions     []string `json:"actions"`
+	Resources   []string `json:"resources"`
+	Condition   string   `json:"condition"`
+	Source      string   `json:"source"`
+}
+
+// Policy creation DTO
+type DTOCreatePolicy struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Effect      string   `json:"effect"`
+	Actions     []string `json:"actions"`
+	Resources   []string `json:"resources"`
+	Condition   string   `json:"condition"`
+}
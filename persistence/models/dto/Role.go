@@ -0,0 +1,27 @@
+package dto
+
+// Data Transfert Object Package
+
+// Describe a role
+type DTORole struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions,omitempty"`
+}
+
+// Role creation DTO
+type DTOCreateRole struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// Permission grant DTO
+type DTOGrantPermission struct {
+	Permission string `json:"permission"`
+}
+
+// Role assignment DTO
+type DTOAssignRole struct {
+	RoleID string `json:"roleId"`
+}
@@ -0,0 +1,18 @@
+package dto
+
+import "time"
+
+// Describe a session of a user
+type DTOSession struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	// The client that created the session
+	IPAddress string `json:"ipAddress"`
+	UserAgent string `json:"userAgent"`
+	Device    string `json:"device"`
+	// The last request made with the session, recorded at most once a minute
+	LastSeenAt time.Time `json:"lastSeenAt"`
+	// True for the session of the request
+	Current bool `json:"current"`
+}
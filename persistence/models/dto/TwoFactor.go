@@ -0,0 +1,52 @@
+package dto
+
+// Returned by the login instead of DTOLoginSuccess when a second factor is needed
+//
+// The challenge has to be sent with the code or the WebAuthn assertion, or with the enrolment of an authenticator
+// if the roles of the user require a second factor that is not enabled yet.
+// The methods are the second factors available to the user: "totp" and "webauthn".
+type DTOLoginChallenge struct {
+	TwoFactorRequired bool     `json:"twoFactorRequired"`
+	EnrolmentRequired bool     `json:"enrolmentRequired"`
+	Methods           []string `json:"methods"`
+	Challenge         string   `json:"challenge"`
+}
+
+// Second step of the login DTO, the code is a TOTP code or a recovery code
+type DTOLoginCode struct {
+	Challenge string `json:"challenge"`
+	Code      string `json:"code"`
+}
+
+// Enrolment of an authenticator during the login DTO
+type DTOLoginEnrolment struct {
+	Challenge string `json:"challenge"`
+}
+
+// Returned when the enrolment during the login is confirmed, the recovery codes are only shown once
+type DTOLoginEnrolmentSuccess struct {
+	DTOLoginSuccess
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
+// A TOTP code or a recovery code of the current user
+type DTOTwoFactorCode struct {
+	Code string `json:"code"`
+}
+
+// Describe a new TOTP authenticator, the uri is meant to be displayed as a QR code
+type DTOTOTPEnrolment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+// Recovery codes, they are only shown once
+type DTORecoveryCodes struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
+// Describe the two-factor authentication of a user
+type DTOTwoFactorStatus struct {
+	Enabled  bool `json:"enabled"`
+	Required bool `json:"required"`
+}
@@ -1,5 +1,7 @@
 package dto
 
+import "time"
+
 // Data Transfert Object Package
 
 // Login DTO
@@ -7,3 +9,87 @@ type UserLoginDTO struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
 }
+
+// Describe a user
+type DTOUser struct {
+	ID            string    `json:"id"`
+	Username      string    `json:"username"`
+	Email         string    `json:"email"`
+	Disabled      bool      `json:"disabled"`
+	EmailVerified bool      `json:"emailVerified"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
+// A page of users
+type DTOUserPage struct {
+	Users       []DTOUser `json:"users"`
+	Page        uint      `json:"page"`
+	Limit       uint      `json:"limit"`
+	Total       uint      `json:"total"`
+	HasNextPage bool      `json:"hasNextPage"`
+}
+
+// User creation DTO
+type DTOCreateUser struct {
+	Username string `json:"username"`
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// User update DTO, empty fields are left unchanged
+type DTOUpdateUser struct {
+	Username string `json:"username"`
+	Email    string `json:"email"`
+}
+
+// Password change DTO
+type DTOSetPassword struct {
+	Password string `json:"password"`
+}
+
+// Password change DTO of the current user
+type DTOChangePassword struct {
+	CurrentPassword string `json:"currentPassword"`
+	NewPassword     string `json:"newPassword"`
+}
+
+// Email change DTO of the current user
+type DTOChangeEmail struct {
+	Email string `json:"email"`
+}
+
+// Email change confirmation DTO
+type DTOConfirmEmailChange struct {
+	Token string `json:"token"`
+}
+
+// Self-service registration DTO
+type DTORegister struct {
+	Username       string `json:"username"`
+	Email          string `json:"email"`
+	Password       string `json:"password"`
+	InvitationCode string `json:"invitationCode"`
+	// optional, the locale of the mails sent to the user
+	Locale string `json:"locale"`
+}
+
+// Email verification DTO
+type DTOVerifyEmail struct {
+	Token string `json:"token"`
+}
+
+// Verification email resend DTO
+type DTOResendEmailVerification struct {
+	Email string `json:"email"`
+}
+
+// Forgotten password DTO
+type DTOForgotPassword struct {
+	Email string `json:"email"`
+}
+
+// Password reset DTO
+type DTOResetPassword struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
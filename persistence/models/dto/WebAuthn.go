@@ -0,0 +1,47 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/ditrit/badaas/services/auth/protocols/webauthn"
+)
+
+// The options of navigator.credentials.create, to be given as they are to the browser
+type DTOWebAuthnCreationOptions struct {
+	PublicKey *webauthn.CreationOptions `json:"publicKey"`
+}
+
+// The options of navigator.credentials.get, to be given as they are to the browser
+type DTOWebAuthnRequestOptions struct {
+	PublicKey *webauthn.RequestOptions `json:"publicKey"`
+}
+
+// Registration of a WebAuthn credential DTO, the name helps the user to recognize the credential
+type DTOWebAuthnRegistration struct {
+	Name       string                              `json:"name"`
+	Credential webauthn.CredentialCreationResponse `json:"credential"`
+}
+
+// Login with a passkey DTO
+type DTOWebAuthnLogin struct {
+	Credential webauthn.CredentialAssertionResponse `json:"credential"`
+}
+
+// Start of the second step of the login with a WebAuthn credential DTO
+type DTOLoginWebAuthnOptions struct {
+	Challenge string `json:"challenge"`
+}
+
+// Second step of the login with a WebAuthn credential DTO
+type DTOLoginWebAuthn struct {
+	Challenge  string                               `json:"challenge"`
+	Credential webauthn.CredentialAssertionResponse `json:"credential"`
+}
+
+// Describe a WebAuthn credential
+type DTOWebAuthnCredential struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt"`
+}
@@ -12,9 +12,22 @@ type CRUDRepository[T models.Tabler, ID any] interface {
 	Create(*T) httperrors.HTTPError
 	Delete(*T) httperrors.HTTPError
 	Save(*T) httperrors.HTTPError
+	// Delete the entities of other models matching the filters, so that the dependents of an entity
+	// can be deleted in its transaction
+	DeleteDependents(filters squirrel.Sqlizer, dependentModels []models.Tabler) httperrors.HTTPError
+	// Create an entity, or assign the columns of the entity it conflicts with on the conflict columns,
+	// the entity is then set to the stored row. The assigned values can be squirrel expressions.
+	Upsert(entity *T, conflictColumns []string, assignments map[string]any) httperrors.HTTPError
+	// Set the columns of the entities matching the filters, without the hooks nor the update time,
+	// return the number of entities updated
+	UpdateColumns(squirrel.Sqlizer, map[string]any) (uint, httperrors.HTTPError)
 	GetByID(ID) (*T, httperrors.HTTPError)
 	GetAll(SortOption) ([]*T, httperrors.HTTPError)
 	Count(squirrel.Sqlizer) (uint, httperrors.HTTPError)
 	Find(squirrel.Sqlizer, pagination.Paginator, SortOption) (*pagination.Page[T], httperrors.HTTPError)
+	// Find the entities matching the filters, including the soft deleted ones
+	FindUnscoped(squirrel.Sqlizer) ([]*T, httperrors.HTTPError)
+	// Delete permanently the entities matching the filters, return the number of entities deleted
+	DeleteUnscoped(squirrel.Sqlizer) (uint, httperrors.HTTPError)
 	Transaction(fn func(CRUDRepository[T, ID]) (any, error)) (any, httperrors.HTTPError)
 }
@@ -110,6 +110,75 @@ func (repository *CRUDRepositoryImpl[T, ID]) Save(entity *T) httperrors.HTTPErro
 	return nil
 }
 
+// Delete the entities of other models matching the filters
+//
+// The dependents are deleted with the database of the repository, so in its transaction if it has one.
+func (repository *CRUDRepositoryImpl[T, ID]) DeleteDependents(
+	filters squirrel.Sqlizer,
+	dependentModels []models.Tabler,
+) httperrors.HTTPError {
+	whereClause, values, httpError := repository.compileSQL(filters)
+	if httpError != nil {
+		return httpError
+	}
+	for _, dependentModel := range dependentModels {
+		err := repository.gormDatabase.Where(whereClause, values...).Delete(dependentModel).Error
+		if err != nil {
+			return DatabaseError(fmt.Sprintf("could not delete the %s with condition %q", dependentModel.TableName(), whereClause), err)
+		}
+	}
+	return nil
+}
+
+// Create an entity of a Model, or assign the columns of the entity it conflicts with on the conflict columns
+//
+// The assignments are done by the database, so the squirrel expressions can use the values of the stored row
+// and the concurrent upserts are not lost. The entity is set to the stored row.
+func (repository *CRUDRepositoryImpl[T, ID]) Upsert(entity *T, conflictColumns []string, assignments map[string]any) httperrors.HTTPError {
+	columns := make([]clause.Column, 0, len(conflictColumns))
+	for _, conflictColumn := range conflictColumns {
+		columns = append(columns, clause.Column{Name: conflictColumn})
+	}
+	values := make(map[string]any, len(assignments))
+	for column, value := range assignments {
+		if expression, ok := value.(squirrel.Sqlizer); ok {
+			sql, args, err := expression.ToSql()
+			if err != nil {
+				return DatabaseError(fmt.Sprintf("could not compile the assignment of %q", column), err)
+			}
+			value = gorm.Expr(sql, args...)
+		}
+		values[column] = value
+	}
+	err := repository.gormDatabase.Clauses(
+		clause.OnConflict{Columns: columns, DoUpdates: clause.Assignments(values)},
+		clause.Returning{},
+	).Create(entity).Error
+	if err != nil {
+		return DatabaseError(fmt.Sprintf("could not upsert %T", entity), err)
+	}
+	return nil
+}
+
+// Set the columns of the entities of a Model matching the filters, return the number of entities updated
+//
+// Only the given columns are written, so that the concurrent changes of the other columns are kept.
+func (repository *CRUDRepositoryImpl[T, ID]) UpdateColumns(filters squirrel.Sqlizer, columns map[string]any) (uint, httperrors.HTTPError) {
+	whereClause, values, httpError := repository.compileSQL(filters)
+	if httpError != nil {
+		return 0, httpError
+	}
+	transaction := repository.gormDatabase.Model(new(T)).Where(whereClause, values...).UpdateColumns(columns)
+	if transaction.Error != nil {
+		var emptyInstanceForError T
+		return 0, DatabaseError(
+			fmt.Sprintf("could not update %s with condition %q", emptyInstanceForError.TableName(), whereClause),
+			transaction.Error,
+		)
+	}
+	return uint(transaction.RowsAffected), nil
+}
+
 // Get an entity of a Model By ID
 func (repository *CRUDRepositoryImpl[T, ID]) GetByID(id ID) (*T, httperrors.HTTPError) {
 	var entity T
@@ -159,7 +228,7 @@ func (repository *CRUDRepositoryImpl[T, ID]) Count(filters squirrel.Sqlizer) (ui
 func (repository *CRUDRepositoryImpl[T, ID]) count(whereClause string, values []interface{}) (uint, httperrors.HTTPError) {
 	var entity *T
 	var count int64
-	transaction := repository.gormDatabase.Model(entity).Where(whereClause, values).Count(&count)
+	transaction := repository.gormDatabase.Model(entity).Where(whereClause, values...).Count(&count)
 	if transaction.Error != nil {
 		var emptyInstanceForError T
 		return 0, DatabaseError(
@@ -226,6 +295,43 @@ func (repository *CRUDRepositoryImpl[T, ID]) Find(
 	return pagination.NewPage(instances, page.Offset(), page.Limit(), nbElem), nil
 }
 
+// Find the entities of a Model matching the filters, including the soft deleted ones
+func (repository *CRUDRepositoryImpl[T, ID]) FindUnscoped(filters squirrel.Sqlizer) ([]*T, httperrors.HTTPError) {
+	whereClause, values, httpError := repository.compileSQL(filters)
+	if httpError != nil {
+		return nil, httpError
+	}
+	var instances []*T
+	err := repository.gormDatabase.Unscoped().Where(whereClause, values...).Find(&instances).Error
+	if err != nil {
+		var emptyInstanceForError T
+		return nil, DatabaseError(
+			fmt.Sprintf("could not get data from %s with condition %q", emptyInstanceForError.TableName(), whereClause),
+			err,
+		)
+	}
+	return instances, nil
+}
+
+// Delete permanently the entities of a Model matching the filters, return the number of entities deleted
+//
+// The entities are not soft deleted, so that the rows of the purged entities don't stay in the table.
+func (repository *CRUDRepositoryImpl[T, ID]) DeleteUnscoped(filters squirrel.Sqlizer) (uint, httperrors.HTTPError) {
+	whereClause, values, httpError := repository.compileSQL(filters)
+	if httpError != nil {
+		return 0, httpError
+	}
+	transaction := repository.gormDatabase.Unscoped().Where(whereClause, values...).Delete(new(T))
+	if transaction.Error != nil {
+		var emptyInstanceForError T
+		return 0, DatabaseError(
+			fmt.Sprintf("could not delete %s with condition %q", emptyInstanceForError.TableName(), whereClause),
+			transaction.Error,
+		)
+	}
+	return uint(transaction.RowsAffected), nil
+}
+
 // compile the sql where clause
 func (repository *CRUDRepositoryImpl[T, ID]) compileSQL(filters squirrel.Sqlizer) (string, []interface{}, httperrors.HTTPError) {
 	compiledSQLString, values, err := filters.ToSql()
@@ -5,9 +5,13 @@ import (
 
 	"github.com/Masterminds/squirrel"
 	mocks "github.com/ditrit/badaas/mocks/configuration"
+	"github.com/ditrit/badaas/persistence/models"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
 )
 
 func TestDatabaseError(t *testing.T) {
@@ -50,3 +54,59 @@ func TestCompileSql_Err(t *testing.T) {
 
 	assert.Error(t, err)
 }
+
+type upsertModel struct {
+	models.BaseModel
+	Key     string `gorm:"unique"`
+	Counter uint
+}
+
+func (upsertModel) TableName() string {
+	return "upsert_models"
+}
+
+// Open a database building the sql statements without running them
+func openDryRunDatabase(t *testing.T) *gorm.DB {
+	database, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+		DryRun:                 true,
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true,
+	})
+	require.NoError(t, err)
+	return database
+}
+
+func TestUpsert(t *testing.T) {
+	database := openDryRunDatabase(t)
+	upsertModelRepository := &CRUDRepositoryImpl[upsertModel, uuid.UUID]{gormDatabase: database, logger: zap.L()}
+	var statement *gorm.Statement
+	err := database.Callback().Create().After("gorm:create").Register("test:statement", func(db *gorm.DB) {
+		statement = db.Statement
+	})
+	require.NoError(t, err)
+
+	herr := upsertModelRepository.Upsert(&upsertModel{Key: "key", Counter: 1}, []string{"key"}, map[string]any{
+		"counter": squirrel.Expr("upsert_models.counter + ?", 1),
+	})
+	require.Nil(t, herr)
+	require.NotNil(t, statement)
+	sql := statement.SQL.String()
+	assert.Contains(t, sql, `ON CONFLICT ("key") DO UPDATE SET "counter"=upsert_models.counter + $`)
+	assert.Contains(t, sql, "RETURNING *")
+}
+
+func TestDeleteUnscoped(t *testing.T) {
+	database := openDryRunDatabase(t)
+	upsertModelRepository := &CRUDRepositoryImpl[upsertModel, uuid.UUID]{gormDatabase: database, logger: zap.L()}
+	var statement *gorm.Statement
+	err := database.Callback().Delete().After("gorm:delete").Register("test:statement", func(db *gorm.DB) {
+		statement = db.Statement
+	})
+	require.NoError(t, err)
+
+	_, herr := upsertModelRepository.DeleteUnscoped(squirrel.Eq{"key": "key"})
+	require.Nil(t, herr)
+	require.NotNil(t, statement)
+	// the rows are deleted, not soft deleted
+	assert.Equal(t, `DELETE FROM "upsert_models" WHERE key = $1`, statement.SQL.String())
+}
@@ -10,9 +10,19 @@ var RouterModule = fx.Module(
 	"router",
 	// middlewares
 	fx.Provide(middlewares.NewJSONController),
+	fx.Provide(middlewares.NewClientIPMiddleware),
 	fx.Provide(middlewares.NewMiddlewareLogger),
 
-	fx.Provide(middlewares.NewAuthenticationMiddleware),
+	// authenticators, enabled by the authentication.schemes config key
+	fx.Provide(
+		fx.Annotate(middlewares.NewCookieAuthenticator, fx.ResultTags(`group:"authenticators"`)),
+		fx.Annotate(middlewares.NewBearerAuthenticator, fx.ResultTags(`group:"authenticators"`)),
+		fx.Annotate(middlewares.NewBasicAuthenticator, fx.ResultTags(`group:"authenticators"`)),
+		fx.Annotate(middlewares.NewClientCertificateAuthenticator, fx.ResultTags(`group:"authenticators"`)),
+	),
+	fx.Provide(fx.Annotate(middlewares.NewAuthenticationMiddleware, fx.ParamTags(`group:"authenticators"`))),
+	fx.Provide(middlewares.NewAuthorizationMiddleware),
+	fx.Provide(middlewares.NewCSRFMiddleware),
 
 	// create router
 	fx.Provide(SetupRouter),
@@ -0,0 +1,33 @@
+package middlewares
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/ditrit/badaas/httperrors"
+	"github.com/ditrit/badaas/services/sessionservice"
+)
+
+// An authentication scheme of the requests
+//
+// The authenticators are provided to fx in the "authenticators" group,
+// the [AuthenticationMiddleware] tries them in the order of the configured schemes.
+type Authenticator interface {
+	// Return the name of the scheme, used to enable it and to select the schemes accepted by a route
+	Scheme() string
+	// Return the claims of the request, or nil if the request has no credentials of this scheme
+	Authenticate(request *http.Request) (*sessionservice.SessionClaims, httperrors.HTTPError)
+	// Return the WWW-Authenticate challenge sent when a request is refused, empty if the scheme has none
+	//
+	// invalid is true if the request was refused because of the credentials of this scheme.
+	Challenge(invalid bool) string
+}
+
+// Return the credentials of the authorization header if it uses the scheme (RFC 7235 section 2.1)
+func getAuthorization(request *http.Request, scheme string) (string, bool) {
+	requestScheme, credentials, ok := strings.Cut(request.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(requestScheme, scheme) {
+		return "", false
+	}
+	return strings.TrimSpace(credentials), true
+}
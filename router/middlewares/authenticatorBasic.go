@@ -0,0 +1,139 @@
+package middlewares
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ditrit/badaas/configuration"
+	"github.com/ditrit/badaas/httperrors"
+	"github.com/ditrit/badaas/persistence/models"
+	"github.com/ditrit/badaas/persistence/models/dto"
+	"github.com/ditrit/badaas/services/ldapservice"
+	"github.com/ditrit/badaas/services/loginthrottlingservice"
+	"github.com/ditrit/badaas/services/sessionservice"
+	"github.com/ditrit/badaas/services/twofactorservice"
+	"github.com/ditrit/badaas/services/userservice"
+	"github.com/ditrit/badaas/services/webauthnservice"
+	"go.uber.org/zap"
+)
+
+// Errors
+var (
+	// The password is not enough for the users who have to give a second factor
+	HERRBasicSecondFactor = httperrors.NewForbiddenError("two-factor required",
+		"the users with a second factor can't authenticate with the basic scheme")
+)
+
+// Check interface compliance
+var _ Authenticator = (*basicAuthenticator)(nil)
+
+// Authenticate the requests with the email and the password of the user (RFC 7617)
+//
+// The failed attempts are throttled like the logins.
+type basicAuthenticator struct {
+	logger                 *zap.Logger
+	ldapService            ldapservice.LDAPService
+	loginThrottlingService loginthrottlingservice.LoginThrottlingService
+	twoFactorService       twofactorservice.TwoFactorService
+	webAuthnService        webauthnservice.WebAuthnService
+	realm                  string
+}
+
+// The basic Authenticator constructor
+func NewBasicAuthenticator(
+	logger *zap.Logger,
+	ldapService ldapservice.LDAPService,
+	loginThrottlingService loginthrottlingservice.LoginThrottlingService,
+	twoFactorService twofactorservice.TwoFactorService,
+	webAuthnService webauthnservice.WebAuthnService,
+	authenticationConfiguration configuration.AuthenticationConfiguration,
+) Authenticator {
+	return &basicAuthenticator{
+		logger:                 logger,
+		ldapService:            ldapService,
+		loginThrottlingService: loginThrottlingService,
+		twoFactorService:       twoFactorService,
+		webAuthnService:        webAuthnService,
+		realm:                  authenticationConfiguration.GetRealm(),
+	}
+}
+
+// Return the name of the scheme
+func (authenticator *basicAuthenticator) Scheme() string {
+	return configuration.AuthenticationSchemeBasic
+}
+
+// Return the claims of the user, the request has no session
+func (authenticator *basicAuthenticator) Authenticate(request *http.Request) (*sessionservice.SessionClaims, httperrors.HTTPError) {
+	credentials, ok := getAuthorization(request, "Basic")
+	if !ok {
+		return nil, nil
+	}
+	userLoginDTO, ok := parseBasicCredentials(credentials)
+	if !ok {
+		return nil, NotAuthenticated
+	}
+	clientIP := sessionservice.GetClientIP(request)
+	_, herr := authenticator.loginThrottlingService.Check(userLoginDTO.Email, clientIP)
+	if herr != nil {
+		return nil, herr
+	}
+	user, herr := authenticator.ldapService.GetUser(userLoginDTO)
+	if herr == userservice.HERRWrongPassword {
+		recordErr := authenticator.loginThrottlingService.RecordFailure(userLoginDTO.Email, clientIP)
+		if recordErr != nil {
+			authenticator.logger.Error("Failed to record a failed login attempt", zap.Error(recordErr))
+		}
+		return nil, herr
+	}
+	if herr != nil {
+		return nil, herr
+	}
+	herr = authenticator.checkNoSecondFactor(user)
+	if herr != nil {
+		return nil, herr
+	}
+	return &sessionservice.SessionClaims{UserID: user.ID}, nil
+}
+
+// Return an error if the user has to give a second factor
+func (authenticator *basicAuthenticator) checkNoSecondFactor(user *models.User) httperrors.HTTPError {
+	totpEnabled, herr := authenticator.twoFactorService.IsEnabled(user.ID)
+	if herr != nil {
+		return herr
+	}
+	webAuthnEnabled, herr := authenticator.webAuthnService.HasCredentials(user.ID)
+	if herr != nil {
+		return herr
+	}
+	required, herr := authenticator.twoFactorService.IsRequired(user.ID)
+	if herr != nil {
+		return herr
+	}
+	if totpEnabled || webAuthnEnabled || required {
+		return HERRBasicSecondFactor
+	}
+	return nil
+}
+
+// Return the basic challenge, the credentials are encoded in UTF-8 (RFC 7617 section 2.1)
+func (authenticator *basicAuthenticator) Challenge(invalid bool) string {
+	return fmt.Sprintf(`Basic realm=%q, charset="UTF-8"`, authenticator.realm)
+}
+
+// Decode the user-id and the password of the basic credentials, the user-id is the email
+func parseBasicCredentials(credentials string) (dto.UserLoginDTO, bool) {
+	decoded, err := base64.StdEncoding.DecodeString(credentials)
+	if err != nil || !utf8.Valid(decoded) {
+		return dto.UserLoginDTO{}, false
+	}
+	// the user-id can't contain a colon, the password can
+	email, password, ok := strings.Cut(string(decoded), ":")
+	if !ok || email == "" {
+		return dto.UserLoginDTO{}, false
+	}
+	return dto.UserLoginDTO{Email: email, Password: password}, true
+}
@@ -0,0 +1,65 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/ditrit/badaas/configuration"
+	"github.com/ditrit/badaas/httperrors"
+	"github.com/ditrit/badaas/services/sessionservice"
+	"github.com/ditrit/badaas/services/userservice"
+)
+
+// Errors
+var (
+	HERRInvalidClientCertificate = httperrors.NewUnauthorizedError("Authentification Error",
+		"the client certificate doesn't match a user")
+)
+
+// Check interface compliance
+var _ Authenticator = (*clientCertificateAuthenticator)(nil)
+
+// Authenticate the requests with the TLS client certificate of the user
+//
+// The certificate is verified by the server with the configured client CAs,
+// the user is the one whose email is the first email address of the certificate.
+type clientCertificateAuthenticator struct {
+	userService userservice.UserService
+}
+
+// The client certificate Authenticator constructor
+func NewClientCertificateAuthenticator(userService userservice.UserService) Authenticator {
+	return &clientCertificateAuthenticator{userService: userService}
+}
+
+// Return the name of the scheme
+func (authenticator *clientCertificateAuthenticator) Scheme() string {
+	return configuration.AuthenticationSchemeClientCertificate
+}
+
+// Return the claims of the user of the certificate, the request has no session
+func (authenticator *clientCertificateAuthenticator) Authenticate(
+	request *http.Request,
+) (*sessionservice.SessionClaims, httperrors.HTTPError) {
+	// the chains are only set if the certificate was verified
+	if request.TLS == nil || len(request.TLS.VerifiedChains) == 0 {
+		return nil, nil
+	}
+	certificate := request.TLS.VerifiedChains[0][0]
+	if len(certificate.EmailAddresses) == 0 {
+		return nil, HERRInvalidClientCertificate
+	}
+	user, herr := authenticator.userService.GetUserByEmail(certificate.EmailAddresses[0])
+	if herr != nil {
+		return nil, HERRInvalidClientCertificate
+	}
+	herr = authenticator.userService.CheckCanLogIn(user)
+	if herr != nil {
+		return nil, herr
+	}
+	return &sessionservice.SessionClaims{UserID: user.ID}, nil
+}
+
+// There is no challenge for the client certificates, they are asked during the TLS handshake
+func (authenticator *clientCertificateAuthenticator) Challenge(invalid bool) string {
+	return ""
+}
@@ -0,0 +1,99 @@
+package middlewares
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/ditrit/badaas/configuration"
+	"github.com/ditrit/badaas/httperrors"
+	"github.com/ditrit/badaas/services/apikeyservice"
+	"github.com/ditrit/badaas/services/sessionservice"
+)
+
+// Check interface compliance
+var (
+	_ Authenticator = (*cookieAuthenticator)(nil)
+	_ Authenticator = (*bearerAuthenticator)(nil)
+)
+
+// Authenticate the requests with the access token sent in the session cookie
+type cookieAuthenticator struct {
+	sessionService sessionservice.SessionService
+	cookieName     string
+}
+
+// The cookie Authenticator constructor
+func NewCookieAuthenticator(
+	sessionService sessionservice.SessionService,
+	cookieConfiguration configuration.CookieConfiguration,
+) Authenticator {
+	return &cookieAuthenticator{
+		sessionService: sessionService,
+		cookieName:     cookieConfiguration.GetName(),
+	}
+}
+
+// Return the name of the scheme
+func (authenticator *cookieAuthenticator) Scheme() string {
+	return configuration.AuthenticationSchemeCookie
+}
+
+// Return the claims of the session of the access token
+func (authenticator *cookieAuthenticator) Authenticate(request *http.Request) (*sessionservice.SessionClaims, httperrors.HTTPError) {
+	accessTokenCookie, err := request.Cookie(authenticator.cookieName)
+	if err != nil {
+		return nil, nil
+	}
+	return authenticator.sessionService.Authenticate(accessTokenCookie.Value)
+}
+
+// There is no challenge for the cookies
+func (authenticator *cookieAuthenticator) Challenge(invalid bool) string {
+	return ""
+}
+
+// Authenticate the requests with the API key or the access token sent as a bearer token (RFC 6750)
+type bearerAuthenticator struct {
+	sessionService sessionservice.SessionService
+	apiKeyService  apikeyservice.APIKeyService
+	realm          string
+}
+
+// The bearer Authenticator constructor
+func NewBearerAuthenticator(
+	sessionService sessionservice.SessionService,
+	apiKeyService apikeyservice.APIKeyService,
+	authenticationConfiguration configuration.AuthenticationConfiguration,
+) Authenticator {
+	return &bearerAuthenticator{
+		sessionService: sessionService,
+		apiKeyService:  apiKeyService,
+		realm:          authenticationConfiguration.GetRealm(),
+	}
+}
+
+// Return the name of the scheme
+func (authenticator *bearerAuthenticator) Scheme() string {
+	return configuration.AuthenticationSchemeBearer
+}
+
+// Return the claims of the API key or of the session of the access token
+func (authenticator *bearerAuthenticator) Authenticate(request *http.Request) (*sessionservice.SessionClaims, httperrors.HTTPError) {
+	token, ok := getAuthorization(request, "Bearer")
+	if !ok {
+		return nil, nil
+	}
+	if strings.HasPrefix(token, apikeyservice.KeyPrefix) {
+		return authenticator.apiKeyService.Authenticate(token)
+	}
+	return authenticator.sessionService.Authenticate(token)
+}
+
+// Return the bearer challenge, with the invalid_token error if the token was refused (RFC 6750 section 3)
+func (authenticator *bearerAuthenticator) Challenge(invalid bool) string {
+	if invalid {
+		return fmt.Sprintf(`Bearer realm=%q, error="invalid_token"`, authenticator.realm)
+	}
+	return fmt.Sprintf(`Bearer realm=%q`, authenticator.realm)
+}
@@ -1,21 +1,26 @@
 package middlewares
 
 import (
+	"fmt"
 	"net/http"
 
+	"github.com/ditrit/badaas/configuration"
 	"github.com/ditrit/badaas/httperrors"
 	"github.com/ditrit/badaas/services/sessionservice"
-	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
 var (
-	NotAuthenticated = httperrors.NewUnauthorizedError("Authentification Error", "not authenticated")
+	NotAuthenticated = sessionservice.HERRNotAuthenticated
 )
 
 // The authentication middleware
 type AuthenticationMiddleware interface {
+	// Authenticate the requests with every enabled scheme
 	Handle(next http.Handler) http.Handler
+	// Return a [github.com/gorilla/mux] compatible middleware that only
+	// authenticates the requests with the given schemes, among the enabled ones
+	Accept(schemes ...string) func(next http.Handler) http.Handler
 }
 
 // Check interface compliance
@@ -23,43 +28,112 @@ var _ AuthenticationMiddleware = (*authenticationMiddleware)(nil)
 
 // The AuthenticationMiddleware implementation
 type authenticationMiddleware struct {
+	// The enabled authenticators, in the order of the configured schemes
+	authenticators []Authenticator
 	sessionService sessionservice.SessionService
 	logger         *zap.Logger
 }
 
 // The AuthenticationMiddleware constructor
-func NewAuthenticationMiddleware(sessionService sessionservice.SessionService, logger *zap.Logger) AuthenticationMiddleware {
+//
+// The authenticators are the ones provided in the "authenticators" group,
+// only the ones of the configured schemes are enabled.
+func NewAuthenticationMiddleware(
+	authenticators []Authenticator,
+	authenticationConfiguration configuration.AuthenticationConfiguration,
+	sessionService sessionservice.SessionService,
+	logger *zap.Logger,
+) (AuthenticationMiddleware, error) {
+	authenticatorsByScheme := map[string]Authenticator{}
+	for _, authenticator := range authenticators {
+		if _, ok := authenticatorsByScheme[authenticator.Scheme()]; ok {
+			return nil, fmt.Errorf("two authenticators are registered for the scheme %q", authenticator.Scheme())
+		}
+		authenticatorsByScheme[authenticator.Scheme()] = authenticator
+	}
+	enabledAuthenticators := []Authenticator{}
+	for _, scheme := range authenticationConfiguration.GetSchemes() {
+		authenticator, ok := authenticatorsByScheme[scheme]
+		if !ok {
+			return nil, fmt.Errorf("no authenticator is registered for the scheme %q", scheme)
+		}
+		enabledAuthenticators = append(enabledAuthenticators, authenticator)
+	}
 	return &authenticationMiddleware{
+		authenticators: enabledAuthenticators,
 		sessionService: sessionService,
 		logger:         logger,
-	}
+	}, nil
 }
 
-// The authentication middleware
+// Authenticate the requests with every enabled scheme
 func (authenticationMiddleware *authenticationMiddleware) Handle(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
-		accessTokenCookie, err := request.Cookie("access_token")
-		if err != nil {
-			NotAuthenticated.Write(response, authenticationMiddleware.logger)
-			return
-		}
-		extractedUUID, err := uuid.Parse(accessTokenCookie.Value)
-		if err != nil {
-			NotAuthenticated.Write(response, authenticationMiddleware.logger)
-			return
-		}
-		ok, sessionClaims := authenticationMiddleware.sessionService.IsValid(extractedUUID)
-		if !ok {
-			NotAuthenticated.Write(response, authenticationMiddleware.logger)
-			return
+	return authenticationMiddleware.authenticate(authenticationMiddleware.authenticators, next)
+}
+
+// Only authenticate the requests with the given schemes, among the enabled ones
+func (authenticationMiddleware *authenticationMiddleware) Accept(schemes ...string) func(next http.Handler) http.Handler {
+	authenticators := []Authenticator{}
+	for _, authenticator := range authenticationMiddleware.authenticators {
+		for _, scheme := range schemes {
+			if authenticator.Scheme() == scheme {
+				authenticators = append(authenticators, authenticator)
+			}
 		}
-		herr := authenticationMiddleware.sessionService.RollSession(extractedUUID)
-		if herr != nil {
-			herr.Write(response, authenticationMiddleware.logger)
-			return
+	}
+	if len(authenticators) == 0 {
+		authenticationMiddleware.logger.Warn("None of the schemes accepted by a route is enabled",
+			zap.Strings("schemes", schemes))
+	}
+	return func(next http.Handler) http.Handler {
+		return authenticationMiddleware.authenticate(authenticators, next)
+	}
+}
+
+// Try the authenticators in order, the first one finding its credentials in the request decides
+//
+// The requests made with a session are then checked against the client of the session.
+func (authenticationMiddleware *authenticationMiddleware) authenticate(
+	authenticators []Authenticator,
+	next http.Handler,
+) http.Handler {
+	return http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+		for _, authenticator := range authenticators {
+			sessionClaims, herr := authenticator.Authenticate(request)
+			if herr != nil {
+				authenticationMiddleware.refuse(response, authenticators, authenticator, herr)
+				return
+			}
+			if sessionClaims != nil {
+				herr = authenticationMiddleware.sessionService.Track(sessionClaims, request, response)
+				if herr != nil {
+					authenticationMiddleware.refuse(response, authenticators, authenticator, herr)
+					return
+				}
+				request = request.WithContext(sessionservice.SetSessionClaimsContext(
+					request.Context(), sessionClaims))
+				next.ServeHTTP(response, request)
+				return
+			}
 		}
-		request = request.WithContext(sessionservice.SetSessionClaimsContext(
-			request.Context(), sessionClaims))
-		next.ServeHTTP(response, request)
+		authenticationMiddleware.refuse(response, authenticators, nil, NotAuthenticated)
 	})
 }
+
+// Write the error, with the challenges of the accepted schemes if the request is unauthorized (RFC 7235 section 3.1)
+func (authenticationMiddleware *authenticationMiddleware) refuse(
+	response http.ResponseWriter,
+	authenticators []Authenticator,
+	failedAuthenticator Authenticator,
+	herr httperrors.HTTPError,
+) {
+	if httpError, ok := herr.(*httperrors.HTTPErrorImpl); ok && httpError.Status == http.StatusUnauthorized {
+		for _, authenticator := range authenticators {
+			challenge := authenticator.Challenge(authenticator == failedAuthenticator)
+			if challenge != "" {
+				response.Header().Add("WWW-Authenticate", challenge)
+			}
+		}
+	}
+	herr.Write(response, authenticationMiddleware.logger)
+}
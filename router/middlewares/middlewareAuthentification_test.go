@@ -0,0 +1,200 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ditrit/badaas/configuration"
+	"github.com/ditrit/badaas/httperrors"
+	configurationMocks "github.com/ditrit/badaas/mocks/configuration"
+	mockSessionServices "github.com/ditrit/badaas/mocks/services/sessionservice"
+	"github.com/ditrit/badaas/services/sessionservice"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestAuthenticationFirstAuthenticatorWithCredentialsDecides(t *testing.T) {
+	sessionClaims := &sessionservice.SessionClaims{UserID: uuid.New()}
+	bearerAuthenticator := &fakeAuthenticator{scheme: configuration.AuthenticationSchemeBearer}
+	basicAuthenticator := &fakeAuthenticator{scheme: configuration.AuthenticationSchemeBasic, sessionClaims: sessionClaims}
+	cookieAuthenticator := &fakeAuthenticator{scheme: configuration.AuthenticationSchemeCookie}
+	authenticationMiddleware := newAuthenticationMiddleware(t,
+		[]Authenticator{cookieAuthenticator, basicAuthenticator, bearerAuthenticator},
+		configuration.AuthenticationSchemeBearer, configuration.AuthenticationSchemeBasic, configuration.AuthenticationSchemeCookie)
+
+	var claimsInContext *sessionservice.SessionClaims
+	nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claimsInContext = sessionservice.GetSessionClaimsFromContext(r.Context())
+	})
+	response := httptest.NewRecorder()
+
+	authenticationMiddleware.Handle(nextHandler).ServeHTTP(response, httptest.NewRequest("GET", "/me", nil))
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, sessionClaims, claimsInContext)
+	assert.True(t, bearerAuthenticator.called)
+	assert.False(t, cookieAuthenticator.called)
+}
+
+func TestAuthenticationSessionUsedFromAnotherClient(t *testing.T) {
+	sessionClaims := &sessionservice.SessionClaims{UserID: uuid.New(), SessionUUID: uuid.New()}
+	cookieAuthenticator := &fakeAuthenticator{scheme: configuration.AuthenticationSchemeCookie, sessionClaims: sessionClaims}
+	sessionService := mockSessionServices.NewSessionService(t)
+	sessionService.On("Track", sessionClaims, mock.Anything, mock.Anything).Return(sessionservice.HERRNotAuthenticated)
+	authenticationMiddleware := newAuthenticationMiddlewareWithSessionService(t,
+		[]Authenticator{cookieAuthenticator}, sessionService, configuration.AuthenticationSchemeCookie)
+	nextCalled := false
+	nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		nextCalled = true
+	})
+	response := httptest.NewRecorder()
+
+	authenticationMiddleware.Handle(nextHandler).ServeHTTP(response, httptest.NewRequest("GET", "/me", nil))
+	assert.Equal(t, http.StatusUnauthorized, response.Code)
+	assert.False(t, nextCalled)
+}
+
+func TestAuthenticationWithoutCredentialsSendsTheChallenges(t *testing.T) {
+	authenticationMiddleware := newAuthenticationMiddleware(t,
+		[]Authenticator{
+			&fakeAuthenticator{scheme: configuration.AuthenticationSchemeBearer, challenge: `Bearer realm="badaas"`},
+			&fakeAuthenticator{scheme: configuration.AuthenticationSchemeCookie},
+			&fakeAuthenticator{scheme: configuration.AuthenticationSchemeBasic, challenge: `Basic realm="badaas", charset="UTF-8"`},
+		},
+		configuration.AuthenticationSchemeBearer, configuration.AuthenticationSchemeCookie, configuration.AuthenticationSchemeBasic)
+
+	var actuallyRunned bool = false
+	nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		actuallyRunned = true
+	})
+	response := httptest.NewRecorder()
+
+	authenticationMiddleware.Handle(nextHandler).ServeHTTP(response, httptest.NewRequest("GET", "/me", nil))
+	assert.False(t, actuallyRunned)
+	assert.Equal(t, http.StatusUnauthorized, response.Code)
+	assert.Equal(t, []string{`Bearer realm="badaas"`, `Basic realm="badaas", charset="UTF-8"`},
+		response.Header().Values("WWW-Authenticate"))
+}
+
+func TestAuthenticationWithInvalidCredentials(t *testing.T) {
+	cookieAuthenticator := &fakeAuthenticator{scheme: configuration.AuthenticationSchemeCookie}
+	authenticationMiddleware := newAuthenticationMiddleware(t,
+		[]Authenticator{
+			&fakeAuthenticator{
+				scheme: configuration.AuthenticationSchemeBearer, herr: sessionservice.HERRNotAuthenticated,
+				challenge: `Bearer realm="badaas"`,
+			},
+			cookieAuthenticator,
+		},
+		configuration.AuthenticationSchemeBearer, configuration.AuthenticationSchemeCookie)
+
+	var actuallyRunned bool = false
+	nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		actuallyRunned = true
+	})
+	response := httptest.NewRecorder()
+
+	authenticationMiddleware.Handle(nextHandler).ServeHTTP(response, httptest.NewRequest("GET", "/me", nil))
+	assert.False(t, actuallyRunned)
+	assert.False(t, cookieAuthenticator.called)
+	assert.Equal(t, http.StatusUnauthorized, response.Code)
+	assert.Equal(t, `Bearer realm="badaas", error="invalid_token"`, response.Header().Get("WWW-Authenticate"))
+}
+
+func TestAuthenticationForbiddenHasNoChallenge(t *testing.T) {
+	authenticationMiddleware := newAuthenticationMiddleware(t,
+		[]Authenticator{&fakeAuthenticator{
+			scheme: configuration.AuthenticationSchemeBasic, herr: HERRBasicSecondFactor, challenge: `Basic realm="badaas"`,
+		}},
+		configuration.AuthenticationSchemeBasic)
+	response := httptest.NewRecorder()
+
+	authenticationMiddleware.Handle(http.NotFoundHandler()).ServeHTTP(response, httptest.NewRequest("GET", "/me", nil))
+	assert.Equal(t, http.StatusForbidden, response.Code)
+	assert.Empty(t, response.Header().Values("WWW-Authenticate"))
+}
+
+func TestAuthenticationAcceptOnlyTriesTheGivenSchemes(t *testing.T) {
+	basicAuthenticator := &fakeAuthenticator{scheme: configuration.AuthenticationSchemeBasic, challenge: `Basic realm="badaas"`}
+	cookieAuthenticator := &fakeAuthenticator{scheme: configuration.AuthenticationSchemeCookie}
+	authenticationMiddleware := newAuthenticationMiddleware(t,
+		[]Authenticator{basicAuthenticator, cookieAuthenticator},
+		configuration.AuthenticationSchemeBasic, configuration.AuthenticationSchemeCookie)
+	response := httptest.NewRecorder()
+
+	authenticationMiddleware.Accept(configuration.AuthenticationSchemeCookie)(http.NotFoundHandler()).
+		ServeHTTP(response, httptest.NewRequest("GET", "/logout", nil))
+	assert.Equal(t, http.StatusUnauthorized, response.Code)
+	assert.True(t, cookieAuthenticator.called)
+	assert.False(t, basicAuthenticator.called)
+	assert.Empty(t, response.Header().Values("WWW-Authenticate"))
+}
+
+func TestAuthenticationUnknownScheme(t *testing.T) {
+	authenticationConfiguration := configurationMocks.NewAuthenticationConfiguration(t)
+	authenticationConfiguration.On("GetSchemes").Return([]string{"negotiate"})
+
+	_, err := NewAuthenticationMiddleware([]Authenticator{}, authenticationConfiguration,
+		mockSessionServices.NewSessionService(t), zap.L())
+	assert.ErrorContains(t, err, `no authenticator is registered for the scheme "negotiate"`)
+}
+
+func TestAuthenticationDuplicatedScheme(t *testing.T) {
+	authenticators := []Authenticator{
+		&fakeAuthenticator{scheme: configuration.AuthenticationSchemeBasic},
+		&fakeAuthenticator{scheme: configuration.AuthenticationSchemeBasic},
+	}
+
+	_, err := NewAuthenticationMiddleware(authenticators, configurationMocks.NewAuthenticationConfiguration(t),
+		mockSessionServices.NewSessionService(t), zap.L())
+	assert.ErrorContains(t, err, `two authenticators are registered for the scheme "basic"`)
+}
+
+// An Authenticator returning fixed results
+type fakeAuthenticator struct {
+	scheme        string
+	sessionClaims *sessionservice.SessionClaims
+	herr          httperrors.HTTPError
+	challenge     string
+	called        bool
+}
+
+func (authenticator *fakeAuthenticator) Scheme() string {
+	return authenticator.scheme
+}
+
+func (authenticator *fakeAuthenticator) Authenticate(*http.Request) (*sessionservice.SessionClaims, httperrors.HTTPError) {
+	authenticator.called = true
+	return authenticator.sessionClaims, authenticator.herr
+}
+
+func (authenticator *fakeAuthenticator) Challenge(invalid bool) string {
+	if invalid && authenticator.challenge != "" {
+		return authenticator.challenge + `, error="invalid_token"`
+	}
+	return authenticator.challenge
+}
+
+// Create an authentication middleware with the authenticators and the enabled schemes
+func newAuthenticationMiddleware(t *testing.T, authenticators []Authenticator, schemes ...string) AuthenticationMiddleware {
+	sessionService := mockSessionServices.NewSessionService(t)
+	sessionService.On("Track", mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	return newAuthenticationMiddlewareWithSessionService(t, authenticators, sessionService, schemes...)
+}
+
+// Create an authentication middleware with the authenticators, the session service and the enabled schemes
+func newAuthenticationMiddlewareWithSessionService(
+	t *testing.T,
+	authenticators []Authenticator,
+	sessionService sessionservice.SessionService,
+	schemes ...string,
+) AuthenticationMiddleware {
+	authenticationConfiguration := configurationMocks.NewAuthenticationConfiguration(t)
+	authenticationConfiguration.On("GetSchemes").Return(schemes)
+	authenticationMiddleware, err := NewAuthenticationMiddleware(authenticators, authenticationConfiguration, sessionService, zap.L())
+	require.NoError(t, err)
+	return authenticationMiddleware
+}
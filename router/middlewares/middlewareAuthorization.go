@@ -0,0 +1,131 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/ditrit/badaas/httperrors"
+	"github.com/ditrit/badaas/services/apikeyservice"
+	"github.com/ditrit/badaas/services/policyservice"
+	"github.com/ditrit/badaas/services/rbacservice"
+	"github.com/ditrit/badaas/services/sessionservice"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// Return the resource targeted by a request, used to evaluate the policies
+type ResourceResolver func(request *http.Request) (policyservice.Resource, httperrors.HTTPError)
+
+// The authorization middleware
+//
+// It must be used after the [AuthenticationMiddleware] since it relies on the session claims.
+type AuthorizationMiddleware interface {
+	// Return a [github.com/gorilla/mux] compatible middleware that only let
+	// the request through if the user has been granted the permission
+	RequirePermission(permission string) func(next http.Handler) http.Handler
+
+	// Return a [github.com/gorilla/mux] compatible middleware that only let
+	// the request through if the policies allow the action on the resource returned by the resolver
+	RequirePolicy(action string, resourceResolver ResourceResolver) func(next http.Handler) http.Handler
+
+	// Refuse the requests authenticated with an API key, the account and the credentials
+	// of the users can only be managed with a session
+	RefuseAPIKeys(next http.Handler) http.Handler
+}
+
+// Check interface compliance
+var _ AuthorizationMiddleware = (*authorizationMiddleware)(nil)
+
+// The AuthorizationMiddleware implementation
+type authorizationMiddleware struct {
+	rbacService   rbacservice.RBACService
+	policyService policyservice.PolicyService
+	logger        *zap.Logger
+}
+
+// The AuthorizationMiddleware constructor
+func NewAuthorizationMiddleware(
+	rbacService rbacservice.RBACService,
+	policyService policyservice.PolicyService,
+	logger *zap.Logger,
+) AuthorizationMiddleware {
+	return &authorizationMiddleware{
+		rbacService:   rbacService,
+		policyService: policyService,
+		logger:        logger,
+	}
+}
+
+// Only let the request through if the user has been granted the permission
+//
+// The requests authenticated with an API key also need a scope of the key allowing the permission.
+func (authorizationMiddleware *authorizationMiddleware) RequirePermission(permission string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+			sessionClaims := sessionservice.GetSessionClaimsFromContext(request.Context())
+			if !rbacservice.ClaimsAllow(sessionClaims, permission) {
+				rbacservice.HERRPermissionDenied.Write(response, authorizationMiddleware.logger)
+				return
+			}
+			herr := authorizationMiddleware.rbacService.CheckPermission(sessionClaims.UserID, permission)
+			if herr != nil {
+				herr.Write(response, authorizationMiddleware.logger)
+				return
+			}
+			next.ServeHTTP(response, request)
+		})
+	}
+}
+
+// Refuse the requests authenticated with an API key
+func (authorizationMiddleware *authorizationMiddleware) RefuseAPIKeys(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+		if sessionservice.GetSessionClaimsFromContext(request.Context()).IsAPIKey() {
+			apikeyservice.HERRAPIKeyRefused.Write(response, authorizationMiddleware.logger)
+			return
+		}
+		next.ServeHTTP(response, request)
+	})
+}
+
+// Only let the request through if the policies allow the action on the resource
+//
+// Can be used on unauthenticated routes, the subject is then anonymous.
+func (authorizationMiddleware *authorizationMiddleware) RequirePolicy(
+	action string,
+	resourceResolver ResourceResolver,
+) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+			resource, herr := resourceResolver(request)
+			if herr != nil {
+				herr.Write(response, authorizationMiddleware.logger)
+				return
+			}
+			herr = authorizationMiddleware.policyService.Authorize(policyservice.AccessRequest{
+				Subject:  sessionservice.LookupSessionClaimsFromContext(request.Context()),
+				Action:   action,
+				Resource: resource,
+				Context:  makeRequestAttributes(request),
+			})
+			if herr != nil {
+				herr.Write(response, authorizationMiddleware.logger)
+				return
+			}
+			next.ServeHTTP(response, request)
+		})
+	}
+}
+
+// Return the attributes of the request available to the policies
+func makeRequestAttributes(request *http.Request) map[string]any {
+	vars := map[string]any{}
+	for key, value := range mux.Vars(request) {
+		vars[key] = value
+	}
+	return map[string]any{
+		"method": request.Method,
+		"path":   request.URL.Path,
+		"ip":     sessionservice.GetClientIP(request),
+		"vars":   vars,
+	}
+}
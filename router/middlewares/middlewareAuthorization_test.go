@@ -0,0 +1,159 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ditrit/badaas/httperrors"
+	mockPolicyServices "github.com/ditrit/badaas/mocks/services/policyservice"
+	mockRBACServices "github.com/ditrit/badaas/mocks/services/rbacservice"
+	"github.com/ditrit/badaas/services/policyservice"
+	"github.com/ditrit/badaas/services/rbacservice"
+	"github.com/ditrit/badaas/services/sessionservice"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func TestRequirePermission(t *testing.T) {
+	userID := uuid.New()
+	rbacService := mockRBACServices.NewRBACService(t)
+	rbacService.On("CheckPermission", userID, rbacservice.PermissionRolesManage).Return(nil)
+	authorizationMiddleware := NewAuthorizationMiddleware(rbacService, mockPolicyServices.NewPolicyService(t), zap.L())
+
+	var actuallyRunned bool = false
+	nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		actuallyRunned = true
+	})
+	request := httptest.NewRequest("GET", "/roles", nil)
+	request = request.WithContext(sessionservice.SetSessionClaimsContext(
+		request.Context(), &sessionservice.SessionClaims{UserID: userID}))
+	response := httptest.NewRecorder()
+
+	authorizationMiddleware.RequirePermission(rbacservice.PermissionRolesManage)(nextHandler).ServeHTTP(response, request)
+	assert.True(t, actuallyRunned)
+	assert.Equal(t, http.StatusOK, response.Code)
+}
+
+func TestRequirePermissionAPIKeyScopes(t *testing.T) {
+	userID := uuid.New()
+	rbacService := mockRBACServices.NewRBACService(t)
+	rbacService.On("CheckPermission", userID, rbacservice.PermissionRolesManage).Return(nil).Once()
+	authorizationMiddleware := NewAuthorizationMiddleware(rbacService, mockPolicyServices.NewPolicyService(t), zap.L())
+
+	nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	for scope, expectedStatus := range map[string]int{"roles:*": http.StatusOK, "users:manage": http.StatusForbidden} {
+		request := httptest.NewRequest("GET", "/roles", nil)
+		request = request.WithContext(sessionservice.SetSessionClaimsContext(
+			request.Context(), &sessionservice.SessionClaims{UserID: userID, APIKeyID: uuid.New(), Scopes: []string{scope}}))
+		response := httptest.NewRecorder()
+
+		authorizationMiddleware.RequirePermission(rbacservice.PermissionRolesManage)(nextHandler).ServeHTTP(response, request)
+		assert.Equal(t, expectedStatus, response.Code, scope)
+	}
+}
+
+func TestRequirePermissionDenied(t *testing.T) {
+	userID := uuid.New()
+	rbacService := mockRBACServices.NewRBACService(t)
+	rbacService.On("CheckPermission", userID, rbacservice.PermissionRolesManage).Return(rbacservice.HERRPermissionDenied)
+	authorizationMiddleware := NewAuthorizationMiddleware(rbacService, mockPolicyServices.NewPolicyService(t), zap.L())
+
+	var actuallyRunned bool = false
+	nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		actuallyRunned = true
+	})
+	request := httptest.NewRequest("GET", "/roles", nil)
+	request = request.WithContext(sessionservice.SetSessionClaimsContext(
+		request.Context(), &sessionservice.SessionClaims{UserID: userID}))
+	response := httptest.NewRecorder()
+
+	authorizationMiddleware.RequirePermission(rbacservice.PermissionRolesManage)(nextHandler).ServeHTTP(response, request)
+	assert.False(t, actuallyRunned)
+	assert.Equal(t, http.StatusForbidden, response.Code)
+}
+
+func TestRequirePermissionError(t *testing.T) {
+	userID := uuid.New()
+	rbacService := mockRBACServices.NewRBACService(t)
+	rbacService.On("CheckPermission", userID, rbacservice.PermissionRolesManage).
+		Return(httperrors.NewInternalServerError("database error", "test error", nil))
+	authorizationMiddleware := NewAuthorizationMiddleware(rbacService, mockPolicyServices.NewPolicyService(t), zap.L())
+
+	nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	request := httptest.NewRequest("GET", "/roles", nil)
+	request = request.WithContext(sessionservice.SetSessionClaimsContext(
+		request.Context(), &sessionservice.SessionClaims{UserID: userID}))
+	response := httptest.NewRecorder()
+
+	authorizationMiddleware.RequirePermission(rbacservice.PermissionRolesManage)(nextHandler).ServeHTTP(response, request)
+	assert.Equal(t, http.StatusInternalServerError, response.Code)
+}
+
+func TestRefuseAPIKeys(t *testing.T) {
+	authorizationMiddleware := NewAuthorizationMiddleware(mockRBACServices.NewRBACService(t), mockPolicyServices.NewPolicyService(t), zap.L())
+
+	nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	for claims, expectedStatus := range map[*sessionservice.SessionClaims]int{
+		{UserID: uuid.New()}: http.StatusOK,
+		{UserID: uuid.New(), APIKeyID: uuid.New(), Scopes: []string{"*"}}: http.StatusForbidden,
+	} {
+		request := httptest.NewRequest("POST", "/me/password", nil)
+		request = request.WithContext(sessionservice.SetSessionClaimsContext(request.Context(), claims))
+		response := httptest.NewRecorder()
+
+		authorizationMiddleware.RefuseAPIKeys(nextHandler).ServeHTTP(response, request)
+		assert.Equal(t, expectedStatus, response.Code)
+	}
+}
+
+func postResolver(request *http.Request) (policyservice.Resource, httperrors.HTTPError) {
+	return policyservice.NewResource("post", map[string]any{"id": mux.Vars(request)["id"]}), nil
+}
+
+func TestRequirePolicy(t *testing.T) {
+	userID := uuid.New()
+	policyService := mockPolicyServices.NewPolicyService(t)
+	policyService.On("Authorize", mock.MatchedBy(func(accessRequest policyservice.AccessRequest) bool {
+		return accessRequest.Subject.UserID == userID &&
+			accessRequest.Action == "posts:update" &&
+			accessRequest.Resource.ResourceAttributes()["id"] == "42" &&
+			accessRequest.Context["method"] == "PUT" &&
+			accessRequest.Context["ip"] == "192.0.2.1"
+	})).Return(nil)
+	authorizationMiddleware := NewAuthorizationMiddleware(mockRBACServices.NewRBACService(t), policyService, zap.L())
+
+	var actuallyRunned bool = false
+	nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		actuallyRunned = true
+	})
+	request := httptest.NewRequest("PUT", "/posts/42", nil)
+	request = mux.SetURLVars(request, map[string]string{"id": "42"})
+	request = request.WithContext(sessionservice.SetSessionClaimsContext(
+		request.Context(), &sessionservice.SessionClaims{UserID: userID}))
+	response := httptest.NewRecorder()
+
+	authorizationMiddleware.RequirePolicy("posts:update", postResolver)(nextHandler).ServeHTTP(response, request)
+	assert.True(t, actuallyRunned)
+	assert.Equal(t, http.StatusOK, response.Code)
+}
+
+func TestRequirePolicyDenied(t *testing.T) {
+	policyService := mockPolicyServices.NewPolicyService(t)
+	policyService.On("Authorize", mock.Anything).Return(policyservice.HERRAccessDenied)
+	authorizationMiddleware := NewAuthorizationMiddleware(mockRBACServices.NewRBACService(t), policyService, zap.L())
+
+	var actuallyRunned bool = false
+	nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		actuallyRunned = true
+	})
+	request := httptest.NewRequest("PUT", "/posts/42", nil)
+	response := httptest.NewRecorder()
+
+	authorizationMiddleware.RequirePolicy("posts:update", postResolver)(nextHandler).ServeHTTP(response, request)
+	assert.False(t, actuallyRunned)
+	assert.Equal(t, http.StatusForbidden, response.Code)
+}
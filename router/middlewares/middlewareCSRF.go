@@ -0,0 +1,92 @@
+package middlewares
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/ditrit/badaas/configuration"
+	"github.com/ditrit/badaas/httperrors"
+	"github.com/ditrit/badaas/services/sessionservice"
+	"go.uber.org/zap"
+)
+
+var (
+	HERRInvalidCSRFToken = httperrors.NewForbiddenError("csrf error", "the csrf token is missing or invalid")
+)
+
+// The methods that don't change the state of the server
+var safeMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodOptions: true,
+	http.MethodTrace:   true,
+}
+
+// Protect the requests authenticated with the session cookie against the cross-site request forgery
+type CSRFMiddleware interface {
+	// [github.com/gorilla/mux] compatible middleware function
+	Handle(next http.Handler) http.Handler
+}
+
+// Check interface compliance
+var _ CSRFMiddleware = (*csrfMiddleware)(nil)
+
+// The CSRFMiddleware implementation
+type csrfMiddleware struct {
+	cookieConfiguration configuration.CookieConfiguration
+	logger              *zap.Logger
+}
+
+// The CSRFMiddleware constructor
+func NewCSRFMiddleware(
+	cookieConfiguration configuration.CookieConfiguration,
+	logger *zap.Logger,
+) CSRFMiddleware {
+	return &csrfMiddleware{
+		cookieConfiguration: cookieConfiguration,
+		logger:              logger,
+	}
+}
+
+// Refuse the state-changing requests sent with the session cookie or the refresh token cookie
+// if the csrf header doesn't hold the token of the csrf cookie (double submit cookie)
+//
+// The requests sent with a bearer token can't be forged by another site, they are not checked.
+func (csrfMiddleware *csrfMiddleware) Handle(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+		if csrfMiddleware.cookieConfiguration.GetCSRFEnabled() && csrfMiddleware.needsToken(request) &&
+			!csrfMiddleware.hasValidToken(request) {
+			csrfMiddleware.logger.Warn("Refused a request without a valid csrf token",
+				zap.String("method", request.Method),
+				zap.String("url", request.URL.Path),
+				zap.String("ip", sessionservice.GetClientIP(request)))
+			HERRInvalidCSRFToken.Write(response, csrfMiddleware.logger)
+			return
+		}
+		next.ServeHTTP(response, request)
+	})
+}
+
+// Return true if the request changes the state of the server with the session cookie or the refresh token cookie
+func (csrfMiddleware *csrfMiddleware) needsToken(request *http.Request) bool {
+	if safeMethods[request.Method] {
+		return false
+	}
+	_, sessionCookieErr := request.Cookie(csrfMiddleware.cookieConfiguration.GetName())
+	_, refreshTokenCookieErr := request.Cookie(sessionservice.RefreshTokenCookieName)
+	if sessionCookieErr != nil && refreshTokenCookieErr != nil {
+		return false
+	}
+	_, hasBearerToken := getAuthorization(request, "Bearer")
+	return !hasBearerToken
+}
+
+// Return true if the csrf header holds the token of the csrf cookie
+func (csrfMiddleware *csrfMiddleware) hasValidToken(request *http.Request) bool {
+	csrfCookie, err := request.Cookie(csrfMiddleware.cookieConfiguration.GetCSRFCookieName())
+	if err != nil || csrfCookie.Value == "" {
+		return false
+	}
+	csrfHeader := request.Header.Get(csrfMiddleware.cookieConfiguration.GetCSRFHeaderName())
+	return subtle.ConstantTimeCompare([]byte(csrfHeader), []byte(csrfCookie.Value)) == 1
+}
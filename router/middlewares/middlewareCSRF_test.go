@@ -0,0 +1,115 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	configurationMocks "github.com/ditrit/badaas/mocks/configuration"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestCSRFMiddleware(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		cookies        []*http.Cookie
+		headers        map[string]string
+		expectedStatus int
+	}{
+		{
+			name:           "safe method",
+			method:         "GET",
+			cookies:        []*http.Cookie{{Name: "access_token", Value: "session"}},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "without session cookie",
+			method:         "POST",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "with a bearer token",
+			method:         "POST",
+			cookies:        []*http.Cookie{{Name: "access_token", Value: "session"}},
+			headers:        map[string]string{"Authorization": "Bearer token"},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "valid token",
+			method:         "DELETE",
+			cookies:        []*http.Cookie{{Name: "access_token", Value: "session"}, {Name: "csrf_token", Value: "csrf"}},
+			headers:        map[string]string{"X-CSRF-Token": "csrf"},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "without token",
+			method:         "POST",
+			cookies:        []*http.Cookie{{Name: "access_token", Value: "session"}, {Name: "csrf_token", Value: "csrf"}},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "wrong token",
+			method:         "POST",
+			cookies:        []*http.Cookie{{Name: "access_token", Value: "session"}, {Name: "csrf_token", Value: "csrf"}},
+			headers:        map[string]string{"X-CSRF-Token": "forged"},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "refresh token without token",
+			method:         "POST",
+			cookies:        []*http.Cookie{{Name: "refresh_token", Value: "refresh"}, {Name: "csrf_token", Value: "csrf"}},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "refresh token with valid token",
+			method:         "POST",
+			cookies:        []*http.Cookie{{Name: "refresh_token", Value: "refresh"}, {Name: "csrf_token", Value: "csrf"}},
+			headers:        map[string]string{"X-CSRF-Token": "csrf"},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "without csrf cookie",
+			method:         "POST",
+			cookies:        []*http.Cookie{{Name: "access_token", Value: "session"}},
+			headers:        map[string]string{"X-CSRF-Token": ""},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cookieConfiguration := configurationMocks.NewCookieConfiguration(t)
+			cookieConfiguration.On("GetCSRFEnabled").Return(true)
+			cookieConfiguration.On("GetName").Return("access_token").Maybe()
+			cookieConfiguration.On("GetCSRFCookieName").Return("csrf_token").Maybe()
+			cookieConfiguration.On("GetCSRFHeaderName").Return("X-CSRF-Token").Maybe()
+			request := httptest.NewRequest(test.method, "/me/password", nil)
+			for _, cookie := range test.cookies {
+				request.AddCookie(cookie)
+			}
+			for name, value := range test.headers {
+				request.Header.Set(name, value)
+			}
+			response := httptest.NewRecorder()
+
+			NewCSRFMiddleware(cookieConfiguration, zap.L()).Handle(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})).ServeHTTP(response, request)
+			assert.Equal(t, test.expectedStatus, response.Code)
+		})
+	}
+}
+
+func TestCSRFMiddlewareDisabled(t *testing.T) {
+	cookieConfiguration := configurationMocks.NewCookieConfiguration(t)
+	cookieConfiguration.On("GetCSRFEnabled").Return(false)
+	request := httptest.NewRequest("POST", "/me/password", nil)
+	request.AddCookie(&http.Cookie{Name: "access_token", Value: "session"})
+	response := httptest.NewRecorder()
+
+	NewCSRFMiddleware(cookieConfiguration, zap.L()).Handle(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})).ServeHTTP(response, request)
+	assert.Equal(t, http.StatusOK, response.Code)
+}
@@ -0,0 +1,80 @@
+package middlewares
+
+import (
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/ditrit/badaas/configuration"
+	"github.com/ditrit/badaas/services/sessionservice"
+)
+
+// Set the address of the requests forwarded by a trusted proxy to the address of their client
+//
+// The X-Forwarded-For header is read from the right, the first address that is not a trusted proxy is the client.
+// The header of the requests that don't come from a trusted proxy is ignored, so that the clients can't forge it.
+type ClientIPMiddleware interface {
+	// [github.com/gorilla/mux] compatible middleware function
+	Handle(next http.Handler) http.Handler
+}
+
+// check interface compliance
+var _ ClientIPMiddleware = (*clientIPMiddlewareImpl)(nil)
+
+// ClientIPMiddleware implementation
+type clientIPMiddlewareImpl struct {
+	httpServerConfiguration configuration.HTTPServerConfiguration
+}
+
+// ClientIPMiddleware constructor
+func NewClientIPMiddleware(httpServerConfiguration configuration.HTTPServerConfiguration) ClientIPMiddleware {
+	return &clientIPMiddlewareImpl{
+		httpServerConfiguration: httpServerConfiguration,
+	}
+}
+
+// Set the address of the request to the address of its client if it was forwarded by a trusted proxy
+func (clientIPMiddleware *clientIPMiddlewareImpl) Handle(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		clientIP := clientIPMiddleware.getForwardedFor(r)
+		if clientIP != "" {
+			r = r.WithContext(r.Context())
+			r.RemoteAddr = clientIP
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Return the address of the client of a request forwarded by trusted proxies, empty if it was not
+func (clientIPMiddleware *clientIPMiddlewareImpl) getForwardedFor(request *http.Request) string {
+	if !clientIPMiddleware.isTrusted(net.ParseIP(sessionservice.GetClientIP(request))) {
+		return ""
+	}
+	addresses := strings.Split(strings.Join(request.Header.Values("X-Forwarded-For"), ","), ",")
+	clientIP := ""
+	for i := len(addresses) - 1; i >= 0; i-- {
+		ip := net.ParseIP(strings.TrimSpace(addresses[i]))
+		if ip == nil {
+			// the addresses before a malformed one can't be trusted
+			break
+		}
+		clientIP = ip.String()
+		if !clientIPMiddleware.isTrusted(ip) {
+			break
+		}
+	}
+	return clientIP
+}
+
+// Return true if the ip address is the address of a trusted proxy
+func (clientIPMiddleware *clientIPMiddlewareImpl) isTrusted(ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+	for _, trustedProxy := range clientIPMiddleware.httpServerConfiguration.GetTrustedProxies() {
+		if trustedProxy.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,74 @@
+package middlewares
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	configurationMocks "github.com/ditrit/badaas/mocks/configuration"
+	"github.com/ditrit/badaas/services/sessionservice"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientIPMiddleware(t *testing.T) {
+	_, proxies, _ := net.ParseCIDR("10.0.0.0/8")
+	tests := []struct {
+		name         string
+		remoteAddr   string
+		forwardedFor []string
+		expectedIP   string
+	}{
+		{
+			name:       "without proxy",
+			remoteAddr: "192.0.2.1:1234",
+			expectedIP: "192.0.2.1",
+		},
+		{
+			name:         "forged header",
+			remoteAddr:   "192.0.2.1:1234",
+			forwardedFor: []string{"198.51.100.7"},
+			expectedIP:   "192.0.2.1",
+		},
+		{
+			name:         "trusted proxy",
+			remoteAddr:   "10.0.0.2:1234",
+			forwardedFor: []string{"198.51.100.7"},
+			expectedIP:   "198.51.100.7",
+		},
+		{
+			name:         "address prepended by the client",
+			remoteAddr:   "10.0.0.2:1234",
+			forwardedFor: []string{"203.0.113.9, 198.51.100.7", "10.0.0.3"},
+			expectedIP:   "198.51.100.7",
+		},
+		{
+			name:         "malformed address",
+			remoteAddr:   "10.0.0.2:1234",
+			forwardedFor: []string{"198.51.100.7, unknown"},
+			expectedIP:   "10.0.0.2",
+		},
+		{
+			name:       "trusted proxy without header",
+			remoteAddr: "10.0.0.2:1234",
+			expectedIP: "10.0.0.2",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			httpServerConfiguration := configurationMocks.NewHTTPServerConfiguration(t)
+			httpServerConfiguration.On("GetTrustedProxies").Return([]*net.IPNet{proxies})
+			request := httptest.NewRequest("GET", "/info", nil)
+			request.RemoteAddr = test.remoteAddr
+			for _, forwardedFor := range test.forwardedFor {
+				request.Header.Add("X-Forwarded-For", forwardedFor)
+			}
+
+			var clientIP string
+			NewClientIPMiddleware(httpServerConfiguration).Handle(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				clientIP = sessionservice.GetClientIP(r)
+			})).ServeHTTP(httptest.NewRecorder(), request)
+			assert.Equal(t, test.expectedIP, clientIP)
+		})
+	}
+}
@@ -3,8 +3,13 @@ package router
 import (
 	"net/http"
 
+	"github.com/ditrit/badaas/configuration"
 	"github.com/ditrit/badaas/controllers"
 	"github.com/ditrit/badaas/router/middlewares"
+	"github.com/ditrit/badaas/services/oauthservice"
+	"github.com/ditrit/badaas/services/policyservice"
+	"github.com/ditrit/badaas/services/rbacservice"
+	"github.com/ditrit/badaas/services/userservice"
 	"github.com/gorilla/mux"
 )
 
@@ -12,14 +17,33 @@ import (
 func SetupRouter(
 	//middlewares
 	jsonController middlewares.JSONController,
+	clientIPMiddleware middlewares.ClientIPMiddleware,
 	middlewareLogger middlewares.MiddlewareLogger,
 	authenticationMiddleware middlewares.AuthenticationMiddleware,
+	authorizationMiddleware middlewares.AuthorizationMiddleware,
+	csrfMiddleware middlewares.CSRFMiddleware,
 
 	// controllers
 	basicAuthentificationController controllers.BasicAuthentificationController,
 	informationController controllers.InformationController,
+	rbacController controllers.RBACController,
+	policyController controllers.PolicyController,
+	groupController controllers.GroupController,
+	userController controllers.UserController,
+	accountController controllers.AccountController,
+	registrationController controllers.RegistrationController,
+	emailVerificationController controllers.EmailVerificationController,
+	passwordResetController controllers.PasswordResetController,
+	twoFactorController controllers.TwoFactorController,
+	webAuthnController controllers.WebAuthnController,
+	oidcController controllers.OIDCController,
+	samlController controllers.SAMLController,
+	oauthController controllers.OAuthController,
+	apiKeyController controllers.APIKeyController,
+	sessionController controllers.SessionController,
 ) http.Handler {
 	router := mux.NewRouter()
+	router.Use(clientIPMiddleware.Handle)
 	router.Use(middlewareLogger.Handle)
 
 	router.HandleFunc(
@@ -32,11 +56,131 @@ func SetupRouter(
 			basicAuthentificationController.BasicLoginHandler,
 		),
 	).Methods("POST")
+	router.HandleFunc("/login/2fa", jsonController.Wrap(twoFactorController.LoginWithCode)).Methods("POST")
+	router.HandleFunc("/login/2fa/enrol", jsonController.Wrap(twoFactorController.BeginLoginEnrolment)).Methods("POST")
+	router.HandleFunc("/login/2fa/enrol/confirm", jsonController.Wrap(twoFactorController.ConfirmLoginEnrolment)).Methods("POST")
+	router.HandleFunc("/login/2fa/webauthn/options", jsonController.Wrap(webAuthnController.BeginSecondFactorLogin)).Methods("POST")
+	router.HandleFunc("/login/2fa/webauthn", jsonController.Wrap(webAuthnController.SecondFactorLogin)).Methods("POST")
+	router.HandleFunc("/login/webauthn/options", jsonController.Wrap(webAuthnController.BeginLogin)).Methods("POST")
+	router.HandleFunc("/login/webauthn", jsonController.Wrap(webAuthnController.Login)).Methods("POST")
+	router.HandleFunc("/login/oidc", jsonController.Wrap(oidcController.ListProviders)).Methods("GET")
+	router.HandleFunc("/login/oidc/{provider}", jsonController.Wrap(oidcController.Login)).Methods("GET")
+	router.HandleFunc("/login/oidc/{provider}/callback", jsonController.Wrap(oidcController.Callback)).Methods("GET")
+	router.HandleFunc("/login/saml", jsonController.Wrap(samlController.Login)).Methods("GET")
+	router.HandleFunc("/login/saml/acs", jsonController.Wrap(samlController.AssertionConsumerService)).Methods("POST")
+	router.HandleFunc("/saml/metadata", jsonController.Wrap(samlController.Metadata)).Methods("GET")
+	router.HandleFunc("/oauth/token", jsonController.Wrap(oauthController.Token)).Methods("POST")
+	router.HandleFunc("/oauth/introspect", jsonController.Wrap(oauthController.Introspect)).Methods("POST")
+	router.HandleFunc("/oauth/revoke", jsonController.Wrap(oauthController.Revoke)).Methods("POST")
+	router.HandleFunc("/.well-known/jwks.json", jsonController.Wrap(sessionController.JWKS)).Methods("GET")
+	router.HandleFunc("/register", jsonController.Wrap(registrationController.Register)).Methods("POST")
+	router.HandleFunc("/verify-email", jsonController.Wrap(emailVerificationController.VerifyEmail)).Methods("POST")
+	router.HandleFunc("/verify-email/resend", jsonController.Wrap(emailVerificationController.ResendEmailVerification)).Methods("POST")
+	router.HandleFunc("/password/forgot", jsonController.Wrap(passwordResetController.ForgotPassword)).Methods("POST")
+	router.HandleFunc("/password/reset", jsonController.Wrap(passwordResetController.ResetPassword)).Methods("POST")
+
+	// the refresh token is sent in a cookie, the refresh needs the csrf token even once the access token expired
+	refresh := router.PathPrefix("").Subrouter()
+	refresh.Use(csrfMiddleware.Handle)
+	refresh.HandleFunc("/session/refresh", jsonController.Wrap(sessionController.Refresh)).Methods("POST")
+
+	// the logout ends the session of the request, it needs a scheme with a session
+	sessionProtected := router.PathPrefix("").Subrouter()
+	sessionProtected.Use(authenticationMiddleware.Accept(configuration.AuthenticationSchemeCookie, configuration.AuthenticationSchemeBearer))
+	sessionProtected.Use(csrfMiddleware.Handle)
+	sessionProtected.HandleFunc("/logout", jsonController.Wrap(basicAuthentificationController.Logout)).Methods("GET")
 
 	protected := router.PathPrefix("").Subrouter()
 	protected.Use(authenticationMiddleware.Handle)
+	protected.Use(csrfMiddleware.Handle)
+
+	protected.HandleFunc("/me", jsonController.Wrap(accountController.GetMe)).Methods("GET")
+
+	// the account and the credentials can't be managed with an API key, whatever its scopes
+	account := protected.PathPrefix("").Subrouter()
+	account.Use(authorizationMiddleware.RefuseAPIKeys)
+	account.HandleFunc("/me/password", jsonController.Wrap(accountController.ChangePassword)).Methods("POST")
+	account.HandleFunc("/me/email", jsonController.Wrap(accountController.ChangeEmail)).Methods("POST")
+	account.HandleFunc("/me/email/confirm", jsonController.Wrap(accountController.ConfirmEmailChange)).Methods("POST")
+	account.HandleFunc("/me/2fa", jsonController.Wrap(twoFactorController.GetStatus)).Methods("GET")
+	account.HandleFunc("/me/2fa", jsonController.Wrap(twoFactorController.BeginEnrolment)).Methods("POST")
+	account.HandleFunc("/me/2fa/confirm", jsonController.Wrap(twoFactorController.ConfirmEnrolment)).Methods("POST")
+	account.HandleFunc("/me/2fa/disable", jsonController.Wrap(twoFactorController.Disable)).Methods("POST")
+	account.HandleFunc("/me/2fa/recovery-codes", jsonController.Wrap(twoFactorController.RegenerateRecoveryCodes)).Methods("POST")
+	account.HandleFunc("/me/webauthn", jsonController.Wrap(webAuthnController.ListCredentials)).Methods("GET")
+	account.HandleFunc("/me/webauthn", jsonController.Wrap(webAuthnController.Register)).Methods("POST")
+	account.HandleFunc("/me/webauthn/options", jsonController.Wrap(webAuthnController.BeginRegistration)).Methods("POST")
+	account.HandleFunc("/me/webauthn/{id}", jsonController.Wrap(webAuthnController.DeleteCredential)).Methods("DELETE")
+	account.HandleFunc("/me/api-keys", jsonController.Wrap(apiKeyController.ListAPIKeys)).Methods("GET")
+	account.HandleFunc("/me/api-keys", jsonController.Wrap(apiKeyController.CreateAPIKey)).Methods("POST")
+	account.HandleFunc("/me/api-keys/{id}", jsonController.Wrap(apiKeyController.RevokeAPIKey)).Methods("DELETE")
+	account.HandleFunc("/me/sessions", jsonController.Wrap(sessionController.ListSessions)).Methods("GET")
+	account.HandleFunc("/me/sessions", jsonController.Wrap(sessionController.RevokeOtherSessions)).Methods("DELETE")
+	account.HandleFunc("/me/sessions/{id}", jsonController.Wrap(sessionController.RevokeSession)).Methods("DELETE")
+
+	rolesManagement := protected.PathPrefix("").Subrouter()
+	rolesManagement.Use(authorizationMiddleware.RequirePermission(rbacservice.PermissionRolesManage))
+	rolesManagement.HandleFunc("/roles", jsonController.Wrap(rbacController.ListRoles)).Methods("GET")
+	rolesManagement.HandleFunc("/roles", jsonController.Wrap(rbacController.CreateRole)).Methods("POST")
+	rolesManagement.HandleFunc("/roles/{id}", jsonController.Wrap(rbacController.GetRole)).Methods("GET")
+	rolesManagement.HandleFunc("/roles/{id}", jsonController.Wrap(rbacController.DeleteRole)).Methods("DELETE")
+	rolesManagement.HandleFunc("/roles/{id}/permissions", jsonController.Wrap(rbacController.GrantPermission)).Methods("POST")
+	rolesManagement.HandleFunc("/roles/{id}/permissions/{permission}", jsonController.Wrap(rbacController.RevokePermission)).Methods("DELETE")
+	rolesManagement.HandleFunc("/users/{id}/roles", jsonController.Wrap(rbacController.GetUserRoles)).Methods("GET")
+	rolesManagement.HandleFunc("/users/{id}/roles", jsonController.Wrap(rbacController.AssignRole)).Methods("POST")
+	rolesManagement.HandleFunc("/users/{id}/roles/{roleID}", jsonController.Wrap(rbacController.UnassignRole)).Methods("DELETE")
+	rolesManagement.HandleFunc("/groups/{id}/roles", jsonController.Wrap(rbacController.GetGroupRoles)).Methods("GET")
+	rolesManagement.HandleFunc("/groups/{id}/roles", jsonController.Wrap(rbacController.AssignGroupRole)).Methods("POST")
+	rolesManagement.HandleFunc("/groups/{id}/roles/{roleID}", jsonController.Wrap(rbacController.UnassignGroupRole)).Methods("DELETE")
+
+	// the group controller checks the permissions and the membership roles itself
+	protected.HandleFunc("/organisations", jsonController.Wrap(groupController.ListOrganisations)).Methods("GET")
+	protected.HandleFunc("/organisations", jsonController.Wrap(groupController.CreateOrganisation)).Methods("POST")
+	protected.HandleFunc("/organisations/{id}", jsonController.Wrap(groupController.GetOrganisation)).Methods("GET")
+	protected.HandleFunc("/organisations/{id}", jsonController.Wrap(groupController.DeleteOrganisation)).Methods("DELETE")
+	protected.HandleFunc("/organisations/{id}/members", jsonController.Wrap(groupController.ListOrganisationMembers)).Methods("GET")
+	protected.HandleFunc("/organisations/{id}/members", jsonController.Wrap(groupController.AddOrganisationMember)).Methods("POST")
+	protected.HandleFunc("/organisations/{id}/members/{userID}", jsonController.Wrap(groupController.RemoveOrganisationMember)).Methods("DELETE")
+	protected.HandleFunc("/groups", jsonController.Wrap(groupController.ListGroups)).Methods("GET")
+	protected.HandleFunc("/groups", jsonController.Wrap(groupController.CreateGroup)).Methods("POST")
+	protected.HandleFunc("/groups/{id}", jsonController.Wrap(groupController.GetGroup)).Methods("GET")
+	protected.HandleFunc("/groups/{id}", jsonController.Wrap(groupController.DeleteGroup)).Methods("DELETE")
+	protected.HandleFunc("/groups/{id}/parent", jsonController.Wrap(groupController.SetGroupParent)).Methods("PUT")
+	protected.HandleFunc("/groups/{id}/members", jsonController.Wrap(groupController.ListGroupMembers)).Methods("GET")
+	protected.HandleFunc("/groups/{id}/members", jsonController.Wrap(groupController.AddGroupMember)).Methods("POST")
+	protected.HandleFunc("/groups/{id}/members/{userID}", jsonController.Wrap(groupController.RemoveGroupMember)).Methods("DELETE")
+
+	usersManagement := protected.PathPrefix("").Subrouter()
+	usersManagement.Use(authorizationMiddleware.RequirePermission(userservice.PermissionUsersManage))
+	usersManagement.HandleFunc("/users", jsonController.Wrap(userController.ListUsers)).Methods("GET")
+	usersManagement.HandleFunc("/users", jsonController.Wrap(userController.CreateUser)).Methods("POST")
+	usersManagement.HandleFunc("/users/{id}", jsonController.Wrap(userController.GetUser)).Methods("GET")
+	usersManagement.HandleFunc("/users/{id}", jsonController.Wrap(userController.UpdateUser)).Methods("PATCH")
+	usersManagement.HandleFunc("/users/{id}", jsonController.Wrap(userController.DeleteUser)).Methods("DELETE")
+	usersManagement.HandleFunc("/users/{id}/password", jsonController.Wrap(userController.SetPassword)).Methods("POST")
+	usersManagement.HandleFunc("/users/{id}/disable", jsonController.Wrap(userController.DisableUser)).Methods("POST")
+	usersManagement.HandleFunc("/users/{id}/enable", jsonController.Wrap(userController.EnableUser)).Methods("POST")
+	usersManagement.HandleFunc("/users/{id}/unlock", jsonController.Wrap(userController.UnlockUser)).Methods("POST")
+	usersManagement.HandleFunc("/users/{id}/2fa", jsonController.Wrap(twoFactorController.ResetTwoFactor)).Methods("DELETE")
+	usersManagement.HandleFunc("/users/{id}/sessions", jsonController.Wrap(sessionController.ListUserSessions)).Methods("GET")
+	usersManagement.HandleFunc("/users/{id}/sessions", jsonController.Wrap(sessionController.RevokeUserSessions)).Methods("DELETE")
+	usersManagement.HandleFunc("/users/{id}/sessions/{sessionID}", jsonController.Wrap(sessionController.RevokeUserSession)).Methods("DELETE")
+
+	account.HandleFunc("/oauth/authorize", jsonController.Wrap(oauthController.Authorize)).Methods("GET")
+	account.HandleFunc("/oauth/authorize", jsonController.Wrap(oauthController.Consent)).Methods("POST")
+
+	clientsManagement := protected.PathPrefix("").Subrouter()
+	clientsManagement.Use(authorizationMiddleware.RequirePermission(oauthservice.PermissionClientsManage))
+	clientsManagement.HandleFunc("/oauth/clients", jsonController.Wrap(oauthController.ListClients)).Methods("GET")
+	clientsManagement.HandleFunc("/oauth/clients", jsonController.Wrap(oauthController.CreateClient)).Methods("POST")
+	clientsManagement.HandleFunc("/oauth/clients/{id}", jsonController.Wrap(oauthController.GetClient)).Methods("GET")
+	clientsManagement.HandleFunc("/oauth/clients/{id}", jsonController.Wrap(oauthController.DeleteClient)).Methods("DELETE")
 
-	protected.HandleFunc("/logout", jsonController.Wrap(basicAuthentificationController.Logout)).Methods("GET")
+	policiesManagement := protected.PathPrefix("").Subrouter()
+	policiesManagement.Use(authorizationMiddleware.RequirePermission(policyservice.PermissionPoliciesManage))
+	policiesManagement.HandleFunc("/policies", jsonController.Wrap(policyController.ListPolicies)).Methods("GET")
+	policiesManagement.HandleFunc("/policies", jsonController.Wrap(policyController.CreatePolicy)).Methods("POST")
+	policiesManagement.HandleFunc("/policies/{id}", jsonController.Wrap(policyController.DeletePolicy)).Methods("DELETE")
 
 	return router
 }
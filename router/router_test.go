@@ -2,6 +2,7 @@ package router
 
 import (
 	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	controllersMocks "github.com/ditrit/badaas/mocks/controllers"
@@ -10,14 +11,85 @@ import (
 	"github.com/stretchr/testify/mock"
 )
 
-func TestSetupRouter(t *testing.T) {
+// Return the router with mocked controllers and middlewares
+func setupTestRouter(
+	t *testing.T,
+	clientIPMiddleware *middlewaresMocks.ClientIPMiddleware,
+	middlewareLogger *middlewaresMocks.MiddlewareLogger,
+	csrfMiddleware *middlewaresMocks.CSRFMiddleware,
+) http.Handler {
 	jsonController := middlewaresMocks.NewJSONController(t)
-	middlewareLogger := middlewaresMocks.NewMiddlewareLogger(t)
 	authenticationMiddleware := middlewaresMocks.NewAuthenticationMiddleware(t)
+	authenticationMiddleware.On("Accept", mock.Anything, mock.Anything).Return(func(next http.Handler) http.Handler { return next })
+	authorizationMiddleware := middlewaresMocks.NewAuthorizationMiddleware(t)
+	authorizationMiddleware.On("RequirePermission", mock.Anything).Return(func(next http.Handler) http.Handler { return next })
 
 	basicController := controllersMocks.NewBasicAuthentificationController(t)
 	informationController := controllersMocks.NewInformationController(t)
+	rbacController := controllersMocks.NewRBACController(t)
+	policyController := controllersMocks.NewPolicyController(t)
+	groupController := controllersMocks.NewGroupController(t)
+	userController := controllersMocks.NewUserController(t)
+	accountController := controllersMocks.NewAccountController(t)
+	registrationController := controllersMocks.NewRegistrationController(t)
+	emailVerificationController := controllersMocks.NewEmailVerificationController(t)
+	passwordResetController := controllersMocks.NewPasswordResetController(t)
+	twoFactorController := controllersMocks.NewTwoFactorController(t)
+	webAuthnController := controllersMocks.NewWebAuthnController(t)
+	oidcController := controllersMocks.NewOIDCController(t)
+	samlController := controllersMocks.NewSAMLController(t)
+	oauthController := controllersMocks.NewOAuthController(t)
+	apiKeyController := controllersMocks.NewAPIKeyController(t)
+	sessionController := controllersMocks.NewSessionController(t)
 	jsonController.On("Wrap", mock.Anything).Return(func(response http.ResponseWriter, request *http.Request) {})
-	router := SetupRouter(jsonController, middlewareLogger, authenticationMiddleware, basicController, informationController)
+	return SetupRouter(
+		jsonController,
+		clientIPMiddleware,
+		middlewareLogger,
+		authenticationMiddleware,
+		authorizationMiddleware,
+		csrfMiddleware,
+		basicController,
+		informationController,
+		rbacController,
+		policyController,
+		groupController,
+		userController,
+		accountController,
+		registrationController,
+		emailVerificationController,
+		passwordResetController,
+		twoFactorController,
+		webAuthnController,
+		oidcController,
+		samlController,
+		oauthController,
+		apiKeyController,
+		sessionController,
+	)
+}
+
+func TestSetupRouter(t *testing.T) {
+	router := setupTestRouter(t, middlewaresMocks.NewClientIPMiddleware(t), middlewaresMocks.NewMiddlewareLogger(t),
+		middlewaresMocks.NewCSRFMiddleware(t))
 	assert.NotNil(t, router)
 }
+
+func TestSetupRouterRefreshNeedsCSRFToken(t *testing.T) {
+	passThrough := func(next http.Handler) http.Handler { return next }
+	clientIPMiddleware := middlewaresMocks.NewClientIPMiddleware(t)
+	clientIPMiddleware.On("Handle", mock.Anything).Return(passThrough)
+	middlewareLogger := middlewaresMocks.NewMiddlewareLogger(t)
+	middlewareLogger.On("Handle", mock.Anything).Return(passThrough)
+	csrfMiddleware := middlewaresMocks.NewCSRFMiddleware(t)
+	csrfMiddleware.On("Handle", mock.Anything).Return(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+		})
+	})
+	router := setupTestRouter(t, clientIPMiddleware, middlewareLogger, csrfMiddleware)
+	response := httptest.NewRecorder()
+
+	router.ServeHTTP(response, httptest.NewRequest("POST", "/session/refresh", nil))
+	assert.Equal(t, http.StatusForbidden, response.Code)
+}
@@ -0,0 +1,198 @@
+package apikeyservice
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/ditrit/badaas/httperrors"
+	"github.com/ditrit/badaas/persistence/models"
+	"github.com/ditrit/badaas/persistence/models/dto"
+	"github.com/ditrit/badaas/persistence/repository"
+	"github.com/ditrit/badaas/services/sessionservice"
+	"github.com/ditrit/badaas/services/userservice"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// The prefix of the API keys, it lets the secret scanners recognize them
+const KeyPrefix = "badaas_"
+
+const (
+	// The number of random bytes of the keys
+	keySize = 32
+	// The number of random characters of the key kept in its prefix
+	prefixSize = 6
+	// The last use of a key is only recorded once during this interval, not on every request
+	lastUsedUpdateInterval = time.Minute
+)
+
+// Errors
+var (
+	HERRInvalidAPIKey = httperrors.NewUnauthorizedError("Authentification Error", "the API key is invalid or expired")
+	// An API key can't create other keys, which could have more scopes
+	HERRAPIKeyNotAllowed = httperrors.NewForbiddenError("api key refused", "the API keys must be managed with a session")
+	// The account and the credentials of a user can't be managed with its API keys, whatever their scopes
+	HERRAPIKeyRefused = httperrors.NewForbiddenError("api key refused", "this route can only be used with a session")
+)
+
+// APIKeyService handles the personal access tokens of the users
+type APIKeyService interface {
+	// Create a key for the user, return it with its value, which is not stored and can't be shown again
+	CreateAPIKey(userID uuid.UUID, createAPIKeyDTO dto.DTOCreateAPIKey) (*models.APIKey, string, httperrors.HTTPError)
+	GetAPIKeys(userID uuid.UUID) ([]*models.APIKey, httperrors.HTTPError)
+	RevokeAPIKey(userID, apiKeyID uuid.UUID) httperrors.HTTPError
+	// Return the claims of a request authenticated with the key
+	Authenticate(key string) (*sessionservice.SessionClaims, httperrors.HTTPError)
+}
+
+// Check interface compliance
+var _ APIKeyService = (*apiKeyServiceImpl)(nil)
+
+// APIKeyService implementation
+type apiKeyServiceImpl struct {
+	logger           *zap.Logger
+	apiKeyRepository repository.CRUDRepository[models.APIKey, uuid.UUID]
+	userService      userservice.UserService
+}
+
+// APIKeyService constructor
+func NewAPIKeyService(
+	logger *zap.Logger,
+	apiKeyRepository repository.CRUDRepository[models.APIKey, uuid.UUID],
+	userService userservice.UserService,
+) APIKeyService {
+	return &apiKeyServiceImpl{
+		logger:           logger,
+		apiKeyRepository: apiKeyRepository,
+		userService:      userService,
+	}
+}
+
+// Create a key for the user, return it with its value, which is not stored and can't be shown again
+func (apiKeyService *apiKeyServiceImpl) CreateAPIKey(
+	userID uuid.UUID,
+	createAPIKeyDTO dto.DTOCreateAPIKey,
+) (*models.APIKey, string, httperrors.HTTPError) {
+	name := strings.TrimSpace(createAPIKeyDTO.Name)
+	if name == "" {
+		return nil, "", httperrors.NewHTTPError(http.StatusBadRequest, "invalid api key",
+			"the name of the key is required", nil, false)
+	}
+	if createAPIKeyDTO.ExpiresAt != nil && !createAPIKeyDTO.ExpiresAt.After(time.Now()) {
+		return nil, "", httperrors.NewHTTPError(http.StatusBadRequest, "invalid api key",
+			"the expiration date must be in the future", nil, false)
+	}
+	key, err := generateKey()
+	if err != nil {
+		return nil, "", httperrors.NewInternalServerError("api key error", "failed to generate the key", err)
+	}
+	apiKey := &models.APIKey{
+		UserID:    userID,
+		Name:      name,
+		Prefix:    key[:len(KeyPrefix)+prefixSize],
+		KeyHash:   hashKey(key),
+		Scope:     strings.Join(strings.Fields(strings.Join(createAPIKeyDTO.Scopes, " ")), " "),
+		ExpiresAt: createAPIKeyDTO.ExpiresAt,
+	}
+	herr := apiKeyService.apiKeyRepository.Create(apiKey)
+	if herr != nil {
+		return nil, "", herr
+	}
+	apiKeyService.logger.Info("Created an API key",
+		zap.String("userID", userID.String()), zap.String("apiKeyID", apiKey.ID.String()))
+	return apiKey, key, nil
+}
+
+// Return the keys of the user
+func (apiKeyService *apiKeyServiceImpl) GetAPIKeys(userID uuid.UUID) ([]*models.APIKey, httperrors.HTTPError) {
+	apiKeys, herr := apiKeyService.apiKeyRepository.Find(squirrel.Eq{"user_id": userID.String()}, nil, nil)
+	if herr != nil {
+		return nil, herr
+	}
+	return apiKeys.Ressources, nil
+}
+
+// Delete a key of the user
+func (apiKeyService *apiKeyServiceImpl) RevokeAPIKey(userID, apiKeyID uuid.UUID) httperrors.HTTPError {
+	apiKey, herr := apiKeyService.apiKeyRepository.GetByID(apiKeyID)
+	if herr != nil {
+		return herr
+	}
+	if apiKey.UserID != userID {
+		return httperrors.NewErrorNotFound("api key", fmt.Sprintf("no api key found with id %q", apiKeyID))
+	}
+	herr = apiKeyService.apiKeyRepository.Delete(apiKey)
+	if herr != nil {
+		return herr
+	}
+	apiKeyService.logger.Info("Revoked an API key",
+		zap.String("userID", userID.String()), zap.String("apiKeyID", apiKeyID.String()))
+	return nil
+}
+
+// Return the claims of a request authenticated with the key
+//
+// The keys of the users who can't log in anymore are refused.
+func (apiKeyService *apiKeyServiceImpl) Authenticate(key string) (*sessionservice.SessionClaims, httperrors.HTTPError) {
+	if !strings.HasPrefix(key, KeyPrefix) {
+		return nil, HERRInvalidAPIKey
+	}
+	apiKeys, herr := apiKeyService.apiKeyRepository.Find(squirrel.Eq{"key_hash": hashKey(key)}, nil, nil)
+	if herr != nil {
+		return nil, herr
+	}
+	if !apiKeys.HasContent {
+		return nil, HERRInvalidAPIKey
+	}
+	apiKey := apiKeys.Ressources[0]
+	if apiKey.IsExpired() {
+		return nil, HERRInvalidAPIKey
+	}
+	user, herr := apiKeyService.userService.GetUserByID(apiKey.UserID)
+	if herr != nil {
+		return nil, HERRInvalidAPIKey
+	}
+	if apiKeyService.userService.CheckCanLogIn(user) != nil {
+		return nil, HERRInvalidAPIKey
+	}
+	now := time.Now()
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) > lastUsedUpdateInterval {
+		apiKey.LastUsedAt = &now
+		herr = apiKeyService.apiKeyRepository.Save(apiKey)
+		if herr != nil {
+			// the request is still authenticated
+			apiKeyService.logger.Warn("Failed to record the use of an API key",
+				zap.String("apiKeyID", apiKey.ID.String()), zap.Error(herr))
+		}
+	}
+	return &sessionservice.SessionClaims{
+		UserID:   apiKey.UserID,
+		APIKeyID: apiKey.ID,
+		Scopes:   apiKey.GetScopes(),
+	}, nil
+}
+
+// Generate a random key
+func generateKey() (string, error) {
+	b := make([]byte, keySize)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return KeyPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Hash a key, only the hash of the keys are stored
+//
+// The keys are random, a fast hash is enough, unlike for the passwords.
+func hashKey(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(hash[:])
+}
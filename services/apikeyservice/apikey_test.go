@@ -0,0 +1,124 @@
+package apikeyservice_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	mocksRepository "github.com/ditrit/badaas/mocks/persistence/repository"
+	mocksUserService "github.com/ditrit/badaas/mocks/services/userservice"
+	"github.com/ditrit/badaas/persistence/models"
+	"github.com/ditrit/badaas/persistence/models/dto"
+	"github.com/ditrit/badaas/persistence/pagination"
+	"github.com/ditrit/badaas/services/apikeyservice"
+	"github.com/ditrit/badaas/services/userservice"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestCreateAPIKey(t *testing.T) {
+	apiKeyRepository := mocksRepository.NewCRUDRepository[models.APIKey, uuid.UUID](t)
+	apiKeyRepository.On("Create", mock.Anything).Return(nil)
+	service := apikeyservice.NewAPIKeyService(zap.NewNop(), apiKeyRepository, mocksUserService.NewUserService(t))
+	userID := uuid.New()
+	expiresAt := time.Now().Add(time.Hour)
+
+	apiKey, key, herr := service.CreateAPIKey(userID, dto.DTOCreateAPIKey{
+		Name: " ci ", Scopes: []string{"users:manage", " roles:* "}, ExpiresAt: &expiresAt,
+	})
+	require.Nil(t, herr)
+	assert.True(t, strings.HasPrefix(key, apikeyservice.KeyPrefix))
+	assert.True(t, strings.HasPrefix(key, apiKey.Prefix))
+	assert.NotContains(t, apiKey.KeyHash, key)
+	assert.Equal(t, userID, apiKey.UserID)
+	assert.Equal(t, "ci", apiKey.Name)
+	assert.Equal(t, []string{"users:manage", "roles:*"}, apiKey.GetScopes())
+	assert.Equal(t, &expiresAt, apiKey.ExpiresAt)
+}
+
+func TestCreateAPIKeyInvalid(t *testing.T) {
+	service := apikeyservice.NewAPIKeyService(zap.NewNop(),
+		mocksRepository.NewCRUDRepository[models.APIKey, uuid.UUID](t), mocksUserService.NewUserService(t))
+	expiresAt := time.Now().Add(-time.Hour)
+
+	_, _, herr := service.CreateAPIKey(uuid.New(), dto.DTOCreateAPIKey{Name: " "})
+	assert.NotNil(t, herr)
+	_, _, herr = service.CreateAPIKey(uuid.New(), dto.DTOCreateAPIKey{Name: "ci", ExpiresAt: &expiresAt})
+	assert.NotNil(t, herr)
+}
+
+// Create a key with the service and return its value with the stored key
+func createKey(t *testing.T, service apikeyservice.APIKeyService,
+	apiKeyRepository *mocksRepository.CRUDRepository[models.APIKey, uuid.UUID],
+	userID uuid.UUID, expiresAt *time.Time,
+) (string, *models.APIKey) {
+	var apiKey *models.APIKey
+	apiKeyRepository.On("Create", mock.Anything).Run(func(args mock.Arguments) {
+		apiKey = args.Get(0).(*models.APIKey)
+		apiKey.ID = uuid.New()
+	}).Return(nil).Once()
+	_, key, herr := service.CreateAPIKey(userID, dto.DTOCreateAPIKey{Name: "ci", Scopes: []string{"users:manage"}})
+	require.Nil(t, herr)
+	apiKey.ExpiresAt = expiresAt
+	apiKeyRepository.On("Find", squirrel.Eq{"key_hash": apiKey.KeyHash}, nil, nil).
+		Return(pagination.NewPage([]*models.APIKey{apiKey}, 1, 10, 1), nil)
+	return key, apiKey
+}
+
+func TestAuthenticate(t *testing.T) {
+	apiKeyRepository := mocksRepository.NewCRUDRepository[models.APIKey, uuid.UUID](t)
+	userService := mocksUserService.NewUserService(t)
+	service := apikeyservice.NewAPIKeyService(zap.NewNop(), apiKeyRepository, userService)
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}}
+	userService.On("GetUserByID", user.ID).Return(user, nil)
+	userService.On("CheckCanLogIn", user).Return(nil)
+	key, apiKey := createKey(t, service, apiKeyRepository, user.ID, nil)
+	apiKeyRepository.On("Save", apiKey).Return(nil).Once()
+
+	sessionClaims, herr := service.Authenticate(key)
+	require.Nil(t, herr)
+	assert.Equal(t, user.ID, sessionClaims.UserID)
+	assert.Equal(t, apiKey.ID, sessionClaims.APIKeyID)
+	assert.Equal(t, []string{"users:manage"}, sessionClaims.Scopes)
+	assert.NotNil(t, apiKey.LastUsedAt)
+
+	// the last use is not recorded again right away
+	_, herr = service.Authenticate(key)
+	assert.Nil(t, herr)
+}
+
+func TestAuthenticateRefused(t *testing.T) {
+	apiKeyRepository := mocksRepository.NewCRUDRepository[models.APIKey, uuid.UUID](t)
+	userService := mocksUserService.NewUserService(t)
+	service := apikeyservice.NewAPIKeyService(zap.NewNop(), apiKeyRepository, userService)
+	disabledUser := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Disabled: true}
+	userService.On("GetUserByID", disabledUser.ID).Return(disabledUser, nil)
+	userService.On("CheckCanLogIn", disabledUser).Return(userservice.HERRUserDisabled)
+	expiredAt := time.Now().Add(-time.Minute)
+	expiredKey, _ := createKey(t, service, apiKeyRepository, uuid.New(), &expiredAt)
+	disabledUserKey, _ := createKey(t, service, apiKeyRepository, disabledUser.ID, nil)
+	apiKeyRepository.On("Find", mock.Anything, nil, nil).Return(pagination.NewPage([]*models.APIKey{}, 1, 10, 0), nil)
+
+	for _, key := range []string{"no-prefix", apikeyservice.KeyPrefix + "unknown", expiredKey, disabledUserKey} {
+		_, herr := service.Authenticate(key)
+		assert.Equal(t, apikeyservice.HERRInvalidAPIKey, herr, key)
+	}
+}
+
+func TestRevokeAPIKeyOfAnotherUser(t *testing.T) {
+	apiKeyRepository := mocksRepository.NewCRUDRepository[models.APIKey, uuid.UUID](t)
+	service := apikeyservice.NewAPIKeyService(zap.NewNop(), apiKeyRepository, mocksUserService.NewUserService(t))
+	apiKey := &models.APIKey{BaseModel: models.BaseModel{ID: uuid.New()}, UserID: uuid.New()}
+	apiKeyRepository.On("GetByID", apiKey.ID).Return(apiKey, nil)
+
+	herr := service.RevokeAPIKey(uuid.New(), apiKey.ID)
+	require.NotNil(t, herr)
+	assert.Contains(t, herr.Error(), "not found")
+
+	apiKeyRepository.On("Delete", apiKey).Return(nil)
+	assert.Nil(t, service.RevokeAPIKey(apiKey.UserID, apiKey.ID))
+}
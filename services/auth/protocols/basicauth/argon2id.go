@@ -0,0 +1,113 @@
+package basicauth
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+const (
+	argon2idSaltLength = 16
+	argon2idKeyLength  = 32
+)
+
+// The cost parameters of argon2id (see [golang.org/x/crypto/argon2.IDKey])
+type Argon2idParameters struct {
+	// memory in KiB
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+}
+
+// Check interface compliance
+var _ PasswordHasher = (*argon2idHasher)(nil)
+
+// A PasswordHasher using argon2id
+//
+// The hashes use the PHC string format: $argon2id$v=19$m=<memory>,t=<iterations>,p=<parallelism>$<salt>$<key>
+type argon2idHasher struct {
+	parameters Argon2idParameters
+}
+
+// Create a PasswordHasher using argon2id with the parameters
+func NewArgon2idHasher(parameters Argon2idParameters) (PasswordHasher, error) {
+	if parameters.Memory == 0 || parameters.Iterations == 0 || parameters.Parallelism == 0 {
+		return nil, errors.New("the argon2id parameters must be greater than 0")
+	}
+	return &argon2idHasher{parameters: parameters}, nil
+}
+
+// Salt and hash the password
+func (hasher *argon2idHasher) Hash(password string) ([]byte, error) {
+	salt := make([]byte, argon2idSaltLength)
+	_, err := rand.Read(salt)
+	if err != nil {
+		return nil, err
+	}
+	key := argon2.IDKey([]byte(password), salt,
+		hasher.parameters.Iterations, hasher.parameters.Memory, hasher.parameters.Parallelism, argon2idKeyLength)
+	return []byte(fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version,
+		hasher.parameters.Memory, hasher.parameters.Iterations, hasher.parameters.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	)), nil
+}
+
+// Check if the password matches the hash, the parameters of the hash are used
+func (hasher *argon2idHasher) Verify(hash []byte, password string) bool {
+	parameters, salt, key, err := parseArgon2idHash(hash)
+	if err != nil {
+		return false
+	}
+	otherKey := argon2.IDKey([]byte(password), salt,
+		parameters.Iterations, parameters.Memory, parameters.Parallelism, uint32(len(key)))
+	return subtle.ConstantTimeCompare(key, otherKey) == 1
+}
+
+// Return true if the hash is not an argon2id hash with the parameters of the hasher
+func (hasher *argon2idHasher) NeedsRehash(hash []byte) bool {
+	parameters, _, key, err := parseArgon2idHash(hash)
+	if err != nil {
+		return true
+	}
+	return parameters != hasher.parameters || len(key) != argon2idKeyLength
+}
+
+// Parse an argon2id hash in the PHC string format
+func parseArgon2idHash(hash []byte) (Argon2idParameters, []byte, []byte, error) {
+	var parameters Argon2idParameters
+	parts := strings.Split(string(hash), "$")
+	if len(parts) != 6 || parts[0] != "" || parts[1] != "argon2id" {
+		return parameters, nil, nil, errors.New("not an argon2id hash")
+	}
+	var version int
+	_, err := fmt.Sscanf(parts[2], "v=%d", &version)
+	if err != nil {
+		return parameters, nil, nil, err
+	}
+	if version != argon2.Version {
+		return parameters, nil, nil, fmt.Errorf("unsupported argon2id version %d", version)
+	}
+	_, err = fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &parameters.Memory, &parameters.Iterations, &parameters.Parallelism)
+	if err != nil {
+		return parameters, nil, nil, err
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return parameters, nil, nil, err
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return parameters, nil, nil, err
+	}
+	if parameters.Iterations == 0 || parameters.Parallelism == 0 || len(key) == 0 {
+		return parameters, nil, nil, errors.New("invalid argon2id hash")
+	}
+	return parameters, salt, key, nil
+}
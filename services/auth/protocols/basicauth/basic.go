@@ -1,23 +1,100 @@
 package basicauth
 
 import (
+	"errors"
+	"fmt"
+
+	"github.com/ditrit/badaas/configuration"
 	"golang.org/x/crypto/bcrypt"
 )
 
-// The bcrypt hashing cost (see [golang.org/x/crypto/bcrypt.Cost])
-const cost = bcrypt.DefaultCost
+// Returned when a password can't be hashed by the algorithm without being truncated
+var ErrPasswordTooLong = errors.New("the password is too long")
+
+// PasswordHasher salt and hash the passwords
+//
+// The hashes identify their algorithm and parameters so they can be checked after a change of configuration.
+type PasswordHasher interface {
+	// Salt and hash the password
+	Hash(password string) ([]byte, error)
+	// Check if the password matches the hash
+	Verify(hash []byte, password string) bool
+	// Return true if the hash was not made with the current algorithm and parameters
+	NeedsRehash(hash []byte) bool
+}
+
+// Check interface compliance
+var _ PasswordHasher = (*passwordHasherImpl)(nil)
+
+// A PasswordHasher hashing with the configured algorithm and checking the hashes of every algorithm
+type passwordHasherImpl struct {
+	hasher PasswordHasher
+	// the hashers of the other algorithms, to check the hashes made before a change of algorithm
+	otherHashers []PasswordHasher
+}
+
+// Create the PasswordHasher of the algorithm selected in the configuration
+func NewPasswordHasher(passwordHashingConfiguration configuration.PasswordHashingConfiguration) (PasswordHasher, error) {
+	argon2idHasher, err := NewArgon2idHasher(Argon2idParameters{
+		Memory:      passwordHashingConfiguration.GetArgon2idMemory(),
+		Iterations:  passwordHashingConfiguration.GetArgon2idIterations(),
+		Parallelism: passwordHashingConfiguration.GetArgon2idParallelism(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	bcryptHasher, err := NewBcryptHasher(passwordHashingConfiguration.GetBcryptCost())
+	if err != nil {
+		return nil, err
+	}
+	switch passwordHashingConfiguration.GetAlgorithm() {
+	case configuration.PasswordHashingArgon2id:
+		return &passwordHasherImpl{hasher: argon2idHasher, otherHashers: []PasswordHasher{bcryptHasher}}, nil
+	case configuration.PasswordHashingBcrypt:
+		return &passwordHasherImpl{hasher: bcryptHasher, otherHashers: []PasswordHasher{argon2idHasher}}, nil
+	default:
+		return nil, fmt.Errorf("unknown password hashing algorithm %q", passwordHashingConfiguration.GetAlgorithm())
+	}
+}
+
+// Salt and hash the password with the configured algorithm
+func (passwordHasher *passwordHasherImpl) Hash(password string) ([]byte, error) {
+	return passwordHasher.hasher.Hash(password)
+}
+
+// Check if the password matches the hash, whatever its algorithm
+func (passwordHasher *passwordHasherImpl) Verify(hash []byte, password string) bool {
+	if passwordHasher.hasher.Verify(hash, password) {
+		return true
+	}
+	for _, otherHasher := range passwordHasher.otherHashers {
+		if otherHasher.Verify(hash, password) {
+			return true
+		}
+	}
+	return false
+}
+
+// Return true if the hash was not made with the configured algorithm and parameters
+func (passwordHasher *passwordHasherImpl) NeedsRehash(hash []byte) bool {
+	return passwordHasher.hasher.NeedsRehash(hash)
+}
+
+// The bcrypt hasher of SaltAndHashPassword and CheckUserPassword
+var defaultBcryptHasher = &bcryptHasher{cost: bcrypt.DefaultCost}
 
-// Salt and hash the password
+// Salt and hash the password with bcrypt
+//
+// Deprecated: use a PasswordHasher, created by NewPasswordHasher from the configuration.
 func SaltAndHashPassword(password string) []byte {
-	// bcrypt.GenerateFromPassword only return an error is the cost is not between 4 and 31, with cost=bcrypt.DefaultCost we are safe
-	bytes, _ := bcrypt.GenerateFromPassword(
-		[]byte(password),
-		cost,
-	)
-	return bytes
+	// the error of a password too long for bcrypt is ignored, as before the PasswordHasher
+	hash, _ := defaultBcryptHasher.Hash(password)
+	return hash
 }
 
-// Check if the password is valid
+// Check if the password matches the bcrypt hash
+//
+// Deprecated: use a PasswordHasher, created by NewPasswordHasher from the configuration.
 func CheckUserPassword(passwordHash []byte, passwordToCheck string) bool {
-	return bcrypt.CompareHashAndPassword(passwordHash, []byte(passwordToCheck)) == nil
+	return defaultBcryptHasher.Verify(passwordHash, passwordToCheck)
 }
@@ -1,12 +1,123 @@
 package basicauth_test
 
 import (
+	"strings"
 	"testing"
 
+	"github.com/ditrit/badaas/configuration"
+	mocksConfiguration "github.com/ditrit/badaas/mocks/configuration"
 	"github.com/ditrit/badaas/services/auth/protocols/basicauth"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
 )
 
+// Cheap argon2id parameters to keep the tests fast
+var testArgon2idParameters = basicauth.Argon2idParameters{Memory: 64, Iterations: 1, Parallelism: 1}
+
+func newPasswordHashingConfiguration(t *testing.T, algorithm string) *mocksConfiguration.PasswordHashingConfiguration {
+	passwordHashingConfiguration := mocksConfiguration.NewPasswordHashingConfiguration(t)
+	passwordHashingConfiguration.On("GetAlgorithm").Return(algorithm).Maybe()
+	passwordHashingConfiguration.On("GetArgon2idMemory").Return(testArgon2idParameters.Memory)
+	passwordHashingConfiguration.On("GetArgon2idIterations").Return(testArgon2idParameters.Iterations)
+	passwordHashingConfiguration.On("GetArgon2idParallelism").Return(testArgon2idParameters.Parallelism)
+	passwordHashingConfiguration.On("GetBcryptCost").Return(bcrypt.MinCost)
+	return passwordHashingConfiguration
+}
+
+func TestArgon2idHasher(t *testing.T) {
+	hasher, err := basicauth.NewArgon2idHasher(testArgon2idParameters)
+	require.NoError(t, err)
+
+	hash, err := hasher.Hash("voila")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(hash), "$argon2id$v=19$m=64,t=1,p=1$"), string(hash))
+	assert.True(t, hasher.Verify(hash, "voila"))
+	assert.False(t, hasher.Verify(hash, "wrong password"))
+	assert.False(t, hasher.NeedsRehash(hash))
+
+	otherHash, err := hasher.Hash("voila")
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, otherHash, "the hashes should be salted")
+}
+
+func TestArgon2idHasherNeedsRehash(t *testing.T) {
+	hasher, err := basicauth.NewArgon2idHasher(testArgon2idParameters)
+	require.NoError(t, err)
+	strongerHasher, err := basicauth.NewArgon2idHasher(basicauth.Argon2idParameters{Memory: 128, Iterations: 2, Parallelism: 1})
+	require.NoError(t, err)
+
+	hash, err := hasher.Hash("voila")
+	require.NoError(t, err)
+	// the parameters of the hash are used to verify it
+	assert.True(t, strongerHasher.Verify(hash, "voila"))
+	assert.True(t, strongerHasher.NeedsRehash(hash))
+	assert.True(t, hasher.NeedsRehash([]byte("not a hash")))
+}
+
+func TestArgon2idHasherInvalidParameters(t *testing.T) {
+	hasher, err := basicauth.NewArgon2idHasher(basicauth.Argon2idParameters{Memory: 64, Iterations: 0, Parallelism: 1})
+	assert.Error(t, err)
+	assert.Nil(t, hasher)
+}
+
+func TestBcryptHasher(t *testing.T) {
+	hasher, err := basicauth.NewBcryptHasher(bcrypt.MinCost)
+	require.NoError(t, err)
+
+	hash, err := hasher.Hash("voila")
+	require.NoError(t, err)
+	assert.True(t, hasher.Verify(hash, "voila"))
+	assert.False(t, hasher.Verify(hash, "wrong password"))
+	assert.False(t, hasher.NeedsRehash(hash))
+
+	strongerHasher, err := basicauth.NewBcryptHasher(bcrypt.MinCost + 1)
+	require.NoError(t, err)
+	assert.True(t, strongerHasher.NeedsRehash(hash))
+}
+
+func TestBcryptHasherPasswordTooLong(t *testing.T) {
+	hasher, err := basicauth.NewBcryptHasher(bcrypt.MinCost)
+	require.NoError(t, err)
+
+	hash, err := hasher.Hash(strings.Repeat("a", 73))
+	assert.ErrorIs(t, err, basicauth.ErrPasswordTooLong)
+	assert.Nil(t, hash)
+}
+
+func TestBcryptHasherInvalidCost(t *testing.T) {
+	hasher, err := basicauth.NewBcryptHasher(bcrypt.MaxCost + 1)
+	assert.Error(t, err)
+	assert.Nil(t, hasher)
+}
+
+func TestPasswordHasherChangeOfAlgorithm(t *testing.T) {
+	bcryptHasher, err := basicauth.NewPasswordHasher(newPasswordHashingConfiguration(t, configuration.PasswordHashingBcrypt))
+	require.NoError(t, err)
+	argon2idHasher, err := basicauth.NewPasswordHasher(newPasswordHashingConfiguration(t, configuration.PasswordHashingArgon2id))
+	require.NoError(t, err)
+
+	bcryptHash, err := bcryptHasher.Hash("voila")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(bcryptHash), "$2a$"))
+	// the hashes of the previous algorithm are still valid but must be replaced
+	assert.True(t, argon2idHasher.Verify(bcryptHash, "voila"))
+	assert.False(t, argon2idHasher.Verify(bcryptHash, "wrong password"))
+	assert.True(t, argon2idHasher.NeedsRehash(bcryptHash))
+
+	argon2idHash, err := argon2idHasher.Hash("voila")
+	require.NoError(t, err)
+	assert.True(t, bcryptHasher.Verify(argon2idHash, "voila"))
+	assert.True(t, bcryptHasher.NeedsRehash(argon2idHash))
+	assert.False(t, argon2idHasher.NeedsRehash(argon2idHash))
+}
+
+func TestPasswordHasherUnknownAlgorithm(t *testing.T) {
+	hasher, err := basicauth.NewPasswordHasher(newPasswordHashingConfiguration(t, "md5"))
+	assert.Error(t, err)
+	assert.Nil(t, hasher)
+}
+
 func TestSaltAndHashPassword(t *testing.T) {
 	password := "password"
 	hash := basicauth.SaltAndHashPassword(password)
@@ -18,5 +129,4 @@ func TestCheckUserPassword(t *testing.T) {
 	hash := basicauth.SaltAndHashPassword(password)
 	assert.True(t, basicauth.CheckUserPassword(hash, password), "the password and it's hash should match")
 	assert.False(t, basicauth.CheckUserPassword(hash, "wrong password"), "the password and it's hash should match")
-
 }
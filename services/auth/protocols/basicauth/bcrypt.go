@@ -0,0 +1,47 @@
+package basicauth
+
+import (
+	"fmt"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// bcrypt only uses the first 72 bytes of the password
+const bcryptMaxPasswordLength = 72
+
+// Check interface compliance
+var _ PasswordHasher = (*bcryptHasher)(nil)
+
+// A PasswordHasher using bcrypt
+//
+// The hashes use the modular crypt format: $2a$<cost>$<salt and key>
+type bcryptHasher struct {
+	cost int
+}
+
+// Create a PasswordHasher using bcrypt with the cost
+func NewBcryptHasher(cost int) (PasswordHasher, error) {
+	if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		return nil, fmt.Errorf("the bcrypt cost must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+	}
+	return &bcryptHasher{cost: cost}, nil
+}
+
+// Salt and hash the password, return ErrPasswordTooLong instead of truncating the password
+func (hasher *bcryptHasher) Hash(password string) ([]byte, error) {
+	if len(password) > bcryptMaxPasswordLength {
+		return nil, ErrPasswordTooLong
+	}
+	return bcrypt.GenerateFromPassword([]byte(password), hasher.cost)
+}
+
+// Check if the password matches the hash
+func (hasher *bcryptHasher) Verify(hash []byte, password string) bool {
+	return bcrypt.CompareHashAndPassword(hash, []byte(password)) == nil
+}
+
+// Return true if the hash is not a bcrypt hash with the cost of the hasher
+func (hasher *bcryptHasher) NeedsRehash(hash []byte) bool {
+	cost, err := bcrypt.Cost(hash)
+	return err != nil || cost != hasher.cost
+}
@@ -0,0 +1,239 @@
+// Package jwt signs and verifies the JWT access tokens of the stateless sessions (RFC 7519),
+// with a set of keys identified by their kid so that they can be rotated.
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-jose/go-jose/v3"
+	josejwt "github.com/go-jose/go-jose/v3/jwt"
+)
+
+// The issuer of the tokens
+const Issuer = "badaas"
+
+// The minimum size of the RSA keys
+const minRSAKeySize = 2048
+
+// Returned when a token is malformed, has a wrong signature or is expired
+var ErrInvalidToken = errors.New("jwt invalid token")
+
+// The claims of an access token
+type Claims struct {
+	// The id of the user
+	Subject string
+	// The id of the session the token was issued for
+	SessionID string
+	// The hash of the client the session is bound to, empty if the session is not bound
+	Fingerprint string
+	IssuedAt    time.Time
+	ExpiresAt   time.Time
+}
+
+// The claims specific to badaas
+type privateClaims struct {
+	SessionID   string `json:"sid"`
+	Fingerprint string `json:"cfp,omitempty"`
+}
+
+// A signing key and its id
+type Key struct {
+	ID         string
+	PrivateKey crypto.Signer
+}
+
+// The keys signing and verifying the tokens, the first one signs the new tokens
+//
+// The other keys only verify the tokens, they let the tokens signed before a rotation expire.
+type KeySet struct {
+	algorithm jose.SignatureAlgorithm
+	keys      []Key
+	signer    jose.Signer
+}
+
+// Create a key set, the keys must match the algorithm
+func NewKeySet(algorithm string, keys []Key) (*KeySet, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("jwt: at least one key is required")
+	}
+	seen := map[string]bool{}
+	for _, key := range keys {
+		if key.ID == "" || seen[key.ID] {
+			return nil, fmt.Errorf("jwt: the key ids must be set and unique, got %q", key.ID)
+		}
+		seen[key.ID] = true
+		err := checkKey(algorithm, key.PrivateKey)
+		if err != nil {
+			return nil, fmt.Errorf("jwt: key %q: %w", key.ID, err)
+		}
+	}
+	signer, err := jose.NewSigner(
+		jose.SigningKey{
+			Algorithm: jose.SignatureAlgorithm(algorithm),
+			Key:       jose.JSONWebKey{Key: keys[0].PrivateKey, KeyID: keys[0].ID},
+		},
+		(&jose.SignerOptions{}).WithType("JWT"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &KeySet{algorithm: jose.SignatureAlgorithm(algorithm), keys: keys, signer: signer}, nil
+}
+
+// Return an error if the key can't be used with the algorithm
+func checkKey(algorithm string, key crypto.Signer) error {
+	switch jose.SignatureAlgorithm(algorithm) {
+	case jose.RS256, jose.RS384, jose.RS512, jose.PS256, jose.PS384, jose.PS512:
+		rsaKey, ok := key.(*rsa.PrivateKey)
+		if !ok {
+			return fmt.Errorf("%s needs an RSA key", algorithm)
+		}
+		if rsaKey.N.BitLen() < minRSAKeySize {
+			return fmt.Errorf("the RSA keys must have at least %d bits", minRSAKeySize)
+		}
+	case jose.ES256, jose.ES384, jose.ES512:
+		ecdsaKey, ok := key.(*ecdsa.PrivateKey)
+		if !ok || ecdsaKey.Curve != curves[jose.SignatureAlgorithm(algorithm)] {
+			return fmt.Errorf("%s needs an ECDSA key on the %s curve", algorithm,
+				curves[jose.SignatureAlgorithm(algorithm)].Params().Name)
+		}
+	case jose.EdDSA:
+		if _, ok := key.(ed25519.PrivateKey); !ok {
+			return fmt.Errorf("%s needs an Ed25519 key", algorithm)
+		}
+	default:
+		return fmt.Errorf("unsupported algorithm %q", algorithm)
+	}
+	return nil
+}
+
+// The curves of the ECDSA algorithms
+var curves = map[jose.SignatureAlgorithm]elliptic.Curve{
+	jose.ES256: elliptic.P256(),
+	jose.ES384: elliptic.P384(),
+	jose.ES512: elliptic.P521(),
+}
+
+// Generate a key for the algorithm
+func GenerateKey(algorithm string) (crypto.Signer, error) {
+	switch jose.SignatureAlgorithm(algorithm) {
+	case jose.RS256, jose.RS384, jose.RS512, jose.PS256, jose.PS384, jose.PS512:
+		return rsa.GenerateKey(rand.Reader, minRSAKeySize)
+	case jose.ES256, jose.ES384, jose.ES512:
+		return ecdsa.GenerateKey(curves[jose.SignatureAlgorithm(algorithm)], rand.Reader)
+	case jose.EdDSA:
+		_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+		return privateKey, err
+	default:
+		return nil, fmt.Errorf("jwt: unsupported algorithm %q", algorithm)
+	}
+}
+
+// Parse a PEM encoded private key, in the PKCS #8, PKCS #1 or SEC 1 format
+func ParsePrivateKey(pemBytes []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, errors.New("jwt: no PEM block found")
+	}
+	var key any
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("jwt: %w", err)
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, errors.New("jwt: the key can't sign")
+	}
+	return signer, nil
+}
+
+// Sign the claims with the first key
+func (keySet *KeySet) Sign(claims Claims) (string, error) {
+	return josejwt.Signed(keySet.signer).
+		Claims(josejwt.Claims{
+			Issuer:   Issuer,
+			Subject:  claims.Subject,
+			IssuedAt: josejwt.NewNumericDate(claims.IssuedAt),
+			Expiry:   josejwt.NewNumericDate(claims.ExpiresAt),
+		}).
+		Claims(privateClaims{SessionID: claims.SessionID, Fingerprint: claims.Fingerprint}).
+		CompactSerialize()
+}
+
+// Verify the token with the key of its kid and return its claims
+//
+// The tokens signed with another algorithm than the configured one are refused.
+func (keySet *KeySet) Verify(token string) (*Claims, error) {
+	parsedToken, err := josejwt.ParseSigned(token)
+	if err != nil || len(parsedToken.Headers) != 1 {
+		return nil, ErrInvalidToken
+	}
+	header := parsedToken.Headers[0]
+	if jose.SignatureAlgorithm(header.Algorithm) != keySet.algorithm {
+		return nil, ErrInvalidToken
+	}
+	key := keySet.getKey(header.KeyID)
+	if key == nil {
+		return nil, ErrInvalidToken
+	}
+	standardClaims := josejwt.Claims{}
+	claims := privateClaims{}
+	err = parsedToken.Claims(key.PrivateKey.Public(), &standardClaims, &claims)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	// a minute of leeway absorbs the clock skew between the nodes
+	err = standardClaims.Validate(josejwt.Expected{Issuer: Issuer, Time: time.Now()})
+	if err != nil || standardClaims.Expiry == nil || standardClaims.IssuedAt == nil {
+		return nil, ErrInvalidToken
+	}
+	return &Claims{
+		Subject:     standardClaims.Subject,
+		SessionID:   claims.SessionID,
+		Fingerprint: claims.Fingerprint,
+		IssuedAt:    standardClaims.IssuedAt.Time(),
+		ExpiresAt:   standardClaims.Expiry.Time(),
+	}, nil
+}
+
+// Return the key with this id, or nil
+func (keySet *KeySet) getKey(keyID string) *Key {
+	for i := range keySet.keys {
+		if keySet.keys[i].ID == keyID {
+			return &keySet.keys[i]
+		}
+	}
+	return nil
+}
+
+// Return the public keys, published so that other services can verify the tokens (RFC 7517)
+func (keySet *KeySet) JWKS() jose.JSONWebKeySet {
+	jwks := jose.JSONWebKeySet{Keys: []jose.JSONWebKey{}}
+	for _, key := range keySet.keys {
+		jwks.Keys = append(jwks.Keys, jose.JSONWebKey{
+			Key:       key.PrivateKey.Public(),
+			KeyID:     key.ID,
+			Algorithm: string(keySet.algorithm),
+			Use:       "sig",
+		})
+	}
+	return jwks
+}
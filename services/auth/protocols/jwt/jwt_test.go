@@ -0,0 +1,132 @@
+package jwt_test
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/ditrit/badaas/services/auth/protocols/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newKeySet(t *testing.T, algorithm string, ids ...string) *jwt.KeySet {
+	keys := []jwt.Key{}
+	for _, id := range ids {
+		privateKey, err := jwt.GenerateKey(algorithm)
+		require.NoError(t, err)
+		keys = append(keys, jwt.Key{ID: id, PrivateKey: privateKey})
+	}
+	keySet, err := jwt.NewKeySet(algorithm, keys)
+	require.NoError(t, err)
+	return keySet
+}
+
+func TestSignAndVerify(t *testing.T) {
+	for _, algorithm := range []string{"ES256", "ES384", "EdDSA", "RS256", "PS256"} {
+		t.Run(algorithm, func(t *testing.T) {
+			keySet := newKeySet(t, algorithm, "key-1")
+			now := time.Now().Truncate(time.Second)
+			token, err := keySet.Sign(jwt.Claims{
+				Subject: "user", SessionID: "session", Fingerprint: "client", IssuedAt: now, ExpiresAt: now.Add(time.Minute),
+			})
+			require.NoError(t, err)
+
+			claims, err := keySet.Verify(token)
+			require.NoError(t, err)
+			assert.Equal(t, &jwt.Claims{
+				Subject: "user", SessionID: "session", Fingerprint: "client", IssuedAt: now, ExpiresAt: now.Add(time.Minute),
+			}, claims)
+		})
+	}
+}
+
+func TestVerifyRefused(t *testing.T) {
+	keySet := newKeySet(t, "ES256", "key-1")
+	otherKeySet := newKeySet(t, "ES256", "key-1")
+	now := time.Now()
+
+	expired, err := keySet.Sign(jwt.Claims{Subject: "user", IssuedAt: now.Add(-time.Hour), ExpiresAt: now.Add(-2 * time.Minute)})
+	require.NoError(t, err)
+	_, err = keySet.Verify(expired)
+	assert.ErrorIs(t, err, jwt.ErrInvalidToken)
+
+	forged, err := otherKeySet.Sign(jwt.Claims{Subject: "user", IssuedAt: now, ExpiresAt: now.Add(time.Minute)})
+	require.NoError(t, err)
+	_, err = keySet.Verify(forged)
+	assert.ErrorIs(t, err, jwt.ErrInvalidToken)
+
+	// the algorithm of the token must be the configured one
+	otherAlgorithm := newKeySet(t, "EdDSA", "key-1")
+	token, err := otherAlgorithm.Sign(jwt.Claims{Subject: "user", IssuedAt: now, ExpiresAt: now.Add(time.Minute)})
+	require.NoError(t, err)
+	_, err = keySet.Verify(token)
+	assert.ErrorIs(t, err, jwt.ErrInvalidToken)
+
+	_, err = keySet.Verify("not.a.token")
+	assert.ErrorIs(t, err, jwt.ErrInvalidToken)
+}
+
+func TestKeyRotation(t *testing.T) {
+	oldKey, err := jwt.GenerateKey("ES256")
+	require.NoError(t, err)
+	newKey, err := jwt.GenerateKey("ES256")
+	require.NoError(t, err)
+	before, err := jwt.NewKeySet("ES256", []jwt.Key{{ID: "old", PrivateKey: oldKey}})
+	require.NoError(t, err)
+	after, err := jwt.NewKeySet("ES256", []jwt.Key{{ID: "new", PrivateKey: newKey}, {ID: "old", PrivateKey: oldKey}})
+	require.NoError(t, err)
+
+	now := time.Now()
+	token, err := before.Sign(jwt.Claims{Subject: "user", IssuedAt: now, ExpiresAt: now.Add(time.Minute)})
+	require.NoError(t, err)
+	_, err = after.Verify(token)
+	assert.NoError(t, err)
+
+	jwks, err := json.Marshal(after.JWKS())
+	require.NoError(t, err)
+	var decoded struct {
+		Keys []map[string]any `json:"keys"`
+	}
+	require.NoError(t, json.Unmarshal(jwks, &decoded))
+	require.Len(t, decoded.Keys, 2)
+	assert.Equal(t, "new", decoded.Keys[0]["kid"])
+	assert.Equal(t, "ES256", decoded.Keys[0]["alg"])
+	assert.NotContains(t, decoded.Keys[0], "d")
+}
+
+func TestNewKeySetInvalid(t *testing.T) {
+	ecdsaKey, err := jwt.GenerateKey("ES256")
+	require.NoError(t, err)
+
+	_, err = jwt.NewKeySet("RS256", []jwt.Key{{ID: "key", PrivateKey: ecdsaKey}})
+	assert.Error(t, err)
+	_, err = jwt.NewKeySet("ES384", []jwt.Key{{ID: "key", PrivateKey: ecdsaKey}})
+	assert.Error(t, err)
+	_, err = jwt.NewKeySet("HS256", []jwt.Key{{ID: "key", PrivateKey: ecdsaKey}})
+	assert.Error(t, err)
+	_, err = jwt.NewKeySet("ES256", []jwt.Key{{ID: "key", PrivateKey: ecdsaKey}, {ID: "key", PrivateKey: ecdsaKey}})
+	assert.Error(t, err)
+	_, err = jwt.NewKeySet("ES256", nil)
+	assert.Error(t, err)
+}
+
+func TestParsePrivateKey(t *testing.T) {
+	privateKey, err := jwt.GenerateKey("RS256")
+	require.NoError(t, err)
+	pkcs1 := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(privateKey.(*rsa.PrivateKey))})
+	pkcs8Bytes, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	require.NoError(t, err)
+	pkcs8 := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8Bytes})
+
+	for _, pemBytes := range [][]byte{pkcs1, pkcs8} {
+		parsedKey, err := jwt.ParsePrivateKey(pemBytes)
+		require.NoError(t, err)
+		assert.True(t, privateKey.(*rsa.PrivateKey).Equal(parsedKey))
+	}
+	_, err = jwt.ParsePrivateKey([]byte("not a key"))
+	assert.Error(t, err)
+}
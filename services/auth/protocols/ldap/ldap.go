@@ -0,0 +1,218 @@
+package ldap
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	goldap "github.com/go-ldap/ldap/v3"
+)
+
+// Returned when the user is unknown, ambiguous or when the password is wrong
+var ErrInvalidCredentials = errors.New("ldap invalid credentials")
+
+// Returned when the directory can't be reached or refuses the search
+var ErrDirectory = errors.New("ldap directory error")
+
+// The filter used to find the user when none is configured, %s is replaced by the escaped login
+const DefaultUserFilter = "(mail=%s)"
+
+// The configuration of a directory
+type Config struct {
+	// ldap:// or ldaps:// url of the directory
+	URL string
+	// Upgrade the ldap:// connection to TLS before binding
+	StartTLS bool
+	// The TLS configuration of ldaps:// and StartTLS, the server name is taken from the url if empty
+	TLSConfig *tls.Config
+	// The timeout of the connection and of each request
+	Timeout time.Duration
+	// The service account used to search the users
+	BindDN       string
+	BindPassword string
+	// The entry under which the users are searched
+	BaseDN string
+	// The filter finding a user, %s is replaced by the escaped login
+	UserFilter string
+	// The attributes read from the user entry
+	EmailAttribute    string
+	UsernameAttribute string
+	// The attribute listing the groups of the user (memberOf for Active Directory)
+	GroupsAttribute string
+	// Optional search of the groups whose GroupFilter matches, %s is replaced by the escaped dn of the user
+	GroupBaseDN string
+	GroupFilter string
+}
+
+// The user entry found in the directory
+type Entry struct {
+	DN       string
+	Email    string
+	Username string
+	// The distinguished names of the groups of the user
+	Groups []string
+}
+
+// A directory authenticating the users by binding with their password
+type Directory struct {
+	config Config
+}
+
+// Return a directory for the configuration
+func NewDirectory(config Config) *Directory {
+	if config.UserFilter == "" {
+		config.UserFilter = DefaultUserFilter
+	}
+	return &Directory{config: config}
+}
+
+// Find the entry of the login with the service account then bind as the user to check the password
+func (directory *Directory) Authenticate(login, password string) (*Entry, error) {
+	// an empty password would be an unauthenticated bind (RFC 4513 section 5.1.2), accepted by most servers
+	if login == "" || password == "" {
+		return nil, ErrInvalidCredentials
+	}
+	conn, err := directory.dial()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	err = conn.Bind(directory.config.BindDN, directory.config.BindPassword)
+	if err != nil {
+		return nil, fmt.Errorf("%w: service account bind: %s", ErrDirectory, err)
+	}
+	entry, err := directory.searchUser(conn, login)
+	if err != nil {
+		return nil, err
+	}
+
+	err = conn.Bind(entry.DN, password)
+	if err != nil {
+		if goldap.IsErrorWithCode(err, goldap.LDAPResultInvalidCredentials) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, fmt.Errorf("%w: user bind: %s", ErrDirectory, err)
+	}
+
+	if directory.config.GroupBaseDN != "" && directory.config.GroupFilter != "" {
+		// the groups are searched with the service account, the user may not be allowed to read them
+		err = conn.Bind(directory.config.BindDN, directory.config.BindPassword)
+		if err != nil {
+			return nil, fmt.Errorf("%w: service account bind: %s", ErrDirectory, err)
+		}
+		groups, err := directory.searchGroups(conn, entry.DN)
+		if err != nil {
+			return nil, err
+		}
+		entry.Groups = append(entry.Groups, groups...)
+	}
+	return entry, nil
+}
+
+// Connect to the directory and start TLS if configured
+func (directory *Directory) dial() (*goldap.Conn, error) {
+	tlsConfig, err := directory.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+	conn, err := goldap.DialURL(
+		directory.config.URL,
+		goldap.DialWithDialer(&net.Dialer{Timeout: directory.config.Timeout}),
+		goldap.DialWithTLSConfig(tlsConfig),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrDirectory, err)
+	}
+	conn.SetTimeout(directory.config.Timeout)
+	if directory.config.StartTLS {
+		err = conn.StartTLS(tlsConfig)
+		if err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("%w: start tls: %s", ErrDirectory, err)
+		}
+	}
+	return conn, nil
+}
+
+// Return a copy of the TLS configuration with the server name of the url
+func (directory *Directory) tlsConfig() (*tls.Config, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if directory.config.TLSConfig != nil {
+		tlsConfig = directory.config.TLSConfig.Clone()
+	}
+	if tlsConfig.ServerName == "" {
+		parsedURL, err := url.Parse(directory.config.URL)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrDirectory, err)
+		}
+		tlsConfig.ServerName = parsedURL.Hostname()
+	}
+	return tlsConfig, nil
+}
+
+// Return the single entry matching the login
+func (directory *Directory) searchUser(conn *goldap.Conn, login string) (*Entry, error) {
+	attributes := []string{"dn"}
+	for _, attribute := range []string{
+		directory.config.EmailAttribute,
+		directory.config.UsernameAttribute,
+		directory.config.GroupsAttribute,
+	} {
+		if attribute != "" {
+			attributes = append(attributes, attribute)
+		}
+	}
+	result, err := conn.Search(goldap.NewSearchRequest(
+		directory.config.BaseDN,
+		goldap.ScopeWholeSubtree, goldap.NeverDerefAliases,
+		// two entries are enough to know that the login is ambiguous
+		2, int(directory.config.Timeout.Seconds()), false,
+		strings.ReplaceAll(directory.config.UserFilter, "%s", goldap.EscapeFilter(login)),
+		attributes, nil,
+	))
+	if err != nil {
+		if goldap.IsErrorWithCode(err, goldap.LDAPResultSizeLimitExceeded) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, fmt.Errorf("%w: user search: %s", ErrDirectory, err)
+	}
+	if len(result.Entries) != 1 {
+		return nil, ErrInvalidCredentials
+	}
+	entry := result.Entries[0]
+	foundEntry := &Entry{DN: entry.DN, Groups: []string{}}
+	if directory.config.EmailAttribute != "" {
+		foundEntry.Email = entry.GetAttributeValue(directory.config.EmailAttribute)
+	}
+	if directory.config.UsernameAttribute != "" {
+		foundEntry.Username = entry.GetAttributeValue(directory.config.UsernameAttribute)
+	}
+	if directory.config.GroupsAttribute != "" {
+		foundEntry.Groups = append(foundEntry.Groups, entry.GetAttributeValues(directory.config.GroupsAttribute)...)
+	}
+	return foundEntry, nil
+}
+
+// Return the dn of the groups of the user
+func (directory *Directory) searchGroups(conn *goldap.Conn, userDN string) ([]string, error) {
+	result, err := conn.Search(goldap.NewSearchRequest(
+		directory.config.GroupBaseDN,
+		goldap.ScopeWholeSubtree, goldap.NeverDerefAliases,
+		0, int(directory.config.Timeout.Seconds()), false,
+		strings.ReplaceAll(directory.config.GroupFilter, "%s", goldap.EscapeFilter(userDN)),
+		[]string{"dn"}, nil,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("%w: group search: %s", ErrDirectory, err)
+	}
+	groups := make([]string, 0, len(result.Entries))
+	for _, entry := range result.Entries {
+		groups = append(groups, entry.DN)
+	}
+	return groups, nil
+}
@@ -0,0 +1,155 @@
+package ldap_test
+
+import (
+	"crypto/tls"
+	"testing"
+	"time"
+
+	"github.com/ditrit/badaas/services/auth/protocols/ldap"
+	"github.com/ditrit/badaas/services/auth/protocols/ldap/ldaptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	serviceDN = "cn=badaas,ou=services,dc=example,dc=com"
+	bobDN     = "uid=bob,ou=people,dc=example,dc=com"
+	adminsDN  = "cn=admins,ou=groups,dc=example,dc=com"
+)
+
+func setupServer(t *testing.T) *ldaptest.Server {
+	server, err := ldaptest.NewServer(
+		ldaptest.Entry{DN: serviceDN, Password: "service-password"},
+		ldaptest.Entry{
+			DN:       bobDN,
+			Password: "bob-password",
+			Attributes: map[string][]string{
+				"objectClass": {"inetOrgPerson"},
+				"mail":        {"bob@example.com"},
+				"uid":         {"bob"},
+				"memberOf":    {adminsDN},
+			},
+		},
+		ldaptest.Entry{
+			DN: adminsDN,
+			Attributes: map[string][]string{
+				"objectClass": {"groupOfNames"},
+				"member":      {bobDN},
+			},
+		},
+	)
+	require.NoError(t, err)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newConfig(server *ldaptest.Server) ldap.Config {
+	return ldap.Config{
+		URL:               server.URL,
+		TLSConfig:         &tls.Config{RootCAs: server.RootCAs, MinVersion: tls.VersionTLS12},
+		Timeout:           5 * time.Second,
+		BindDN:            serviceDN,
+		BindPassword:      "service-password",
+		BaseDN:            "ou=people,dc=example,dc=com",
+		EmailAttribute:    "mail",
+		UsernameAttribute: "uid",
+		GroupsAttribute:   "memberOf",
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	server := setupServer(t)
+	directory := ldap.NewDirectory(newConfig(server))
+
+	entry, err := directory.Authenticate("bob@example.com", "bob-password")
+	require.NoError(t, err)
+	assert.Equal(t, &ldap.Entry{
+		DN:       bobDN,
+		Email:    "bob@example.com",
+		Username: "bob",
+		Groups:   []string{adminsDN},
+	}, entry)
+	assert.Equal(t, []string{serviceDN, bobDN}, server.Binds())
+}
+
+func TestAuthenticateWithStartTLS(t *testing.T) {
+	server := setupServer(t)
+	server.RequireTLS = true
+	config := newConfig(server)
+
+	_, err := ldap.NewDirectory(config).Authenticate("bob@example.com", "bob-password")
+	assert.ErrorIs(t, err, ldap.ErrDirectory)
+
+	config.StartTLS = true
+	entry, err := ldap.NewDirectory(config).Authenticate("bob@example.com", "bob-password")
+	require.NoError(t, err)
+	assert.Equal(t, bobDN, entry.DN)
+
+	// the certificate of the server must be trusted
+	config.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	_, err = ldap.NewDirectory(config).Authenticate("bob@example.com", "bob-password")
+	assert.ErrorIs(t, err, ldap.ErrDirectory)
+}
+
+func TestAuthenticateWithGroupSearch(t *testing.T) {
+	server := setupServer(t)
+	config := newConfig(server)
+	config.GroupsAttribute = ""
+	config.GroupBaseDN = "ou=groups,dc=example,dc=com"
+	config.GroupFilter = "(&(objectClass=groupOfNames)(member=%s))"
+
+	entry, err := ldap.NewDirectory(config).Authenticate("bob", "bob-password")
+	assert.ErrorIs(t, err, ldap.ErrInvalidCredentials)
+	assert.Nil(t, entry)
+
+	config.UserFilter = "(|(uid=%s)(mail=%s))"
+	entry, err = ldap.NewDirectory(config).Authenticate("bob", "bob-password")
+	require.NoError(t, err)
+	assert.Equal(t, []string{adminsDN}, entry.Groups)
+}
+
+func TestAuthenticateRefused(t *testing.T) {
+	server := setupServer(t)
+	server.SetEntry(ldaptest.Entry{
+		DN:         "uid=bob2,ou=people,dc=example,dc=com",
+		Password:   "bob2-password",
+		Attributes: map[string][]string{"mail": {"shared@example.com"}},
+	})
+	server.SetEntry(ldaptest.Entry{
+		DN:         "uid=bob3,ou=people,dc=example,dc=com",
+		Password:   "bob3-password",
+		Attributes: map[string][]string{"mail": {"shared@example.com"}},
+	})
+	directory := ldap.NewDirectory(newConfig(server))
+
+	testCases := []struct {
+		name     string
+		login    string
+		password string
+	}{
+		{"wrong password", "bob@example.com", "wrong"},
+		{"empty password", "bob@example.com", ""},
+		{"unknown user", "alice@example.com", "bob-password"},
+		{"ambiguous login", "shared@example.com", "bob2-password"},
+		{"filter injection", "*)(mail=*", "bob-password"},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := directory.Authenticate(testCase.login, testCase.password)
+			assert.ErrorIs(t, err, ldap.ErrInvalidCredentials)
+		})
+	}
+}
+
+func TestAuthenticateDirectoryError(t *testing.T) {
+	server := setupServer(t)
+	config := newConfig(server)
+	config.BindPassword = "wrong"
+
+	_, err := ldap.NewDirectory(config).Authenticate("bob@example.com", "bob-password")
+	assert.ErrorIs(t, err, ldap.ErrDirectory)
+
+	server.Close()
+	_, err = ldap.NewDirectory(newConfig(server)).Authenticate("bob@example.com", "bob-password")
+	assert.ErrorIs(t, err, ldap.ErrDirectory)
+}
@@ -0,0 +1,372 @@
+// Package ldaptest provides an in-process LDAP server to test the directory authentication.
+//
+// It only implements the operations used by the login: simple bind, search with
+// and/or/not/equality/presence filters, StartTLS and unbind.
+package ldaptest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	goldap "github.com/go-ldap/ldap/v3"
+)
+
+// The OID of the StartTLS extended operation (RFC 4511 section 4.14)
+const startTLSOID = "1.3.6.1.4.1.1466.20037"
+
+// An entry of the directory
+type Entry struct {
+	DN         string
+	Attributes map[string][]string
+	// The password of the simple bind, the entry can't bind if empty
+	Password string
+}
+
+// An LDAP server listening on the loopback interface
+type Server struct {
+	// The ldap:// url of the server
+	URL string
+	// Refuse the binds before StartTLS
+	RequireTLS bool
+	// The certificate authority trusting the certificate of the server
+	RootCAs *x509.CertPool
+
+	listener  net.Listener
+	tlsConfig *tls.Config
+	mutex     sync.Mutex
+	entries   []Entry
+	binds     []string
+	waitGroup sync.WaitGroup
+}
+
+// Start a server with the entries, it is closed by Close
+func NewServer(entries ...Entry) (*Server, error) {
+	certificate, rootCAs, err := newCertificate()
+	if err != nil {
+		return nil, err
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	server := &Server{
+		URL:       "ldap://" + listener.Addr().String(),
+		RootCAs:   rootCAs,
+		listener:  listener,
+		tlsConfig: &tls.Config{Certificates: []tls.Certificate{certificate}, MinVersion: tls.VersionTLS12},
+		entries:   entries,
+	}
+	server.waitGroup.Add(1)
+	go server.serve()
+	return server, nil
+}
+
+// Stop listening and wait for the open connections
+func (server *Server) Close() {
+	server.listener.Close()
+	server.waitGroup.Wait()
+}
+
+// Add or replace an entry
+func (server *Server) SetEntry(entry Entry) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	for i := range server.entries {
+		if strings.EqualFold(server.entries[i].DN, entry.DN) {
+			server.entries[i] = entry
+			return
+		}
+	}
+	server.entries = append(server.entries, entry)
+}
+
+// Return the dn of the successful binds
+func (server *Server) Binds() []string {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	return append([]string{}, server.binds...)
+}
+
+func (server *Server) serve() {
+	defer server.waitGroup.Done()
+	for {
+		conn, err := server.listener.Accept()
+		if err != nil {
+			return
+		}
+		server.waitGroup.Add(1)
+		go func() {
+			defer server.waitGroup.Done()
+			server.handle(conn)
+		}()
+	}
+}
+
+// The state of a client connection
+type session struct {
+	conn     net.Conn
+	isTLS    bool
+	boundDN  string
+	isClosed bool
+}
+
+func (server *Server) handle(conn net.Conn) {
+	current := &session{conn: conn}
+	defer func() { current.conn.Close() }()
+	for !current.isClosed {
+		_ = current.conn.SetDeadline(time.Now().Add(10 * time.Second))
+		packet, err := ber.ReadPacket(current.conn)
+		if err != nil || len(packet.Children) < 2 {
+			return
+		}
+		messageID := packet.Children[0].Value
+		request := packet.Children[1]
+		var responses []*ber.Packet
+		switch request.Tag {
+		case goldap.ApplicationBindRequest:
+			responses = []*ber.Packet{server.bind(current, request)}
+		case goldap.ApplicationSearchRequest:
+			responses = server.search(current, request)
+		case goldap.ApplicationExtendedRequest:
+			responses = []*ber.Packet{server.extended(current, request)}
+		case goldap.ApplicationUnbindRequest:
+			return
+		default:
+			return
+		}
+		for _, response := range responses {
+			message := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "LDAP Response")
+			message.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, messageID, "MessageID"))
+			message.AppendChild(response)
+			_, err = current.conn.Write(message.Bytes())
+			if err != nil {
+				return
+			}
+		}
+		if request.Tag == goldap.ApplicationExtendedRequest && current.isTLS {
+			if _, ok := current.conn.(*tls.Conn); !ok {
+				current.conn = tls.Server(current.conn, server.tlsConfig)
+			}
+		}
+	}
+}
+
+// Return an LDAPResult (RFC 4511 section 4.1.9) of the application tag
+func newResult(tag ber.Tag, resultCode int, message string) *ber.Packet {
+	result := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "Result")
+	result.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, resultCode, "resultCode"))
+	result.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "matchedDN"))
+	result.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, message, "diagnosticMessage"))
+	return result
+}
+
+func (server *Server) bind(current *session, request *ber.Packet) *ber.Packet {
+	if len(request.Children) < 3 || request.Children[2].Tag != 0 {
+		return newResult(goldap.ApplicationBindResponse, goldap.LDAPResultAuthMethodNotSupported, "only simple bind")
+	}
+	name := request.Children[1].Data.String()
+	password := request.Children[2].Data.String()
+	if server.RequireTLS && !current.isTLS {
+		return newResult(goldap.ApplicationBindResponse, goldap.LDAPResultConfidentialityRequired, "start tls first")
+	}
+	current.boundDN = ""
+	if name == "" && password == "" {
+		return newResult(goldap.ApplicationBindResponse, goldap.LDAPResultSuccess, "")
+	}
+
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	for _, entry := range server.entries {
+		if strings.EqualFold(entry.DN, name) && entry.Password != "" && entry.Password == password {
+			current.boundDN = entry.DN
+			server.binds = append(server.binds, entry.DN)
+			return newResult(goldap.ApplicationBindResponse, goldap.LDAPResultSuccess, "")
+		}
+	}
+	return newResult(goldap.ApplicationBindResponse, goldap.LDAPResultInvalidCredentials, "")
+}
+
+func (server *Server) extended(current *session, request *ber.Packet) *ber.Packet {
+	if len(request.Children) < 1 || request.Children[0].Data.String() != startTLSOID {
+		return newResult(goldap.ApplicationExtendedResponse, goldap.LDAPResultProtocolError, "unsupported operation")
+	}
+	if current.isTLS {
+		return newResult(goldap.ApplicationExtendedResponse, goldap.LDAPResultOperationsError, "already encrypted")
+	}
+	current.isTLS = true
+	return newResult(goldap.ApplicationExtendedResponse, goldap.LDAPResultSuccess, "")
+}
+
+// Return the entries of the search followed by the SearchResultDone, only bound connections can search
+func (server *Server) search(current *session, request *ber.Packet) []*ber.Packet {
+	if len(request.Children) < 8 {
+		return []*ber.Packet{newResult(goldap.ApplicationSearchResultDone, goldap.LDAPResultProtocolError, "")}
+	}
+	if current.boundDN == "" {
+		return []*ber.Packet{newResult(goldap.ApplicationSearchResultDone, goldap.LDAPResultInsufficientAccessRights, "bind first")}
+	}
+	baseDN := request.Children[0].Data.String()
+	scope, _ := request.Children[1].Value.(int64)
+	sizeLimit, _ := request.Children[3].Value.(int64)
+	filter := request.Children[6]
+	attributes := map[string]bool{}
+	for _, attribute := range request.Children[7].Children {
+		attributes[strings.ToLower(attribute.Data.String())] = true
+	}
+
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	responses := []*ber.Packet{}
+	for _, entry := range server.entries {
+		if !inScope(entry.DN, baseDN, scope) {
+			continue
+		}
+		matches, err := match(entry, filter)
+		if err != nil {
+			return []*ber.Packet{newResult(goldap.ApplicationSearchResultDone, goldap.LDAPResultUnwillingToPerform, err.Error())}
+		}
+		if !matches {
+			continue
+		}
+		if sizeLimit > 0 && int64(len(responses)) == sizeLimit {
+			return append(responses, newResult(goldap.ApplicationSearchResultDone, goldap.LDAPResultSizeLimitExceeded, ""))
+		}
+		responses = append(responses, newSearchEntry(entry, attributes))
+	}
+	return append(responses, newResult(goldap.ApplicationSearchResultDone, goldap.LDAPResultSuccess, ""))
+}
+
+// Return whether the dn is in the scope of the base
+func inScope(dn, baseDN string, scope int64) bool {
+	dn = strings.ToLower(dn)
+	baseDN = strings.ToLower(baseDN)
+	switch scope {
+	case goldap.ScopeBaseObject:
+		return dn == baseDN
+	case goldap.ScopeSingleLevel:
+		parent := strings.SplitN(dn, ",", 2)
+		return len(parent) == 2 && parent[1] == baseDN
+	default:
+		return dn == baseDN || baseDN == "" || strings.HasSuffix(dn, ","+baseDN)
+	}
+}
+
+// Evaluate the filter (RFC 4511 section 4.5.1.7) on the entry
+func match(entry Entry, filter *ber.Packet) (bool, error) {
+	switch filter.Tag {
+	case goldap.FilterAnd:
+		for _, child := range filter.Children {
+			matches, err := match(entry, child)
+			if err != nil || !matches {
+				return false, err
+			}
+		}
+		return true, nil
+	case goldap.FilterOr:
+		for _, child := range filter.Children {
+			matches, err := match(entry, child)
+			if err != nil || matches {
+				return matches, err
+			}
+		}
+		return false, nil
+	case goldap.FilterNot:
+		if len(filter.Children) != 1 {
+			return false, errors.New("invalid not filter")
+		}
+		matches, err := match(entry, filter.Children[0])
+		return !matches, err
+	case goldap.FilterEqualityMatch:
+		if len(filter.Children) != 2 {
+			return false, errors.New("invalid equality filter")
+		}
+		name := filter.Children[0].Data.String()
+		value := filter.Children[1].Data.String()
+		if strings.EqualFold(name, "objectClass") && strings.EqualFold(value, "*") {
+			return true, nil
+		}
+		for _, entryValue := range attributeValues(entry, name) {
+			if strings.EqualFold(entryValue, value) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case goldap.FilterPresent:
+		return len(attributeValues(entry, filter.Data.String())) != 0, nil
+	default:
+		return false, errors.New("unsupported filter")
+	}
+}
+
+// Return the values of the attribute, the names are case insensitive
+func attributeValues(entry Entry, name string) []string {
+	for attribute, values := range entry.Attributes {
+		if strings.EqualFold(attribute, name) {
+			return values
+		}
+	}
+	return nil
+}
+
+// Return the SearchResultEntry with the requested attributes, all of them if none is requested
+func newSearchEntry(entry Entry, attributes map[string]bool) *ber.Packet {
+	searchEntry := ber.Encode(ber.ClassApplication, ber.TypeConstructed, goldap.ApplicationSearchResultEntry, nil, "Search Result Entry")
+	searchEntry.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, entry.DN, "objectName"))
+	attributeList := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "attributes")
+	for name, values := range entry.Attributes {
+		if len(attributes) != 0 && !attributes["*"] && !attributes[strings.ToLower(name)] {
+			continue
+		}
+		attribute := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "attribute")
+		attribute.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, name, "type"))
+		set := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "vals")
+		for _, value := range values {
+			set.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, value, "value"))
+		}
+		attribute.AppendChild(set)
+		attributeList.AppendChild(attribute)
+	}
+	searchEntry.AppendChild(attributeList)
+	return searchEntry
+}
+
+// Generate a self-signed certificate for 127.0.0.1 valid for a day and the pool trusting it
+func newCertificate() (tls.Certificate, *x509.CertPool, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ldaptest"},
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	raw, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, nil, err
+	}
+	certificate, err := x509.ParseCertificate(raw)
+	if err != nil {
+		return tls.Certificate{}, nil, err
+	}
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(certificate)
+	return tls.Certificate{Certificate: [][]byte{raw}, PrivateKey: key, Leaf: certificate}, rootCAs, nil
+}
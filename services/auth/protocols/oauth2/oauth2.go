@@ -0,0 +1,219 @@
+// Package oauth2 implements the building blocks of an OAuth 2.0 authorization server (RFC 6749),
+// with the proof key for code exchange of the public clients (RFC 7636).
+package oauth2
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// The grant types of the token endpoint
+const (
+	GrantTypeAuthorizationCode = "authorization_code"
+	GrantTypeRefreshToken      = "refresh_token"
+	GrantTypeClientCredentials = "client_credentials"
+)
+
+// The only response type of the authorization endpoint, the implicit grant is not supported
+const ResponseTypeCode = "code"
+
+// The only code challenge method accepted, the plain method doesn't protect the code
+const CodeChallengeMethodS256 = "S256"
+
+// The type of the issued tokens (RFC 6750)
+const TokenTypeBearer = "Bearer"
+
+// The error codes of the authorization and token endpoints (RFC 6749 sections 4.1.2.1 and 5.2)
+const (
+	ErrorInvalidRequest          = "invalid_request"
+	ErrorInvalidClient           = "invalid_client"
+	ErrorInvalidGrant            = "invalid_grant"
+	ErrorUnauthorizedClient      = "unauthorized_client"
+	ErrorUnsupportedGrantType    = "unsupported_grant_type"
+	ErrorUnsupportedResponseType = "unsupported_response_type"
+	ErrorInvalidScope            = "invalid_scope"
+	ErrorAccessDenied            = "access_denied"
+	ErrorServerError             = "server_error"
+)
+
+// Returned when the client authentication of the request is malformed
+var ErrInvalidClientAuthentication = errors.New("oauth2 invalid client authentication")
+
+// The number of random bytes of the tokens, the codes and the client secrets
+const tokenSize = 32
+
+// The code verifiers and the S256 code challenges (RFC 7636 section 4.1 and 4.2)
+var (
+	codeVerifierRegexp  = regexp.MustCompile(`^[A-Za-z0-9\-._~]{43,128}$`)
+	codeChallengeRegexp = regexp.MustCompile(`^[A-Za-z0-9\-_]{43}$`)
+)
+
+// Generate a random token, also used for the codes and the client secrets
+func GenerateToken() (string, error) {
+	b := make([]byte, tokenSize)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Hash a token, only the hash of the tokens are stored
+func HashToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+// Return true if the token matches the stored hash, in constant time
+func TokenMatches(token, hash string) bool {
+	return subtle.ConstantTimeCompare([]byte(HashToken(token)), []byte(hash)) == 1
+}
+
+// Return true if the code challenge can be sent with the S256 method
+func IsValidCodeChallenge(challenge string) bool {
+	return codeChallengeRegexp.MatchString(challenge)
+}
+
+// Return true if the verifier matches the S256 code challenge
+func VerifyCodeChallenge(verifier, challenge string) bool {
+	if !codeVerifierRegexp.MatchString(verifier) {
+		return false
+	}
+	hash := sha256.Sum256([]byte(verifier))
+	computed := base64.RawURLEncoding.EncodeToString(hash[:])
+	return subtle.ConstantTimeCompare([]byte(computed), []byte(challenge)) == 1
+}
+
+// Split a space delimited scope (RFC 6749 section 3.3), the scopes are sorted and deduplicated
+func ParseScope(scope string) []string {
+	scopes := []string{}
+	seen := map[string]bool{}
+	for _, value := range strings.Fields(scope) {
+		if !seen[value] {
+			seen[value] = true
+			scopes = append(scopes, value)
+		}
+	}
+	sort.Strings(scopes)
+	return scopes
+}
+
+// Join the scopes with spaces
+func FormatScope(scopes []string) string {
+	return strings.Join(ParseScope(strings.Join(scopes, " ")), " ")
+}
+
+// Return true if all the scopes are allowed
+func IsSubset(scopes, allowed []string) bool {
+	allowedScopes := map[string]bool{}
+	for _, scope := range allowed {
+		allowedScopes[scope] = true
+	}
+	for _, scope := range scopes {
+		if !allowedScopes[scope] {
+			return false
+		}
+	}
+	return true
+}
+
+// Return true if the redirect uri is one of the registered ones
+//
+// The uris are compared exactly, except the port of the loopback uris of the native apps (RFC 8252 section 7.3).
+func RedirectURIMatches(registered []string, redirectURI string) bool {
+	for _, registeredURI := range registered {
+		if registeredURI == redirectURI {
+			return true
+		}
+	}
+	parsedURI, err := url.Parse(redirectURI)
+	if err != nil || parsedURI.Scheme != "http" || !isLoopback(parsedURI.Hostname()) {
+		return false
+	}
+	for _, registeredURI := range registered {
+		parsedRegisteredURI, err := url.Parse(registeredURI)
+		if err != nil || parsedRegisteredURI.Scheme != "http" || parsedRegisteredURI.Hostname() != parsedURI.Hostname() {
+			continue
+		}
+		if parsedRegisteredURI.Path == parsedURI.Path && parsedRegisteredURI.RawQuery == parsedURI.RawQuery {
+			return true
+		}
+	}
+	return false
+}
+
+// Return true if the host is a loopback ip address, localhost is not trusted to resolve to the loopback interface
+func isLoopback(host string) bool {
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// Return true if the redirect uri can be registered: an absolute uri without fragment (RFC 6749 section 3.1.2)
+func IsValidRedirectURI(redirectURI string) bool {
+	parsedURI, err := url.Parse(redirectURI)
+	return err == nil && parsedURI.IsAbs() && parsedURI.Fragment == "" && !strings.Contains(redirectURI, " ")
+}
+
+// Add the parameters to the query of the redirect uri, keeping its own parameters
+func AddQueryParameters(redirectURI string, parameters url.Values) (string, error) {
+	parsedURI, err := url.Parse(redirectURI)
+	if err != nil {
+		return "", err
+	}
+	query := parsedURI.Query()
+	for name, values := range parameters {
+		for _, value := range values {
+			if value != "" {
+				query.Add(name, value)
+			}
+		}
+	}
+	parsedURI.RawQuery = query.Encode()
+	return parsedURI.String(), nil
+}
+
+// The client authentication of a request to the token, introspection or revocation endpoints
+type ClientCredentials struct {
+	ClientID     string
+	ClientSecret string
+	// True if the credentials were sent with the HTTP Basic authentication scheme
+	Basic bool
+}
+
+// Read the client authentication of the request, with the HTTP Basic scheme or in the form (RFC 6749 section 2.3.1)
+//
+// The form of the request must be parsed. Using both methods is refused.
+func GetClientCredentials(r *http.Request) (ClientCredentials, error) {
+	username, password, ok := r.BasicAuth()
+	formClientID := r.PostForm.Get("client_id")
+	formClientSecret := r.PostForm.Get("client_secret")
+	if !ok {
+		return ClientCredentials{ClientID: formClientID, ClientSecret: formClientSecret}, nil
+	}
+	if formClientSecret != "" {
+		return ClientCredentials{}, ErrInvalidClientAuthentication
+	}
+	// the client id and secret are form-urlencoded before being sent with the basic scheme
+	clientID, err := url.QueryUnescape(username)
+	if err != nil {
+		return ClientCredentials{}, ErrInvalidClientAuthentication
+	}
+	clientSecret, err := url.QueryUnescape(password)
+	if err != nil {
+		return ClientCredentials{}, ErrInvalidClientAuthentication
+	}
+	if formClientID != "" && formClientID != clientID {
+		return ClientCredentials{}, ErrInvalidClientAuthentication
+	}
+	return ClientCredentials{ClientID: clientID, ClientSecret: clientSecret, Basic: true}, nil
+}
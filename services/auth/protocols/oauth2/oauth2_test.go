@@ -0,0 +1,112 @@
+package oauth2_test
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/ditrit/badaas/services/auth/protocols/oauth2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHashToken(t *testing.T) {
+	token, err := oauth2.GenerateToken()
+	require.NoError(t, err)
+	assert.Len(t, token, 43)
+	hash := oauth2.HashToken(token)
+	assert.True(t, oauth2.TokenMatches(token, hash))
+	assert.False(t, oauth2.TokenMatches(token+"x", hash))
+}
+
+func TestVerifyCodeChallenge(t *testing.T) {
+	verifier, err := oauth2.GenerateToken()
+	require.NoError(t, err)
+	verifierHash := sha256.Sum256([]byte(verifier))
+	challenge := base64.RawURLEncoding.EncodeToString(verifierHash[:])
+	assert.True(t, oauth2.IsValidCodeChallenge(challenge))
+	assert.True(t, oauth2.VerifyCodeChallenge(verifier, challenge))
+	assert.False(t, oauth2.VerifyCodeChallenge(verifier+"x", challenge))
+
+	// the verifiers must have at least 43 characters
+	shortVerifier := "short"
+	hash := sha256.Sum256([]byte(shortVerifier))
+	assert.False(t, oauth2.VerifyCodeChallenge(shortVerifier, base64.RawURLEncoding.EncodeToString(hash[:])))
+	assert.False(t, oauth2.IsValidCodeChallenge("plain"))
+}
+
+func TestScopes(t *testing.T) {
+	assert.Equal(t, []string{"read", "write"}, oauth2.ParseScope(" write read  write"))
+	assert.Equal(t, []string{}, oauth2.ParseScope(""))
+	assert.Equal(t, "read write", oauth2.FormatScope([]string{"write", "read"}))
+	assert.True(t, oauth2.IsSubset([]string{"read"}, []string{"read", "write"}))
+	assert.True(t, oauth2.IsSubset([]string{}, []string{"read"}))
+	assert.False(t, oauth2.IsSubset([]string{"read", "admin"}, []string{"read", "write"}))
+}
+
+func TestRedirectURIMatches(t *testing.T) {
+	registered := []string{"https://app.example.com/callback", "http://127.0.0.1/callback"}
+	assert.True(t, oauth2.RedirectURIMatches(registered, "https://app.example.com/callback"))
+	assert.False(t, oauth2.RedirectURIMatches(registered, "https://app.example.com/callback/"))
+	assert.False(t, oauth2.RedirectURIMatches(registered, "https://app.example.com/callback?next=1"))
+	assert.False(t, oauth2.RedirectURIMatches(registered, "https://app.example.com:8443/callback"))
+	// the native apps choose the port of their loopback redirect uri
+	assert.True(t, oauth2.RedirectURIMatches(registered, "http://127.0.0.1:51004/callback"))
+	assert.False(t, oauth2.RedirectURIMatches(registered, "http://127.0.0.1:51004/other"))
+	assert.False(t, oauth2.RedirectURIMatches(registered, "http://localhost:51004/callback"))
+}
+
+func TestIsValidRedirectURI(t *testing.T) {
+	assert.True(t, oauth2.IsValidRedirectURI("https://app.example.com/callback"))
+	assert.True(t, oauth2.IsValidRedirectURI("com.example.app:/callback"))
+	assert.False(t, oauth2.IsValidRedirectURI("/callback"))
+	assert.False(t, oauth2.IsValidRedirectURI("https://app.example.com/callback#fragment"))
+}
+
+func TestAddQueryParameters(t *testing.T) {
+	redirectURL, err := oauth2.AddQueryParameters("https://app.example.com/callback?tenant=1",
+		url.Values{"code": {"abc"}, "state": {""}})
+	require.NoError(t, err)
+	assert.Equal(t, "https://app.example.com/callback?code=abc&tenant=1", redirectURL)
+}
+
+func TestGetClientCredentials(t *testing.T) {
+	testCases := []struct {
+		name     string
+		username string
+		password string
+		form     string
+		expected oauth2.ClientCredentials
+		err      error
+	}{
+		{"form", "", "", "client_id=client&client_secret=secret",
+			oauth2.ClientCredentials{ClientID: "client", ClientSecret: "secret"}, nil},
+		{"public client", "", "", "client_id=client",
+			oauth2.ClientCredentials{ClientID: "client"}, nil},
+		{"basic", "client", "s%3Acret", "",
+			oauth2.ClientCredentials{ClientID: "client", ClientSecret: "s:cret", Basic: true}, nil},
+		{"basic and same client id", "client", "secret", "client_id=client",
+			oauth2.ClientCredentials{ClientID: "client", ClientSecret: "secret", Basic: true}, nil},
+		{"basic and other client id", "client", "secret", "client_id=other",
+			oauth2.ClientCredentials{}, oauth2.ErrInvalidClientAuthentication},
+		{"both methods", "client", "secret", "client_secret=secret",
+			oauth2.ClientCredentials{}, oauth2.ErrInvalidClientAuthentication},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			request := httptest.NewRequest("POST", "/oauth/token", strings.NewReader(testCase.form))
+			request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			if testCase.username != "" {
+				request.SetBasicAuth(testCase.username, testCase.password)
+			}
+			require.NoError(t, request.ParseForm())
+
+			credentials, err := oauth2.GetClientCredentials(request)
+			assert.ErrorIs(t, err, testCase.err)
+			assert.Equal(t, testCase.expected, credentials)
+		})
+	}
+}
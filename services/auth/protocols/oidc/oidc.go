@@ -0,0 +1,166 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+
+	gooidc "github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+)
+
+// Returned when the code can't be exchanged for tokens
+var ErrExchange = errors.New("oidc code exchange failed")
+
+// Returned when the ID token is missing or invalid
+var ErrInvalidIDToken = errors.New("oidc invalid id token")
+
+// The scopes asked when none is configured
+var DefaultScopes = []string{gooidc.ScopeOpenID, "email", "profile"}
+
+// The number of random bytes of the state, the nonce and the code verifier
+const randomSize = 32
+
+// The parameters of a login, kept by the relying party until the callback of the provider
+type AuthRequest struct {
+	// Sent back by the provider with the code, it identifies the login and protects against CSRF
+	State string
+	// Included in the ID token, it binds the token to the login
+	Nonce string
+	// The PKCE verifier (RFC 7636), only its hash is sent to the authorization endpoint
+	CodeVerifier string
+}
+
+// Generate the random parameters of a new login
+func NewAuthRequest() (*AuthRequest, error) {
+	values := make([]string, 3)
+	for i := range values {
+		b := make([]byte, randomSize)
+		_, err := rand.Read(b)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = base64.RawURLEncoding.EncodeToString(b)
+	}
+	return &AuthRequest{
+		State:        values[0],
+		Nonce:        values[1],
+		CodeVerifier: values[2],
+	}, nil
+}
+
+// The claims of the ID token used to find or create the user
+type Claims struct {
+	Subject           string
+	Email             string
+	EmailVerified     bool
+	Name              string
+	PreferredUsername string
+}
+
+// The claims of the ID token as sent by the providers
+type idTokenClaims struct {
+	Email             string `json:"email"`
+	EmailVerified     any    `json:"email_verified"`
+	Name              string `json:"name"`
+	PreferredUsername string `json:"preferred_username"`
+}
+
+// The relying party of an OpenID provider, the endpoints of the provider are discovered from its issuer
+type RelyingParty struct {
+	oauth2Config oauth2.Config
+	verifier     *gooidc.IDTokenVerifier
+	httpClient   *http.Client
+}
+
+// Discover the provider of the issuer and return its relying party
+//
+// The http client is used for all the requests to the provider.
+func NewRelyingParty(
+	ctx context.Context,
+	httpClient *http.Client,
+	issuer, clientID, clientSecret, redirectURL string,
+	scopes []string,
+) (*RelyingParty, error) {
+	provider, err := gooidc.NewProvider(gooidc.ClientContext(ctx, httpClient), issuer)
+	if err != nil {
+		return nil, err
+	}
+	if len(scopes) == 0 {
+		scopes = DefaultScopes
+	}
+	if !contains(scopes, gooidc.ScopeOpenID) {
+		scopes = append([]string{gooidc.ScopeOpenID}, scopes...)
+	}
+	return &RelyingParty{
+		oauth2Config: oauth2.Config{
+			ClientID:     clientID,
+			ClientSecret: clientSecret,
+			RedirectURL:  redirectURL,
+			Endpoint:     provider.Endpoint(),
+			Scopes:       scopes,
+		},
+		verifier:   provider.Verifier(&gooidc.Config{ClientID: clientID}),
+		httpClient: httpClient,
+	}, nil
+}
+
+// Return the url of the authorization endpoint the user is redirected to
+func (relyingParty *RelyingParty) AuthCodeURL(request *AuthRequest) string {
+	challenge := sha256.Sum256([]byte(request.CodeVerifier))
+	return relyingParty.oauth2Config.AuthCodeURL(
+		request.State,
+		gooidc.Nonce(request.Nonce),
+		oauth2.SetAuthURLParam("code_challenge", base64.RawURLEncoding.EncodeToString(challenge[:])),
+		oauth2.SetAuthURLParam("code_challenge_method", "S256"),
+	)
+}
+
+// Exchange the code returned by the provider for an ID token, and return its claims once verified
+//
+// The signature, the issuer, the audience, the expiration and the nonce of the token are checked.
+func (relyingParty *RelyingParty) Exchange(ctx context.Context, code string, request *AuthRequest) (*Claims, error) {
+	ctx = gooidc.ClientContext(ctx, relyingParty.httpClient)
+	token, err := relyingParty.oauth2Config.Exchange(ctx, code,
+		oauth2.SetAuthURLParam("code_verifier", request.CodeVerifier))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrExchange, err)
+	}
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return nil, fmt.Errorf("%w: no id token in the token response", ErrInvalidIDToken)
+	}
+	idToken, err := relyingParty.verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidIDToken, err)
+	}
+	if idToken.Nonce != request.Nonce {
+		return nil, fmt.Errorf("%w: the nonce doesn't match", ErrInvalidIDToken)
+	}
+	var claims idTokenClaims
+	err = idToken.Claims(&claims)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidIDToken, err)
+	}
+	return &Claims{
+		Subject:           idToken.Subject,
+		Email:             claims.Email,
+		EmailVerified:     claims.EmailVerified == true || claims.EmailVerified == "true",
+		Name:              claims.Name,
+		PreferredUsername: claims.PreferredUsername,
+	}, nil
+}
+
+// Return true if the value is in the list
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,112 @@
+package oidc_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/ditrit/badaas/services/auth/protocols/oidc"
+	"github.com/ditrit/badaas/services/auth/protocols/oidc/oidctest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const redirectURL = "https://badaas.example.com/login/oidc/stub/callback"
+
+var user = oidctest.User{Subject: "1234", Email: "bob@email.com", EmailVerified: true, Name: "Bob", PreferredUsername: "bob"}
+
+func setupProvider(t *testing.T) (*oidctest.Provider, *oidc.RelyingParty) {
+	provider, err := oidctest.NewProvider("badaas", "secret")
+	require.NoError(t, err)
+	t.Cleanup(provider.Close)
+	relyingParty, err := oidc.NewRelyingParty(context.Background(), http.DefaultClient,
+		provider.Issuer(), "badaas", "secret", redirectURL, nil)
+	require.NoError(t, err)
+	return provider, relyingParty
+}
+
+// Start a login and return the callback url of the provider
+func authorize(t *testing.T, provider *oidctest.Provider, relyingParty *oidc.RelyingParty, request *oidc.AuthRequest) *url.URL {
+	authorizationURL := relyingParty.AuthCodeURL(request)
+	callbackURL, err := provider.Authorize(authorizationURL, user)
+	require.NoError(t, err)
+	return callbackURL
+}
+
+func TestAuthCodeURL(t *testing.T) {
+	provider, relyingParty := setupProvider(t)
+	request, err := oidc.NewAuthRequest()
+	require.NoError(t, err)
+
+	authorizationURL, err := url.Parse(relyingParty.AuthCodeURL(request))
+	require.NoError(t, err)
+	assert.Equal(t, provider.Issuer()+"/authorize", authorizationURL.Scheme+"://"+authorizationURL.Host+authorizationURL.Path)
+	query := authorizationURL.Query()
+	assert.Equal(t, request.State, query.Get("state"))
+	assert.Equal(t, request.Nonce, query.Get("nonce"))
+	assert.Equal(t, "S256", query.Get("code_challenge_method"))
+	assert.NotContains(t, authorizationURL.RawQuery, request.CodeVerifier)
+	assert.Equal(t, "openid email profile", query.Get("scope"))
+}
+
+func TestExchange(t *testing.T) {
+	provider, relyingParty := setupProvider(t)
+	request, err := oidc.NewAuthRequest()
+	require.NoError(t, err)
+	callbackURL := authorize(t, provider, relyingParty, request)
+	assert.Equal(t, request.State, callbackURL.Query().Get("state"))
+
+	claims, err := relyingParty.Exchange(context.Background(), callbackURL.Query().Get("code"), request)
+	require.NoError(t, err)
+	assert.Equal(t, &oidc.Claims{
+		Subject:           "1234",
+		Email:             "bob@email.com",
+		EmailVerified:     true,
+		Name:              "Bob",
+		PreferredUsername: "bob",
+	}, claims)
+}
+
+func TestExchangeWrongCodeVerifier(t *testing.T) {
+	provider, relyingParty := setupProvider(t)
+	request, err := oidc.NewAuthRequest()
+	require.NoError(t, err)
+	callbackURL := authorize(t, provider, relyingParty, request)
+
+	// the code was intercepted by an attacker who doesn't know the verifier
+	otherRequest, err := oidc.NewAuthRequest()
+	require.NoError(t, err)
+	_, err = relyingParty.Exchange(context.Background(), callbackURL.Query().Get("code"), otherRequest)
+	assert.ErrorIs(t, err, oidc.ErrExchange)
+}
+
+func TestExchangeWrongNonce(t *testing.T) {
+	provider, relyingParty := setupProvider(t)
+	provider.NonceOverride = "replayed"
+	request, err := oidc.NewAuthRequest()
+	require.NoError(t, err)
+	callbackURL := authorize(t, provider, relyingParty, request)
+
+	_, err = relyingParty.Exchange(context.Background(), callbackURL.Query().Get("code"), request)
+	assert.ErrorIs(t, err, oidc.ErrInvalidIDToken)
+}
+
+func TestExchangeOtherAudience(t *testing.T) {
+	provider, _ := setupProvider(t)
+	provider.ClientID = "other"
+	relyingParty, err := oidc.NewRelyingParty(context.Background(), http.DefaultClient,
+		provider.Issuer(), "other", "secret", redirectURL, nil)
+	require.NoError(t, err)
+	request, err := oidc.NewAuthRequest()
+	require.NoError(t, err)
+	callbackURL := authorize(t, provider, relyingParty, request)
+
+	// the ID token of another client is refused
+	badaasRelyingParty, err := oidc.NewRelyingParty(context.Background(), http.DefaultClient,
+		provider.Issuer(), "badaas", "secret", redirectURL, nil)
+	require.NoError(t, err)
+	provider.ClientID = "badaas"
+	_, err = badaasRelyingParty.Exchange(context.Background(), callbackURL.Query().Get("code"), request)
+	assert.ErrorIs(t, err, oidc.ErrInvalidIDToken)
+}
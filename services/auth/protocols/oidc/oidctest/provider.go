@@ -0,0 +1,238 @@
+// Package oidctest provides a stub OpenID provider to test the relying party.
+package oidctest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/go-jose/go-jose/v3"
+)
+
+// The key id of the signing key of the provider
+const keyID = "stub-key"
+
+// The identity of the user who logs in on the provider
+type User struct {
+	Subject           string
+	Email             string
+	EmailVerified     bool
+	Name              string
+	PreferredUsername string
+}
+
+// A pending authorization, waiting for the exchange of its code
+type authorization struct {
+	user          User
+	clientID      string
+	redirectURI   string
+	nonce         string
+	codeChallenge string
+}
+
+// A stub OpenID provider serving the discovery document, the keys and the token endpoint
+//
+// The users are logged in by calling Authorize with the url the relying party redirects to.
+type Provider struct {
+	Server       *httptest.Server
+	ClientID     string
+	ClientSecret string
+	// Change the nonce of the ID tokens, to test their verification
+	NonceOverride string
+
+	key            *rsa.PrivateKey
+	mutex          sync.Mutex
+	authorizations map[string]*authorization
+}
+
+// Start a provider accepting the client, it has to be closed once the test is done
+func NewProvider(clientID, clientSecret string) (*Provider, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+	provider := &Provider{
+		ClientID:       clientID,
+		ClientSecret:   clientSecret,
+		key:            key,
+		authorizations: map[string]*authorization{},
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", provider.discovery)
+	mux.HandleFunc("/keys", provider.keys)
+	mux.HandleFunc("/token", provider.token)
+	provider.Server = httptest.NewServer(mux)
+	return provider, nil
+}
+
+// Return the issuer of the provider
+func (provider *Provider) Issuer() string {
+	return provider.Server.URL
+}
+
+// Stop the provider
+func (provider *Provider) Close() {
+	provider.Server.Close()
+}
+
+// Log the user in with the authorization url and return the url of the callback, with the code and the state
+func (provider *Provider) Authorize(authorizationURL string, user User) (*url.URL, error) {
+	parsedURL, err := url.Parse(authorizationURL)
+	if err != nil {
+		return nil, err
+	}
+	query := parsedURL.Query()
+	if query.Get("client_id") != provider.ClientID || query.Get("response_type") != "code" {
+		return nil, errors.New("invalid authorization request")
+	}
+	if query.Get("code_challenge_method") != "S256" {
+		return nil, errors.New("PKCE is required")
+	}
+	code, err := randomString()
+	if err != nil {
+		return nil, err
+	}
+	provider.mutex.Lock()
+	provider.authorizations[code] = &authorization{
+		user:          user,
+		clientID:      query.Get("client_id"),
+		redirectURI:   query.Get("redirect_uri"),
+		nonce:         query.Get("nonce"),
+		codeChallenge: query.Get("code_challenge"),
+	}
+	provider.mutex.Unlock()
+
+	callbackURL, err := url.Parse(query.Get("redirect_uri"))
+	if err != nil {
+		return nil, err
+	}
+	callbackQuery := callbackURL.Query()
+	callbackQuery.Set("code", code)
+	callbackQuery.Set("state", query.Get("state"))
+	callbackURL.RawQuery = callbackQuery.Encode()
+	return callbackURL, nil
+}
+
+// Serve the discovery document
+func (provider *Provider) discovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"issuer":                                provider.Issuer(),
+		"authorization_endpoint":                provider.Issuer() + "/authorize",
+		"token_endpoint":                        provider.Issuer() + "/token",
+		"jwks_uri":                              provider.Issuer() + "/keys",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"code_challenge_methods_supported":      []string{"S256"},
+	})
+}
+
+// Serve the public signing key
+func (provider *Provider) keys(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, jose.JSONWebKeySet{Keys: []jose.JSONWebKey{{
+		Key:       &provider.key.PublicKey,
+		KeyID:     keyID,
+		Algorithm: string(jose.RS256),
+		Use:       "sig",
+	}}})
+}
+
+// Exchange a code for an ID token, after checking the client, the redirect uri and the PKCE verifier
+func (provider *Provider) token(w http.ResponseWriter, r *http.Request) {
+	err := r.ParseForm()
+	if err != nil || r.PostForm.Get("grant_type") != "authorization_code" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+	clientID, clientSecret, ok := r.BasicAuth()
+	if !ok {
+		clientID, clientSecret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+	if clientID != provider.ClientID || clientSecret != provider.ClientSecret {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+	provider.mutex.Lock()
+	code := r.PostForm.Get("code")
+	authorization, ok := provider.authorizations[code]
+	delete(provider.authorizations, code)
+	provider.mutex.Unlock()
+	verifierHash := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if !ok || authorization.redirectURI != r.PostForm.Get("redirect_uri") ||
+		authorization.codeChallenge != base64.RawURLEncoding.EncodeToString(verifierHash[:]) {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+	idToken, err := provider.signIDToken(authorization)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token": "stub-access-token",
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     idToken,
+	})
+}
+
+// Sign the ID token of the authorization
+func (provider *Provider) signIDToken(authorization *authorization) (string, error) {
+	signer, err := jose.NewSigner(
+		jose.SigningKey{Algorithm: jose.RS256, Key: provider.key},
+		(&jose.SignerOptions{}).WithType("JWT").WithHeader("kid", keyID),
+	)
+	if err != nil {
+		return "", err
+	}
+	nonce := authorization.nonce
+	if provider.NonceOverride != "" {
+		nonce = provider.NonceOverride
+	}
+	now := time.Now()
+	payload, err := json.Marshal(map[string]any{
+		"iss":                provider.Issuer(),
+		"sub":                authorization.user.Subject,
+		"aud":                authorization.clientID,
+		"iat":                now.Unix(),
+		"exp":                now.Add(time.Hour).Unix(),
+		"nonce":              nonce,
+		"email":              authorization.user.Email,
+		"email_verified":     authorization.user.EmailVerified,
+		"name":               authorization.user.Name,
+		"preferred_username": authorization.user.PreferredUsername,
+	})
+	if err != nil {
+		return "", err
+	}
+	signature, err := signer.Sign(payload)
+	if err != nil {
+		return "", err
+	}
+	return signature.CompactSerialize()
+}
+
+// Write a JSON response
+func writeJSON(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+// Return a random url safe string
+func randomString() (string, error) {
+	b := make([]byte, 32)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
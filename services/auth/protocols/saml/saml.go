@@ -0,0 +1,479 @@
+package saml
+
+import (
+	"bytes"
+	"compress/flate"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/beevik/etree"
+	xrv "github.com/mattermost/xml-roundtrip-validator"
+	dsig "github.com/russellhaering/goxmldsig"
+	"github.com/russellhaering/goxmldsig/etreeutils"
+)
+
+// The namespaces of the SAML 2.0 documents
+const (
+	ProtocolNamespace  = "urn:oasis:names:tc:SAML:2.0:protocol"
+	AssertionNamespace = "urn:oasis:names:tc:SAML:2.0:assertion"
+	MetadataNamespace  = "urn:oasis:names:tc:SAML:2.0:metadata"
+)
+
+// The SAML 2.0 identifiers used by the service provider
+const (
+	HTTPRedirectBinding      = "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect"
+	HTTPPostBinding          = "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST"
+	StatusSuccess            = "urn:oasis:names:tc:SAML:2.0:status:Success"
+	BearerConfirmation       = "urn:oasis:names:tc:SAML:2.0:cm:bearer"
+	NameIDFormatPersistent   = "urn:oasis:names:tc:SAML:2.0:nameid-format:persistent"
+	NameIDFormatEmailAddress = "urn:oasis:names:tc:SAML:1.1:nameid-format:emailAddress"
+	NameIDFormatUnspecified  = "urn:oasis:names:tc:SAML:1.1:nameid-format:unspecified"
+	NameIDFormatEntity       = "urn:oasis:names:tc:SAML:2.0:nameid-format:entity"
+	// The signature algorithm of the AuthnRequests
+	SigAlgRSASHA256 = dsig.RSASHA256SignatureMethod
+)
+
+// The tolerated difference between the clocks of the providers by default
+const DefaultMaxClockSkew = 3 * time.Minute
+
+// The format of the xsd:dateTime sent to the identity provider
+const timeFormat = "2006-01-02T15:04:05.000Z"
+
+// The number of random bytes of the id of an AuthnRequest
+const requestIDSize = 20
+
+// Returned when the response of the identity provider is malformed or doesn't match the service provider
+var ErrInvalidResponse = errors.New("saml invalid response")
+
+// Returned when the signature of the response can't be verified with the certificates of the identity provider
+var ErrInvalidSignature = errors.New("saml invalid signature")
+
+// The identity provider trusted by the service provider
+type IdentityProvider struct {
+	EntityID string
+	// The url of the single sign on service, using the HTTP-Redirect binding
+	SSOURL string
+	// The certificates the assertions can be signed with
+	Certificates []*x509.Certificate
+}
+
+// A SAML 2.0 service provider, the AuthnRequests are sent with the HTTP-Redirect binding
+// and the responses are received with the HTTP-POST binding
+type ServiceProvider struct {
+	EntityID                    string
+	AssertionConsumerServiceURL string
+	// The key signing the AuthnRequests and its certificate, published in the metadata
+	Key              *rsa.PrivateKey
+	Certificate      *x509.Certificate
+	IdentityProvider IdentityProvider
+	// The tolerated difference between the clocks of the providers, DefaultMaxClockSkew if zero
+	MaxClockSkew time.Duration
+}
+
+// An AuthnRequest sent to the identity provider, its id is given back in the response
+type AuthnRequest struct {
+	ID           string
+	IssueInstant time.Time
+}
+
+// The identity asserted by the identity provider
+type Assertion struct {
+	// The id of the assertion, a replayed assertion has the same id
+	ID string
+	// The id of the AuthnRequest the assertion answers, as signed in its subject confirmation
+	InResponseTo string
+	// The time after which the assertion can't be accepted anymore, clock skew included
+	ExpiresAt    time.Time
+	NameID       string
+	NameIDFormat string
+	SessionIndex string
+	// The values of the attributes, by name
+	Attributes map[string][]string
+}
+
+// Return the first value of the attribute, or an empty string
+func (assertion *Assertion) Attribute(name string) string {
+	values := assertion.Attributes[name]
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// Return the metadata of the service provider, to be given to the identity provider
+func (serviceProvider *ServiceProvider) Metadata() ([]byte, error) {
+	document := etree.NewDocument()
+	document.CreateProcInst("xml", `version="1.0" encoding="UTF-8"`)
+	entityDescriptor := document.CreateElement("md:EntityDescriptor")
+	entityDescriptor.CreateAttr("xmlns:md", MetadataNamespace)
+	entityDescriptor.CreateAttr("entityID", serviceProvider.EntityID)
+	descriptor := entityDescriptor.CreateElement("md:SPSSODescriptor")
+	descriptor.CreateAttr("AuthnRequestsSigned", "true")
+	descriptor.CreateAttr("WantAssertionsSigned", "true")
+	descriptor.CreateAttr("protocolSupportEnumeration", ProtocolNamespace)
+	keyDescriptor := descriptor.CreateElement("md:KeyDescriptor")
+	keyDescriptor.CreateAttr("use", "signing")
+	keyInfo := keyDescriptor.CreateElement("ds:KeyInfo")
+	keyInfo.CreateAttr("xmlns:ds", dsig.Namespace)
+	keyInfo.CreateElement("ds:X509Data").CreateElement("ds:X509Certificate").
+		SetText(base64.StdEncoding.EncodeToString(serviceProvider.Certificate.Raw))
+	for _, format := range []string{NameIDFormatPersistent, NameIDFormatEmailAddress, NameIDFormatUnspecified} {
+		descriptor.CreateElement("md:NameIDFormat").SetText(format)
+	}
+	assertionConsumerService := descriptor.CreateElement("md:AssertionConsumerService")
+	assertionConsumerService.CreateAttr("Binding", HTTPPostBinding)
+	assertionConsumerService.CreateAttr("Location", serviceProvider.AssertionConsumerServiceURL)
+	assertionConsumerService.CreateAttr("index", "0")
+	assertionConsumerService.CreateAttr("isDefault", "true")
+	document.Indent(2)
+	return document.WriteToBytes()
+}
+
+// Generate a new AuthnRequest
+func NewAuthnRequest() (*AuthnRequest, error) {
+	b := make([]byte, requestIDSize)
+	_, err := rand.Read(b)
+	if err != nil {
+		return nil, err
+	}
+	// an xsd:ID can't start with a digit
+	return &AuthnRequest{ID: "id-" + hex.EncodeToString(b), IssueInstant: time.Now().UTC()}, nil
+}
+
+// Return the url of the single sign on service of the identity provider with the signed AuthnRequest,
+// the relay state is given back with the response
+func (serviceProvider *ServiceProvider) RedirectURL(request *AuthnRequest, relayState string) (string, error) {
+	document := etree.NewDocument()
+	authnRequest := document.CreateElement("samlp:AuthnRequest")
+	authnRequest.CreateAttr("xmlns:samlp", ProtocolNamespace)
+	authnRequest.CreateAttr("xmlns:saml", AssertionNamespace)
+	authnRequest.CreateAttr("ID", request.ID)
+	authnRequest.CreateAttr("Version", "2.0")
+	authnRequest.CreateAttr("IssueInstant", request.IssueInstant.UTC().Format(timeFormat))
+	authnRequest.CreateAttr("Destination", serviceProvider.IdentityProvider.SSOURL)
+	authnRequest.CreateAttr("ProtocolBinding", HTTPPostBinding)
+	authnRequest.CreateAttr("AssertionConsumerServiceURL", serviceProvider.AssertionConsumerServiceURL)
+	issuer := authnRequest.CreateElement("saml:Issuer")
+	issuer.CreateAttr("Format", NameIDFormatEntity)
+	issuer.SetText(serviceProvider.EntityID)
+	nameIDPolicy := authnRequest.CreateElement("samlp:NameIDPolicy")
+	nameIDPolicy.CreateAttr("AllowCreate", "true")
+	rawRequest, err := document.WriteToBytes()
+	if err != nil {
+		return "", err
+	}
+
+	// DEFLATE encoding of the HTTP-Redirect binding
+	var compressed bytes.Buffer
+	writer, err := flate.NewWriter(&compressed, flate.BestCompression)
+	if err != nil {
+		return "", err
+	}
+	_, err = writer.Write(rawRequest)
+	if err != nil {
+		return "", err
+	}
+	err = writer.Close()
+	if err != nil {
+		return "", err
+	}
+
+	// the signature covers the query string in this exact order
+	query := "SAMLRequest=" + url.QueryEscape(base64.StdEncoding.EncodeToString(compressed.Bytes()))
+	if relayState != "" {
+		query += "&RelayState=" + url.QueryEscape(relayState)
+	}
+	query += "&SigAlg=" + url.QueryEscape(SigAlgRSASHA256)
+	hash := sha256.Sum256([]byte(query))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, serviceProvider.Key, crypto.SHA256, hash[:])
+	if err != nil {
+		return "", err
+	}
+	query += "&Signature=" + url.QueryEscape(base64.StdEncoding.EncodeToString(signature))
+
+	separator := "?"
+	if strings.Contains(serviceProvider.IdentityProvider.SSOURL, "?") {
+		separator = "&"
+	}
+	return serviceProvider.IdentityProvider.SSOURL + separator + query, nil
+}
+
+// Verify the base64 encoded response posted by the identity provider and return its assertion
+//
+// The assertion must be signed by the identity provider, the response may also be signed.
+// The destination, the issuers, the audience, the validity period and the bearer confirmation are checked.
+// The caller must check that the assertion answers one of its pending AuthnRequests,
+// unsolicited responses and encrypted assertions are refused.
+func (serviceProvider *ServiceProvider) ParseResponse(encodedResponse string) (*Assertion, error) {
+	rawResponse, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(encodedResponse), ""))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidResponse, err)
+	}
+	// the XML must be parsed the same way by the validation of the signature and by the service provider
+	err = xrv.Validate(bytes.NewReader(rawResponse))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidResponse, err)
+	}
+	document := etree.NewDocument()
+	err = document.ReadFromBytes(rawResponse)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidResponse, err)
+	}
+	response := document.Root()
+	if response == nil || !is(response, ProtocolNamespace, "Response") {
+		return nil, fmt.Errorf("%w: not a response", ErrInvalidResponse)
+	}
+	if child(response, dsig.Namespace, dsig.SignatureTag) != nil {
+		response, err = serviceProvider.verifySignature(response)
+		if err != nil {
+			return nil, err
+		}
+	}
+	err = serviceProvider.checkResponse(response)
+	if err != nil {
+		return nil, err
+	}
+	inResponseTo := response.SelectAttrValue("InResponseTo", "")
+	if inResponseTo == "" {
+		return nil, fmt.Errorf("%w: unsolicited response", ErrInvalidResponse)
+	}
+
+	if len(children(response, AssertionNamespace, "EncryptedAssertion")) != 0 {
+		return nil, fmt.Errorf("%w: encrypted assertions are not supported", ErrInvalidResponse)
+	}
+	assertions := children(response, AssertionNamespace, "Assertion")
+	if len(assertions) != 1 {
+		return nil, fmt.Errorf("%w: expected one assertion, got %d", ErrInvalidResponse, len(assertions))
+	}
+	// only the signed content of the assertion is read
+	assertion, err := serviceProvider.verifySignature(assertions[0])
+	if err != nil {
+		return nil, err
+	}
+	return serviceProvider.parseAssertion(assertion, inResponseTo)
+}
+
+// Check the response is a successful answer to the service provider
+func (serviceProvider *ServiceProvider) checkResponse(response *etree.Element) error {
+	if response.SelectAttrValue("Version", "") != "2.0" {
+		return fmt.Errorf("%w: unsupported version", ErrInvalidResponse)
+	}
+	destination := response.SelectAttrValue("Destination", "")
+	if destination != "" && destination != serviceProvider.AssertionConsumerServiceURL {
+		return fmt.Errorf("%w: wrong destination %q", ErrInvalidResponse, destination)
+	}
+	issuer := child(response, AssertionNamespace, "Issuer")
+	if issuer != nil && strings.TrimSpace(issuer.Text()) != serviceProvider.IdentityProvider.EntityID {
+		return fmt.Errorf("%w: wrong issuer %q", ErrInvalidResponse, issuer.Text())
+	}
+	statusCode := child(child(response, ProtocolNamespace, "Status"), ProtocolNamespace, "StatusCode")
+	if statusCode == nil {
+		return fmt.Errorf("%w: no status", ErrInvalidResponse)
+	}
+	status := statusCode.SelectAttrValue("Value", "")
+	if status != StatusSuccess {
+		return fmt.Errorf("%w: the identity provider answered %q", ErrInvalidResponse, status)
+	}
+	return nil
+}
+
+// Check the conditions of the signed assertion and return the identity it asserts
+func (serviceProvider *ServiceProvider) parseAssertion(assertion *etree.Element, inResponseTo string) (*Assertion, error) {
+	now := time.Now()
+	maxClockSkew := serviceProvider.MaxClockSkew
+	if maxClockSkew == 0 {
+		maxClockSkew = DefaultMaxClockSkew
+	}
+
+	issuer := child(assertion, AssertionNamespace, "Issuer")
+	if issuer == nil || strings.TrimSpace(issuer.Text()) != serviceProvider.IdentityProvider.EntityID {
+		return nil, fmt.Errorf("%w: wrong assertion issuer", ErrInvalidResponse)
+	}
+
+	conditions := child(assertion, AssertionNamespace, "Conditions")
+	if conditions == nil {
+		return nil, fmt.Errorf("%w: no conditions", ErrInvalidResponse)
+	}
+	err := checkValidity(conditions, now, maxClockSkew)
+	if err != nil {
+		return nil, err
+	}
+	if !hasAudience(conditions, serviceProvider.EntityID) {
+		return nil, fmt.Errorf("%w: the service provider is not in the audience", ErrInvalidResponse)
+	}
+
+	subject := child(assertion, AssertionNamespace, "Subject")
+	nameID := child(subject, AssertionNamespace, "NameID")
+	if nameID == nil || strings.TrimSpace(nameID.Text()) == "" {
+		return nil, fmt.Errorf("%w: no name id", ErrInvalidResponse)
+	}
+	confirmationExpiresAt, ok := serviceProvider.findBearerConfirmation(subject, inResponseTo, now, maxClockSkew)
+	if !ok {
+		return nil, fmt.Errorf("%w: no valid bearer subject confirmation", ErrInvalidResponse)
+	}
+	id := assertion.SelectAttrValue("ID", "")
+	if id == "" {
+		return nil, fmt.Errorf("%w: no assertion id", ErrInvalidResponse)
+	}
+
+	result := &Assertion{
+		ID:           id,
+		InResponseTo: inResponseTo,
+		ExpiresAt:    confirmationExpiresAt.Add(maxClockSkew),
+		NameID:       strings.TrimSpace(nameID.Text()),
+		NameIDFormat: nameID.SelectAttrValue("Format", NameIDFormatUnspecified),
+		Attributes:   map[string][]string{},
+	}
+	authnStatement := child(assertion, AssertionNamespace, "AuthnStatement")
+	if authnStatement != nil {
+		result.SessionIndex = authnStatement.SelectAttrValue("SessionIndex", "")
+	}
+	for _, attributeStatement := range children(assertion, AssertionNamespace, "AttributeStatement") {
+		for _, attribute := range children(attributeStatement, AssertionNamespace, "Attribute") {
+			name := attribute.SelectAttrValue("Name", "")
+			for _, value := range children(attribute, AssertionNamespace, "AttributeValue") {
+				result.Attributes[name] = append(result.Attributes[name], strings.TrimSpace(value.Text()))
+			}
+		}
+	}
+	return result, nil
+}
+
+// Return the expiration of a bearer confirmation of the subject the service provider can use for this request,
+// and false if there is none
+//
+// The confirmation must be bound to the request by its InResponseTo, the one of the response is not always signed.
+func (serviceProvider *ServiceProvider) findBearerConfirmation(
+	subject *etree.Element,
+	inResponseTo string,
+	now time.Time,
+	maxClockSkew time.Duration,
+) (time.Time, bool) {
+	for _, confirmation := range children(subject, AssertionNamespace, "SubjectConfirmation") {
+		if confirmation.SelectAttrValue("Method", "") != BearerConfirmation {
+			continue
+		}
+		data := child(confirmation, AssertionNamespace, "SubjectConfirmationData")
+		if data == nil ||
+			data.SelectAttrValue("Recipient", "") != serviceProvider.AssertionConsumerServiceURL ||
+			data.SelectAttrValue("InResponseTo", "") != inResponseTo {
+			continue
+		}
+		// a bearer confirmation must expire
+		notOnOrAfter, err := time.Parse(time.RFC3339, data.SelectAttrValue("NotOnOrAfter", ""))
+		if err != nil || checkValidity(data, now, maxClockSkew) != nil {
+			continue
+		}
+		return notOnOrAfter, true
+	}
+	return time.Time{}, false
+}
+
+// Verify the enveloped signature of the element with the certificates of the identity provider,
+// and return the signed element
+func (serviceProvider *ServiceProvider) verifySignature(element *etree.Element) (*etree.Element, error) {
+	// keep the namespaces declared by the parents
+	context, err := etreeutils.NSBuildParentContext(element)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidSignature, err)
+	}
+	context, err = context.SubContext(element)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidSignature, err)
+	}
+	detached, err := etreeutils.NSDetatch(context, element)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidSignature, err)
+	}
+	validationContext := dsig.NewDefaultValidationContext(&dsig.MemoryX509CertificateStore{
+		Roots: serviceProvider.IdentityProvider.Certificates,
+	})
+	signed, err := validationContext.Validate(detached)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidSignature, err)
+	}
+	return signed, nil
+}
+
+// Check the NotBefore and NotOnOrAfter attributes of the element
+func checkValidity(element *etree.Element, now time.Time, maxClockSkew time.Duration) error {
+	notBefore := element.SelectAttrValue("NotBefore", "")
+	if notBefore != "" {
+		notBeforeTime, err := time.Parse(time.RFC3339, notBefore)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidResponse, err)
+		}
+		if now.Add(maxClockSkew).Before(notBeforeTime) {
+			return fmt.Errorf("%w: the assertion is not valid yet", ErrInvalidResponse)
+		}
+	}
+	notOnOrAfter := element.SelectAttrValue("NotOnOrAfter", "")
+	if notOnOrAfter != "" {
+		notOnOrAfterTime, err := time.Parse(time.RFC3339, notOnOrAfter)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidResponse, err)
+		}
+		if !now.Add(-maxClockSkew).Before(notOnOrAfterTime) {
+			return fmt.Errorf("%w: the assertion is expired", ErrInvalidResponse)
+		}
+	}
+	return nil
+}
+
+// Return true if every audience restriction of the conditions includes the entity
+func hasAudience(conditions *etree.Element, entityID string) bool {
+	restrictions := children(conditions, AssertionNamespace, "AudienceRestriction")
+	if len(restrictions) == 0 {
+		return false
+	}
+	for _, restriction := range restrictions {
+		found := false
+		for _, audience := range children(restriction, AssertionNamespace, "Audience") {
+			if strings.TrimSpace(audience.Text()) == entityID {
+				found = true
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// Return true if the element has the namespace and the tag, whatever its prefix
+func is(element *etree.Element, namespace, tag string) bool {
+	return element.Tag == tag && element.NamespaceURI() == namespace
+}
+
+// Return the first child element with the namespace and the tag, or nil
+func child(element *etree.Element, namespace, tag string) *etree.Element {
+	found := children(element, namespace, tag)
+	if len(found) == 0 {
+		return nil
+	}
+	return found[0]
+}
+
+// Return the child elements with the namespace and the tag
+func children(element *etree.Element, namespace, tag string) []*etree.Element {
+	if element == nil {
+		return nil
+	}
+	var found []*etree.Element
+	for _, childElement := range element.ChildElements() {
+		if is(childElement, namespace, tag) {
+			found = append(found, childElement)
+		}
+	}
+	return found
+}
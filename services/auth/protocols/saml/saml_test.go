@@ -0,0 +1,180 @@
+package saml_test
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ditrit/badaas/services/auth/protocols/saml"
+	"github.com/ditrit/badaas/services/auth/protocols/saml/samltest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	entityID = "https://badaas.example.com/saml/metadata"
+	acsURL   = "https://badaas.example.com/login/saml/acs"
+)
+
+func setupProviders(t *testing.T) (*samltest.IdentityProvider, *saml.ServiceProvider) {
+	identityProvider, err := samltest.NewIdentityProvider("https://idp.example.com")
+	require.NoError(t, err)
+	key, certificate, err := samltest.NewCertificate("badaas")
+	require.NoError(t, err)
+	return identityProvider, &saml.ServiceProvider{
+		EntityID:                    entityID,
+		AssertionConsumerServiceURL: acsURL,
+		Key:                         key,
+		Certificate:                 certificate,
+		IdentityProvider:            identityProvider.Descriptor(),
+	}
+}
+
+// Send an AuthnRequest to the identity provider and return its response for the user
+func login(t *testing.T, identityProvider *samltest.IdentityProvider, serviceProvider *saml.ServiceProvider) *samltest.Response {
+	request, err := saml.NewAuthnRequest()
+	require.NoError(t, err)
+	redirectURL, err := serviceProvider.RedirectURL(request, "")
+	require.NoError(t, err)
+	receivedRequest, err := identityProvider.ParseAuthnRequest(redirectURL, serviceProvider.Certificate)
+	require.NoError(t, err)
+	return identityProvider.NewResponse(receivedRequest, "bob-id")
+}
+
+func TestMetadata(t *testing.T) {
+	_, serviceProvider := setupProviders(t)
+
+	metadata, err := serviceProvider.Metadata()
+	require.NoError(t, err)
+	assert.Contains(t, string(metadata), `entityID="`+entityID+`"`)
+	assert.Contains(t, string(metadata), `AuthnRequestsSigned="true"`)
+	assert.Contains(t, string(metadata), `Location="`+acsURL+`"`)
+	assert.Contains(t, string(metadata), base64.StdEncoding.EncodeToString(serviceProvider.Certificate.Raw))
+}
+
+func TestRedirectURL(t *testing.T) {
+	identityProvider, serviceProvider := setupProviders(t)
+	request, err := saml.NewAuthnRequest()
+	require.NoError(t, err)
+
+	redirectURL, err := serviceProvider.RedirectURL(request, "relay")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(redirectURL, identityProvider.SSOURL+"?SAMLRequest="))
+	receivedRequest, err := identityProvider.ParseAuthnRequest(redirectURL, serviceProvider.Certificate)
+	require.NoError(t, err)
+	assert.Equal(t, &samltest.AuthnRequest{
+		ID:                          request.ID,
+		Issuer:                      entityID,
+		AssertionConsumerServiceURL: acsURL,
+		RelayState:                  "relay",
+	}, receivedRequest)
+
+	// the signature is checked with the key of the service provider
+	_, otherCertificate, err := samltest.NewCertificate("other")
+	require.NoError(t, err)
+	_, err = identityProvider.ParseAuthnRequest(redirectURL, otherCertificate)
+	assert.Error(t, err)
+}
+
+func TestParseResponse(t *testing.T) {
+	for _, signResponse := range []bool{false, true} {
+		identityProvider, serviceProvider := setupProviders(t)
+		response := login(t, identityProvider, serviceProvider)
+		response.SignResponse = signResponse
+		response.Attributes = map[string][]string{"email": {"bob@email.com"}, "groups": {"admins", "users"}}
+		encodedResponse, err := identityProvider.Sign(response)
+		require.NoError(t, err)
+
+		assertion, err := serviceProvider.ParseResponse(encodedResponse)
+		require.NoError(t, err)
+		assert.WithinDuration(t, response.NotOnOrAfter.Add(saml.DefaultMaxClockSkew), assertion.ExpiresAt, time.Second)
+		assertion.ExpiresAt = time.Time{}
+		assert.Equal(t, &saml.Assertion{
+			ID:           "assertion-" + response.InResponseTo,
+			InResponseTo: response.InResponseTo,
+			NameID:       "bob-id",
+			NameIDFormat: saml.NameIDFormatPersistent,
+			SessionIndex: "session-index",
+			Attributes:   map[string][]string{"email": {"bob@email.com"}, "groups": {"admins", "users"}},
+		}, assertion)
+		assert.Equal(t, "bob@email.com", assertion.Attribute("email"))
+	}
+}
+
+func TestParseResponseRefused(t *testing.T) {
+	_, otherKeyCertificate, err := samltest.NewCertificate("other")
+	require.NoError(t, err)
+	testCases := []struct {
+		name     string
+		modify   func(*samltest.IdentityProvider, *samltest.Response)
+		expected error
+	}{
+		{"unsigned assertion", func(_ *samltest.IdentityProvider, response *samltest.Response) {
+			response.UnsignedAssertion = true
+			response.SignResponse = true
+		}, saml.ErrInvalidSignature},
+		{"untrusted certificate", func(identityProvider *samltest.IdentityProvider, _ *samltest.Response) {
+			identityProvider.Certificate = otherKeyCertificate
+		}, saml.ErrInvalidSignature},
+		{"other audience", func(_ *samltest.IdentityProvider, response *samltest.Response) {
+			response.Audience = "https://other.example.com"
+		}, saml.ErrInvalidResponse},
+		{"other destination", func(_ *samltest.IdentityProvider, response *samltest.Response) {
+			response.Destination = "https://other.example.com/acs"
+		}, saml.ErrInvalidResponse},
+		{"expired", func(_ *samltest.IdentityProvider, response *samltest.Response) {
+			response.NotBefore = time.Now().Add(-time.Hour)
+			response.NotOnOrAfter = time.Now().Add(-10 * time.Minute)
+		}, saml.ErrInvalidResponse},
+		{"not valid yet", func(_ *samltest.IdentityProvider, response *samltest.Response) {
+			response.NotBefore = time.Now().Add(10 * time.Minute)
+		}, saml.ErrInvalidResponse},
+		{"unsolicited", func(_ *samltest.IdentityProvider, response *samltest.Response) {
+			response.InResponseTo = ""
+		}, saml.ErrInvalidResponse},
+		{"unbound confirmation", func(_ *samltest.IdentityProvider, response *samltest.Response) {
+			response.UnboundConfirmation = true
+		}, saml.ErrInvalidResponse},
+		{"failure status", func(_ *samltest.IdentityProvider, response *samltest.Response) {
+			response.Status = "urn:oasis:names:tc:SAML:2.0:status:Requester"
+		}, saml.ErrInvalidResponse},
+		{"other issuer", func(identityProvider *samltest.IdentityProvider, _ *samltest.Response) {
+			identityProvider.EntityID = "https://other-idp.example.com"
+		}, saml.ErrInvalidResponse},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			identityProvider, serviceProvider := setupProviders(t)
+			response := login(t, identityProvider, serviceProvider)
+			testCase.modify(identityProvider, response)
+			encodedResponse, err := identityProvider.Sign(response)
+			require.NoError(t, err)
+
+			_, err = serviceProvider.ParseResponse(encodedResponse)
+			assert.ErrorIs(t, err, testCase.expected)
+		})
+	}
+}
+
+func TestParseResponseTampered(t *testing.T) {
+	identityProvider, serviceProvider := setupProviders(t)
+	response := login(t, identityProvider, serviceProvider)
+	encodedResponse, err := identityProvider.Sign(response)
+	require.NoError(t, err)
+	rawResponse, err := base64.StdEncoding.DecodeString(encodedResponse)
+	require.NoError(t, err)
+
+	tampered := strings.Replace(string(rawResponse), "bob-id", "admin-id", 1)
+	_, err = serviceProvider.ParseResponse(base64.StdEncoding.EncodeToString([]byte(tampered)))
+	assert.ErrorIs(t, err, saml.ErrInvalidSignature)
+}
+
+func TestParseResponseMalformed(t *testing.T) {
+	_, serviceProvider := setupProviders(t)
+
+	_, err := serviceProvider.ParseResponse("not base64")
+	assert.ErrorIs(t, err, saml.ErrInvalidResponse)
+	_, err = serviceProvider.ParseResponse(base64.StdEncoding.EncodeToString([]byte("<foo/>")))
+	assert.ErrorIs(t, err, saml.ErrInvalidResponse)
+}
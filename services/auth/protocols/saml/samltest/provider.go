@@ -0,0 +1,277 @@
+// Package samltest provides a stub SAML identity provider to test the service provider.
+package samltest
+
+import (
+	"bytes"
+	"compress/flate"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"errors"
+	"io"
+	"math/big"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/ditrit/badaas/services/auth/protocols/saml"
+	dsig "github.com/russellhaering/goxmldsig"
+)
+
+// The format of the xsd:dateTime of the responses
+const timeFormat = "2006-01-02T15:04:05Z"
+
+// An AuthnRequest received by the identity provider
+type AuthnRequest struct {
+	ID                          string
+	Issuer                      string
+	AssertionConsumerServiceURL string
+	RelayState                  string
+}
+
+// A response of the identity provider, the assertion is always signed and the response only if SignResponse is true
+type Response struct {
+	InResponseTo string
+	// The destination of the response and the recipient of the subject confirmation
+	Destination  string
+	Audience     string
+	NameID       string
+	NameIDFormat string
+	SessionIndex string
+	Attributes   map[string][]string
+	NotBefore    time.Time
+	NotOnOrAfter time.Time
+	Status       string
+	SignResponse bool
+	// Don't sign the assertion, to test its verification
+	UnsignedAssertion bool
+	// Leave the InResponseTo out of the subject confirmation, to test its binding to the request
+	UnboundConfirmation bool
+}
+
+// A stub identity provider with a locally generated certificate
+type IdentityProvider struct {
+	EntityID    string
+	SSOURL      string
+	Key         *rsa.PrivateKey
+	Certificate *x509.Certificate
+}
+
+// Generate the key and the certificate of a new identity provider
+func NewIdentityProvider(entityID string) (*IdentityProvider, error) {
+	key, certificate, err := NewCertificate(entityID)
+	if err != nil {
+		return nil, err
+	}
+	return &IdentityProvider{
+		EntityID:    entityID,
+		SSOURL:      "https://idp.example.com/sso",
+		Key:         key,
+		Certificate: certificate,
+	}, nil
+}
+
+// Generate an RSA key and a self-signed certificate valid for a day
+func NewCertificate(commonName string) (*rsa.PrivateKey, *x509.Certificate, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	raw, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	certificate, err := x509.ParseCertificate(raw)
+	if err != nil {
+		return nil, nil, err
+	}
+	return key, certificate, nil
+}
+
+// Return the identity provider as trusted by the service provider
+func (identityProvider *IdentityProvider) Descriptor() saml.IdentityProvider {
+	return saml.IdentityProvider{
+		EntityID:     identityProvider.EntityID,
+		SSOURL:       identityProvider.SSOURL,
+		Certificates: []*x509.Certificate{identityProvider.Certificate},
+	}
+}
+
+// Verify the signature of the redirection of the service provider and return its AuthnRequest
+func (identityProvider *IdentityProvider) ParseAuthnRequest(redirectURL string, spCertificate *x509.Certificate) (*AuthnRequest, error) {
+	parsedURL, err := url.Parse(redirectURL)
+	if err != nil {
+		return nil, err
+	}
+	query := parsedURL.Query()
+	if query.Get("SigAlg") != saml.SigAlgRSASHA256 {
+		return nil, errors.New("unsupported signature algorithm")
+	}
+	// the signed content is the raw query string without the signature
+	signedQuery := parsedURL.RawQuery[:strings.Index(parsedURL.RawQuery, "&Signature=")]
+	signature, err := base64.StdEncoding.DecodeString(query.Get("Signature"))
+	if err != nil {
+		return nil, err
+	}
+	hash := sha256.Sum256([]byte(signedQuery))
+	err = rsa.VerifyPKCS1v15(spCertificate.PublicKey.(*rsa.PublicKey), crypto.SHA256, hash[:], signature)
+	if err != nil {
+		return nil, err
+	}
+
+	compressed, err := base64.StdEncoding.DecodeString(query.Get("SAMLRequest"))
+	if err != nil {
+		return nil, err
+	}
+	rawRequest, err := io.ReadAll(flate.NewReader(bytes.NewReader(compressed)))
+	if err != nil {
+		return nil, err
+	}
+	document := etree.NewDocument()
+	err = document.ReadFromBytes(rawRequest)
+	if err != nil {
+		return nil, err
+	}
+	root := document.Root()
+	if root == nil || root.Tag != "AuthnRequest" {
+		return nil, errors.New("not an AuthnRequest")
+	}
+	issuer := root.SelectElement("Issuer")
+	if issuer == nil {
+		return nil, errors.New("no issuer")
+	}
+	return &AuthnRequest{
+		ID:                          root.SelectAttrValue("ID", ""),
+		Issuer:                      issuer.Text(),
+		AssertionConsumerServiceURL: root.SelectAttrValue("AssertionConsumerServiceURL", ""),
+		RelayState:                  query.Get("RelayState"),
+	}, nil
+}
+
+// Return a successful response to the request, valid for five minutes
+func (identityProvider *IdentityProvider) NewResponse(request *AuthnRequest, nameID string) *Response {
+	now := time.Now()
+	return &Response{
+		InResponseTo: request.ID,
+		Destination:  request.AssertionConsumerServiceURL,
+		Audience:     request.Issuer,
+		NameID:       nameID,
+		NameIDFormat: saml.NameIDFormatPersistent,
+		SessionIndex: "session-index",
+		Attributes:   map[string][]string{},
+		NotBefore:    now.Add(-time.Minute),
+		NotOnOrAfter: now.Add(5 * time.Minute),
+		Status:       saml.StatusSuccess,
+	}
+}
+
+// Build and sign the response, and return it base64 encoded as posted to the service provider
+func (identityProvider *IdentityProvider) Sign(response *Response) (string, error) {
+	now := time.Now().UTC().Format(timeFormat)
+	notBefore := response.NotBefore.UTC().Format(timeFormat)
+	notOnOrAfter := response.NotOnOrAfter.UTC().Format(timeFormat)
+
+	responseElement := etree.NewElement("samlp:Response")
+	responseElement.CreateAttr("xmlns:samlp", saml.ProtocolNamespace)
+	responseElement.CreateAttr("xmlns:saml", saml.AssertionNamespace)
+	responseElement.CreateAttr("ID", "response-"+response.InResponseTo)
+	responseElement.CreateAttr("Version", "2.0")
+	responseElement.CreateAttr("IssueInstant", now)
+	responseElement.CreateAttr("Destination", response.Destination)
+	responseElement.CreateAttr("InResponseTo", response.InResponseTo)
+	responseElement.CreateElement("saml:Issuer").SetText(identityProvider.EntityID)
+	responseElement.CreateElement("samlp:Status").CreateElement("samlp:StatusCode").CreateAttr("Value", response.Status)
+
+	assertion := etree.NewElement("saml:Assertion")
+	assertion.CreateAttr("xmlns:saml", saml.AssertionNamespace)
+	assertion.CreateAttr("ID", "assertion-"+response.InResponseTo)
+	assertion.CreateAttr("Version", "2.0")
+	assertion.CreateAttr("IssueInstant", now)
+	assertion.CreateElement("saml:Issuer").SetText(identityProvider.EntityID)
+	subject := assertion.CreateElement("saml:Subject")
+	nameID := subject.CreateElement("saml:NameID")
+	nameID.CreateAttr("Format", response.NameIDFormat)
+	nameID.SetText(response.NameID)
+	confirmation := subject.CreateElement("saml:SubjectConfirmation")
+	confirmation.CreateAttr("Method", saml.BearerConfirmation)
+	confirmationData := confirmation.CreateElement("saml:SubjectConfirmationData")
+	if !response.UnboundConfirmation {
+		confirmationData.CreateAttr("InResponseTo", response.InResponseTo)
+	}
+	confirmationData.CreateAttr("NotOnOrAfter", notOnOrAfter)
+	confirmationData.CreateAttr("Recipient", response.Destination)
+	conditions := assertion.CreateElement("saml:Conditions")
+	conditions.CreateAttr("NotBefore", notBefore)
+	conditions.CreateAttr("NotOnOrAfter", notOnOrAfter)
+	conditions.CreateElement("saml:AudienceRestriction").CreateElement("saml:Audience").SetText(response.Audience)
+	authnStatement := assertion.CreateElement("saml:AuthnStatement")
+	authnStatement.CreateAttr("AuthnInstant", now)
+	authnStatement.CreateAttr("SessionIndex", response.SessionIndex)
+	authnStatement.CreateElement("saml:AuthnContext").CreateElement("saml:AuthnContextClassRef").
+		SetText("urn:oasis:names:tc:SAML:2.0:ac:classes:PasswordProtectedTransport")
+	if len(response.Attributes) != 0 {
+		attributeStatement := assertion.CreateElement("saml:AttributeStatement")
+		for name, values := range response.Attributes {
+			attribute := attributeStatement.CreateElement("saml:Attribute")
+			attribute.CreateAttr("Name", name)
+			for _, value := range values {
+				attribute.CreateElement("saml:AttributeValue").SetText(value)
+			}
+		}
+	}
+
+	var err error
+	if !response.UnsignedAssertion {
+		assertion, err = identityProvider.signElement(assertion)
+		if err != nil {
+			return "", err
+		}
+	}
+	responseElement.AddChild(assertion)
+	if response.SignResponse {
+		responseElement, err = identityProvider.signElement(responseElement)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	document := etree.NewDocument()
+	document.SetRoot(responseElement)
+	rawResponse, err := document.WriteToBytes()
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(rawResponse), nil
+}
+
+// Return the element with an enveloped signature placed after its issuer, as required by the schema
+func (identityProvider *IdentityProvider) signElement(element *etree.Element) (*etree.Element, error) {
+	signingContext, err := dsig.NewSigningContext(identityProvider.Key, [][]byte{identityProvider.Certificate.Raw})
+	if err != nil {
+		return nil, err
+	}
+	signingContext.Canonicalizer = dsig.MakeC14N10ExclusiveCanonicalizerWithPrefixList("")
+	err = signingContext.SetSignatureMethod(dsig.RSASHA256SignatureMethod)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := signingContext.ConstructSignature(element, true)
+	if err != nil {
+		return nil, err
+	}
+	signed := element.Copy()
+	signed.InsertChildAt(1, signature)
+	return signed, nil
+}
@@ -0,0 +1,112 @@
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// The parameters of the generated codes, the defaults of the authenticator apps (RFC 6238)
+const (
+	Digits = 6
+	Period = 30 * time.Second
+	// The number of periods before and after the current one in which a code is still accepted,
+	// to tolerate the clock drift of the devices
+	Skew = 1
+)
+
+// The number of random bytes of a secret (160 bits, as recommended by RFC 4226)
+const secretSize = 20
+
+var base32NoPadding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// Generate a random secret, encoded in base32 without padding
+func GenerateSecret() (string, error) {
+	b := make([]byte, secretSize)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return base32NoPadding.EncodeToString(b), nil
+}
+
+// Return the otpauth:// uri of the secret, to be displayed as a QR code to the user
+//
+// See https://github.com/google/google-authenticator/wiki/Key-Uri-Format
+func ProvisioningURI(issuer, accountName, secret string) string {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(Digits))
+	query.Set("period", fmt.Sprint(int(Period.Seconds())))
+	return (&url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + issuer + ":" + accountName,
+		RawQuery: query.Encode(),
+	}).String()
+}
+
+// Return the time step of t
+func Step(t time.Time) int64 {
+	return t.Unix() / int64(Period.Seconds())
+}
+
+// Return the code of the secret for the time t
+func GenerateCode(secret string, t time.Time) (string, error) {
+	key, err := decodeSecret(secret)
+	if err != nil {
+		return "", err
+	}
+	return generateCode(key, Step(t)), nil
+}
+
+// Check the code against the secret at the time t
+//
+// The codes of the steps lower or equal to lastUsedStep are refused, so that a code can't be replayed.
+// Return the step of the code if it is valid.
+func Validate(secret, code string, t time.Time, lastUsedStep int64) (int64, bool) {
+	key, err := decodeSecret(secret)
+	if err != nil || len(code) != Digits {
+		return 0, false
+	}
+	currentStep := Step(t)
+	for step := currentStep - Skew; step <= currentStep+Skew; step++ {
+		if step <= lastUsedStep {
+			continue
+		}
+		if subtle.ConstantTimeCompare([]byte(generateCode(key, step)), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// Decode a base32 secret, the padding and the case are optional
+func decodeSecret(secret string) ([]byte, error) {
+	return base32NoPadding.DecodeString(strings.ToUpper(strings.TrimRight(secret, "=")))
+}
+
+// Compute the HOTP code of the counter (RFC 4226)
+func generateCode(key []byte, counter int64) string {
+	message := make([]byte, 8)
+	binary.BigEndian.PutUint64(message, uint64(counter))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(message)
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	modulo := uint32(1)
+	for i := 0; i < Digits; i++ {
+		modulo *= 10
+	}
+	return fmt.Sprintf("%0*d", Digits, value%modulo)
+}
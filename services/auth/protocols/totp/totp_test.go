@@ -0,0 +1,90 @@
+package totp_test
+
+import (
+	"encoding/base32"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/ditrit/badaas/services/auth/protocols/totp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The SHA1 secret of the test vectors of RFC 6238
+var rfcSecret = base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+
+func TestGenerateCodeRFC6238Vectors(t *testing.T) {
+	// the RFC gives 8 digits codes, the 6 last digits are the 6 digits codes
+	vectors := map[int64]string{
+		59:          "287082",
+		1111111109:  "081804",
+		1111111111:  "050471",
+		1234567890:  "005924",
+		2000000000:  "279037",
+		20000000000: "353130",
+	}
+	for unix, expected := range vectors {
+		code, err := totp.GenerateCode(rfcSecret, time.Unix(unix, 0))
+		require.NoError(t, err)
+		assert.Equal(t, expected, code, "at %d", unix)
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	secret, err := totp.GenerateSecret()
+	require.NoError(t, err)
+	assert.Len(t, secret, 32)
+	otherSecret, err := totp.GenerateSecret()
+	require.NoError(t, err)
+	assert.NotEqual(t, secret, otherSecret)
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	code, err := totp.GenerateCode(rfcSecret, now)
+	require.NoError(t, err)
+
+	step, ok := totp.Validate(rfcSecret, code, now, 0)
+	assert.True(t, ok)
+	assert.Equal(t, totp.Step(now), step)
+
+	// accepted during the next period to tolerate the clock drift
+	_, ok = totp.Validate(rfcSecret, code, now.Add(totp.Period), 0)
+	assert.True(t, ok)
+
+	// refused after
+	_, ok = totp.Validate(rfcSecret, code, now.Add(2*totp.Period), 0)
+	assert.False(t, ok)
+}
+
+func TestValidateRefusesReplay(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	code, err := totp.GenerateCode(rfcSecret, now)
+	require.NoError(t, err)
+
+	_, ok := totp.Validate(rfcSecret, code, now, totp.Step(now))
+	assert.False(t, ok)
+}
+
+func TestValidateRefusesMalformedCodes(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	_, ok := totp.Validate(rfcSecret, "", now, 0)
+	assert.False(t, ok)
+	_, ok = totp.Validate(rfcSecret, "0059240", now, 0)
+	assert.False(t, ok)
+	_, ok = totp.Validate("not base32 !", "005924", now, 0)
+	assert.False(t, ok)
+}
+
+func TestProvisioningURI(t *testing.T) {
+	uri, err := url.Parse(totp.ProvisioningURI("badaas", "bob@email.com", "JBSWY3DPEHPK3PXP"))
+	require.NoError(t, err)
+	assert.Equal(t, "otpauth", uri.Scheme)
+	assert.Equal(t, "totp", uri.Host)
+	assert.Equal(t, "/badaas:bob@email.com", uri.Path)
+	assert.Equal(t, "JBSWY3DPEHPK3PXP", uri.Query().Get("secret"))
+	assert.Equal(t, "badaas", uri.Query().Get("issuer"))
+	assert.Equal(t, "6", uri.Query().Get("digits"))
+	assert.Equal(t, "30", uri.Query().Get("period"))
+}
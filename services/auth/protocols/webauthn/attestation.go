@@ -0,0 +1,77 @@
+package webauthn
+
+import (
+	"crypto/x509"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// The attestation object returned by the authenticator during a registration
+type attestationObject struct {
+	Format            string          `cbor:"fmt"`
+	Statement         cbor.RawMessage `cbor:"attStmt"`
+	AuthenticatorData []byte          `cbor:"authData"`
+
+	authenticatorData *authenticatorData
+}
+
+// The statement of the packed attestation format
+type packedStatement struct {
+	Algorithm    int64    `cbor:"alg"`
+	Signature    []byte   `cbor:"sig"`
+	Certificates [][]byte `cbor:"x5c"`
+}
+
+// Parse an attestation object and its authenticator data
+func parseAttestationObject(data []byte) (*attestationObject, error) {
+	var attestation attestationObject
+	err := cbor.Unmarshal(data, &attestation)
+	if err != nil {
+		return nil, verificationError("malformed attestation object: %s", err)
+	}
+	attestation.authenticatorData, err = parseAuthenticatorData(attestation.AuthenticatorData)
+	if err != nil {
+		return nil, err
+	}
+	return &attestation, nil
+}
+
+// Verify the attestation statement, only the none and packed formats are accepted
+//
+// The certificates of a packed attestation are not checked against trust anchors,
+// since the options ask the authenticators not to identify themselves.
+func (attestation *attestationObject) verify(credentialPublicKey *publicKey, clientDataHash []byte) error {
+	switch attestation.Format {
+	case "none":
+		var statement map[string]any
+		err := cbor.Unmarshal(attestation.Statement, &statement)
+		if err != nil || len(statement) != 0 {
+			return verificationError("the none attestation statement must be empty")
+		}
+		return nil
+	case "packed":
+		var statement packedStatement
+		err := cbor.Unmarshal(attestation.Statement, &statement)
+		if err != nil {
+			return verificationError("malformed packed attestation statement: %s", err)
+		}
+		signedData := append(append([]byte{}, attestation.AuthenticatorData...), clientDataHash...)
+		if len(statement.Certificates) == 0 {
+			// self attestation, signed with the key of the new credential
+			if statement.Algorithm != credentialPublicKey.algorithm {
+				return verificationError("the self attestation algorithm doesn't match the credential")
+			}
+			return credentialPublicKey.verify(signedData, statement.Signature)
+		}
+		certificate, err := x509.ParseCertificate(statement.Certificates[0])
+		if err != nil {
+			return verificationError("malformed attestation certificate: %s", err)
+		}
+		attestationKey := &publicKey{algorithm: statement.Algorithm, key: certificate.PublicKey}
+		if !attestationKey.matchesAlgorithm() {
+			return verificationError("the attestation certificate doesn't match the algorithm %d", statement.Algorithm)
+		}
+		return attestationKey.verify(signedData, statement.Signature)
+	}
+	return verificationError("unsupported attestation format %q", attestation.Format)
+}
@@ -0,0 +1,88 @@
+package webauthn
+
+import (
+	"encoding/binary"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// The flags of the authenticator data
+const (
+	flagUserPresent            byte = 0x01
+	flagUserVerified           byte = 0x04
+	flagBackupEligible         byte = 0x08
+	flagAttestedCredentialData byte = 0x40
+	flagExtensionData          byte = 0x80
+)
+
+// The length of the authenticator data without attested credential data nor extensions
+const authenticatorDataMinLength = 37
+
+// The length of the AAGUID of the attested credential data
+const aaguidLength = 16
+
+// The data signed by the authenticator
+type authenticatorData struct {
+	rpIDHash  []byte
+	flags     byte
+	signCount uint32
+
+	// the attested credential data, only present during a registration
+	aaguid              []byte
+	credentialID        []byte
+	credentialPublicKey []byte
+}
+
+// Parse the authenticator data, the extensions are ignored
+func parseAuthenticatorData(data []byte) (*authenticatorData, error) {
+	if len(data) < authenticatorDataMinLength {
+		return nil, verificationError("authenticator data too short")
+	}
+	authData := &authenticatorData{
+		rpIDHash:  data[0:32],
+		flags:     data[32],
+		signCount: binary.BigEndian.Uint32(data[33:37]),
+	}
+	rest := data[authenticatorDataMinLength:]
+	if authData.flags&flagAttestedCredentialData != 0 {
+		if len(rest) < aaguidLength+2 {
+			return nil, verificationError("attested credential data too short")
+		}
+		authData.aaguid = rest[:aaguidLength]
+		credentialIDLength := int(binary.BigEndian.Uint16(rest[aaguidLength : aaguidLength+2]))
+		rest = rest[aaguidLength+2:]
+		if len(rest) < credentialIDLength {
+			return nil, verificationError("credential id too short")
+		}
+		authData.credentialID = rest[:credentialIDLength]
+		rest = rest[credentialIDLength:]
+
+		var publicKey cbor.RawMessage
+		var err error
+		rest, err = cbor.UnmarshalFirst(rest, &publicKey)
+		if err != nil {
+			return nil, verificationError("malformed credential public key: %s", err)
+		}
+		authData.credentialPublicKey = publicKey
+	}
+	return authData, checkExtensions(authData.flags, rest)
+}
+
+// Check that the remaining bytes of the authenticator data are the announced extensions
+func checkExtensions(flags byte, rest []byte) error {
+	if flags&flagExtensionData == 0 {
+		if len(rest) != 0 {
+			return verificationError("unexpected trailing authenticator data")
+		}
+		return nil
+	}
+	var extensions map[string]any
+	rest, err := cbor.UnmarshalFirst(rest, &extensions)
+	if err != nil {
+		return verificationError("malformed extensions: %s", err)
+	}
+	if len(rest) != 0 {
+		return verificationError("unexpected trailing authenticator data")
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package webauthn
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"math/big"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// The COSE key types and curves of the accepted public keys (RFC 8152)
+const (
+	coseKeyTypeOKP int64 = 1
+	coseKeyTypeEC2 int64 = 2
+	coseKeyTypeRSA int64 = 3
+
+	coseCurveP256    int64 = 1
+	coseCurveEd25519 int64 = 6
+)
+
+// The smallest accepted RSA key
+const rsaMinBits = 2048
+
+// The common parameters of the COSE keys
+type coseKey struct {
+	KeyType   int64 `cbor:"1,keyasint"`
+	Algorithm int64 `cbor:"3,keyasint"`
+}
+
+// The parameters of the elliptic curve and octet key pair COSE keys
+type coseCurveKey struct {
+	Curve int64  `cbor:"-1,keyasint"`
+	X     []byte `cbor:"-2,keyasint"`
+	Y     []byte `cbor:"-3,keyasint"`
+}
+
+// The parameters of the RSA COSE keys
+type coseRSAKey struct {
+	N []byte `cbor:"-1,keyasint"`
+	E []byte `cbor:"-2,keyasint"`
+}
+
+// A public key and the algorithm of its signatures
+type publicKey struct {
+	algorithm int64
+	key       crypto.PublicKey
+}
+
+// Parse a COSE encoded public key, only ES256, EdDSA with Ed25519 and RS256 are accepted
+func parsePublicKey(data []byte) (*publicKey, error) {
+	var key coseKey
+	err := cbor.Unmarshal(data, &key)
+	if err != nil {
+		return nil, verificationError("malformed public key: %s", err)
+	}
+	switch {
+	case key.KeyType == coseKeyTypeEC2 && key.Algorithm == AlgES256:
+		var curveKey coseCurveKey
+		err = cbor.Unmarshal(data, &curveKey)
+		if err != nil {
+			return nil, verificationError("malformed public key: %s", err)
+		}
+		if curveKey.Curve != coseCurveP256 || len(curveKey.X) != 32 || len(curveKey.Y) != 32 {
+			return nil, verificationError("invalid P-256 public key")
+		}
+		x := new(big.Int).SetBytes(curveKey.X)
+		y := new(big.Int).SetBytes(curveKey.Y)
+		if !elliptic.P256().IsOnCurve(x, y) {
+			return nil, verificationError("the public key is not on the P-256 curve")
+		}
+		return &publicKey{algorithm: AlgES256, key: &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}}, nil
+	case key.KeyType == coseKeyTypeOKP && key.Algorithm == AlgEdDSA:
+		var curveKey coseCurveKey
+		err = cbor.Unmarshal(data, &curveKey)
+		if err != nil {
+			return nil, verificationError("malformed public key: %s", err)
+		}
+		if curveKey.Curve != coseCurveEd25519 || len(curveKey.X) != ed25519.PublicKeySize {
+			return nil, verificationError("invalid Ed25519 public key")
+		}
+		return &publicKey{algorithm: AlgEdDSA, key: ed25519.PublicKey(curveKey.X)}, nil
+	case key.KeyType == coseKeyTypeRSA && key.Algorithm == AlgRS256:
+		var rsaKey coseRSAKey
+		err = cbor.Unmarshal(data, &rsaKey)
+		if err != nil {
+			return nil, verificationError("malformed public key: %s", err)
+		}
+		n := new(big.Int).SetBytes(rsaKey.N)
+		e := new(big.Int).SetBytes(rsaKey.E)
+		if n.BitLen() < rsaMinBits || !e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1 {
+			return nil, verificationError("invalid RSA public key")
+		}
+		return &publicKey{algorithm: AlgRS256, key: &rsa.PublicKey{N: n, E: int(e.Int64())}}, nil
+	}
+	return nil, verificationError("unsupported public key type %d with algorithm %d", key.KeyType, key.Algorithm)
+}
+
+// Verify the signature of the data
+func (key *publicKey) verify(data, signature []byte) error {
+	valid := false
+	switch key.algorithm {
+	case AlgES256:
+		hash := sha256.Sum256(data)
+		valid = ecdsa.VerifyASN1(key.key.(*ecdsa.PublicKey), hash[:], signature)
+	case AlgEdDSA:
+		valid = ed25519.Verify(key.key.(ed25519.PublicKey), data, signature)
+	case AlgRS256:
+		hash := sha256.Sum256(data)
+		valid = rsa.VerifyPKCS1v15(key.key.(*rsa.PublicKey), crypto.SHA256, hash[:], signature) == nil
+	}
+	if !valid {
+		return verificationError("invalid signature")
+	}
+	return nil
+}
+
+// Return true if the type of the key can verify the signatures of its algorithm
+func (key *publicKey) matchesAlgorithm() bool {
+	switch key.algorithm {
+	case AlgES256:
+		ecdsaKey, ok := key.key.(*ecdsa.PublicKey)
+		return ok && ecdsaKey.Curve == elliptic.P256()
+	case AlgEdDSA:
+		_, ok := key.key.(ed25519.PublicKey)
+		return ok
+	case AlgRS256:
+		_, ok := key.key.(*rsa.PublicKey)
+		return ok
+	}
+	return false
+}
@@ -0,0 +1,419 @@
+package webauthn_test
+
+import (
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/ditrit/badaas/services/auth/protocols/webauthn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// A response to navigator.credentials.create and the challenge of its options
+type registrationVector struct {
+	challenge         string
+	credentialID      string
+	clientDataJSON    string
+	attestationObject string
+}
+
+// A response to navigator.credentials.get and the challenge of its options
+type assertionVector struct {
+	challenge         string
+	clientDataJSON    string
+	authenticatorData string
+	signature         string
+}
+
+// The ceremonies of a credential, the assertion and the public key are only known for the specification vectors
+type vector struct {
+	name           string
+	relyingPartyID string
+	origin         string
+	registration   registrationVector
+	assertion      *assertionVector
+	publicKey      string
+}
+
+// The ceremonies recorded from authenticators, in hexadecimal
+//
+// The first vectors come from the test vectors of the WebAuthn Level 3 specification (§16),
+// the others were recorded from browsers and authenticators by the go-webauthn project.
+var vectors = []vector{
+	// §16.2 none attestation, ES256
+	{
+		name:           "NoneES256",
+		relyingPartyID: "example.org",
+		origin:         "https://example.org",
+		registration: registrationVector{
+			challenge:    "00c30fb78531c464d2b6771dab8d7b603c01162f2fa486bea70f283ae556e130",
+			credentialID: "f91f391db4c9b2fde0ea70189cba3fb63f579ba6122b33ad94ff3ec330084be4",
+			clientDataJSON: "7b2274797065223a22776562617574686e2e637265617465222c226368616c6c656e6765223a22414d4d507434557878" +
+				"475453746e63647134313759447742466938767049612d7077386f4f755657345441222c226f726967696e223a226874" +
+				"7470733a2f2f6578616d706c652e6f7267222c2263726f73734f726967696e223a66616c73652c226578747261446174" +
+				"61223a22636c69656e74446174614a534f4e206d617920626520657874656e6465642077697468206164646974696f6e" +
+				"616c206669656c647320696e20746865206675747572652c207375636820617320746869733a20426b5165446a646354" +
+				"427258426941774a544c453551227d",
+			attestationObject: "a363666d74646e6f6e656761747453746d74a068617574684461746158a4bfabc37432958b063360d3ad6461c9c4735a" +
+				"e7f8edd46592a5e0f01452b2e4b559000000008446ccb9ab1db374750b2367ff6f3a1f0020f91f391db4c9b2fde0ea70" +
+				"189cba3fb63f579ba6122b33ad94ff3ec330084be4a5010203262001215820afefa16f97ca9b2d23eb86ccb64098d20d" +
+				"b90856062eb249c33a9b672f26df61225820930a56b87a2fca66334b03458abf879717c12cc68ed73290af2e2664796b" +
+				"9220",
+		},
+		assertion: &assertionVector{
+			challenge: "39c0e7521417ba54d43e8dc95174f423dee9bf3cd804ff6d65c857c9abf4d408",
+			clientDataJSON: "7b2274797065223a22776562617574686e2e676574222c226368616c6c656e6765223a224f63446e55685158756c5455" +
+				"506f334a5558543049393770767a7a59425039745a63685879617630314167222c226f726967696e223a226874747073" +
+				"3a2f2f6578616d706c652e6f7267222c2263726f73734f726967696e223a66616c73657d",
+			authenticatorData: "bfabc37432958b063360d3ad6461c9c4735ae7f8edd46592a5e0f01452b2e4b51900000000",
+			signature: "3046022100f50a4e2e4409249c4a853ba361282f09841df4dd4547a13a87780218deffcd380221008480ac0f0b935381" +
+				"74f575bf11a1dd5d78c6e486013f937295ea13653e331e87",
+		},
+		publicKey: "a5010203262001215820afefa16f97ca9b2d23eb86ccb64098d20db90856062eb249c33a9b672f26df61225820930a56" +
+			"b87a2fca66334b03458abf879717c12cc68ed73290af2e2664796b9220",
+	},
+	// §16.3 packed self attestation, ES256
+	{
+		name:           "PackedSelfES256",
+		relyingPartyID: "example.org",
+		origin:         "https://example.org",
+		registration: registrationVector{
+			challenge:    "7869c2b772d4b58eba9378cf8f29e26cf935aa77df0da89fa99c0bdc0a76f7e5",
+			credentialID: "455ef34e2043a87db3d4afeb39bbcb6cc32df9347c789a865ecdca129cbef58c",
+			clientDataJSON: "7b2274797065223a22776562617574686e2e637265617465222c226368616c6c656e6765223a2265476e4374334c5574" +
+				"5936366b336a506a796e6962506b31716e666644616966715a774c33417032392d55222c226f726967696e223a226874" +
+				"7470733a2f2f6578616d706c652e6f7267222c2263726f73734f726967696e223a66616c73652c226578747261446174" +
+				"61223a22636c69656e74446174614a534f4e206d617920626520657874656e6465642077697468206164646974696f6e" +
+				"616c206669656c647320696e20746865206675747572652c207375636820617320746869733a205539685458764b4532" +
+				"55526b4d6e625f307859485667227d",
+			attestationObject: "a363666d74667061636b65646761747453746d74a263616c672663736967584630440220067a20754ab925005dbf3780" +
+				"97c92120031581c73228d1fb4f5b881bcd7da98302207fc7b147558c7c0eba3af18bd9d121fa3d3a26d17fe3f2202721" +
+				"78f473b6006d68617574684461746158a4bfabc37432958b063360d3ad6461c9c4735ae7f8edd46592a5e0f01452b2e4" +
+				"b55d00000000df850e09db6afbdfab51697791506cfc0020455ef34e2043a87db3d4afeb39bbcb6cc32df9347c789a86" +
+				"5ecdca129cbef58ca5010203262001215820eb151c8176b225cc651559fecf07af450fd85802046656b34c18f6cf1938" +
+				"43c5225820927b8aa427a2be1b8834d233a2d34f61f13bfd44119c325d5896e183fee484f2",
+		},
+		assertion: &assertionVector{
+			challenge: "4478a10b1352348dd160c1353b0d469b5db19eb91c27f7dfa6fed39fe26af20b",
+			clientDataJSON: "7b2274797065223a22776562617574686e2e676574222c226368616c6c656e6765223a225248696843784e534e493352" +
+				"594d45314f7731476d3132786e726b634a5f6666707637546e2d4a71386773222c226f726967696e223a226874747073" +
+				"3a2f2f6578616d706c652e6f7267222c2263726f73734f726967696e223a66616c73652c22657874726144617461223a" +
+				"22636c69656e74446174614a534f4e206d617920626520657874656e6465642077697468206164646974696f6e616c20" +
+				"6669656c647320696e20746865206675747572652c207375636820617320746869733a206754623533727a3645685357" +
+				"6f6d58477a696d433151227d",
+			authenticatorData: "bfabc37432958b063360d3ad6461c9c4735ae7f8edd46592a5e0f01452b2e4b50900000000",
+			signature: "304402203310b9431903c401f1be2bdc8d23a4007682dbbddcf846994947b7f465daf84002204e94dd00047b316061b3" +
+				"b99772b7efd95994a83ef584b3b6b825ea3550251b66",
+		},
+		publicKey: "a5010203262001215820eb151c8176b225cc651559fecf07af450fd85802046656b34c18f6cf193843c5225820927b8a" +
+			"a427a2be1b8834d233a2d34f61f13bfd44119c325d5896e183fee484f2",
+	},
+	// §16.10 packed attestation with a certificate, RS256 credential
+	{
+		name:           "PackedRS256",
+		relyingPartyID: "example.org",
+		origin:         "https://example.org",
+		registration: registrationVector{
+			challenge:    "bea8f0770009bd57f2c0df6fea9f743a27e4b61bbe923c862c7aad7a9fc8e4a6",
+			credentialID: "992a18acc83f67533600c1138a4b4c4bd236de13629cf025ed17cb00b00b74df",
+			clientDataJSON: "7b2274797065223a22776562617574686e2e637265617465222c226368616c6c656e6765223a2276716a776477414a76" +
+				"566679774e3976367039304f69666b7468752d6b6a79474c48717465705f49354b59222c226f726967696e223a226874" +
+				"7470733a2f2f6578616d706c652e6f7267222c2263726f73734f726967696e223a66616c73657d",
+			attestationObject: "a363666d74667061636b65646761747453746d74a363616c672663736967584730450221008b8c5c6ea8c142c032e0be" +
+				"69e1353d44461c5c9109941cdda951b976eb95b6b302204d52f406c19e254b3ff9589bd18070fb055ac8db12fdd0a673" +
+				"4bea9d7168e900637835638159022630820222308201c7a00302010202101f6fb7a5ece81b45896b983a995da5f3300a" +
+				"06082a8648ce3d0403023062311e301c06035504030c15576562417574686e207465737420766563746f7273310c300a" +
+				"060355040a0c0357334331253023060355040b0c1c41757468656e74696361746f72204174746573746174696f6e2043" +
+				"41310b30090603550406130241413020170d3234303130313030303030305a180f33303234303130313030303030305a" +
+				"305f311e301c06035504030c15576562417574686e207465737420766563746f7273310c300a060355040a0c03573343" +
+				"31223020060355040b0c1941757468656e74696361746f72204174746573746174696f6e310b30090603550406130241" +
+				"413059301306072a8648ce3d020106082a8648ce3d03010703420004b7b36b7542a11120b443c794d0c99fdc25a06b76" +
+				"586413d81e086163ef6fe147a557afc34e2861d9057d6d465d4705a0310550bdeeb5f35ee35b9425ab859981a360305e" +
+				"300c0603551d130101ff04023000300e0603551d0f0101ff040403020780301d0603551d0e04160414fb37b647bccfb9" +
+				"e54d989eaaacc1633868703fb3301f0603551d2304183016801445aff715b0dd786741fee996ebc16547a3931b1e300a" +
+				"06082a8648ce3d0403020349003046022100b86bc129d92afca7d9869a39f70f139a305b4073a39eb654d81424bed575" +
+				"7d91022100cf9f7c60cab7c4a7d3e7f0020f281a93d4fd0a9f95121b989f56932a68885fba6861757468446174615902" +
+				"1bbfabc37432958b063360d3ad6461c9c4735ae7f8edd46592a5e0f01452b2e4b55d00000000428f8878298b9862a36a" +
+				"d8c7527bfef20020992a18acc83f67533600c1138a4b4c4bd236de13629cf025ed17cb00b00b74dfa401030339010020" +
+				"5901b403ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff" +
+				"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff" +
+				"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff" +
+				"fffffffffffffffffffffffffffffffffffffff7ffffffffffffffffffffffffffffffffffffffffffffffffffffffff" +
+				"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff" +
+				"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff800000000000000000" +
+				"000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000" +
+				"000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000" +
+				"000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000" +
+				"000000000000012143010001",
+		},
+		assertion: &assertionVector{
+			challenge: "295f59f5fa8fe62c5aca9e27626c78c8da376ae6d8cd2dd29aebad601e1bc4c5",
+			clientDataJSON: "7b2274797065223a22776562617574686e2e676574222c226368616c6c656e6765223a224b56395a3966715035697861" +
+				"7970346e596d7834794e6f33617562597a5333536d75757459423462784d55222c226f726967696e223a226874747073" +
+				"3a2f2f6578616d706c652e6f7267222c2263726f73734f726967696e223a66616c73657d",
+			authenticatorData: "bfabc37432958b063360d3ad6461c9c4735ae7f8edd46592a5e0f01452b2e4b51900000000",
+			signature: "01063d52d7c39b4d432fc7063c5d93e582bdcb16889cd71f888d67d880ea730a428498d3bc8e1ee11f2b1ecbe6c292b1" +
+				"18c55ffaaddefa8cad0a54dd137c51f1eec673f1bb6c4d1789d6826a222b22d0f585fc901fdc933212e579d199b89d67" +
+				"2aa44891333e6a1355536025e82b25590256c3538229b55737083b2f6b9377e49e2472f11952f79fdd0da180b5ffd901" +
+				"b4049a8f081bb40711bef76c62aed943571f2d0575304cb549d68d8892f95086a30f93716aee818f8dc06e96c0d5e0ed" +
+				"4cfa9fd8773d90464b68cf140f7986666ff9c9e3302acd0535d60d769f465e2ab57ef8aabc89fccfef7ba32a64154a8b" +
+				"3d26be2298f470b8cc5377dbe3dfd4b0b45f8f01e63bde6cfc76b62771f9b70aa27cf40152cad93aa5acd784fd4b90f6" +
+				"76e2ea828d0bf2400aebbaae4153e5838f537f88b6228346782a93a899be66ec77de45b3efcf311da6321c92e6b0cd11" +
+				"bfe653bf3e98cee8e341f02d67dbb6f9c98d9e8178090cfb5b70fbc6d541599ac794ae2f1d4de1286ec8de8c2daf7b1d" +
+				"15c8438e90d924df5c19045220a4c8438c1b979bbe016cf3d0eeec23c3999d4882cc645b776de930756612cdc6dd3981" +
+				"60ff02a6",
+		},
+		publicKey: "a4010303390100205901b403ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff" +
+			"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff" +
+			"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff" +
+			"fffffffffffffffffffffffffffffffffffffffffffffffffffffff7ffffffffffffffffffffffffffffffffffffffff" +
+			"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff" +
+			"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff80" +
+			"000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000" +
+			"000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000" +
+			"000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000" +
+			"0000000000000000000000000000012143010001",
+	},
+	// §16.11 packed attestation with a certificate, EdDSA credential
+	{
+		name:           "PackedEdDSA",
+		relyingPartyID: "example.org",
+		origin:         "https://example.org",
+		registration: registrationVector{
+			challenge:    "a8abf9dabdc6b0df63466b39bda9e8a34a34e185337a59f1c579990676d3b3bd",
+			credentialID: "ce9f840ed96599580cd140fbc7bb3230633f50f61041aff73308ae71caa8a2bd",
+			clientDataJSON: "7b2274797065223a22776562617574686e2e637265617465222c226368616c6c656e6765223a22714b76353272334773" +
+				"4e396a526d733576616e6f6f306f303459557a656c6e7878586d5a426e6254733730222c226f726967696e223a226874" +
+				"7470733a2f2f6578616d706c652e6f7267222c2263726f73734f726967696e223a66616c73652c226578747261446174" +
+				"61223a22636c69656e74446174614a534f4e206d617920626520657874656e6465642077697468206164646974696f6e" +
+				"616c206669656c647320696e20746865206675747572652c207375636820617320746869733a20425f44543567375a44" +
+				"5f2d394f544c59583549764551227d",
+			attestationObject: "a363666d74667061636b65646761747453746d74a363616c67266373696758483046022100d83f60bd80269537583218" +
+				"858aefb03ac57d45fa06e42feaae332d187f62da9f022100a02bd3cb6f7e1d283c93bad1f3f4b5a4c0494463da7fdbf2" +
+				"56949116754d1f17637835638159022730820223308201c8a003020102021100b2cfc9ea33c8643b0e1a760463eaf164" +
+				"300a06082a8648ce3d0403023062311e301c06035504030c15576562417574686e207465737420766563746f7273310c" +
+				"300a060355040a0c0357334331253023060355040b0c1c41757468656e74696361746f72204174746573746174696f6e" +
+				"204341310b30090603550406130241413020170d3234303130313030303030305a180f33303234303130313030303030" +
+				"305a305f311e301c06035504030c15576562417574686e207465737420766563746f7273310c300a060355040a0c0357" +
+				"334331223020060355040b0c1941757468656e74696361746f72204174746573746174696f6e310b3009060355040613" +
+				"0241413059301306072a8648ce3d020106082a8648ce3d03010703420004dd2b7a564b73b8c0b81c4c62e521925c4d11" +
+				"98ec9f583dbf1eebe364b65cd9c29a9bdf346aaa81fb6b9507e5249a52fdaf8e39e26b0b7dc45992a7e233b70f70a360" +
+				"305e300c0603551d130101ff04023000300e0603551d0f0101ff040403020780301d0603551d0e041604140ae27546bc" +
+				"7eccb1b4b597bd354f0c0b1f1f8f8e301f0603551d2304183016801445aff715b0dd786741fee996ebc16547a3931b1e" +
+				"300a06082a8648ce3d0403020349003046022100a0d434ecb5fc3bfd7da5f41904517ad2836249f561bd834ba7a438a8" +
+				"ab7a4ce8022100fac845bb7a02513b58e9f319654dbe49b0f02b95835bac568c71f8a18cdde9ab686175746844617461" +
+				"5881bfabc37432958b063360d3ad6461c9c4735ae7f8edd46592a5e0f01452b2e4b54100000000d5aa33581e8ca478e2" +
+				"0fe713f5d32ff20020ce9f840ed96599580cd140fbc7bb3230633f50f61041aff73308ae71caa8a2bda4010103272006" +
+				"21582044e06ddd331c36a8dc667bab52bcae63486c916aa5e339e6acebaa84934bf832",
+		},
+		assertion: &assertionVector{
+			challenge: "895957e01c633a698348a2d8a31a54b7db27e8c1c43b2080d79ae2190267bfd2",
+			clientDataJSON: "7b2274797065223a22776562617574686e2e676574222c226368616c6c656e6765223a2269566c583442786a4f6d6d44" +
+				"534b4c596f7870557439736e364d48454f7943413135726947514a6e763949222c226f726967696e223a226874747073" +
+				"3a2f2f6578616d706c652e6f7267222c2263726f73734f726967696e223a66616c73657d",
+			authenticatorData: "bfabc37432958b063360d3ad6461c9c4735ae7f8edd46592a5e0f01452b2e4b50100000000",
+			signature: "f5c59c7e46c34f6f8cc197101ddf9934fa2595f68eb1913a637e8419eb9ba4cfdfc48f85393bc0d40b011f0d6fecb097" +
+				"d6607525713223a0dc0d453993dae00b",
+		},
+		publicKey: "a401010327200621582044e06ddd331c36a8dc667bab52bcae63486c916aa5e339e6acebaa84934bf832",
+	},
+	// Touch ID on macOS, packed self attestation, ES256
+	{
+		name:           "PackedSelfES256MacOS",
+		relyingPartyID: "localhost",
+		origin:         "http://localhost:9005",
+		registration: registrationVector{
+			challenge: "ad689ec7cc4338f7e20a0c85bb804b5babd53a65ca80fc07ae5302804b3d4810",
+			credentialID: "00ec7abc5186213b65c2386a145bc0909981cd2cdfc04d5d05ad5f551fcbb6ae4bdf914944d7609177bce2fdfed13115" +
+				"3424a9",
+			clientDataJSON: "7b226368616c6c656e6765223a2272576965783878444f506669436779467534424c573676564f6d584b67507748726c" +
+				"4d4367457339534241222c226f726967696e223a22687474703a2f2f6c6f63616c686f73743a39303035222c22747970" +
+				"65223a22776562617574686e2e637265617465227d",
+			attestationObject: "a363666d74667061636b65646761747453746d74a263616c67266373696758473045022100981d830e71f09cc4e097d1" +
+				"eb1d5104ef1e087344f1e5bf2f5553574df3a722f902206ff1e533b9add7492ddb3d80bb9bc797cc985c691c9e19eed4" +
+				"72ea88ac34af0b68617574684461746158b749960de5880e8c687434170f6476605b8fe4aeb9a28632c7995cf3ba831d" +
+				"9763455c9139ccadce000235bcc60a648b0b25f1f05503003300ec7abc5186213b65c2386a145bc0909981cd2cdfc04d" +
+				"5d05ad5f551fcbb6ae4bdf914944d7609177bce2fdfed131153424a9a5010203262001215820a6f4e622770888a07f52" +
+				"c1542a5009c7f068f9649dbfaabf208db838d6ae0cd52258200d3b5caa474a04b5984ed1d4c8e8b55210ab21e2f56031" +
+				"1b01d0b02b5e54ad95",
+		},
+	},
+	// a credential created on webauthn.io, none attestation, ES256
+	{
+		name:           "NoneES256WebAuthnIO",
+		relyingPartyID: "webauthn.io",
+		origin:         "https://webauthn.io",
+		registration: registrationVector{
+			challenge: "b15b7849c71e333a854a77c0abc8602f36e5be8ddf6b8fda15511c204487209d",
+			credentialID: "e89af2ef733f595583a172ec1b146c055547a4f583a4dcb5113197504bc92dd4c09f9130ea70c653a5bc88edd991c2c4" +
+				"aabf82070bf1d29d96031cedf118b041",
+			clientDataJSON: "7b226368616c6c656e6765223a2273567434536363654d7a7146536e6641713868674c7a626c766f336661345f614656" +
+				"456349455348494a30222c226f726967696e223a2268747470733a2f2f776562617574686e2e696f222c227479706522" +
+				"3a22776562617574686e2e637265617465227d",
+			attestationObject: "a363666d74646e6f6e656761747453746d74a068617574684461746158c474a6ea9213c99c2f74b22492b320cf40262a" +
+				"94c1a950a0397f29250b60841ef04100000000000000000000000000000000000000000040e89af2ef733f595583a172" +
+				"ec1b146c055547a4f583a4dcb5113197504bc92dd4c09f9130ea70c653a5bc88edd991c2c4aabf82070bf1d29d96031c" +
+				"edf118b041a5010203262001215820fbe9ff42f662b610f27189a789f93abcc1e2c013fa92e827d8f96c9ef92b712822" +
+				"582040941626d81fab32d425a793311932e81831e892dc5bd29dfae07cb6047f54d9",
+		},
+	},
+	// a Solo 2 security key, packed attestation with a certificate, EdDSA credential
+	{
+		name:           "PackedEdDSASolo2",
+		relyingPartyID: "webauthn.firstyear.id.au",
+		origin:         "https://webauthn.firstyear.id.au",
+		registration: registrationVector{
+			challenge: "0966f1093d06e0bdb2391a412fa4965628307b6910117061a392fac04e3bf84b",
+			credentialID: "a30058e85e7ce7bb5d6bd3e0e6214d0a0ea4b07a4639886b36a227e3aa64be110c28880d19c292faf0c601412ba2ad3e" +
+				"5479e5d4b8734243d14266014c78c67292ca64a48b6b69b600d23ef751e35f3a0977fcc53a2127bbb083c104df7fd299" +
+				"eae3e5c7b9142be2493876abf88c9129c36173f90e2767b38259a3d4962e2092e80c4c9794a924be215dc24d6f6d62e7" +
+				"3b7a7fa1644e51e2201ff4b808b54b3c825790f7b462f320a77112b3d0acacdea4bcc4f2b15448b7f879294aa9668d13" +
+				"2822fdeb3c059355ffd8fc022ca5a63b154bdf99497d428e1c6f6b7376e62a5a9947a68e8198dead8e81a54f014c9900" +
+				"a9f3af75596a15e11ccb025082f791ec53af9d5b68ab3779a1a1308c",
+			clientDataJSON: "7b2274797065223a22776562617574686e2e637265617465222c226368616c6c656e6765223a22435762784354304734" +
+				"4c32794f5270424c3653575669677765326b51455842686f354c36774534372d4573222c226f726967696e223a226874" +
+				"7470733a2f2f776562617574686e2e6669727374796561722e69642e6175222c2263726f73734f726967696e223a6661" +
+				"6c73657d",
+			attestationObject: "a363666d74667061636b65646761747453746d74a363616c6726637369675848304602210085d132a982dbf6c74e4294" +
+				"c0ebcbbe602292e40f0a4ffff5c9482f08e13b3dd5022100a495d3acde163223f0157a93221d2823c019066defb3ecbf" +
+				"528b5b4054a75fdf63783563815902ab308202a73082024ca00302010202146aa3e96dc45614564093ee9f4aaa08cc93" +
+				"967a8a300a06082a8648ce3d040302302d3111300f060355040a0c08536f6c6f4b657973310b30090603550406130243" +
+				"48310b300906035504030c0246313020170d3231303532333030353230365a180f32303731303531313030353230365a" +
+				"308183310b30090603550406130255533111300f060355040a0c08536f6c6f4b65797331223020060355040b0c194175" +
+				"7468656e74696361746f72204174746573746174696f6e313d303b06035504030c34536f6c6f2032204e46432b555342" +
+				"2d432032333639443444303133434534384342394632364637454438433941363036382042323059301306072a8648ce" +
+				"3d020106082a8648ce3d03010703420004ba3795767d3f9a821df86d1896fbe5a5e8240f4ac9cbaaa484203d2de5144a" +
+				"8e397534323ffe6ceec98b8ead6c9be72cc66511e09c7abae5c9b644ea71a3a00ea381f03081ed301d0603551d0e0416" +
+				"04143a0840c2e56f3fcc030e855815f301c923c9f76f301f0603551d23041830168014416bb64befa2190de4625ffd29" +
+				"0496b98229b4f830090603551d1304023000300b0603551d0f0404030204f0303206082b060105050701010426302430" +
+				"2206082b060105050730028616687474703a2f2f692e7332706b692e6e65742f66312f30270603551d1f0420301e301c" +
+				"a01aa0188616687474703a2f2f632e7332706b692e6e65742f72312f3021060b2b0601040182e51c0101040412041023" +
+				"69d4d013ce48cb9f26f7ed8c9a60683013060b2b0601040182e51c020101040403020430300a06082a8648ce3d040302" +
+				"03490030460221008ff3646896bd14d85bce26ae7701961c03ac5f0b8f9c343733bdfd05b54d52400221008bdbdb5677" +
+				"0fc442bf0936f91ca7e30a6c1a79f6be66ba070dc4d5fe3c87257068617574684461746159016d6ab9bbf0df9a16f91d" +
+				"bb33bbb132faf9d17c782c4826c6ec70ecee58d97ef52a41000000272369d4d013ce48cb9f26f7ed8c9a6068010ca300" +
+				"58e85e7ce7bb5d6bd3e0e6214d0a0ea4b07a4639886b36a227e3aa64be110c28880d19c292faf0c601412ba2ad3e5479" +
+				"e5d4b8734243d14266014c78c67292ca64a48b6b69b600d23ef751e35f3a0977fcc53a2127bbb083c104df7fd299eae3" +
+				"e5c7b9142be2493876abf88c9129c36173f90e2767b38259a3d4962e2092e80c4c9794a924be215dc24d6f6d62e73b7a" +
+				"7fa1644e51e2201ff4b808b54b3c825790f7b462f320a77112b3d0acacdea4bcc4f2b15448b7f879294aa9668d132822" +
+				"fdeb3c059355ffd8fc022ca5a63b154bdf99497d428e1c6f6b7376e62a5a9947a68e8198dead8e81a54f014c9900a9f3" +
+				"af75596a15e11ccb025082f791ec53af9d5b68ab3779a1a1308ca40101032720062158208f3f54909edc2a8a04ddb952" +
+				"bb396ac6474ba6932e165dc2222493f287564ba9",
+		},
+	},
+}
+
+// The §16.4 none attestation of a ceremony run in a cross origin iframe
+var crossOriginRegistration = registrationVector{
+	challenge:    "3be5aacd03537142472340ab5969f240f1d87716e20b6807ac230655fa4b3b49",
+	credentialID: "6e1050c0d2ca2f07c755cb2c66a74c64fa43065c18f938354d9915db2bd5ce57",
+	clientDataJSON: "7b2274797065223a22776562617574686e2e637265617465222c226368616c6c656e6765223a224f2d57717a514e5463" +
+		"554a484930437257576e7951504859647862694332674872434d475666704c4f306b222c226f726967696e223a226874" +
+		"7470733a2f2f6578616d706c652e6f7267222c2263726f73734f726967696e223a747275652c22657874726144617461" +
+		"223a22636c69656e74446174614a534f4e206d617920626520657874656e6465642077697468206164646974696f6e61" +
+		"6c206669656c647320696e20746865206675747572652c207375636820617320746869733a207a5a7175457444523944" +
+		"577170573574425754467567227d",
+	attestationObject: "a363666d74646e6f6e656761747453746d74a068617574684461746158a4bfabc37432958b063360d3ad6461c9c4735a" +
+		"e7f8edd46592a5e0f01452b2e4b54500000000883f4f6014f19c09d87aa38123be48d000206e1050c0d2ca2f07c755cb" +
+		"2c66a74c64fa43065c18f938354d9915db2bd5ce57a501020326200121582022200a473f90b11078851550d03b4e44a2" +
+		"279f8c4eca27b3153dedfe03e4e97d225820cbd0be95e746ad6f5a8191be11756e4c0420e72f65b466d39bc56b8b123a" +
+		"9c6e",
+}
+
+// Decode an hexadecimal string of the vectors
+func decodeHex(t *testing.T, data string) []byte {
+	decoded, err := hex.DecodeString(data)
+	require.NoError(t, err)
+	return decoded
+}
+
+// Return the relying party the credential of the vector was created for
+func vectorRelyingParty(relyingPartyID, origin string) *webauthn.RelyingParty {
+	return &webauthn.RelyingParty{
+		ID:      relyingPartyID,
+		Name:    relyingPartyID,
+		Origins: []string{origin},
+		Timeout: 5 * time.Minute,
+	}
+}
+
+// Return the response of the registration vector
+func registrationResponse(t *testing.T, registration registrationVector) *webauthn.CredentialCreationResponse {
+	credentialID := decodeHex(t, registration.credentialID)
+	return &webauthn.CredentialCreationResponse{
+		ID:    hex.EncodeToString(credentialID),
+		RawID: credentialID,
+		Type:  webauthn.PublicKeyCredentialType,
+		Response: webauthn.AttestationResponse{
+			ClientDataJSON:    decodeHex(t, registration.clientDataJSON),
+			AttestationObject: decodeHex(t, registration.attestationObject),
+		},
+	}
+}
+
+// Return the response of the assertion vector
+func assertionResponse(t *testing.T, credentialID []byte, assertion *assertionVector) *webauthn.CredentialAssertionResponse {
+	return &webauthn.CredentialAssertionResponse{
+		ID:    hex.EncodeToString(credentialID),
+		RawID: credentialID,
+		Type:  webauthn.PublicKeyCredentialType,
+		Response: webauthn.AssertionResponse{
+			ClientDataJSON:    decodeHex(t, assertion.clientDataJSON),
+			AuthenticatorData: decodeHex(t, assertion.authenticatorData),
+			Signature:         decodeHex(t, assertion.signature),
+		},
+	}
+}
+
+func TestVectors(t *testing.T) {
+	for _, vector := range vectors {
+		vector := vector
+		t.Run(vector.name, func(t *testing.T) {
+			relyingParty := vectorRelyingParty(vector.relyingPartyID, vector.origin)
+
+			credential, err := relyingParty.VerifyRegistration(decodeHex(t, vector.registration.challenge),
+				registrationResponse(t, vector.registration), webauthn.UserVerificationPreferred)
+			require.NoError(t, err)
+			assert.Equal(t, decodeHex(t, vector.registration.credentialID), credential.ID)
+			if vector.assertion == nil {
+				return
+			}
+			assert.Equal(t, decodeHex(t, vector.publicKey), credential.PublicKey)
+
+			assertion, err := relyingParty.VerifyAssertion(decodeHex(t, vector.assertion.challenge),
+				credential.PublicKey, credential.SignCount,
+				assertionResponse(t, credential.ID, vector.assertion), webauthn.UserVerificationPreferred)
+			require.NoError(t, err)
+			// the authenticators of the specification have no counter
+			assert.Equal(t, uint32(0), assertion.SignCount)
+		})
+	}
+}
+
+func TestVectorsTamperedSignature(t *testing.T) {
+	for _, vector := range vectors {
+		if vector.assertion == nil {
+			continue
+		}
+		vector := vector
+		t.Run(vector.name, func(t *testing.T) {
+			credentialID := decodeHex(t, vector.registration.credentialID)
+			response := assertionResponse(t, credentialID, vector.assertion)
+			response.Response.Signature[len(response.Response.Signature)-1] ^= 0xff
+
+			_, err := vectorRelyingParty(vector.relyingPartyID, vector.origin).VerifyAssertion(
+				decodeHex(t, vector.assertion.challenge), decodeHex(t, vector.publicKey), 0,
+				response, webauthn.UserVerificationPreferred)
+			assert.ErrorIs(t, err, webauthn.ErrVerification)
+		})
+	}
+}
+
+func TestVectorCrossOriginRefused(t *testing.T) {
+	_, err := vectorRelyingParty("example.org", "https://example.org").VerifyRegistration(
+		decodeHex(t, crossOriginRegistration.challenge),
+		registrationResponse(t, crossOriginRegistration), webauthn.UserVerificationPreferred)
+	assert.ErrorIs(t, err, webauthn.ErrVerification)
+	assert.ErrorContains(t, err, "cross origin")
+}
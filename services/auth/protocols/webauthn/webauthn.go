@@ -0,0 +1,417 @@
+package webauthn
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Returned when a response of an authenticator can't be verified
+var ErrVerification = errors.New("webauthn verification failed")
+
+// Returned when the signature counter of a credential went backwards, the authenticator may have been cloned
+var ErrSignCount = errors.New("webauthn signature counter went backwards")
+
+// The type of the public key credentials, the only one defined by WebAuthn
+const PublicKeyCredentialType = "public-key"
+
+// The number of random bytes of a challenge
+const challengeSize = 32
+
+// The values of the userVerification option
+type UserVerification string
+
+const (
+	UserVerificationRequired    UserVerification = "required"
+	UserVerificationPreferred   UserVerification = "preferred"
+	UserVerificationDiscouraged UserVerification = "discouraged"
+)
+
+// The COSE algorithms of the accepted public keys
+const (
+	AlgES256 int64 = -7
+	AlgEdDSA int64 = -8
+	AlgRS256 int64 = -257
+)
+
+// Binary data, encoded in base64url without padding in JSON like the browsers helpers do
+type URLEncodedBase64 []byte
+
+// Encode the data in base64url
+func (data URLEncodedBase64) MarshalJSON() ([]byte, error) {
+	return json.Marshal(base64.RawURLEncoding.EncodeToString(data))
+}
+
+// Decode base64url data, with or without padding
+func (data *URLEncodedBase64) UnmarshalJSON(b []byte) error {
+	var encoded string
+	err := json.Unmarshal(b, &encoded)
+	if err != nil {
+		return err
+	}
+	decoded, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(encoded, "="))
+	if err != nil {
+		return err
+	}
+	*data = decoded
+	return nil
+}
+
+// The relying party of the options
+type RelyingPartyEntity struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// The user of a new credential, the id is stored by the authenticator and returned as the user handle
+type UserEntity struct {
+	ID          URLEncodedBase64 `json:"id"`
+	Name        string           `json:"name"`
+	DisplayName string           `json:"displayName"`
+}
+
+// An algorithm accepted for a new credential
+type CredentialParameter struct {
+	Type      string `json:"type"`
+	Algorithm int64  `json:"alg"`
+}
+
+// Identify an existing credential
+type CredentialDescriptor struct {
+	Type       string           `json:"type"`
+	ID         URLEncodedBase64 `json:"id"`
+	Transports []string         `json:"transports,omitempty"`
+}
+
+// The requirements on the authenticator of a new credential
+type AuthenticatorSelection struct {
+	ResidentKey        string           `json:"residentKey"`
+	RequireResidentKey bool             `json:"requireResidentKey"`
+	UserVerification   UserVerification `json:"userVerification"`
+}
+
+// The options of navigator.credentials.create, sent as the publicKey member
+type CreationOptions struct {
+	Challenge              URLEncodedBase64       `json:"challenge"`
+	RelyingParty           RelyingPartyEntity     `json:"rp"`
+	User                   UserEntity             `json:"user"`
+	Parameters             []CredentialParameter  `json:"pubKeyCredParams"`
+	Timeout                int64                  `json:"timeout"`
+	ExcludeCredentials     []CredentialDescriptor `json:"excludeCredentials"`
+	AuthenticatorSelection AuthenticatorSelection `json:"authenticatorSelection"`
+	Attestation            string                 `json:"attestation"`
+}
+
+// The options of navigator.credentials.get, sent as the publicKey member
+type RequestOptions struct {
+	Challenge        URLEncodedBase64       `json:"challenge"`
+	Timeout          int64                  `json:"timeout"`
+	RelyingPartyID   string                 `json:"rpId"`
+	AllowCredentials []CredentialDescriptor `json:"allowCredentials"`
+	UserVerification UserVerification       `json:"userVerification"`
+}
+
+// The response of the authenticator to navigator.credentials.create
+type AttestationResponse struct {
+	ClientDataJSON    URLEncodedBase64 `json:"clientDataJSON"`
+	AttestationObject URLEncodedBase64 `json:"attestationObject"`
+	Transports        []string         `json:"transports"`
+}
+
+// The PublicKeyCredential returned by navigator.credentials.create
+type CredentialCreationResponse struct {
+	ID       string              `json:"id"`
+	RawID    URLEncodedBase64    `json:"rawId"`
+	Type     string              `json:"type"`
+	Response AttestationResponse `json:"response"`
+}
+
+// The response of the authenticator to navigator.credentials.get
+type AssertionResponse struct {
+	ClientDataJSON    URLEncodedBase64 `json:"clientDataJSON"`
+	AuthenticatorData URLEncodedBase64 `json:"authenticatorData"`
+	Signature         URLEncodedBase64 `json:"signature"`
+	UserHandle        URLEncodedBase64 `json:"userHandle"`
+}
+
+// The PublicKeyCredential returned by navigator.credentials.get
+type CredentialAssertionResponse struct {
+	ID       string            `json:"id"`
+	RawID    URLEncodedBase64  `json:"rawId"`
+	Type     string            `json:"type"`
+	Response AssertionResponse `json:"response"`
+}
+
+// A credential whose registration has been verified
+type Credential struct {
+	ID []byte
+	// The COSE encoded public key
+	PublicKey      []byte
+	SignCount      uint32
+	AAGUID         []byte
+	Transports     []string
+	UserVerified   bool
+	BackupEligible bool
+}
+
+// An assertion whose signature has been verified
+type Assertion struct {
+	SignCount    uint32
+	UserVerified bool
+	UserHandle   []byte
+}
+
+// The relying party, it creates the options of the ceremonies and verifies the responses of the authenticators
+type RelyingParty struct {
+	// The domain of the relying party, the credentials are bound to it
+	ID   string
+	Name string
+	// The origins of the pages allowed to run the ceremonies
+	Origins []string
+	Timeout time.Duration
+}
+
+// Return the options of the registration of a new credential
+//
+// The existing credentials of the user are excluded, so that an authenticator is not registered twice.
+func (relyingParty *RelyingParty) NewCreationOptions(
+	user UserEntity,
+	excludeCredentials []CredentialDescriptor,
+	userVerification UserVerification,
+) (*CreationOptions, error) {
+	challenge, err := NewChallenge()
+	if err != nil {
+		return nil, err
+	}
+	return &CreationOptions{
+		Challenge:    challenge,
+		RelyingParty: RelyingPartyEntity{ID: relyingParty.ID, Name: relyingParty.Name},
+		User:         user,
+		Parameters: []CredentialParameter{
+			{Type: PublicKeyCredentialType, Algorithm: AlgES256},
+			{Type: PublicKeyCredentialType, Algorithm: AlgEdDSA},
+			{Type: PublicKeyCredentialType, Algorithm: AlgRS256},
+		},
+		Timeout:            relyingParty.Timeout.Milliseconds(),
+		ExcludeCredentials: excludeCredentials,
+		AuthenticatorSelection: AuthenticatorSelection{
+			// a discoverable credential can be used as a passkey, without giving the email first
+			ResidentKey:      "preferred",
+			UserVerification: userVerification,
+		},
+		Attestation: "none",
+	}, nil
+}
+
+// Return the options of an authentication
+//
+// Without allowed credentials, the authenticator lets the user pick one of its discoverable credentials.
+func (relyingParty *RelyingParty) NewRequestOptions(
+	allowCredentials []CredentialDescriptor,
+	userVerification UserVerification,
+) (*RequestOptions, error) {
+	challenge, err := NewChallenge()
+	if err != nil {
+		return nil, err
+	}
+	return &RequestOptions{
+		Challenge:        challenge,
+		Timeout:          relyingParty.Timeout.Milliseconds(),
+		RelyingPartyID:   relyingParty.ID,
+		AllowCredentials: allowCredentials,
+		UserVerification: userVerification,
+	}, nil
+}
+
+// Verify the response to the creation options with the challenge and return the new credential
+//
+// The attestation is only checked to be consistent, the authenticators are not checked against trust anchors.
+func (relyingParty *RelyingParty) VerifyRegistration(
+	challenge []byte,
+	response *CredentialCreationResponse,
+	userVerification UserVerification,
+) (*Credential, error) {
+	if response.Type != PublicKeyCredentialType {
+		return nil, verificationError("unexpected credential type %q", response.Type)
+	}
+	err := relyingParty.verifyClientData(response.Response.ClientDataJSON, "webauthn.create", challenge)
+	if err != nil {
+		return nil, err
+	}
+	attestation, err := parseAttestationObject(response.Response.AttestationObject)
+	if err != nil {
+		return nil, err
+	}
+	authData := attestation.authenticatorData
+	err = relyingParty.verifyAuthenticatorData(authData, userVerification)
+	if err != nil {
+		return nil, err
+	}
+	if authData.credentialPublicKey == nil {
+		return nil, verificationError("no attested credential data")
+	}
+	if !bytesEqual(authData.credentialID, response.RawID) {
+		return nil, verificationError("the credential id doesn't match the attested credential")
+	}
+	publicKey, err := parsePublicKey(authData.credentialPublicKey)
+	if err != nil {
+		return nil, err
+	}
+	clientDataHash := sha256.Sum256(response.Response.ClientDataJSON)
+	err = attestation.verify(publicKey, clientDataHash[:])
+	if err != nil {
+		return nil, err
+	}
+	return &Credential{
+		ID:             authData.credentialID,
+		PublicKey:      authData.credentialPublicKey,
+		SignCount:      authData.signCount,
+		AAGUID:         authData.aaguid,
+		Transports:     response.Response.Transports,
+		UserVerified:   authData.flags&flagUserVerified != 0,
+		BackupEligible: authData.flags&flagBackupEligible != 0,
+	}, nil
+}
+
+// Verify the response to the request options with the challenge, and the stored public key and counter of the credential
+//
+// ErrSignCount is returned if the counter of the authenticator is not greater than the stored one.
+func (relyingParty *RelyingParty) VerifyAssertion(
+	challenge []byte,
+	publicKey []byte,
+	signCount uint32,
+	response *CredentialAssertionResponse,
+	userVerification UserVerification,
+) (*Assertion, error) {
+	if response.Type != PublicKeyCredentialType {
+		return nil, verificationError("unexpected credential type %q", response.Type)
+	}
+	err := relyingParty.verifyClientData(response.Response.ClientDataJSON, "webauthn.get", challenge)
+	if err != nil {
+		return nil, err
+	}
+	authData, err := parseAuthenticatorData(response.Response.AuthenticatorData)
+	if err != nil {
+		return nil, err
+	}
+	err = relyingParty.verifyAuthenticatorData(authData, userVerification)
+	if err != nil {
+		return nil, err
+	}
+	key, err := parsePublicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	clientDataHash := sha256.Sum256(response.Response.ClientDataJSON)
+	signedData := append(append([]byte{}, response.Response.AuthenticatorData...), clientDataHash[:]...)
+	err = key.verify(signedData, response.Response.Signature)
+	if err != nil {
+		return nil, err
+	}
+	// the authenticators without counter always return 0
+	if (authData.signCount != 0 || signCount != 0) && authData.signCount <= signCount {
+		return nil, ErrSignCount
+	}
+	return &Assertion{
+		SignCount:    authData.signCount,
+		UserVerified: authData.flags&flagUserVerified != 0,
+		UserHandle:   response.Response.UserHandle,
+	}, nil
+}
+
+// Generate a random challenge
+func NewChallenge() ([]byte, error) {
+	challenge := make([]byte, challengeSize)
+	_, err := rand.Read(challenge)
+	if err != nil {
+		return nil, err
+	}
+	return challenge, nil
+}
+
+// Return the challenge signed by the authenticator, to find the ceremony of a response
+func ParseChallenge(clientDataJSON []byte) ([]byte, error) {
+	clientData, err := parseClientData(clientDataJSON)
+	if err != nil {
+		return nil, err
+	}
+	return clientData.challenge, nil
+}
+
+// The collected client data, signed by the authenticator
+type clientData struct {
+	Type        string `json:"type"`
+	Challenge   string `json:"challenge"`
+	Origin      string `json:"origin"`
+	CrossOrigin bool   `json:"crossOrigin"`
+
+	challenge []byte
+}
+
+// Parse the client data
+func parseClientData(clientDataJSON []byte) (*clientData, error) {
+	var data clientData
+	err := json.Unmarshal(clientDataJSON, &data)
+	if err != nil {
+		return nil, verificationError("malformed client data: %s", err)
+	}
+	data.challenge, err = base64.RawURLEncoding.DecodeString(strings.TrimRight(data.Challenge, "="))
+	if err != nil {
+		return nil, verificationError("malformed challenge: %s", err)
+	}
+	return &data, nil
+}
+
+// Check the type, the challenge and the origin of the client data
+func (relyingParty *RelyingParty) verifyClientData(clientDataJSON []byte, ceremonyType string, challenge []byte) error {
+	data, err := parseClientData(clientDataJSON)
+	if err != nil {
+		return err
+	}
+	if data.Type != ceremonyType {
+		return verificationError("unexpected client data type %q", data.Type)
+	}
+	if !bytesEqual(data.challenge, challenge) {
+		return verificationError("the challenge doesn't match")
+	}
+	if data.CrossOrigin {
+		return verificationError("cross origin ceremonies are not allowed")
+	}
+	for _, origin := range relyingParty.Origins {
+		if data.Origin == origin {
+			return nil
+		}
+	}
+	return verificationError("origin %q is not allowed", data.Origin)
+}
+
+// Check the relying party and the flags of the authenticator data
+func (relyingParty *RelyingParty) verifyAuthenticatorData(authData *authenticatorData, userVerification UserVerification) error {
+	rpIDHash := sha256.Sum256([]byte(relyingParty.ID))
+	if !bytesEqual(authData.rpIDHash, rpIDHash[:]) {
+		return verificationError("the credential is bound to another relying party")
+	}
+	if authData.flags&flagUserPresent == 0 {
+		return verificationError("the user was not present")
+	}
+	if userVerification == UserVerificationRequired && authData.flags&flagUserVerified == 0 {
+		return verificationError("the user was not verified")
+	}
+	return nil
+}
+
+// Compare two byte slices in constant time
+func bytesEqual(a, b []byte) bool {
+	return len(a) == len(b) && subtle.ConstantTimeCompare(a, b) == 1
+}
+
+// Wrap ErrVerification with the reason of the failure
+func verificationError(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrVerification, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,169 @@
+package webauthn_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ditrit/badaas/services/auth/protocols/webauthn"
+	"github.com/ditrit/badaas/services/auth/protocols/webauthn/webauthntest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const origin = "https://badaas.example.com"
+
+var relyingParty = &webauthn.RelyingParty{
+	ID:      "badaas.example.com",
+	Name:    "badaas",
+	Origins: []string{origin},
+	Timeout: 5 * time.Minute,
+}
+
+var user = webauthn.UserEntity{ID: []byte("user-id"), Name: "bob@email.com", DisplayName: "bob"}
+
+// Register the credential of the authenticator and return it
+func register(t *testing.T, authenticator *webauthntest.Authenticator) *webauthn.Credential {
+	options, err := relyingParty.NewCreationOptions(user, nil, webauthn.UserVerificationPreferred)
+	require.NoError(t, err)
+	response, err := authenticator.CreateCredential(options)
+	require.NoError(t, err)
+	credential, err := relyingParty.VerifyRegistration(options.Challenge, response, webauthn.UserVerificationPreferred)
+	require.NoError(t, err)
+	return credential
+}
+
+func TestRegistration(t *testing.T) {
+	authenticator := webauthntest.NewAuthenticator(origin)
+
+	credential := register(t, authenticator)
+	assert.Equal(t, authenticator.CredentialID, credential.ID)
+	assert.True(t, credential.UserVerified)
+	assert.Equal(t, uint32(0), credential.SignCount)
+	assert.Equal(t, []string{"internal"}, credential.Transports)
+}
+
+func TestRegistrationWithSelfAttestation(t *testing.T) {
+	authenticator := webauthntest.NewAuthenticator(origin)
+	authenticator.SelfAttestation = true
+
+	credential := register(t, authenticator)
+	assert.Equal(t, authenticator.CredentialID, credential.ID)
+}
+
+func TestRegistrationWrongChallenge(t *testing.T) {
+	options, err := relyingParty.NewCreationOptions(user, nil, webauthn.UserVerificationPreferred)
+	require.NoError(t, err)
+	response, err := webauthntest.NewAuthenticator(origin).CreateCredential(options)
+	require.NoError(t, err)
+
+	_, err = relyingParty.VerifyRegistration([]byte("another challenge"), response, webauthn.UserVerificationPreferred)
+	assert.ErrorIs(t, err, webauthn.ErrVerification)
+}
+
+func TestRegistrationWrongOrigin(t *testing.T) {
+	options, err := relyingParty.NewCreationOptions(user, nil, webauthn.UserVerificationPreferred)
+	require.NoError(t, err)
+	response, err := webauthntest.NewAuthenticator("https://phishing.example.com").CreateCredential(options)
+	require.NoError(t, err)
+
+	_, err = relyingParty.VerifyRegistration(options.Challenge, response, webauthn.UserVerificationPreferred)
+	assert.ErrorIs(t, err, webauthn.ErrVerification)
+}
+
+func TestRegistrationUserVerificationRequired(t *testing.T) {
+	authenticator := webauthntest.NewAuthenticator(origin)
+	authenticator.UserVerification = false
+	options, err := relyingParty.NewCreationOptions(user, nil, webauthn.UserVerificationRequired)
+	require.NoError(t, err)
+	response, err := authenticator.CreateCredential(options)
+	require.NoError(t, err)
+
+	_, err = relyingParty.VerifyRegistration(options.Challenge, response, webauthn.UserVerificationRequired)
+	assert.ErrorIs(t, err, webauthn.ErrVerification)
+}
+
+func TestAssertion(t *testing.T) {
+	authenticator := webauthntest.NewAuthenticator(origin)
+	credential := register(t, authenticator)
+
+	options, err := relyingParty.NewRequestOptions(nil, webauthn.UserVerificationRequired)
+	require.NoError(t, err)
+	response, err := authenticator.GetAssertion(options)
+	require.NoError(t, err)
+
+	assertion, err := relyingParty.VerifyAssertion(options.Challenge, credential.PublicKey, credential.SignCount,
+		response, webauthn.UserVerificationRequired)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(1), assertion.SignCount)
+	assert.Equal(t, user.ID, webauthn.URLEncodedBase64(assertion.UserHandle))
+}
+
+func TestAssertionWrongRelyingParty(t *testing.T) {
+	authenticator := webauthntest.NewAuthenticator(origin)
+	credential := register(t, authenticator)
+
+	options, err := relyingParty.NewRequestOptions(nil, webauthn.UserVerificationPreferred)
+	require.NoError(t, err)
+	options.RelyingPartyID = "example.com"
+	response, err := authenticator.GetAssertion(options)
+	require.NoError(t, err)
+
+	_, err = relyingParty.VerifyAssertion(options.Challenge, credential.PublicKey, credential.SignCount,
+		response, webauthn.UserVerificationPreferred)
+	assert.ErrorIs(t, err, webauthn.ErrVerification)
+}
+
+func TestAssertionTamperedSignature(t *testing.T) {
+	authenticator := webauthntest.NewAuthenticator(origin)
+	credential := register(t, authenticator)
+
+	options, err := relyingParty.NewRequestOptions(nil, webauthn.UserVerificationPreferred)
+	require.NoError(t, err)
+	response, err := authenticator.GetAssertion(options)
+	require.NoError(t, err)
+	response.Response.Signature[len(response.Response.Signature)-1] ^= 0xff
+
+	_, err = relyingParty.VerifyAssertion(options.Challenge, credential.PublicKey, credential.SignCount,
+		response, webauthn.UserVerificationPreferred)
+	assert.ErrorIs(t, err, webauthn.ErrVerification)
+}
+
+func TestAssertionSignCountWentBackwards(t *testing.T) {
+	authenticator := webauthntest.NewAuthenticator(origin)
+	credential := register(t, authenticator)
+
+	options, err := relyingParty.NewRequestOptions(nil, webauthn.UserVerificationPreferred)
+	require.NoError(t, err)
+	response, err := authenticator.GetAssertion(options)
+	require.NoError(t, err)
+
+	// a clone of the authenticator already used the counter
+	_, err = relyingParty.VerifyAssertion(options.Challenge, credential.PublicKey, 5,
+		response, webauthn.UserVerificationPreferred)
+	assert.ErrorIs(t, err, webauthn.ErrSignCount)
+}
+
+func TestParseChallenge(t *testing.T) {
+	authenticator := webauthntest.NewAuthenticator(origin)
+	register(t, authenticator)
+	options, err := relyingParty.NewRequestOptions(nil, webauthn.UserVerificationPreferred)
+	require.NoError(t, err)
+	response, err := authenticator.GetAssertion(options)
+	require.NoError(t, err)
+
+	challenge, err := webauthn.ParseChallenge(response.Response.ClientDataJSON)
+	require.NoError(t, err)
+	assert.Equal(t, []byte(options.Challenge), challenge)
+}
+
+func TestURLEncodedBase64JSON(t *testing.T) {
+	encoded, err := json.Marshal(webauthn.URLEncodedBase64{0xfb, 0xff})
+	require.NoError(t, err)
+	assert.Equal(t, `"-_8"`, string(encoded))
+
+	var decoded webauthn.URLEncodedBase64
+	// the padding sent by some clients is accepted
+	require.NoError(t, json.Unmarshal([]byte(`"-_8="`), &decoded))
+	assert.Equal(t, webauthn.URLEncodedBase64{0xfb, 0xff}, decoded)
+}
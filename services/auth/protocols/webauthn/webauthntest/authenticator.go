@@ -0,0 +1,202 @@
+// Package webauthntest provides a software authenticator to test the WebAuthn ceremonies.
+package webauthntest
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+
+	"github.com/ditrit/badaas/services/auth/protocols/webauthn"
+	"github.com/fxamacker/cbor/v2"
+)
+
+// The flags set by the authenticator
+const (
+	flagUserPresent            byte = 0x01
+	flagUserVerified           byte = 0x04
+	flagAttestedCredentialData byte = 0x40
+)
+
+// Returned when none of the credentials of the options belong to the authenticator
+var ErrNoCredential = errors.New("the authenticator has no allowed credential")
+
+// Returned when the authenticator already holds one of the excluded credentials
+var ErrExcludedCredential = errors.New("the authenticator holds an excluded credential")
+
+// A software authenticator holding a single discoverable ES256 credential
+type Authenticator struct {
+	// The origin of the page running the ceremonies
+	Origin string
+	// Set the user verified flag in the responses
+	UserVerification bool
+	// Return a packed self attestation instead of the none attestation
+	SelfAttestation bool
+
+	CredentialID []byte
+	UserHandle   []byte
+	SignCount    uint32
+	privateKey   *ecdsa.PrivateKey
+}
+
+// Create an authenticator for the pages of the origin, it verifies its user
+func NewAuthenticator(origin string) *Authenticator {
+	return &Authenticator{
+		Origin:           origin,
+		UserVerification: true,
+	}
+}
+
+// Create the credential of the authenticator and return the response of navigator.credentials.create
+func (authenticator *Authenticator) CreateCredential(
+	options *webauthn.CreationOptions,
+) (*webauthn.CredentialCreationResponse, error) {
+	for _, excluded := range options.ExcludeCredentials {
+		if authenticator.CredentialID != nil && bytes.Equal(excluded.ID, authenticator.CredentialID) {
+			return nil, ErrExcludedCredential
+		}
+	}
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	credentialID := make([]byte, 16)
+	_, err = rand.Read(credentialID)
+	if err != nil {
+		return nil, err
+	}
+	authenticator.privateKey = privateKey
+	authenticator.CredentialID = credentialID
+	authenticator.UserHandle = options.User.ID
+	authenticator.SignCount = 0
+
+	publicKey, err := authenticator.encodePublicKey()
+	if err != nil {
+		return nil, err
+	}
+	authData := authenticator.authenticatorData(options.RelyingParty.ID, flagAttestedCredentialData)
+	authData = append(authData, make([]byte, 16)...) // the zero AAGUID of the software authenticators
+	authData = append(authData, byte(len(credentialID)>>8), byte(len(credentialID)))
+	authData = append(authData, credentialID...)
+	authData = append(authData, publicKey...)
+
+	clientDataJSON, err := authenticator.clientData("webauthn.create", options.Challenge)
+	if err != nil {
+		return nil, err
+	}
+	format, statement := "none", map[string]any{}
+	if authenticator.SelfAttestation {
+		signature, err := authenticator.sign(authData, clientDataJSON)
+		if err != nil {
+			return nil, err
+		}
+		format, statement = "packed", map[string]any{"alg": webauthn.AlgES256, "sig": signature}
+	}
+	attestationObject, err := cbor.Marshal(map[string]any{
+		"fmt":      format,
+		"attStmt":  statement,
+		"authData": authData,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &webauthn.CredentialCreationResponse{
+		ID:    base64.RawURLEncoding.EncodeToString(credentialID),
+		RawID: credentialID,
+		Type:  webauthn.PublicKeyCredentialType,
+		Response: webauthn.AttestationResponse{
+			ClientDataJSON:    clientDataJSON,
+			AttestationObject: attestationObject,
+			Transports:        []string{"internal"},
+		},
+	}, nil
+}
+
+// Sign the challenge with the credential and return the response of navigator.credentials.get
+func (authenticator *Authenticator) GetAssertion(
+	options *webauthn.RequestOptions,
+) (*webauthn.CredentialAssertionResponse, error) {
+	if authenticator.privateKey == nil {
+		return nil, ErrNoCredential
+	}
+	allowed := len(options.AllowCredentials) == 0
+	for _, allowedCredential := range options.AllowCredentials {
+		if bytes.Equal(allowedCredential.ID, authenticator.CredentialID) {
+			allowed = true
+		}
+	}
+	if !allowed {
+		return nil, ErrNoCredential
+	}
+	authenticator.SignCount++
+	authData := authenticator.authenticatorData(options.RelyingPartyID, 0)
+	clientDataJSON, err := authenticator.clientData("webauthn.get", options.Challenge)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := authenticator.sign(authData, clientDataJSON)
+	if err != nil {
+		return nil, err
+	}
+	return &webauthn.CredentialAssertionResponse{
+		ID:    base64.RawURLEncoding.EncodeToString(authenticator.CredentialID),
+		RawID: authenticator.CredentialID,
+		Type:  webauthn.PublicKeyCredentialType,
+		Response: webauthn.AssertionResponse{
+			ClientDataJSON:    clientDataJSON,
+			AuthenticatorData: authData,
+			Signature:         signature,
+			UserHandle:        authenticator.UserHandle,
+		},
+	}, nil
+}
+
+// Return the authenticator data without attested credential data
+func (authenticator *Authenticator) authenticatorData(rpID string, flags byte) []byte {
+	rpIDHash := sha256.Sum256([]byte(rpID))
+	flags |= flagUserPresent
+	if authenticator.UserVerification {
+		flags |= flagUserVerified
+	}
+	signCount := make([]byte, 4)
+	binary.BigEndian.PutUint32(signCount, authenticator.SignCount)
+	authData := append(rpIDHash[:], flags)
+	return append(authData, signCount...)
+}
+
+// Return the client data collected by the browser
+func (authenticator *Authenticator) clientData(ceremonyType string, challenge []byte) ([]byte, error) {
+	return json.Marshal(map[string]any{
+		"type":        ceremonyType,
+		"challenge":   base64.RawURLEncoding.EncodeToString(challenge),
+		"origin":      authenticator.Origin,
+		"crossOrigin": false,
+	})
+}
+
+// Sign the authenticator data and the hash of the client data
+func (authenticator *Authenticator) sign(authData, clientDataJSON []byte) ([]byte, error) {
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	hash := sha256.Sum256(append(append([]byte{}, authData...), clientDataHash[:]...))
+	return ecdsa.SignASN1(rand.Reader, authenticator.privateKey, hash[:])
+}
+
+// Return the COSE encoded public key of the credential
+func (authenticator *Authenticator) encodePublicKey() ([]byte, error) {
+	x := make([]byte, 32)
+	y := make([]byte, 32)
+	authenticator.privateKey.X.FillBytes(x)
+	authenticator.privateKey.Y.FillBytes(y)
+	return cbor.Marshal(map[int]any{
+		1:  2, // EC2
+		3:  webauthn.AlgES256,
+		-1: 1, // P-256
+		-2: x,
+		-3: y,
+	})
+}
@@ -0,0 +1,666 @@
+package groupservice
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/ditrit/badaas/httperrors"
+	"github.com/ditrit/badaas/persistence/models"
+	"github.com/ditrit/badaas/persistence/repository"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Allow to manage every organisation, group and membership
+const PermissionGroupsManage string = "groups:manage"
+
+// GroupService handle organisations, groups and their members
+//
+// The members of a group are also members of its ancestors, so a group can be used as a principal
+// that includes the members of its subgroups. On the other hand, the owners and admins of a group
+// can manage the members of its subgroups and the owners and admins of an organisation
+// can manage the members of every group of the organisation.
+type GroupService interface {
+	CreateOrganisation(name, description string) (*models.Organisation, httperrors.HTTPError)
+	GetOrganisations() ([]*models.Organisation, httperrors.HTTPError)
+	GetOrganisation(organisationID uuid.UUID) (*models.Organisation, httperrors.HTTPError)
+	DeleteOrganisation(organisationID uuid.UUID) httperrors.HTTPError
+	GetOrganisationMembers(organisationID uuid.UUID) ([]*models.OrganisationMember, httperrors.HTTPError)
+	AddOrganisationMember(organisationID, userID uuid.UUID, role string) httperrors.HTTPError
+	RemoveOrganisationMember(organisationID, userID uuid.UUID) httperrors.HTTPError
+	// Return the membership role of the user in the organisation, empty if the user is not a member
+	GetOrganisationRole(userID, organisationID uuid.UUID) (string, httperrors.HTTPError)
+
+	CreateGroup(name, description string, organisationID, parentID *uuid.UUID) (*models.Group, httperrors.HTTPError)
+	// Return the groups, only the ones of the organisation if organisationID is not nil
+	GetGroups(organisationID *uuid.UUID) ([]*models.Group, httperrors.HTTPError)
+	GetGroup(groupID uuid.UUID) (*models.Group, httperrors.HTTPError)
+	// Move a group under another group, or at the top level if parentID is nil
+	SetGroupParent(groupID uuid.UUID, parentID *uuid.UUID) httperrors.HTTPError
+	DeleteGroup(groupID uuid.UUID) httperrors.HTTPError
+	GetGroupMembers(groupID uuid.UUID) ([]*models.GroupMember, httperrors.HTTPError)
+	AddGroupMember(groupID, userID uuid.UUID, role string) httperrors.HTTPError
+	RemoveGroupMember(groupID, userID uuid.UUID) httperrors.HTTPError
+	// Return the strongest membership role the user has on the group,
+	// considering the group, its ancestors and its organisation. Empty if the user has none.
+	GetGroupRole(userID, groupID uuid.UUID) (string, httperrors.HTTPError)
+
+	// Return the groups the user is a member of, directly or through a subgroup
+	GetUserGroups(userID uuid.UUID) ([]*models.Group, httperrors.HTTPError)
+	// Return the ids of the groups the user is a member of, directly or through a subgroup
+	GetUserGroupIDs(userID uuid.UUID) ([]string, httperrors.HTTPError)
+	// Return the ids of the organisations the user is a member of
+	GetUserOrganisationIDs(userID uuid.UUID) ([]string, httperrors.HTTPError)
+}
+
+// Check interface compliance
+var _ GroupService = (*groupServiceImpl)(nil)
+
+// The GroupService concrete implementation
+type groupServiceImpl struct {
+	logger                       *zap.Logger
+	organisationRepository       repository.CRUDRepository[models.Organisation, uuid.UUID]
+	groupRepository              repository.CRUDRepository[models.Group, uuid.UUID]
+	organisationMemberRepository repository.CRUDRepository[models.OrganisationMember, uuid.UUID]
+	groupMemberRepository        repository.CRUDRepository[models.GroupMember, uuid.UUID]
+	groupRoleRepository          repository.CRUDRepository[models.GroupRole, uuid.UUID]
+	userRepository               repository.CRUDRepository[models.User, uuid.UUID]
+}
+
+// GroupService constructor
+func NewGroupService(
+	logger *zap.Logger,
+	organisationRepository repository.CRUDRepository[models.Organisation, uuid.UUID],
+	groupRepository repository.CRUDRepository[models.Group, uuid.UUID],
+	organisationMemberRepository repository.CRUDRepository[models.OrganisationMember, uuid.UUID],
+	groupMemberRepository repository.CRUDRepository[models.GroupMember, uuid.UUID],
+	groupRoleRepository repository.CRUDRepository[models.GroupRole, uuid.UUID],
+	userRepository repository.CRUDRepository[models.User, uuid.UUID],
+) GroupService {
+	return &groupServiceImpl{
+		logger:                       logger,
+		organisationRepository:       organisationRepository,
+		groupRepository:              groupRepository,
+		organisationMemberRepository: organisationMemberRepository,
+		groupMemberRepository:        groupMemberRepository,
+		groupRoleRepository:          groupRoleRepository,
+		userRepository:               userRepository,
+	}
+}
+
+// Create a new organisation
+func (groupService *groupServiceImpl) CreateOrganisation(name, description string) (*models.Organisation, httperrors.HTTPError) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, newInvalidError("invalid organisation", "the organisation name can't be empty")
+	}
+	organisation := &models.Organisation{
+		Name:        name,
+		Description: description,
+	}
+	herr := groupService.organisationRepository.Create(organisation)
+	if herr != nil {
+		return nil, herr
+	}
+	groupService.logger.Info("Successfully created a new organisation", zap.String("organisation", name))
+	return organisation, nil
+}
+
+// Return all the organisations
+func (groupService *groupServiceImpl) GetOrganisations() ([]*models.Organisation, httperrors.HTTPError) {
+	return groupService.organisationRepository.GetAll(repository.NewSortOption("name", false))
+}
+
+// Return the organisation with the provided id
+func (groupService *groupServiceImpl) GetOrganisation(organisationID uuid.UUID) (*models.Organisation, httperrors.HTTPError) {
+	organisations, herr := groupService.organisationRepository.Find(
+		squirrel.Eq{"id": organisationID.String()}, nil, nil,
+	)
+	if herr != nil {
+		return nil, herr
+	}
+	if !organisations.HasContent {
+		return nil, httperrors.NewErrorNotFound("organisation", fmt.Sprintf("no organisation found with id %q", organisationID))
+	}
+	return organisations.Ressources[0], nil
+}
+
+// Delete an organisation and its memberships
+//
+// An organisation that still has groups can't be deleted.
+func (groupService *groupServiceImpl) DeleteOrganisation(organisationID uuid.UUID) httperrors.HTTPError {
+	organisation, herr := groupService.GetOrganisation(organisationID)
+	if herr != nil {
+		return herr
+	}
+	count, herr := groupService.groupRepository.Count(squirrel.Eq{"organisation_id": organisationID.String()})
+	if herr != nil {
+		return herr
+	}
+	if count > 0 {
+		return newInvalidError("organisation error", "the organisation still has groups, delete them first")
+	}
+	members, herr := groupService.GetOrganisationMembers(organisationID)
+	if herr != nil {
+		return herr
+	}
+	for _, member := range members {
+		herr = groupService.organisationMemberRepository.Delete(member)
+		if herr != nil {
+			return herr
+		}
+	}
+	herr = groupService.organisationRepository.Delete(organisation)
+	if herr != nil {
+		return herr
+	}
+	groupService.logger.Info("Deleted organisation", zap.String("organisation", organisation.Name))
+	return nil
+}
+
+// Return the members of an organisation
+func (groupService *groupServiceImpl) GetOrganisationMembers(organisationID uuid.UUID) ([]*models.OrganisationMember, httperrors.HTTPError) {
+	members, herr := groupService.organisationMemberRepository.Find(
+		squirrel.Eq{"organisation_id": organisationID.String()}, nil, nil,
+	)
+	if herr != nil {
+		return nil, herr
+	}
+	return members.Ressources, nil
+}
+
+// Add a user to an organisation, or change its role if it is already a member
+func (groupService *groupServiceImpl) AddOrganisationMember(organisationID, userID uuid.UUID, role string) httperrors.HTTPError {
+	if !models.IsValidMembershipRole(role) {
+		return newInvalidMembershipRoleError(role)
+	}
+	_, herr := groupService.GetOrganisation(organisationID)
+	if herr != nil {
+		return herr
+	}
+	herr = groupService.checkUserExists(userID)
+	if herr != nil {
+		return herr
+	}
+	members, herr := groupService.organisationMemberRepository.Find(
+		squirrel.Eq{"organisation_id": organisationID.String(), "user_id": userID.String()}, nil, nil,
+	)
+	if herr != nil {
+		return herr
+	}
+	if members.HasContent {
+		member := members.Ressources[0]
+		member.Role = role
+		herr = groupService.organisationMemberRepository.Save(member)
+	} else {
+		herr = groupService.organisationMemberRepository.Create(&models.OrganisationMember{
+			OrganisationID: organisationID,
+			UserID:         userID,
+			Role:           role,
+		})
+	}
+	if herr != nil {
+		return herr
+	}
+	groupService.logger.Info("Added organisation member",
+		zap.String("organisationID", organisationID.String()),
+		zap.String("userID", userID.String()),
+		zap.String("role", role),
+	)
+	return nil
+}
+
+// Remove a user from an organisation and from the groups of the organisation
+func (groupService *groupServiceImpl) RemoveOrganisationMember(organisationID, userID uuid.UUID) httperrors.HTTPError {
+	members, herr := groupService.organisationMemberRepository.Find(
+		squirrel.Eq{"organisation_id": organisationID.String(), "user_id": userID.String()}, nil, nil,
+	)
+	if herr != nil {
+		return herr
+	}
+	if !members.HasContent {
+		return httperrors.NewErrorNotFound("member",
+			fmt.Sprintf("the user %q is not a member of the organisation %q", userID, organisationID))
+	}
+	groups, herr := groupService.GetGroups(&organisationID)
+	if herr != nil {
+		return herr
+	}
+	if len(groups) > 0 {
+		groupMembers, herr := groupService.groupMemberRepository.Find(
+			squirrel.Eq{"group_id": getIDs(groups), "user_id": userID.String()}, nil, nil,
+		)
+		if herr != nil {
+			return herr
+		}
+		for _, groupMember := range groupMembers.Ressources {
+			herr = groupService.groupMemberRepository.Delete(groupMember)
+			if herr != nil {
+				return herr
+			}
+		}
+	}
+	for _, member := range members.Ressources {
+		herr = groupService.organisationMemberRepository.Delete(member)
+		if herr != nil {
+			return herr
+		}
+	}
+	groupService.logger.Info("Removed organisation member",
+		zap.String("organisationID", organisationID.String()), zap.String("userID", userID.String()))
+	return nil
+}
+
+// Return the membership role of the user in the organisation, empty if the user is not a member
+func (groupService *groupServiceImpl) GetOrganisationRole(userID, organisationID uuid.UUID) (string, httperrors.HTTPError) {
+	members, herr := groupService.organisationMemberRepository.Find(
+		squirrel.Eq{"organisation_id": organisationID.String(), "user_id": userID.String()}, nil, nil,
+	)
+	if herr != nil {
+		return "", herr
+	}
+	if !members.HasContent {
+		return "", nil
+	}
+	return members.Ressources[0].Role, nil
+}
+
+// Create a new group
+//
+// A subgroup belongs to the organisation of its parent.
+func (groupService *groupServiceImpl) CreateGroup(
+	name, description string,
+	organisationID, parentID *uuid.UUID,
+) (*models.Group, httperrors.HTTPError) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, newInvalidError("invalid group", "the group name can't be empty")
+	}
+	if parentID != nil {
+		parent, herr := groupService.GetGroup(*parentID)
+		if herr != nil {
+			return nil, herr
+		}
+		if organisationID != nil && !sameID(organisationID, parent.OrganisationID) {
+			return nil, newInvalidError("invalid group", "a subgroup must belong to the organisation of its parent")
+		}
+		organisationID = parent.OrganisationID
+	} else if organisationID != nil {
+		_, herr := groupService.GetOrganisation(*organisationID)
+		if herr != nil {
+			return nil, herr
+		}
+	}
+	group := &models.Group{
+		Name:           name,
+		Description:    description,
+		OrganisationID: organisationID,
+		ParentID:       parentID,
+	}
+	herr := groupService.groupRepository.Create(group)
+	if herr != nil {
+		return nil, herr
+	}
+	groupService.logger.Info("Successfully created a new group", zap.String("group", name))
+	return group, nil
+}
+
+// Return the groups, only the ones of the organisation if organisationID is not nil
+func (groupService *groupServiceImpl) GetGroups(organisationID *uuid.UUID) ([]*models.Group, httperrors.HTTPError) {
+	if organisationID == nil {
+		return groupService.groupRepository.GetAll(repository.NewSortOption("name", false))
+	}
+	groups, herr := groupService.groupRepository.Find(
+		squirrel.Eq{"organisation_id": organisationID.String()}, nil, repository.NewSortOption("name", false),
+	)
+	if herr != nil {
+		return nil, herr
+	}
+	return groups.Ressources, nil
+}
+
+// Return the group with the provided id
+func (groupService *groupServiceImpl) GetGroup(groupID uuid.UUID) (*models.Group, httperrors.HTTPError) {
+	groups, herr := groupService.groupRepository.Find(squirrel.Eq{"id": groupID.String()}, nil, nil)
+	if herr != nil {
+		return nil, herr
+	}
+	if !groups.HasContent {
+		return nil, httperrors.NewErrorNotFound("group", fmt.Sprintf("no group found with id %q", groupID))
+	}
+	return groups.Ressources[0], nil
+}
+
+// Move a group under another group, or at the top level if parentID is nil
+//
+// The parent must belong to the same organisation and can't be a descendant of the group.
+func (groupService *groupServiceImpl) SetGroupParent(groupID uuid.UUID, parentID *uuid.UUID) httperrors.HTTPError {
+	group, herr := groupService.GetGroup(groupID)
+	if herr != nil {
+		return herr
+	}
+	if parentID != nil {
+		parent, herr := groupService.GetGroup(*parentID)
+		if herr != nil {
+			return herr
+		}
+		if !sameID(group.OrganisationID, parent.OrganisationID) {
+			return newInvalidError("invalid group", "a subgroup must belong to the organisation of its parent")
+		}
+		ancestors, herr := groupService.getAncestors(parent)
+		if herr != nil {
+			return herr
+		}
+		for _, ancestor := range append(ancestors, parent) {
+			if ancestor.ID == group.ID {
+				return newInvalidError("invalid group", "a group can't be nested in itself or in one of its subgroups")
+			}
+		}
+	}
+	group.ParentID = parentID
+	herr = groupService.groupRepository.Save(group)
+	if herr != nil {
+		return herr
+	}
+	groupService.logger.Info("Moved group", zap.String("group", group.Name))
+	return nil
+}
+
+// Delete a group, its memberships and its role assignments
+//
+// A group that still has subgroups can't be deleted.
+func (groupService *groupServiceImpl) DeleteGroup(groupID uuid.UUID) httperrors.HTTPError {
+	group, herr := groupService.GetGroup(groupID)
+	if herr != nil {
+		return herr
+	}
+	count, herr := groupService.groupRepository.Count(squirrel.Eq{"parent_id": groupID.String()})
+	if herr != nil {
+		return herr
+	}
+	if count > 0 {
+		return newInvalidError("group error", "the group still has subgroups, delete or move them first")
+	}
+	members, herr := groupService.GetGroupMembers(groupID)
+	if herr != nil {
+		return herr
+	}
+	for _, member := range members {
+		herr = groupService.groupMemberRepository.Delete(member)
+		if herr != nil {
+			return herr
+		}
+	}
+	groupRoles, herr := groupService.groupRoleRepository.Find(squirrel.Eq{"group_id": groupID.String()}, nil, nil)
+	if herr != nil {
+		return herr
+	}
+	for _, groupRole := range groupRoles.Ressources {
+		herr = groupService.groupRoleRepository.Delete(groupRole)
+		if herr != nil {
+			return herr
+		}
+	}
+	herr = groupService.groupRepository.Delete(group)
+	if herr != nil {
+		return herr
+	}
+	groupService.logger.Info("Deleted group", zap.String("group", group.Name))
+	return nil
+}
+
+// Return the direct members of a group
+func (groupService *groupServiceImpl) GetGroupMembers(groupID uuid.UUID) ([]*models.GroupMember, httperrors.HTTPError) {
+	members, herr := groupService.groupMemberRepository.Find(squirrel.Eq{"group_id": groupID.String()}, nil, nil)
+	if herr != nil {
+		return nil, herr
+	}
+	return members.Ressources, nil
+}
+
+// Add a user to a group, or change its role if it is already a member
+//
+// The user must be a member of the organisation of the group.
+func (groupService *groupServiceImpl) AddGroupMember(groupID, userID uuid.UUID, role string) httperrors.HTTPError {
+	if !models.IsValidMembershipRole(role) {
+		return newInvalidMembershipRoleError(role)
+	}
+	group, herr := groupService.GetGroup(groupID)
+	if herr != nil {
+		return herr
+	}
+	herr = groupService.checkUserExists(userID)
+	if herr != nil {
+		return herr
+	}
+	if group.OrganisationID != nil {
+		organisationRole, herr := groupService.GetOrganisationRole(userID, *group.OrganisationID)
+		if herr != nil {
+			return herr
+		}
+		if organisationRole == "" {
+			return newInvalidError("invalid member", "the user must be a member of the organisation of the group")
+		}
+	}
+	members, herr := groupService.groupMemberRepository.Find(
+		squirrel.Eq{"group_id": groupID.String(), "user_id": userID.String()}, nil, nil,
+	)
+	if herr != nil {
+		return herr
+	}
+	if members.HasContent {
+		member := members.Ressources[0]
+		member.Role = role
+		herr = groupService.groupMemberRepository.Save(member)
+	} else {
+		herr = groupService.groupMemberRepository.Create(&models.GroupMember{
+			GroupID: groupID,
+			UserID:  userID,
+			Role:    role,
+		})
+	}
+	if herr != nil {
+		return herr
+	}
+	groupService.logger.Info("Added group member",
+		zap.String("groupID", groupID.String()),
+		zap.String("userID", userID.String()),
+		zap.String("role", role),
+	)
+	return nil
+}
+
+// Remove a user from a group
+func (groupService *groupServiceImpl) RemoveGroupMember(groupID, userID uuid.UUID) httperrors.HTTPError {
+	members, herr := groupService.groupMemberRepository.Find(
+		squirrel.Eq{"group_id": groupID.String(), "user_id": userID.String()}, nil, nil,
+	)
+	if herr != nil {
+		return herr
+	}
+	if !members.HasContent {
+		return httperrors.NewErrorNotFound("member",
+			fmt.Sprintf("the user %q is not a member of the group %q", userID, groupID))
+	}
+	for _, member := range members.Ressources {
+		herr = groupService.groupMemberRepository.Delete(member)
+		if herr != nil {
+			return herr
+		}
+	}
+	groupService.logger.Info("Removed group member",
+		zap.String("groupID", groupID.String()), zap.String("userID", userID.String()))
+	return nil
+}
+
+// Return the strongest membership role the user has on the group,
+// considering the group, its ancestors and its organisation. Empty if the user has none.
+func (groupService *groupServiceImpl) GetGroupRole(userID, groupID uuid.UUID) (string, httperrors.HTTPError) {
+	group, herr := groupService.GetGroup(groupID)
+	if herr != nil {
+		return "", herr
+	}
+	ancestors, herr := groupService.getAncestors(group)
+	if herr != nil {
+		return "", herr
+	}
+	members, herr := groupService.groupMemberRepository.Find(
+		squirrel.Eq{"group_id": getIDs(append(ancestors, group)), "user_id": userID.String()}, nil, nil,
+	)
+	if herr != nil {
+		return "", herr
+	}
+	role := ""
+	for _, member := range members.Ressources {
+		role = strongestRole(role, member.Role)
+	}
+	if group.OrganisationID != nil {
+		organisationRole, herr := groupService.GetOrganisationRole(userID, *group.OrganisationID)
+		if herr != nil {
+			return "", herr
+		}
+		// being a simple member of the organisation does not give any role on its groups
+		if models.CanManageMembers(organisationRole) {
+			role = strongestRole(role, organisationRole)
+		}
+	}
+	return role, nil
+}
+
+// Return the groups the user is a member of, directly or through a subgroup
+func (groupService *groupServiceImpl) GetUserGroups(userID uuid.UUID) ([]*models.Group, httperrors.HTTPError) {
+	groupIDs, herr := groupService.GetUserGroupIDs(userID)
+	if herr != nil {
+		return nil, herr
+	}
+	if len(groupIDs) == 0 {
+		return []*models.Group{}, nil
+	}
+	groups, herr := groupService.groupRepository.Find(
+		squirrel.Eq{"id": groupIDs}, nil, repository.NewSortOption("name", false),
+	)
+	if herr != nil {
+		return nil, herr
+	}
+	return groups.Ressources, nil
+}
+
+// Return the ids of the groups the user is a member of, directly or through a subgroup
+func (groupService *groupServiceImpl) GetUserGroupIDs(userID uuid.UUID) ([]string, httperrors.HTTPError) {
+	members, herr := groupService.groupMemberRepository.Find(squirrel.Eq{"user_id": userID.String()}, nil, nil)
+	if herr != nil {
+		return nil, herr
+	}
+	seen := make(map[string]bool)
+	groupIDs := []string{}
+	frontier := []string{}
+	for _, member := range members.Ressources {
+		groupID := member.GroupID.String()
+		if !seen[groupID] {
+			seen[groupID] = true
+			groupIDs = append(groupIDs, groupID)
+			frontier = append(frontier, groupID)
+		}
+	}
+	// walk up the hierarchy, one level at a time
+	for len(frontier) > 0 {
+		groups, herr := groupService.groupRepository.Find(squirrel.Eq{"id": frontier}, nil, nil)
+		if herr != nil {
+			return nil, herr
+		}
+		frontier = []string{}
+		for _, group := range groups.Ressources {
+			if group.ParentID == nil {
+				continue
+			}
+			parentID := group.ParentID.String()
+			if !seen[parentID] {
+				seen[parentID] = true
+				groupIDs = append(groupIDs, parentID)
+				frontier = append(frontier, parentID)
+			}
+		}
+	}
+	return groupIDs, nil
+}
+
+// Return the ids of the organisations the user is a member of
+func (groupService *groupServiceImpl) GetUserOrganisationIDs(userID uuid.UUID) ([]string, httperrors.HTTPError) {
+	members, herr := groupService.organisationMemberRepository.Find(squirrel.Eq{"user_id": userID.String()}, nil, nil)
+	if herr != nil {
+		return nil, herr
+	}
+	organisationIDs := make([]string, 0, len(members.Ressources))
+	for _, member := range members.Ressources {
+		organisationIDs = append(organisationIDs, member.OrganisationID.String())
+	}
+	return organisationIDs, nil
+}
+
+// Return the ancestors of a group, from its parent to the top level group
+func (groupService *groupServiceImpl) getAncestors(group *models.Group) ([]*models.Group, httperrors.HTTPError) {
+	ancestors := []*models.Group{}
+	seen := map[uuid.UUID]bool{group.ID: true}
+	for group.ParentID != nil && !seen[*group.ParentID] {
+		parent, herr := groupService.GetGroup(*group.ParentID)
+		if herr != nil {
+			return nil, herr
+		}
+		seen[parent.ID] = true
+		ancestors = append(ancestors, parent)
+		group = parent
+	}
+	return ancestors, nil
+}
+
+// Return an HTTPError if the user does not exist
+func (groupService *groupServiceImpl) checkUserExists(userID uuid.UUID) httperrors.HTTPError {
+	count, herr := groupService.userRepository.Count(squirrel.Eq{"id": userID.String()})
+	if herr != nil {
+		return herr
+	}
+	if count == 0 {
+		return httperrors.NewErrorNotFound("user", fmt.Sprintf("no user found with id %q", userID))
+	}
+	return nil
+}
+
+// Return the ids of the groups
+func getIDs(groups []*models.Group) []string {
+	ids := make([]string, 0, len(groups))
+	for _, group := range groups {
+		ids = append(ids, group.ID.String())
+	}
+	return ids
+}
+
+// Return true if both ids are nil or equal
+func sameID(first, second *uuid.UUID) bool {
+	if first == nil || second == nil {
+		return first == second
+	}
+	return *first == *second
+}
+
+// Return the strongest of the two membership roles
+func strongestRole(first, second string) string {
+	if models.MembershipRoleIncludes(first, second) {
+		return first
+	}
+	return second
+}
+
+// Create an HTTPError for an invalid input
+func newInvalidError(err, message string) httperrors.HTTPError {
+	return httperrors.NewHTTPError(http.StatusBadRequest, err, message, nil, false)
+}
+
+// Create an HTTPError for an unknown membership role
+func newInvalidMembershipRoleError(role string) httperrors.HTTPError {
+	return newInvalidError("invalid membership role", fmt.Sprintf(
+		"the membership role %q is not valid, use one of %q, %q or %q",
+		role, models.MembershipRoleOwner, models.MembershipRoleAdmin, models.MembershipRoleMember,
+	))
+}
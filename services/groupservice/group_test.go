@@ -0,0 +1,240 @@
+package groupservice_test
+
+import (
+	"testing"
+
+	"github.com/ditrit/badaas/httperrors"
+	repositorymocks "github.com/ditrit/badaas/mocks/persistence/repository"
+	"github.com/ditrit/badaas/persistence/models"
+	"github.com/ditrit/badaas/persistence/pagination"
+	"github.com/ditrit/badaas/services/groupservice"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type groupTestValues struct {
+	organisationRepository       *repositorymocks.CRUDRepository[models.Organisation, uuid.UUID]
+	groupRepository              *repositorymocks.CRUDRepository[models.Group, uuid.UUID]
+	organisationMemberRepository *repositorymocks.CRUDRepository[models.OrganisationMember, uuid.UUID]
+	groupMemberRepository        *repositorymocks.CRUDRepository[models.GroupMember, uuid.UUID]
+	groupRoleRepository          *repositorymocks.CRUDRepository[models.GroupRole, uuid.UUID]
+	userRepository               *repositorymocks.CRUDRepository[models.User, uuid.UUID]
+	service                      groupservice.GroupService
+}
+
+// make values for test
+func setupTest(t *testing.T) groupTestValues {
+	values := groupTestValues{
+		organisationRepository:       repositorymocks.NewCRUDRepository[models.Organisation, uuid.UUID](t),
+		groupRepository:              repositorymocks.NewCRUDRepository[models.Group, uuid.UUID](t),
+		organisationMemberRepository: repositorymocks.NewCRUDRepository[models.OrganisationMember, uuid.UUID](t),
+		groupMemberRepository:        repositorymocks.NewCRUDRepository[models.GroupMember, uuid.UUID](t),
+		groupRoleRepository:          repositorymocks.NewCRUDRepository[models.GroupRole, uuid.UUID](t),
+		userRepository:               repositorymocks.NewCRUDRepository[models.User, uuid.UUID](t),
+	}
+	values.service = groupservice.NewGroupService(
+		zap.L(),
+		values.organisationRepository,
+		values.groupRepository,
+		values.organisationMemberRepository,
+		values.groupMemberRepository,
+		values.groupRoleRepository,
+		values.userRepository,
+	)
+	return values
+}
+
+// make a group
+func makeGroup(name string, organisationID, parentID *uuid.UUID) *models.Group {
+	return &models.Group{
+		BaseModel:      models.BaseModel{ID: uuid.New()},
+		Name:           name,
+		OrganisationID: organisationID,
+		ParentID:       parentID,
+	}
+}
+
+// make a page of groups
+func groupPage(groups ...*models.Group) *pagination.Page[models.Group] {
+	return pagination.NewPage(groups, 0, 10, uint(len(groups)))
+}
+
+func TestCreateOrganisationEmptyName(t *testing.T) {
+	values := setupTest(t)
+
+	_, err := values.service.CreateOrganisation("  ", "")
+	assert.Error(t, err)
+	values.organisationRepository.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestDeleteOrganisationWithGroups(t *testing.T) {
+	values := setupTest(t)
+	organisationID := uuid.New()
+	values.organisationRepository.On("Find", mock.Anything, nil, nil).
+		Return(pagination.NewPage([]*models.Organisation{{Name: "acme"}}, 0, 10, 1), nil)
+	values.groupRepository.On("Count", mock.Anything).Return(uint(2), nil)
+
+	err := values.service.DeleteOrganisation(organisationID)
+	assert.Error(t, err)
+	values.organisationRepository.AssertNotCalled(t, "Delete", mock.Anything)
+}
+
+func TestCreateSubgroupInheritsOrganisation(t *testing.T) {
+	values := setupTest(t)
+	organisationID := uuid.New()
+	parent := makeGroup("engineering", &organisationID, nil)
+	values.groupRepository.On("Find", mock.Anything, nil, nil).Return(groupPage(parent), nil)
+	values.groupRepository.On("Create", mock.Anything).Return(nil)
+
+	group, err := values.service.CreateGroup("backend", "", nil, &parent.ID)
+	require.NoError(t, err)
+	assert.Equal(t, &organisationID, group.OrganisationID)
+	assert.Equal(t, &parent.ID, group.ParentID)
+}
+
+func TestCreateSubgroupOtherOrganisation(t *testing.T) {
+	values := setupTest(t)
+	organisationID := uuid.New()
+	otherOrganisationID := uuid.New()
+	parent := makeGroup("engineering", &organisationID, nil)
+	values.groupRepository.On("Find", mock.Anything, nil, nil).Return(groupPage(parent), nil)
+
+	_, err := values.service.CreateGroup("backend", "", &otherOrganisationID, &parent.ID)
+	assert.Error(t, err)
+	values.groupRepository.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestSetGroupParentCycle(t *testing.T) {
+	values := setupTest(t)
+	root := makeGroup("root", nil, nil)
+	child := makeGroup("child", nil, &root.ID)
+	values.groupRepository.On("Find", mock.Anything, nil, nil).Return(groupPage(root), nil).Once()
+	values.groupRepository.On("Find", mock.Anything, nil, nil).Return(groupPage(child), nil).Once()
+	values.groupRepository.On("Find", mock.Anything, nil, nil).Return(groupPage(root), nil).Once()
+
+	err := values.service.SetGroupParent(root.ID, &child.ID)
+	assert.Error(t, err)
+	values.groupRepository.AssertNotCalled(t, "Save", mock.Anything)
+}
+
+func TestDeleteGroupWithSubgroups(t *testing.T) {
+	values := setupTest(t)
+	group := makeGroup("root", nil, nil)
+	values.groupRepository.On("Find", mock.Anything, nil, nil).Return(groupPage(group), nil)
+	values.groupRepository.On("Count", mock.Anything).Return(uint(1), nil)
+
+	err := values.service.DeleteGroup(group.ID)
+	assert.Error(t, err)
+	values.groupRepository.AssertNotCalled(t, "Delete", mock.Anything)
+}
+
+func TestAddGroupMemberInvalidRole(t *testing.T) {
+	values := setupTest(t)
+
+	err := values.service.AddGroupMember(uuid.New(), uuid.New(), "guest")
+	assert.Error(t, err)
+}
+
+func TestAddGroupMemberNotInOrganisation(t *testing.T) {
+	values := setupTest(t)
+	organisationID := uuid.New()
+	group := makeGroup("engineering", &organisationID, nil)
+	values.groupRepository.On("Find", mock.Anything, nil, nil).Return(groupPage(group), nil)
+	values.userRepository.On("Count", mock.Anything).Return(uint(1), nil)
+	values.organisationMemberRepository.On("Find", mock.Anything, nil, nil).
+		Return(pagination.NewPage([]*models.OrganisationMember{}, 0, 10, 0), nil)
+
+	err := values.service.AddGroupMember(group.ID, uuid.New(), models.MembershipRoleMember)
+	assert.Error(t, err)
+	values.groupMemberRepository.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestAddGroupMember(t *testing.T) {
+	values := setupTest(t)
+	group := makeGroup("engineering", nil, nil)
+	userID := uuid.New()
+	values.groupRepository.On("Find", mock.Anything, nil, nil).Return(groupPage(group), nil)
+	values.userRepository.On("Count", mock.Anything).Return(uint(1), nil)
+	values.groupMemberRepository.On("Find", mock.Anything, nil, nil).
+		Return(pagination.NewPage([]*models.GroupMember{}, 0, 10, 0), nil)
+	values.groupMemberRepository.On("Create", &models.GroupMember{
+		GroupID: group.ID,
+		UserID:  userID,
+		Role:    models.MembershipRoleAdmin,
+	}).Return(nil)
+
+	err := values.service.AddGroupMember(group.ID, userID, models.MembershipRoleAdmin)
+	assert.NoError(t, err)
+}
+
+func TestAddGroupMemberUnknownUser(t *testing.T) {
+	values := setupTest(t)
+	group := makeGroup("engineering", nil, nil)
+	values.groupRepository.On("Find", mock.Anything, nil, nil).Return(groupPage(group), nil)
+	values.userRepository.On("Count", mock.Anything).Return(uint(0), nil)
+
+	err := values.service.AddGroupMember(group.ID, uuid.New(), models.MembershipRoleMember)
+	assert.Error(t, err)
+}
+
+func TestGetUserGroupIDsIncludesAncestors(t *testing.T) {
+	values := setupTest(t)
+	userID := uuid.New()
+	root := makeGroup("root", nil, nil)
+	child := makeGroup("child", nil, &root.ID)
+	values.groupMemberRepository.On("Find", mock.Anything, nil, nil).
+		Return(pagination.NewPage([]*models.GroupMember{{GroupID: child.ID, UserID: userID}}, 0, 10, 1), nil)
+	values.groupRepository.On("Find", mock.Anything, nil, nil).Return(groupPage(child), nil).Once()
+	values.groupRepository.On("Find", mock.Anything, nil, nil).Return(groupPage(root), nil).Once()
+
+	groupIDs, err := values.service.GetUserGroupIDs(userID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{child.ID.String(), root.ID.String()}, groupIDs)
+}
+
+func TestGetUserGroupIDsDatabaseError(t *testing.T) {
+	values := setupTest(t)
+	values.groupMemberRepository.On("Find", mock.Anything, nil, nil).Return(nil, httperrors.AnError)
+
+	_, err := values.service.GetUserGroupIDs(uuid.New())
+	assert.Equal(t, httperrors.AnError, err)
+}
+
+func TestGetGroupRoleFromAncestor(t *testing.T) {
+	values := setupTest(t)
+	userID := uuid.New()
+	root := makeGroup("root", nil, nil)
+	child := makeGroup("child", nil, &root.ID)
+	values.groupRepository.On("Find", mock.Anything, nil, nil).Return(groupPage(child), nil).Once()
+	values.groupRepository.On("Find", mock.Anything, nil, nil).Return(groupPage(root), nil).Once()
+	values.groupMemberRepository.On("Find", mock.Anything, nil, nil).
+		Return(pagination.NewPage([]*models.GroupMember{
+			{GroupID: child.ID, UserID: userID, Role: models.MembershipRoleMember},
+			{GroupID: root.ID, UserID: userID, Role: models.MembershipRoleOwner},
+		}, 0, 10, 2), nil)
+
+	role, err := values.service.GetGroupRole(userID, child.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.MembershipRoleOwner, role)
+}
+
+func TestGetGroupRoleFromOrganisation(t *testing.T) {
+	values := setupTest(t)
+	userID := uuid.New()
+	organisationID := uuid.New()
+	group := makeGroup("engineering", &organisationID, nil)
+	values.groupRepository.On("Find", mock.Anything, nil, nil).Return(groupPage(group), nil)
+	values.groupMemberRepository.On("Find", mock.Anything, nil, nil).
+		Return(pagination.NewPage([]*models.GroupMember{}, 0, 10, 0), nil)
+	values.organisationMemberRepository.On("Find", mock.Anything, nil, nil).
+		Return(pagination.NewPage([]*models.OrganisationMember{
+			{OrganisationID: organisationID, UserID: userID, Role: models.MembershipRoleAdmin},
+		}, 0, 10, 1), nil)
+
+	role, err := values.service.GetGroupRole(userID, group.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.MembershipRoleAdmin, role)
+}
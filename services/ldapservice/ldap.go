@@ -0,0 +1,357 @@
+package ldapservice
+
+import (
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/ditrit/badaas/configuration"
+	"github.com/ditrit/badaas/httperrors"
+	"github.com/ditrit/badaas/persistence/models"
+	"github.com/ditrit/badaas/persistence/models/dto"
+	"github.com/ditrit/badaas/persistence/repository"
+	"github.com/ditrit/badaas/services/auth/protocols/ldap"
+	"github.com/ditrit/badaas/services/rbacservice"
+	"github.com/ditrit/badaas/services/userservice"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Errors
+var (
+	HERRDirectoryUnavailable = httperrors.NewHTTPError(http.StatusServiceUnavailable, "directory unavailable",
+		"the directory can't authenticate the user, please retry later", nil, false)
+	HERRAccountNotLinked = httperrors.NewForbiddenError("account not linked",
+		"no account is linked to this directory user")
+)
+
+// LDAPService authenticate the users with the local passwords or with the LDAP directory
+type LDAPService interface {
+	// Return the user if the email and the password are correct, return userservice.HERRWrongPassword if not
+	//
+	// The users linked to the directory are authenticated by the directory, the other local users by their local password.
+	// The directory users unknown to badaas are created if the configuration allows it.
+	GetUser(userLoginDTO dto.UserLoginDTO) (*models.User, httperrors.HTTPError)
+}
+
+// Check interface compliance
+var _ LDAPService = (*ldapServiceImpl)(nil)
+
+// LDAPService implementation
+type ldapServiceImpl struct {
+	logger                 *zap.Logger
+	ldapConfiguration      configuration.LDAPConfiguration
+	ldapIdentityRepository repository.CRUDRepository[models.LDAPIdentity, uuid.UUID]
+	userService            userservice.UserService
+	rbacService            rbacservice.RBACService
+
+	// the directory, created with the certificates on the first use
+	mutex     sync.Mutex
+	directory *ldap.Directory
+}
+
+// LDAPService constructor
+func NewLDAPService(
+	logger *zap.Logger,
+	ldapConfiguration configuration.LDAPConfiguration,
+	ldapIdentityRepository repository.CRUDRepository[models.LDAPIdentity, uuid.UUID],
+	userService userservice.UserService,
+	rbacService rbacservice.RBACService,
+) LDAPService {
+	return &ldapServiceImpl{
+		logger:                 logger,
+		ldapConfiguration:      ldapConfiguration,
+		ldapIdentityRepository: ldapIdentityRepository,
+		userService:            userService,
+		rbacService:            rbacService,
+	}
+}
+
+// Return the user if the email and the password are correct, return userservice.HERRWrongPassword if not
+//
+// The users linked to the directory are authenticated by the directory, the other local users by their local password.
+// The directory users unknown to badaas are created if the configuration allows it.
+func (ldapService *ldapServiceImpl) GetUser(userLoginDTO dto.UserLoginDTO) (*models.User, httperrors.HTTPError) {
+	if !ldapService.ldapConfiguration.IsEnabled() {
+		return ldapService.userService.GetUser(userLoginDTO)
+	}
+	user, herr := ldapService.userService.GetUserByEmail(userLoginDTO.Email)
+	if herr != nil {
+		if impl, ok := herr.(*httperrors.HTTPErrorImpl); !ok || impl.Status != http.StatusNotFound {
+			return nil, herr
+		}
+	}
+	var identity *models.LDAPIdentity
+	if user != nil {
+		identity, herr = ldapService.getIdentity(squirrel.Eq{"user_id": user.ID.String()})
+		if herr != nil {
+			return nil, herr
+		}
+		if identity == nil {
+			// a local account, the directory is not asked
+			return ldapService.userService.GetUser(userLoginDTO)
+		}
+	}
+
+	entry, herr := ldapService.authenticate(userLoginDTO)
+	if herr != nil {
+		return nil, herr
+	}
+	if identity == nil {
+		// the email of the directory user may have changed since its last login
+		identity, herr = ldapService.getIdentity(squirrel.Eq{"dn": entry.DN})
+		if herr != nil {
+			return nil, herr
+		}
+	}
+	if identity == nil {
+		user, identity, herr = ldapService.createUser(entry, userLoginDTO.Email)
+	} else {
+		user, herr = ldapService.updateUser(identity, entry)
+	}
+	if herr != nil {
+		return nil, herr
+	}
+
+	herr = ldapService.syncRoles(user, entry)
+	if herr != nil {
+		return nil, herr
+	}
+	herr = ldapService.userService.CheckCanLogIn(user)
+	if herr != nil {
+		return nil, herr
+	}
+	return user, nil
+}
+
+// Authenticate the login with the directory
+func (ldapService *ldapServiceImpl) authenticate(userLoginDTO dto.UserLoginDTO) (*ldap.Entry, httperrors.HTTPError) {
+	directory, herr := ldapService.getDirectory()
+	if herr != nil {
+		return nil, herr
+	}
+	entry, err := directory.Authenticate(userLoginDTO.Email, userLoginDTO.Password)
+	if errors.Is(err, ldap.ErrInvalidCredentials) {
+		return nil, userservice.HERRWrongPassword
+	}
+	if err != nil {
+		ldapService.logger.Error("The directory failed to authenticate a user", zap.Error(err))
+		return nil, HERRDirectoryUnavailable
+	}
+	return entry, nil
+}
+
+// Return the identity matching the condition, or nil
+func (ldapService *ldapServiceImpl) getIdentity(condition squirrel.Eq) (*models.LDAPIdentity, httperrors.HTTPError) {
+	identities, herr := ldapService.ldapIdentityRepository.Find(condition, nil, nil)
+	if herr != nil {
+		return nil, herr
+	}
+	if !identities.HasContent {
+		return nil, nil
+	}
+	return identities.Ressources[0], nil
+}
+
+// Create the shadow user of the directory entry, the sessions and the roles belong to it
+//
+// The user can only log in with the directory until it resets its password.
+func (ldapService *ldapServiceImpl) createUser(entry *ldap.Entry, login string) (*models.User, *models.LDAPIdentity, httperrors.HTTPError) {
+	if !ldapService.ldapConfiguration.GetProvisioning() {
+		ldapService.logger.Info("Refused an unknown directory user", zap.String("dn", entry.DN))
+		return nil, nil, HERRAccountNotLinked
+	}
+	email := entry.Email
+	if email == "" {
+		email = login
+	}
+	username := entry.Username
+	if username == "" {
+		username = strings.SplitN(email, "@", 2)[0]
+	}
+	password, err := randomPassword()
+	if err != nil {
+		return nil, nil, httperrors.NewInternalServerError("password error", "failed to generate a password", err)
+	}
+	user, err := ldapService.userService.NewSystemUser(username, email, password)
+	if err != nil {
+		var herr httperrors.HTTPError
+		if errors.As(err, &herr) {
+			return nil, nil, herr
+		}
+		return nil, nil, httperrors.NewHTTPError(http.StatusBadRequest, "invalid directory user", err.Error(), nil, false)
+	}
+	// the directory is the reference of the organisation, its emails are trusted
+	herr := ldapService.userService.MarkEmailVerified(user.ID)
+	if herr != nil {
+		return nil, nil, herr
+	}
+	user.EmailVerified = true
+	identity := &models.LDAPIdentity{UserID: user.ID, DN: entry.DN}
+	herr = ldapService.ldapIdentityRepository.Create(identity)
+	if herr != nil {
+		return nil, nil, herr
+	}
+	ldapService.logger.Info("Created a directory user", zap.String("userID", user.ID.String()))
+	return user, identity, nil
+}
+
+// Update the user and its identity with the directory entry
+func (ldapService *ldapServiceImpl) updateUser(identity *models.LDAPIdentity, entry *ldap.Entry) (*models.User, httperrors.HTTPError) {
+	if identity.DN != entry.DN {
+		identity.DN = entry.DN
+		herr := ldapService.ldapIdentityRepository.Save(identity)
+		if herr != nil {
+			return nil, herr
+		}
+	}
+	user, herr := ldapService.userService.GetUserByID(identity.UserID)
+	if herr != nil {
+		return nil, herr
+	}
+	username := ""
+	if entry.Username != "" && entry.Username != user.Username {
+		username = entry.Username
+	}
+	email := ""
+	if entry.Email != "" && !strings.EqualFold(entry.Email, user.Email) {
+		email = entry.Email
+	}
+	if username == "" && email == "" {
+		return user, nil
+	}
+	user, herr = ldapService.userService.UpdateUser(user.ID, username, email)
+	if herr != nil || email == "" {
+		return user, herr
+	}
+	// the new email comes from the directory, it is trusted like the first one
+	herr = ldapService.userService.MarkEmailVerified(user.ID)
+	if herr != nil {
+		return nil, herr
+	}
+	user.EmailVerified = true
+	return user, nil
+}
+
+// Give the user the roles of its groups and remove the other mapped roles
+//
+// The roles which are not mapped to a group are left untouched, they are managed in badaas.
+func (ldapService *ldapServiceImpl) syncRoles(user *models.User, entry *ldap.Entry) httperrors.HTTPError {
+	groupRoles := ldapService.ldapConfiguration.GetGroupRoles()
+	if len(groupRoles) == 0 {
+		return nil
+	}
+	roles, herr := ldapService.rbacService.GetRoles()
+	if herr != nil {
+		return herr
+	}
+	rolesByName := map[string]*models.Role{}
+	for _, role := range roles {
+		rolesByName[role.Name] = role
+	}
+
+	// a role mapped to several groups is kept if the user is member of any of them
+	memberRoles := map[string]bool{}
+	mappedRoles := map[string]bool{}
+	for _, mapping := range groupRoles {
+		isMember := false
+		for _, group := range entry.Groups {
+			if strings.EqualFold(group, mapping.Group) {
+				isMember = true
+				break
+			}
+		}
+		for _, roleName := range mapping.Roles {
+			mappedRoles[roleName] = true
+			if isMember {
+				memberRoles[roleName] = true
+			}
+		}
+	}
+
+	for roleName := range mappedRoles {
+		role, ok := rolesByName[roleName]
+		if !ok {
+			ldapService.logger.Warn("A role mapped to a directory group doesn't exist", zap.String("role", roleName))
+			continue
+		}
+		if memberRoles[roleName] {
+			herr = ldapService.rbacService.AssignRole(user.ID, role.ID)
+			if herr != nil {
+				return herr
+			}
+			continue
+		}
+		herr = ldapService.rbacService.UnassignRole(user.ID, role.ID)
+		if herr != nil {
+			if impl, ok := herr.(*httperrors.HTTPErrorImpl); !ok || impl.Status != http.StatusNotFound {
+				return herr
+			}
+		}
+	}
+	return nil
+}
+
+// Return the directory described by the configuration, its certificates are loaded on the first call
+func (ldapService *ldapServiceImpl) getDirectory() (*ldap.Directory, httperrors.HTTPError) {
+	ldapService.mutex.Lock()
+	defer ldapService.mutex.Unlock()
+	if ldapService.directory != nil {
+		return ldapService.directory, nil
+	}
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if path := ldapService.ldapConfiguration.GetCACertificate(); path != "" {
+		rootCAs, err := loadCertPool(path)
+		if err != nil {
+			return nil, httperrors.NewInternalServerError("ldap error", "failed to load the certificates of the directory", err)
+		}
+		tlsConfig.RootCAs = rootCAs
+	}
+	ldapService.directory = ldap.NewDirectory(ldap.Config{
+		URL:               ldapService.ldapConfiguration.GetURL(),
+		StartTLS:          ldapService.ldapConfiguration.GetStartTLS(),
+		TLSConfig:         tlsConfig,
+		Timeout:           ldapService.ldapConfiguration.GetTimeout(),
+		BindDN:            ldapService.ldapConfiguration.GetBindDN(),
+		BindPassword:      ldapService.ldapConfiguration.GetBindPassword(),
+		BaseDN:            ldapService.ldapConfiguration.GetBaseDN(),
+		UserFilter:        ldapService.ldapConfiguration.GetUserFilter(),
+		EmailAttribute:    ldapService.ldapConfiguration.GetEmailAttribute(),
+		UsernameAttribute: ldapService.ldapConfiguration.GetUsernameAttribute(),
+		GroupsAttribute:   ldapService.ldapConfiguration.GetGroupsAttribute(),
+		GroupBaseDN:       ldapService.ldapConfiguration.GetGroupBaseDN(),
+		GroupFilter:       ldapService.ldapConfiguration.GetGroupFilter(),
+	})
+	return ldapService.directory, nil
+}
+
+// Load the PEM encoded certificates of the file
+func loadCertPool(path string) (*x509.CertPool, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(content) {
+		return nil, fmt.Errorf("no certificate found in %q", path)
+	}
+	return pool, nil
+}
+
+// Return a random password nobody knows
+func randomPassword() (string, error) {
+	b := make([]byte, 32)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
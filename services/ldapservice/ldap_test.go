@@ -0,0 +1,246 @@
+package ldapservice_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/ditrit/badaas/configuration"
+	"github.com/ditrit/badaas/httperrors"
+	mocksConfiguration "github.com/ditrit/badaas/mocks/configuration"
+	mocksRepository "github.com/ditrit/badaas/mocks/persistence/repository"
+	mocksRBACService "github.com/ditrit/badaas/mocks/services/rbacservice"
+	mocksUserService "github.com/ditrit/badaas/mocks/services/userservice"
+	"github.com/ditrit/badaas/persistence/models"
+	"github.com/ditrit/badaas/persistence/models/dto"
+	"github.com/ditrit/badaas/persistence/pagination"
+	"github.com/ditrit/badaas/services/auth/protocols/ldap/ldaptest"
+	"github.com/ditrit/badaas/services/ldapservice"
+	"github.com/ditrit/badaas/services/userservice"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const (
+	serviceDN = "cn=badaas,ou=services,dc=example,dc=com"
+	bobDN     = "uid=bob,ou=people,dc=example,dc=com"
+	adminsDN  = "cn=admins,ou=groups,dc=example,dc=com"
+)
+
+var notFound = httperrors.NewErrorNotFound("user", "no user found")
+
+type testSetup struct {
+	server             *ldaptest.Server
+	ldapConfiguration  *mocksConfiguration.LDAPConfiguration
+	identityRepository *mocksRepository.CRUDRepository[models.LDAPIdentity, uuid.UUID]
+	userService        *mocksUserService.UserService
+	rbacService        *mocksRBACService.RBACService
+	service            ldapservice.LDAPService
+}
+
+func setupTest(t *testing.T, provisioning bool, groupRoles ...configuration.LDAPGroupRoles) *testSetup {
+	server, err := ldaptest.NewServer(
+		ldaptest.Entry{DN: serviceDN, Password: "service-password"},
+		ldaptest.Entry{
+			DN:       bobDN,
+			Password: "bob-password",
+			Attributes: map[string][]string{
+				"mail":     {"bob@example.com"},
+				"uid":      {"bob"},
+				"memberOf": {adminsDN},
+			},
+		},
+	)
+	require.NoError(t, err)
+	t.Cleanup(server.Close)
+
+	ldapConfiguration := mocksConfiguration.NewLDAPConfiguration(t)
+	ldapConfiguration.On("IsEnabled").Return(true).Maybe()
+	ldapConfiguration.On("GetURL").Return(server.URL).Maybe()
+	ldapConfiguration.On("GetStartTLS").Return(false).Maybe()
+	ldapConfiguration.On("GetCACertificate").Return("").Maybe()
+	ldapConfiguration.On("GetTimeout").Return(5 * time.Second).Maybe()
+	ldapConfiguration.On("GetBindDN").Return(serviceDN).Maybe()
+	ldapConfiguration.On("GetBindPassword").Return("service-password").Maybe()
+	ldapConfiguration.On("GetBaseDN").Return("ou=people,dc=example,dc=com").Maybe()
+	ldapConfiguration.On("GetUserFilter").Return("(mail=%s)").Maybe()
+	ldapConfiguration.On("GetEmailAttribute").Return("mail").Maybe()
+	ldapConfiguration.On("GetUsernameAttribute").Return("uid").Maybe()
+	ldapConfiguration.On("GetGroupsAttribute").Return("memberOf").Maybe()
+	ldapConfiguration.On("GetGroupBaseDN").Return("").Maybe()
+	ldapConfiguration.On("GetGroupFilter").Return("").Maybe()
+	ldapConfiguration.On("GetGroupRoles").Return(groupRoles).Maybe()
+	ldapConfiguration.On("GetProvisioning").Return(provisioning).Maybe()
+
+	setup := &testSetup{
+		server:             server,
+		ldapConfiguration:  ldapConfiguration,
+		identityRepository: mocksRepository.NewCRUDRepository[models.LDAPIdentity, uuid.UUID](t),
+		userService:        mocksUserService.NewUserService(t),
+		rbacService:        mocksRBACService.NewRBACService(t),
+	}
+	setup.service = ldapservice.NewLDAPService(zap.NewNop(), ldapConfiguration,
+		setup.identityRepository, setup.userService, setup.rbacService)
+	return setup
+}
+
+func (setup *testSetup) onFindIdentity(condition squirrel.Eq, identities ...*models.LDAPIdentity) {
+	setup.identityRepository.On("Find", condition, nil, nil).
+		Return(pagination.NewPage(identities, 1, 10, uint(len(identities))), nil)
+}
+
+func TestGetUserDisabled(t *testing.T) {
+	ldapConfiguration := mocksConfiguration.NewLDAPConfiguration(t)
+	ldapConfiguration.On("IsEnabled").Return(false)
+	userService := mocksUserService.NewUserService(t)
+	login := dto.UserLoginDTO{Email: "bob@example.com", Password: "local-password"}
+	user := &models.User{Email: "bob@example.com"}
+	userService.On("GetUser", login).Return(user, nil)
+	service := ldapservice.NewLDAPService(zap.NewNop(), ldapConfiguration,
+		mocksRepository.NewCRUDRepository[models.LDAPIdentity, uuid.UUID](t),
+		userService, mocksRBACService.NewRBACService(t))
+
+	loggedUser, herr := service.GetUser(login)
+	require.Nil(t, herr)
+	assert.Equal(t, user, loggedUser)
+}
+
+func TestGetUserLocalAccount(t *testing.T) {
+	setup := setupTest(t, true)
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Email: "bob@example.com"}
+	login := dto.UserLoginDTO{Email: "bob@example.com", Password: "local-password"}
+	setup.userService.On("GetUserByEmail", "bob@example.com").Return(user, nil)
+	setup.onFindIdentity(squirrel.Eq{"user_id": user.ID.String()})
+	setup.userService.On("GetUser", login).Return(user, nil)
+
+	loggedUser, herr := setup.service.GetUser(login)
+	require.Nil(t, herr)
+	assert.Equal(t, user, loggedUser)
+	// the directory is not asked for the local accounts
+	assert.Empty(t, setup.server.Binds())
+}
+
+func TestGetUserLinkedAccount(t *testing.T) {
+	setup := setupTest(t, false)
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Username: "bob", Email: "bob@example.com"}
+	setup.userService.On("GetUserByEmail", "bob@example.com").Return(user, nil)
+	setup.onFindIdentity(squirrel.Eq{"user_id": user.ID.String()}, &models.LDAPIdentity{UserID: user.ID, DN: bobDN})
+	setup.userService.On("GetUserByID", user.ID).Return(user, nil)
+	setup.userService.On("CheckCanLogIn", user).Return(nil)
+
+	loggedUser, herr := setup.service.GetUser(dto.UserLoginDTO{Email: "bob@example.com", Password: "bob-password"})
+	require.Nil(t, herr)
+	assert.Equal(t, user, loggedUser)
+	assert.Equal(t, []string{serviceDN, bobDN}, setup.server.Binds())
+}
+
+func TestGetUserLinkedAccountWrongPassword(t *testing.T) {
+	setup := setupTest(t, false)
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Username: "bob", Email: "bob@example.com"}
+	setup.userService.On("GetUserByEmail", "bob@example.com").Return(user, nil)
+	setup.onFindIdentity(squirrel.Eq{"user_id": user.ID.String()}, &models.LDAPIdentity{UserID: user.ID, DN: bobDN})
+
+	// the local password of a linked account is not accepted
+	_, herr := setup.service.GetUser(dto.UserLoginDTO{Email: "bob@example.com", Password: "local-password"})
+	assert.Equal(t, userservice.HERRWrongPassword, herr)
+}
+
+func TestGetUserUpdatesUsername(t *testing.T) {
+	setup := setupTest(t, false)
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Username: "robert", Email: "bob@example.com"}
+	renamedUser := &models.User{BaseModel: user.BaseModel, Username: "bob", Email: "bob@example.com"}
+	setup.userService.On("GetUserByEmail", "bob@example.com").Return(user, nil)
+	setup.onFindIdentity(squirrel.Eq{"user_id": user.ID.String()}, &models.LDAPIdentity{UserID: user.ID, DN: bobDN})
+	setup.userService.On("GetUserByID", user.ID).Return(user, nil)
+	setup.userService.On("UpdateUser", user.ID, "bob", "").Return(renamedUser, nil)
+	setup.userService.On("CheckCanLogIn", renamedUser).Return(nil)
+
+	loggedUser, herr := setup.service.GetUser(dto.UserLoginDTO{Email: "bob@example.com", Password: "bob-password"})
+	require.Nil(t, herr)
+	assert.Equal(t, "bob", loggedUser.Username)
+}
+
+func TestGetUserProvisioning(t *testing.T) {
+	setup := setupTest(t, true)
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Username: "bob", Email: "bob@example.com"}
+	setup.userService.On("GetUserByEmail", "bob@example.com").Return(nil, notFound)
+	setup.onFindIdentity(squirrel.Eq{"dn": bobDN})
+	setup.userService.On("NewSystemUser", "bob", "bob@example.com", mock.AnythingOfType("string")).Return(user, nil)
+	setup.userService.On("MarkEmailVerified", user.ID).Return(nil)
+	setup.identityRepository.On("Create", &models.LDAPIdentity{UserID: user.ID, DN: bobDN}).Return(nil)
+	setup.userService.On("CheckCanLogIn", user).Return(nil)
+
+	loggedUser, herr := setup.service.GetUser(dto.UserLoginDTO{Email: "bob@example.com", Password: "bob-password"})
+	require.Nil(t, herr)
+	assert.Equal(t, user, loggedUser)
+	assert.True(t, loggedUser.EmailVerified)
+}
+
+func TestGetUserWithoutProvisioning(t *testing.T) {
+	setup := setupTest(t, false)
+	setup.userService.On("GetUserByEmail", "bob@example.com").Return(nil, notFound)
+	setup.onFindIdentity(squirrel.Eq{"dn": bobDN})
+
+	_, herr := setup.service.GetUser(dto.UserLoginDTO{Email: "bob@example.com", Password: "bob-password"})
+	assert.Equal(t, ldapservice.HERRAccountNotLinked, herr)
+}
+
+func TestGetUserEmailChangedInDirectory(t *testing.T) {
+	setup := setupTest(t, true)
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Username: "bob", Email: "old@example.com"}
+	updatedUser := &models.User{BaseModel: user.BaseModel, Username: "bob", Email: "bob@example.com"}
+	setup.userService.On("GetUserByEmail", "bob@example.com").Return(nil, notFound)
+	setup.onFindIdentity(squirrel.Eq{"dn": bobDN}, &models.LDAPIdentity{UserID: user.ID, DN: bobDN})
+	setup.userService.On("GetUserByID", user.ID).Return(user, nil)
+	setup.userService.On("UpdateUser", user.ID, "", "bob@example.com").Return(updatedUser, nil)
+	setup.userService.On("MarkEmailVerified", user.ID).Return(nil)
+	setup.userService.On("CheckCanLogIn", updatedUser).Return(nil)
+
+	loggedUser, herr := setup.service.GetUser(dto.UserLoginDTO{Email: "bob@example.com", Password: "bob-password"})
+	require.Nil(t, herr)
+	assert.Equal(t, updatedUser, loggedUser)
+	assert.True(t, loggedUser.EmailVerified)
+}
+
+func TestGetUserUnknown(t *testing.T) {
+	setup := setupTest(t, true)
+	setup.userService.On("GetUserByEmail", "alice@example.com").Return(nil, notFound)
+
+	_, herr := setup.service.GetUser(dto.UserLoginDTO{Email: "alice@example.com", Password: "bob-password"})
+	assert.Equal(t, userservice.HERRWrongPassword, herr)
+}
+
+func TestGetUserDirectoryUnavailable(t *testing.T) {
+	setup := setupTest(t, true)
+	setup.server.Close()
+	setup.userService.On("GetUserByEmail", "bob@example.com").Return(nil, notFound)
+
+	_, herr := setup.service.GetUser(dto.UserLoginDTO{Email: "bob@example.com", Password: "bob-password"})
+	assert.Equal(t, ldapservice.HERRDirectoryUnavailable, herr)
+}
+
+func TestGetUserSyncsRoles(t *testing.T) {
+	setup := setupTest(t, false,
+		configuration.LDAPGroupRoles{Group: adminsDN, Roles: []string{"admin"}},
+		configuration.LDAPGroupRoles{Group: "cn=auditors,ou=groups,dc=example,dc=com", Roles: []string{"auditor", "missing"}},
+	)
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Username: "bob", Email: "bob@example.com"}
+	adminRole := &models.Role{BaseModel: models.BaseModel{ID: uuid.New()}, Name: "admin"}
+	auditorRole := &models.Role{BaseModel: models.BaseModel{ID: uuid.New()}, Name: "auditor"}
+	otherRole := &models.Role{BaseModel: models.BaseModel{ID: uuid.New()}, Name: "other"}
+	setup.userService.On("GetUserByEmail", "bob@example.com").Return(user, nil)
+	setup.onFindIdentity(squirrel.Eq{"user_id": user.ID.String()}, &models.LDAPIdentity{UserID: user.ID, DN: bobDN})
+	setup.userService.On("GetUserByID", user.ID).Return(user, nil)
+	setup.rbacService.On("GetRoles").Return([]*models.Role{adminRole, auditorRole, otherRole}, nil)
+	setup.rbacService.On("AssignRole", user.ID, adminRole.ID).Return(nil)
+	setup.rbacService.On("UnassignRole", user.ID, auditorRole.ID).Return(notFound)
+	setup.userService.On("CheckCanLogIn", user).Return(nil)
+
+	_, herr := setup.service.GetUser(dto.UserLoginDTO{Email: "bob@example.com", Password: "bob-password"})
+	require.Nil(t, herr)
+	// the roles which are not mapped are left untouched
+	setup.rbacService.AssertNotCalled(t, "UnassignRole", user.ID, otherRole.ID)
+}
@@ -0,0 +1,292 @@
+package loginthrottlingservice
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/ditrit/badaas/configuration"
+	"github.com/ditrit/badaas/httperrors"
+	"github.com/ditrit/badaas/persistence/models"
+	"github.com/ditrit/badaas/persistence/repository"
+	"github.com/google/uuid"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+// Errors
+var (
+	HERRTooManyAttempts = httperrors.NewHTTPError(http.StatusTooManyRequests, "too many attempts",
+		"too many failed login attempts, please retry later", nil, false)
+)
+
+// LoginThrottlingService slow down and lock the brute-force attacks on the login
+//
+// The failed attempts are counted per account and per ip address. After each failure the next attempt
+// is delayed a bit more, and the account or the ip address is locked once its maximum number of attempts is reached.
+// The unknown emails are throttled like the existing accounts, so that the responses don't tell if an account exists.
+type LoginThrottlingService interface {
+	// Return the duration to wait and an error if a login attempt on the email from the ip must be refused for now
+	Check(email, ip string) (time.Duration, httperrors.HTTPError)
+	// Count a failed login attempt on the email from the ip, and lock them if needed
+	RecordFailure(email, ip string) httperrors.HTTPError
+	// Forget the failed attempts on the email and unlock it
+	ResetAccount(email string) httperrors.HTTPError
+	// Delete the throttles whose failed attempts are out of the window and whose lockout is over
+	PurgeStale() httperrors.HTTPError
+}
+
+// Check interface compliance
+var _ LoginThrottlingService = (*loginThrottlingServiceImpl)(nil)
+
+// LoginThrottlingService implementation
+type loginThrottlingServiceImpl struct {
+	logger                       *zap.Logger
+	loginThrottlingConfiguration configuration.LoginThrottlingConfiguration
+	loginThrottleRepository      repository.CRUDRepository[models.LoginThrottle, uuid.UUID]
+}
+
+// LoginThrottlingService constructor
+//
+// The stale throttles are purged in the background between the start and the stop of the application,
+// so that the failures on unknown emails don't fill the table.
+func NewLoginThrottlingService(
+	lc fx.Lifecycle,
+	logger *zap.Logger,
+	loginThrottlingConfiguration configuration.LoginThrottlingConfiguration,
+	loginThrottleRepository repository.CRUDRepository[models.LoginThrottle, uuid.UUID],
+) LoginThrottlingService {
+	loginThrottlingService := &loginThrottlingServiceImpl{
+		logger:                       logger,
+		loginThrottlingConfiguration: loginThrottlingConfiguration,
+		loginThrottleRepository:      loginThrottleRepository,
+	}
+	stop := make(chan struct{})
+	stopped := make(chan struct{})
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			go loginThrottlingService.run(stop, stopped)
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			close(stop)
+			select {
+			case <-stopped:
+			case <-ctx.Done():
+			}
+			return nil
+		},
+	})
+	return loginThrottlingService
+}
+
+// Purge the stale throttles once per window, until stop is closed
+func (loginThrottlingService *loginThrottlingServiceImpl) run(stop <-chan struct{}, stopped chan<- struct{}) {
+	defer close(stopped)
+	ticker := time.NewTicker(loginThrottlingService.loginThrottlingConfiguration.GetWindow())
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+		herr := loginThrottlingService.PurgeStale()
+		if herr != nil {
+			loginThrottlingService.logger.Error("Failed to purge the stale login throttles", zap.Error(herr))
+		}
+	}
+}
+
+// Return the duration to wait and an error if a login attempt on the email from the ip must be refused for now
+func (loginThrottlingService *loginThrottlingServiceImpl) Check(email, ip string) (time.Duration, httperrors.HTTPError) {
+	if !loginThrottlingService.loginThrottlingConfiguration.GetEnabled() {
+		return 0, nil
+	}
+	now := time.Now()
+	var retryAfter time.Duration
+	for _, key := range throttleKeys(email, ip) {
+		throttle, herr := loginThrottlingService.getThrottle(key)
+		if herr != nil {
+			return 0, herr
+		}
+		if throttle == nil {
+			continue
+		}
+		wait := loginThrottlingService.waitFor(throttle, now)
+		if wait > retryAfter {
+			retryAfter = wait
+		}
+	}
+	if retryAfter > 0 {
+		return retryAfter, HERRTooManyAttempts
+	}
+	return 0, nil
+}
+
+// Count a failed login attempt on the email from the ip, and lock them if needed
+func (loginThrottlingService *loginThrottlingServiceImpl) RecordFailure(email, ip string) httperrors.HTTPError {
+	if !loginThrottlingService.loginThrottlingConfiguration.GetEnabled() {
+		return nil
+	}
+	herr := loginThrottlingService.recordFailure(accountKey(email),
+		loginThrottlingService.loginThrottlingConfiguration.GetAccountMaxAttempts())
+	if herr != nil {
+		return herr
+	}
+	if ip == "" {
+		return nil
+	}
+	return loginThrottlingService.recordFailure(ipKey(ip),
+		loginThrottlingService.loginThrottlingConfiguration.GetIPMaxAttempts())
+}
+
+// Forget the failed attempts on the email and unlock it
+func (loginThrottlingService *loginThrottlingServiceImpl) ResetAccount(email string) httperrors.HTTPError {
+	_, herr := loginThrottlingService.loginThrottleRepository.UpdateColumns(
+		squirrel.And{
+			squirrel.Eq{"key": accountKey(email)},
+			squirrel.Or{squirrel.Gt{"failed_attempts": 0}, squirrel.NotEq{"locked_until": time.Time{}}},
+		},
+		map[string]any{"failed_attempts": 0, "locked_until": time.Time{}},
+	)
+	return herr
+}
+
+// Delete the throttles whose failed attempts are out of the window and whose lockout is over
+//
+// The rows are deleted permanently, a soft deleted row would still hold the unique key of its throttle.
+func (loginThrottlingService *loginThrottlingServiceImpl) PurgeStale() httperrors.HTTPError {
+	now := time.Now()
+	purged, herr := loginThrottlingService.loginThrottleRepository.DeleteUnscoped(squirrel.And{
+		squirrel.Lt{"last_failure_at": now.Add(-loginThrottlingService.loginThrottlingConfiguration.GetWindow())},
+		squirrel.LtOrEq{"locked_until": now},
+	})
+	if herr != nil {
+		return herr
+	}
+	if purged > 0 {
+		loginThrottlingService.logger.Debug("Purged the stale login throttles", zap.Uint("count", purged))
+	}
+	return nil
+}
+
+// Increment the failed attempts of the key and lock it when maxAttempts is reached, 0 means never locked
+//
+// The failed attempts are incremented by the database, so that the concurrent failures are all counted
+// and the lock is decided on the incremented row.
+func (loginThrottlingService *loginThrottlingServiceImpl) recordFailure(key string, maxAttempts uint) httperrors.HTTPError {
+	now := time.Now()
+	// the failed attempts of a stale throttle start again from the first, like in isStale
+	staleSQL, staleArgs, err := squirrel.Or{
+		squirrel.Eq{"login_throttles.failed_attempts": 0},
+		squirrel.And{
+			squirrel.NotEq{"login_throttles.locked_until": time.Time{}},
+			squirrel.LtOrEq{"login_throttles.locked_until": now},
+		},
+		squirrel.Lt{
+			"login_throttles.last_failure_at": now.Add(-loginThrottlingService.loginThrottlingConfiguration.GetWindow()),
+		},
+	}.ToSql()
+	if err != nil {
+		return httperrors.NewInternalServerError("login throttling error", "failed to build the throttle update", err)
+	}
+	throttle := &models.LoginThrottle{Key: key, FailedAttempts: 1, LastFailureAt: now}
+	herr := loginThrottlingService.loginThrottleRepository.Upsert(throttle, []string{"key"}, map[string]any{
+		"failed_attempts": squirrel.Expr(
+			"CASE WHEN "+staleSQL+" THEN 1 ELSE login_throttles.failed_attempts + 1 END",
+			staleArgs...,
+		),
+		"locked_until": squirrel.Expr(
+			"CASE WHEN "+staleSQL+" THEN ? ELSE login_throttles.locked_until END",
+			append(append([]any{}, staleArgs...), time.Time{})...,
+		),
+		"last_failure_at": now,
+		"updated_at":      now,
+	})
+	if herr != nil {
+		return herr
+	}
+	if maxAttempts == 0 || throttle.FailedAttempts < maxAttempts || throttle.IsLocked() {
+		return nil
+	}
+	throttle.LockedUntil = now.Add(loginThrottlingService.loginThrottlingConfiguration.GetLockoutDuration())
+	_, herr = loginThrottlingService.loginThrottleRepository.UpdateColumns(
+		squirrel.Eq{"id": throttle.ID.String()},
+		map[string]any{"locked_until": throttle.LockedUntil},
+	)
+	if herr != nil {
+		return herr
+	}
+	loginThrottlingService.logger.Warn("Locked the login after too many failed attempts",
+		zap.String("key", key), zap.Time("lockedUntil", throttle.LockedUntil))
+	return nil
+}
+
+// Return the duration to wait before the next attempt is allowed
+func (loginThrottlingService *loginThrottlingServiceImpl) waitFor(throttle *models.LoginThrottle, now time.Time) time.Duration {
+	if now.Before(throttle.LockedUntil) {
+		return throttle.LockedUntil.Sub(now)
+	}
+	if loginThrottlingService.isStale(throttle, now) {
+		return 0
+	}
+	nextAttemptAt := throttle.LastFailureAt.Add(loginThrottlingService.delay(throttle.FailedAttempts))
+	if now.Before(nextAttemptAt) {
+		return nextAttemptAt.Sub(now)
+	}
+	return 0
+}
+
+// Return true if the failed attempts of the throttle must not be taken into account anymore
+func (loginThrottlingService *loginThrottlingServiceImpl) isStale(throttle *models.LoginThrottle, now time.Time) bool {
+	return throttle.FailedAttempts == 0 ||
+		(!throttle.LockedUntil.IsZero() && !now.Before(throttle.LockedUntil)) ||
+		now.Sub(throttle.LastFailureAt) > loginThrottlingService.loginThrottlingConfiguration.GetWindow()
+}
+
+// Return the delay imposed after failedAttempts failures, it doubles after each failure up to the maximum delay
+func (loginThrottlingService *loginThrottlingServiceImpl) delay(failedAttempts uint) time.Duration {
+	maxDelay := loginThrottlingService.loginThrottlingConfiguration.GetMaxDelay()
+	delay := loginThrottlingService.loginThrottlingConfiguration.GetBaseDelay()
+	for i := uint(1); i < failedAttempts && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxDelay {
+		return maxDelay
+	}
+	return delay
+}
+
+// Return the throttle of the key or nil if there is none
+func (loginThrottlingService *loginThrottlingServiceImpl) getThrottle(key string) (*models.LoginThrottle, httperrors.HTTPError) {
+	throttles, herr := loginThrottlingService.loginThrottleRepository.Find(squirrel.Eq{"key": key}, nil, nil)
+	if herr != nil {
+		return nil, herr
+	}
+	if !throttles.HasContent {
+		return nil, nil
+	}
+	return throttles.Ressources[0], nil
+}
+
+// Return the keys of the throttles checked for a login attempt
+func throttleKeys(email, ip string) []string {
+	keys := []string{accountKey(email)}
+	if ip != "" {
+		keys = append(keys, ipKey(ip))
+	}
+	return keys
+}
+
+// Return the key of the throttle of an account
+func accountKey(email string) string {
+	return "account:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+// Return the key of the throttle of an ip address
+func ipKey(ip string) string {
+	return "ip:" + ip
+}
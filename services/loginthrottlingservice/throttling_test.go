@@ -0,0 +1,193 @@
+package loginthrottlingservice_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	mocksConfiguration "github.com/ditrit/badaas/mocks/configuration"
+	mocksRepository "github.com/ditrit/badaas/mocks/persistence/repository"
+	"github.com/ditrit/badaas/persistence/models"
+	"github.com/ditrit/badaas/persistence/pagination"
+	"github.com/ditrit/badaas/services/loginthrottlingservice"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/fx/fxtest"
+	"go.uber.org/zap"
+)
+
+func setupTest(t *testing.T, enabled bool) (
+	*mocksRepository.CRUDRepository[models.LoginThrottle, uuid.UUID],
+	loginthrottlingservice.LoginThrottlingService,
+) {
+	loginThrottlingConfiguration := mocksConfiguration.NewLoginThrottlingConfiguration(t)
+	loginThrottlingConfiguration.On("GetEnabled").Return(enabled).Maybe()
+	loginThrottlingConfiguration.On("GetAccountMaxAttempts").Return(uint(3)).Maybe()
+	loginThrottlingConfiguration.On("GetIPMaxAttempts").Return(uint(10)).Maybe()
+	loginThrottlingConfiguration.On("GetBaseDelay").Return(time.Second).Maybe()
+	loginThrottlingConfiguration.On("GetMaxDelay").Return(30 * time.Second).Maybe()
+	loginThrottlingConfiguration.On("GetLockoutDuration").Return(15 * time.Minute).Maybe()
+	loginThrottlingConfiguration.On("GetWindow").Return(time.Hour).Maybe()
+	loginThrottleRepository := mocksRepository.NewCRUDRepository[models.LoginThrottle, uuid.UUID](t)
+	// the purge is not started
+	return loginThrottleRepository, loginthrottlingservice.NewLoginThrottlingService(
+		fxtest.NewLifecycle(t), zap.NewNop(), loginThrottlingConfiguration, loginThrottleRepository)
+}
+
+func onFind(repository *mocksRepository.CRUDRepository[models.LoginThrottle, uuid.UUID], key string, throttles ...*models.LoginThrottle) {
+	repository.On("Find", squirrel.Eq{"key": key}, nil, nil).
+		Return(pagination.NewPage(throttles, 1, 10, uint(len(throttles))), nil)
+}
+
+func TestCheckDisabled(t *testing.T) {
+	_, service := setupTest(t, false)
+	retryAfter, herr := service.Check("bob@email.com", "192.0.2.1")
+	assert.Nil(t, herr)
+	assert.Zero(t, retryAfter)
+}
+
+func TestCheckWithoutFailures(t *testing.T) {
+	repository, service := setupTest(t, true)
+	onFind(repository, "account:bob@email.com")
+	onFind(repository, "ip:192.0.2.1")
+
+	retryAfter, herr := service.Check(" Bob@email.com", "192.0.2.1")
+	assert.Nil(t, herr)
+	assert.Zero(t, retryAfter)
+}
+
+func TestCheckProgressiveDelay(t *testing.T) {
+	repository, service := setupTest(t, true)
+	onFind(repository, "account:bob@email.com",
+		&models.LoginThrottle{Key: "account:bob@email.com", FailedAttempts: 2, LastFailureAt: time.Now()})
+	onFind(repository, "ip:192.0.2.1")
+
+	// the delay doubles after the second failure
+	retryAfter, herr := service.Check("bob@email.com", "192.0.2.1")
+	assert.Equal(t, loginthrottlingservice.HERRTooManyAttempts, herr)
+	assert.InDelta(t, 2*time.Second, retryAfter, float64(100*time.Millisecond))
+}
+
+func TestCheckDelayElapsed(t *testing.T) {
+	repository, service := setupTest(t, true)
+	onFind(repository, "account:bob@email.com",
+		&models.LoginThrottle{Key: "account:bob@email.com", FailedAttempts: 2, LastFailureAt: time.Now().Add(-3 * time.Second)})
+
+	retryAfter, herr := service.Check("bob@email.com", "")
+	assert.Nil(t, herr)
+	assert.Zero(t, retryAfter)
+}
+
+func TestCheckLockedIP(t *testing.T) {
+	repository, service := setupTest(t, true)
+	onFind(repository, "account:alice@email.com")
+	onFind(repository, "ip:192.0.2.1", &models.LoginThrottle{
+		Key: "ip:192.0.2.1", FailedAttempts: 10,
+		LastFailureAt: time.Now(), LockedUntil: time.Now().Add(10 * time.Minute),
+	})
+
+	retryAfter, herr := service.Check("alice@email.com", "192.0.2.1")
+	assert.Equal(t, loginthrottlingservice.HERRTooManyAttempts, herr)
+	assert.InDelta(t, 10*time.Minute, retryAfter, float64(time.Second))
+}
+
+func TestCheckLockoutExpired(t *testing.T) {
+	repository, service := setupTest(t, true)
+	onFind(repository, "account:bob@email.com", &models.LoginThrottle{
+		Key: "account:bob@email.com", FailedAttempts: 3,
+		LastFailureAt: time.Now().Add(-20 * time.Minute), LockedUntil: time.Now().Add(-5 * time.Minute),
+	})
+
+	retryAfter, herr := service.Check("bob@email.com", "")
+	assert.Nil(t, herr)
+	assert.Zero(t, retryAfter)
+}
+
+// Set the throttle stored by the upsert of a key
+func onUpsert(repository *mocksRepository.CRUDRepository[models.LoginThrottle, uuid.UUID], key string, stored models.LoginThrottle) {
+	repository.On("Upsert", mock.MatchedBy(func(throttle *models.LoginThrottle) bool {
+		return throttle.Key == key
+	}), []string{"key"}, mock.Anything).Run(func(args mock.Arguments) {
+		*args.Get(0).(*models.LoginThrottle) = stored
+	}).Return(nil).Once()
+}
+
+func TestRecordFailureUpsertsThrottles(t *testing.T) {
+	repository, service := setupTest(t, true)
+	repository.On("Upsert", mock.MatchedBy(func(throttle *models.LoginThrottle) bool {
+		return throttle.Key == "account:bob@email.com" && throttle.FailedAttempts == 1 && throttle.LockedUntil.IsZero()
+	}), []string{"key"}, mock.MatchedBy(func(assignments map[string]any) bool {
+		// the failed attempts are incremented by the database, so that the concurrent failures are all counted
+		increment, ok := assignments["failed_attempts"].(squirrel.Sqlizer)
+		if !ok {
+			return false
+		}
+		sql, _, err := increment.ToSql()
+		return err == nil && strings.Contains(sql, "ELSE login_throttles.failed_attempts + 1 END")
+	})).Return(nil).Once()
+	onUpsert(repository, "ip:192.0.2.1", models.LoginThrottle{Key: "ip:192.0.2.1", FailedAttempts: 1})
+
+	assert.Nil(t, service.RecordFailure("bob@email.com", "192.0.2.1"))
+}
+
+func TestRecordFailureLocksAccount(t *testing.T) {
+	repository, service := setupTest(t, true)
+	throttleID := uuid.New()
+	onUpsert(repository, "account:bob@email.com", models.LoginThrottle{
+		BaseModel: models.BaseModel{ID: throttleID}, Key: "account:bob@email.com", FailedAttempts: 3, LastFailureAt: time.Now(),
+	})
+	repository.On("UpdateColumns", squirrel.Eq{"id": throttleID.String()}, mock.MatchedBy(func(columns map[string]any) bool {
+		lockedUntil, ok := columns["locked_until"].(time.Time)
+		return ok && lockedUntil.After(time.Now().Add(14*time.Minute))
+	})).Return(uint(1), nil).Once()
+
+	assert.Nil(t, service.RecordFailure("bob@email.com", ""))
+}
+
+func TestRecordFailureAlreadyLocked(t *testing.T) {
+	// a concurrent failure locked the account first, its lockout is not extended
+	repository, service := setupTest(t, true)
+	onUpsert(repository, "account:bob@email.com", models.LoginThrottle{
+		Key: "account:bob@email.com", FailedAttempts: 4, LastFailureAt: time.Now(), LockedUntil: time.Now().Add(10 * time.Minute),
+	})
+
+	assert.Nil(t, service.RecordFailure("bob@email.com", ""))
+}
+
+func TestRecordFailureBelowTheMaximum(t *testing.T) {
+	repository, service := setupTest(t, true)
+	onUpsert(repository, "account:bob@email.com", models.LoginThrottle{
+		Key: "account:bob@email.com", FailedAttempts: 2, LastFailureAt: time.Now(),
+	})
+
+	assert.Nil(t, service.RecordFailure("bob@email.com", ""))
+}
+
+func TestRecordFailureDisabled(t *testing.T) {
+	_, service := setupTest(t, false)
+	assert.Nil(t, service.RecordFailure("bob@email.com", "192.0.2.1"))
+}
+
+func TestResetAccount(t *testing.T) {
+	repository, service := setupTest(t, true)
+	repository.On("UpdateColumns", squirrel.And{
+		squirrel.Eq{"key": "account:bob@email.com"},
+		squirrel.Or{squirrel.Gt{"failed_attempts": 0}, squirrel.NotEq{"locked_until": time.Time{}}},
+	}, map[string]any{"failed_attempts": 0, "locked_until": time.Time{}}).Return(uint(1), nil).Once()
+
+	assert.Nil(t, service.ResetAccount(" Bob@email.com"))
+}
+
+func TestPurgeStale(t *testing.T) {
+	repository, service := setupTest(t, true)
+	repository.On("DeleteUnscoped", mock.MatchedBy(func(filters squirrel.And) bool {
+		lastFailureAt := filters[0].(squirrel.Lt)["last_failure_at"].(time.Time)
+		lockedUntil := filters[1].(squirrel.LtOrEq)["locked_until"].(time.Time)
+		// the failures out of the window, once the lockout is over
+		return time.Since(lastFailureAt).Round(time.Minute) == time.Hour && time.Since(lockedUntil) < time.Minute
+	})).Return(uint(2), nil)
+
+	assert.Nil(t, service.PurgeStale())
+}
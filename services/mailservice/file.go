@@ -0,0 +1,38 @@
+package mailservice
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Check interface compliance
+var _ MailSender = (*fileMailSender)(nil)
+
+// A MailSender writing each mail to an .eml file, useful for development and tests
+type fileMailSender struct {
+	directory string
+}
+
+// Create a MailSender writing the mails to .eml files in the directory
+func NewFileMailSender(directory string) MailSender {
+	return &fileMailSender{directory: directory}
+}
+
+// Write the mail to a new file of the directory
+func (sender *fileMailSender) Send(mail *Mail) error {
+	message, err := buildMessage(mail)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(sender.directory, 0o750)
+	if err != nil {
+		return err
+	}
+	// the files are sorted by sending date
+	fileName := fmt.Sprintf("%s-%s.eml", time.Now().UTC().Format("20060102T150405.000000000"), uuid.NewString())
+	return os.WriteFile(filepath.Join(sender.directory, fileName), message, 0o640)
+}
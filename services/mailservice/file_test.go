@@ -0,0 +1,60 @@
+package mailservice_test
+
+import (
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ditrit/badaas/services/mailservice"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileMailSender(t *testing.T) {
+	directory := filepath.Join(t.TempDir(), "mails")
+	sender := mailservice.NewFileMailSender(directory)
+
+	err := sender.Send(&mailservice.Mail{
+		From:    "no-reply@badaas.com",
+		To:      "bob@email.com",
+		Subject: "Vérifiez votre email",
+		Text:    "Hello Bob",
+		HTML:    "<p>Hello Bob</p>",
+	})
+	require.NoError(t, err)
+
+	files, err := os.ReadDir(directory)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	file, err := os.Open(filepath.Join(directory, files[0].Name()))
+	require.NoError(t, err)
+	defer file.Close()
+
+	message, err := mail.ReadMessage(file)
+	require.NoError(t, err)
+	assert.Equal(t, "bob@email.com", message.Header.Get("To"))
+	subject, err := new(mime.WordDecoder).DecodeHeader(message.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, "Vérifiez votre email", subject)
+
+	mediaType, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	assert.Equal(t, "multipart/alternative", mediaType)
+	reader := multipart.NewReader(message.Body, params["boundary"])
+	contents := []string{}
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		content, err := io.ReadAll(part)
+		require.NoError(t, err)
+		contents = append(contents, string(content))
+	}
+	assert.Equal(t, []string{"Hello Bob", "<p>Hello Bob</p>"}, contents)
+}
@@ -0,0 +1,62 @@
+package mailservice
+
+import (
+	"fmt"
+
+	"github.com/ditrit/badaas/configuration"
+	"go.uber.org/zap"
+)
+
+// A mail sent to a user
+type Mail struct {
+	From    string
+	To      string
+	Subject string
+	Text    string
+	// optional html alternative of the text
+	HTML string
+}
+
+// MailSender deliver the mails
+//
+// Replace the provided implementation to send the mails with another transport.
+type MailSender interface {
+	Send(mail *Mail) error
+}
+
+// Create the MailSender of the transport selected in the configuration
+func NewMailSender(logger *zap.Logger, mailConfiguration configuration.MailConfiguration) (MailSender, error) {
+	switch mailConfiguration.GetTransport() {
+	case configuration.MailTransportLog:
+		return NewLogMailSender(logger), nil
+	case configuration.MailTransportFile:
+		return NewFileMailSender(mailConfiguration.GetFilePath()), nil
+	case configuration.MailTransportSMTP:
+		return NewSMTPMailSender(mailConfiguration), nil
+	default:
+		return nil, fmt.Errorf("unknown mail transport %q", mailConfiguration.GetTransport())
+	}
+}
+
+// Check interface compliance
+var _ MailSender = (*logMailSender)(nil)
+
+// A MailSender writing the mails to the logs, useful for development
+type logMailSender struct {
+	logger *zap.Logger
+}
+
+// Create a MailSender writing the mails to the logs
+func NewLogMailSender(logger *zap.Logger) MailSender {
+	return &logMailSender{logger: logger}
+}
+
+// Write the mail to the logs
+func (sender *logMailSender) Send(mail *Mail) error {
+	sender.logger.Info("Mail sent",
+		zap.String("to", mail.To),
+		zap.String("subject", mail.Subject),
+		zap.String("text", mail.Text),
+	)
+	return nil
+}
@@ -0,0 +1,33 @@
+package mailservice_test
+
+import (
+	"testing"
+
+	mocksConfiguration "github.com/ditrit/badaas/mocks/configuration"
+	"github.com/ditrit/badaas/services/mailservice"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestLogMailSender(t *testing.T) {
+	observedZapCore, observedLogs := observer.New(zap.DebugLevel)
+	sender := mailservice.NewLogMailSender(zap.New(observedZapCore))
+
+	err := sender.Send(&mailservice.Mail{To: "bob@email.com", Subject: "Hello", Text: "Hello Bob"})
+	require.NoError(t, err)
+	require.Equal(t, 1, observedLogs.Len())
+	log := observedLogs.All()[0]
+	assert.Equal(t, "Mail sent", log.Message)
+	assert.Equal(t, map[string]any{"to": "bob@email.com", "subject": "Hello", "text": "Hello Bob"}, log.ContextMap())
+}
+
+func TestNewMailSenderUnknownTransport(t *testing.T) {
+	mailConfiguration := mocksConfiguration.NewMailConfiguration(t)
+	mailConfiguration.On("GetTransport").Return("pigeon")
+
+	sender, err := mailservice.NewMailSender(zap.L(), mailConfiguration)
+	assert.Error(t, err)
+	assert.Nil(t, sender)
+}
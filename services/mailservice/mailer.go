@@ -0,0 +1,222 @@
+package mailservice
+
+import (
+	"context"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/ditrit/badaas/configuration"
+	"github.com/ditrit/badaas/httperrors"
+	"github.com/ditrit/badaas/persistence/models"
+	"github.com/ditrit/badaas/persistence/repository"
+	"github.com/google/uuid"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+// Mailer send the mails built from templates
+//
+// The mails are saved in a queue and delivered in the background, so a mail is not lost
+// when the transport is down and the callers don't wait for the delivery.
+type Mailer interface {
+	// Render the template in the locale and add the mail to the send queue
+	SendTemplate(to, locale, templateName string, data any) error
+	// Deliver the pending mails whose next attempt is due
+	ProcessQueue() error
+}
+
+// Check interface compliance
+var _ Mailer = (*mailerImpl)(nil)
+
+// Mailer implementation
+type mailerImpl struct {
+	logger                *zap.Logger
+	mailConfiguration     configuration.MailConfiguration
+	templateRenderer      TemplateRenderer
+	mailSender            MailSender
+	mailMessageRepository repository.CRUDRepository[models.MailMessage, uuid.UUID]
+	// wake the worker up when a mail is added to the queue
+	wakeUp chan struct{}
+}
+
+// Mailer constructor
+//
+// The send queue is processed in the background between the start and the stop of the application.
+func NewMailer(
+	lc fx.Lifecycle,
+	logger *zap.Logger,
+	mailConfiguration configuration.MailConfiguration,
+	templateRenderer TemplateRenderer,
+	mailSender MailSender,
+	mailMessageRepository repository.CRUDRepository[models.MailMessage, uuid.UUID],
+) Mailer {
+	mailer := &mailerImpl{
+		logger:                logger,
+		mailConfiguration:     mailConfiguration,
+		templateRenderer:      templateRenderer,
+		mailSender:            mailSender,
+		mailMessageRepository: mailMessageRepository,
+		wakeUp:                make(chan struct{}, 1),
+	}
+	stop := make(chan struct{})
+	stopped := make(chan struct{})
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			go mailer.run(stop, stopped)
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			close(stop)
+			select {
+			case <-stopped:
+			case <-ctx.Done():
+			}
+			return nil
+		},
+	})
+	return mailer
+}
+
+// Process the queue periodically, or as soon as a mail is added, until stop is closed
+func (mailer *mailerImpl) run(stop <-chan struct{}, stopped chan<- struct{}) {
+	defer close(stopped)
+	ticker := time.NewTicker(mailer.mailConfiguration.GetQueueInterval())
+	defer ticker.Stop()
+	for {
+		err := mailer.ProcessQueue()
+		if err != nil {
+			mailer.logger.Error("Failed to process the mail queue", zap.Error(err))
+		}
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		case <-mailer.wakeUp:
+		}
+	}
+}
+
+// Render the template in the locale and add the mail to the send queue
+func (mailer *mailerImpl) SendTemplate(to, locale, templateName string, data any) error {
+	mail, err := mailer.templateRenderer.Render(locale, templateName, data)
+	if err != nil {
+		return err
+	}
+	mailMessage := &models.MailMessage{
+		From:          mailer.mailConfiguration.GetFrom(),
+		To:            to,
+		Subject:       mail.Subject,
+		Text:          mail.Text,
+		HTML:          mail.HTML,
+		Status:        models.MailStatusPending,
+		NextAttemptAt: time.Now(),
+	}
+	err = mailer.mailMessageRepository.Create(mailMessage)
+	if err != nil {
+		return err
+	}
+	// don't block if the worker is already woken up
+	select {
+	case mailer.wakeUp <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// The time a node has to deliver a claimed mail, before the other nodes can claim it again
+const claimDuration = 10 * time.Minute
+
+// Deliver the pending mails whose next attempt is due
+//
+// A failed delivery is retried later with an exponential backoff,
+// the mail is marked as failed once the maximum number of attempts is reached.
+// Each mail is claimed before its delivery, so that it is delivered by a single node.
+func (mailer *mailerImpl) ProcessQueue() error {
+	mailMessages, herr := mailer.mailMessageRepository.Find(
+		squirrel.And{
+			squirrel.Eq{"status": models.MailStatusPending},
+			squirrel.LtOrEq{"next_attempt_at": time.Now()},
+		},
+		nil,
+		repository.NewSortOption("next_attempt_at", false),
+	)
+	if herr != nil {
+		return herr
+	}
+	for _, mailMessage := range mailMessages.Ressources {
+		claimed, herr := mailer.claim(mailMessage)
+		if herr != nil {
+			return herr
+		}
+		if !claimed {
+			continue
+		}
+		mailer.deliver(mailMessage)
+		herr = mailer.mailMessageRepository.Save(mailMessage)
+		if herr != nil {
+			return herr
+		}
+	}
+	return nil
+}
+
+// Claim a pending mail by postponing its next attempt, return false if another node claimed it first
+//
+// The claim expires, so that the mail is still delivered if the node stops before the delivery.
+func (mailer *mailerImpl) claim(mailMessage *models.MailMessage) (bool, httperrors.HTTPError) {
+	now := time.Now()
+	claimedUntil := now.Add(claimDuration)
+	updated, herr := mailer.mailMessageRepository.UpdateColumns(
+		squirrel.And{
+			squirrel.Eq{"id": mailMessage.ID.String(), "status": models.MailStatusPending},
+			squirrel.LtOrEq{"next_attempt_at": now},
+		},
+		map[string]any{"next_attempt_at": claimedUntil, "updated_at": now},
+	)
+	if herr != nil {
+		return false, herr
+	}
+	mailMessage.NextAttemptAt = claimedUntil
+	return updated > 0, nil
+}
+
+// Try to deliver a mail and update its status
+//
+// The bodies of the mails may hold tokens, they are cleared once the mail is sent or failed.
+func (mailer *mailerImpl) deliver(mailMessage *models.MailMessage) {
+	mailMessage.Attempts++
+	err := mailer.mailSender.Send(&Mail{
+		From:    mailMessage.From,
+		To:      mailMessage.To,
+		Subject: mailMessage.Subject,
+		Text:    mailMessage.Text,
+		HTML:    mailMessage.HTML,
+	})
+	if err == nil {
+		now := time.Now()
+		mailMessage.Status = models.MailStatusSent
+		mailMessage.SentAt = &now
+		mailMessage.LastError = ""
+		clearBodies(mailMessage)
+		return
+	}
+	mailMessage.LastError = err.Error()
+	if mailMessage.Attempts >= mailer.mailConfiguration.GetQueueMaxAttempts() {
+		mailMessage.Status = models.MailStatusFailed
+		clearBodies(mailMessage)
+		mailer.logger.Error("Failed to send a mail, giving up",
+			zap.String("mailID", mailMessage.ID.String()), zap.Uint("attempts", mailMessage.Attempts), zap.Error(err))
+		return
+	}
+	// wait the queue interval, then twice as long after each failure
+	backoff := mailer.mailConfiguration.GetQueueInterval() << (mailMessage.Attempts - 1)
+	mailMessage.NextAttemptAt = time.Now().Add(backoff)
+	mailer.logger.Warn("Failed to send a mail, retrying later",
+		zap.String("mailID", mailMessage.ID.String()), zap.Uint("attempts", mailMessage.Attempts), zap.Error(err))
+}
+
+// Clear the text and the html of a mail which won't be delivered again
+func clearBodies(mailMessage *models.MailMessage) {
+	mailMessage.Text = ""
+	mailMessage.HTML = ""
+}
@@ -0,0 +1,203 @@
+package mailservice_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	mocksConfiguration "github.com/ditrit/badaas/mocks/configuration"
+	mocksRepository "github.com/ditrit/badaas/mocks/persistence/repository"
+	mocksMailService "github.com/ditrit/badaas/mocks/services/mailservice"
+	"github.com/ditrit/badaas/persistence/models"
+	"github.com/ditrit/badaas/persistence/pagination"
+	"github.com/ditrit/badaas/persistence/repository"
+	"github.com/ditrit/badaas/services/mailservice"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx/fxtest"
+	"go.uber.org/zap"
+)
+
+func setupMailerTest(t *testing.T) (
+	*mocksRepository.CRUDRepository[models.MailMessage, uuid.UUID],
+	*mocksMailService.MailSender,
+	mailservice.Mailer,
+) {
+	mailConfiguration := mocksConfiguration.NewMailConfiguration(t)
+	mailConfiguration.On("GetFrom").Return("no-reply@badaas.com").Maybe()
+	mailConfiguration.On("GetQueueInterval").Return(time.Minute).Maybe()
+	mailConfiguration.On("GetQueueMaxAttempts").Return(uint(3)).Maybe()
+	templateRenderer := mocksMailService.NewTemplateRenderer(t)
+	templateRenderer.On("Render", mock.Anything, mock.Anything, mock.Anything).
+		Return(&mailservice.Mail{Subject: "Hello", Text: "Hello Bob"}, nil).Maybe()
+	mailMessageRepository := mocksRepository.NewCRUDRepository[models.MailMessage, uuid.UUID](t)
+	mailSender := mocksMailService.NewMailSender(t)
+	// the worker is not started
+	mailer := mailservice.NewMailer(fxtest.NewLifecycle(t), zap.L(), mailConfiguration,
+		templateRenderer, mailSender, mailMessageRepository)
+	return mailMessageRepository, mailSender, mailer
+}
+
+// Let the mailer claim the mail, or let another node claim it first
+func onClaim(mailMessageRepository *mocksRepository.CRUDRepository[models.MailMessage, uuid.UUID],
+	mailMessage *models.MailMessage, claimed bool,
+) {
+	updated := uint(0)
+	if claimed {
+		updated = 1
+	}
+	// the claim only succeeds if the mail is still pending
+	mailMessageRepository.On("UpdateColumns",
+		mock.MatchedBy(func(filters squirrel.And) bool {
+			return assert.ObjectsAreEqual(
+				squirrel.Eq{"id": mailMessage.ID.String(), "status": models.MailStatusPending}, filters[0])
+		}),
+		mock.AnythingOfType("map[string]interface {}"),
+	).Return(updated, nil).Once()
+}
+
+func TestSendTemplate(t *testing.T) {
+	mailMessageRepository, _, mailer := setupMailerTest(t)
+	mailMessageRepository.On("Create", mock.Anything).Return(nil)
+
+	err := mailer.SendTemplate("bob@email.com", "fr", mailservice.TemplatePasswordReset, nil)
+	require.NoError(t, err)
+	mailMessage := mailMessageRepository.Calls[0].Arguments.Get(0).(*models.MailMessage)
+	assert.Equal(t, "no-reply@badaas.com", mailMessage.From)
+	assert.Equal(t, "bob@email.com", mailMessage.To)
+	assert.Equal(t, "Hello", mailMessage.Subject)
+	assert.Equal(t, models.MailStatusPending, mailMessage.Status)
+}
+
+func TestProcessQueue(t *testing.T) {
+	mailMessageRepository, mailSender, mailer := setupMailerTest(t)
+	mailMessage := &models.MailMessage{
+		BaseModel: models.BaseModel{ID: uuid.New()},
+		To:        "bob@email.com", Subject: "Hello", Text: "Your token", HTML: "<p>Your token</p>",
+		Status: models.MailStatusPending,
+	}
+	mailMessageRepository.On("Find", mock.Anything, nil, mock.Anything).
+		Return(pagination.NewPage([]*models.MailMessage{mailMessage}, 1, 1, 1), nil)
+	onClaim(mailMessageRepository, mailMessage, true)
+	mailMessageRepository.On("Save", mailMessage).Return(nil)
+	mailSender.On("Send", mock.Anything).Return(nil)
+
+	err := mailer.ProcessQueue()
+	require.NoError(t, err)
+	assert.Equal(t, models.MailStatusSent, mailMessage.Status)
+	assert.Equal(t, uint(1), mailMessage.Attempts)
+	assert.NotNil(t, mailMessage.SentAt)
+	sentMail := mailSender.Calls[0].Arguments.Get(0).(*mailservice.Mail)
+	assert.Equal(t, "bob@email.com", sentMail.To)
+	assert.Equal(t, "Your token", sentMail.Text)
+	// the bodies are not kept once sent
+	assert.Empty(t, mailMessage.Text)
+	assert.Empty(t, mailMessage.HTML)
+}
+
+func TestProcessQueueClaimedByAnotherNode(t *testing.T) {
+	mailMessageRepository, mailSender, mailer := setupMailerTest(t)
+	mailMessage := &models.MailMessage{BaseModel: models.BaseModel{ID: uuid.New()}, To: "bob@email.com", Status: models.MailStatusPending}
+	mailMessageRepository.On("Find", mock.Anything, nil, mock.Anything).
+		Return(pagination.NewPage([]*models.MailMessage{mailMessage}, 1, 1, 1), nil)
+	onClaim(mailMessageRepository, mailMessage, false)
+
+	err := mailer.ProcessQueue()
+	require.NoError(t, err)
+	mailSender.AssertNotCalled(t, "Send", mock.Anything)
+	mailMessageRepository.AssertNotCalled(t, "Save", mock.Anything)
+}
+
+func TestProcessQueueRetry(t *testing.T) {
+	mailMessageRepository, mailSender, mailer := setupMailerTest(t)
+	mailMessage := &models.MailMessage{
+		BaseModel: models.BaseModel{ID: uuid.New()}, To: "bob@email.com", Text: "Your token",
+		Status: models.MailStatusPending, Attempts: 1,
+	}
+	mailMessageRepository.On("Find", mock.Anything, nil, mock.Anything).
+		Return(pagination.NewPage([]*models.MailMessage{mailMessage}, 1, 1, 1), nil)
+	onClaim(mailMessageRepository, mailMessage, true)
+	mailMessageRepository.On("Save", mailMessage).Return(nil)
+	mailSender.On("Send", mock.Anything).Return(errors.New("connection refused"))
+
+	err := mailer.ProcessQueue()
+	require.NoError(t, err)
+	assert.Equal(t, models.MailStatusPending, mailMessage.Status)
+	assert.Equal(t, uint(2), mailMessage.Attempts)
+	assert.Equal(t, "connection refused", mailMessage.LastError)
+	// the delay doubles after each failure
+	assert.WithinDuration(t, time.Now().Add(2*time.Minute), mailMessage.NextAttemptAt, time.Second)
+	// the bodies are kept for the next attempt
+	assert.Equal(t, "Your token", mailMessage.Text)
+}
+
+func TestProcessQueueGiveUp(t *testing.T) {
+	mailMessageRepository, mailSender, mailer := setupMailerTest(t)
+	mailMessage := &models.MailMessage{
+		BaseModel: models.BaseModel{ID: uuid.New()}, To: "bob@email.com", Text: "Your token",
+		Status: models.MailStatusPending, Attempts: 2,
+	}
+	mailMessageRepository.On("Find", mock.Anything, nil, mock.Anything).
+		Return(pagination.NewPage([]*models.MailMessage{mailMessage}, 1, 1, 1), nil)
+	onClaim(mailMessageRepository, mailMessage, true)
+	mailMessageRepository.On("Save", mailMessage).Return(nil)
+	mailSender.On("Send", mock.Anything).Return(errors.New("connection refused"))
+
+	err := mailer.ProcessQueue()
+	require.NoError(t, err)
+	assert.Equal(t, models.MailStatusFailed, mailMessage.Status)
+	assert.Equal(t, uint(3), mailMessage.Attempts)
+	assert.Empty(t, mailMessage.Text)
+}
+
+func TestMailerWorker(t *testing.T) {
+	mailConfiguration := mocksConfiguration.NewMailConfiguration(t)
+	mailConfiguration.On("GetFrom").Return("no-reply@badaas.com")
+	mailConfiguration.On("GetQueueInterval").Return(time.Hour)
+	templateRenderer := mocksMailService.NewTemplateRenderer(t)
+	templateRenderer.On("Render", "en", mailservice.TemplatePasswordReset, nil).
+		Return(&mailservice.Mail{Subject: "Hello", Text: "Hello Bob"}, nil)
+	mailMessageRepository := mocksRepository.NewCRUDRepository[models.MailMessage, uuid.UUID](t)
+	// the queue is shared by the test and the worker
+	var mutex sync.Mutex
+	queue := []*models.MailMessage{}
+	mailMessageRepository.On("Create", mock.Anything).Run(func(args mock.Arguments) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		queue = append(queue, args.Get(0).(*models.MailMessage))
+	}).Return(nil)
+	mailMessageRepository.On("Find", mock.Anything, nil, mock.Anything).Return(
+		func(squirrel.Sqlizer, pagination.Paginator, repository.SortOption) *pagination.Page[models.MailMessage] {
+			mutex.Lock()
+			defer mutex.Unlock()
+			page := pagination.NewPage(queue, 1, 10, uint(len(queue)))
+			queue = []*models.MailMessage{}
+			return page
+		}, nil)
+	mailMessageRepository.On("UpdateColumns", mock.Anything, mock.Anything).Return(uint(1), nil)
+	mailMessageRepository.On("Save", mock.Anything).Return(nil)
+	sent := make(chan *mailservice.Mail, 1)
+	mailSender := mocksMailService.NewMailSender(t)
+	mailSender.On("Send", mock.Anything).Run(func(args mock.Arguments) {
+		sent <- args.Get(0).(*mailservice.Mail)
+	}).Return(nil)
+
+	lifecycle := fxtest.NewLifecycle(t)
+	mailer := mailservice.NewMailer(lifecycle, zap.L(), mailConfiguration,
+		templateRenderer, mailSender, mailMessageRepository)
+	lifecycle.RequireStart()
+	defer lifecycle.RequireStop()
+
+	require.NoError(t, mailer.SendTemplate("bob@email.com", "en", mailservice.TemplatePasswordReset, nil))
+	// the worker is woken up without waiting for the queue interval
+	select {
+	case mail := <-sent:
+		assert.Equal(t, "bob@email.com", mail.To)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the mail was not sent")
+	}
+}
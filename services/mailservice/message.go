@@ -0,0 +1,69 @@
+package mailservice
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/textproto"
+	"time"
+)
+
+// Build the MIME message of a mail
+//
+// The message is a multipart/alternative message if the mail has an html version.
+func buildMessage(mail *Mail) ([]byte, error) {
+	var buffer bytes.Buffer
+	fmt.Fprintf(&buffer, "From: %s\r\n", mail.From)
+	fmt.Fprintf(&buffer, "To: %s\r\n", mail.To)
+	fmt.Fprintf(&buffer, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", mail.Subject))
+	fmt.Fprintf(&buffer, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buffer.WriteString("MIME-Version: 1.0\r\n")
+
+	if mail.HTML == "" {
+		buffer.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+		buffer.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+		err := writeQuotedPrintable(&buffer, mail.Text)
+		if err != nil {
+			return nil, err
+		}
+		return buffer.Bytes(), nil
+	}
+
+	writer := multipart.NewWriter(&buffer)
+	fmt.Fprintf(&buffer, "Content-Type: multipart/alternative; boundary=%s\r\n\r\n", writer.Boundary())
+	parts := []struct{ contentType, content string }{
+		{"text/plain; charset=utf-8", mail.Text},
+		{"text/html; charset=utf-8", mail.HTML},
+	}
+	for _, part := range parts {
+		partWriter, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		err = writeQuotedPrintable(partWriter, part.content)
+		if err != nil {
+			return nil, err
+		}
+	}
+	err := writer.Close()
+	if err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// Write the content encoded in quoted-printable
+func writeQuotedPrintable(writer io.Writer, content string) error {
+	encoder := quotedprintable.NewWriter(writer)
+	_, err := encoder.Write([]byte(content))
+	if err != nil {
+		return err
+	}
+	return encoder.Close()
+}
@@ -0,0 +1,79 @@
+package mailservice
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+
+	"github.com/ditrit/badaas/configuration"
+)
+
+// Check interface compliance
+var _ MailSender = (*smtpMailSender)(nil)
+
+// A MailSender delivering the mails to a smtp server
+type smtpMailSender struct {
+	mailConfiguration configuration.MailConfiguration
+}
+
+// Create a MailSender delivering the mails to the smtp server of the configuration
+func NewSMTPMailSender(mailConfiguration configuration.MailConfiguration) MailSender {
+	return &smtpMailSender{mailConfiguration: mailConfiguration}
+}
+
+// Deliver the mail to the smtp server
+//
+// The connection is upgraded with STARTTLS if enabled, the server must then support it.
+// The client authenticates only if a username is configured.
+func (sender *smtpMailSender) Send(mail *Mail) error {
+	message, err := buildMessage(mail)
+	if err != nil {
+		return err
+	}
+	host := sender.mailConfiguration.GetSMTPHost()
+	client, err := smtp.Dial(net.JoinHostPort(host, strconv.Itoa(sender.mailConfiguration.GetSMTPPort())))
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if sender.mailConfiguration.GetSMTPStartTLS() {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return fmt.Errorf("the smtp server %q doesn't support STARTTLS", host)
+		}
+		err = client.StartTLS(&tls.Config{ServerName: host, MinVersion: tls.VersionTLS12})
+		if err != nil {
+			return err
+		}
+	}
+	if username := sender.mailConfiguration.GetSMTPUsername(); username != "" {
+		err = client.Auth(smtp.PlainAuth("", username, sender.mailConfiguration.GetSMTPPassword(), host))
+		if err != nil {
+			return err
+		}
+	}
+
+	err = client.Mail(mail.From)
+	if err != nil {
+		return err
+	}
+	err = client.Rcpt(mail.To)
+	if err != nil {
+		return err
+	}
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	_, err = writer.Write(message)
+	if err != nil {
+		return err
+	}
+	err = writer.Close()
+	if err != nil {
+		return err
+	}
+	return client.Quit()
+}
@@ -0,0 +1,103 @@
+package mailservice_test
+
+import (
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	mocksConfiguration "github.com/ditrit/badaas/mocks/configuration"
+	"github.com/ditrit/badaas/services/mailservice"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// A minimal smtp server without STARTTLS, it sends the commands and the data it receives to the channel
+func startSMTPServer(t *testing.T) (int, <-chan string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+	received := make(chan string, 16)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		text := textproto.NewConn(conn)
+		text.PrintfLine("220 localhost ESMTP")
+		for {
+			line, err := text.ReadLine()
+			if err != nil {
+				close(received)
+				return
+			}
+			command := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+			switch command {
+			case "EHLO", "HELO":
+				text.PrintfLine("250-localhost")
+				text.PrintfLine("250 8BITMIME")
+			case "DATA":
+				text.PrintfLine("354 go ahead")
+				data, _ := text.ReadDotLines()
+				received <- strings.Join(data, "\n")
+				text.PrintfLine("250 queued")
+			case "QUIT":
+				text.PrintfLine("221 bye")
+				close(received)
+				return
+			default:
+				received <- line
+				text.PrintfLine("250 ok")
+			}
+		}
+	}()
+	return listener.Addr().(*net.TCPAddr).Port, received
+}
+
+func newSMTPConfiguration(t *testing.T, port int, startTLS bool) *mocksConfiguration.MailConfiguration {
+	mailConfiguration := mocksConfiguration.NewMailConfiguration(t)
+	mailConfiguration.On("GetSMTPHost").Return("127.0.0.1")
+	mailConfiguration.On("GetSMTPPort").Return(port)
+	mailConfiguration.On("GetSMTPStartTLS").Return(startTLS).Maybe()
+	mailConfiguration.On("GetSMTPUsername").Return("").Maybe()
+	return mailConfiguration
+}
+
+func TestSMTPMailSender(t *testing.T) {
+	port, received := startSMTPServer(t)
+	sender := mailservice.NewSMTPMailSender(newSMTPConfiguration(t, port, false))
+
+	err := sender.Send(&mailservice.Mail{From: "no-reply@badaas.com", To: "bob@email.com", Subject: "Hello", Text: "Hello Bob"})
+	require.NoError(t, err)
+
+	lines := []string{}
+	for line := range received {
+		lines = append(lines, line)
+	}
+	require.Len(t, lines, 3)
+	assert.Equal(t, "MAIL FROM:<no-reply@badaas.com>", strings.SplitN(lines[0], " BODY", 2)[0])
+	assert.Equal(t, "RCPT TO:<bob@email.com>", lines[1])
+	assert.Contains(t, lines[2], "Subject: Hello")
+	assert.Contains(t, lines[2], "Hello Bob")
+}
+
+func TestSMTPMailSenderStartTLSNotSupported(t *testing.T) {
+	port, _ := startSMTPServer(t)
+	sender := mailservice.NewSMTPMailSender(newSMTPConfiguration(t, port, true))
+
+	err := sender.Send(&mailservice.Mail{From: "no-reply@badaas.com", To: "bob@email.com", Subject: "Hello", Text: "Hello Bob"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "STARTTLS")
+}
+
+func TestSMTPMailSenderConnectionRefused(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+	sender := mailservice.NewSMTPMailSender(newSMTPConfiguration(t, port, true))
+
+	err = sender.Send(&mailservice.Mail{From: "no-reply@badaas.com", To: "bob@email.com"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,145 @@
+package mailservice
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	htmltemplate "html/template"
+	"io/fs"
+	"os"
+	"strings"
+	texttemplate "text/template"
+
+	"github.com/ditrit/badaas/configuration"
+)
+
+// The names of the templates used by badaas
+const (
+	TemplateEmailVerification = "email_verification"
+	TemplateEmailChange       = "email_change"
+	TemplatePasswordReset     = "password_reset"
+)
+
+// The locale used when no template exists in the locale of the user nor in the default one
+const fallbackLocale = "en"
+
+//go:embed templates
+var embeddedTemplates embed.FS
+
+// TemplateRenderer build the mails from templates
+//
+// A template is made of a text file <locale>/<name>.txt.tmpl defining a "subject" template,
+// and of an optional html file <locale>/<name>.html.tmpl.
+type TemplateRenderer interface {
+	// Render the template in the locale, or in the closest available locale
+	Render(locale, name string, data any) (*Mail, error)
+}
+
+// Check interface compliance
+var _ TemplateRenderer = (*templateRendererImpl)(nil)
+
+// TemplateRenderer implementation
+type templateRendererImpl struct {
+	// the templates are looked up in order
+	templateFSs       []fs.FS
+	mailConfiguration configuration.MailConfiguration
+}
+
+// TemplateRenderer constructor
+//
+// The templates of the templates path of the configuration override the embedded ones.
+func NewTemplateRenderer(mailConfiguration configuration.MailConfiguration) TemplateRenderer {
+	embeddedFS, err := fs.Sub(embeddedTemplates, "templates")
+	if err != nil {
+		panic(err)
+	}
+	templateFSs := []fs.FS{embeddedFS}
+	if templatesPath := mailConfiguration.GetTemplatesPath(); templatesPath != "" {
+		templateFSs = append([]fs.FS{os.DirFS(templatesPath)}, templateFSs...)
+	}
+	return &templateRendererImpl{
+		templateFSs:       templateFSs,
+		mailConfiguration: mailConfiguration,
+	}
+}
+
+// Render the template in the locale
+//
+// If the template doesn't exist in the locale, the language of the locale, the default locale
+// and finally english are tried.
+func (renderer *templateRendererImpl) Render(locale, name string, data any) (*Mail, error) {
+	for _, candidate := range renderer.candidateLocales(locale) {
+		mail, err := renderer.render(candidate, name, data)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		return mail, err
+	}
+	return nil, &fs.PathError{Op: "render", Path: name, Err: fs.ErrNotExist}
+}
+
+// Return the locales to try in order
+func (renderer *templateRendererImpl) candidateLocales(locale string) []string {
+	locale = strings.ToLower(strings.ReplaceAll(locale, "_", "-"))
+	candidates := []string{}
+	if locale != "" {
+		candidates = append(candidates, locale)
+		if language, _, found := strings.Cut(locale, "-"); found {
+			candidates = append(candidates, language)
+		}
+	}
+	return append(candidates, strings.ToLower(renderer.mailConfiguration.GetDefaultLocale()), fallbackLocale)
+}
+
+// Render the template in the locale, return fs.ErrNotExist if there is no such template
+func (renderer *templateRendererImpl) render(locale, name string, data any) (*Mail, error) {
+	templateFS, content, err := renderer.readFile(locale + "/" + name + ".txt.tmpl")
+	if err != nil {
+		return nil, err
+	}
+	textTemplate, err := texttemplate.New(name).Parse(string(content))
+	if err != nil {
+		return nil, err
+	}
+	var subject, text bytes.Buffer
+	err = textTemplate.ExecuteTemplate(&subject, "subject", data)
+	if err != nil {
+		return nil, err
+	}
+	err = textTemplate.Execute(&text, data)
+	if err != nil {
+		return nil, err
+	}
+	mail := &Mail{Subject: strings.TrimSpace(subject.String()), Text: text.String()}
+
+	// the html version is looked up next to the text version
+	content, err = fs.ReadFile(templateFS, locale+"/"+name+".html.tmpl")
+	if errors.Is(err, fs.ErrNotExist) {
+		return mail, nil
+	} else if err != nil {
+		return nil, err
+	}
+	htmlTemplate, err := htmltemplate.New(name).Parse(string(content))
+	if err != nil {
+		return nil, err
+	}
+	var html bytes.Buffer
+	err = htmlTemplate.Execute(&html, data)
+	if err != nil {
+		return nil, err
+	}
+	mail.HTML = html.String()
+	return mail, nil
+}
+
+// Read a file from the first templates filesystem containing it
+func (renderer *templateRendererImpl) readFile(path string) (fs.FS, []byte, error) {
+	for _, templateFS := range renderer.templateFSs {
+		content, err := fs.ReadFile(templateFS, path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		return templateFS, content, err
+	}
+	return nil, nil, fs.ErrNotExist
+}
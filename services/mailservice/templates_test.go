@@ -0,0 +1,89 @@
+package mailservice_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	mocksConfiguration "github.com/ditrit/badaas/mocks/configuration"
+	"github.com/ditrit/badaas/services/mailservice"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var tokenMailData = struct{ Username, Token string }{"bob", "abcd"}
+
+func newTemplateRenderer(t *testing.T, defaultLocale, templatesPath string) mailservice.TemplateRenderer {
+	mailConfiguration := mocksConfiguration.NewMailConfiguration(t)
+	mailConfiguration.On("GetTemplatesPath").Return(templatesPath)
+	mailConfiguration.On("GetDefaultLocale").Return(defaultLocale).Maybe()
+	return mailservice.NewTemplateRenderer(mailConfiguration)
+}
+
+func TestRenderTemplate(t *testing.T) {
+	renderer := newTemplateRenderer(t, "en", "")
+
+	mail, err := renderer.Render("en", mailservice.TemplatePasswordReset, tokenMailData)
+	require.NoError(t, err)
+	assert.Equal(t, "Reset your password", mail.Subject)
+	assert.Contains(t, mail.Text, "Hello bob,")
+	assert.Contains(t, mail.Text, "abcd")
+	assert.Contains(t, mail.HTML, "<strong>abcd</strong>")
+}
+
+func TestRenderTemplateEscapesHTML(t *testing.T) {
+	renderer := newTemplateRenderer(t, "en", "")
+
+	mail, err := renderer.Render("en", mailservice.TemplateEmailVerification,
+		struct{ Username, Token string }{"<b>bob</b>", "abcd"})
+	require.NoError(t, err)
+	assert.Contains(t, mail.Text, "<b>bob</b>")
+	assert.Contains(t, mail.HTML, "&lt;b&gt;bob&lt;/b&gt;")
+}
+
+func TestRenderTemplateLocaleFallback(t *testing.T) {
+	renderer := newTemplateRenderer(t, "en", "")
+
+	// the language is used when the region has no template
+	mail, err := renderer.Render("fr_CA", mailservice.TemplateEmailChange, tokenMailData)
+	require.NoError(t, err)
+	assert.Equal(t, "Confirmez votre nouvelle adresse email", mail.Subject)
+
+	// the default locale is used when the language has no template
+	mail, err = renderer.Render("de", mailservice.TemplateEmailChange, tokenMailData)
+	require.NoError(t, err)
+	assert.Equal(t, "Confirm your new email", mail.Subject)
+}
+
+func TestRenderTemplateDefaultLocale(t *testing.T) {
+	renderer := newTemplateRenderer(t, "fr", "")
+
+	mail, err := renderer.Render("", mailservice.TemplatePasswordReset, tokenMailData)
+	require.NoError(t, err)
+	assert.Equal(t, "Réinitialisez votre mot de passe", mail.Subject)
+}
+
+func TestRenderTemplateOverride(t *testing.T) {
+	templatesPath := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(templatesPath, "en"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(templatesPath, "en", "password_reset.txt.tmpl"),
+		[]byte(`{{define "subject"}}Custom subject{{end}}Token: {{.Token}}`), 0o600))
+	renderer := newTemplateRenderer(t, "en", templatesPath)
+
+	mail, err := renderer.Render("en", mailservice.TemplatePasswordReset, tokenMailData)
+	require.NoError(t, err)
+	assert.Equal(t, &mailservice.Mail{Subject: "Custom subject", Text: "Token: abcd"}, mail)
+
+	// the templates that are not overridden are still available
+	mail, err = renderer.Render("en", mailservice.TemplateEmailChange, tokenMailData)
+	require.NoError(t, err)
+	assert.Equal(t, "Confirm your new email", mail.Subject)
+}
+
+func TestRenderUnknownTemplate(t *testing.T) {
+	renderer := newTemplateRenderer(t, "en", "")
+
+	mail, err := renderer.Render("en", "unknown", tokenMailData)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	assert.Nil(t, mail)
+}
@@ -0,0 +1,68 @@
+package oauthservice
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/ditrit/badaas/httperrors"
+	"github.com/ditrit/badaas/persistence/models/dto"
+	"github.com/ditrit/badaas/services/auth/protocols/oauth2"
+	"go.uber.org/zap"
+)
+
+// An error of the token, introspection and revocation endpoints, written as described by RFC 6749 section 5.2
+// instead of the usual badaas errors so that the OAuth 2.0 libraries of the clients understand it
+type OAuthError struct {
+	Status      int
+	Code        string
+	Description string
+}
+
+// Check interface compliance
+var _ httperrors.HTTPError = (*OAuthError)(nil)
+
+// OAuthError constructor, the failed client authentications are answered with a 401 status
+func NewOAuthError(code, description string) *OAuthError {
+	status := http.StatusBadRequest
+	switch code {
+	case oauth2.ErrorInvalidClient:
+		status = http.StatusUnauthorized
+	case oauth2.ErrorServerError:
+		status = http.StatusInternalServerError
+	}
+	return &OAuthError{Status: status, Code: code, Description: description}
+}
+
+// Convert the error to its JSON response
+func (oauthError *OAuthError) ToJSON() string {
+	payload, _ := json.Marshal(&dto.DTOOAuthError{
+		Error:            oauthError.Code,
+		ErrorDescription: oauthError.Description,
+	})
+	return string(payload)
+}
+
+// Implement the Error interface
+func (oauthError *OAuthError) Error() string {
+	return fmt.Sprintf(`OAuthError: %s`, oauthError.ToJSON())
+}
+
+// The errors are the answers to the mistakes of the clients, they are not logged
+func (oauthError *OAuthError) Log() bool {
+	return false
+}
+
+// Write the error to the http response
+func (oauthError *OAuthError) Write(httpResponse http.ResponseWriter, logger *zap.Logger) {
+	httpResponse.Header().Set("Content-Type", "application/json")
+	httpResponse.Header().Set("Cache-Control", "no-store")
+	if oauthError.Status == http.StatusUnauthorized {
+		httpResponse.Header().Set("WWW-Authenticate", `Basic realm="badaas"`)
+	}
+	httpResponse.WriteHeader(oauthError.Status)
+	_, err := httpResponse.Write([]byte(oauthError.ToJSON()))
+	if err != nil && logger != nil {
+		logger.Error("Failed to write an OAuth error", zap.Error(err))
+	}
+}
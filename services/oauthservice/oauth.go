@@ -0,0 +1,435 @@
+package oauthservice
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/ditrit/badaas/configuration"
+	"github.com/ditrit/badaas/httperrors"
+	"github.com/ditrit/badaas/persistence/models"
+	"github.com/ditrit/badaas/persistence/models/dto"
+	"github.com/ditrit/badaas/persistence/repository"
+	"github.com/ditrit/badaas/services/auth/protocols/oauth2"
+	"github.com/ditrit/badaas/services/userservice"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// The permission to manage the OAuth 2.0 clients
+const PermissionClientsManage string = "clients:manage"
+
+// Errors
+var (
+	HERRClientNotFound = httperrors.NewErrorNotFound("client", "no OAuth client has this id")
+	// Returned when the client or the redirect uri of an authorization request is wrong,
+	// the user is not redirected to an uri which may not belong to the client (RFC 6749 section 4.1.2.1)
+	HERRInvalidAuthorizationRequest = httperrors.NewHTTPError(http.StatusBadRequest, "invalid authorization request",
+		"the client or the redirect uri of the authorization request is invalid", nil, false)
+)
+
+// OAuthService makes badaas an OAuth 2.0 authorization server for third-party clients
+type OAuthService interface {
+	// Register a client, return it with its secret, which is not stored and can't be shown again
+	CreateClient(createClientDTO dto.DTOCreateOAuthClient) (*models.OAuthClient, string, httperrors.HTTPError)
+	GetClients() ([]*models.OAuthClient, httperrors.HTTPError)
+	GetClient(clientID uuid.UUID) (*models.OAuthClient, httperrors.HTTPError)
+	// Delete a client and revoke its tokens
+	DeleteClient(clientID uuid.UUID) httperrors.HTTPError
+
+	// Check the authorization request of the user, the code is issued at once if the user already consented to the scopes
+	Authorize(userID uuid.UUID, request dto.DTOOAuthAuthorizationRequest) (*Authorization, httperrors.HTTPError)
+	// Record the decision of the user on the consent screen, return the url the user is redirected to
+	Consent(userID uuid.UUID, request dto.DTOOAuthAuthorizationRequest, approved bool) (string, httperrors.HTTPError)
+
+	// Exchange a grant for tokens
+	Token(credentials oauth2.ClientCredentials, request dto.DTOOAuthTokenRequest) (*dto.DTOOAuthToken, httperrors.HTTPError)
+	// Describe a token to a confidential client (RFC 7662)
+	Introspect(credentials oauth2.ClientCredentials, token string) (*dto.DTOOAuthIntrospection, httperrors.HTTPError)
+	// Revoke a token of the client (RFC 7009), a refresh token revokes its whole grant
+	Revoke(credentials oauth2.ClientCredentials, token string) httperrors.HTTPError
+}
+
+// Check interface compliance
+var _ OAuthService = (*oauthServiceImpl)(nil)
+
+// The result of an authorization request
+type Authorization struct {
+	// The url the user agent is redirected to, with the code or the error, empty if the consent of the user is needed
+	RedirectURL string
+	// The client and the scopes shown on the consent screen
+	Client *models.OAuthClient
+	Scopes []configuration.OAuthScope
+}
+
+// OAuthService implementation
+type oauthServiceImpl struct {
+	logger                           *zap.Logger
+	oauthConfiguration               configuration.OAuthConfiguration
+	oauthClientRepository            repository.CRUDRepository[models.OAuthClient, uuid.UUID]
+	oauthAuthorizationCodeRepository repository.CRUDRepository[models.OAuthAuthorizationCode, uuid.UUID]
+	oauthTokenRepository             repository.CRUDRepository[models.OAuthToken, uuid.UUID]
+	oauthConsentRepository           repository.CRUDRepository[models.OAuthConsent, uuid.UUID]
+	userService                      userservice.UserService
+}
+
+// OAuthService constructor
+func NewOAuthService(
+	logger *zap.Logger,
+	oauthConfiguration configuration.OAuthConfiguration,
+	oauthClientRepository repository.CRUDRepository[models.OAuthClient, uuid.UUID],
+	oauthAuthorizationCodeRepository repository.CRUDRepository[models.OAuthAuthorizationCode, uuid.UUID],
+	oauthTokenRepository repository.CRUDRepository[models.OAuthToken, uuid.UUID],
+	oauthConsentRepository repository.CRUDRepository[models.OAuthConsent, uuid.UUID],
+	userService userservice.UserService,
+) OAuthService {
+	return &oauthServiceImpl{
+		logger:                           logger,
+		oauthConfiguration:               oauthConfiguration,
+		oauthClientRepository:            oauthClientRepository,
+		oauthAuthorizationCodeRepository: oauthAuthorizationCodeRepository,
+		oauthTokenRepository:             oauthTokenRepository,
+		oauthConsentRepository:           oauthConsentRepository,
+		userService:                      userService,
+	}
+}
+
+// Register a client, return it with its secret, which is not stored and can't be shown again
+//
+// The public clients have no secret and can't use the client credentials grant.
+func (oauthService *oauthServiceImpl) CreateClient(createClientDTO dto.DTOCreateOAuthClient) (*models.OAuthClient, string, httperrors.HTTPError) {
+	name := strings.TrimSpace(createClientDTO.Name)
+	if name == "" {
+		return nil, "", invalidClient("the name of the client is required")
+	}
+	grantTypes := oauth2.ParseScope(strings.Join(createClientDTO.GrantTypes, " "))
+	if len(grantTypes) == 0 {
+		return nil, "", invalidClient("the client needs at least one grant type")
+	}
+	for _, grantType := range grantTypes {
+		switch grantType {
+		case oauth2.GrantTypeAuthorizationCode, oauth2.GrantTypeRefreshToken:
+		case oauth2.GrantTypeClientCredentials:
+			if createClientDTO.Public {
+				return nil, "", invalidClient("a public client can't use the client credentials grant")
+			}
+		default:
+			return nil, "", invalidClient("unsupported grant type " + grantType)
+		}
+	}
+	for _, redirectURI := range createClientDTO.RedirectURIs {
+		if !oauth2.IsValidRedirectURI(redirectURI) {
+			return nil, "", invalidClient("invalid redirect uri " + redirectURI)
+		}
+	}
+	client := &models.OAuthClient{
+		Name:         name,
+		RedirectURIs: strings.Join(createClientDTO.RedirectURIs, " "),
+		GrantTypes:   strings.Join(grantTypes, " "),
+	}
+	if client.AllowsGrantType(oauth2.GrantTypeAuthorizationCode) && len(createClientDTO.RedirectURIs) == 0 {
+		return nil, "", invalidClient("the authorization code grant needs a redirect uri")
+	}
+	scopes := oauth2.ParseScope(strings.Join(createClientDTO.Scopes, " "))
+	if !oauth2.IsSubset(scopes, oauthService.getScopeNames()) {
+		return nil, "", invalidClient("the scopes must be declared in the configuration")
+	}
+	client.Scope = oauth2.FormatScope(scopes)
+
+	secret := ""
+	if !createClientDTO.Public {
+		var err error
+		secret, err = oauth2.GenerateToken()
+		if err != nil {
+			return nil, "", httperrors.NewInternalServerError("secret error", "failed to generate the client secret", err)
+		}
+		client.SecretHash = oauth2.HashToken(secret)
+	}
+	herr := oauthService.oauthClientRepository.Create(client)
+	if herr != nil {
+		return nil, "", herr
+	}
+	oauthService.logger.Info("Registered an OAuth client",
+		zap.String("clientID", client.ID.String()), zap.String("name", client.Name))
+	return client, secret, nil
+}
+
+// Return the error of an invalid client registration
+func invalidClient(message string) httperrors.HTTPError {
+	return httperrors.NewHTTPError(http.StatusBadRequest, "invalid client", message, nil, false)
+}
+
+// Return all the clients
+func (oauthService *oauthServiceImpl) GetClients() ([]*models.OAuthClient, httperrors.HTTPError) {
+	return oauthService.oauthClientRepository.GetAll(nil)
+}
+
+// Return a client
+func (oauthService *oauthServiceImpl) GetClient(clientID uuid.UUID) (*models.OAuthClient, httperrors.HTTPError) {
+	clients, herr := oauthService.oauthClientRepository.Find(squirrel.Eq{"id": clientID.String()}, nil, nil)
+	if herr != nil {
+		return nil, herr
+	}
+	if !clients.HasContent {
+		return nil, HERRClientNotFound
+	}
+	return clients.Ressources[0], nil
+}
+
+// Delete a client, its tokens, its codes and the consents of the users
+func (oauthService *oauthServiceImpl) DeleteClient(clientID uuid.UUID) httperrors.HTTPError {
+	client, herr := oauthService.GetClient(clientID)
+	if herr != nil {
+		return herr
+	}
+	condition := squirrel.Eq{"client_id": clientID.String()}
+	tokens, herr := oauthService.oauthTokenRepository.Find(condition, nil, nil)
+	if herr != nil {
+		return herr
+	}
+	for _, token := range tokens.Ressources {
+		herr = oauthService.oauthTokenRepository.Delete(token)
+		if herr != nil {
+			return herr
+		}
+	}
+	codes, herr := oauthService.oauthAuthorizationCodeRepository.Find(condition, nil, nil)
+	if herr != nil {
+		return herr
+	}
+	for _, code := range codes.Ressources {
+		herr = oauthService.oauthAuthorizationCodeRepository.Delete(code)
+		if herr != nil {
+			return herr
+		}
+	}
+	consents, herr := oauthService.oauthConsentRepository.Find(condition, nil, nil)
+	if herr != nil {
+		return herr
+	}
+	for _, consent := range consents.Ressources {
+		herr = oauthService.oauthConsentRepository.Delete(consent)
+		if herr != nil {
+			return herr
+		}
+	}
+	return oauthService.oauthClientRepository.Delete(client)
+}
+
+// Check the authorization request of the user, the code is issued at once if the user already consented to the scopes
+//
+// The errors are sent to the redirect uri of the client, unless the client or the redirect uri is wrong.
+func (oauthService *oauthServiceImpl) Authorize(userID uuid.UUID, request dto.DTOOAuthAuthorizationRequest) (*Authorization, httperrors.HTTPError) {
+	client, redirectURI, herr := oauthService.getClientAndRedirectURI(request)
+	if herr != nil {
+		return nil, herr
+	}
+	scopes, oauthError := oauthService.checkAuthorizationRequest(client, request)
+	if oauthError != nil {
+		redirectURL, herr := errorRedirectURL(redirectURI, request.State, oauthError)
+		return &Authorization{RedirectURL: redirectURL}, herr
+	}
+	consent, herr := oauthService.getConsent(userID, client.ID)
+	if herr != nil {
+		return nil, herr
+	}
+	if consent != nil && oauth2.IsSubset(scopes, oauth2.ParseScope(consent.Scope)) {
+		redirectURL, herr := oauthService.issueCode(userID, client, redirectURI, scopes, request)
+		return &Authorization{RedirectURL: redirectURL}, herr
+	}
+	return &Authorization{Client: client, Scopes: oauthService.describeScopes(scopes)}, nil
+}
+
+// Record the decision of the user on the consent screen, return the url the user is redirected to
+//
+// The consent is remembered, the user is not asked again for the same scopes.
+func (oauthService *oauthServiceImpl) Consent(userID uuid.UUID, request dto.DTOOAuthAuthorizationRequest, approved bool) (string, httperrors.HTTPError) {
+	client, redirectURI, herr := oauthService.getClientAndRedirectURI(request)
+	if herr != nil {
+		return "", herr
+	}
+	scopes, oauthError := oauthService.checkAuthorizationRequest(client, request)
+	if oauthError != nil {
+		return errorRedirectURL(redirectURI, request.State, oauthError)
+	}
+	if !approved {
+		return errorRedirectURL(redirectURI, request.State,
+			NewOAuthError(oauth2.ErrorAccessDenied, "the user denied the authorization"))
+	}
+	consent, herr := oauthService.getConsent(userID, client.ID)
+	if herr != nil {
+		return "", herr
+	}
+	if consent == nil {
+		herr = oauthService.oauthConsentRepository.Create(&models.OAuthConsent{
+			UserID:   userID,
+			ClientID: client.ID,
+			Scope:    oauth2.FormatScope(scopes),
+		})
+	} else {
+		consent.Scope = oauth2.FormatScope(append(oauth2.ParseScope(consent.Scope), scopes...))
+		herr = oauthService.oauthConsentRepository.Save(consent)
+	}
+	if herr != nil {
+		return "", herr
+	}
+	return oauthService.issueCode(userID, client, redirectURI, scopes, request)
+}
+
+// Return the client of the request and the redirect uri the user is sent back to
+func (oauthService *oauthServiceImpl) getClientAndRedirectURI(
+	request dto.DTOOAuthAuthorizationRequest,
+) (*models.OAuthClient, string, httperrors.HTTPError) {
+	clientID, err := uuid.Parse(request.ClientID)
+	if err != nil {
+		return nil, "", HERRInvalidAuthorizationRequest
+	}
+	client, herr := oauthService.GetClient(clientID)
+	if herr == HERRClientNotFound {
+		return nil, "", HERRInvalidAuthorizationRequest
+	}
+	if herr != nil {
+		return nil, "", herr
+	}
+	redirectURIs := client.GetRedirectURIs()
+	if request.RedirectURI == "" {
+		// the redirect uri can be omitted if the client registered a single one (RFC 6749 section 3.1.2.3)
+		if len(redirectURIs) != 1 {
+			return nil, "", HERRInvalidAuthorizationRequest
+		}
+		return client, redirectURIs[0], nil
+	}
+	if !oauth2.RedirectURIMatches(redirectURIs, request.RedirectURI) {
+		return nil, "", HERRInvalidAuthorizationRequest
+	}
+	return client, request.RedirectURI, nil
+}
+
+// Check the parameters of the authorization request, return the requested scopes
+//
+// The client's scopes are requested if the scope is omitted. The public clients must send a S256 code challenge.
+func (oauthService *oauthServiceImpl) checkAuthorizationRequest(
+	client *models.OAuthClient,
+	request dto.DTOOAuthAuthorizationRequest,
+) ([]string, *OAuthError) {
+	if request.ResponseType != oauth2.ResponseTypeCode {
+		return nil, NewOAuthError(oauth2.ErrorUnsupportedResponseType, "only the code response type is supported")
+	}
+	if !client.AllowsGrantType(oauth2.GrantTypeAuthorizationCode) {
+		return nil, NewOAuthError(oauth2.ErrorUnauthorizedClient, "the client can't use the authorization code grant")
+	}
+	scopes, oauthError := getRequestedScopes(client, request.Scope)
+	if oauthError != nil {
+		return nil, oauthError
+	}
+	if request.CodeChallenge == "" {
+		if client.IsPublic() {
+			return nil, NewOAuthError(oauth2.ErrorInvalidRequest, "a public client must send a code challenge")
+		}
+		return scopes, nil
+	}
+	if request.CodeChallengeMethod != oauth2.CodeChallengeMethodS256 {
+		return nil, NewOAuthError(oauth2.ErrorInvalidRequest, "only the S256 code challenge method is supported")
+	}
+	if !oauth2.IsValidCodeChallenge(request.CodeChallenge) {
+		return nil, NewOAuthError(oauth2.ErrorInvalidRequest, "invalid code challenge")
+	}
+	return scopes, nil
+}
+
+// Return the requested scopes, all the scopes of the client if the scope is omitted
+func getRequestedScopes(client *models.OAuthClient, scope string) ([]string, *OAuthError) {
+	clientScopes := oauth2.ParseScope(client.Scope)
+	scopes := oauth2.ParseScope(scope)
+	if len(scopes) == 0 {
+		return clientScopes, nil
+	}
+	if !oauth2.IsSubset(scopes, clientScopes) {
+		return nil, NewOAuthError(oauth2.ErrorInvalidScope, "the client can't ask these scopes")
+	}
+	return scopes, nil
+}
+
+// Return the consent of the user to the client, or nil
+func (oauthService *oauthServiceImpl) getConsent(userID, clientID uuid.UUID) (*models.OAuthConsent, httperrors.HTTPError) {
+	consents, herr := oauthService.oauthConsentRepository.Find(
+		squirrel.Eq{"user_id": userID.String(), "client_id": clientID.String()}, nil, nil)
+	if herr != nil {
+		return nil, herr
+	}
+	if !consents.HasContent {
+		return nil, nil
+	}
+	return consents.Ressources[0], nil
+}
+
+// Create an authorization code and return the redirect url carrying it
+func (oauthService *oauthServiceImpl) issueCode(
+	userID uuid.UUID,
+	client *models.OAuthClient,
+	redirectURI string,
+	scopes []string,
+	request dto.DTOOAuthAuthorizationRequest,
+) (string, httperrors.HTTPError) {
+	code, err := oauth2.GenerateToken()
+	if err != nil {
+		return "", httperrors.NewInternalServerError("code error", "failed to generate the authorization code", err)
+	}
+	herr := oauthService.oauthAuthorizationCodeRepository.Create(&models.OAuthAuthorizationCode{
+		ClientID:             client.ID,
+		UserID:               userID,
+		CodeHash:             oauth2.HashToken(code),
+		GrantID:              uuid.New(),
+		RedirectURI:          redirectURI,
+		RedirectURIRequested: request.RedirectURI != "",
+		Scope:                oauth2.FormatScope(scopes),
+		CodeChallenge:        request.CodeChallenge,
+		ExpiresAt:            time.Now().Add(oauthService.oauthConfiguration.GetCodeDuration()),
+	})
+	if herr != nil {
+		return "", herr
+	}
+	return redirectURL(redirectURI, url.Values{"code": {code}, "state": {request.State}})
+}
+
+// Return the redirect url carrying the error of the authorization request (RFC 6749 section 4.1.2.1)
+func errorRedirectURL(redirectURI, state string, oauthError *OAuthError) (string, httperrors.HTTPError) {
+	return redirectURL(redirectURI, url.Values{
+		"error":             {oauthError.Code},
+		"error_description": {oauthError.Description},
+		"state":             {state},
+	})
+}
+
+// Add the parameters to the redirect uri
+func redirectURL(redirectURI string, parameters url.Values) (string, httperrors.HTTPError) {
+	redirectURL, err := oauth2.AddQueryParameters(redirectURI, parameters)
+	if err != nil {
+		return "", httperrors.NewInternalServerError("redirect error", "failed to build the redirect url", err)
+	}
+	return redirectURL, nil
+}
+
+// Return the names of the configured scopes
+func (oauthService *oauthServiceImpl) getScopeNames() []string {
+	names := []string{}
+	for _, scope := range oauthService.oauthConfiguration.GetScopes() {
+		names = append(names, scope.Name)
+	}
+	return names
+}
+
+// Return the configured scopes with their description
+func (oauthService *oauthServiceImpl) describeScopes(names []string) []configuration.OAuthScope {
+	descriptions := map[string]string{}
+	for _, scope := range oauthService.oauthConfiguration.GetScopes() {
+		descriptions[scope.Name] = scope.Description
+	}
+	scopes := make([]configuration.OAuthScope, 0, len(names))
+	for _, name := range names {
+		scopes = append(scopes, configuration.OAuthScope{Name: name, Description: descriptions[name]})
+	}
+	return scopes
+}
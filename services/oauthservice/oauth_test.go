@@ -0,0 +1,270 @@
+package oauthservice_test
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/ditrit/badaas/configuration"
+	mocksConfiguration "github.com/ditrit/badaas/mocks/configuration"
+	mocksRepository "github.com/ditrit/badaas/mocks/persistence/repository"
+	mocksUserService "github.com/ditrit/badaas/mocks/services/userservice"
+	"github.com/ditrit/badaas/persistence/models"
+	"github.com/ditrit/badaas/persistence/models/dto"
+	"github.com/ditrit/badaas/persistence/pagination"
+	"github.com/ditrit/badaas/services/auth/protocols/oauth2"
+	"github.com/ditrit/badaas/services/oauthservice"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const redirectURI = "https://app.example.com/callback"
+
+type testSetup struct {
+	clientRepository  *mocksRepository.CRUDRepository[models.OAuthClient, uuid.UUID]
+	codeRepository    *mocksRepository.CRUDRepository[models.OAuthAuthorizationCode, uuid.UUID]
+	tokenRepository   *mocksRepository.CRUDRepository[models.OAuthToken, uuid.UUID]
+	consentRepository *mocksRepository.CRUDRepository[models.OAuthConsent, uuid.UUID]
+	userService       *mocksUserService.UserService
+	service           oauthservice.OAuthService
+}
+
+func setupTest(t *testing.T) *testSetup {
+	oauthConfiguration := mocksConfiguration.NewOAuthConfiguration(t)
+	oauthConfiguration.On("GetScopes").Return([]configuration.OAuthScope{
+		{Name: "profile", Description: "Read your profile"},
+		{Name: "write", Description: "Modify your data"},
+	}).Maybe()
+	oauthConfiguration.On("GetCodeDuration").Return(time.Minute).Maybe()
+	oauthConfiguration.On("GetAccessTokenDuration").Return(time.Hour).Maybe()
+	oauthConfiguration.On("GetRefreshTokenDuration").Return(24 * time.Hour).Maybe()
+
+	setup := &testSetup{
+		clientRepository:  mocksRepository.NewCRUDRepository[models.OAuthClient, uuid.UUID](t),
+		codeRepository:    mocksRepository.NewCRUDRepository[models.OAuthAuthorizationCode, uuid.UUID](t),
+		tokenRepository:   mocksRepository.NewCRUDRepository[models.OAuthToken, uuid.UUID](t),
+		consentRepository: mocksRepository.NewCRUDRepository[models.OAuthConsent, uuid.UUID](t),
+		userService:       mocksUserService.NewUserService(t),
+	}
+	setup.service = oauthservice.NewOAuthService(zap.NewNop(), oauthConfiguration, setup.clientRepository,
+		setup.codeRepository, setup.tokenRepository, setup.consentRepository, setup.userService)
+	return setup
+}
+
+// Register the client in the mocked repository, return its secret
+func (setup *testSetup) withClient(client *models.OAuthClient, public bool) string {
+	client.ID = uuid.New()
+	secret := ""
+	if !public {
+		secret, _ = oauth2.GenerateToken()
+		client.SecretHash = oauth2.HashToken(secret)
+	}
+	setup.clientRepository.On("Find", squirrel.Eq{"id": client.ID.String()}, nil, nil).
+		Return(pagination.NewPage([]*models.OAuthClient{client}, 1, 10, 1), nil).Maybe()
+	return secret
+}
+
+func (setup *testSetup) withConsent(userID, clientID uuid.UUID, consents ...*models.OAuthConsent) {
+	setup.consentRepository.On("Find", squirrel.Eq{"user_id": userID.String(), "client_id": clientID.String()}, nil, nil).
+		Return(pagination.NewPage(consents, 1, 10, uint(len(consents))), nil)
+}
+
+func newChallenge(t *testing.T) (string, string) {
+	verifier, err := oauth2.GenerateToken()
+	require.NoError(t, err)
+	hash := sha256.Sum256([]byte(verifier))
+	return verifier, base64.RawURLEncoding.EncodeToString(hash[:])
+}
+
+func webClient() *models.OAuthClient {
+	return &models.OAuthClient{
+		Name:         "web app",
+		RedirectURIs: redirectURI,
+		GrantTypes:   "authorization_code refresh_token",
+		Scope:        "profile write",
+	}
+}
+
+func TestCreateClient(t *testing.T) {
+	setup := setupTest(t)
+	setup.clientRepository.On("Create", mock.Anything).Return(nil)
+
+	client, secret, herr := setup.service.CreateClient(dto.DTOCreateOAuthClient{
+		Name:         "web app",
+		RedirectURIs: []string{redirectURI},
+		GrantTypes:   []string{"refresh_token", "authorization_code"},
+		Scopes:       []string{"write", "profile"},
+	})
+	require.Nil(t, herr)
+	assert.NotEmpty(t, secret)
+	assert.True(t, oauth2.TokenMatches(secret, client.SecretHash))
+	assert.Equal(t, "authorization_code refresh_token", client.GrantTypes)
+	assert.Equal(t, "profile write", client.Scope)
+
+	client, secret, herr = setup.service.CreateClient(dto.DTOCreateOAuthClient{
+		Name:         "mobile app",
+		Public:       true,
+		RedirectURIs: []string{"com.example.app:/callback"},
+		GrantTypes:   []string{"authorization_code"},
+	})
+	require.Nil(t, herr)
+	assert.Empty(t, secret)
+	assert.True(t, client.IsPublic())
+}
+
+func TestCreateClientInvalid(t *testing.T) {
+	setup := setupTest(t)
+	testCases := []struct {
+		name   string
+		client dto.DTOCreateOAuthClient
+	}{
+		{"no name", dto.DTOCreateOAuthClient{GrantTypes: []string{"client_credentials"}}},
+		{"unknown grant type", dto.DTOCreateOAuthClient{Name: "app", GrantTypes: []string{"password"}}},
+		{"public client credentials", dto.DTOCreateOAuthClient{Name: "app", Public: true, GrantTypes: []string{"client_credentials"}}},
+		{"no redirect uri", dto.DTOCreateOAuthClient{Name: "app", GrantTypes: []string{"authorization_code"}}},
+		{"relative redirect uri", dto.DTOCreateOAuthClient{Name: "app", GrantTypes: []string{"authorization_code"},
+			RedirectURIs: []string{"/callback"}}},
+		{"unknown scope", dto.DTOCreateOAuthClient{Name: "app", GrantTypes: []string{"client_credentials"},
+			Scopes: []string{"admin"}}},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, _, herr := setup.service.CreateClient(testCase.client)
+			require.NotNil(t, herr)
+			assert.Contains(t, herr.Error(), "invalid client")
+		})
+	}
+}
+
+func TestAuthorizeUnknownClientIsNotRedirected(t *testing.T) {
+	setup := setupTest(t)
+	client := webClient()
+	setup.withClient(client, false)
+
+	_, herr := setup.service.Authorize(uuid.New(), dto.DTOOAuthAuthorizationRequest{
+		ResponseType: "code", ClientID: "not-a-uuid",
+	})
+	assert.Equal(t, oauthservice.HERRInvalidAuthorizationRequest, herr)
+
+	_, herr = setup.service.Authorize(uuid.New(), dto.DTOOAuthAuthorizationRequest{
+		ResponseType: "code", ClientID: client.ID.String(), RedirectURI: "https://evil.example.com/callback",
+	})
+	assert.Equal(t, oauthservice.HERRInvalidAuthorizationRequest, herr)
+}
+
+func TestAuthorizeErrorIsRedirected(t *testing.T) {
+	setup := setupTest(t)
+	client := webClient()
+	setup.withClient(client, true)
+
+	authorization, herr := setup.service.Authorize(uuid.New(), dto.DTOOAuthAuthorizationRequest{
+		ResponseType: "code", ClientID: client.ID.String(), State: "xyz",
+	})
+	require.Nil(t, herr)
+	redirectURL, err := url.Parse(authorization.RedirectURL)
+	require.NoError(t, err)
+	assert.Equal(t, "invalid_request", redirectURL.Query().Get("error"))
+	assert.Equal(t, "xyz", redirectURL.Query().Get("state"))
+
+	authorization, herr = setup.service.Authorize(uuid.New(), dto.DTOOAuthAuthorizationRequest{
+		ResponseType: "code", ClientID: client.ID.String(), Scope: "admin",
+	})
+	require.Nil(t, herr)
+	assert.Contains(t, authorization.RedirectURL, "error=invalid_scope")
+}
+
+func TestAuthorizeAsksConsent(t *testing.T) {
+	setup := setupTest(t)
+	client := webClient()
+	setup.withClient(client, false)
+	userID := uuid.New()
+	setup.withConsent(userID, client.ID, &models.OAuthConsent{UserID: userID, ClientID: client.ID, Scope: "profile"})
+
+	authorization, herr := setup.service.Authorize(userID, dto.DTOOAuthAuthorizationRequest{
+		ResponseType: "code", ClientID: client.ID.String(), Scope: "profile write",
+	})
+	require.Nil(t, herr)
+	assert.Empty(t, authorization.RedirectURL)
+	assert.Equal(t, client, authorization.Client)
+	assert.Equal(t, []configuration.OAuthScope{
+		{Name: "profile", Description: "Read your profile"},
+		{Name: "write", Description: "Modify your data"},
+	}, authorization.Scopes)
+}
+
+func TestAuthorizeWithConsentIssuesCode(t *testing.T) {
+	setup := setupTest(t)
+	client := webClient()
+	setup.withClient(client, true)
+	userID := uuid.New()
+	setup.withConsent(userID, client.ID, &models.OAuthConsent{UserID: userID, ClientID: client.ID, Scope: "profile write"})
+	_, challenge := newChallenge(t)
+	var code *models.OAuthAuthorizationCode
+	setup.codeRepository.On("Create", mock.Anything).Run(func(args mock.Arguments) {
+		code = args.Get(0).(*models.OAuthAuthorizationCode)
+	}).Return(nil)
+
+	authorization, herr := setup.service.Authorize(userID, dto.DTOOAuthAuthorizationRequest{
+		ResponseType:        "code",
+		ClientID:            client.ID.String(),
+		Scope:               "profile",
+		State:               "xyz",
+		CodeChallenge:       challenge,
+		CodeChallengeMethod: "S256",
+	})
+	require.Nil(t, herr)
+	redirectURL, err := url.Parse(authorization.RedirectURL)
+	require.NoError(t, err)
+	assert.Equal(t, "xyz", redirectURL.Query().Get("state"))
+	assert.True(t, oauth2.TokenMatches(redirectURL.Query().Get("code"), code.CodeHash))
+	assert.Equal(t, "profile", code.Scope)
+	assert.Equal(t, challenge, code.CodeChallenge)
+	assert.Equal(t, redirectURI, code.RedirectURI)
+	assert.False(t, code.RedirectURIRequested)
+}
+
+func TestConsent(t *testing.T) {
+	setup := setupTest(t)
+	client := webClient()
+	setup.withClient(client, false)
+	userID := uuid.New()
+	request := dto.DTOOAuthAuthorizationRequest{ResponseType: "code", ClientID: client.ID.String(), Scope: "write"}
+
+	redirectURL, herr := setup.service.Consent(userID, request, false)
+	require.Nil(t, herr)
+	assert.Contains(t, redirectURL, "error=access_denied")
+
+	consent := &models.OAuthConsent{UserID: userID, ClientID: client.ID, Scope: "profile"}
+	setup.withConsent(userID, client.ID, consent)
+	setup.consentRepository.On("Save", consent).Return(nil)
+	setup.codeRepository.On("Create", mock.Anything).Return(nil)
+
+	redirectURL, herr = setup.service.Consent(userID, request, true)
+	require.Nil(t, herr)
+	assert.Contains(t, redirectURL, "code=")
+	assert.Equal(t, "profile write", consent.Scope)
+}
+
+func TestDeleteClientRevokesTokens(t *testing.T) {
+	setup := setupTest(t)
+	client := webClient()
+	setup.withClient(client, false)
+	token := &models.OAuthToken{ClientID: client.ID}
+	condition := squirrel.Eq{"client_id": client.ID.String()}
+	setup.tokenRepository.On("Find", condition, nil, nil).
+		Return(pagination.NewPage([]*models.OAuthToken{token}, 1, 10, 1), nil)
+	setup.tokenRepository.On("Delete", token).Return(nil)
+	setup.codeRepository.On("Find", condition, nil, nil).
+		Return(pagination.NewPage([]*models.OAuthAuthorizationCode{}, 1, 10, 0), nil)
+	setup.consentRepository.On("Find", condition, nil, nil).
+		Return(pagination.NewPage([]*models.OAuthConsent{}, 1, 10, 0), nil)
+	setup.clientRepository.On("Delete", client).Return(nil)
+
+	assert.Nil(t, setup.service.DeleteClient(client.ID))
+}
@@ -0,0 +1,413 @@
+package oauthservice
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/ditrit/badaas/httperrors"
+	"github.com/ditrit/badaas/persistence/models"
+	"github.com/ditrit/badaas/persistence/models/dto"
+	"github.com/ditrit/badaas/services/auth/protocols/oauth2"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Exchange a grant for tokens
+//
+// An authorization code or a refresh token presented twice revokes all the tokens of its grant,
+// as it was probably stolen (RFC 6819 section 5.2.1.1 and RFC 6749 section 10.4).
+func (oauthService *oauthServiceImpl) Token(
+	credentials oauth2.ClientCredentials,
+	request dto.DTOOAuthTokenRequest,
+) (*dto.DTOOAuthToken, httperrors.HTTPError) {
+	client, herr := oauthService.authenticateClient(credentials)
+	if herr != nil {
+		return nil, herr
+	}
+	switch request.GrantType {
+	case oauth2.GrantTypeAuthorizationCode, oauth2.GrantTypeRefreshToken, oauth2.GrantTypeClientCredentials:
+	case "":
+		return nil, NewOAuthError(oauth2.ErrorInvalidRequest, "the grant type is required")
+	default:
+		return nil, NewOAuthError(oauth2.ErrorUnsupportedGrantType, "unsupported grant type")
+	}
+	if !client.AllowsGrantType(request.GrantType) {
+		return nil, NewOAuthError(oauth2.ErrorUnauthorizedClient, "the client can't use this grant type")
+	}
+	switch request.GrantType {
+	case oauth2.GrantTypeAuthorizationCode:
+		return oauthService.exchangeCode(client, request)
+	case oauth2.GrantTypeRefreshToken:
+		return oauthService.refresh(client, request)
+	default:
+		return oauthService.clientCredentials(client, request)
+	}
+}
+
+// Exchange an authorization code
+func (oauthService *oauthServiceImpl) exchangeCode(
+	client *models.OAuthClient,
+	request dto.DTOOAuthTokenRequest,
+) (*dto.DTOOAuthToken, httperrors.HTTPError) {
+	if request.Code == "" {
+		return nil, NewOAuthError(oauth2.ErrorInvalidRequest, "the code is required")
+	}
+	codes, herr := oauthService.oauthAuthorizationCodeRepository.Find(
+		squirrel.Eq{"code_hash": oauth2.HashToken(request.Code)}, nil, nil)
+	if herr != nil {
+		return nil, herr
+	}
+	if !codes.HasContent {
+		return nil, invalidGrant()
+	}
+	code := codes.Ressources[0]
+	if code.ClientID != client.ID {
+		return nil, invalidGrant()
+	}
+	if code.Used {
+		return nil, oauthService.revokeReusedGrant(client, code.GrantID, "authorization code")
+	}
+	if code.IsExpired() || !redirectURIMatches(code, request.RedirectURI) {
+		return nil, invalidGrant()
+	}
+	if code.CodeChallenge != "" || request.CodeVerifier != "" {
+		if !oauth2.VerifyCodeChallenge(request.CodeVerifier, code.CodeChallenge) {
+			return nil, invalidGrant()
+		}
+	}
+	// a code can only be used once, even by concurrent requests
+	updated, herr := oauthService.oauthAuthorizationCodeRepository.UpdateColumns(
+		squirrel.Eq{"id": code.ID.String(), "used": false},
+		map[string]any{"used": true, "updated_at": time.Now()},
+	)
+	if herr != nil {
+		return nil, herr
+	}
+	if updated == 0 {
+		return nil, oauthService.revokeReusedGrant(client, code.GrantID, "authorization code")
+	}
+	code.Used = true
+	user, herr := oauthService.userService.GetUserByID(code.UserID)
+	if herr != nil {
+		if isNotFound(herr) {
+			return nil, invalidGrant()
+		}
+		return nil, herr
+	}
+	if user.Disabled {
+		return nil, invalidGrant()
+	}
+	return oauthService.issueTokens(client, &code.UserID, code.GrantID, code.Scope,
+		client.AllowsGrantType(oauth2.GrantTypeRefreshToken))
+}
+
+// Exchange a refresh token, the refresh token is rotated
+func (oauthService *oauthServiceImpl) refresh(
+	client *models.OAuthClient,
+	request dto.DTOOAuthTokenRequest,
+) (*dto.DTOOAuthToken, httperrors.HTTPError) {
+	if request.RefreshToken == "" {
+		return nil, NewOAuthError(oauth2.ErrorInvalidRequest, "the refresh token is required")
+	}
+	token, herr := oauthService.getToken(request.RefreshToken)
+	if herr != nil {
+		return nil, herr
+	}
+	if token == nil || token.Type != models.OAuthRefreshToken || token.ClientID != client.ID {
+		return nil, invalidGrant()
+	}
+	if token.RotatedAt != nil {
+		return nil, oauthService.revokeReusedGrant(client, token.GrantID, "refresh token")
+	}
+	if token.IsExpired() || token.UserID == nil {
+		return nil, invalidGrant()
+	}
+	scope := token.Scope
+	if request.Scope != "" {
+		// the client can narrow the scope of the new access token (RFC 6749 section 6)
+		scopes := oauth2.ParseScope(request.Scope)
+		if !oauth2.IsSubset(scopes, oauth2.ParseScope(token.Scope)) {
+			return nil, NewOAuthError(oauth2.ErrorInvalidScope, "the scope exceeds the scope of the grant")
+		}
+		scope = oauth2.FormatScope(scopes)
+	}
+	user, herr := oauthService.userService.GetUserByID(*token.UserID)
+	if herr != nil {
+		if isNotFound(herr) {
+			return nil, invalidGrant()
+		}
+		return nil, herr
+	}
+	if user.Disabled {
+		return nil, invalidGrant()
+	}
+	// a refresh token can only be rotated once, even by concurrent requests
+	now := time.Now()
+	updated, herr := oauthService.oauthTokenRepository.UpdateColumns(
+		squirrel.Eq{"id": token.ID.String(), "rotated_at": nil},
+		map[string]any{"rotated_at": now, "updated_at": now},
+	)
+	if herr != nil {
+		return nil, herr
+	}
+	if updated == 0 {
+		return nil, oauthService.revokeReusedGrant(client, token.GrantID, "refresh token")
+	}
+	token.RotatedAt = &now
+	response, herr := oauthService.issueTokens(client, token.UserID, token.GrantID, scope, false)
+	if herr != nil {
+		return nil, herr
+	}
+	// the new refresh token keeps the scope of the grant, even if the access token is narrowed
+	response.RefreshToken, herr = oauthService.createToken(client, token.UserID, token.GrantID,
+		models.OAuthRefreshToken, token.Scope, oauthService.oauthConfiguration.GetRefreshTokenDuration())
+	if herr != nil {
+		return nil, herr
+	}
+	return response, nil
+}
+
+// Issue an access token to a confidential client acting on its own behalf
+func (oauthService *oauthServiceImpl) clientCredentials(
+	client *models.OAuthClient,
+	request dto.DTOOAuthTokenRequest,
+) (*dto.DTOOAuthToken, httperrors.HTTPError) {
+	if client.IsPublic() {
+		return nil, NewOAuthError(oauth2.ErrorUnauthorizedClient, "a public client can't use the client credentials grant")
+	}
+	scopes, oauthError := getRequestedScopes(client, request.Scope)
+	if oauthError != nil {
+		return nil, oauthError
+	}
+	return oauthService.issueTokens(client, nil, uuid.New(), oauth2.FormatScope(scopes), false)
+}
+
+// Create an access token, and a refresh token if asked, in the grant
+func (oauthService *oauthServiceImpl) issueTokens(
+	client *models.OAuthClient,
+	userID *uuid.UUID,
+	grantID uuid.UUID,
+	scope string,
+	withRefreshToken bool,
+) (*dto.DTOOAuthToken, httperrors.HTTPError) {
+	accessTokenDuration := oauthService.oauthConfiguration.GetAccessTokenDuration()
+	accessToken, herr := oauthService.createToken(client, userID, grantID, models.OAuthAccessToken,
+		scope, accessTokenDuration)
+	if herr != nil {
+		return nil, herr
+	}
+	response := &dto.DTOOAuthToken{
+		AccessToken: accessToken,
+		TokenType:   oauth2.TokenTypeBearer,
+		ExpiresIn:   int64(accessTokenDuration.Seconds()),
+		Scope:       scope,
+	}
+	if withRefreshToken {
+		response.RefreshToken, herr = oauthService.createToken(client, userID, grantID, models.OAuthRefreshToken,
+			scope, oauthService.oauthConfiguration.GetRefreshTokenDuration())
+		if herr != nil {
+			return nil, herr
+		}
+	}
+	return response, nil
+}
+
+// Create a token and return its value, only its hash is stored
+func (oauthService *oauthServiceImpl) createToken(
+	client *models.OAuthClient,
+	userID *uuid.UUID,
+	grantID uuid.UUID,
+	tokenType, scope string,
+	duration time.Duration,
+) (string, httperrors.HTTPError) {
+	value, err := oauth2.GenerateToken()
+	if err != nil {
+		return "", httperrors.NewInternalServerError("token error", "failed to generate the token", err)
+	}
+	herr := oauthService.oauthTokenRepository.Create(&models.OAuthToken{
+		ClientID:  client.ID,
+		UserID:    userID,
+		TokenHash: oauth2.HashToken(value),
+		Type:      tokenType,
+		Scope:     scope,
+		GrantID:   grantID,
+		ExpiresAt: time.Now().Add(duration),
+	})
+	if herr != nil {
+		return "", herr
+	}
+	return value, nil
+}
+
+// Describe a token to a confidential client (RFC 7662)
+//
+// The unknown, expired and rotated tokens, and the tokens of the disabled users are inactive.
+func (oauthService *oauthServiceImpl) Introspect(
+	credentials oauth2.ClientCredentials,
+	tokenValue string,
+) (*dto.DTOOAuthIntrospection, httperrors.HTTPError) {
+	client, herr := oauthService.authenticateClient(credentials)
+	if herr != nil {
+		return nil, herr
+	}
+	if client.IsPublic() {
+		return nil, NewOAuthError(oauth2.ErrorInvalidClient, "only the confidential clients can introspect the tokens")
+	}
+	if tokenValue == "" {
+		return nil, NewOAuthError(oauth2.ErrorInvalidRequest, "the token is required")
+	}
+	inactive := &dto.DTOOAuthIntrospection{Active: false}
+	token, herr := oauthService.getToken(tokenValue)
+	if herr != nil {
+		return nil, herr
+	}
+	if token == nil || token.IsExpired() || token.RotatedAt != nil {
+		return inactive, nil
+	}
+	introspection := &dto.DTOOAuthIntrospection{
+		Active:    true,
+		Scope:     token.Scope,
+		ClientID:  token.ClientID.String(),
+		ExpiresAt: token.ExpiresAt.Unix(),
+		IssuedAt:  token.CreatedAt.Unix(),
+	}
+	if token.Type == models.OAuthAccessToken {
+		introspection.TokenType = oauth2.TokenTypeBearer
+	}
+	if token.UserID != nil {
+		user, herr := oauthService.userService.GetUserByID(*token.UserID)
+		if herr != nil {
+			if isNotFound(herr) {
+				return inactive, nil
+			}
+			return nil, herr
+		}
+		if user.Disabled {
+			return inactive, nil
+		}
+		introspection.Username = user.Username
+		introspection.Subject = user.ID.String()
+	}
+	return introspection, nil
+}
+
+// Revoke a token of the client (RFC 7009), a refresh token revokes its whole grant
+//
+// The unknown tokens and the tokens of the other clients are ignored, the revocation succeeds anyway.
+func (oauthService *oauthServiceImpl) Revoke(credentials oauth2.ClientCredentials, tokenValue string) httperrors.HTTPError {
+	client, herr := oauthService.authenticateClient(credentials)
+	if herr != nil {
+		return herr
+	}
+	if tokenValue == "" {
+		return NewOAuthError(oauth2.ErrorInvalidRequest, "the token is required")
+	}
+	token, herr := oauthService.getToken(tokenValue)
+	if herr != nil {
+		return herr
+	}
+	if token == nil || token.ClientID != client.ID {
+		return nil
+	}
+	if token.Type == models.OAuthRefreshToken {
+		return oauthService.revokeGrant(token.GrantID)
+	}
+	return oauthService.oauthTokenRepository.Delete(token)
+}
+
+// Authenticate the client of a request to the token, introspection or revocation endpoints
+//
+// The public clients only send their id, the confidential clients must send their secret.
+func (oauthService *oauthServiceImpl) authenticateClient(credentials oauth2.ClientCredentials) (*models.OAuthClient, httperrors.HTTPError) {
+	clientID, err := uuid.Parse(credentials.ClientID)
+	if err != nil {
+		return nil, invalidClientAuthentication()
+	}
+	client, herr := oauthService.GetClient(clientID)
+	if herr == HERRClientNotFound {
+		return nil, invalidClientAuthentication()
+	}
+	if herr != nil {
+		return nil, herr
+	}
+	if client.IsPublic() {
+		if credentials.ClientSecret != "" {
+			return nil, invalidClientAuthentication()
+		}
+		return client, nil
+	}
+	if !oauth2.TokenMatches(credentials.ClientSecret, client.SecretHash) {
+		return nil, invalidClientAuthentication()
+	}
+	return client, nil
+}
+
+// Return the token with this value, or nil
+func (oauthService *oauthServiceImpl) getToken(value string) (*models.OAuthToken, httperrors.HTTPError) {
+	tokens, herr := oauthService.oauthTokenRepository.Find(
+		squirrel.Eq{"token_hash": oauth2.HashToken(value)}, nil, nil)
+	if herr != nil {
+		return nil, herr
+	}
+	if !tokens.HasContent {
+		return nil, nil
+	}
+	return tokens.Ressources[0], nil
+}
+
+// Delete all the tokens of a grant
+func (oauthService *oauthServiceImpl) revokeGrant(grantID uuid.UUID) httperrors.HTTPError {
+	tokens, herr := oauthService.oauthTokenRepository.Find(squirrel.Eq{"grant_id": grantID.String()}, nil, nil)
+	if herr != nil {
+		return herr
+	}
+	for _, token := range tokens.Ressources {
+		herr = oauthService.oauthTokenRepository.Delete(token)
+		if herr != nil {
+			return herr
+		}
+	}
+	return nil
+}
+
+// Revoke the grant of an authorization code or a refresh token presented twice, and return the error to answer
+func (oauthService *oauthServiceImpl) revokeReusedGrant(
+	client *models.OAuthClient,
+	grantID uuid.UUID,
+	credential string,
+) httperrors.HTTPError {
+	oauthService.logger.Warn("An OAuth "+credential+" was used twice, revoking its grant",
+		zap.String("clientID", client.ID.String()), zap.String("grantID", grantID.String()))
+	herr := oauthService.revokeGrant(grantID)
+	if herr != nil {
+		return herr
+	}
+	return invalidGrant()
+}
+
+// Return true if the redirect uri of the token request is the one of the authorization request
+//
+// It must be given again if the authorization request had one (RFC 6749 section 4.1.3).
+func redirectURIMatches(code *models.OAuthAuthorizationCode, redirectURI string) bool {
+	if redirectURI == "" {
+		return !code.RedirectURIRequested
+	}
+	return redirectURI == code.RedirectURI
+}
+
+// Return the error of a wrong, expired or revoked grant
+func invalidGrant() *OAuthError {
+	return NewOAuthError(oauth2.ErrorInvalidGrant, "the grant is invalid, expired or revoked")
+}
+
+// Return the error of a failed client authentication
+func invalidClientAuthentication() *OAuthError {
+	return NewOAuthError(oauth2.ErrorInvalidClient, "the client authentication failed")
+}
+
+// Return true if the error is a not found error
+func isNotFound(herr httperrors.HTTPError) bool {
+	impl, ok := herr.(*httperrors.HTTPErrorImpl)
+	return ok && impl.Status == http.StatusNotFound
+}
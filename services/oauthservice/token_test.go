@@ -0,0 +1,367 @@
+package oauthservice_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/ditrit/badaas/persistence/models"
+	"github.com/ditrit/badaas/persistence/models/dto"
+	"github.com/ditrit/badaas/persistence/pagination"
+	"github.com/ditrit/badaas/services/auth/protocols/oauth2"
+	"github.com/ditrit/badaas/services/oauthservice"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// Store the code in the mocked repository, return its value
+func (setup *testSetup) withCode(code *models.OAuthAuthorizationCode) string {
+	value, _ := oauth2.GenerateToken()
+	code.ID = uuid.New()
+	code.CodeHash = oauth2.HashToken(value)
+	setup.codeRepository.On("Find", squirrel.Eq{"code_hash": code.CodeHash}, nil, nil).
+		Return(pagination.NewPage([]*models.OAuthAuthorizationCode{code}, 1, 10, 1), nil)
+	return value
+}
+
+// Store the token in the mocked repository, return its value
+func (setup *testSetup) withToken(token *models.OAuthToken) string {
+	value, _ := oauth2.GenerateToken()
+	token.ID = uuid.New()
+	token.TokenHash = oauth2.HashToken(value)
+	setup.tokenRepository.On("Find", squirrel.Eq{"token_hash": token.TokenHash}, nil, nil).
+		Return(pagination.NewPage([]*models.OAuthToken{token}, 1, 10, 1), nil).Maybe()
+	return value
+}
+
+// Record the tokens created in the mocked repository
+func (setup *testSetup) recordTokens() *[]*models.OAuthToken {
+	tokens := []*models.OAuthToken{}
+	setup.tokenRepository.On("Create", mock.Anything).Run(func(args mock.Arguments) {
+		tokens = append(tokens, args.Get(0).(*models.OAuthToken))
+	}).Return(nil)
+	return &tokens
+}
+
+func (setup *testSetup) withUser(userID uuid.UUID, disabled bool) {
+	user := &models.User{Username: "bob", Email: "bob@example.com", Disabled: disabled}
+	user.ID = userID
+	setup.userService.On("GetUserByID", userID).Return(user, nil)
+}
+
+func assertOAuthError(t *testing.T, code string, herr error) {
+	oauthError, ok := herr.(*oauthservice.OAuthError)
+	require.True(t, ok, "expected an OAuth error, got %v", herr)
+	assert.Equal(t, code, oauthError.Code)
+}
+
+func TestTokenClientAuthentication(t *testing.T) {
+	setup := setupTest(t)
+	client := webClient()
+	secret := setup.withClient(client, false)
+	setup.clientRepository.On("Find", mock.Anything, nil, nil).
+		Return(pagination.NewPage([]*models.OAuthClient{}, 1, 10, 0), nil).Maybe()
+
+	_, herr := setup.service.Token(oauth2.ClientCredentials{ClientID: client.ID.String(), ClientSecret: "wrong"},
+		dto.DTOOAuthTokenRequest{GrantType: "authorization_code"})
+	assertOAuthError(t, "invalid_client", herr)
+	assert.Equal(t, http.StatusUnauthorized, herr.(*oauthservice.OAuthError).Status)
+
+	_, herr = setup.service.Token(oauth2.ClientCredentials{ClientID: uuid.NewString(), ClientSecret: secret},
+		dto.DTOOAuthTokenRequest{GrantType: "authorization_code"})
+	assertOAuthError(t, "invalid_client", herr)
+
+	_, herr = setup.service.Token(oauth2.ClientCredentials{ClientID: client.ID.String(), ClientSecret: secret},
+		dto.DTOOAuthTokenRequest{GrantType: "client_credentials"})
+	assertOAuthError(t, "unauthorized_client", herr)
+
+	_, herr = setup.service.Token(oauth2.ClientCredentials{ClientID: client.ID.String(), ClientSecret: secret},
+		dto.DTOOAuthTokenRequest{GrantType: "password"})
+	assertOAuthError(t, "unsupported_grant_type", herr)
+}
+
+func TestTokenAuthorizationCodeWithPKCE(t *testing.T) {
+	setup := setupTest(t)
+	client := webClient()
+	setup.withClient(client, true)
+	userID := uuid.New()
+	setup.withUser(userID, false)
+	verifier, challenge := newChallenge(t)
+	code := &models.OAuthAuthorizationCode{
+		ClientID:      client.ID,
+		UserID:        userID,
+		GrantID:       uuid.New(),
+		RedirectURI:   redirectURI,
+		Scope:         "profile",
+		CodeChallenge: challenge,
+		ExpiresAt:     time.Now().Add(time.Minute),
+	}
+	value := setup.withCode(code)
+	credentials := oauth2.ClientCredentials{ClientID: client.ID.String()}
+
+	_, herr := setup.service.Token(credentials, dto.DTOOAuthTokenRequest{
+		GrantType: "authorization_code", Code: value, RedirectURI: redirectURI, CodeVerifier: verifier + "x",
+	})
+	assertOAuthError(t, "invalid_grant", herr)
+	assert.False(t, code.Used)
+
+	setup.codeRepository.On("UpdateColumns", squirrel.Eq{"id": code.ID.String(), "used": false}, mock.Anything).
+		Return(uint(1), nil)
+	tokens := setup.recordTokens()
+	response, herr := setup.service.Token(credentials, dto.DTOOAuthTokenRequest{
+		GrantType: "authorization_code", Code: value, RedirectURI: redirectURI, CodeVerifier: verifier,
+	})
+	require.Nil(t, herr)
+	assert.True(t, code.Used)
+	assert.Equal(t, "Bearer", response.TokenType)
+	assert.Equal(t, int64(3600), response.ExpiresIn)
+	assert.Equal(t, "profile", response.Scope)
+	require.Len(t, *tokens, 2)
+	assert.True(t, oauth2.TokenMatches(response.AccessToken, (*tokens)[0].TokenHash))
+	assert.True(t, oauth2.TokenMatches(response.RefreshToken, (*tokens)[1].TokenHash))
+	assert.Equal(t, code.GrantID, (*tokens)[1].GrantID)
+	assert.Equal(t, &userID, (*tokens)[1].UserID)
+}
+
+func TestTokenAuthorizationCodeReuseRevokesGrant(t *testing.T) {
+	setup := setupTest(t)
+	client := webClient()
+	secret := setup.withClient(client, false)
+	code := &models.OAuthAuthorizationCode{
+		ClientID:  client.ID,
+		UserID:    uuid.New(),
+		GrantID:   uuid.New(),
+		ExpiresAt: time.Now().Add(time.Minute),
+		Used:      true,
+	}
+	value := setup.withCode(code)
+	token := &models.OAuthToken{ClientID: client.ID, GrantID: code.GrantID}
+	setup.tokenRepository.On("Find", squirrel.Eq{"grant_id": code.GrantID.String()}, nil, nil).
+		Return(pagination.NewPage([]*models.OAuthToken{token}, 1, 10, 1), nil)
+	setup.tokenRepository.On("Delete", token).Return(nil)
+
+	_, herr := setup.service.Token(oauth2.ClientCredentials{ClientID: client.ID.String(), ClientSecret: secret},
+		dto.DTOOAuthTokenRequest{GrantType: "authorization_code", Code: value})
+	assertOAuthError(t, "invalid_grant", herr)
+}
+
+func TestTokenAuthorizationCodeConcurrentUseRevokesGrant(t *testing.T) {
+	setup := setupTest(t)
+	client := webClient()
+	secret := setup.withClient(client, false)
+	code := &models.OAuthAuthorizationCode{
+		ClientID:  client.ID,
+		UserID:    uuid.New(),
+		GrantID:   uuid.New(),
+		ExpiresAt: time.Now().Add(time.Minute),
+	}
+	value := setup.withCode(code)
+	// another request used the code since it was read
+	setup.codeRepository.On("UpdateColumns", squirrel.Eq{"id": code.ID.String(), "used": false}, mock.Anything).
+		Return(uint(0), nil)
+	token := &models.OAuthToken{ClientID: client.ID, GrantID: code.GrantID}
+	setup.tokenRepository.On("Find", squirrel.Eq{"grant_id": code.GrantID.String()}, nil, nil).
+		Return(pagination.NewPage([]*models.OAuthToken{token}, 1, 10, 1), nil)
+	setup.tokenRepository.On("Delete", token).Return(nil)
+
+	_, herr := setup.service.Token(oauth2.ClientCredentials{ClientID: client.ID.String(), ClientSecret: secret},
+		dto.DTOOAuthTokenRequest{GrantType: "authorization_code", Code: value})
+	assertOAuthError(t, "invalid_grant", herr)
+}
+
+func TestTokenAuthorizationCodeRedirectURIRequired(t *testing.T) {
+	setup := setupTest(t)
+	client := webClient()
+	secret := setup.withClient(client, false)
+	value := setup.withCode(&models.OAuthAuthorizationCode{
+		ClientID:             client.ID,
+		UserID:               uuid.New(),
+		GrantID:              uuid.New(),
+		RedirectURI:          redirectURI,
+		RedirectURIRequested: true,
+		ExpiresAt:            time.Now().Add(time.Minute),
+	})
+	credentials := oauth2.ClientCredentials{ClientID: client.ID.String(), ClientSecret: secret}
+
+	for _, requestRedirectURI := range []string{"", redirectURI + "/other"} {
+		_, herr := setup.service.Token(credentials, dto.DTOOAuthTokenRequest{
+			GrantType: "authorization_code", Code: value, RedirectURI: requestRedirectURI,
+		})
+		assertOAuthError(t, "invalid_grant", herr)
+	}
+}
+
+func TestTokenRefreshRotation(t *testing.T) {
+	setup := setupTest(t)
+	client := webClient()
+	secret := setup.withClient(client, false)
+	userID := uuid.New()
+	setup.withUser(userID, false)
+	refreshToken := &models.OAuthToken{
+		ClientID:  client.ID,
+		UserID:    &userID,
+		Type:      models.OAuthRefreshToken,
+		Scope:     "profile write",
+		GrantID:   uuid.New(),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+	value := setup.withToken(refreshToken)
+	credentials := oauth2.ClientCredentials{ClientID: client.ID.String(), ClientSecret: secret}
+
+	_, herr := setup.service.Token(credentials, dto.DTOOAuthTokenRequest{
+		GrantType: "refresh_token", RefreshToken: value, Scope: "admin",
+	})
+	assertOAuthError(t, "invalid_scope", herr)
+
+	setup.tokenRepository.On("UpdateColumns", squirrel.Eq{"id": refreshToken.ID.String(), "rotated_at": nil}, mock.Anything).
+		Return(uint(1), nil)
+	tokens := setup.recordTokens()
+	response, herr := setup.service.Token(credentials, dto.DTOOAuthTokenRequest{
+		GrantType: "refresh_token", RefreshToken: value, Scope: "profile",
+	})
+	require.Nil(t, herr)
+	assert.NotNil(t, refreshToken.RotatedAt)
+	assert.Equal(t, "profile", response.Scope)
+	require.Len(t, *tokens, 2)
+	assert.Equal(t, "profile", (*tokens)[0].Scope)
+	assert.Equal(t, "profile write", (*tokens)[1].Scope)
+	assert.Equal(t, refreshToken.GrantID, (*tokens)[1].GrantID)
+
+	// the rotated refresh token is presented again
+	setup.tokenRepository.On("Find", squirrel.Eq{"grant_id": refreshToken.GrantID.String()}, nil, nil).
+		Return(pagination.NewPage(*tokens, 1, 10, 2), nil)
+	setup.tokenRepository.On("Delete", mock.Anything).Return(nil).Twice()
+	_, herr = setup.service.Token(credentials, dto.DTOOAuthTokenRequest{GrantType: "refresh_token", RefreshToken: value})
+	assertOAuthError(t, "invalid_grant", herr)
+}
+
+func TestTokenRefreshConcurrentRotationRevokesGrant(t *testing.T) {
+	setup := setupTest(t)
+	client := webClient()
+	secret := setup.withClient(client, false)
+	userID := uuid.New()
+	setup.withUser(userID, false)
+	refreshToken := &models.OAuthToken{
+		ClientID:  client.ID,
+		UserID:    &userID,
+		Type:      models.OAuthRefreshToken,
+		GrantID:   uuid.New(),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+	value := setup.withToken(refreshToken)
+	// another request rotated the refresh token since it was read
+	setup.tokenRepository.On("UpdateColumns", squirrel.Eq{"id": refreshToken.ID.String(), "rotated_at": nil}, mock.Anything).
+		Return(uint(0), nil)
+	setup.tokenRepository.On("Find", squirrel.Eq{"grant_id": refreshToken.GrantID.String()}, nil, nil).
+		Return(pagination.NewPage([]*models.OAuthToken{refreshToken}, 1, 10, 1), nil)
+	setup.tokenRepository.On("Delete", refreshToken).Return(nil)
+
+	_, herr := setup.service.Token(oauth2.ClientCredentials{ClientID: client.ID.String(), ClientSecret: secret},
+		dto.DTOOAuthTokenRequest{GrantType: "refresh_token", RefreshToken: value})
+	assertOAuthError(t, "invalid_grant", herr)
+}
+
+func TestTokenRefreshDisabledUser(t *testing.T) {
+	setup := setupTest(t)
+	client := webClient()
+	secret := setup.withClient(client, false)
+	userID := uuid.New()
+	setup.withUser(userID, true)
+	value := setup.withToken(&models.OAuthToken{
+		ClientID:  client.ID,
+		UserID:    &userID,
+		Type:      models.OAuthRefreshToken,
+		GrantID:   uuid.New(),
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+
+	_, herr := setup.service.Token(oauth2.ClientCredentials{ClientID: client.ID.String(), ClientSecret: secret},
+		dto.DTOOAuthTokenRequest{GrantType: "refresh_token", RefreshToken: value})
+	assertOAuthError(t, "invalid_grant", herr)
+}
+
+func TestTokenClientCredentials(t *testing.T) {
+	setup := setupTest(t)
+	client := &models.OAuthClient{Name: "batch", GrantTypes: "client_credentials", Scope: "profile write"}
+	secret := setup.withClient(client, false)
+	tokens := setup.recordTokens()
+
+	response, herr := setup.service.Token(oauth2.ClientCredentials{ClientID: client.ID.String(), ClientSecret: secret},
+		dto.DTOOAuthTokenRequest{GrantType: "client_credentials", Scope: "write"})
+	require.Nil(t, herr)
+	assert.Empty(t, response.RefreshToken)
+	assert.Equal(t, "write", response.Scope)
+	require.Len(t, *tokens, 1)
+	assert.Nil(t, (*tokens)[0].UserID)
+}
+
+func TestIntrospect(t *testing.T) {
+	setup := setupTest(t)
+	client := webClient()
+	secret := setup.withClient(client, false)
+	userID := uuid.New()
+	setup.withUser(userID, false)
+	accessToken := &models.OAuthToken{
+		ClientID:  client.ID,
+		UserID:    &userID,
+		Type:      models.OAuthAccessToken,
+		Scope:     "profile",
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+	value := setup.withToken(accessToken)
+	expiredValue := setup.withToken(&models.OAuthToken{ClientID: client.ID, ExpiresAt: time.Now().Add(-time.Second)})
+	setup.tokenRepository.On("Find", mock.Anything, nil, nil).
+		Return(pagination.NewPage([]*models.OAuthToken{}, 1, 10, 0), nil)
+	credentials := oauth2.ClientCredentials{ClientID: client.ID.String(), ClientSecret: secret}
+
+	introspection, herr := setup.service.Introspect(credentials, value)
+	require.Nil(t, herr)
+	assert.True(t, introspection.Active)
+	assert.Equal(t, "profile", introspection.Scope)
+	assert.Equal(t, "bob", introspection.Username)
+	assert.Equal(t, userID.String(), introspection.Subject)
+	assert.Equal(t, "Bearer", introspection.TokenType)
+	assert.Equal(t, accessToken.ExpiresAt.Unix(), introspection.ExpiresAt)
+
+	for _, inactiveValue := range []string{expiredValue, "unknown"} {
+		introspection, herr = setup.service.Introspect(credentials, inactiveValue)
+		require.Nil(t, herr)
+		assert.Equal(t, &dto.DTOOAuthIntrospection{Active: false}, introspection)
+	}
+}
+
+func TestIntrospectRequiresConfidentialClient(t *testing.T) {
+	setup := setupTest(t)
+	client := webClient()
+	setup.withClient(client, true)
+
+	_, herr := setup.service.Introspect(oauth2.ClientCredentials{ClientID: client.ID.String()}, "token")
+	assertOAuthError(t, "invalid_client", herr)
+}
+
+func TestRevoke(t *testing.T) {
+	setup := setupTest(t)
+	client := webClient()
+	secret := setup.withClient(client, false)
+	accessToken := &models.OAuthToken{ClientID: client.ID, Type: models.OAuthAccessToken}
+	value := setup.withToken(accessToken)
+	otherValue := setup.withToken(&models.OAuthToken{ClientID: uuid.New(), Type: models.OAuthAccessToken})
+	setup.tokenRepository.On("Delete", accessToken).Return(nil).Once()
+	credentials := oauth2.ClientCredentials{ClientID: client.ID.String(), ClientSecret: secret}
+
+	assert.Nil(t, setup.service.Revoke(credentials, value))
+	// the tokens of the other clients are ignored
+	assert.Nil(t, setup.service.Revoke(credentials, otherValue))
+
+	refreshToken := &models.OAuthToken{ClientID: client.ID, Type: models.OAuthRefreshToken, GrantID: uuid.New()}
+	refreshValue := setup.withToken(refreshToken)
+	grantToken := &models.OAuthToken{ClientID: client.ID, GrantID: refreshToken.GrantID}
+	setup.tokenRepository.On("Find", squirrel.Eq{"grant_id": refreshToken.GrantID.String()}, nil, nil).
+		Return(pagination.NewPage([]*models.OAuthToken{refreshToken, grantToken}, 1, 10, 2), nil)
+	setup.tokenRepository.On("Delete", refreshToken).Return(nil).Once()
+	setup.tokenRepository.On("Delete", grantToken).Return(nil).Once()
+	assert.Nil(t, setup.service.Revoke(credentials, refreshValue))
+}
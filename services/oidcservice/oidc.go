@@ -0,0 +1,324 @@
+package oidcservice
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/ditrit/badaas/configuration"
+	"github.com/ditrit/badaas/httperrors"
+	"github.com/ditrit/badaas/persistence/models"
+	"github.com/ditrit/badaas/persistence/repository"
+	"github.com/ditrit/badaas/services/auth/protocols/oidc"
+	"github.com/ditrit/badaas/services/userservice"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// The timeout of the requests to the providers
+const providerTimeout = 10 * time.Second
+
+// Errors
+var (
+	HERRUnknownProvider = httperrors.NewErrorNotFound("provider", "no OpenID provider found with this name")
+	HERRInvalidState    = httperrors.NewHTTPError(http.StatusBadRequest, "invalid state",
+		"the OpenID login is invalid or expired, please start again", nil, false)
+	HERRProviderError = httperrors.NewHTTPError(http.StatusBadGateway, "provider error",
+		"the OpenID provider could not be reached", nil, false)
+	HERRInvalidIDToken   = httperrors.NewUnauthorizedError("invalid id token", "the identity could not be verified")
+	HERRAccountNotLinked = httperrors.NewForbiddenError("account not linked",
+		"no account is linked to this identity")
+)
+
+// OIDCService log the users in with the configured OpenID providers
+type OIDCService interface {
+	// Return the names of the configured providers
+	GetProviders() []string
+	// Start a login on the provider, return the url the user is redirected to and the state of the login
+	BeginLogin(provider string) (string, string, httperrors.HTTPError)
+	// Exchange the code sent back by the provider and return the user of the identity,
+	// the user is linked or created if it is unknown and the configuration allows it
+	FinishLogin(provider, state, code string) (*models.User, httperrors.HTTPError)
+}
+
+// Check interface compliance
+var _ OIDCService = (*oidcServiceImpl)(nil)
+
+// OIDCService implementation
+type oidcServiceImpl struct {
+	logger                    *zap.Logger
+	oidcConfiguration         configuration.OIDCConfiguration
+	oidcIdentityRepository    repository.CRUDRepository[models.OIDCIdentity, uuid.UUID]
+	oidcAuthRequestRepository repository.CRUDRepository[models.OIDCAuthRequest, uuid.UUID]
+	userService               userservice.UserService
+	httpClient                *http.Client
+
+	// the relying parties of the providers, created on their first login as the discovery needs the provider to be up
+	mutex          sync.Mutex
+	relyingParties map[string]*oidc.RelyingParty
+}
+
+// OIDCService constructor
+func NewOIDCService(
+	logger *zap.Logger,
+	oidcConfiguration configuration.OIDCConfiguration,
+	oidcIdentityRepository repository.CRUDRepository[models.OIDCIdentity, uuid.UUID],
+	oidcAuthRequestRepository repository.CRUDRepository[models.OIDCAuthRequest, uuid.UUID],
+	userService userservice.UserService,
+) OIDCService {
+	return &oidcServiceImpl{
+		logger:                    logger,
+		oidcConfiguration:         oidcConfiguration,
+		oidcIdentityRepository:    oidcIdentityRepository,
+		oidcAuthRequestRepository: oidcAuthRequestRepository,
+		userService:               userService,
+		httpClient:                &http.Client{Timeout: providerTimeout},
+		relyingParties:            map[string]*oidc.RelyingParty{},
+	}
+}
+
+// Return the names of the configured providers
+func (oidcService *oidcServiceImpl) GetProviders() []string {
+	providers := oidcService.oidcConfiguration.GetProviders()
+	names := make([]string, 0, len(providers))
+	for _, provider := range providers {
+		names = append(names, provider.Name)
+	}
+	return names
+}
+
+// Start a login on the provider, return the url the user is redirected to and the state of the login
+//
+// Only the hash of the state is stored, the nonce and the PKCE verifier are kept until the callback.
+func (oidcService *oidcServiceImpl) BeginLogin(provider string) (string, string, httperrors.HTTPError) {
+	relyingParty, herr := oidcService.getRelyingParty(provider)
+	if herr != nil {
+		return "", "", herr
+	}
+	request, err := oidc.NewAuthRequest()
+	if err != nil {
+		return "", "", httperrors.NewInternalServerError("state error", "failed to generate a state", err)
+	}
+	herr = oidcService.oidcAuthRequestRepository.Create(&models.OIDCAuthRequest{
+		Provider:     provider,
+		StateHash:    hashState(request.State),
+		Nonce:        request.Nonce,
+		CodeVerifier: request.CodeVerifier,
+		ExpiresAt:    time.Now().Add(oidcService.oidcConfiguration.GetRequestDuration()),
+	})
+	if herr != nil {
+		return "", "", herr
+	}
+	return relyingParty.AuthCodeURL(request), request.State, nil
+}
+
+// Exchange the code sent back by the provider and return the user of the identity,
+// the user is linked or created if it is unknown and the configuration allows it
+//
+// The login can't be completed twice with the same state.
+func (oidcService *oidcServiceImpl) FinishLogin(provider, state, code string) (*models.User, httperrors.HTTPError) {
+	relyingParty, herr := oidcService.getRelyingParty(provider)
+	if herr != nil {
+		return nil, herr
+	}
+	authRequest, herr := oidcService.getAuthRequest(provider, state)
+	if herr != nil {
+		return nil, herr
+	}
+	claims, err := relyingParty.Exchange(context.Background(), code, &oidc.AuthRequest{
+		State:        state,
+		Nonce:        authRequest.Nonce,
+		CodeVerifier: authRequest.CodeVerifier,
+	})
+	if errors.Is(err, oidc.ErrInvalidIDToken) {
+		oidcService.logger.Warn("Rejected an OpenID ID token", zap.String("provider", provider), zap.Error(err))
+		return nil, HERRInvalidIDToken
+	}
+	if err != nil {
+		oidcService.logger.Info("Failed to exchange an OpenID code", zap.String("provider", provider), zap.Error(err))
+		return nil, HERRProviderError
+	}
+	user, herr := oidcService.getUser(provider, claims)
+	if herr != nil {
+		return nil, herr
+	}
+	herr = oidcService.userService.CheckCanLogIn(user)
+	if herr != nil {
+		return nil, herr
+	}
+	return user, nil
+}
+
+// Return the user of the identity, linking or creating it if allowed
+func (oidcService *oidcServiceImpl) getUser(provider string, claims *oidc.Claims) (*models.User, httperrors.HTTPError) {
+	identities, herr := oidcService.oidcIdentityRepository.Find(squirrel.Eq{
+		"provider": provider,
+		"subject":  claims.Subject,
+	}, nil, nil)
+	if herr != nil {
+		return nil, herr
+	}
+	if identities.HasContent {
+		identity := identities.Ressources[0]
+		if identity.Email != claims.Email {
+			identity.Email = claims.Email
+			herr = oidcService.oidcIdentityRepository.Save(identity)
+			if herr != nil {
+				return nil, herr
+			}
+		}
+		return oidcService.userService.GetUserByID(identity.UserID)
+	}
+
+	user, herr := oidcService.findOrCreateUser(provider, claims)
+	if herr != nil {
+		return nil, herr
+	}
+	herr = oidcService.oidcIdentityRepository.Create(&models.OIDCIdentity{
+		UserID:   user.ID,
+		Provider: provider,
+		Subject:  claims.Subject,
+		Email:    claims.Email,
+	})
+	if herr != nil {
+		return nil, herr
+	}
+	oidcService.logger.Info("Linked an OpenID identity",
+		zap.String("provider", provider), zap.String("userID", user.ID.String()))
+	return user, nil
+}
+
+// Return the user with the verified email of the identity if the linking is allowed,
+// or create a new user if the provisioning is allowed
+//
+// An email not verified by the provider is never trusted to link an existing user, it could be anybody's.
+func (oidcService *oidcServiceImpl) findOrCreateUser(provider string, claims *oidc.Claims) (*models.User, httperrors.HTTPError) {
+	if claims.Email == "" {
+		oidcService.logger.Info("Refused an OpenID identity without email", zap.String("provider", provider))
+		return nil, HERRAccountNotLinked
+	}
+	user, herr := oidcService.userService.GetUserByEmail(claims.Email)
+	if herr != nil {
+		if impl, ok := herr.(*httperrors.HTTPErrorImpl); !ok || impl.Status != http.StatusNotFound {
+			return nil, herr
+		}
+	}
+	if user != nil {
+		if !claims.EmailVerified || !oidcService.oidcConfiguration.GetLinkByEmail() {
+			return nil, HERRAccountNotLinked
+		}
+		return user, nil
+	}
+	if !oidcService.oidcConfiguration.GetProvisioning() {
+		return nil, HERRAccountNotLinked
+	}
+	// the user can only log in with the provider until it resets its password
+	password, err := randomPassword()
+	if err != nil {
+		return nil, httperrors.NewInternalServerError("password error", "failed to generate a password", err)
+	}
+	user, err = oidcService.userService.NewSystemUser(username(claims), claims.Email, password)
+	if err != nil {
+		var herr httperrors.HTTPError
+		if errors.As(err, &herr) {
+			return nil, herr
+		}
+		return nil, httperrors.NewHTTPError(http.StatusBadRequest, "invalid identity", err.Error(), nil, false)
+	}
+	if claims.EmailVerified {
+		herr = oidcService.userService.MarkEmailVerified(user.ID)
+		if herr != nil {
+			return nil, herr
+		}
+		user.EmailVerified = true
+	}
+	return user, nil
+}
+
+// Return the login of the state and delete it, or an error if it doesn't exist or is expired
+func (oidcService *oidcServiceImpl) getAuthRequest(provider, state string) (*models.OIDCAuthRequest, httperrors.HTTPError) {
+	if state == "" {
+		return nil, HERRInvalidState
+	}
+	authRequests, herr := oidcService.oidcAuthRequestRepository.Find(squirrel.Eq{
+		"provider":   provider,
+		"state_hash": hashState(state),
+	}, nil, nil)
+	if herr != nil {
+		return nil, herr
+	}
+	if !authRequests.HasContent {
+		return nil, HERRInvalidState
+	}
+	authRequest := authRequests.Ressources[0]
+	herr = oidcService.oidcAuthRequestRepository.Delete(authRequest)
+	if herr != nil {
+		return nil, herr
+	}
+	if authRequest.IsExpired() {
+		return nil, HERRInvalidState
+	}
+	return authRequest, nil
+}
+
+// Return the relying party of the provider, the provider is discovered on the first call
+func (oidcService *oidcServiceImpl) getRelyingParty(name string) (*oidc.RelyingParty, httperrors.HTTPError) {
+	oidcService.mutex.Lock()
+	defer oidcService.mutex.Unlock()
+	relyingParty, ok := oidcService.relyingParties[name]
+	if ok {
+		return relyingParty, nil
+	}
+	for _, provider := range oidcService.oidcConfiguration.GetProviders() {
+		if provider.Name != name {
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), providerTimeout)
+		defer cancel()
+		relyingParty, err := oidc.NewRelyingParty(ctx, oidcService.httpClient, provider.Issuer,
+			provider.ClientID, provider.ClientSecret, provider.RedirectURL, provider.Scopes)
+		if err != nil {
+			oidcService.logger.Error("Failed to discover an OpenID provider",
+				zap.String("provider", name), zap.String("issuer", provider.Issuer), zap.Error(err))
+			return nil, HERRProviderError
+		}
+		oidcService.relyingParties[name] = relyingParty
+		return relyingParty, nil
+	}
+	return nil, HERRUnknownProvider
+}
+
+// Return the username of a new user, the email is used if the provider doesn't give one
+func username(claims *oidc.Claims) string {
+	if claims.PreferredUsername != "" {
+		return claims.PreferredUsername
+	}
+	if claims.Name != "" {
+		return claims.Name
+	}
+	return strings.SplitN(claims.Email, "@", 2)[0]
+}
+
+// Return the hex encoded SHA-256 hash of the state
+func hashState(state string) string {
+	hash := sha256.Sum256([]byte(state))
+	return hex.EncodeToString(hash[:])
+}
+
+// Return a random password nobody knows
+func randomPassword() (string, error) {
+	b := make([]byte, 32)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
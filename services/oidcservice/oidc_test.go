@@ -0,0 +1,217 @@
+package oidcservice_test
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/ditrit/badaas/configuration"
+	"github.com/ditrit/badaas/httperrors"
+	mocksConfiguration "github.com/ditrit/badaas/mocks/configuration"
+	mocksRepository "github.com/ditrit/badaas/mocks/persistence/repository"
+	mocksUserService "github.com/ditrit/badaas/mocks/services/userservice"
+	"github.com/ditrit/badaas/persistence/models"
+	"github.com/ditrit/badaas/persistence/pagination"
+	"github.com/ditrit/badaas/services/auth/protocols/oidc/oidctest"
+	"github.com/ditrit/badaas/services/oidcservice"
+	"github.com/ditrit/badaas/services/userservice"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const redirectURL = "https://badaas.example.com/login/oidc/stub/callback"
+
+var notFound = httperrors.NewErrorNotFound("user", "no user found")
+
+type testSetup struct {
+	provider              *oidctest.Provider
+	oidcConfiguration     *mocksConfiguration.OIDCConfiguration
+	identityRepository    *mocksRepository.CRUDRepository[models.OIDCIdentity, uuid.UUID]
+	authRequestRepository *mocksRepository.CRUDRepository[models.OIDCAuthRequest, uuid.UUID]
+	userService           *mocksUserService.UserService
+	service               oidcservice.OIDCService
+	// the last login created by the service
+	authRequest *models.OIDCAuthRequest
+}
+
+func setupTest(t *testing.T, provisioning, linkByEmail bool) *testSetup {
+	provider, err := oidctest.NewProvider("badaas", "secret")
+	require.NoError(t, err)
+	t.Cleanup(provider.Close)
+	oidcConfiguration := mocksConfiguration.NewOIDCConfiguration(t)
+	oidcConfiguration.On("GetProviders").Return([]configuration.OIDCProvider{{
+		Name:         "stub",
+		Issuer:       provider.Issuer(),
+		ClientID:     "badaas",
+		ClientSecret: "secret",
+		RedirectURL:  redirectURL,
+	}}).Maybe()
+	oidcConfiguration.On("GetRequestDuration").Return(10 * time.Minute).Maybe()
+	oidcConfiguration.On("GetProvisioning").Return(provisioning).Maybe()
+	oidcConfiguration.On("GetLinkByEmail").Return(linkByEmail).Maybe()
+	setup := &testSetup{
+		provider:              provider,
+		oidcConfiguration:     oidcConfiguration,
+		identityRepository:    mocksRepository.NewCRUDRepository[models.OIDCIdentity, uuid.UUID](t),
+		authRequestRepository: mocksRepository.NewCRUDRepository[models.OIDCAuthRequest, uuid.UUID](t),
+		userService:           mocksUserService.NewUserService(t),
+	}
+	setup.authRequestRepository.On("Create", mock.AnythingOfType("*models.OIDCAuthRequest")).
+		Run(func(args mock.Arguments) {
+			setup.authRequest = args.Get(0).(*models.OIDCAuthRequest)
+		}).Return(nil).Maybe()
+	setup.service = oidcservice.NewOIDCService(zap.NewNop(), oidcConfiguration,
+		setup.identityRepository, setup.authRequestRepository, setup.userService)
+	return setup
+}
+
+// Log the user in on the provider and return the state and the code of the callback
+func (setup *testSetup) authorize(t *testing.T, user oidctest.User) (string, string) {
+	authorizationURL, state, herr := setup.service.BeginLogin("stub")
+	require.Nil(t, herr)
+	callbackURL, err := setup.provider.Authorize(authorizationURL, user)
+	require.NoError(t, err)
+	assert.Equal(t, state, callbackURL.Query().Get("state"))
+	setup.authRequestRepository.On("Find", mock.MatchedBy(func(eq squirrel.Eq) bool {
+		return eq["state_hash"] == setup.authRequest.StateHash && eq["provider"] == "stub"
+	}), nil, nil).Return(pagination.NewPage([]*models.OIDCAuthRequest{setup.authRequest}, 1, 10, 1), nil)
+	setup.authRequestRepository.On("Delete", setup.authRequest).Return(nil)
+	return state, callbackURL.Query().Get("code")
+}
+
+func (setup *testSetup) onFindIdentity(subject string, identities ...*models.OIDCIdentity) {
+	setup.identityRepository.On("Find", squirrel.Eq{"provider": "stub", "subject": subject}, nil, nil).
+		Return(pagination.NewPage(identities, 1, 10, uint(len(identities))), nil)
+}
+
+func TestBeginLogin(t *testing.T) {
+	setup := setupTest(t, true, true)
+
+	authorizationURL, state, herr := setup.service.BeginLogin("stub")
+	require.Nil(t, herr)
+	parsedURL, err := url.Parse(authorizationURL)
+	require.NoError(t, err)
+	assert.Equal(t, state, parsedURL.Query().Get("state"))
+	assert.Equal(t, "stub", setup.authRequest.Provider)
+	assert.NotEqual(t, state, setup.authRequest.StateHash)
+	assert.Equal(t, setup.authRequest.Nonce, parsedURL.Query().Get("nonce"))
+}
+
+func TestBeginLoginUnknownProvider(t *testing.T) {
+	setup := setupTest(t, true, true)
+
+	_, _, herr := setup.service.BeginLogin("unknown")
+	assert.Equal(t, oidcservice.HERRUnknownProvider, herr)
+}
+
+func TestFinishLoginKnownIdentity(t *testing.T) {
+	setup := setupTest(t, false, false)
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Email: "bob@email.com"}
+	state, code := setup.authorize(t, oidctest.User{Subject: "1234", Email: "bob@email.com", EmailVerified: true})
+	setup.onFindIdentity("1234", &models.OIDCIdentity{UserID: user.ID, Provider: "stub", Subject: "1234", Email: "bob@email.com"})
+	setup.userService.On("GetUserByID", user.ID).Return(user, nil)
+	setup.userService.On("CheckCanLogIn", user).Return(nil)
+
+	loggedUser, herr := setup.service.FinishLogin("stub", state, code)
+	require.Nil(t, herr)
+	assert.Equal(t, user, loggedUser)
+}
+
+func TestFinishLoginLinkByVerifiedEmail(t *testing.T) {
+	setup := setupTest(t, false, true)
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Email: "bob@email.com"}
+	state, code := setup.authorize(t, oidctest.User{Subject: "1234", Email: "bob@email.com", EmailVerified: true})
+	setup.onFindIdentity("1234")
+	setup.userService.On("GetUserByEmail", "bob@email.com").Return(user, nil)
+	setup.identityRepository.On("Create", &models.OIDCIdentity{
+		UserID: user.ID, Provider: "stub", Subject: "1234", Email: "bob@email.com",
+	}).Return(nil)
+	setup.userService.On("CheckCanLogIn", user).Return(nil)
+
+	loggedUser, herr := setup.service.FinishLogin("stub", state, code)
+	require.Nil(t, herr)
+	assert.Equal(t, user, loggedUser)
+}
+
+func TestFinishLoginDoesNotLinkUnverifiedEmail(t *testing.T) {
+	setup := setupTest(t, true, true)
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Email: "bob@email.com"}
+	state, code := setup.authorize(t, oidctest.User{Subject: "1234", Email: "bob@email.com", EmailVerified: false})
+	setup.onFindIdentity("1234")
+	setup.userService.On("GetUserByEmail", "bob@email.com").Return(user, nil)
+
+	_, herr := setup.service.FinishLogin("stub", state, code)
+	assert.Equal(t, oidcservice.HERRAccountNotLinked, herr)
+}
+
+func TestFinishLoginProvisioning(t *testing.T) {
+	setup := setupTest(t, true, true)
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Username: "bob", Email: "bob@email.com"}
+	state, code := setup.authorize(t, oidctest.User{
+		Subject: "1234", Email: "bob@email.com", EmailVerified: true, PreferredUsername: "bob",
+	})
+	setup.onFindIdentity("1234")
+	setup.userService.On("GetUserByEmail", "bob@email.com").Return(nil, notFound)
+	setup.userService.On("NewSystemUser", "bob", "bob@email.com", mock.AnythingOfType("string")).Return(user, nil)
+	setup.userService.On("MarkEmailVerified", user.ID).Return(nil)
+	setup.identityRepository.On("Create", mock.AnythingOfType("*models.OIDCIdentity")).Return(nil)
+	setup.userService.On("CheckCanLogIn", user).Return(nil)
+
+	loggedUser, herr := setup.service.FinishLogin("stub", state, code)
+	require.Nil(t, herr)
+	assert.Equal(t, user, loggedUser)
+	assert.True(t, loggedUser.EmailVerified)
+}
+
+func TestFinishLoginWithoutProvisioning(t *testing.T) {
+	setup := setupTest(t, false, true)
+	state, code := setup.authorize(t, oidctest.User{Subject: "1234", Email: "bob@email.com", EmailVerified: true})
+	setup.onFindIdentity("1234")
+	setup.userService.On("GetUserByEmail", "bob@email.com").Return(nil, notFound)
+
+	_, herr := setup.service.FinishLogin("stub", state, code)
+	assert.Equal(t, oidcservice.HERRAccountNotLinked, herr)
+}
+
+func TestFinishLoginUserDisabled(t *testing.T) {
+	setup := setupTest(t, false, false)
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Disabled: true}
+	state, code := setup.authorize(t, oidctest.User{Subject: "1234", Email: "bob@email.com"})
+	setup.onFindIdentity("1234", &models.OIDCIdentity{UserID: user.ID, Provider: "stub", Subject: "1234", Email: "bob@email.com"})
+	setup.userService.On("GetUserByID", user.ID).Return(user, nil)
+	setup.userService.On("CheckCanLogIn", user).Return(userservice.HERRUserDisabled)
+
+	_, herr := setup.service.FinishLogin("stub", state, code)
+	assert.Equal(t, userservice.HERRUserDisabled, herr)
+}
+
+func TestFinishLoginUnknownState(t *testing.T) {
+	setup := setupTest(t, true, true)
+	setup.authRequestRepository.On("Find", mock.Anything, nil, nil).
+		Return(pagination.NewPage([]*models.OIDCAuthRequest{}, 1, 10, 0), nil)
+
+	_, herr := setup.service.FinishLogin("stub", "forged", "code")
+	assert.Equal(t, oidcservice.HERRInvalidState, herr)
+}
+
+func TestFinishLoginExpiredState(t *testing.T) {
+	setup := setupTest(t, true, true)
+	state, code := setup.authorize(t, oidctest.User{Subject: "1234"})
+	setup.authRequest.ExpiresAt = time.Now().Add(-time.Second)
+
+	_, herr := setup.service.FinishLogin("stub", state, code)
+	assert.Equal(t, oidcservice.HERRInvalidState, herr)
+}
+
+func TestFinishLoginWrongNonce(t *testing.T) {
+	setup := setupTest(t, true, true)
+	setup.provider.NonceOverride = "replayed"
+	state, code := setup.authorize(t, oidctest.User{Subject: "1234"})
+
+	_, herr := setup.service.FinishLogin("stub", state, code)
+	assert.Equal(t, oidcservice.HERRInvalidIDToken, herr)
+}
@@ -0,0 +1,83 @@
+package passwordpolicyservice
+
+import (
+	"bufio"
+	"crypto/sha1"
+	_ "embed"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// The length of the prefixes of the SHA-1 hashes sent to a HashRangeSource
+const hashPrefixLength = 5
+
+// A short list of common passwords, used when no list is configured
+//
+//go:embed breached.txt
+var bundledBreachedPasswords string
+
+// HashRangeSource return the breached password hashes sharing a prefix
+//
+// Only the prefix of the hash of a password is given to the source (k-anonymity),
+// so an online source never learns the password checked.
+type HashRangeSource interface {
+	// Return the uppercase hexadecimal suffixes of the SHA-1 hashes starting with the prefix
+	GetSuffixes(prefix string) ([]string, error)
+}
+
+// Check interface compliance
+var _ HashRangeSource = (*offlineHashRangeSource)(nil)
+
+// A HashRangeSource reading the hashes from a list held in memory
+type offlineHashRangeSource struct {
+	suffixes map[string][]string
+}
+
+// Create a HashRangeSource from a list of SHA-1 hashes
+//
+// The list contains a hash per line, optionally followed by ":<count>" like the lists of haveibeenpwned.com.
+func NewOfflineHashRangeSource(reader io.Reader) (HashRangeSource, error) {
+	source := &offlineHashRangeSource{suffixes: make(map[string][]string)}
+	scanner := bufio.NewScanner(reader)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		hash, _, _ := strings.Cut(line, ":")
+		hash = strings.ToUpper(hash)
+		if _, err := hex.DecodeString(hash); err != nil || len(hash) != sha1.Size*2 {
+			return nil, fmt.Errorf("invalid SHA-1 hash on line %d", lineNumber)
+		}
+		prefix := hash[:hashPrefixLength]
+		source.suffixes[prefix] = append(source.suffixes[prefix], hash[hashPrefixLength:])
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return source, nil
+}
+
+// Return the suffixes of the hashes starting with the prefix
+func (source *offlineHashRangeSource) GetSuffixes(prefix string) ([]string, error) {
+	return source.suffixes[prefix], nil
+}
+
+// Return true if the password is in the hash range source
+func isBreached(source HashRangeSource, password string) (bool, error) {
+	// SHA-1 is the hash used by the lists of breached passwords
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	suffixes, err := source.GetSuffixes(hash[:hashPrefixLength])
+	if err != nil {
+		return false, err
+	}
+	for _, suffix := range suffixes {
+		if suffix == hash[hashPrefixLength:] {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,33 @@
+package passwordpolicyservice_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ditrit/badaas/services/passwordpolicyservice"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOfflineHashRangeSource(t *testing.T) {
+	// SHA-1 of "password" in the format of haveibeenpwned.com, then of "123456" in lowercase
+	source, err := passwordpolicyservice.NewOfflineHashRangeSource(strings.NewReader(
+		"5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8:9545824\n\n7c4a8d09ca3762af61e59520943dc26494f8941b\n"))
+	require.NoError(t, err)
+
+	suffixes, err := source.GetSuffixes("5BAA6")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1E4C9B93F3F0682250B6CF8331B7EE68FD8"}, suffixes)
+	suffixes, err = source.GetSuffixes("7C4A8")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"D09CA3762AF61E59520943DC26494F8941B"}, suffixes)
+	suffixes, err = source.GetSuffixes("00000")
+	require.NoError(t, err)
+	assert.Empty(t, suffixes)
+}
+
+func TestOfflineHashRangeSourceInvalidHash(t *testing.T) {
+	source, err := passwordpolicyservice.NewOfflineHashRangeSource(strings.NewReader("password\n"))
+	assert.Error(t, err)
+	assert.Nil(t, source)
+}
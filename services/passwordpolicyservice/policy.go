@@ -0,0 +1,237 @@
+package passwordpolicyservice
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/ditrit/badaas/configuration"
+	"github.com/ditrit/badaas/httperrors"
+	"github.com/ditrit/badaas/persistence/models"
+	"github.com/ditrit/badaas/persistence/repository"
+	"github.com/ditrit/badaas/services/auth/protocols/basicauth"
+	"github.com/google/uuid"
+)
+
+// The usernames and the email local parts shorter than this are not searched in the passwords
+const minUserInfoLength = 3
+
+// PasswordPolicyService check the passwords chosen by the users
+type PasswordPolicyService interface {
+	// Return a validation error if the password of the user doesn't satisfy the policy
+	//
+	// The user is only used to check its username, its email and its previous passwords,
+	// so it doesn't need to be saved yet.
+	Validate(user *models.User, password string) httperrors.HTTPError
+	// Add the current password of the user to its history, before it is replaced
+	RecordPassword(user *models.User) httperrors.HTTPError
+}
+
+// Check interface compliance
+var _ PasswordPolicyService = (*passwordPolicyServiceImpl)(nil)
+
+// PasswordPolicyService implementation
+type passwordPolicyServiceImpl struct {
+	passwordPolicyConfiguration configuration.PasswordPolicyConfiguration
+	passwordHistoryRepository   repository.CRUDRepository[models.PasswordHistory, uuid.UUID]
+	passwordHasher              basicauth.PasswordHasher
+	breachedSource              HashRangeSource
+}
+
+// PasswordPolicyService constructor
+//
+// The list of breached passwords is loaded from the path of the configuration, or from the bundled list.
+func NewPasswordPolicyService(
+	passwordPolicyConfiguration configuration.PasswordPolicyConfiguration,
+	passwordHistoryRepository repository.CRUDRepository[models.PasswordHistory, uuid.UUID],
+	passwordHasher basicauth.PasswordHasher,
+) (PasswordPolicyService, error) {
+	var breachedSource HashRangeSource
+	if passwordPolicyConfiguration.GetBreachedEnabled() {
+		var err error
+		breachedSource, err = loadBreachedSource(passwordPolicyConfiguration.GetBreachedPath())
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &passwordPolicyServiceImpl{
+		passwordPolicyConfiguration: passwordPolicyConfiguration,
+		passwordHistoryRepository:   passwordHistoryRepository,
+		passwordHasher:              passwordHasher,
+		breachedSource:              breachedSource,
+	}, nil
+}
+
+// Load the list of breached passwords of the path, or the bundled one if the path is empty
+func loadBreachedSource(path string) (HashRangeSource, error) {
+	if path == "" {
+		return NewOfflineHashRangeSource(strings.NewReader(bundledBreachedPasswords))
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return NewOfflineHashRangeSource(file)
+}
+
+// Return a validation error if the password of the user doesn't satisfy the policy
+//
+// The error describes the first rule that is not satisfied.
+func (service *passwordPolicyServiceImpl) Validate(user *models.User, password string) httperrors.HTTPError {
+	message, herr := service.check(user, password)
+	if herr != nil {
+		return herr
+	}
+	if message != "" {
+		return httperrors.NewValidationError("the password doesn't satisfy the policy",
+			map[string]string{"password": message})
+	}
+	return nil
+}
+
+// Return the description of the first rule that the password doesn't satisfy, empty if none
+func (service *passwordPolicyServiceImpl) check(user *models.User, password string) (string, httperrors.HTTPError) {
+	config := service.passwordPolicyConfiguration
+	length := uint(utf8.RuneCountInString(password))
+	if length < config.GetMinLength() || length == 0 {
+		return fmt.Sprintf("the password must contain at least %d characters", config.GetMinLength()), nil
+	}
+	if maxLength := config.GetMaxLength(); maxLength > 0 && length > maxLength {
+		return fmt.Sprintf("the password must contain at most %d characters", maxLength), nil
+	}
+	if message := checkCharacterClasses(config, password); message != "" {
+		return message, nil
+	}
+	if config.GetForbidUserInfo() && containsUserInfo(user, password) {
+		return "the password can't contain the username or the email", nil
+	}
+	if service.breachedSource != nil {
+		breached, err := isBreached(service.breachedSource, password)
+		if err != nil {
+			return "", httperrors.NewInternalServerError("password error", "failed to check the breached passwords", err)
+		}
+		if breached {
+			return "the password is too common or was found in a data breach", nil
+		}
+	}
+	reused, herr := service.isReused(user, password)
+	if herr != nil {
+		return "", herr
+	}
+	if reused {
+		return fmt.Sprintf("the password can't be one of the last %d passwords", config.GetHistorySize()), nil
+	}
+	return "", nil
+}
+
+// Return the description of the first required character class missing in the password, empty if none
+func checkCharacterClasses(config configuration.PasswordPolicyConfiguration, password string) string {
+	var hasLowercase, hasUppercase, hasDigit, hasSymbol bool
+	for _, character := range password {
+		switch {
+		case unicode.IsLower(character):
+			hasLowercase = true
+		case unicode.IsUpper(character):
+			hasUppercase = true
+		case unicode.IsDigit(character):
+			hasDigit = true
+		case !unicode.IsLetter(character):
+			hasSymbol = true
+		}
+	}
+	switch {
+	case config.GetRequireLowercase() && !hasLowercase:
+		return "the password must contain a lowercase letter"
+	case config.GetRequireUppercase() && !hasUppercase:
+		return "the password must contain an uppercase letter"
+	case config.GetRequireDigit() && !hasDigit:
+		return "the password must contain a digit"
+	case config.GetRequireSymbol() && !hasSymbol:
+		return "the password must contain a character that is neither a letter nor a digit"
+	}
+	return ""
+}
+
+// Return true if the password contains the username, the email or the local part of the email of the user
+func containsUserInfo(user *models.User, password string) bool {
+	password = strings.ToLower(password)
+	localPart, _, _ := strings.Cut(user.Email, "@")
+	for _, userInfo := range []string{user.Username, user.Email, localPart} {
+		userInfo = strings.ToLower(strings.TrimSpace(userInfo))
+		if len(userInfo) >= minUserInfoLength && strings.Contains(password, userInfo) {
+			return true
+		}
+	}
+	return false
+}
+
+// Return true if the password is the current password of the user or one of its last passwords
+func (service *passwordPolicyServiceImpl) isReused(user *models.User, password string) (bool, httperrors.HTTPError) {
+	historySize := service.passwordPolicyConfiguration.GetHistorySize()
+	if historySize == 0 || len(user.Password) == 0 {
+		return false, nil
+	}
+	if service.passwordHasher.Verify(user.Password, password) {
+		return true, nil
+	}
+	history, herr := service.getHistory(user.ID)
+	if herr != nil {
+		return false, herr
+	}
+	// the current password counts in the history size
+	for i, previousPassword := range history {
+		if uint(i) >= historySize-1 {
+			break
+		}
+		if service.passwordHasher.Verify(previousPassword.Password, password) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Add the current password of the user to its history, before it is replaced
+//
+// The oldest passwords that are not needed anymore are deleted.
+func (service *passwordPolicyServiceImpl) RecordPassword(user *models.User) httperrors.HTTPError {
+	historySize := service.passwordPolicyConfiguration.GetHistorySize()
+	// the current password is enough to forbid the reuse of the last password
+	if historySize <= 1 || len(user.Password) == 0 {
+		return nil
+	}
+	herr := service.passwordHistoryRepository.Create(&models.PasswordHistory{
+		UserID:   user.ID,
+		Password: user.Password,
+	})
+	if herr != nil {
+		return herr
+	}
+	history, herr := service.getHistory(user.ID)
+	if herr != nil {
+		return herr
+	}
+	for i, previousPassword := range history {
+		if uint(i) < historySize-1 {
+			continue
+		}
+		herr = service.passwordHistoryRepository.Delete(previousPassword)
+		if herr != nil {
+			return herr
+		}
+	}
+	return nil
+}
+
+// Return the previous passwords of the user, the most recent first
+func (service *passwordPolicyServiceImpl) getHistory(userID uuid.UUID) ([]*models.PasswordHistory, httperrors.HTTPError) {
+	history, herr := service.passwordHistoryRepository.Find(
+		squirrel.Eq{"user_id": userID.String()}, nil, repository.NewSortOption("created_at", true))
+	if herr != nil {
+		return nil, herr
+	}
+	return history.Ressources, nil
+}
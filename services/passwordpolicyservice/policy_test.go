@@ -0,0 +1,183 @@
+package passwordpolicyservice_test
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/ditrit/badaas/httperrors"
+	mocksConfiguration "github.com/ditrit/badaas/mocks/configuration"
+	mocksRepository "github.com/ditrit/badaas/mocks/persistence/repository"
+	"github.com/ditrit/badaas/persistence/models"
+	"github.com/ditrit/badaas/persistence/pagination"
+	"github.com/ditrit/badaas/services/auth/protocols/basicauth"
+	"github.com/ditrit/badaas/services/passwordpolicyservice"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type policy struct {
+	minLength, maxLength, historySize                               uint
+	requireLowercase, requireUppercase, requireDigit, requireSymbol bool
+	forbidUserInfo, breachedEnabled                                 bool
+	breachedPath                                                    string
+}
+
+func newPasswordPolicyConfiguration(t *testing.T, policy policy) *mocksConfiguration.PasswordPolicyConfiguration {
+	passwordPolicyConfiguration := mocksConfiguration.NewPasswordPolicyConfiguration(t)
+	passwordPolicyConfiguration.On("GetMinLength").Return(policy.minLength).Maybe()
+	passwordPolicyConfiguration.On("GetMaxLength").Return(policy.maxLength).Maybe()
+	passwordPolicyConfiguration.On("GetRequireLowercase").Return(policy.requireLowercase).Maybe()
+	passwordPolicyConfiguration.On("GetRequireUppercase").Return(policy.requireUppercase).Maybe()
+	passwordPolicyConfiguration.On("GetRequireDigit").Return(policy.requireDigit).Maybe()
+	passwordPolicyConfiguration.On("GetRequireSymbol").Return(policy.requireSymbol).Maybe()
+	passwordPolicyConfiguration.On("GetForbidUserInfo").Return(policy.forbidUserInfo).Maybe()
+	passwordPolicyConfiguration.On("GetHistorySize").Return(policy.historySize).Maybe()
+	passwordPolicyConfiguration.On("GetBreachedEnabled").Return(policy.breachedEnabled).Maybe()
+	passwordPolicyConfiguration.On("GetBreachedPath").Return(policy.breachedPath).Maybe()
+	return passwordPolicyConfiguration
+}
+
+func newPasswordHasher(t *testing.T) basicauth.PasswordHasher {
+	passwordHasher, err := basicauth.NewBcryptHasher(bcrypt.MinCost)
+	require.NoError(t, err)
+	return passwordHasher
+}
+
+func setupTest(t *testing.T, policy policy) (
+	*mocksRepository.CRUDRepository[models.PasswordHistory, uuid.UUID],
+	passwordpolicyservice.PasswordPolicyService,
+) {
+	passwordHistoryRepository := mocksRepository.NewCRUDRepository[models.PasswordHistory, uuid.UUID](t)
+	service, err := passwordpolicyservice.NewPasswordPolicyService(
+		newPasswordPolicyConfiguration(t, policy), passwordHistoryRepository, newPasswordHasher(t))
+	require.NoError(t, err)
+	return passwordHistoryRepository, service
+}
+
+// Return the message of the validation error of the password
+func getPasswordMessage(t *testing.T, herr httperrors.HTTPError) string {
+	require.Error(t, herr)
+	herrImpl := herr.(*httperrors.HTTPErrorImpl)
+	assert.Equal(t, http.StatusBadRequest, herrImpl.Status)
+	return herrImpl.Fields["password"]
+}
+
+func TestValidateLength(t *testing.T) {
+	_, service := setupTest(t, policy{minLength: 8, maxLength: 12})
+	user := &models.User{Username: "bob", Email: "bob@email.com"}
+
+	assert.Equal(t, "the password must contain at least 8 characters",
+		getPasswordMessage(t, service.Validate(user, "short")))
+	assert.Equal(t, "the password must contain at most 12 characters",
+		getPasswordMessage(t, service.Validate(user, "a very long passphrase")))
+	// the characters are counted, not the bytes
+	assert.NoError(t, service.Validate(user, "éééééééé"))
+}
+
+func TestValidateEmptyPassword(t *testing.T) {
+	_, service := setupTest(t, policy{})
+
+	assert.Error(t, service.Validate(&models.User{}, ""))
+}
+
+func TestValidateCharacterClasses(t *testing.T) {
+	_, service := setupTest(t, policy{requireLowercase: true, requireUppercase: true, requireDigit: true, requireSymbol: true})
+	user := &models.User{}
+
+	assert.Equal(t, "the password must contain a lowercase letter",
+		getPasswordMessage(t, service.Validate(user, "CORRECT HORSE")))
+	assert.Equal(t, "the password must contain an uppercase letter",
+		getPasswordMessage(t, service.Validate(user, "correct horse")))
+	assert.Equal(t, "the password must contain a digit",
+		getPasswordMessage(t, service.Validate(user, "Correct horse")))
+	assert.Equal(t, "the password must contain a character that is neither a letter nor a digit",
+		getPasswordMessage(t, service.Validate(user, "Correcthorse1")))
+	assert.NoError(t, service.Validate(user, "Correct horse 1"))
+}
+
+func TestValidateUserInfo(t *testing.T) {
+	_, service := setupTest(t, policy{forbidUserInfo: true})
+	user := &models.User{Username: "Robert", Email: "bobby@email.com"}
+
+	assert.Equal(t, "the password can't contain the username or the email",
+		getPasswordMessage(t, service.Validate(user, "i am robert!")))
+	assert.Equal(t, "the password can't contain the username or the email",
+		getPasswordMessage(t, service.Validate(user, "BOBBY2000")))
+	assert.NoError(t, service.Validate(user, "correct horse battery staple"))
+}
+
+func TestValidateBreachedBundledList(t *testing.T) {
+	_, service := setupTest(t, policy{breachedEnabled: true})
+
+	assert.Equal(t, "the password is too common or was found in a data breach",
+		getPasswordMessage(t, service.Validate(&models.User{}, "password123")))
+	assert.NoError(t, service.Validate(&models.User{}, "correct horse battery staple"))
+}
+
+func TestValidateBreachedConfiguredList(t *testing.T) {
+	breachedPath := filepath.Join(t.TempDir(), "breached.txt")
+	// SHA-1 of "correct horse battery staple"
+	require.NoError(t, os.WriteFile(breachedPath, []byte("ABF7AAD6438836DBE526AA231ABDE2D0EEF74D42:42\n"), 0o600))
+	_, service := setupTest(t, policy{breachedEnabled: true, breachedPath: breachedPath})
+
+	assert.Error(t, service.Validate(&models.User{}, "correct horse battery staple"))
+	// the bundled list is not used
+	assert.NoError(t, service.Validate(&models.User{}, "password123"))
+}
+
+func TestNewPasswordPolicyServiceMissingList(t *testing.T) {
+	service, err := passwordpolicyservice.NewPasswordPolicyService(
+		newPasswordPolicyConfiguration(t, policy{breachedEnabled: true, breachedPath: "/does/not/exist"}),
+		mocksRepository.NewCRUDRepository[models.PasswordHistory, uuid.UUID](t), newPasswordHasher(t))
+	assert.Error(t, err)
+	assert.Nil(t, service)
+}
+
+func TestValidateHistory(t *testing.T) {
+	passwordHistoryRepository, service := setupTest(t, policy{historySize: 3})
+	passwordHasher := newPasswordHasher(t)
+	hash := func(password string) []byte {
+		passwordHash, err := passwordHasher.Hash(password)
+		require.NoError(t, err)
+		return passwordHash
+	}
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Password: hash("current")}
+	passwordHistoryRepository.On("Find", squirrel.Eq{"user_id": user.ID.String()}, nil, mock.Anything).
+		Return(pagination.NewPage([]*models.PasswordHistory{
+			{Password: hash("previous")},
+			{Password: hash("before previous")},
+			{Password: hash("oldest")},
+		}, 1, 3, 3), nil)
+
+	for _, password := range []string{"current", "previous", "before previous"} {
+		assert.Equal(t, "the password can't be one of the last 3 passwords",
+			getPasswordMessage(t, service.Validate(user, password)))
+	}
+	// only the last passwords are forbidden
+	assert.NoError(t, service.Validate(user, "oldest"))
+}
+
+func TestRecordPassword(t *testing.T) {
+	passwordHistoryRepository, service := setupTest(t, policy{historySize: 2})
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Password: []byte("hash")}
+	oldest := &models.PasswordHistory{Password: []byte("oldest")}
+	passwordHistoryRepository.On("Create", &models.PasswordHistory{UserID: user.ID, Password: user.Password}).Return(nil)
+	passwordHistoryRepository.On("Find", squirrel.Eq{"user_id": user.ID.String()}, nil, mock.Anything).
+		Return(pagination.NewPage([]*models.PasswordHistory{{Password: user.Password}, oldest}, 1, 2, 2), nil)
+	passwordHistoryRepository.On("Delete", oldest).Return(nil)
+
+	assert.NoError(t, service.RecordPassword(user))
+}
+
+func TestRecordPasswordWithoutHistory(t *testing.T) {
+	passwordHistoryRepository, service := setupTest(t, policy{historySize: 1})
+
+	assert.NoError(t, service.RecordPassword(&models.User{Password: []byte("hash")}))
+	passwordHistoryRepository.AssertNotCalled(t, "Create", mock.Anything)
+}
@@ -0,0 +1,453 @@
+package policyservice
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ditrit/badaas/configuration"
+	"github.com/ditrit/badaas/httperrors"
+	"github.com/ditrit/badaas/persistence/models"
+	"github.com/ditrit/badaas/persistence/repository"
+	"github.com/ditrit/badaas/services/groupservice"
+	"github.com/ditrit/badaas/services/rbacservice"
+	"github.com/google/uuid"
+	"github.com/noirbizarre/gonja"
+	"github.com/noirbizarre/gonja/exec"
+	"go.uber.org/zap"
+)
+
+// Allow to manage the policies stored in the database
+const PermissionPoliciesManage string = "policies:manage"
+
+// The sources a policy can come from
+const (
+	PolicySourceConfiguration string = "configuration"
+	PolicySourceDatabase      string = "database"
+)
+
+// Errors
+var (
+	HERRAccessDenied = httperrors.NewForbiddenError(
+		"access denied",
+		"you are not allowed to perform this action on this ressource",
+	)
+)
+
+// The result of the evaluation of one policy
+type PolicyEvaluation struct {
+	Policy     string
+	Source     string
+	Effect     string
+	Applicable bool
+	Reason     string
+}
+
+// The decision taken by the policy engine
+type Decision struct {
+	Allowed bool
+
+	// The name of the policy that took the decision, empty if no policy applied
+	Policy string
+
+	// Human readable explanation of the decision
+	Reason string
+
+	// The evaluation of every policy
+	Evaluations []PolicyEvaluation
+}
+
+// PolicyService evaluate attribute based access control policies
+type PolicyService interface {
+	// Evaluate the policies against the access request and return the decision
+	Evaluate(accessRequest AccessRequest) (*Decision, httperrors.HTTPError)
+	// Return an HTTPError if the access request is denied
+	Authorize(accessRequest AccessRequest) httperrors.HTTPError
+	// Return the policies declared in the configuration and stored in the database
+	GetPolicies() ([]*models.Policy, httperrors.HTTPError)
+	CreatePolicy(policy *models.Policy) httperrors.HTTPError
+	DeletePolicy(policyID uuid.UUID) httperrors.HTTPError
+}
+
+// Check interface compliance
+var _ PolicyService = (*policyServiceImpl)(nil)
+
+// The PolicyService concrete implementation
+type policyServiceImpl struct {
+	logger              *zap.Logger
+	policyRepository    repository.CRUDRepository[models.Policy, uuid.UUID]
+	rbacService         rbacservice.RBACService
+	groupService        groupservice.GroupService
+	policyConfiguration configuration.PolicyConfiguration
+
+	// compiled conditions, indexed by their source
+	conditions map[string]*exec.Template
+	mutex      sync.Mutex
+
+	// return the current time, replaced in tests
+	now func() time.Time
+}
+
+// PolicyService constructor
+//
+// Return an error if a policy declared in the configuration is not valid.
+func NewPolicyService(
+	logger *zap.Logger,
+	policyRepository repository.CRUDRepository[models.Policy, uuid.UUID],
+	rbacService rbacservice.RBACService,
+	groupService groupservice.GroupService,
+	policyConfiguration configuration.PolicyConfiguration,
+) (PolicyService, error) {
+	policyService := &policyServiceImpl{
+		logger:              logger,
+		policyRepository:    policyRepository,
+		rbacService:         rbacService,
+		groupService:        groupService,
+		policyConfiguration: policyConfiguration,
+		conditions:          make(map[string]*exec.Template),
+		now:                 time.Now,
+	}
+	for _, policyDefinition := range policyConfiguration.GetPolicies() {
+		herr := policyService.validatePolicy(makePolicy(policyDefinition))
+		if herr != nil {
+			return nil, fmt.Errorf("the policy %q declared in the configuration is not valid: %w", policyDefinition.Name, herr)
+		}
+	}
+	return policyService, nil
+}
+
+// Evaluate the policies against the access request
+//
+// The decision follows a deny-overrides algorithm: the access is denied if a deny policy applies,
+// allowed if at least one allow policy applies and denied if no policy applies.
+// A condition that can't be evaluated makes the access denied.
+func (policyService *policyServiceImpl) Evaluate(accessRequest AccessRequest) (*Decision, httperrors.HTTPError) {
+	policies, herr := policyService.GetPolicies()
+	if herr != nil {
+		return nil, herr
+	}
+	attributes, herr := policyService.makeAttributes(accessRequest)
+	if herr != nil {
+		return nil, herr
+	}
+	resourceType := accessRequest.getResource().ResourceType()
+
+	decision := &Decision{
+		Allowed: false,
+		Reason:  fmt.Sprintf("no policy allows the action %q on the resource %q", accessRequest.Action, resourceType),
+	}
+	var allowingEvaluation, denyingEvaluation *PolicyEvaluation
+	for _, policy := range policies {
+		evaluation := policyService.evaluatePolicy(policy, accessRequest.Action, resourceType, attributes)
+		decision.Evaluations = append(decision.Evaluations, evaluation)
+		if !evaluation.Applicable {
+			continue
+		}
+		if evaluation.Effect == models.PolicyEffectDeny && denyingEvaluation == nil {
+			denyingEvaluation = &evaluation
+		} else if evaluation.Effect == models.PolicyEffectAllow && allowingEvaluation == nil {
+			allowingEvaluation = &evaluation
+		}
+	}
+	if denyingEvaluation != nil {
+		decision.Policy = denyingEvaluation.Policy
+		decision.Reason = denyingEvaluation.Reason
+	} else if allowingEvaluation != nil {
+		decision.Allowed = true
+		decision.Policy = allowingEvaluation.Policy
+		decision.Reason = allowingEvaluation.Reason
+	}
+	policyService.explain(accessRequest, decision)
+	return decision, nil
+}
+
+// Return an HTTPError if the access request is denied
+func (policyService *policyServiceImpl) Authorize(accessRequest AccessRequest) httperrors.HTTPError {
+	decision, herr := policyService.Evaluate(accessRequest)
+	if herr != nil {
+		return herr
+	}
+	if !decision.Allowed {
+		return HERRAccessDenied
+	}
+	return nil
+}
+
+// Return the policies declared in the configuration and stored in the database
+//
+// The policies declared in the configuration have a nil ID.
+func (policyService *policyServiceImpl) GetPolicies() ([]*models.Policy, httperrors.HTTPError) {
+	policies := []*models.Policy{}
+	for _, policyDefinition := range policyService.policyConfiguration.GetPolicies() {
+		policies = append(policies, makePolicy(policyDefinition))
+	}
+	databasePolicies, herr := policyService.policyRepository.GetAll(repository.NewSortOption("name", false))
+	if herr != nil {
+		return nil, herr
+	}
+	return append(policies, databasePolicies...), nil
+}
+
+// Validate and store a policy in the database
+func (policyService *policyServiceImpl) CreatePolicy(policy *models.Policy) httperrors.HTTPError {
+	herr := policyService.validatePolicy(policy)
+	if herr != nil {
+		return herr
+	}
+	herr = policyService.policyRepository.Create(policy)
+	if herr != nil {
+		return herr
+	}
+	policyService.logger.Info("Successfully created a new policy", zap.String("policy", policy.Name))
+	return nil
+}
+
+// Delete a policy stored in the database
+func (policyService *policyServiceImpl) DeletePolicy(policyID uuid.UUID) httperrors.HTTPError {
+	policy, herr := policyService.policyRepository.GetByID(policyID)
+	if herr != nil {
+		return httperrors.NewErrorNotFound("policy", fmt.Sprintf("no policy found with id %q", policyID))
+	}
+	herr = policyService.policyRepository.Delete(policy)
+	if herr != nil {
+		return herr
+	}
+	policyService.logger.Info("Deleted policy", zap.String("policy", policy.Name))
+	return nil
+}
+
+// Check that a policy is well formed
+func (policyService *policyServiceImpl) validatePolicy(policy *models.Policy) httperrors.HTTPError {
+	if strings.TrimSpace(policy.Name) == "" {
+		return newInvalidPolicyError("the policy name can't be empty")
+	}
+	if policy.Effect != models.PolicyEffectAllow && policy.Effect != models.PolicyEffectDeny {
+		return newInvalidPolicyError(fmt.Sprintf("the effect must be either %q or %q", models.PolicyEffectAllow, models.PolicyEffectDeny))
+	}
+	if len(policy.GetActions()) == 0 {
+		return newInvalidPolicyError("the policy must apply to at least one action")
+	}
+	if len(policy.GetResources()) == 0 {
+		return newInvalidPolicyError("the policy must apply to at least one resource")
+	}
+	_, err := policyService.compileCondition(policy.Condition)
+	if err != nil {
+		return newInvalidPolicyError(fmt.Sprintf("the condition is not valid: %s", err.Error()))
+	}
+	return nil
+}
+
+// Evaluate one policy against the action, the resource type and the attributes of the request
+func (policyService *policyServiceImpl) evaluatePolicy(
+	policy *models.Policy,
+	action, resourceType string,
+	attributes gonja.Context,
+) PolicyEvaluation {
+	evaluation := PolicyEvaluation{
+		Policy: policy.Name,
+		Source: PolicySourceDatabase,
+		Effect: policy.Effect,
+	}
+	if policy.ID == uuid.Nil {
+		evaluation.Source = PolicySourceConfiguration
+	}
+	if !matchesAny(policy.GetActions(), action) {
+		evaluation.Reason = fmt.Sprintf("the action %q does not match %q", action, policy.Actions)
+		return evaluation
+	}
+	if !matchesAny(policy.GetResources(), resourceType) {
+		evaluation.Reason = fmt.Sprintf("the resource %q does not match %q", resourceType, policy.Resources)
+		return evaluation
+	}
+	if policy.Condition == "" {
+		evaluation.Applicable = true
+		evaluation.Reason = fmt.Sprintf("the policy %q applies unconditionally", policy.Name)
+		return evaluation
+	}
+	result, err := policyService.evaluateCondition(policy.Condition, attributes)
+	if err != nil {
+		// fail closed: a broken condition denies the access
+		evaluation.Applicable = true
+		evaluation.Effect = models.PolicyEffectDeny
+		evaluation.Reason = fmt.Sprintf("the condition %q of the policy %q can't be evaluated: %s", policy.Condition, policy.Name, err.Error())
+		return evaluation
+	}
+	evaluation.Applicable = result
+	if result {
+		evaluation.Reason = fmt.Sprintf("the condition %q of the policy %q is true", policy.Condition, policy.Name)
+	} else {
+		evaluation.Reason = fmt.Sprintf("the condition %q is false", policy.Condition)
+	}
+	return evaluation
+}
+
+// Evaluate a condition, return true if it holds
+func (policyService *policyServiceImpl) evaluateCondition(condition string, attributes gonja.Context) (bool, error) {
+	template, err := policyService.compileCondition(condition)
+	if err != nil {
+		return false, err
+	}
+	result, err := template.Execute(attributes)
+	if err != nil {
+		return false, err
+	}
+	return strings.TrimSpace(result) == "true", nil
+}
+
+// The jinja delimiters, refused in the conditions so that a condition can't close the if statement it is put in
+var templateDelimiters = []string{"{%", "%}", "{{", "}}", "{#", "#}"}
+
+// Compile a condition into a jinja template, the templates are cached
+func (policyService *policyServiceImpl) compileCondition(condition string) (*exec.Template, error) {
+	policyService.mutex.Lock()
+	defer policyService.mutex.Unlock()
+	template, ok := policyService.conditions[condition]
+	if ok {
+		return template, nil
+	}
+	for _, delimiter := range templateDelimiters {
+		if strings.Contains(condition, delimiter) {
+			return nil, fmt.Errorf("the condition can't contain %q", delimiter)
+		}
+	}
+	expression := condition
+	if strings.TrimSpace(expression) == "" {
+		expression = "true"
+	}
+	template, err := gonja.FromString(fmt.Sprintf("{%% if %s %%}true{%% endif %%}", expression))
+	if err != nil {
+		return nil, err
+	}
+	policyService.conditions[condition] = template
+	return template, nil
+}
+
+// Build the attributes available in the conditions
+func (policyService *policyServiceImpl) makeAttributes(accessRequest AccessRequest) (gonja.Context, httperrors.HTTPError) {
+	subject := map[string]any{
+		"authenticated": false,
+		"id":            "",
+		"session_id":    "",
+		"roles":         []string{},
+		"permissions":   []string{},
+		"groups":        []string{},
+		"organisations": []string{},
+	}
+	if accessRequest.Subject != nil {
+		subject["authenticated"] = true
+		subject["id"] = accessRequest.Subject.UserID.String()
+		subject["session_id"] = accessRequest.Subject.SessionUUID.String()
+		roles, herr := policyService.rbacService.GetUserRoles(accessRequest.Subject.UserID)
+		if herr != nil {
+			return nil, herr
+		}
+		roleNames := make([]string, 0, len(roles))
+		for _, role := range roles {
+			roleNames = append(roleNames, role.Name)
+		}
+		subject["roles"] = roleNames
+		permissions, herr := policyService.rbacService.GetUserPermissions(accessRequest.Subject.UserID)
+		if herr != nil {
+			return nil, herr
+		}
+		subject["permissions"] = permissions
+		groupIDs, herr := policyService.groupService.GetUserGroupIDs(accessRequest.Subject.UserID)
+		if herr != nil {
+			return nil, herr
+		}
+		subject["groups"] = groupIDs
+		organisationIDs, herr := policyService.groupService.GetUserOrganisationIDs(accessRequest.Subject.UserID)
+		if herr != nil {
+			return nil, herr
+		}
+		subject["organisations"] = organisationIDs
+	}
+	now := policyService.now()
+	context := accessRequest.Context
+	if context == nil {
+		context = map[string]any{}
+	}
+	return gonja.Context{
+		"subject":  subject,
+		"action":   accessRequest.Action,
+		"resource": accessRequest.getResource().ResourceAttributes(),
+		"context":  context,
+		"env": map[string]any{
+			"now":     now.Format(time.RFC3339),
+			"date":    now.Format("2006-01-02"),
+			"hour":    now.Hour(),
+			"minute":  now.Minute(),
+			"weekday": strings.ToLower(now.Weekday().String()),
+		},
+	}, nil
+}
+
+// Log the decision, in explain mode every policy evaluation is logged
+func (policyService *policyServiceImpl) explain(accessRequest AccessRequest, decision *Decision) {
+	userID := ""
+	if accessRequest.Subject != nil {
+		userID = accessRequest.Subject.UserID.String()
+	}
+	if !policyService.policyConfiguration.GetExplain() {
+		if !decision.Allowed {
+			policyService.logger.Debug("Access denied",
+				zap.String("userID", userID),
+				zap.String("action", accessRequest.Action),
+				zap.String("resource", accessRequest.getResource().ResourceType()),
+				zap.String("reason", decision.Reason),
+			)
+		}
+		return
+	}
+	for _, evaluation := range decision.Evaluations {
+		policyService.logger.Info("Policy evaluated",
+			zap.String("policy", evaluation.Policy),
+			zap.String("source", evaluation.Source),
+			zap.String("effect", evaluation.Effect),
+			zap.Bool("applicable", evaluation.Applicable),
+			zap.String("reason", evaluation.Reason),
+		)
+	}
+	message := "Access allowed"
+	if !decision.Allowed {
+		message = "Access denied"
+	}
+	policyService.logger.Info(message,
+		zap.String("userID", userID),
+		zap.String("action", accessRequest.Action),
+		zap.String("resource", accessRequest.getResource().ResourceType()),
+		zap.String("policy", decision.Policy),
+		zap.String("reason", decision.Reason),
+	)
+}
+
+// Return true if the value matches one of the patterns (see [path.Match])
+func matchesAny(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		matched, err := path.Match(pattern, value)
+		if err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// Create a policy from a policy declared in the configuration
+func makePolicy(policyDefinition configuration.PolicyDefinition) *models.Policy {
+	return &models.Policy{
+		Name:        policyDefinition.Name,
+		Description: policyDefinition.Description,
+		Effect:      policyDefinition.Effect,
+		Actions:     strings.Join(policyDefinition.Actions, ","),
+		Resources:   strings.Join(policyDefinition.Resources, ","),
+		Condition:   policyDefinition.Condition,
+	}
+}
+
+// Create an HTTPError for a malformed policy
+func newInvalidPolicyError(message string) httperrors.HTTPError {
+	return httperrors.NewHTTPError(http.StatusBadRequest, "invalid policy", message, nil, false)
+}
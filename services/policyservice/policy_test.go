@@ -0,0 +1,306 @@
+package policyservice
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ditrit/badaas/configuration"
+	configurationmocks "github.com/ditrit/badaas/mocks/configuration"
+	repositorymocks "github.com/ditrit/badaas/mocks/persistence/repository"
+	groupservicemocks "github.com/ditrit/badaas/mocks/services/groupservice"
+	rbacservicemocks "github.com/ditrit/badaas/mocks/services/rbacservice"
+	"github.com/ditrit/badaas/persistence/models"
+	"github.com/ditrit/badaas/services/sessionservice"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+type policyTestValues struct {
+	policyRepository    *repositorymocks.CRUDRepository[models.Policy, uuid.UUID]
+	rbacService         *rbacservicemocks.RBACService
+	groupService        *groupservicemocks.GroupService
+	policyConfiguration *configurationmocks.PolicyConfiguration
+	observedLogs        *observer.ObservedLogs
+	service             *policyServiceImpl
+}
+
+// make values for test
+func setupTest(t *testing.T, explain bool, policies ...configuration.PolicyDefinition) policyTestValues {
+	observedZapCore, observedLogs := observer.New(zap.DebugLevel)
+	observedLogger := zap.New(observedZapCore)
+	values := policyTestValues{
+		policyRepository:    repositorymocks.NewCRUDRepository[models.Policy, uuid.UUID](t),
+		rbacService:         rbacservicemocks.NewRBACService(t),
+		groupService:        groupservicemocks.NewGroupService(t),
+		policyConfiguration: configurationmocks.NewPolicyConfiguration(t),
+		observedLogs:        observedLogs,
+	}
+	values.policyConfiguration.On("GetPolicies").Return(policies)
+	values.policyConfiguration.On("GetExplain").Return(explain).Maybe()
+	service, err := NewPolicyService(
+		observedLogger,
+		values.policyRepository,
+		values.rbacService,
+		values.groupService,
+		values.policyConfiguration,
+	)
+	require.NoError(t, err)
+	values.service = service.(*policyServiceImpl)
+	values.service.now = func() time.Time {
+		return time.Date(2023, time.March, 6, 10, 30, 0, 0, time.UTC)
+	}
+	return values
+}
+
+// make an access request for a user without roles, member of the groups
+func makeAccessRequest(values policyTestValues, action string, resource Resource, groupIDs ...string) AccessRequest {
+	userID := uuid.New()
+	values.rbacService.On("GetUserRoles", userID).Return([]*models.Role{}, nil)
+	values.rbacService.On("GetUserPermissions", userID).Return([]string{}, nil)
+	values.groupService.On("GetUserGroupIDs", userID).Return(append([]string{}, groupIDs...), nil)
+	values.groupService.On("GetUserOrganisationIDs", userID).Return([]string{}, nil)
+	return AccessRequest{
+		Subject:  &sessionservice.SessionClaims{UserID: userID},
+		Action:   action,
+		Resource: resource,
+	}
+}
+
+var ownerCanEdit = configuration.PolicyDefinition{
+	Name:      "owner-can-edit",
+	Effect:    models.PolicyEffectAllow,
+	Actions:   []string{"posts:update"},
+	Resources: []string{"post"},
+	Condition: "subject.id == resource.owner",
+}
+
+func TestNewPolicyServiceInvalidConfiguration(t *testing.T) {
+	policyConfiguration := configurationmocks.NewPolicyConfiguration(t)
+	policyConfiguration.On("GetPolicies").Return([]configuration.PolicyDefinition{{
+		Name:      "broken",
+		Effect:    "maybe",
+		Actions:   []string{"posts:update"},
+		Resources: []string{"post"},
+	}})
+	_, err := NewPolicyService(
+		zap.L(),
+		repositorymocks.NewCRUDRepository[models.Policy, uuid.UUID](t),
+		rbacservicemocks.NewRBACService(t),
+		groupservicemocks.NewGroupService(t),
+		policyConfiguration,
+	)
+	assert.ErrorContains(t, err, "broken")
+}
+
+func TestEvaluateAllow(t *testing.T) {
+	values := setupTest(t, false, ownerCanEdit)
+	values.policyRepository.On("GetAll", mock.Anything).Return([]*models.Policy{}, nil)
+	accessRequest := makeAccessRequest(values, "posts:update", nil)
+	accessRequest.Resource = NewResource("post", map[string]any{"owner": accessRequest.Subject.UserID.String()})
+
+	decision, err := values.service.Evaluate(accessRequest)
+	require.NoError(t, err)
+	assert.True(t, decision.Allowed)
+	assert.Equal(t, "owner-can-edit", decision.Policy)
+	require.Len(t, decision.Evaluations, 1)
+	assert.Equal(t, PolicySourceConfiguration, decision.Evaluations[0].Source)
+}
+
+func TestEvaluateConditionFalse(t *testing.T) {
+	values := setupTest(t, false, ownerCanEdit)
+	values.policyRepository.On("GetAll", mock.Anything).Return([]*models.Policy{}, nil)
+	accessRequest := makeAccessRequest(values, "posts:update", NewResource("post", map[string]any{"owner": "someone else"}))
+
+	herr := values.service.Authorize(accessRequest)
+	assert.Equal(t, HERRAccessDenied, herr)
+}
+
+func TestEvaluateNoPolicy(t *testing.T) {
+	values := setupTest(t, false)
+	values.policyRepository.On("GetAll", mock.Anything).Return([]*models.Policy{}, nil)
+
+	decision, err := values.service.Evaluate(AccessRequest{Action: "posts:read", Resource: NewResource("post", nil)})
+	require.NoError(t, err)
+	assert.False(t, decision.Allowed)
+	assert.Empty(t, decision.Policy)
+}
+
+func TestEvaluateDenyOverrides(t *testing.T) {
+	values := setupTest(t, false, ownerCanEdit)
+	values.policyRepository.On("GetAll", mock.Anything).Return([]*models.Policy{{
+		BaseModel: models.BaseModel{ID: uuid.New()},
+		Name:      "no-archived-edit",
+		Effect:    models.PolicyEffectDeny,
+		Actions:   "posts:*",
+		Resources: "post",
+		Condition: "resource.state == 'archived'",
+	}}, nil)
+	accessRequest := makeAccessRequest(values, "posts:update", nil)
+	accessRequest.Resource = NewResource("post", map[string]any{
+		"owner": accessRequest.Subject.UserID.String(),
+		"state": "archived",
+	})
+
+	decision, err := values.service.Evaluate(accessRequest)
+	require.NoError(t, err)
+	assert.False(t, decision.Allowed)
+	assert.Equal(t, "no-archived-edit", decision.Policy)
+}
+
+func TestEvaluateSharedWithGroup(t *testing.T) {
+	values := setupTest(t, false, configuration.PolicyDefinition{
+		Name:      "shared-with-group",
+		Effect:    models.PolicyEffectAllow,
+		Actions:   []string{"documents:read"},
+		Resources: []string{"document"},
+		Condition: "resource.group in subject.groups",
+	})
+	values.policyRepository.On("GetAll", mock.Anything).Return([]*models.Policy{}, nil)
+	groupID := uuid.NewString()
+	document := NewResource("document", map[string]any{"group": groupID})
+
+	decision, err := values.service.Evaluate(makeAccessRequest(values, "documents:read", document, groupID))
+	require.NoError(t, err)
+	assert.True(t, decision.Allowed)
+
+	decision, err = values.service.Evaluate(makeAccessRequest(values, "documents:read", document))
+	require.NoError(t, err)
+	assert.False(t, decision.Allowed)
+}
+
+func TestEvaluateEnvironment(t *testing.T) {
+	values := setupTest(t, false, configuration.PolicyDefinition{
+		Name:      "office-hours",
+		Effect:    models.PolicyEffectAllow,
+		Actions:   []string{"*"},
+		Resources: []string{"*"},
+		Condition: "env.hour >= 9 and env.hour < 18 and env.weekday == 'monday' and context.ip == '10.0.0.1'",
+	})
+	values.policyRepository.On("GetAll", mock.Anything).Return([]*models.Policy{}, nil)
+
+	decision, err := values.service.Evaluate(AccessRequest{
+		Action:   "reports:read",
+		Resource: NewResource("report", nil),
+		Context:  map[string]any{"ip": "10.0.0.1"},
+	})
+	require.NoError(t, err)
+	assert.True(t, decision.Allowed)
+}
+
+func TestEvaluateConditionErrorFailsClosed(t *testing.T) {
+	values := setupTest(t, false, configuration.PolicyDefinition{
+		Name:      "allow-all",
+		Effect:    models.PolicyEffectAllow,
+		Actions:   []string{"*"},
+		Resources: []string{"*"},
+	})
+	// bypass the validation to simulate a policy that fails at evaluation time
+	values.policyRepository.On("GetAll", mock.Anything).Return([]*models.Policy{{
+		BaseModel: models.BaseModel{ID: uuid.New()},
+		Name:      "broken",
+		Effect:    models.PolicyEffectAllow,
+		Actions:   "*",
+		Resources: "*",
+		Condition: "resource.owner|unknownfilter",
+	}}, nil)
+
+	decision, err := values.service.Evaluate(AccessRequest{Action: "posts:read", Resource: NewResource("post", nil)})
+	require.NoError(t, err)
+	assert.False(t, decision.Allowed)
+	assert.Equal(t, "broken", decision.Policy)
+}
+
+// a condition closing the if statement it is put in would make the policy apply whatever the attributes
+const breakOutCondition = "false %}{% endif %}true{% if false"
+
+func TestCreatePolicyBreakOutCondition(t *testing.T) {
+	values := setupTest(t, false)
+	for _, condition := range []string{
+		breakOutCondition,
+		"subject.id == resource.owner or {{ true }}",
+		"subject.id == resource.owner {# comment #}",
+	} {
+		herr := values.service.CreatePolicy(&models.Policy{
+			Name:      "editors",
+			Effect:    models.PolicyEffectAllow,
+			Actions:   "posts:update",
+			Resources: "post",
+			Condition: condition,
+		})
+		assert.Error(t, herr, condition)
+	}
+}
+
+func TestEvaluateBreakOutConditionFailsClosed(t *testing.T) {
+	values := setupTest(t, false)
+	// stored before the conditions were validated
+	values.policyRepository.On("GetAll", mock.Anything).Return([]*models.Policy{{
+		BaseModel: models.BaseModel{ID: uuid.New()},
+		Name:      "editors",
+		Effect:    models.PolicyEffectAllow,
+		Actions:   "posts:update",
+		Resources: "post",
+		Condition: breakOutCondition,
+	}}, nil)
+
+	decision, err := values.service.Evaluate(AccessRequest{Action: "posts:update", Resource: NewResource("post", nil)})
+	require.NoError(t, err)
+	assert.False(t, decision.Allowed)
+}
+
+func TestEvaluateExplain(t *testing.T) {
+	values := setupTest(t, true, ownerCanEdit)
+	values.policyRepository.On("GetAll", mock.Anything).Return([]*models.Policy{}, nil)
+
+	_, err := values.service.Evaluate(AccessRequest{Action: "posts:update", Resource: NewResource("post", nil)})
+	require.NoError(t, err)
+	require.Equal(t, 2, values.observedLogs.Len())
+	assert.Equal(t, "Policy evaluated", values.observedLogs.All()[0].Message)
+	assert.Equal(t, "Access denied", values.observedLogs.All()[1].Message)
+}
+
+func TestCreatePolicy(t *testing.T) {
+	values := setupTest(t, false)
+	policy := &models.Policy{
+		Name:      "readers",
+		Effect:    models.PolicyEffectAllow,
+		Actions:   "posts:read",
+		Resources: "post",
+		Condition: "'reader' in subject.roles",
+	}
+	values.policyRepository.On("Create", policy).Return(nil)
+
+	assert.NoError(t, values.service.CreatePolicy(policy))
+}
+
+func TestCreatePolicyInvalid(t *testing.T) {
+	values := setupTest(t, false)
+
+	herr := values.service.CreatePolicy(&models.Policy{
+		Name:      "readers",
+		Effect:    models.PolicyEffectAllow,
+		Resources: "post",
+	})
+	assert.Error(t, herr)
+	herr = values.service.CreatePolicy(&models.Policy{
+		Name:      "readers",
+		Effect:    models.PolicyEffectAllow,
+		Actions:   "posts:read",
+		Resources: "post",
+		Condition: "subject.roles ==",
+	})
+	assert.Error(t, herr)
+}
+
+func TestDeletePolicyNotFound(t *testing.T) {
+	values := setupTest(t, false)
+	policyID := uuid.New()
+	values.policyRepository.On("GetByID", policyID).Return(nil, HERRAccessDenied)
+
+	herr := values.service.DeletePolicy(policyID)
+	assert.Error(t, herr)
+}
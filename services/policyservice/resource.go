@@ -0,0 +1,65 @@
+package policyservice
+
+import "github.com/ditrit/badaas/services/sessionservice"
+
+// A resource on which an access is evaluated
+type Resource interface {
+	// The type of the resource, matched against the resources of the policies
+	ResourceType() string
+
+	// The attributes of the resource, available as `resource` in the conditions
+	ResourceAttributes() map[string]any
+}
+
+// Check interface compliance
+var _ Resource = (*resourceImpl)(nil)
+
+// A generic resource
+type resourceImpl struct {
+	resourceType string
+	attributes   map[string]any
+}
+
+// Create a resource from its type and its attributes
+func NewResource(resourceType string, attributes map[string]any) Resource {
+	if attributes == nil {
+		attributes = map[string]any{}
+	}
+	return &resourceImpl{
+		resourceType: resourceType,
+		attributes:   attributes,
+	}
+}
+
+// Return the type of the resource
+func (resource *resourceImpl) ResourceType() string {
+	return resource.resourceType
+}
+
+// Return the attributes of the resource
+func (resource *resourceImpl) ResourceAttributes() map[string]any {
+	return resource.attributes
+}
+
+// Describe an access to evaluate
+type AccessRequest struct {
+	// The subject trying to access the resource, nil for an anonymous access
+	Subject *sessionservice.SessionClaims
+
+	// The action performed on the resource (ex: "posts:update")
+	Action string
+
+	// The target of the action
+	Resource Resource
+
+	// The attributes of the request (ex: method, ip), available as `context` in the conditions
+	Context map[string]any
+}
+
+// Return the resource of the request, an untyped resource without attributes if not set
+func (accessRequest AccessRequest) getResource() Resource {
+	if accessRequest.Resource == nil {
+		return NewResource("", nil)
+	}
+	return accessRequest.Resource
+}
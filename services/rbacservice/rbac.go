@@ -0,0 +1,514 @@
+package rbacservice
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/ditrit/badaas/httperrors"
+	"github.com/ditrit/badaas/persistence/models"
+	"github.com/ditrit/badaas/persistence/repository"
+	"github.com/ditrit/badaas/services/groupservice"
+	"github.com/ditrit/badaas/services/sessionservice"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// The well known permissions used by badaas
+const (
+	// Grant every permission
+	PermissionAll string = "*"
+	// Allow to manage the roles, their permissions and their assignment
+	PermissionRolesManage string = "roles:manage"
+)
+
+// The name of the role granted to the bootstrap admin
+const SuperAdminRoleName string = "superadmin"
+
+// Errors
+var (
+	HERRPermissionDenied = httperrors.NewForbiddenError(
+		"permission denied",
+		"you don't have the permission to access this ressource",
+	)
+)
+
+// RBACService handle roles, permissions and their assignment to users and groups
+//
+// A user is granted the roles assigned to it and the roles assigned to the groups it is a member of.
+type RBACService interface {
+	CreateRole(name, description string) (*models.Role, httperrors.HTTPError)
+	GetRoles() ([]*models.Role, httperrors.HTTPError)
+	GetRole(roleID uuid.UUID) (*models.Role, httperrors.HTTPError)
+	DeleteRole(roleID uuid.UUID) httperrors.HTTPError
+	GetRolePermissions(roleID uuid.UUID) ([]string, httperrors.HTTPError)
+	GrantPermission(roleID uuid.UUID, permission string) httperrors.HTTPError
+	RevokePermission(roleID uuid.UUID, permission string) httperrors.HTTPError
+	AssignRole(userID, roleID uuid.UUID) httperrors.HTTPError
+	UnassignRole(userID, roleID uuid.UUID) httperrors.HTTPError
+	AssignGroupRole(groupID, roleID uuid.UUID) httperrors.HTTPError
+	UnassignGroupRole(groupID, roleID uuid.UUID) httperrors.HTTPError
+	GetGroupRoles(groupID uuid.UUID) ([]*models.Role, httperrors.HTTPError)
+	GetUserRoles(userID uuid.UUID) ([]*models.Role, httperrors.HTTPError)
+	GetUserPermissions(userID uuid.UUID) ([]string, httperrors.HTTPError)
+	HasPermission(userID uuid.UUID, permission string) (bool, httperrors.HTTPError)
+	CheckPermission(userID uuid.UUID, permission string) httperrors.HTTPError
+	EnsureSuperAdminRole() (*models.Role, httperrors.HTTPError)
+}
+
+// Check interface compliance
+var _ RBACService = (*rbacServiceImpl)(nil)
+
+// The RBACService concrete implementation
+type rbacServiceImpl struct {
+	logger                   *zap.Logger
+	roleRepository           repository.CRUDRepository[models.Role, uuid.UUID]
+	rolePermissionRepository repository.CRUDRepository[models.RolePermission, uuid.UUID]
+	userRoleRepository       repository.CRUDRepository[models.UserRole, uuid.UUID]
+	groupRoleRepository      repository.CRUDRepository[models.GroupRole, uuid.UUID]
+	groupService             groupservice.GroupService
+}
+
+// RBACService constructor
+func NewRBACService(
+	logger *zap.Logger,
+	roleRepository repository.CRUDRepository[models.Role, uuid.UUID],
+	rolePermissionRepository repository.CRUDRepository[models.RolePermission, uuid.UUID],
+	userRoleRepository repository.CRUDRepository[models.UserRole, uuid.UUID],
+	groupRoleRepository repository.CRUDRepository[models.GroupRole, uuid.UUID],
+	groupService groupservice.GroupService,
+) RBACService {
+	return &rbacServiceImpl{
+		logger:                   logger,
+		roleRepository:           roleRepository,
+		rolePermissionRepository: rolePermissionRepository,
+		userRoleRepository:       userRoleRepository,
+		groupRoleRepository:      groupRoleRepository,
+		groupService:             groupService,
+	}
+}
+
+// Create a new role
+func (rbacService *rbacServiceImpl) CreateRole(name, description string) (*models.Role, httperrors.HTTPError) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, httperrors.NewHTTPError(http.StatusBadRequest, "invalid role", "the role name can't be empty", nil, false)
+	}
+	role := &models.Role{
+		Name:        name,
+		Description: description,
+	}
+	herr := rbacService.roleRepository.Create(role)
+	if herr != nil {
+		return nil, herr
+	}
+	rbacService.logger.Info("Successfully created a new role", zap.String("role", name))
+	return role, nil
+}
+
+// Return all the roles
+func (rbacService *rbacServiceImpl) GetRoles() ([]*models.Role, httperrors.HTTPError) {
+	return rbacService.roleRepository.GetAll(repository.NewSortOption("name", false))
+}
+
+// Return the role with the provided id
+func (rbacService *rbacServiceImpl) GetRole(roleID uuid.UUID) (*models.Role, httperrors.HTTPError) {
+	roles, herr := rbacService.roleRepository.Find(squirrel.Eq{"id": roleID.String()}, nil, nil)
+	if herr != nil {
+		return nil, herr
+	}
+	if !roles.HasContent {
+		return nil, httperrors.NewErrorNotFound("role", fmt.Sprintf("no role found with id %q", roleID))
+	}
+	return roles.Ressources[0], nil
+}
+
+// Delete a role, its permissions and its assignments
+func (rbacService *rbacServiceImpl) DeleteRole(roleID uuid.UUID) httperrors.HTTPError {
+	role, herr := rbacService.GetRole(roleID)
+	if herr != nil {
+		return herr
+	}
+	if role.Name == SuperAdminRoleName {
+		return httperrors.NewForbiddenError("role error", "the superadmin role can't be deleted")
+	}
+	rolePermissions, herr := rbacService.rolePermissionRepository.Find(squirrel.Eq{"role_id": roleID.String()}, nil, nil)
+	if herr != nil {
+		return herr
+	}
+	for _, rolePermission := range rolePermissions.Ressources {
+		herr = rbacService.rolePermissionRepository.Delete(rolePermission)
+		if herr != nil {
+			return herr
+		}
+	}
+	userRoles, herr := rbacService.userRoleRepository.Find(squirrel.Eq{"role_id": roleID.String()}, nil, nil)
+	if herr != nil {
+		return herr
+	}
+	for _, userRole := range userRoles.Ressources {
+		herr = rbacService.userRoleRepository.Delete(userRole)
+		if herr != nil {
+			return herr
+		}
+	}
+	groupRoles, herr := rbacService.groupRoleRepository.Find(squirrel.Eq{"role_id": roleID.String()}, nil, nil)
+	if herr != nil {
+		return herr
+	}
+	for _, groupRole := range groupRoles.Ressources {
+		herr = rbacService.groupRoleRepository.Delete(groupRole)
+		if herr != nil {
+			return herr
+		}
+	}
+	herr = rbacService.roleRepository.Delete(role)
+	if herr != nil {
+		return herr
+	}
+	rbacService.logger.Info("Deleted role", zap.String("role", role.Name))
+	return nil
+}
+
+// Return the permissions granted to a role
+func (rbacService *rbacServiceImpl) GetRolePermissions(roleID uuid.UUID) ([]string, httperrors.HTTPError) {
+	return rbacService.getPermissionsOfRoles([]string{roleID.String()})
+}
+
+// Grant a permission to a role. Granting a permission twice is a no-op.
+func (rbacService *rbacServiceImpl) GrantPermission(roleID uuid.UUID, permission string) httperrors.HTTPError {
+	permission = strings.TrimSpace(permission)
+	if permission == "" {
+		return httperrors.NewHTTPError(http.StatusBadRequest, "invalid permission", "the permission can't be empty", nil, false)
+	}
+	_, herr := rbacService.GetRole(roleID)
+	if herr != nil {
+		return herr
+	}
+	count, herr := rbacService.rolePermissionRepository.Count(
+		squirrel.Eq{"role_id": roleID.String(), "permission": permission},
+	)
+	if herr != nil {
+		return herr
+	}
+	if count > 0 {
+		return nil
+	}
+	herr = rbacService.rolePermissionRepository.Create(&models.RolePermission{
+		RoleID:     roleID,
+		Permission: permission,
+	})
+	if herr != nil {
+		return herr
+	}
+	rbacService.logger.Info("Granted permission",
+		zap.String("roleID", roleID.String()), zap.String("permission", permission))
+	return nil
+}
+
+// Revoke a permission from a role
+func (rbacService *rbacServiceImpl) RevokePermission(roleID uuid.UUID, permission string) httperrors.HTTPError {
+	rolePermissions, herr := rbacService.rolePermissionRepository.Find(
+		squirrel.Eq{"role_id": roleID.String(), "permission": permission}, nil, nil,
+	)
+	if herr != nil {
+		return herr
+	}
+	if !rolePermissions.HasContent {
+		return httperrors.NewErrorNotFound("permission",
+			fmt.Sprintf("the permission %q is not granted to the role %q", permission, roleID))
+	}
+	for _, rolePermission := range rolePermissions.Ressources {
+		herr = rbacService.rolePermissionRepository.Delete(rolePermission)
+		if herr != nil {
+			return herr
+		}
+	}
+	rbacService.logger.Info("Revoked permission",
+		zap.String("roleID", roleID.String()), zap.String("permission", permission))
+	return nil
+}
+
+// Assign a role to a user. Assigning a role twice is a no-op.
+func (rbacService *rbacServiceImpl) AssignRole(userID, roleID uuid.UUID) httperrors.HTTPError {
+	_, herr := rbacService.GetRole(roleID)
+	if herr != nil {
+		return herr
+	}
+	count, herr := rbacService.userRoleRepository.Count(
+		squirrel.Eq{"user_id": userID.String(), "role_id": roleID.String()},
+	)
+	if herr != nil {
+		return herr
+	}
+	if count > 0 {
+		return nil
+	}
+	herr = rbacService.userRoleRepository.Create(&models.UserRole{
+		UserID: userID,
+		RoleID: roleID,
+	})
+	if herr != nil {
+		return herr
+	}
+	rbacService.logger.Info("Assigned role",
+		zap.String("userID", userID.String()), zap.String("roleID", roleID.String()))
+	return nil
+}
+
+// Remove a role from a user
+func (rbacService *rbacServiceImpl) UnassignRole(userID, roleID uuid.UUID) httperrors.HTTPError {
+	userRoles, herr := rbacService.userRoleRepository.Find(
+		squirrel.Eq{"user_id": userID.String(), "role_id": roleID.String()}, nil, nil,
+	)
+	if herr != nil {
+		return herr
+	}
+	if !userRoles.HasContent {
+		return httperrors.NewErrorNotFound("role",
+			fmt.Sprintf("the role %q is not assigned to the user %q", roleID, userID))
+	}
+	for _, userRole := range userRoles.Ressources {
+		herr = rbacService.userRoleRepository.Delete(userRole)
+		if herr != nil {
+			return herr
+		}
+	}
+	rbacService.logger.Info("Unassigned role",
+		zap.String("userID", userID.String()), zap.String("roleID", roleID.String()))
+	return nil
+}
+
+// Assign a role to a group, the role is granted to every member of the group and of its subgroups.
+// Assigning a role twice is a no-op.
+func (rbacService *rbacServiceImpl) AssignGroupRole(groupID, roleID uuid.UUID) httperrors.HTTPError {
+	_, herr := rbacService.GetRole(roleID)
+	if herr != nil {
+		return herr
+	}
+	_, herr = rbacService.groupService.GetGroup(groupID)
+	if herr != nil {
+		return herr
+	}
+	count, herr := rbacService.groupRoleRepository.Count(
+		squirrel.Eq{"group_id": groupID.String(), "role_id": roleID.String()},
+	)
+	if herr != nil {
+		return herr
+	}
+	if count > 0 {
+		return nil
+	}
+	herr = rbacService.groupRoleRepository.Create(&models.GroupRole{
+		GroupID: groupID,
+		RoleID:  roleID,
+	})
+	if herr != nil {
+		return herr
+	}
+	rbacService.logger.Info("Assigned role to group",
+		zap.String("groupID", groupID.String()), zap.String("roleID", roleID.String()))
+	return nil
+}
+
+// Remove a role from a group
+func (rbacService *rbacServiceImpl) UnassignGroupRole(groupID, roleID uuid.UUID) httperrors.HTTPError {
+	groupRoles, herr := rbacService.groupRoleRepository.Find(
+		squirrel.Eq{"group_id": groupID.String(), "role_id": roleID.String()}, nil, nil,
+	)
+	if herr != nil {
+		return herr
+	}
+	if !groupRoles.HasContent {
+		return httperrors.NewErrorNotFound("role",
+			fmt.Sprintf("the role %q is not assigned to the group %q", roleID, groupID))
+	}
+	for _, groupRole := range groupRoles.Ressources {
+		herr = rbacService.groupRoleRepository.Delete(groupRole)
+		if herr != nil {
+			return herr
+		}
+	}
+	rbacService.logger.Info("Unassigned role from group",
+		zap.String("groupID", groupID.String()), zap.String("roleID", roleID.String()))
+	return nil
+}
+
+// Return the roles assigned to a group
+func (rbacService *rbacServiceImpl) GetGroupRoles(groupID uuid.UUID) ([]*models.Role, httperrors.HTTPError) {
+	roleIDs, herr := rbacService.getGroupRoleIDs([]string{groupID.String()})
+	if herr != nil {
+		return nil, herr
+	}
+	return rbacService.getRoles(roleIDs)
+}
+
+// Return the roles granted to a user, directly or through its groups
+func (rbacService *rbacServiceImpl) GetUserRoles(userID uuid.UUID) ([]*models.Role, httperrors.HTTPError) {
+	roleIDs, herr := rbacService.getUserRoleIDs(userID)
+	if herr != nil {
+		return nil, herr
+	}
+	return rbacService.getRoles(roleIDs)
+}
+
+// Return the permissions granted to a user through its roles and the roles of its groups
+func (rbacService *rbacServiceImpl) GetUserPermissions(userID uuid.UUID) ([]string, httperrors.HTTPError) {
+	roleIDs, herr := rbacService.getUserRoleIDs(userID)
+	if herr != nil {
+		return nil, herr
+	}
+	return rbacService.getPermissionsOfRoles(roleIDs)
+}
+
+// Return true if the user has been granted the permission
+func (rbacService *rbacServiceImpl) HasPermission(userID uuid.UUID, permission string) (bool, httperrors.HTTPError) {
+	grantedPermissions, herr := rbacService.GetUserPermissions(userID)
+	if herr != nil {
+		return false, herr
+	}
+	for _, grantedPermission := range grantedPermissions {
+		if PermissionMatches(grantedPermission, permission) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Return an HTTPError if the user has not been granted the permission
+func (rbacService *rbacServiceImpl) CheckPermission(userID uuid.UUID, permission string) httperrors.HTTPError {
+	ok, herr := rbacService.HasPermission(userID, permission)
+	if herr != nil {
+		return herr
+	}
+	if !ok {
+		rbacService.logger.Debug("Permission denied",
+			zap.String("userID", userID.String()), zap.String("permission", permission))
+		return HERRPermissionDenied
+	}
+	return nil
+}
+
+// Create the superadmin role if it does not exist and make sure it is granted every permission
+func (rbacService *rbacServiceImpl) EnsureSuperAdminRole() (*models.Role, httperrors.HTTPError) {
+	roles, herr := rbacService.roleRepository.Find(squirrel.Eq{"name": SuperAdminRoleName}, nil, nil)
+	if herr != nil {
+		return nil, herr
+	}
+	var superAdminRole *models.Role
+	if roles.HasContent {
+		superAdminRole = roles.Ressources[0]
+	} else {
+		superAdminRole, herr = rbacService.CreateRole(SuperAdminRoleName, "Is granted every permission")
+		if herr != nil {
+			return nil, herr
+		}
+	}
+	herr = rbacService.GrantPermission(superAdminRole.ID, PermissionAll)
+	if herr != nil {
+		return nil, herr
+	}
+	return superAdminRole, nil
+}
+
+// Return the ids of the roles assigned to a user or to the groups it is a member of
+func (rbacService *rbacServiceImpl) getUserRoleIDs(userID uuid.UUID) ([]string, httperrors.HTTPError) {
+	userRoles, herr := rbacService.userRoleRepository.Find(squirrel.Eq{"user_id": userID.String()}, nil, nil)
+	if herr != nil {
+		return nil, herr
+	}
+	roleIDs := make([]string, 0, len(userRoles.Ressources))
+	for _, userRole := range userRoles.Ressources {
+		roleIDs = append(roleIDs, userRole.RoleID.String())
+	}
+	groupIDs, herr := rbacService.groupService.GetUserGroupIDs(userID)
+	if herr != nil {
+		return nil, herr
+	}
+	groupRoleIDs, herr := rbacService.getGroupRoleIDs(groupIDs)
+	if herr != nil {
+		return nil, herr
+	}
+	return append(roleIDs, groupRoleIDs...), nil
+}
+
+// Return the ids of the roles assigned to the groups
+func (rbacService *rbacServiceImpl) getGroupRoleIDs(groupIDs []string) ([]string, httperrors.HTTPError) {
+	if len(groupIDs) == 0 {
+		return []string{}, nil
+	}
+	groupRoles, herr := rbacService.groupRoleRepository.Find(squirrel.Eq{"group_id": groupIDs}, nil, nil)
+	if herr != nil {
+		return nil, herr
+	}
+	roleIDs := make([]string, 0, len(groupRoles.Ressources))
+	for _, groupRole := range groupRoles.Ressources {
+		roleIDs = append(roleIDs, groupRole.RoleID.String())
+	}
+	return roleIDs, nil
+}
+
+// Return the roles with the provided ids, sorted by name
+func (rbacService *rbacServiceImpl) getRoles(roleIDs []string) ([]*models.Role, httperrors.HTTPError) {
+	if len(roleIDs) == 0 {
+		return []*models.Role{}, nil
+	}
+	roles, herr := rbacService.roleRepository.Find(
+		squirrel.Eq{"id": roleIDs}, nil, repository.NewSortOption("name", false),
+	)
+	if herr != nil {
+		return nil, herr
+	}
+	return roles.Ressources, nil
+}
+
+// Return the deduplicated permissions granted to the roles
+func (rbacService *rbacServiceImpl) getPermissionsOfRoles(roleIDs []string) ([]string, httperrors.HTTPError) {
+	if len(roleIDs) == 0 {
+		return []string{}, nil
+	}
+	rolePermissions, herr := rbacService.rolePermissionRepository.Find(squirrel.Eq{"role_id": roleIDs}, nil, nil)
+	if herr != nil {
+		return nil, herr
+	}
+	seen := make(map[string]bool)
+	permissions := make([]string, 0, len(rolePermissions.Ressources))
+	for _, rolePermission := range rolePermissions.Ressources {
+		if !seen[rolePermission.Permission] {
+			seen[rolePermission.Permission] = true
+			permissions = append(permissions, rolePermission.Permission)
+		}
+	}
+	return permissions, nil
+}
+
+// Return true if the granted permission satisfies the required permission.
+//
+// A granted permission matches if it is equal to the required one, if it is the "*" wildcard
+// or if it ends with ":*" and the required permission starts with the same prefix (ex: "users:*" matches "users:read").
+func PermissionMatches(grantedPermission, requiredPermission string) bool {
+	if grantedPermission == PermissionAll || grantedPermission == requiredPermission {
+		return true
+	}
+	if strings.HasSuffix(grantedPermission, ":*") {
+		return strings.HasPrefix(requiredPermission, strings.TrimSuffix(grantedPermission, "*"))
+	}
+	return false
+}
+
+// Return true if the request of the claims may use the permission, if its user has been granted it.
+//
+// The requests made with a session may use every permission, the ones made with an API key
+// only the permissions allowed by one of its scopes.
+func ClaimsAllow(sessionClaims *sessionservice.SessionClaims, permission string) bool {
+	if !sessionClaims.IsAPIKey() {
+		return true
+	}
+	for _, scope := range sessionClaims.Scopes {
+		if PermissionMatches(scope, permission) {
+			return true
+		}
+	}
+	return false
+}
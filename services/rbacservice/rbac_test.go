@@ -0,0 +1,252 @@
+package rbacservice_test
+
+import (
+	"testing"
+
+	"github.com/ditrit/badaas/httperrors"
+	repositorymocks "github.com/ditrit/badaas/mocks/persistence/repository"
+	groupservicemocks "github.com/ditrit/badaas/mocks/services/groupservice"
+	"github.com/ditrit/badaas/persistence/models"
+	"github.com/ditrit/badaas/persistence/pagination"
+	"github.com/ditrit/badaas/services/rbacservice"
+	"github.com/ditrit/badaas/services/sessionservice"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+type rbacTestValues struct {
+	roleRepository           *repositorymocks.CRUDRepository[models.Role, uuid.UUID]
+	rolePermissionRepository *repositorymocks.CRUDRepository[models.RolePermission, uuid.UUID]
+	userRoleRepository       *repositorymocks.CRUDRepository[models.UserRole, uuid.UUID]
+	groupRoleRepository      *repositorymocks.CRUDRepository[models.GroupRole, uuid.UUID]
+	groupService             *groupservicemocks.GroupService
+	observedLogs             *observer.ObservedLogs
+	service                  rbacservice.RBACService
+}
+
+// make values for test
+func setupTest(t *testing.T) rbacTestValues {
+	observedZapCore, observedLogs := observer.New(zap.DebugLevel)
+	observedLogger := zap.New(observedZapCore)
+	values := rbacTestValues{
+		roleRepository:           repositorymocks.NewCRUDRepository[models.Role, uuid.UUID](t),
+		rolePermissionRepository: repositorymocks.NewCRUDRepository[models.RolePermission, uuid.UUID](t),
+		userRoleRepository:       repositorymocks.NewCRUDRepository[models.UserRole, uuid.UUID](t),
+		groupRoleRepository:      repositorymocks.NewCRUDRepository[models.GroupRole, uuid.UUID](t),
+		groupService:             groupservicemocks.NewGroupService(t),
+		observedLogs:             observedLogs,
+	}
+	values.service = rbacservice.NewRBACService(
+		observedLogger,
+		values.roleRepository,
+		values.rolePermissionRepository,
+		values.userRoleRepository,
+		values.groupRoleRepository,
+		values.groupService,
+	)
+	return values
+}
+
+func TestPermissionMatches(t *testing.T) {
+	assert.True(t, rbacservice.PermissionMatches("*", "roles:manage"))
+	assert.True(t, rbacservice.PermissionMatches("roles:manage", "roles:manage"))
+	assert.True(t, rbacservice.PermissionMatches("users:*", "users:read"))
+	assert.False(t, rbacservice.PermissionMatches("users:*", "roles:manage"))
+	assert.False(t, rbacservice.PermissionMatches("users:read", "users:write"))
+	assert.False(t, rbacservice.PermissionMatches("users", "users:read"))
+}
+
+func TestClaimsAllow(t *testing.T) {
+	assert.True(t, rbacservice.ClaimsAllow(&sessionservice.SessionClaims{UserID: uuid.New()}, "roles:manage"))
+	apiKeyClaims := &sessionservice.SessionClaims{UserID: uuid.New(), APIKeyID: uuid.New(), Scopes: []string{"users:*"}}
+	assert.True(t, rbacservice.ClaimsAllow(apiKeyClaims, "users:manage"))
+	assert.False(t, rbacservice.ClaimsAllow(apiKeyClaims, "roles:manage"))
+	assert.False(t, rbacservice.ClaimsAllow(&sessionservice.SessionClaims{APIKeyID: uuid.New()}, "users:manage"))
+}
+
+func TestCreateRole(t *testing.T) {
+	values := setupTest(t)
+	values.roleRepository.On("Create", mock.Anything).Return(nil)
+
+	role, err := values.service.CreateRole(" editor ", "can edit")
+	require.NoError(t, err)
+	assert.Equal(t, "editor", role.Name)
+	assert.Equal(t, "can edit", role.Description)
+	require.Equal(t, 1, values.observedLogs.Len())
+	assert.Equal(t, "Successfully created a new role", values.observedLogs.All()[0].Message)
+}
+
+func TestCreateRoleEmptyName(t *testing.T) {
+	values := setupTest(t)
+
+	role, err := values.service.CreateRole("  ", "")
+	assert.Error(t, err)
+	assert.Nil(t, role)
+}
+
+func TestGetRoleNotFound(t *testing.T) {
+	values := setupTest(t)
+	values.roleRepository.On("Find", mock.Anything, nil, nil).
+		Return(pagination.NewPage([]*models.Role{}, 1, 10, 0), nil)
+
+	role, err := values.service.GetRole(uuid.New())
+	assert.Error(t, err)
+	assert.Nil(t, role)
+}
+
+func TestDeleteSuperAdminRole(t *testing.T) {
+	values := setupTest(t)
+	role := &models.Role{BaseModel: models.BaseModel{ID: uuid.New()}, Name: rbacservice.SuperAdminRoleName}
+	values.roleRepository.On("Find", mock.Anything, nil, nil).
+		Return(pagination.NewPage([]*models.Role{role}, 1, 10, 1), nil)
+
+	err := values.service.DeleteRole(role.ID)
+	assert.Error(t, err)
+}
+
+func TestAssignRole(t *testing.T) {
+	values := setupTest(t)
+	role := &models.Role{BaseModel: models.BaseModel{ID: uuid.New()}, Name: "editor"}
+	userID := uuid.New()
+	values.roleRepository.On("Find", mock.Anything, nil, nil).
+		Return(pagination.NewPage([]*models.Role{role}, 1, 10, 1), nil)
+	values.userRoleRepository.On("Count", mock.Anything).Return(uint(0), nil)
+	values.userRoleRepository.On("Create", &models.UserRole{UserID: userID, RoleID: role.ID}).Return(nil)
+
+	err := values.service.AssignRole(userID, role.ID)
+	assert.NoError(t, err)
+}
+
+func TestAssignRoleAlreadyAssigned(t *testing.T) {
+	values := setupTest(t)
+	role := &models.Role{BaseModel: models.BaseModel{ID: uuid.New()}, Name: "editor"}
+	values.roleRepository.On("Find", mock.Anything, nil, nil).
+		Return(pagination.NewPage([]*models.Role{role}, 1, 10, 1), nil)
+	values.userRoleRepository.On("Count", mock.Anything).Return(uint(1), nil)
+
+	err := values.service.AssignRole(uuid.New(), role.ID)
+	assert.NoError(t, err)
+	values.userRoleRepository.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestHasPermission(t *testing.T) {
+	values := setupTest(t)
+	userID := uuid.New()
+	roleID := uuid.New()
+	values.userRoleRepository.On("Find", mock.Anything, nil, nil).
+		Return(pagination.NewPage([]*models.UserRole{{UserID: userID, RoleID: roleID}}, 1, 10, 1), nil)
+	values.groupService.On("GetUserGroupIDs", userID).Return([]string{}, nil)
+	values.rolePermissionRepository.On("Find", mock.Anything, nil, nil).
+		Return(pagination.NewPage([]*models.RolePermission{
+			{RoleID: roleID, Permission: "users:*"},
+			{RoleID: roleID, Permission: "users:*"},
+		}, 1, 10, 2), nil)
+
+	permissions, err := values.service.GetUserPermissions(userID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"users:*"}, permissions)
+
+	ok, err := values.service.HasPermission(userID, "users:read")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = values.service.HasPermission(userID, rbacservice.PermissionRolesManage)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestHasPermissionNoRole(t *testing.T) {
+	values := setupTest(t)
+	values.userRoleRepository.On("Find", mock.Anything, nil, nil).
+		Return(pagination.NewPage([]*models.UserRole{}, 1, 10, 0), nil)
+	values.groupService.On("GetUserGroupIDs", mock.Anything).Return([]string{}, nil)
+
+	ok, err := values.service.HasPermission(uuid.New(), rbacservice.PermissionRolesManage)
+	require.NoError(t, err)
+	assert.False(t, ok)
+	values.rolePermissionRepository.AssertNotCalled(t, "Find", mock.Anything, nil, nil)
+}
+
+func TestCheckPermission(t *testing.T) {
+	values := setupTest(t)
+	values.userRoleRepository.On("Find", mock.Anything, nil, nil).
+		Return(pagination.NewPage([]*models.UserRole{}, 1, 10, 0), nil)
+	values.groupService.On("GetUserGroupIDs", mock.Anything).Return([]string{}, nil)
+
+	err := values.service.CheckPermission(uuid.New(), rbacservice.PermissionRolesManage)
+	assert.Equal(t, rbacservice.HERRPermissionDenied, err)
+}
+
+func TestCheckPermissionDatabaseError(t *testing.T) {
+	values := setupTest(t)
+	values.userRoleRepository.On("Find", mock.Anything, nil, nil).
+		Return(nil, httperrors.AnError)
+
+	err := values.service.CheckPermission(uuid.New(), rbacservice.PermissionRolesManage)
+	assert.Equal(t, httperrors.AnError, err)
+}
+
+func TestEnsureSuperAdminRole(t *testing.T) {
+	values := setupTest(t)
+	values.roleRepository.On("Find", mock.Anything, nil, nil).
+		Return(pagination.NewPage([]*models.Role{}, 1, 10, 0), nil).Once()
+	values.roleRepository.On("Create", mock.Anything).Return(nil)
+	values.roleRepository.On("Find", mock.Anything, nil, nil).
+		Return(pagination.NewPage([]*models.Role{{Name: rbacservice.SuperAdminRoleName}}, 1, 10, 1), nil)
+	values.rolePermissionRepository.On("Count", mock.Anything).Return(uint(0), nil)
+	values.rolePermissionRepository.On("Create", mock.Anything).Return(nil)
+
+	role, err := values.service.EnsureSuperAdminRole()
+	require.NoError(t, err)
+	assert.Equal(t, rbacservice.SuperAdminRoleName, role.Name)
+	values.rolePermissionRepository.AssertCalled(t, "Create", &models.RolePermission{
+		RoleID:     role.ID,
+		Permission: rbacservice.PermissionAll,
+	})
+}
+
+func TestHasPermissionThroughGroup(t *testing.T) {
+	values := setupTest(t)
+	userID := uuid.New()
+	groupID := uuid.New()
+	roleID := uuid.New()
+	values.userRoleRepository.On("Find", mock.Anything, nil, nil).
+		Return(pagination.NewPage([]*models.UserRole{}, 1, 10, 0), nil)
+	values.groupService.On("GetUserGroupIDs", userID).Return([]string{groupID.String()}, nil)
+	values.groupRoleRepository.On("Find", mock.Anything, nil, nil).
+		Return(pagination.NewPage([]*models.GroupRole{{GroupID: groupID, RoleID: roleID}}, 1, 10, 1), nil)
+	values.rolePermissionRepository.On("Find", mock.Anything, nil, nil).
+		Return(pagination.NewPage([]*models.RolePermission{{RoleID: roleID, Permission: "posts:*"}}, 1, 10, 1), nil)
+
+	ok, err := values.service.HasPermission(userID, "posts:read")
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestAssignGroupRole(t *testing.T) {
+	values := setupTest(t)
+	role := &models.Role{BaseModel: models.BaseModel{ID: uuid.New()}, Name: "editor"}
+	groupID := uuid.New()
+	values.roleRepository.On("Find", mock.Anything, nil, nil).
+		Return(pagination.NewPage([]*models.Role{role}, 1, 10, 1), nil)
+	values.groupService.On("GetGroup", groupID).Return(&models.Group{Name: "team"}, nil)
+	values.groupRoleRepository.On("Count", mock.Anything).Return(uint(0), nil)
+	values.groupRoleRepository.On("Create", &models.GroupRole{GroupID: groupID, RoleID: role.ID}).Return(nil)
+
+	err := values.service.AssignGroupRole(groupID, role.ID)
+	assert.NoError(t, err)
+}
+
+func TestUnassignGroupRoleNotAssigned(t *testing.T) {
+	values := setupTest(t)
+	values.groupRoleRepository.On("Find", mock.Anything, nil, nil).
+		Return(pagination.NewPage([]*models.GroupRole{}, 1, 10, 0), nil)
+
+	err := values.service.UnassignGroupRole(uuid.New(), uuid.New())
+	assert.Error(t, err)
+	values.groupRoleRepository.AssertNotCalled(t, "Delete", mock.Anything)
+}
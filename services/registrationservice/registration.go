@@ -0,0 +1,146 @@
+package registrationservice
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/ditrit/badaas/configuration"
+	"github.com/ditrit/badaas/httperrors"
+	"github.com/ditrit/badaas/persistence/models"
+	"github.com/ditrit/badaas/persistence/models/dto"
+	"github.com/ditrit/badaas/services/userservice"
+	validator "github.com/ditrit/badaas/validators"
+	"go.uber.org/zap"
+)
+
+// The maximum length of a username
+const MaxUsernameLength = 64
+
+// Errors
+var (
+	HERRRegistrationDisabled = httperrors.NewForbiddenError("registration disabled", "the registration of new users is disabled")
+)
+
+// RegistrationService let the users create their account
+type RegistrationService interface {
+	// Check the registration against the configured policy and create the user
+	Register(registerDTO dto.DTORegister) (*models.User, httperrors.HTTPError)
+}
+
+// Check interface compliance
+var _ RegistrationService = (*registrationServiceImpl)(nil)
+
+// The RegistrationService concrete implementation
+type registrationServiceImpl struct {
+	logger                    *zap.Logger
+	userService               userservice.UserService
+	registrationConfiguration configuration.RegistrationConfiguration
+}
+
+// RegistrationService constructor
+func NewRegistrationService(
+	logger *zap.Logger,
+	userService userservice.UserService,
+	registrationConfiguration configuration.RegistrationConfiguration,
+) RegistrationService {
+	return &registrationServiceImpl{
+		logger:                    logger,
+		userService:               userService,
+		registrationConfiguration: registrationConfiguration,
+	}
+}
+
+// Check the registration against the configured policy and create the user
+//
+// Every invalid field is reported in the returned error.
+func (registrationService *registrationServiceImpl) Register(registerDTO dto.DTORegister) (*models.User, httperrors.HTTPError) {
+	if !registrationService.registrationConfiguration.GetEnabled() {
+		return nil, HERRRegistrationDisabled
+	}
+	fields := map[string]string{}
+	username := strings.TrimSpace(registerDTO.Username)
+	if username == "" {
+		fields["username"] = "the username can't be empty"
+	} else if len(username) > MaxUsernameLength {
+		fields["username"] = "the username is too long"
+	}
+	email, err := validator.ValidEmail(registerDTO.Email)
+	if err != nil {
+		fields["email"] = "the email is not valid"
+	} else if !registrationService.isDomainAllowed(email) {
+		fields["email"] = "the domain of the email is not allowed"
+	}
+	if registerDTO.Password == "" {
+		fields["password"] = "the password can't be empty"
+	}
+	if !registrationService.isInvitationCodeValid(registerDTO.InvitationCode) {
+		fields["invitationCode"] = "the invitation code is not valid"
+	}
+	if registerDTO.Locale != "" && !userservice.IsValidLocale(registerDTO.Locale) {
+		fields["locale"] = "the locale is not valid"
+	}
+	if len(fields) > 0 {
+		return nil, httperrors.NewValidationError("the registration is not valid", fields)
+	}
+
+	user, err := registrationService.userService.NewUser(username, email, registerDTO.Password)
+	if err != nil {
+		herr, ok := err.(*httperrors.HTTPErrorImpl)
+		if !ok {
+			return nil, httperrors.NewValidationError("the registration is not valid",
+				map[string]string{"email": "the email is not valid"})
+		}
+		if herr.Status == http.StatusConflict {
+			return nil, httperrors.NewValidationError("the registration is not valid",
+				map[string]string{"email": "the email is already used"})
+		}
+		return nil, herr
+	}
+	registrationService.logger.Info("User registered", zap.String("userID", user.ID.String()))
+	if registerDTO.Locale != "" {
+		// the mails are sent in the default locale if this fails
+		herr := registrationService.userService.SetLocale(user.ID, registerDTO.Locale)
+		if herr != nil {
+			registrationService.logger.Warn("Failed to set the locale of the user",
+				zap.String("userID", user.ID.String()), zap.Error(herr))
+		}
+	}
+	// the user can ask for a new verification email if this one fails
+	herr := registrationService.userService.SendEmailVerification(user.ID)
+	if herr != nil {
+		registrationService.logger.Warn("Failed to send the verification email",
+			zap.String("userID", user.ID.String()), zap.Error(herr))
+	}
+	return user, nil
+}
+
+// Return true if the domain of the email is allowed to register
+func (registrationService *registrationServiceImpl) isDomainAllowed(email string) bool {
+	allowedDomains := registrationService.registrationConfiguration.GetAllowedDomains()
+	if len(allowedDomains) == 0 {
+		return true
+	}
+	domain := strings.ToLower(email[strings.LastIndex(email, "@")+1:])
+	for _, allowedDomain := range allowedDomains {
+		if domain == allowedDomain {
+			return true
+		}
+	}
+	return false
+}
+
+// Return true if no invitation code is required or if the code is one of the configured ones
+func (registrationService *registrationServiceImpl) isInvitationCodeValid(code string) bool {
+	invitationCodes := registrationService.registrationConfiguration.GetInvitationCodes()
+	if len(invitationCodes) == 0 {
+		return true
+	}
+	valid := false
+	for _, invitationCode := range invitationCodes {
+		if subtle.ConstantTimeCompare([]byte(code), []byte(invitationCode)) == 1 {
+			valid = true
+		}
+	}
+	return valid
+}
@@ -0,0 +1,88 @@
+package registrationservice_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/ditrit/badaas/httperrors"
+	mocksConfiguration "github.com/ditrit/badaas/mocks/configuration"
+	mocksUserService "github.com/ditrit/badaas/mocks/services/userservice"
+	"github.com/ditrit/badaas/persistence/models"
+	"github.com/ditrit/badaas/persistence/models/dto"
+	"github.com/ditrit/badaas/services/registrationservice"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func setupTest(
+	t *testing.T,
+	enabled bool,
+	allowedDomains, invitationCodes []string,
+) (*mocksUserService.UserService, registrationservice.RegistrationService) {
+	userService := mocksUserService.NewUserService(t)
+	registrationConfiguration := mocksConfiguration.NewRegistrationConfiguration(t)
+	registrationConfiguration.On("GetEnabled").Return(enabled)
+	registrationConfiguration.On("GetAllowedDomains").Return(allowedDomains).Maybe()
+	registrationConfiguration.On("GetInvitationCodes").Return(invitationCodes).Maybe()
+	return userService, registrationservice.NewRegistrationService(zap.L(), userService, registrationConfiguration)
+}
+
+func TestRegisterDisabled(t *testing.T) {
+	_, service := setupTest(t, false, nil, nil)
+
+	user, err := service.Register(dto.DTORegister{Username: "bob", Email: "bob@email.com", Password: "1234"})
+	assert.Equal(t, registrationservice.HERRRegistrationDisabled, err)
+	assert.Nil(t, user)
+}
+
+func TestRegister(t *testing.T) {
+	userService, service := setupTest(t, true, []string{"email.com"}, []string{"welcome"})
+	createdUser := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Username: "bob", Email: "bob@Email.com"}
+	userService.On("NewUser", "bob", "bob@Email.com", "1234").Return(createdUser, nil)
+	userService.On("SetLocale", createdUser.ID, "fr-FR").Return(nil)
+	userService.On("SendEmailVerification", createdUser.ID).Return(nil)
+
+	user, err := service.Register(dto.DTORegister{
+		Username:       " bob ",
+		Email:          "bob@Email.com",
+		Password:       "1234",
+		InvitationCode: "welcome",
+		Locale:         "fr-FR",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, createdUser, user)
+}
+
+func TestRegisterFieldErrors(t *testing.T) {
+	userService, service := setupTest(t, true, []string{"email.com"}, []string{"welcome"})
+
+	user, err := service.Register(dto.DTORegister{
+		Username:       "",
+		Email:          "bob@other.com",
+		InvitationCode: "wrong",
+		Locale:         "not a locale",
+	})
+	require.Error(t, err)
+	assert.Nil(t, user)
+	assert.Equal(t, map[string]string{
+		"username":       "the username can't be empty",
+		"email":          "the domain of the email is not allowed",
+		"password":       "the password can't be empty",
+		"invitationCode": "the invitation code is not valid",
+		"locale":         "the locale is not valid",
+	}, err.(*httperrors.HTTPErrorImpl).Fields)
+	userService.AssertNotCalled(t, "NewUser")
+}
+
+func TestRegisterEmailAlreadyUsed(t *testing.T) {
+	userService, service := setupTest(t, true, nil, nil)
+	userService.On("NewUser", "bob", "bob@email.com", "1234").
+		Return(nil, httperrors.NewHTTPError(http.StatusConflict, "models.User already exist in database", "", nil, false))
+
+	user, err := service.Register(dto.DTORegister{Username: "bob", Email: "bob@email.com", Password: "1234"})
+	require.Error(t, err)
+	assert.Nil(t, user)
+	assert.Equal(t, map[string]string{"email": "the email is already used"}, err.(*httperrors.HTTPErrorImpl).Fields)
+}
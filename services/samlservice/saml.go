@@ -0,0 +1,409 @@
+package samlservice
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/ditrit/badaas/configuration"
+	"github.com/ditrit/badaas/httperrors"
+	"github.com/ditrit/badaas/persistence/models"
+	"github.com/ditrit/badaas/persistence/repository"
+	"github.com/ditrit/badaas/services/auth/protocols/saml"
+	"github.com/ditrit/badaas/services/userservice"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Errors
+var (
+	HERRSAMLDisabled = httperrors.NewErrorNotFound("saml", "the SAML login is not enabled")
+	HERRInvalidState = httperrors.NewHTTPError(http.StatusBadRequest, "invalid state",
+		"the SAML login is invalid or expired, please start again", nil, false)
+	HERRInvalidAssertion = httperrors.NewUnauthorizedError("invalid assertion", "the identity could not be verified")
+	HERRAccountNotLinked = httperrors.NewForbiddenError("account not linked",
+		"no account is linked to this identity")
+)
+
+// SAMLService log the users in with the SAML identity provider, badaas being the service provider
+type SAMLService interface {
+	// Return the metadata of the service provider
+	GetMetadata() ([]byte, httperrors.HTTPError)
+	// Start a login, return the url of the identity provider the user is redirected to
+	// and the id of the AuthnRequest, to be kept by the browser
+	BeginLogin() (string, string, httperrors.HTTPError)
+	// Verify the response posted by the identity provider to the AuthnRequest started by the browser
+	// and return the user of the identity, the user is linked or created if it is unknown and the configuration allows it
+	FinishLogin(encodedResponse, requestID string) (*models.User, httperrors.HTTPError)
+}
+
+// Check interface compliance
+var _ SAMLService = (*samlServiceImpl)(nil)
+
+// SAMLService implementation
+type samlServiceImpl struct {
+	logger                    *zap.Logger
+	samlConfiguration         configuration.SAMLConfiguration
+	samlIdentityRepository    repository.CRUDRepository[models.SAMLIdentity, uuid.UUID]
+	samlAuthRequestRepository repository.CRUDRepository[models.SAMLAuthRequest, uuid.UUID]
+	samlAssertionRepository   repository.CRUDRepository[models.SAMLAssertion, uuid.UUID]
+	userService               userservice.UserService
+
+	// the service provider, created with the keys and the certificates on the first use
+	mutex           sync.Mutex
+	serviceProvider *saml.ServiceProvider
+}
+
+// SAMLService constructor
+func NewSAMLService(
+	logger *zap.Logger,
+	samlConfiguration configuration.SAMLConfiguration,
+	samlIdentityRepository repository.CRUDRepository[models.SAMLIdentity, uuid.UUID],
+	samlAuthRequestRepository repository.CRUDRepository[models.SAMLAuthRequest, uuid.UUID],
+	samlAssertionRepository repository.CRUDRepository[models.SAMLAssertion, uuid.UUID],
+	userService userservice.UserService,
+) SAMLService {
+	return &samlServiceImpl{
+		logger:                    logger,
+		samlConfiguration:         samlConfiguration,
+		samlIdentityRepository:    samlIdentityRepository,
+		samlAuthRequestRepository: samlAuthRequestRepository,
+		samlAssertionRepository:   samlAssertionRepository,
+		userService:               userService,
+	}
+}
+
+// Return the metadata of the service provider
+func (samlService *samlServiceImpl) GetMetadata() ([]byte, httperrors.HTTPError) {
+	serviceProvider, herr := samlService.getServiceProvider()
+	if herr != nil {
+		return nil, herr
+	}
+	metadata, err := serviceProvider.Metadata()
+	if err != nil {
+		return nil, httperrors.NewInternalServerError("metadata error", "failed to write the metadata", err)
+	}
+	return metadata, nil
+}
+
+// Start a login, return the url of the identity provider the user is redirected to
+// and the id of the AuthnRequest, to be kept by the browser
+func (samlService *samlServiceImpl) BeginLogin() (string, string, httperrors.HTTPError) {
+	serviceProvider, herr := samlService.getServiceProvider()
+	if herr != nil {
+		return "", "", herr
+	}
+	request, err := saml.NewAuthnRequest()
+	if err != nil {
+		return "", "", httperrors.NewInternalServerError("request error", "failed to generate an AuthnRequest", err)
+	}
+	redirectURL, err := serviceProvider.RedirectURL(request, "")
+	if err != nil {
+		return "", "", httperrors.NewInternalServerError("request error", "failed to sign the AuthnRequest", err)
+	}
+	herr = samlService.samlAuthRequestRepository.Create(&models.SAMLAuthRequest{
+		RequestID: request.ID,
+		ExpiresAt: time.Now().Add(samlService.samlConfiguration.GetRequestDuration()),
+	})
+	if herr != nil {
+		return "", "", herr
+	}
+	return redirectURL, request.ID, nil
+}
+
+// Verify the response posted by the identity provider to the AuthnRequest started by the browser
+// and return the user of the identity, the user is linked or created if it is unknown and the configuration allows it
+//
+// The response must answer the pending AuthnRequest of the browser, which can't be answered twice,
+// and its assertion can't be used twice.
+func (samlService *samlServiceImpl) FinishLogin(encodedResponse, requestID string) (*models.User, httperrors.HTTPError) {
+	serviceProvider, herr := samlService.getServiceProvider()
+	if herr != nil {
+		return nil, herr
+	}
+	assertion, err := serviceProvider.ParseResponse(encodedResponse)
+	if errors.Is(err, saml.ErrInvalidSignature) {
+		samlService.logger.Warn("Rejected a SAML response with an invalid signature", zap.Error(err))
+		return nil, HERRInvalidAssertion
+	}
+	if err != nil {
+		samlService.logger.Info("Rejected a SAML response", zap.Error(err))
+		return nil, HERRInvalidAssertion
+	}
+	if subtle.ConstantTimeCompare([]byte(assertion.InResponseTo), []byte(requestID)) != 1 {
+		return nil, HERRInvalidState
+	}
+	herr = samlService.consumeAuthRequest(assertion.InResponseTo)
+	if herr != nil {
+		return nil, herr
+	}
+	herr = samlService.consumeAssertion(assertion)
+	if herr != nil {
+		return nil, herr
+	}
+	user, herr := samlService.getUser(assertion)
+	if herr != nil {
+		return nil, herr
+	}
+	herr = samlService.userService.CheckCanLogIn(user)
+	if herr != nil {
+		return nil, herr
+	}
+	return user, nil
+}
+
+// Return the user of the identity, linking or creating it if allowed
+//
+// The username of a known user is updated from the attributes.
+func (samlService *samlServiceImpl) getUser(assertion *saml.Assertion) (*models.User, httperrors.HTTPError) {
+	email := samlService.getEmail(assertion)
+	username := assertion.Attribute(samlService.samlConfiguration.GetUsernameAttribute())
+	identities, herr := samlService.samlIdentityRepository.Find(squirrel.Eq{"name_id": assertion.NameID}, nil, nil)
+	if herr != nil {
+		return nil, herr
+	}
+	if identities.HasContent {
+		identity := identities.Ressources[0]
+		if identity.Email != email {
+			identity.Email = email
+			herr = samlService.samlIdentityRepository.Save(identity)
+			if herr != nil {
+				return nil, herr
+			}
+		}
+		user, herr := samlService.userService.GetUserByID(identity.UserID)
+		if herr != nil {
+			return nil, herr
+		}
+		if username != "" && username != user.Username {
+			return samlService.userService.UpdateUser(user.ID, username, "")
+		}
+		return user, nil
+	}
+
+	user, herr := samlService.findOrCreateUser(email, username)
+	if herr != nil {
+		return nil, herr
+	}
+	herr = samlService.samlIdentityRepository.Create(&models.SAMLIdentity{
+		UserID: user.ID,
+		NameID: assertion.NameID,
+		Email:  email,
+	})
+	if herr != nil {
+		return nil, herr
+	}
+	samlService.logger.Info("Linked a SAML identity", zap.String("userID", user.ID.String()))
+	return user, nil
+}
+
+// Return the user with the email asserted by the identity provider if the link by email is allowed,
+// or create a new user if the provisioning is allowed
+//
+// The emails asserted by the identity provider are only trusted to link an account if configured so.
+func (samlService *samlServiceImpl) findOrCreateUser(email, username string) (*models.User, httperrors.HTTPError) {
+	if email == "" {
+		samlService.logger.Info("Refused a SAML identity without email")
+		return nil, HERRAccountNotLinked
+	}
+	user, herr := samlService.userService.GetUserByEmail(email)
+	if herr != nil {
+		if impl, ok := herr.(*httperrors.HTTPErrorImpl); !ok || impl.Status != http.StatusNotFound {
+			return nil, herr
+		}
+	}
+	if user != nil {
+		if !samlService.samlConfiguration.GetLinkByEmail() {
+			samlService.logger.Info("Refused to link a SAML identity to an existing user",
+				zap.String("userID", user.ID.String()))
+			return nil, HERRAccountNotLinked
+		}
+		return user, nil
+	}
+	if !samlService.samlConfiguration.GetProvisioning() {
+		return nil, HERRAccountNotLinked
+	}
+	if username == "" {
+		username = strings.SplitN(email, "@", 2)[0]
+	}
+	// the user can only log in with the identity provider until it resets its password
+	password, err := randomPassword()
+	if err != nil {
+		return nil, httperrors.NewInternalServerError("password error", "failed to generate a password", err)
+	}
+	user, err = samlService.userService.NewSystemUser(username, email, password)
+	if err != nil {
+		var herr httperrors.HTTPError
+		if errors.As(err, &herr) {
+			return nil, herr
+		}
+		return nil, httperrors.NewHTTPError(http.StatusBadRequest, "invalid identity", err.Error(), nil, false)
+	}
+	herr = samlService.userService.MarkEmailVerified(user.ID)
+	if herr != nil {
+		return nil, herr
+	}
+	user.EmailVerified = true
+	return user, nil
+}
+
+// Return the email of the attributes, or the name id if it is an email address
+func (samlService *samlServiceImpl) getEmail(assertion *saml.Assertion) string {
+	email := assertion.Attribute(samlService.samlConfiguration.GetEmailAttribute())
+	if email == "" && assertion.NameIDFormat == saml.NameIDFormatEmailAddress {
+		email = assertion.NameID
+	}
+	return email
+}
+
+// Delete the pending AuthnRequest, or return an error if it doesn't exist or is expired
+func (samlService *samlServiceImpl) consumeAuthRequest(requestID string) httperrors.HTTPError {
+	authRequests, herr := samlService.samlAuthRequestRepository.Find(squirrel.Eq{"request_id": requestID}, nil, nil)
+	if herr != nil {
+		return herr
+	}
+	if !authRequests.HasContent {
+		return HERRInvalidState
+	}
+	authRequest := authRequests.Ressources[0]
+	herr = samlService.samlAuthRequestRepository.Delete(authRequest)
+	if herr != nil {
+		return herr
+	}
+	if authRequest.IsExpired() {
+		return HERRInvalidState
+	}
+	return nil
+}
+
+// Record the assertion until it expires, or return an error if it was already used
+//
+// The unique id of the recorded assertions makes the check hold across the nodes.
+func (samlService *samlServiceImpl) consumeAssertion(assertion *saml.Assertion) httperrors.HTTPError {
+	_, herr := samlService.samlAssertionRepository.DeleteUnscoped(squirrel.Lt{"expires_at": time.Now()})
+	if herr != nil {
+		return herr
+	}
+	herr = samlService.samlAssertionRepository.Create(&models.SAMLAssertion{
+		AssertionID: assertion.ID,
+		ExpiresAt:   assertion.ExpiresAt,
+	})
+	if herr != nil {
+		if impl, ok := herr.(*httperrors.HTTPErrorImpl); ok && impl.Status == http.StatusConflict {
+			samlService.logger.Warn("Rejected a replayed SAML assertion", zap.String("assertionID", assertion.ID))
+			return HERRInvalidAssertion
+		}
+		return herr
+	}
+	return nil
+}
+
+// Return the service provider described by the configuration, its keys and certificates are loaded on the first call
+func (samlService *samlServiceImpl) getServiceProvider() (*saml.ServiceProvider, httperrors.HTTPError) {
+	if !samlService.samlConfiguration.IsEnabled() {
+		return nil, HERRSAMLDisabled
+	}
+	samlService.mutex.Lock()
+	defer samlService.mutex.Unlock()
+	if samlService.serviceProvider != nil {
+		return samlService.serviceProvider, nil
+	}
+	key, err := loadPrivateKey(samlService.samlConfiguration.GetPrivateKey())
+	if err != nil {
+		return nil, httperrors.NewInternalServerError("saml error", "failed to load the SAML private key", err)
+	}
+	certificates, err := loadCertificates(samlService.samlConfiguration.GetCertificate())
+	if err != nil {
+		return nil, httperrors.NewInternalServerError("saml error", "failed to load the SAML certificate", err)
+	}
+	idpCertificates, err := loadCertificates(samlService.samlConfiguration.GetIdPCertificate())
+	if err != nil {
+		return nil, httperrors.NewInternalServerError("saml error", "failed to load the certificate of the identity provider", err)
+	}
+	samlService.serviceProvider = &saml.ServiceProvider{
+		EntityID:                    samlService.samlConfiguration.GetEntityID(),
+		AssertionConsumerServiceURL: samlService.samlConfiguration.GetAssertionConsumerServiceURL(),
+		Key:                         key,
+		Certificate:                 certificates[0],
+		IdentityProvider: saml.IdentityProvider{
+			EntityID:     samlService.samlConfiguration.GetIdPEntityID(),
+			SSOURL:       samlService.samlConfiguration.GetIdPSSOURL(),
+			Certificates: idpCertificates,
+		},
+		MaxClockSkew: samlService.samlConfiguration.GetMaxClockSkew(),
+	}
+	return samlService.serviceProvider, nil
+}
+
+// Load the PEM encoded certificates of the file
+func loadCertificates(path string) ([]*x509.Certificate, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var certificates []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, content = pem.Decode(content)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certificates = append(certificates, certificate)
+	}
+	if len(certificates) == 0 {
+		return nil, fmt.Errorf("no certificate found in %q", path)
+	}
+	return certificates, nil
+}
+
+// Load the PEM encoded RSA key of the file, in the PKCS #1 or PKCS #8 format
+func loadPrivateKey(path string) (*rsa.PrivateKey, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return nil, fmt.Errorf("no key found in %q", path)
+	}
+	if block.Type == "RSA PRIVATE KEY" {
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("the key of %q is not an RSA key", path)
+	}
+	return rsaKey, nil
+}
+
+// Return a random password nobody knows
+func randomPassword() (string, error) {
+	b := make([]byte, 32)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,297 @@
+package samlservice_test
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/ditrit/badaas/httperrors"
+	mocksConfiguration "github.com/ditrit/badaas/mocks/configuration"
+	mocksRepository "github.com/ditrit/badaas/mocks/persistence/repository"
+	mocksUserService "github.com/ditrit/badaas/mocks/services/userservice"
+	"github.com/ditrit/badaas/persistence/models"
+	"github.com/ditrit/badaas/persistence/pagination"
+	"github.com/ditrit/badaas/services/auth/protocols/saml/samltest"
+	"github.com/ditrit/badaas/services/samlservice"
+	"github.com/ditrit/badaas/services/userservice"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const (
+	entityID = "https://badaas.example.com/saml/metadata"
+	acsURL   = "https://badaas.example.com/login/saml/acs"
+)
+
+var notFound = httperrors.NewErrorNotFound("user", "no user found")
+
+type testSetup struct {
+	identityProvider      *samltest.IdentityProvider
+	spCertificate         *x509.Certificate
+	identityRepository    *mocksRepository.CRUDRepository[models.SAMLIdentity, uuid.UUID]
+	authRequestRepository *mocksRepository.CRUDRepository[models.SAMLAuthRequest, uuid.UUID]
+	assertionRepository   *mocksRepository.CRUDRepository[models.SAMLAssertion, uuid.UUID]
+	userService           *mocksUserService.UserService
+	samlConfiguration     *mocksConfiguration.SAMLConfiguration
+	service               samlservice.SAMLService
+	// the last AuthnRequest created by the service
+	authRequest *models.SAMLAuthRequest
+}
+
+// Write the PEM block in a file of the directory and return its path
+func writePEM(t *testing.T, directory, name, blockType string, content []byte) string {
+	path := filepath.Join(directory, name)
+	err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: content}), 0o600)
+	require.NoError(t, err)
+	return path
+}
+
+func setupTest(t *testing.T, provisioning bool) *testSetup {
+	identityProvider, err := samltest.NewIdentityProvider("https://idp.example.com")
+	require.NoError(t, err)
+	key, certificate, err := samltest.NewCertificate("badaas")
+	require.NoError(t, err)
+	directory := t.TempDir()
+
+	samlConfiguration := mocksConfiguration.NewSAMLConfiguration(t)
+	samlConfiguration.On("IsEnabled").Return(true).Maybe()
+	samlConfiguration.On("GetEntityID").Return(entityID).Maybe()
+	samlConfiguration.On("GetAssertionConsumerServiceURL").Return(acsURL).Maybe()
+	samlConfiguration.On("GetPrivateKey").
+		Return(writePEM(t, directory, "sp.key", "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(key))).Maybe()
+	samlConfiguration.On("GetCertificate").
+		Return(writePEM(t, directory, "sp.crt", "CERTIFICATE", certificate.Raw)).Maybe()
+	samlConfiguration.On("GetIdPEntityID").Return(identityProvider.EntityID).Maybe()
+	samlConfiguration.On("GetIdPSSOURL").Return(identityProvider.SSOURL).Maybe()
+	samlConfiguration.On("GetIdPCertificate").
+		Return(writePEM(t, directory, "idp.crt", "CERTIFICATE", identityProvider.Certificate.Raw)).Maybe()
+	samlConfiguration.On("GetEmailAttribute").Return("mail").Maybe()
+	samlConfiguration.On("GetUsernameAttribute").Return("uid").Maybe()
+	samlConfiguration.On("GetProvisioning").Return(provisioning).Maybe()
+	samlConfiguration.On("GetRequestDuration").Return(10 * time.Minute).Maybe()
+	samlConfiguration.On("GetMaxClockSkew").Return(time.Minute).Maybe()
+
+	setup := &testSetup{
+		identityProvider:      identityProvider,
+		spCertificate:         certificate,
+		identityRepository:    mocksRepository.NewCRUDRepository[models.SAMLIdentity, uuid.UUID](t),
+		authRequestRepository: mocksRepository.NewCRUDRepository[models.SAMLAuthRequest, uuid.UUID](t),
+		assertionRepository:   mocksRepository.NewCRUDRepository[models.SAMLAssertion, uuid.UUID](t),
+		userService:           mocksUserService.NewUserService(t),
+		samlConfiguration:     samlConfiguration,
+	}
+	setup.authRequestRepository.On("Create", mock.AnythingOfType("*models.SAMLAuthRequest")).
+		Run(func(args mock.Arguments) {
+			setup.authRequest = args.Get(0).(*models.SAMLAuthRequest)
+		}).Return(nil).Maybe()
+	setup.service = samlservice.NewSAMLService(zap.NewNop(), samlConfiguration,
+		setup.identityRepository, setup.authRequestRepository, setup.assertionRepository, setup.userService)
+	return setup
+}
+
+// Start a login and return the response of the identity provider for the user, its assertion is used for the first time
+func (setup *testSetup) login(t *testing.T, nameID string, attributes map[string][]string) string {
+	encodedResponse := setup.respond(t, nameID, attributes)
+	setup.assertionRepository.On("DeleteUnscoped", mock.AnythingOfType("squirrel.Lt")).Return(uint(0), nil).Maybe()
+	setup.assertionRepository.On("Create", mock.MatchedBy(func(assertion *models.SAMLAssertion) bool {
+		return assertion.AssertionID == "assertion-"+setup.authRequest.RequestID
+	})).Return(nil).Maybe()
+	return encodedResponse
+}
+
+// Start a login and return the response of the identity provider for the user
+func (setup *testSetup) respond(t *testing.T, nameID string, attributes map[string][]string) string {
+	redirectURL, requestID, herr := setup.service.BeginLogin()
+	require.Nil(t, herr)
+	request, err := setup.identityProvider.ParseAuthnRequest(redirectURL, setup.spCertificate)
+	require.NoError(t, err)
+	assert.Equal(t, setup.authRequest.RequestID, request.ID)
+	assert.Equal(t, requestID, request.ID)
+	response := setup.identityProvider.NewResponse(request, nameID)
+	response.Attributes = attributes
+	encodedResponse, err := setup.identityProvider.Sign(response)
+	require.NoError(t, err)
+	setup.authRequestRepository.On("Find", squirrel.Eq{"request_id": request.ID}, nil, nil).
+		Return(pagination.NewPage([]*models.SAMLAuthRequest{setup.authRequest}, 1, 10, 1), nil)
+	setup.authRequestRepository.On("Delete", setup.authRequest).Return(nil)
+	return encodedResponse
+}
+
+func (setup *testSetup) onFindIdentity(nameID string, identities ...*models.SAMLIdentity) {
+	setup.identityRepository.On("Find", squirrel.Eq{"name_id": nameID}, nil, nil).
+		Return(pagination.NewPage(identities, 1, 10, uint(len(identities))), nil)
+}
+
+func TestGetMetadata(t *testing.T) {
+	setup := setupTest(t, true)
+
+	metadata, herr := setup.service.GetMetadata()
+	require.Nil(t, herr)
+	assert.Contains(t, string(metadata), entityID)
+}
+
+func TestGetMetadataDisabled(t *testing.T) {
+	samlConfiguration := mocksConfiguration.NewSAMLConfiguration(t)
+	samlConfiguration.On("IsEnabled").Return(false)
+	service := samlservice.NewSAMLService(zap.NewNop(), samlConfiguration,
+		mocksRepository.NewCRUDRepository[models.SAMLIdentity, uuid.UUID](t),
+		mocksRepository.NewCRUDRepository[models.SAMLAuthRequest, uuid.UUID](t),
+		mocksRepository.NewCRUDRepository[models.SAMLAssertion, uuid.UUID](t),
+		mocksUserService.NewUserService(t))
+
+	_, herr := service.GetMetadata()
+	assert.Equal(t, samlservice.HERRSAMLDisabled, herr)
+}
+
+func TestFinishLoginKnownIdentity(t *testing.T) {
+	setup := setupTest(t, false)
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Username: "bob", Email: "bob@email.com"}
+	encodedResponse := setup.login(t, "bob-id", map[string][]string{"mail": {"bob@email.com"}, "uid": {"bob"}})
+	setup.onFindIdentity("bob-id", &models.SAMLIdentity{UserID: user.ID, NameID: "bob-id", Email: "bob@email.com"})
+	setup.userService.On("GetUserByID", user.ID).Return(user, nil)
+	setup.userService.On("CheckCanLogIn", user).Return(nil)
+
+	loggedUser, herr := setup.service.FinishLogin(encodedResponse, setup.authRequest.RequestID)
+	require.Nil(t, herr)
+	assert.Equal(t, user, loggedUser)
+}
+
+func TestFinishLoginUpdatesUsername(t *testing.T) {
+	setup := setupTest(t, false)
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Username: "bob", Email: "bob@email.com"}
+	renamedUser := &models.User{BaseModel: user.BaseModel, Username: "robert", Email: "bob@email.com"}
+	encodedResponse := setup.login(t, "bob-id", map[string][]string{"mail": {"bob@email.com"}, "uid": {"robert"}})
+	setup.onFindIdentity("bob-id", &models.SAMLIdentity{UserID: user.ID, NameID: "bob-id", Email: "bob@email.com"})
+	setup.userService.On("GetUserByID", user.ID).Return(user, nil)
+	setup.userService.On("UpdateUser", user.ID, "robert", "").Return(renamedUser, nil)
+	setup.userService.On("CheckCanLogIn", renamedUser).Return(nil)
+
+	loggedUser, herr := setup.service.FinishLogin(encodedResponse, setup.authRequest.RequestID)
+	require.Nil(t, herr)
+	assert.Equal(t, "robert", loggedUser.Username)
+}
+
+func TestFinishLoginLinkByEmail(t *testing.T) {
+	setup := setupTest(t, false)
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Username: "bob", Email: "bob@email.com"}
+	encodedResponse := setup.login(t, "bob-id", map[string][]string{"mail": {"bob@email.com"}})
+	setup.onFindIdentity("bob-id")
+	setup.samlConfiguration.On("GetLinkByEmail").Return(true)
+	setup.userService.On("GetUserByEmail", "bob@email.com").Return(user, nil)
+	setup.identityRepository.On("Create", &models.SAMLIdentity{UserID: user.ID, NameID: "bob-id", Email: "bob@email.com"}).Return(nil)
+	setup.userService.On("CheckCanLogIn", user).Return(nil)
+
+	loggedUser, herr := setup.service.FinishLogin(encodedResponse, setup.authRequest.RequestID)
+	require.Nil(t, herr)
+	assert.Equal(t, user, loggedUser)
+}
+
+func TestFinishLoginDoesNotLinkByEmail(t *testing.T) {
+	setup := setupTest(t, true)
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Username: "bob", Email: "bob@email.com"}
+	encodedResponse := setup.login(t, "bob-id", map[string][]string{"mail": {"bob@email.com"}})
+	setup.onFindIdentity("bob-id")
+	setup.samlConfiguration.On("GetLinkByEmail").Return(false)
+	setup.userService.On("GetUserByEmail", "bob@email.com").Return(user, nil)
+
+	_, herr := setup.service.FinishLogin(encodedResponse, setup.authRequest.RequestID)
+	assert.Equal(t, samlservice.HERRAccountNotLinked, herr)
+}
+
+func TestFinishLoginProvisioning(t *testing.T) {
+	setup := setupTest(t, true)
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Username: "bob", Email: "bob@email.com"}
+	encodedResponse := setup.login(t, "bob-id", map[string][]string{"mail": {"bob@email.com"}, "uid": {"bob"}})
+	setup.onFindIdentity("bob-id")
+	setup.userService.On("GetUserByEmail", "bob@email.com").Return(nil, notFound)
+	setup.userService.On("NewSystemUser", "bob", "bob@email.com", mock.AnythingOfType("string")).Return(user, nil)
+	setup.userService.On("MarkEmailVerified", user.ID).Return(nil)
+	setup.identityRepository.On("Create", mock.AnythingOfType("*models.SAMLIdentity")).Return(nil)
+	setup.userService.On("CheckCanLogIn", user).Return(nil)
+
+	loggedUser, herr := setup.service.FinishLogin(encodedResponse, setup.authRequest.RequestID)
+	require.Nil(t, herr)
+	assert.True(t, loggedUser.EmailVerified)
+}
+
+func TestFinishLoginWithoutProvisioning(t *testing.T) {
+	setup := setupTest(t, false)
+	encodedResponse := setup.login(t, "bob-id", map[string][]string{"mail": {"bob@email.com"}})
+	setup.onFindIdentity("bob-id")
+	setup.userService.On("GetUserByEmail", "bob@email.com").Return(nil, notFound)
+
+	_, herr := setup.service.FinishLogin(encodedResponse, setup.authRequest.RequestID)
+	assert.Equal(t, samlservice.HERRAccountNotLinked, herr)
+}
+
+func TestFinishLoginUserDisabled(t *testing.T) {
+	setup := setupTest(t, false)
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Disabled: true}
+	encodedResponse := setup.login(t, "bob-id", nil)
+	setup.onFindIdentity("bob-id", &models.SAMLIdentity{UserID: user.ID, NameID: "bob-id"})
+	setup.userService.On("GetUserByID", user.ID).Return(user, nil)
+	setup.userService.On("CheckCanLogIn", user).Return(userservice.HERRUserDisabled)
+
+	_, herr := setup.service.FinishLogin(encodedResponse, setup.authRequest.RequestID)
+	assert.Equal(t, userservice.HERRUserDisabled, herr)
+}
+
+func TestFinishLoginReplayed(t *testing.T) {
+	setup := setupTest(t, true)
+	encodedResponse := setup.login(t, "bob-id", nil)
+	setup.authRequest.ExpiresAt = time.Now().Add(-time.Second)
+
+	_, herr := setup.service.FinishLogin(encodedResponse, setup.authRequest.RequestID)
+	assert.Equal(t, samlservice.HERRInvalidState, herr)
+}
+
+func TestFinishLoginOtherBrowser(t *testing.T) {
+	setup := setupTest(t, true)
+	redirectURL, _, herr := setup.service.BeginLogin()
+	require.Nil(t, herr)
+	request, err := setup.identityProvider.ParseAuthnRequest(redirectURL, setup.spCertificate)
+	require.NoError(t, err)
+	encodedResponse, err := setup.identityProvider.Sign(setup.identityProvider.NewResponse(request, "bob-id"))
+	require.NoError(t, err)
+	// the response is posted from a browser which started another login
+	_, otherRequestID, herr := setup.service.BeginLogin()
+	require.Nil(t, herr)
+
+	_, herr = setup.service.FinishLogin(encodedResponse, otherRequestID)
+	assert.Equal(t, samlservice.HERRInvalidState, herr)
+}
+
+func TestFinishLoginReplayedAssertion(t *testing.T) {
+	setup := setupTest(t, true)
+	encodedResponse := setup.respond(t, "bob-id", nil)
+	setup.assertionRepository.On("DeleteUnscoped", mock.AnythingOfType("squirrel.Lt")).Return(uint(0), nil)
+	setup.assertionRepository.On("Create", mock.AnythingOfType("*models.SAMLAssertion")).
+		Return(httperrors.NewHTTPError(http.StatusConflict, "*models.SAMLAssertion already exist in database", "", nil, false))
+
+	_, herr := setup.service.FinishLogin(encodedResponse, setup.authRequest.RequestID)
+	assert.Equal(t, samlservice.HERRInvalidAssertion, herr)
+}
+
+func TestFinishLoginForgedResponse(t *testing.T) {
+	setup := setupTest(t, true)
+	redirectURL, requestID, herr := setup.service.BeginLogin()
+	require.Nil(t, herr)
+	request, err := setup.identityProvider.ParseAuthnRequest(redirectURL, setup.spCertificate)
+	require.NoError(t, err)
+	// signed by an identity provider with another key
+	forger, err := samltest.NewIdentityProvider(setup.identityProvider.EntityID)
+	require.NoError(t, err)
+	encodedResponse, err := forger.Sign(forger.NewResponse(request, "admin-id"))
+	require.NoError(t, err)
+
+	_, herr = setup.service.FinishLogin(encodedResponse, requestID)
+	assert.Equal(t, samlservice.HERRInvalidAssertion, herr)
+}
@@ -0,0 +1,106 @@
+package sessionservice
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/ditrit/badaas/configuration"
+)
+
+// The size of the networks the sessions are bound to, large enough to tolerate the address changes of a client
+const (
+	ipv4NetworkSize = 16
+	ipv6NetworkSize = 48
+)
+
+// The browsers recognised in the user agents, the first match wins
+var browsers = []struct{ token, name string }{
+	{"Edg/", "Edge"},
+	{"OPR/", "Opera"},
+	{"Firefox/", "Firefox"},
+	{"FxiOS/", "Firefox"},
+	{"CriOS/", "Chrome"},
+	{"Chrome/", "Chrome"},
+	{"Safari/", "Safari"},
+	{"curl/", "curl"},
+}
+
+// The operating systems recognised in the user agents, the first match wins
+var operatingSystems = []struct{ token, name string }{
+	{"Windows", "Windows"},
+	{"Android", "Android"},
+	{"iPhone", "iOS"},
+	{"iPad", "iOS"},
+	{"Mac OS X", "macOS"},
+	{"CrOS", "ChromeOS"},
+	{"Linux", "Linux"},
+}
+
+// Return a label of the device of a user agent, ex: "Firefox on Linux"
+func parseDevice(userAgent string) string {
+	browser := ""
+	for _, candidate := range browsers {
+		if strings.Contains(userAgent, candidate.token) {
+			browser = candidate.name
+			break
+		}
+	}
+	operatingSystem := ""
+	for _, candidate := range operatingSystems {
+		if strings.Contains(userAgent, candidate.token) {
+			operatingSystem = candidate.name
+			break
+		}
+	}
+	switch {
+	case browser != "" && operatingSystem != "":
+		return browser + " on " + operatingSystem
+	case browser != "":
+		return browser
+	case operatingSystem != "":
+		return operatingSystem
+	default:
+		return "Unknown device"
+	}
+}
+
+// Return the ip address of the client of the request
+//
+// The address of the requests forwarded by a trusted proxy is set to the address of their client by the router.
+func GetClientIP(request *http.Request) string {
+	ip, _, err := net.SplitHostPort(request.RemoteAddr)
+	if err != nil {
+		return request.RemoteAddr
+	}
+	return ip
+}
+
+// Return the network of an ip address, or the address itself if it can't be parsed
+func getNetwork(ipAddress string) string {
+	ip := net.ParseIP(ipAddress)
+	if ip == nil {
+		return ipAddress
+	}
+	if ipv4 := ip.To4(); ipv4 != nil {
+		return ipv4.Mask(net.CIDRMask(ipv4NetworkSize, 8*net.IPv4len)).String()
+	}
+	return ip.Mask(net.CIDRMask(ipv6NetworkSize, 8*net.IPv6len)).String()
+}
+
+// Return the hash of the client of the request for the binding, empty if the sessions are not bound
+func getFingerprint(binding string, request *http.Request) string {
+	var client string
+	switch binding {
+	case configuration.SessionBindingDevice:
+		client = parseDevice(request.UserAgent())
+	case configuration.SessionBindingDeviceAndNetwork:
+		client = parseDevice(request.UserAgent()) + "|" + getNetwork(GetClientIP(request))
+	default:
+		return ""
+	}
+	hash := sha256.Sum256([]byte(binding + "|" + client))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,30 @@
+package sessionservice
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const firefoxOnLinux = "Mozilla/5.0 (X11; Linux x86_64; rv:120.0) Gecko/20100101 Firefox/120.0"
+
+func TestParseDevice(t *testing.T) {
+	tests := map[string]string{
+		firefoxOnLinux: "Firefox on Linux",
+		"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.0.0":           "Edge on Windows",
+		"Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36":                   "Chrome on Android",
+		"Mozilla/5.0 (iPhone; CPU iPhone OS 17_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Mobile/15E148 Safari/604.1": "Safari on iOS",
+		"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Safari/605.1.15":                   "Safari on macOS",
+		"curl/8.0.1": "curl",
+		"":           "Unknown device",
+	}
+	for userAgent, device := range tests {
+		assert.Equal(t, device, parseDevice(userAgent), userAgent)
+	}
+}
+
+func TestGetNetwork(t *testing.T) {
+	assert.Equal(t, "198.51.0.0", getNetwork("198.51.100.7"))
+	assert.Equal(t, "2001:db8:1234::", getNetwork("2001:db8:1234:5678::1"))
+	assert.Equal(t, "not an ip", getNetwork("not an ip"))
+}
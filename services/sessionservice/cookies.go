@@ -0,0 +1,125 @@
+package sessionservice
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ditrit/badaas/configuration"
+)
+
+// The number of random bytes of the session, refresh and csrf tokens
+const tokenSize = 32
+
+// The SameSite attributes by configuration value
+var sameSiteModes = map[string]http.SameSite{
+	configuration.CookieSameSiteStrict: http.SameSiteStrictMode,
+	configuration.CookieSameSiteLax:    http.SameSiteLaxMode,
+	configuration.CookieSameSiteNone:   http.SameSiteNoneMode,
+}
+
+// Return an error if the browsers would refuse the cookies of the configuration
+func checkCookieConfiguration(cookieConfiguration configuration.CookieConfiguration) error {
+	sameSite, ok := sameSiteModes[cookieConfiguration.GetSameSite()]
+	if !ok {
+		return fmt.Errorf("unknown cookie SameSite attribute %q", cookieConfiguration.GetSameSite())
+	}
+	if sameSite == http.SameSiteNoneMode && !cookieConfiguration.GetSecure() {
+		return errors.New("the cookies with SameSite=None must be secure")
+	}
+	if cookieConfiguration.GetHostPrefix() &&
+		(!cookieConfiguration.GetSecure() || cookieConfiguration.GetDomain() != "" || cookieConfiguration.GetPath() != "/") {
+		return fmt.Errorf("the %s cookies must be secure, on the path / and without domain", configuration.CookieHostPrefix)
+	}
+	return nil
+}
+
+// Create a cookie with the configured attributes
+func (sessionService *sessionServiceImpl) newCookie(name, path, value string, expiresAt time.Time) *http.Cookie {
+	return &http.Cookie{
+		Name:     name,
+		Domain:   sessionService.cookieConfiguration.GetDomain(),
+		Path:     path,
+		Value:    value,
+		HttpOnly: true,
+		Secure:   sessionService.cookieConfiguration.GetSecure(),
+		SameSite: sameSiteModes[sessionService.cookieConfiguration.GetSameSite()],
+		Expires:  expiresAt,
+	}
+}
+
+// Send the access token in the session cookie, until it expires
+func (sessionService *sessionServiceImpl) setAccessTokenCookie(response http.ResponseWriter, accessToken string, expiresAt time.Time) {
+	http.SetCookie(response, sessionService.newCookie(
+		sessionService.cookieConfiguration.GetName(), sessionService.cookieConfiguration.GetPath(),
+		accessToken, expiresAt,
+	))
+}
+
+// Send a new csrf token in a cookie readable by the scripts, if the csrf protection is enabled
+//
+// The scripts send it back in the csrf header (double submit cookie).
+func (sessionService *sessionServiceImpl) setCSRFCookie(response http.ResponseWriter, expiresAt time.Time) error {
+	if !sessionService.cookieConfiguration.GetCSRFEnabled() {
+		return nil
+	}
+	csrfToken, err := generateToken()
+	if err != nil {
+		return err
+	}
+	cookie := sessionService.newCookie(
+		sessionService.cookieConfiguration.GetCSRFCookieName(), sessionService.cookieConfiguration.GetPath(),
+		csrfToken, expiresAt,
+	)
+	cookie.HttpOnly = false
+	http.SetCookie(response, cookie)
+	return nil
+}
+
+// Send the session cookie and a new csrf token
+func (sessionService *sessionServiceImpl) setSessionCookies(
+	response http.ResponseWriter,
+	accessToken string,
+	accessTokenExpiresAt, sessionExpiresAt time.Time,
+) error {
+	sessionService.setAccessTokenCookie(response, accessToken, accessTokenExpiresAt)
+	return sessionService.setCSRFCookie(response, sessionExpiresAt)
+}
+
+// Ask the browser to delete the session and csrf cookies
+func (sessionService *sessionServiceImpl) expireSessionCookies(response http.ResponseWriter) {
+	for _, name := range []string{
+		sessionService.cookieConfiguration.GetName(),
+		sessionService.cookieConfiguration.GetCSRFCookieName(),
+	} {
+		cookie := sessionService.newCookie(name, sessionService.cookieConfiguration.GetPath(), "", time.Unix(0, 0))
+		cookie.MaxAge = -1
+		http.SetCookie(response, cookie)
+	}
+}
+
+// Return the token of the session cookie of the request, false if it's not the token of this hash
+func (sessionService *sessionServiceImpl) getCookieToken(request *http.Request, tokenHash string) (string, bool) {
+	cookie, err := request.Cookie(sessionService.cookieConfiguration.GetName())
+	if err != nil {
+		return "", false
+	}
+	cookieTokenHash, ok := sessionService.tokenSigner.hash(cookie.Value)
+	if !ok || cookieTokenHash != tokenHash {
+		return "", false
+	}
+	return cookie.Value, true
+}
+
+// Generate a random token
+func generateToken() (string, error) {
+	b := make([]byte, tokenSize)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -11,24 +11,54 @@ import (
 	"github.com/ditrit/badaas/httperrors"
 	"github.com/ditrit/badaas/persistence/models"
 	"github.com/ditrit/badaas/persistence/repository"
+	"github.com/go-jose/go-jose/v3"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
+// The last activity of a session is only recorded once during this interval, not on every request
+const lastSeenUpdateInterval = time.Minute
+
+// The pulls of the changed sessions overlap by this margin, for the clock differences between the nodes
+const revocationPullMargin = time.Minute
+
 // Errors
 var (
 	HERRSessionExpired = httperrors.NewUnauthorizedError(
 		"session error",
 		"session is expired",
 	)
+	HERRNotAuthenticated = httperrors.NewUnauthorizedError("Authentification Error", "not authenticated")
+	HERRSessionNotFound  = httperrors.NewErrorNotFound("session", "no session found with this id")
 )
 
 // SessionService handle sessions
 type SessionService interface {
-	IsValid(sessionUUID uuid.UUID) (bool, *SessionClaims)
 	RollSession(uuid.UUID) httperrors.HTTPError
-	LogUserIn(user *models.User, response http.ResponseWriter) httperrors.HTTPError
+	LogUserIn(user *models.User, request *http.Request, response http.ResponseWriter) httperrors.HTTPError
 	LogUserOut(sessionClaims *SessionClaims, response http.ResponseWriter) httperrors.HTTPError
+	// Return the active sessions of a user
+	GetUserSessions(userID uuid.UUID) ([]*models.Session, httperrors.HTTPError)
+	// Delete a session of a user
+	RevokeSession(userID, sessionUUID uuid.UUID) httperrors.HTTPError
+	// Delete all the sessions of a user
+	RevokeUserSessions(userID uuid.UUID) httperrors.HTTPError
+	// Delete all the sessions of a user except the one given
+	RevokeOtherUserSessions(userID, keptSessionUUID uuid.UUID) httperrors.HTTPError
+	// Return the claims of a request authenticated with the access token
+	Authenticate(accessToken string) (*SessionClaims, httperrors.HTTPError)
+	// Check that an authenticated request comes from the client of its session and record its activity
+	//
+	// The session cookie of a rolled session is extended with it.
+	Track(sessionClaims *SessionClaims, request *http.Request, response http.ResponseWriter) httperrors.HTTPError
+	// Replace the token of the session of the request, after a change of the privileges of its user
+	//
+	// The requests authenticated with an API key have no session to rotate.
+	RotateSession(sessionClaims *SessionClaims, response http.ResponseWriter) httperrors.HTTPError
+	// Exchange a refresh token for a new access token, only in the jwt mode
+	Refresh(refreshToken string, response http.ResponseWriter) httperrors.HTTPError
+	// Return the public keys verifying the access tokens, empty in the database mode
+	GetJWKS() jose.JSONWebKeySet
 }
 
 // Check interface compliance
@@ -38,55 +68,82 @@ var _ SessionService = (*sessionServiceImpl)(nil)
 type sessionServiceImpl struct {
 	sessionRepository    repository.CRUDRepository[models.Session, uuid.UUID]
 	cache                map[uuid.UUID]*models.Session
-	mutex                sync.Mutex
+	tokens               map[string]uuid.UUID // the ids of the cached sessions by token hash
+	tokenSigner          tokenSigner
+	mutex                sync.Mutex // protects the cache, never held during the database queries
 	logger               *zap.Logger
 	sessionConfiguration configuration.SessionConfiguration
+	cookieConfiguration  configuration.CookieConfiguration
 }
 
 // The SessionService constructor
+//
+// The implementation depends on the session mode.
 func NewSessionService(
 	logger *zap.Logger,
 	sessionRepository repository.CRUDRepository[models.Session, uuid.UUID],
 	sessionConfiguration configuration.SessionConfiguration,
-) SessionService {
+	cookieConfiguration configuration.CookieConfiguration,
+) (SessionService, error) {
+	err := checkCookieConfiguration(cookieConfiguration)
+	if err != nil {
+		return nil, err
+	}
+	tokenSigner, err := newTokenSigner(sessionConfiguration.GetSigningKeys())
+	if err != nil {
+		return nil, err
+	}
 	sessionService := &sessionServiceImpl{
 		cache:                make(map[uuid.UUID]*models.Session),
+		tokens:               make(map[string]uuid.UUID),
+		tokenSigner:          tokenSigner,
 		logger:               logger,
 		sessionRepository:    sessionRepository,
 		sessionConfiguration: sessionConfiguration,
+		cookieConfiguration:  cookieConfiguration,
 	}
-	sessionService.init()
-	return sessionService
-}
-
-// Create a new session
-func newSession(userID uuid.UUID, sessionDuration time.Duration) *models.Session {
-	return &models.Session{
-		UserID:    userID,
-		ExpiresAt: time.Now().Add(sessionDuration),
+	switch sessionConfiguration.GetBinding() {
+	case configuration.SessionBindingNone, configuration.SessionBindingDevice, configuration.SessionBindingDeviceAndNetwork:
+	default:
+		return nil, fmt.Errorf("unknown session binding %q", sessionConfiguration.GetBinding())
+	}
+	switch sessionConfiguration.GetMode() {
+	case configuration.SessionModeDatabase:
+		sessionService.init()
+		return sessionService, nil
+	case configuration.SessionModeJWT:
+		return newJWTSessionService(sessionService)
+	default:
+		return nil, fmt.Errorf("unknown session mode %q", sessionConfiguration.GetMode())
 	}
 }
 
-// Return true if the session exists and is still valid.
-// A instance of SessionClaims is returned to be added to the request context if the conditions previously mentioned are met.
-func (sessionService *sessionServiceImpl) IsValid(sessionUUID uuid.UUID) (bool, *SessionClaims) {
-	sessionInstance := sessionService.get(sessionUUID)
-	if sessionInstance == nil {
-		return false, nil
+// Create a new session for the client of the request
+func newSession(userID uuid.UUID, sessionDuration time.Duration, binding string, request *http.Request) *models.Session {
+	now := time.Now()
+	return &models.Session{
+		UserID:      userID,
+		ExpiresAt:   now.Add(sessionDuration),
+		IPAddress:   GetClientIP(request),
+		UserAgent:   request.UserAgent(),
+		Device:      parseDevice(request.UserAgent()),
+		Fingerprint: getFingerprint(binding, request),
+		LastSeenAt:  now,
 	}
-	return true, makeSessionClaims(sessionInstance)
 }
 
 // Get a session from cache
 // return nil if not found
+//
+// The sessions revoked by another node are removed from the cache by the pulls of the revocations.
 func (sessionService *sessionServiceImpl) get(sessionUUID uuid.UUID) *models.Session {
 	sessionService.mutex.Lock()
-	defer sessionService.mutex.Unlock()
 	session, ok := sessionService.cache[sessionUUID]
+	sessionService.mutex.Unlock()
 	if ok {
 		return session
 	}
-	sessionsFoundWithUUID, databaseError := sessionService.sessionRepository.Find(squirrel.Eq{"uuid": sessionUUID.String()}, nil, nil)
+	sessionsFoundWithUUID, databaseError := sessionService.sessionRepository.Find(squirrel.Eq{"id": sessionUUID.String()}, nil, nil)
 	if databaseError != nil {
 		return nil
 	}
@@ -98,20 +155,97 @@ func (sessionService *sessionServiceImpl) get(sessionUUID uuid.UUID) *models.Ses
 
 // Add a session to the cache
 func (sessionService *sessionServiceImpl) add(session *models.Session) httperrors.HTTPError {
-	sessionService.mutex.Lock()
-	defer sessionService.mutex.Unlock()
 	herr := sessionService.sessionRepository.Create(session)
 	if herr != nil {
 		return herr
 	}
-	sessionService.cache[session.ID] = session
+	sessionService.mutex.Lock()
+	sessionService.cacheSession(session)
+	sessionService.mutex.Unlock()
 	sessionService.logger.Debug("Added session", zap.String("uuid", session.ID.String()))
 	return nil
 }
 
+// Get a session by the hash of its token
+// return nil if not found
+//
+// The tokens rotated or revoked by another node are removed from the cache by the pulls of the revocations.
+func (sessionService *sessionServiceImpl) getByTokenHash(tokenHash string) *models.Session {
+	sessionService.mutex.Lock()
+	sessionUUID, ok := sessionService.tokens[tokenHash]
+	session := sessionService.cache[sessionUUID]
+	sessionService.mutex.Unlock()
+	if ok {
+		return session
+	}
+	sessionsFoundWithToken, databaseError := sessionService.sessionRepository.Find(squirrel.Eq{"token_hash": tokenHash}, nil, nil)
+	if databaseError != nil {
+		return nil
+	}
+	if !sessionsFoundWithToken.HasContent {
+		return nil // no sessions found in database
+	}
+	return sessionsFoundWithToken.Ressources[0]
+}
+
+// Put a session in the cache, the mutex has to be locked
+func (sessionService *sessionServiceImpl) cacheSession(session *models.Session) {
+	sessionService.cache[session.ID] = session
+	if session.TokenHash != "" {
+		sessionService.tokens[session.TokenHash] = session.ID
+	}
+}
+
+// Replace a cached session by its new version, unless it has been removed from the cache
+//
+// The cached sessions are never modified, since they are read without the mutex once returned.
+func (sessionService *sessionServiceImpl) replace(session *models.Session) {
+	sessionService.mutex.Lock()
+	defer sessionService.mutex.Unlock()
+	if _, ok := sessionService.cache[session.ID]; !ok {
+		return
+	}
+	sessionService.uncache(session.ID)
+	sessionService.cacheSession(session)
+}
+
+// Write the changed columns of a session and cache it, the sessions revoked meanwhile are not written back
+//
+// The update time is written as well so that the other nodes pull the change.
+func (sessionService *sessionServiceImpl) update(session *models.Session, columns map[string]any) httperrors.HTTPError {
+	session.UpdatedAt = time.Now()
+	columns["updated_at"] = session.UpdatedAt
+	updated, herr := sessionService.sessionRepository.UpdateColumns(
+		squirrel.Eq{"id": session.ID.String(), "deleted_at": nil},
+		columns,
+	)
+	if herr != nil {
+		return herr
+	}
+	if updated == 0 {
+		sessionService.mutex.Lock()
+		sessionService.uncache(session.ID)
+		sessionService.mutex.Unlock()
+		return HERRNotAuthenticated
+	}
+	sessionService.replace(session)
+	return nil
+}
+
+// Remove a session from the cache, the mutex has to be locked
+func (sessionService *sessionServiceImpl) uncache(sessionUUID uuid.UUID) {
+	session, ok := sessionService.cache[sessionUUID]
+	if !ok {
+		return
+	}
+	delete(sessionService.tokens, session.TokenHash)
+	delete(sessionService.cache, sessionUUID)
+}
+
 // Initialize the session service
 func (sessionService *sessionServiceImpl) init() error {
 	sessionService.cache = make(map[uuid.UUID]*models.Session)
+	sessionService.tokens = make(map[string]uuid.UUID)
 	go func() {
 		for {
 			sessionService.removeExpired()
@@ -121,57 +255,100 @@ func (sessionService *sessionServiceImpl) init() error {
 			)
 		}
 	}()
+	go func() {
+		since := time.Now()
+		for {
+			time.Sleep(sessionService.sessionConfiguration.GetRevocationPullInterval())
+			since = sessionService.pullRevocations(since)
+		}
+	}()
 	return nil
 }
 
 // Get all sessions and save them in cache
 func (sessionService *sessionServiceImpl) pullFromDB() {
-	sessionService.mutex.Lock()
-	defer sessionService.mutex.Unlock()
 	sessionsFromDatabase, err := sessionService.sessionRepository.GetAll(nil)
 	if err != nil {
 		panic(err)
 	}
-	newSessionCache := make(map[uuid.UUID]*models.Session)
+	sessionService.mutex.Lock()
+	defer sessionService.mutex.Unlock()
+	sessionService.cache = make(map[uuid.UUID]*models.Session)
+	sessionService.tokens = make(map[string]uuid.UUID)
 	for _, sessionFromDatabase := range sessionsFromDatabase {
-		newSessionCache[sessionFromDatabase.ID] = sessionFromDatabase
+		sessionService.cacheSession(sessionFromDatabase)
 	}
-	sessionService.cache = newSessionCache
 	sessionService.logger.Debug(
 		"Pulled sessions from DB",
 		zap.Int("sessionCount", len(sessionsFromDatabase)),
 	)
 }
 
+// Update the cache with the sessions changed or deleted in the database since the given time,
+// return the start of the pull for the next one
+//
+// The sessions revoked or rotated by another node are refused once pulled.
+func (sessionService *sessionServiceImpl) pullRevocations(since time.Time) time.Time {
+	pullStart := time.Now()
+	since = since.Add(-revocationPullMargin)
+	changedSessions, herr := sessionService.sessionRepository.FindUnscoped(squirrel.Or{
+		squirrel.Gt{"updated_at": since},
+		squirrel.Gt{"deleted_at": since},
+	})
+	if herr != nil {
+		sessionService.logger.Error("Failed to pull the revoked sessions", zap.Error(herr))
+		return since.Add(revocationPullMargin)
+	}
+	sessionService.mutex.Lock()
+	defer sessionService.mutex.Unlock()
+	for _, changedSession := range changedSessions {
+		if changedSession.DeletedAt.Valid {
+			sessionService.uncache(changedSession.ID)
+			continue
+		}
+		cachedSession, ok := sessionService.cache[changedSession.ID]
+		if ok && changedSession.UpdatedAt.Before(cachedSession.UpdatedAt) {
+			// changed by this node since the query
+			continue
+		}
+		sessionService.uncache(changedSession.ID)
+		sessionService.cacheSession(changedSession)
+	}
+	return pullStart
+}
+
 // Remove the expired session
 func (sessionService *sessionServiceImpl) removeExpired() {
 	sessionService.mutex.Lock()
-	defer sessionService.mutex.Unlock()
-	var i int
-	for sessionUUID, session := range sessionService.cache {
+	expiredSessions := []*models.Session{}
+	for _, session := range sessionService.cache {
 		if session.IsExpired() {
-			// Delete the session in the database
-			err := sessionService.sessionRepository.Delete(session)
-			if err != nil {
-				panic(err)
-			}
-			// if the deletion of the session in the database was successful,
-			// we now remove the session from the cache.
-			// see https://pkg.go.dev/builtin#delete
-			delete(sessionService.cache, sessionUUID)
-			i++
+			expiredSessions = append(expiredSessions, session)
 		}
 	}
+	sessionService.mutex.Unlock()
+	for _, session := range expiredSessions {
+		// Delete the session in the database
+		err := sessionService.sessionRepository.Delete(session)
+		if err != nil {
+			panic(err)
+		}
+	}
+	// if the deletion of the sessions in the database was successful,
+	// we now remove the sessions from the cache.
+	sessionService.mutex.Lock()
+	for _, session := range expiredSessions {
+		sessionService.uncache(session.ID)
+	}
+	sessionService.mutex.Unlock()
 	sessionService.logger.Debug(
 		"Removed expired session",
-		zap.Int("expiredSessionCount", i),
+		zap.Int("expiredSessionCount", len(expiredSessions)),
 	)
 }
 
 // Delete a session
 func (sessionService *sessionServiceImpl) delete(session *models.Session) httperrors.HTTPError {
-	sessionService.mutex.Lock()
-	defer sessionService.mutex.Unlock()
 	sessionUUID := session.ID
 	err := sessionService.sessionRepository.Delete(session)
 	if err != nil {
@@ -181,7 +358,9 @@ func (sessionService *sessionServiceImpl) delete(session *models.Session) httper
 			err,
 		)
 	}
-	delete(sessionService.cache, sessionUUID)
+	sessionService.mutex.Lock()
+	sessionService.uncache(sessionUUID)
+	sessionService.mutex.Unlock()
 	return nil
 }
 
@@ -198,10 +377,9 @@ func (sessionService *sessionServiceImpl) RollSession(sessionUUID uuid.UUID) htt
 		return HERRSessionExpired
 	}
 	if session.CanBeRolled(rollInterval) {
-		sessionService.mutex.Lock()
-		defer sessionService.mutex.Unlock()
-		session.ExpiresAt = session.ExpiresAt.Add(sessionDuration)
-		herr := sessionService.sessionRepository.Save(session)
+		rolledSession := *session
+		rolledSession.ExpiresAt = session.ExpiresAt.Add(sessionDuration)
+		herr := sessionService.update(&rolledSession, map[string]any{"expires_at": rolledSession.ExpiresAt})
 		if herr != nil {
 			return herr
 		}
@@ -213,14 +391,81 @@ func (sessionService *sessionServiceImpl) RollSession(sessionUUID uuid.UUID) htt
 }
 
 // Log in a user
-func (sessionService *sessionServiceImpl) LogUserIn(user *models.User, response http.ResponseWriter) httperrors.HTTPError {
+func (sessionService *sessionServiceImpl) LogUserIn(
+	user *models.User,
+	request *http.Request,
+	response http.ResponseWriter,
+) httperrors.HTTPError {
+	herr := sessionService.revokeCookieSession(request)
+	if herr != nil {
+		return herr
+	}
 	sessionDuration := sessionService.sessionConfiguration.GetSessionDuration()
-	session := newSession(user.ID, sessionDuration)
-	err := sessionService.add(session)
+	session := newSession(user.ID, sessionDuration, sessionService.sessionConfiguration.GetBinding(), request)
+	token, tokenHash, err := sessionService.tokenSigner.generate()
 	if err != nil {
-		return err
+		return httperrors.NewInternalServerError("session error", "failed to generate the session token", err)
+	}
+	session.TokenHash = tokenHash
+	herr = sessionService.add(session)
+	if herr != nil {
+		return herr
+	}
+	err = sessionService.setSessionCookies(response, token, session.ExpiresAt, session.ExpiresAt)
+	if err != nil {
+		return httperrors.NewInternalServerError("session error", "failed to generate the csrf token", err)
+	}
+	return nil
+}
+
+// Delete the session of the session cookie sent with a login, a new session is always created
+// so that a token planted in the browser before the login can't be used after it
+func (sessionService *sessionServiceImpl) revokeCookieSession(request *http.Request) httperrors.HTTPError {
+	cookie, err := request.Cookie(sessionService.cookieConfiguration.GetName())
+	if err != nil {
+		return nil
+	}
+	tokenHash, ok := sessionService.tokenSigner.hash(cookie.Value)
+	if !ok {
+		return nil
+	}
+	session := sessionService.getByTokenHash(tokenHash)
+	if session == nil {
+		return nil
+	}
+	return sessionService.delete(session)
+}
+
+// Replace the token of the session of the request and send it in the session cookie
+func (sessionService *sessionServiceImpl) RotateSession(
+	sessionClaims *SessionClaims,
+	response http.ResponseWriter,
+) httperrors.HTTPError {
+	if sessionClaims.SessionUUID == uuid.Nil {
+		return nil
+	}
+	session := sessionService.get(sessionClaims.SessionUUID)
+	if session == nil {
+		return HERRNotAuthenticated
+	}
+	token, tokenHash, err := sessionService.tokenSigner.generate()
+	if err != nil {
+		return httperrors.NewInternalServerError("session error", "failed to generate the session token", err)
+	}
+	rotatedSession := *session
+	rotatedSession.TokenHash = tokenHash
+	herr := sessionService.update(&rotatedSession, map[string]any{"token_hash": tokenHash})
+	if herr != nil {
+		return herr
+	}
+	session = &rotatedSession
+	sessionService.logger.Info("Rotated session token",
+		zap.String("userID", session.UserID.String()),
+		zap.String("sessionID", session.ID.String()))
+	err = sessionService.setSessionCookies(response, token, session.ExpiresAt, session.ExpiresAt)
+	if err != nil {
+		return httperrors.NewInternalServerError("session error", "failed to generate the csrf token", err)
 	}
-	CreateAndSetAccessTokenCookie(response, session.ID.String())
 	return nil
 }
 
@@ -234,24 +479,176 @@ func (sessionService *sessionServiceImpl) LogUserOut(sessionClaims *SessionClaim
 	if err != nil {
 		return err
 	}
-	CreateAndSetAccessTokenCookie(response, "")
+	sessionService.expireSessionCookies(response)
 	return nil
 }
 
-// Create an access token and send it in a cookie
-func CreateAndSetAccessTokenCookie(w http.ResponseWriter, sessionUUID string) {
-	accessToken := &http.Cookie{
-		Name:     "access_token",
-		Path:     "/",
-		Value:    sessionUUID,
-		HttpOnly: true,
-		SameSite: http.SameSiteNoneMode, // change to http.SameSiteStrictMode in prod
-		Secure:   false,                 // change to true in prod
-		Expires:  time.Now().Add(48 * time.Hour),
-	}
-	err := accessToken.Valid()
-	if err != nil {
-		panic(err)
+// Return the claims of a request authenticated with the session token
+//
+// The session is rolled if it's close to expiration.
+func (sessionService *sessionServiceImpl) Authenticate(accessToken string) (*SessionClaims, httperrors.HTTPError) {
+	tokenHash, ok := sessionService.tokenSigner.hash(accessToken)
+	if !ok {
+		return nil, HERRNotAuthenticated
+	}
+	session := sessionService.getByTokenHash(tokenHash)
+	if session == nil {
+		return nil, HERRNotAuthenticated
+	}
+	sessionClaims := makeSessionClaims(session)
+	herr := sessionService.RollSession(session.ID)
+	if herr != nil {
+		return nil, herr
+	}
+	return sessionClaims, nil
+}
+
+// Check that an authenticated request comes from the client of its session and record its activity
+//
+// The last activity of a session is only written once a minute, the requests without session are ignored.
+// The cookies of a session rolled by the request are extended with it.
+func (sessionService *sessionServiceImpl) Track(
+	sessionClaims *SessionClaims,
+	request *http.Request,
+	response http.ResponseWriter,
+) httperrors.HTTPError {
+	if sessionClaims.SessionUUID == uuid.Nil {
+		return nil
+	}
+	herr := sessionService.checkClient(sessionClaims, request)
+	if herr != nil {
+		return herr
 	}
-	http.SetCookie(w, accessToken)
+	sessionService.mutex.Lock()
+	// the sessions created by another node are tracked once they are pulled
+	session, ok := sessionService.cache[sessionClaims.SessionUUID]
+	sessionService.mutex.Unlock()
+	if !ok {
+		return nil
+	}
+	if session.ExpiresAt.After(sessionClaims.expiresAt) {
+		token, ok := sessionService.getCookieToken(request, session.TokenHash)
+		if ok {
+			err := sessionService.setSessionCookies(response, token, session.ExpiresAt, session.ExpiresAt)
+			if err != nil {
+				return httperrors.NewInternalServerError("session error", "failed to generate the csrf token", err)
+			}
+		}
+	}
+	now := time.Now()
+	if now.Sub(session.LastSeenAt) < lastSeenUpdateInterval {
+		return nil
+	}
+	trackedSession := *session
+	trackedSession.LastSeenAt = now
+	sessionService.replace(&trackedSession)
+	_, herr = sessionService.sessionRepository.UpdateColumns(
+		squirrel.Eq{"id": session.ID.String(), "deleted_at": nil},
+		map[string]any{"last_seen_at": now},
+	)
+	if herr != nil {
+		// the request is still authenticated
+		sessionService.logger.Warn("Failed to record the activity of a session",
+			zap.String("sessionID", session.ID.String()), zap.Error(herr))
+	}
+	return nil
+}
+
+// Refuse the requests made with a bound session from another client
+func (sessionService *sessionServiceImpl) checkClient(sessionClaims *SessionClaims, request *http.Request) httperrors.HTTPError {
+	if sessionClaims.fingerprint == "" {
+		return nil
+	}
+	if sessionClaims.fingerprint != getFingerprint(sessionService.sessionConfiguration.GetBinding(), request) {
+		sessionService.logger.Warn("Refused a session used from another client",
+			zap.String("userID", sessionClaims.UserID.String()),
+			zap.String("sessionID", sessionClaims.SessionUUID.String()),
+			zap.String("ip", GetClientIP(request)),
+			zap.String("userAgent", request.UserAgent()))
+		return HERRNotAuthenticated
+	}
+	return nil
+}
+
+// The sessions are rolled on use in the database mode, there is no refresh token
+func (sessionService *sessionServiceImpl) Refresh(refreshToken string, response http.ResponseWriter) httperrors.HTTPError {
+	return httperrors.NewHTTPError(http.StatusBadRequest, "session error",
+		"the sessions are rolled on use, they can't be refreshed", nil, false)
+}
+
+// There are no signing keys in the database mode
+func (sessionService *sessionServiceImpl) GetJWKS() jose.JSONWebKeySet {
+	return jose.JSONWebKeySet{Keys: []jose.JSONWebKey{}}
+}
+
+// Return the active sessions of a user
+func (sessionService *sessionServiceImpl) GetUserSessions(userID uuid.UUID) ([]*models.Session, httperrors.HTTPError) {
+	sessions, herr := sessionService.sessionRepository.Find(squirrel.And{
+		squirrel.Eq{"user_id": userID.String()},
+		squirrel.Gt{"expires_at": time.Now()},
+	}, nil, nil)
+	if herr != nil {
+		return nil, herr
+	}
+	return sessions.Ressources, nil
+}
+
+// Delete a session of a user, the other nodes refuse it from their next request
+func (sessionService *sessionServiceImpl) RevokeSession(userID, sessionUUID uuid.UUID) httperrors.HTTPError {
+	sessions, herr := sessionService.sessionRepository.Find(squirrel.Eq{
+		"id":      sessionUUID.String(),
+		"user_id": userID.String(),
+	}, nil, nil)
+	if herr != nil {
+		return herr
+	}
+	if !sessions.HasContent {
+		return HERRSessionNotFound
+	}
+	herr = sessionService.delete(sessions.Ressources[0])
+	if herr != nil {
+		return herr
+	}
+	sessionService.logger.Info("Revoked a session",
+		zap.String("userID", userID.String()), zap.String("sessionID", sessionUUID.String()))
+	return nil
+}
+
+// Delete all the sessions of a user
+func (sessionService *sessionServiceImpl) RevokeUserSessions(userID uuid.UUID) httperrors.HTTPError {
+	return sessionService.revokeUserSessions(userID, uuid.Nil)
+}
+
+// Delete all the sessions of a user except the one given
+func (sessionService *sessionServiceImpl) RevokeOtherUserSessions(userID, keptSessionUUID uuid.UUID) httperrors.HTTPError {
+	return sessionService.revokeUserSessions(userID, keptSessionUUID)
+}
+
+// Delete the sessions of a user, the session keptSessionUUID is kept if it's not nil
+func (sessionService *sessionServiceImpl) revokeUserSessions(userID, keptSessionUUID uuid.UUID) httperrors.HTTPError {
+	var filters squirrel.Sqlizer = squirrel.Eq{"user_id": userID.String()}
+	if keptSessionUUID != uuid.Nil {
+		filters = squirrel.And{filters, squirrel.NotEq{"id": keptSessionUUID.String()}}
+	}
+	sessions, herr := sessionService.sessionRepository.Find(filters, nil, nil)
+	if herr != nil {
+		return herr
+	}
+	for _, session := range sessions.Ressources {
+		herr = sessionService.delete(session)
+		if herr != nil {
+			return herr
+		}
+	}
+	// remove the sessions that are only in the cache
+	sessionService.mutex.Lock()
+	defer sessionService.mutex.Unlock()
+	for sessionUUID, session := range sessionService.cache {
+		if session.UserID == userID && sessionUUID != keptSessionUUID {
+			sessionService.uncache(sessionUUID)
+		}
+	}
+	sessionService.logger.Info("Revoked user sessions",
+		zap.String("userID", userID.String()), zap.Int("sessionCount", len(sessions.Ressources)))
+	return nil
 }
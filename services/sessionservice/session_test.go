@@ -1,11 +1,13 @@
 package sessionservice
 
 import (
+	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
 	"github.com/Masterminds/squirrel"
+	"github.com/ditrit/badaas/configuration"
 	"github.com/ditrit/badaas/httperrors"
 	configurationmocks "github.com/ditrit/badaas/mocks/configuration"
 	repositorymocks "github.com/ditrit/badaas/mocks/persistence/repository"
@@ -18,26 +20,51 @@ import (
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"go.uber.org/zap/zaptest/observer"
+	"gorm.io/gorm"
 )
 
 func TestNewSession(t *testing.T) {
-	sessionInstance := newSession(uuid.Nil, time.Second)
+	request := httptest.NewRequest("POST", "/login", nil)
+	request.Header.Set("User-Agent", firefoxOnLinux)
+	sessionInstance := newSession(uuid.Nil, time.Second, configuration.SessionBindingNone, request)
 	assert.NotNil(t, sessionInstance)
 	assert.Equal(t, uuid.Nil, sessionInstance.UserID)
+	assert.Equal(t, "192.0.2.1", sessionInstance.IPAddress)
+	assert.Equal(t, firefoxOnLinux, sessionInstance.UserAgent)
+	assert.Equal(t, "Firefox on Linux", sessionInstance.Device)
+	assert.Empty(t, sessionInstance.Fingerprint)
+	assert.WithinDuration(t, time.Now(), sessionInstance.LastSeenAt, time.Second)
 }
 
 func TestLogInUser(t *testing.T) {
 	sessionRepositoryMock, service, logs, sessionConfigurationMock := setupTest(t)
-	sessionRepositoryMock.On("Create", mock.Anything).Return(nil)
+	var session *models.Session
+	sessionRepositoryMock.On("Create", mock.Anything).Run(func(args mock.Arguments) {
+		session = args.Get(0).(*models.Session)
+		session.ID = uuid.New()
+	}).Return(nil)
 	sessionConfigurationMock.On("GetSessionDuration").Return(time.Minute)
+	sessionConfigurationMock.On("GetBinding").Return(configuration.SessionBindingNone)
 	response := httptest.NewRecorder()
 	user := &models.User{
 		Username: "bob",
 		Email:    "bob@email.com",
 	}
-	err := service.LogUserIn(user, response)
+	err := service.LogUserIn(user, httptest.NewRequest("POST", "/login", nil), response)
 	require.NoError(t, err)
 	assert.Len(t, service.cache, 1)
+	cookies := getCookies(response)
+	require.Contains(t, cookies, "access_token")
+	accessTokenCookie := cookies["access_token"]
+	assert.Equal(t, hashToken(accessTokenCookie.Value), session.TokenHash)
+	assert.Equal(t, session.ID, service.tokens[session.TokenHash])
+	assert.True(t, accessTokenCookie.HttpOnly)
+	assert.True(t, accessTokenCookie.Secure)
+	assert.Equal(t, http.SameSiteLaxMode, accessTokenCookie.SameSite)
+	assert.Equal(t, session.ExpiresAt.Unix(), accessTokenCookie.Expires.Unix())
+	require.Contains(t, cookies, "csrf_token")
+	assert.NotEmpty(t, cookies["csrf_token"].Value)
+	assert.False(t, cookies["csrf_token"].HttpOnly)
 	assert.Equal(t, 1, logs.Len())
 	log := logs.All()[0]
 	assert.Equal(t, "Added session", log.Message)
@@ -61,29 +88,46 @@ func setupTest(
 		sessionRepository:    sessionRepositoryMock,
 		logger:               logger,
 		cache:                make(map[uuid.UUID]*models.Session),
+		tokens:               make(map[string]uuid.UUID),
 		sessionConfiguration: sessionConfiguration,
+		cookieConfiguration:  newCookieConfigurationMock(t),
 	}
 
 	return sessionRepositoryMock, service, logs, sessionConfiguration
 }
 
+// make a cookie configuration with the default values
+func newCookieConfigurationMock(t *testing.T) *configurationmocks.CookieConfiguration {
+	cookieConfiguration := configurationmocks.NewCookieConfiguration(t)
+	cookieConfiguration.On("GetName").Return("access_token").Maybe()
+	cookieConfiguration.On("GetDomain").Return("").Maybe()
+	cookieConfiguration.On("GetPath").Return("/").Maybe()
+	cookieConfiguration.On("GetSecure").Return(true).Maybe()
+	cookieConfiguration.On("GetSameSite").Return(configuration.CookieSameSiteLax).Maybe()
+	cookieConfiguration.On("GetHostPrefix").Return(false).Maybe()
+	cookieConfiguration.On("GetCSRFEnabled").Return(true).Maybe()
+	cookieConfiguration.On("GetCSRFCookieName").Return("csrf_token").Maybe()
+	return cookieConfiguration
+}
+
 func TestLogInUserDbError(t *testing.T) {
 	sessionRepositoryMock, service, logs, sessionConfigurationMock := setupTest(t)
 	sessionRepositoryMock.On("Create", mock.Anything).Return(httperrors.NewInternalServerError("db err", "nil", nil))
 	sessionConfigurationMock.On("GetSessionDuration").Return(time.Minute)
+	sessionConfigurationMock.On("GetBinding").Return(configuration.SessionBindingNone)
 
 	response := httptest.NewRecorder()
 	user := &models.User{
 		Username: "bob",
 		Email:    "bob@email.com",
 	}
-	err := service.LogUserIn(user, response)
+	err := service.LogUserIn(user, httptest.NewRequest("POST", "/login", nil), response)
 	require.Error(t, err)
 	assert.Len(t, service.cache, 0)
 	assert.Equal(t, 0, logs.Len())
 }
 
-func TestIsValid(t *testing.T) {
+func TestGet(t *testing.T) {
 	sessionRepositoryMock, service, _, _ := setupTest(t)
 	sessionRepositoryMock.On("Create", mock.Anything).Return(nil)
 	uuidSample := uuid.New()
@@ -98,23 +142,16 @@ func TestIsValid(t *testing.T) {
 	require.NoError(t, err)
 	assert.Len(t, service.cache, 1)
 	assert.Equal(t, uuid.Nil, service.cache[uuidSample].UserID)
-	isValid, claims := service.IsValid(uuidSample)
-	require.True(t, isValid)
-	assert.Equal(t, *claims, SessionClaims{
-		UserID:      uuid.Nil,
-		SessionUUID: uuidSample,
-	})
+	assert.Equal(t, session, service.get(uuidSample))
 }
 
-func TestIsValid_SessionNotFound(t *testing.T) {
+func TestGet_SessionNotFound(t *testing.T) {
 	sessionRepositoryMock, service, _, _ := setupTest(t)
 	sessionRepositoryMock.
 		On("Find", mock.Anything, mock.Anything, mock.Anything).
 		Return(pagination.NewPage([]*models.Session{}, 0, 125, 1236), nil)
 	uuidSample := uuid.New()
-	isValid, _ := service.IsValid(uuidSample)
-	require.False(t, isValid)
-	//
+	assert.Nil(t, service.get(uuidSample))
 }
 
 func TestLogOutUser(t *testing.T) {
@@ -133,6 +170,12 @@ func TestLogOutUser(t *testing.T) {
 	err := service.LogUserOut(makeSessionClaims(session), response)
 	require.NoError(t, err)
 	assert.Len(t, service.cache, 0)
+	cookies := getCookies(response)
+	for _, name := range []string{"access_token", "csrf_token"} {
+		require.Contains(t, cookies, name)
+		assert.Empty(t, cookies[name].Value)
+		assert.Less(t, cookies[name].MaxAge, 0)
+	}
 }
 
 func TestLogOutUserDbError(t *testing.T) {
@@ -177,7 +220,7 @@ func TestLogOutUser_SessionNotFound(t *testing.T) {
 
 func TestRollSession(t *testing.T) {
 	sessionRepositoryMock, service, _, sessionConfigurationMock := setupTest(t)
-	sessionRepositoryMock.On("Save", mock.Anything).Return(nil)
+	sessionRepositoryMock.On("UpdateColumns", mock.Anything, mock.Anything).Return(uint(1), nil)
 	sessionDuration := time.Minute
 	sessionConfigurationMock.On("GetSessionDuration").Return(sessionDuration)
 	sessionConfigurationMock.On("GetRollDuration").Return(sessionDuration / 4)
@@ -185,7 +228,7 @@ func TestRollSession(t *testing.T) {
 	originalExpirationTime := time.Now().Add(sessionDuration / 5)
 	session := &models.Session{
 		BaseModel: models.BaseModel{
-			ID: uuid.Nil,
+			ID: uuidSample,
 		},
 		UserID:    uuid.Nil,
 		ExpiresAt: originalExpirationTime,
@@ -193,7 +236,25 @@ func TestRollSession(t *testing.T) {
 	service.cache[uuidSample] = session
 	err := service.RollSession(uuidSample)
 	require.NoError(t, err)
-	assert.Greater(t, session.ExpiresAt, originalExpirationTime)
+	assert.Greater(t, service.cache[uuidSample].ExpiresAt, originalExpirationTime)
+	// the cached session is replaced, not modified
+	assert.Equal(t, originalExpirationTime, session.ExpiresAt)
+}
+
+func TestRollSession_RevokedByAnotherNode(t *testing.T) {
+	sessionRepositoryMock, service, _, sessionConfigurationMock := setupTest(t)
+	// the session was deleted since it was cached, it is not written back
+	sessionRepositoryMock.On("UpdateColumns", mock.Anything, mock.Anything).Return(uint(0), nil)
+	sessionConfigurationMock.On("GetSessionDuration").Return(time.Minute)
+	sessionConfigurationMock.On("GetRollDuration").Return(time.Minute / 4)
+	session := &models.Session{
+		BaseModel: models.BaseModel{ID: uuid.New()},
+		ExpiresAt: time.Now().Add(time.Minute / 5),
+	}
+	service.cacheSession(session)
+	err := service.RollSession(session.ID)
+	assert.Equal(t, HERRNotAuthenticated, err)
+	assert.Empty(t, service.cache)
 }
 
 func TestRollSession_Expired(t *testing.T) {
@@ -239,7 +300,7 @@ func TestRollSession_falseUUID(t *testing.T) {
 func TestRollSession_sessionNotFound(t *testing.T) {
 	sessionRepositoryMock, service, _, sessionConfigurationMock := setupTest(t)
 	sessionRepositoryMock.
-		On("Find", squirrel.Eq{"uuid": "00000000-0000-0000-0000-000000000000"}, nil, nil).
+		On("Find", squirrel.Eq{"id": "00000000-0000-0000-0000-000000000000"}, nil, nil).
 		Return(
 			pagination.NewPage([]*models.Session{}, 0, 10, 0), nil)
 
@@ -283,7 +344,7 @@ func Test_removeExpired(t *testing.T) {
 	uuidSample := uuid.New()
 	session := &models.Session{
 		BaseModel: models.BaseModel{
-			ID: uuid.Nil,
+			ID: uuidSample,
 		},
 		UserID:    uuid.Nil,
 		ExpiresAt: time.Now().Add(-time.Hour),
@@ -338,3 +399,316 @@ func Test_get(t *testing.T) {
 	sessionFound := service.get(uuidSample)
 	assert.Equal(t, sessionFound, session)
 }
+
+func TestRevokeUserSessions(t *testing.T) {
+	sessionRepositoryMock, service, _, _ := setupTest(t)
+	userID := uuid.New()
+	session := &models.Session{BaseModel: models.BaseModel{ID: uuid.New()}, UserID: userID, ExpiresAt: time.Now().Add(time.Hour)}
+	cachedOnly := &models.Session{BaseModel: models.BaseModel{ID: uuid.New()}, UserID: userID, ExpiresAt: time.Now().Add(time.Hour)}
+	otherSession := &models.Session{BaseModel: models.BaseModel{ID: uuid.New()}, UserID: uuid.New(), ExpiresAt: time.Now().Add(time.Hour)}
+	service.cache[session.ID] = session
+	service.cache[cachedOnly.ID] = cachedOnly
+	service.cache[otherSession.ID] = otherSession
+	sessionRepositoryMock.On("Find", squirrel.Eq{"user_id": userID.String()}, nil, nil).
+		Return(pagination.NewPage([]*models.Session{session}, 0, 10, 1), nil)
+	sessionRepositoryMock.On("Delete", session).Return(nil)
+
+	err := service.RevokeUserSessions(userID)
+	require.NoError(t, err)
+	assert.Len(t, service.cache, 1)
+	assert.Contains(t, service.cache, otherSession.ID)
+}
+
+func TestRevokeUserSessions_DbError(t *testing.T) {
+	sessionRepositoryMock, service, _, _ := setupTest(t)
+	sessionRepositoryMock.On("Find", mock.Anything, nil, nil).Return(nil, httperrors.AnError)
+
+	err := service.RevokeUserSessions(uuid.New())
+	assert.Equal(t, httperrors.AnError, err)
+}
+
+func TestRevokeOtherUserSessions(t *testing.T) {
+	sessionRepositoryMock, service, _, _ := setupTest(t)
+	userID := uuid.New()
+	current := &models.Session{BaseModel: models.BaseModel{ID: uuid.New()}, UserID: userID, ExpiresAt: time.Now().Add(time.Hour)}
+	other := &models.Session{BaseModel: models.BaseModel{ID: uuid.New()}, UserID: userID, ExpiresAt: time.Now().Add(time.Hour)}
+	service.cache[current.ID] = current
+	service.cache[other.ID] = other
+	sessionRepositoryMock.On("Find", squirrel.And{
+		squirrel.Eq{"user_id": userID.String()},
+		squirrel.NotEq{"id": current.ID.String()},
+	}, nil, nil).Return(pagination.NewPage([]*models.Session{other}, 0, 10, 1), nil)
+	sessionRepositoryMock.On("Delete", other).Return(nil)
+
+	err := service.RevokeOtherUserSessions(userID, current.ID)
+	require.NoError(t, err)
+	assert.Len(t, service.cache, 1)
+	assert.Contains(t, service.cache, current.ID)
+}
+
+func TestPullRevocations(t *testing.T) {
+	sessionRepositoryMock, service, _, _ := setupTest(t)
+	now := time.Now()
+	revoked := &models.Session{BaseModel: models.BaseModel{ID: uuid.New()}, TokenHash: hashToken("revoked"), ExpiresAt: now.Add(time.Hour)}
+	rotated := &models.Session{BaseModel: models.BaseModel{ID: uuid.New(), UpdatedAt: now.Add(-time.Hour)},
+		TokenHash: hashToken("rotated"), ExpiresAt: now.Add(time.Hour)}
+	changedHere := &models.Session{BaseModel: models.BaseModel{ID: uuid.New(), UpdatedAt: now},
+		TokenHash: hashToken("changedHere"), ExpiresAt: now.Add(time.Hour)}
+	for _, session := range []*models.Session{revoked, rotated, changedHere} {
+		service.cacheSession(session)
+	}
+	deletedRevoked := *revoked
+	deletedRevoked.DeletedAt = gorm.DeletedAt{Time: now, Valid: true}
+	newRotated := *rotated
+	newRotated.UpdatedAt = now
+	newRotated.TokenHash = hashToken("new")
+	oldChangedHere := *changedHere
+	oldChangedHere.UpdatedAt = now.Add(-time.Second)
+	oldChangedHere.TokenHash = hashToken("old")
+	since := now.Add(-time.Second)
+	sessionRepositoryMock.On("FindUnscoped", squirrel.Or{
+		squirrel.Gt{"updated_at": since.Add(-revocationPullMargin)},
+		squirrel.Gt{"deleted_at": since.Add(-revocationPullMargin)},
+	}).Return([]*models.Session{&deletedRevoked, &newRotated, &oldChangedHere}, nil)
+
+	assert.True(t, service.pullRevocations(since).After(since))
+	assert.Equal(t, map[string]uuid.UUID{
+		hashToken("new"):         rotated.ID,
+		hashToken("changedHere"): changedHere.ID,
+	}, service.tokens)
+	sessionRepositoryMock.On("Find", mock.Anything, nil, nil).Return(pagination.NewPage([]*models.Session{}, 0, 1, 0), nil)
+	for _, token := range []string{"revoked", "rotated"} {
+		_, herr := service.Authenticate(token)
+		assert.Equal(t, HERRNotAuthenticated, herr, token)
+	}
+}
+
+func TestPullRevocations_DbError(t *testing.T) {
+	sessionRepositoryMock, service, _, _ := setupTest(t)
+	sessionRepositoryMock.On("FindUnscoped", mock.Anything).Return(nil, httperrors.AnError)
+	since := time.Now().Add(-time.Second)
+	assert.Equal(t, since, service.pullRevocations(since))
+}
+
+func TestGetUserSessions(t *testing.T) {
+	sessionRepositoryMock, service, _, _ := setupTest(t)
+	userID := uuid.New()
+	sessions := []*models.Session{{UserID: userID, ExpiresAt: time.Now().Add(time.Hour)}}
+	sessionRepositoryMock.On("Find", mock.Anything, nil, nil).Return(pagination.NewPage(sessions, 1, 10, 1), nil)
+	userSessions, herr := service.GetUserSessions(userID)
+	require.Nil(t, herr)
+	assert.Equal(t, sessions, userSessions)
+}
+
+func TestRevokeSession(t *testing.T) {
+	sessionRepositoryMock, service, _, _ := setupTest(t)
+	userID := uuid.New()
+	session := &models.Session{BaseModel: models.BaseModel{ID: uuid.New()}, UserID: userID}
+	service.cache[session.ID] = session
+	sessionRepositoryMock.On("Find", squirrel.Eq{"id": session.ID.String(), "user_id": userID.String()}, nil, nil).
+		Return(pagination.NewPage([]*models.Session{session}, 1, 10, 1), nil)
+	sessionRepositoryMock.On("Delete", session).Return(nil)
+	herr := service.RevokeSession(userID, session.ID)
+	require.Nil(t, herr)
+	assert.Len(t, service.cache, 0)
+}
+
+func TestRevokeSession_OtherUser(t *testing.T) {
+	sessionRepositoryMock, service, _, _ := setupTest(t)
+	sessionRepositoryMock.On("Find", mock.Anything, nil, nil).
+		Return(pagination.NewPage([]*models.Session{}, 1, 10, 0), nil)
+	herr := service.RevokeSession(uuid.New(), uuid.New())
+	assert.Equal(t, HERRSessionNotFound, herr)
+}
+
+func TestTrack(t *testing.T) {
+	sessionRepositoryMock, service, _, _ := setupTest(t)
+	session := &models.Session{
+		BaseModel:  models.BaseModel{ID: uuid.New()},
+		ExpiresAt:  time.Now().Add(time.Hour),
+		LastSeenAt: time.Now().Add(-time.Hour),
+	}
+	service.cache[session.ID] = session
+	// only the last activity is written, so that a concurrent change or revocation is not overwritten
+	sessionRepositoryMock.On(
+		"UpdateColumns",
+		squirrel.Eq{"id": session.ID.String(), "deleted_at": nil},
+		mock.MatchedBy(func(columns map[string]any) bool { return len(columns) == 1 && columns["last_seen_at"] != nil }),
+	).Return(uint(1), nil).Once()
+
+	herr := service.Track(makeSessionClaims(session), httptest.NewRequest("GET", "/me", nil), httptest.NewRecorder())
+	require.Nil(t, herr)
+	assert.WithinDuration(t, time.Now(), service.cache[session.ID].LastSeenAt, time.Second)
+
+	// the next requests of the minute are not written
+	herr = service.Track(makeSessionClaims(session), httptest.NewRequest("GET", "/me", nil), httptest.NewRecorder())
+	require.Nil(t, herr)
+}
+
+func TestTrack_WithoutSession(t *testing.T) {
+	_, service, _, _ := setupTest(t)
+	herr := service.Track(&SessionClaims{UserID: uuid.New()}, httptest.NewRequest("GET", "/me", nil), httptest.NewRecorder())
+	assert.Nil(t, herr)
+}
+
+func TestTrack_BoundSession(t *testing.T) {
+	_, service, logs, sessionConfigurationMock := setupTest(t)
+	sessionConfigurationMock.On("GetBinding").Return(configuration.SessionBindingDeviceAndNetwork)
+	loginRequest := httptest.NewRequest("POST", "/login", nil)
+	loginRequest.Header.Set("User-Agent", firefoxOnLinux)
+	loginRequest.RemoteAddr = "198.51.100.7:4321"
+	session := newSession(uuid.New(), time.Hour, configuration.SessionBindingDeviceAndNetwork, loginRequest)
+	session.ID = uuid.New()
+	service.cache[session.ID] = session
+
+	// same browser, same network
+	request := httptest.NewRequest("GET", "/me", nil)
+	request.Header.Set("User-Agent", firefoxOnLinux)
+	request.RemoteAddr = "198.51.42.42:1234"
+	assert.Nil(t, service.Track(makeSessionClaims(session), request, httptest.NewRecorder()))
+
+	// another browser
+	request.Header.Set("User-Agent", "curl/8.0.1")
+	assert.Equal(t, HERRNotAuthenticated, service.Track(makeSessionClaims(session), request, httptest.NewRecorder()))
+
+	// another network
+	request.Header.Set("User-Agent", firefoxOnLinux)
+	request.RemoteAddr = "203.0.113.5:1234"
+	assert.Equal(t, HERRNotAuthenticated, service.Track(makeSessionClaims(session), request, httptest.NewRecorder()))
+	assert.Equal(t, 2, logs.FilterMessage("Refused a session used from another client").Len())
+}
+
+func TestTrack_RolledSessionExtendsTheCookies(t *testing.T) {
+	sessionRepositoryMock, service, _, sessionConfigurationMock := setupTest(t)
+	sessionRepositoryMock.On("UpdateColumns", mock.Anything, mock.Anything).Return(uint(1), nil)
+	sessionConfigurationMock.On("GetRollDuration").Return(time.Hour)
+	sessionConfigurationMock.On("GetSessionDuration").Return(2 * time.Hour)
+	session := &models.Session{
+		BaseModel:  models.BaseModel{ID: uuid.New()},
+		TokenHash:  hashToken("token"),
+		ExpiresAt:  time.Now().Add(time.Minute),
+		LastSeenAt: time.Now(),
+	}
+	service.cacheSession(session)
+	request := httptest.NewRequest("GET", "/me", nil)
+	request.AddCookie(&http.Cookie{Name: "access_token", Value: "token"})
+	response := httptest.NewRecorder()
+
+	sessionClaims, herr := service.Authenticate("token")
+	require.Nil(t, herr)
+	require.Nil(t, service.Track(sessionClaims, request, response))
+	cookies := getCookies(response)
+	require.Contains(t, cookies, "access_token")
+	assert.Equal(t, service.cache[session.ID].ExpiresAt.Unix(), cookies["access_token"].Expires.Unix())
+	assert.Contains(t, cookies, "csrf_token")
+
+	assert.Equal(t, "token", cookies["access_token"].Value)
+
+	// the session is not rolled again
+	sessionClaims, herr = service.Authenticate("token")
+	require.Nil(t, herr)
+	response = httptest.NewRecorder()
+	require.Nil(t, service.Track(sessionClaims, request, response))
+	assert.Empty(t, getCookies(response))
+}
+
+func TestAuthenticate_UnknownToken(t *testing.T) {
+	sessionRepositoryMock, service, _, _ := setupTest(t)
+	sessionRepositoryMock.
+		On("Find", squirrel.Eq{"token_hash": hashToken("token")}, nil, nil).
+		Return(pagination.NewPage([]*models.Session{}, 0, 1, 0), nil)
+	_, herr := service.Authenticate("token")
+	assert.Equal(t, HERRNotAuthenticated, herr)
+}
+
+func TestLogInUser_RevokesTheSessionOfTheCookie(t *testing.T) {
+	sessionRepositoryMock, service, _, sessionConfigurationMock := setupTest(t)
+	sessionConfigurationMock.On("GetSessionDuration").Return(time.Minute)
+	sessionConfigurationMock.On("GetBinding").Return(configuration.SessionBindingNone)
+	previousSession := &models.Session{
+		BaseModel: models.BaseModel{ID: uuid.New()},
+		TokenHash: hashToken("planted"),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+	service.cacheSession(previousSession)
+	sessionRepositoryMock.On("Delete", previousSession).Return(nil)
+	sessionRepositoryMock.On("Create", mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(0).(*models.Session).ID = uuid.New()
+	}).Return(nil)
+	request := httptest.NewRequest("POST", "/login", nil)
+	request.AddCookie(&http.Cookie{Name: "access_token", Value: "planted"})
+	response := httptest.NewRecorder()
+
+	herr := service.LogUserIn(&models.User{}, request, response)
+	require.Nil(t, herr)
+	assert.NotContains(t, service.cache, previousSession.ID)
+	assert.NotContains(t, service.tokens, previousSession.TokenHash)
+	assert.NotEqual(t, "planted", getCookies(response)["access_token"].Value)
+}
+
+func TestRotateSession(t *testing.T) {
+	sessionRepositoryMock, service, _, _ := setupTest(t)
+	session := &models.Session{
+		BaseModel: models.BaseModel{ID: uuid.New()},
+		TokenHash: hashToken("token"),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+	service.cacheSession(session)
+	sessionRepositoryMock.On(
+		"UpdateColumns",
+		squirrel.Eq{"id": session.ID.String(), "deleted_at": nil},
+		mock.MatchedBy(func(columns map[string]any) bool { return len(columns) == 2 && columns["token_hash"] != nil }),
+	).Return(uint(1), nil)
+	response := httptest.NewRecorder()
+
+	herr := service.RotateSession(makeSessionClaims(session), response)
+	require.Nil(t, herr)
+	cookies := getCookies(response)
+	require.Contains(t, cookies, "access_token")
+	newToken := cookies["access_token"].Value
+	assert.NotEqual(t, "token", newToken)
+	assert.Equal(t, hashToken(newToken), service.cache[session.ID].TokenHash)
+	assert.Equal(t, map[string]uuid.UUID{hashToken(newToken): session.ID}, service.tokens)
+	assert.Contains(t, cookies, "csrf_token")
+}
+
+func TestRotateSession_WithoutSession(t *testing.T) {
+	_, service, _, _ := setupTest(t)
+	assert.Nil(t, service.RotateSession(&SessionClaims{UserID: uuid.New(), APIKeyID: uuid.New()}, httptest.NewRecorder()))
+}
+
+func TestCheckCookieConfiguration(t *testing.T) {
+	tests := []struct {
+		name          string
+		sameSite      string
+		secure        bool
+		hostPrefix    bool
+		domain        string
+		expectedError string
+	}{
+		{name: "valid", sameSite: configuration.CookieSameSiteStrict, secure: true, hostPrefix: true},
+		{name: "unknown SameSite", sameSite: "always", expectedError: `unknown cookie SameSite attribute "always"`},
+		{name: "SameSite None not secure", sameSite: configuration.CookieSameSiteNone, expectedError: "SameSite=None must be secure"},
+		{name: "host prefix not secure", sameSite: configuration.CookieSameSiteLax, hostPrefix: true, expectedError: "__Host-"},
+		{
+			name: "host prefix with domain", sameSite: configuration.CookieSameSiteLax, secure: true, hostPrefix: true,
+			domain: "example.com", expectedError: "__Host-",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cookieConfiguration := configurationmocks.NewCookieConfiguration(t)
+			cookieConfiguration.On("GetSameSite").Return(test.sameSite)
+			cookieConfiguration.On("GetSecure").Return(test.secure).Maybe()
+			cookieConfiguration.On("GetHostPrefix").Return(test.hostPrefix).Maybe()
+			cookieConfiguration.On("GetDomain").Return(test.domain).Maybe()
+			cookieConfiguration.On("GetPath").Return("/").Maybe()
+			err := checkCookieConfiguration(cookieConfiguration)
+			if test.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, test.expectedError)
+			}
+		})
+	}
+}
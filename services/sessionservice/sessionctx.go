@@ -2,6 +2,7 @@ package sessionservice
 
 import (
 	"context"
+	"time"
 
 	"github.com/ditrit/badaas/persistence/models"
 	"github.com/google/uuid"
@@ -11,6 +12,19 @@ import (
 type SessionClaims struct {
 	UserID      uuid.UUID
 	SessionUUID uuid.UUID
+	// Set if the request is authenticated with an API key instead of a session
+	APIKeyID uuid.UUID
+	// The permissions an API key is restricted to
+	Scopes []string
+	// The hash of the client the session is bound to
+	fingerprint string
+	// The expiration of the session when the request was authenticated
+	expiresAt time.Time
+}
+
+// Return true if the request is authenticated with an API key
+func (sessionClaims *SessionClaims) IsAPIKey() bool {
+	return sessionClaims.APIKeyID != uuid.Nil
 }
 
 // Unique claim key type
@@ -31,6 +45,8 @@ func makeSessionClaims(session *models.Session) *SessionClaims {
 	return &SessionClaims{
 		UserID:      session.UserID,
 		SessionUUID: session.ID,
+		fingerprint: session.Fingerprint,
+		expiresAt:   session.ExpiresAt,
 	}
 }
 
@@ -43,3 +59,13 @@ func GetSessionClaimsFromContext(ctx context.Context) *SessionClaims {
 	}
 	return claims
 }
+
+// Extract SessionClaims from request context
+// Return nil if the claims are not in the context (unauthenticated request)
+func LookupSessionClaimsFromContext(ctx context.Context) *SessionClaims {
+	claims, ok := ctx.Value(sessionClaimsKey).(*SessionClaims)
+	if !ok {
+		return nil
+	}
+	return claims
+}
@@ -10,7 +10,7 @@ import (
 
 func TestSessionCtx(t *testing.T) {
 	ctx := context.Background()
-	sessionClaims := &SessionClaims{uuid.Nil, uuid.New()}
+	sessionClaims := &SessionClaims{UserID: uuid.Nil, SessionUUID: uuid.New()}
 	ctx = SetSessionClaimsContext(ctx, sessionClaims)
 	claims := GetSessionClaimsFromContext(ctx)
 	assert.Equal(t, uuid.Nil, claims.UserID)
@@ -20,3 +20,11 @@ func TestSessionCtxPanic(t *testing.T) {
 	ctx := context.Background()
 	assert.Panics(t, func() { GetSessionClaimsFromContext(ctx) })
 }
+
+func TestLookupSessionClaimsFromContext(t *testing.T) {
+	ctx := context.Background()
+	assert.Nil(t, LookupSessionClaimsFromContext(ctx))
+	sessionClaims := &SessionClaims{UserID: uuid.Nil, SessionUUID: uuid.New()}
+	ctx = SetSessionClaimsContext(ctx, sessionClaims)
+	assert.Equal(t, sessionClaims, LookupSessionClaimsFromContext(ctx))
+}
@@ -0,0 +1,355 @@
+package sessionservice
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/ditrit/badaas/httperrors"
+	"github.com/ditrit/badaas/persistence/models"
+	"github.com/ditrit/badaas/services/auth/protocols/jwt"
+	"github.com/go-jose/go-jose/v3"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// The name of the cookie holding the refresh token
+	RefreshTokenCookieName = "refresh_token"
+	// The refresh token is only sent to the refresh route
+	RefreshTokenCookiePath = "/session/refresh"
+)
+
+// Check interface compliance
+var _ SessionService = (*jwtSessionService)(nil)
+
+// The SessionService of the jwt mode
+//
+// The requests are authenticated with short lived access tokens verified without the database,
+// only the sessions holding the refresh tokens are stored.
+type jwtSessionService struct {
+	*sessionServiceImpl
+	keySet *jwt.KeySet
+	// The last activity recorded by this node for each session
+	lastSeen      map[uuid.UUID]time.Time
+	lastSeenMutex sync.Mutex
+}
+
+// Create the SessionService of the jwt mode, load the signing keys
+func newJWTSessionService(sessionService *sessionServiceImpl) (*jwtSessionService, error) {
+	keySet, err := loadKeySet(sessionService)
+	if err != nil {
+		return nil, err
+	}
+	jwtSessionService := &jwtSessionService{
+		sessionServiceImpl: sessionService,
+		keySet:             keySet,
+		lastSeen:           make(map[uuid.UUID]time.Time),
+	}
+	jwtSessionService.init()
+	return jwtSessionService, nil
+}
+
+// Load the configured signing keys, generate one if there is none
+func loadKeySet(sessionService *sessionServiceImpl) (*jwt.KeySet, error) {
+	algorithm := sessionService.sessionConfiguration.GetJWTAlgorithm()
+	configuredKeys := sessionService.sessionConfiguration.GetJWTKeys()
+	keys := make([]jwt.Key, 0, len(configuredKeys))
+	for _, configuredKey := range configuredKeys {
+		pemBytes, err := os.ReadFile(configuredKey.PrivateKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the jwt key %q: %w", configuredKey.ID, err)
+		}
+		privateKey, err := jwt.ParsePrivateKey(pemBytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the jwt key %q: %w", configuredKey.ID, err)
+		}
+		keys = append(keys, jwt.Key{ID: configuredKey.ID, PrivateKey: privateKey})
+	}
+	if len(keys) == 0 {
+		privateKey, err := jwt.GenerateKey(algorithm)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, jwt.Key{ID: uuid.NewString(), PrivateKey: privateKey})
+		sessionService.logger.Warn(
+			"No jwt key configured, generated one: the access tokens are only valid on this node until it restarts")
+	}
+	return jwt.NewKeySet(algorithm, keys)
+}
+
+// Initialize the session service, the sessions are not cached
+func (sessionService *jwtSessionService) init() {
+	go func() {
+		for {
+			sessionService.removeExpiredFromDB()
+			sessionService.forgetLastSeen()
+			time.Sleep(
+				sessionService.sessionConfiguration.GetPullInterval(),
+			)
+		}
+	}()
+}
+
+// Delete the expired sessions from the database
+func (sessionService *jwtSessionService) removeExpiredFromDB() {
+	sessions, herr := sessionService.sessionRepository.Find(squirrel.Lt{"expires_at": time.Now()}, nil, nil)
+	if herr != nil {
+		sessionService.logger.Error("Failed to find the expired sessions", zap.Error(herr))
+		return
+	}
+	for _, session := range sessions.Ressources {
+		herr = sessionService.sessionRepository.Delete(session)
+		if herr != nil {
+			sessionService.logger.Error("Failed to delete an expired session", zap.Error(herr))
+			return
+		}
+	}
+	sessionService.logger.Debug(
+		"Removed expired session",
+		zap.Int("expiredSessionCount", len(sessions.Ressources)),
+	)
+}
+
+// Forget the activities recorded before the update interval, they don't throttle the writes anymore
+func (sessionService *jwtSessionService) forgetLastSeen() {
+	sessionService.lastSeenMutex.Lock()
+	defer sessionService.lastSeenMutex.Unlock()
+	now := time.Now()
+	for sessionUUID, lastSeen := range sessionService.lastSeen {
+		if now.Sub(lastSeen) >= lastSeenUpdateInterval {
+			delete(sessionService.lastSeen, sessionUUID)
+		}
+	}
+}
+
+// Return the claims of the access token, without reading the database
+func (sessionService *jwtSessionService) Authenticate(accessToken string) (*SessionClaims, httperrors.HTTPError) {
+	claims, err := sessionService.keySet.Verify(accessToken)
+	if err != nil {
+		return nil, HERRNotAuthenticated
+	}
+	userID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return nil, HERRNotAuthenticated
+	}
+	sessionUUID, err := uuid.Parse(claims.SessionID)
+	if err != nil {
+		return nil, HERRNotAuthenticated
+	}
+	return &SessionClaims{UserID: userID, SessionUUID: sessionUUID, fingerprint: claims.Fingerprint}, nil
+}
+
+// Check that an authenticated request comes from the client of its session and record its activity
+//
+// The client is checked with the fingerprint of the access token, the last activity of a session
+// is only written once a minute by each node.
+func (sessionService *jwtSessionService) Track(
+	sessionClaims *SessionClaims,
+	request *http.Request,
+	response http.ResponseWriter,
+) httperrors.HTTPError {
+	if sessionClaims.SessionUUID == uuid.Nil {
+		return nil
+	}
+	herr := sessionService.checkClient(sessionClaims, request)
+	if herr != nil {
+		return herr
+	}
+	now := time.Now()
+	sessionService.lastSeenMutex.Lock()
+	if now.Sub(sessionService.lastSeen[sessionClaims.SessionUUID]) < lastSeenUpdateInterval {
+		sessionService.lastSeenMutex.Unlock()
+		return nil
+	}
+	sessionService.lastSeen[sessionClaims.SessionUUID] = now
+	sessionService.lastSeenMutex.Unlock()
+	_, herr = sessionService.sessionRepository.UpdateColumns(
+		squirrel.Eq{"id": sessionClaims.SessionUUID.String(), "deleted_at": nil},
+		map[string]any{"last_seen_at": now},
+	)
+	if herr != nil {
+		// the request is still authenticated
+		sessionService.logger.Warn("Failed to record the activity of a session",
+			zap.String("sessionID", sessionClaims.SessionUUID.String()), zap.Error(herr))
+	}
+	return nil
+}
+
+// The sessions are extended with their refresh token
+func (sessionService *jwtSessionService) RollSession(uuid.UUID) httperrors.HTTPError {
+	return nil
+}
+
+// Log in a user, send an access token and a refresh token
+func (sessionService *jwtSessionService) LogUserIn(
+	user *models.User,
+	request *http.Request,
+	response http.ResponseWriter,
+) httperrors.HTTPError {
+	herr := sessionService.revokeCookieSession(request)
+	if herr != nil {
+		return herr
+	}
+	session := newSession(user.ID, sessionService.sessionConfiguration.GetSessionDuration(),
+		sessionService.sessionConfiguration.GetBinding(), request)
+	refreshToken, refreshTokenHash, err := sessionService.tokenSigner.generate()
+	if err != nil {
+		return httperrors.NewInternalServerError("session error", "failed to generate the refresh token", err)
+	}
+	session.TokenHash = refreshTokenHash
+	herr = sessionService.sessionRepository.Create(session)
+	if herr != nil {
+		return herr
+	}
+	sessionService.logger.Debug("Added session", zap.String("uuid", session.ID.String()))
+	return sessionService.setTokens(session, refreshToken, response)
+}
+
+// Delete the session of the access token sent with a login, a new session is always created
+//
+// The refresh token cookie is not sent to the login routes.
+func (sessionService *jwtSessionService) revokeCookieSession(request *http.Request) httperrors.HTTPError {
+	cookie, err := request.Cookie(sessionService.cookieConfiguration.GetName())
+	if err != nil {
+		return nil
+	}
+	sessionClaims, herr := sessionService.Authenticate(cookie.Value)
+	if herr != nil {
+		return nil
+	}
+	session, herr := sessionService.sessionRepository.GetByID(sessionClaims.SessionUUID)
+	if herr != nil {
+		return nil
+	}
+	return sessionService.delete(session)
+}
+
+// Exchange a refresh token for a new access token
+//
+// The refresh token is rotated and the session is extended.
+func (sessionService *jwtSessionService) Refresh(refreshToken string, response http.ResponseWriter) httperrors.HTTPError {
+	refreshTokenHash, ok := sessionService.tokenSigner.hash(refreshToken)
+	if !ok {
+		return HERRNotAuthenticated
+	}
+	sessions, herr := sessionService.sessionRepository.Find(
+		squirrel.Eq{"token_hash": refreshTokenHash}, nil, nil)
+	if herr != nil {
+		return herr
+	}
+	if !sessions.HasContent {
+		return HERRNotAuthenticated
+	}
+	session := sessions.Ressources[0]
+	if session.IsExpired() {
+		return HERRSessionExpired
+	}
+	newRefreshToken, newRefreshTokenHash, err := sessionService.tokenSigner.generate()
+	if err != nil {
+		return httperrors.NewInternalServerError("session error", "failed to generate the refresh token", err)
+	}
+	session.TokenHash = newRefreshTokenHash
+	session.ExpiresAt = time.Now().Add(sessionService.sessionConfiguration.GetSessionDuration())
+	// a refresh token can only be used once, even by concurrent requests
+	updated, herr := sessionService.sessionRepository.UpdateColumns(
+		squirrel.Eq{"id": session.ID.String(), "token_hash": refreshTokenHash, "deleted_at": nil},
+		map[string]any{"token_hash": session.TokenHash, "expires_at": session.ExpiresAt, "updated_at": time.Now()},
+	)
+	if herr != nil {
+		return herr
+	}
+	if updated == 0 {
+		return HERRNotAuthenticated
+	}
+	return sessionService.setTokens(session, newRefreshToken, response)
+}
+
+// Replace the refresh token of the session of the request and sign a new access token
+//
+// The previous access token stays valid until it expires.
+func (sessionService *jwtSessionService) RotateSession(
+	sessionClaims *SessionClaims,
+	response http.ResponseWriter,
+) httperrors.HTTPError {
+	if sessionClaims.SessionUUID == uuid.Nil {
+		return nil
+	}
+	session, herr := sessionService.sessionRepository.GetByID(sessionClaims.SessionUUID)
+	if herr != nil {
+		return HERRNotAuthenticated
+	}
+	refreshToken, refreshTokenHash, err := sessionService.tokenSigner.generate()
+	if err != nil {
+		return httperrors.NewInternalServerError("session error", "failed to generate the refresh token", err)
+	}
+	session.TokenHash = refreshTokenHash
+	updated, herr := sessionService.sessionRepository.UpdateColumns(
+		squirrel.Eq{"id": session.ID.String(), "deleted_at": nil},
+		map[string]any{"token_hash": session.TokenHash, "updated_at": time.Now()},
+	)
+	if herr != nil {
+		return herr
+	}
+	if updated == 0 {
+		return HERRNotAuthenticated
+	}
+	sessionService.logger.Info("Rotated session token",
+		zap.String("userID", session.UserID.String()),
+		zap.String("sessionID", session.ID.String()))
+	return sessionService.setTokens(session, refreshToken, response)
+}
+
+// Log out a user, the refresh token is revoked
+//
+// The access token can't be revoked, it stays valid until it expires.
+func (sessionService *jwtSessionService) LogUserOut(sessionClaims *SessionClaims, response http.ResponseWriter) httperrors.HTTPError {
+	session, herr := sessionService.sessionRepository.GetByID(sessionClaims.SessionUUID)
+	if herr != nil {
+		return HERRNotAuthenticated
+	}
+	herr = sessionService.delete(session)
+	if herr != nil {
+		return herr
+	}
+	sessionService.expireSessionCookies(response)
+	refreshTokenCookie := sessionService.newCookie(RefreshTokenCookieName, RefreshTokenCookiePath, "", time.Unix(0, 0))
+	refreshTokenCookie.MaxAge = -1
+	http.SetCookie(response, refreshTokenCookie)
+	return nil
+}
+
+// Return the public keys verifying the access tokens
+func (sessionService *jwtSessionService) GetJWKS() jose.JSONWebKeySet {
+	return sessionService.keySet.JWKS()
+}
+
+// Sign an access token for the session and send it with the refresh token in cookies
+func (sessionService *jwtSessionService) setTokens(
+	session *models.Session,
+	refreshToken string,
+	response http.ResponseWriter,
+) httperrors.HTTPError {
+	now := time.Now()
+	accessTokenExpiresAt := now.Add(sessionService.sessionConfiguration.GetJWTAccessTokenDuration())
+	accessToken, err := sessionService.keySet.Sign(jwt.Claims{
+		Subject:     session.UserID.String(),
+		SessionID:   session.ID.String(),
+		Fingerprint: session.Fingerprint,
+		IssuedAt:    now,
+		ExpiresAt:   accessTokenExpiresAt,
+	})
+	if err != nil {
+		return httperrors.NewInternalServerError("session error", "failed to sign the access token", err)
+	}
+	err = sessionService.setSessionCookies(response, accessToken, accessTokenExpiresAt, session.ExpiresAt)
+	if err != nil {
+		return httperrors.NewInternalServerError("session error", "failed to generate the csrf token", err)
+	}
+	http.SetCookie(response, sessionService.newCookie(
+		RefreshTokenCookieName, RefreshTokenCookiePath, refreshToken, session.ExpiresAt))
+	return nil
+}
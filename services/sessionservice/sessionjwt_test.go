@@ -0,0 +1,301 @@
+package sessionservice
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/ditrit/badaas/configuration"
+	configurationmocks "github.com/ditrit/badaas/mocks/configuration"
+	repositorymocks "github.com/ditrit/badaas/mocks/persistence/repository"
+	"github.com/ditrit/badaas/persistence/models"
+	"github.com/ditrit/badaas/persistence/pagination"
+	"github.com/ditrit/badaas/services/auth/protocols/jwt"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestNewSessionServiceUnknownMode(t *testing.T) {
+	sessionConfiguration := configurationmocks.NewSessionConfiguration(t)
+	sessionConfiguration.On("GetSigningKeys").Return([]configuration.SessionSigningKey{})
+	sessionConfiguration.On("GetBinding").Return(configuration.SessionBindingNone)
+	sessionConfiguration.On("GetMode").Return("memory")
+	_, err := NewSessionService(zap.L(), repositorymocks.NewCRUDRepository[models.Session, uuid.UUID](t),
+		sessionConfiguration, newCookieConfigurationMock(t))
+	assert.ErrorContains(t, err, `unknown session mode "memory"`)
+}
+
+func TestNewSessionServiceUnknownBinding(t *testing.T) {
+	sessionConfiguration := configurationmocks.NewSessionConfiguration(t)
+	sessionConfiguration.On("GetSigningKeys").Return([]configuration.SessionSigningKey{})
+	sessionConfiguration.On("GetBinding").Return("browser")
+	_, err := NewSessionService(zap.L(), repositorymocks.NewCRUDRepository[models.Session, uuid.UUID](t),
+		sessionConfiguration, newCookieConfigurationMock(t))
+	assert.ErrorContains(t, err, `unknown session binding "browser"`)
+}
+
+func TestAuthenticateDatabaseModeInvalidToken(t *testing.T) {
+	_, service, _, _ := setupTest(t)
+	_, herr := service.Authenticate("not.a.token")
+	assert.Equal(t, HERRNotAuthenticated, herr)
+}
+
+func TestJWTLogUserInAndAuthenticate(t *testing.T) {
+	sessionRepositoryMock, service, sessionConfiguration := setupJWTTest(t)
+	sessionConfiguration.On("GetBinding").Return(configuration.SessionBindingNone)
+	var session *models.Session
+	sessionRepositoryMock.On("Create", mock.Anything).Run(func(args mock.Arguments) {
+		session = args.Get(0).(*models.Session)
+		session.ID = uuid.New()
+	}).Return(nil)
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}}
+	response := httptest.NewRecorder()
+
+	herr := service.LogUserIn(user, httptest.NewRequest("POST", "/login", nil), response)
+	require.Nil(t, herr)
+	cookies := getCookies(response)
+	require.Contains(t, cookies, "access_token")
+	require.Contains(t, cookies, RefreshTokenCookieName)
+	assert.Equal(t, RefreshTokenCookiePath, cookies[RefreshTokenCookieName].Path)
+	assert.Equal(t, hashToken(cookies[RefreshTokenCookieName].Value), session.TokenHash)
+
+	sessionClaims, herr := service.Authenticate(cookies["access_token"].Value)
+	require.Nil(t, herr)
+	assert.Equal(t, &SessionClaims{UserID: user.ID, SessionUUID: session.ID}, sessionClaims)
+}
+
+func TestJWTTrack(t *testing.T) {
+	sessionRepositoryMock, service, _ := setupJWTTest(t)
+	sessionClaims := &SessionClaims{UserID: uuid.New(), SessionUUID: uuid.New()}
+	sessionRepositoryMock.On(
+		"UpdateColumns",
+		squirrel.Eq{"id": sessionClaims.SessionUUID.String(), "deleted_at": nil},
+		mock.MatchedBy(func(columns map[string]any) bool { return len(columns) == 1 && columns["last_seen_at"] != nil }),
+	).Return(uint(1), nil).Once()
+
+	require.Nil(t, service.Track(sessionClaims, httptest.NewRequest("GET", "/me", nil), httptest.NewRecorder()))
+	// the next requests of the minute are not written
+	require.Nil(t, service.Track(sessionClaims, httptest.NewRequest("GET", "/me", nil), httptest.NewRecorder()))
+}
+
+func TestJWTBoundSession(t *testing.T) {
+	sessionRepositoryMock, service, sessionConfiguration := setupJWTTest(t)
+	sessionConfiguration.On("GetBinding").Return(configuration.SessionBindingDevice)
+	sessionRepositoryMock.On("Create", mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(0).(*models.Session).ID = uuid.New()
+	}).Return(nil)
+	sessionRepositoryMock.On("UpdateColumns", mock.Anything, mock.Anything).Return(uint(1), nil).Maybe()
+	loginRequest := httptest.NewRequest("POST", "/login", nil)
+	loginRequest.Header.Set("User-Agent", firefoxOnLinux)
+	response := httptest.NewRecorder()
+	require.Nil(t, service.LogUserIn(&models.User{BaseModel: models.BaseModel{ID: uuid.New()}}, loginRequest, response))
+
+	sessionClaims, herr := service.Authenticate(getCookies(response)["access_token"].Value)
+	require.Nil(t, herr)
+	request := httptest.NewRequest("GET", "/me", nil)
+	request.Header.Set("User-Agent", firefoxOnLinux)
+	assert.Nil(t, service.Track(sessionClaims, request, httptest.NewRecorder()))
+	request.Header.Set("User-Agent", "curl/8.0.1")
+	assert.Equal(t, HERRNotAuthenticated, service.Track(sessionClaims, request, httptest.NewRecorder()))
+}
+
+func TestJWTAuthenticateRefusesOtherKeys(t *testing.T) {
+	_, service, _ := setupJWTTest(t)
+	_, otherService, _ := setupJWTTest(t)
+	accessToken, err := otherService.keySet.Sign(jwt.Claims{
+		Subject:   uuid.NewString(),
+		SessionID: uuid.NewString(),
+		IssuedAt:  time.Now(),
+		ExpiresAt: time.Now().Add(time.Minute),
+	})
+	require.NoError(t, err)
+
+	_, herr := service.Authenticate(accessToken)
+	assert.Equal(t, HERRNotAuthenticated, herr)
+}
+
+func TestJWTRefreshRotatesTheRefreshToken(t *testing.T) {
+	sessionRepositoryMock, service, _ := setupJWTTest(t)
+	session := &models.Session{
+		BaseModel: models.BaseModel{ID: uuid.New()},
+		UserID:    uuid.New(),
+		ExpiresAt: time.Now().Add(time.Minute),
+		TokenHash: hashToken("refresh"),
+	}
+	sessionRepositoryMock.On("Find", squirrel.Eq{"token_hash": hashToken("refresh")}, mock.Anything, mock.Anything).
+		Return(pagination.NewPage([]*models.Session{session}, 1, 10, 1), nil)
+	sessionRepositoryMock.On(
+		"UpdateColumns",
+		squirrel.Eq{"id": session.ID.String(), "token_hash": hashToken("refresh"), "deleted_at": nil},
+		mock.Anything,
+	).Return(uint(1), nil)
+	response := httptest.NewRecorder()
+
+	herr := service.Refresh("refresh", response)
+	require.Nil(t, herr)
+	cookies := getCookies(response)
+	assert.NotEqual(t, "refresh", cookies[RefreshTokenCookieName].Value)
+	assert.Equal(t, hashToken(cookies[RefreshTokenCookieName].Value), session.TokenHash)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), session.ExpiresAt, time.Minute)
+	sessionClaims, herr := service.Authenticate(cookies["access_token"].Value)
+	require.Nil(t, herr)
+	assert.Equal(t, session.ID, sessionClaims.SessionUUID)
+}
+
+func TestJWTRefreshConcurrentlyUsedToken(t *testing.T) {
+	sessionRepositoryMock, service, _ := setupJWTTest(t)
+	session := &models.Session{
+		BaseModel: models.BaseModel{ID: uuid.New()},
+		ExpiresAt: time.Now().Add(time.Minute),
+		TokenHash: hashToken("refresh"),
+	}
+	sessionRepositoryMock.On("Find", mock.Anything, mock.Anything, mock.Anything).
+		Return(pagination.NewPage([]*models.Session{session}, 1, 10, 1), nil)
+	// the refresh token was rotated by another request since it was read
+	sessionRepositoryMock.On("UpdateColumns", mock.Anything, mock.Anything).Return(uint(0), nil)
+	response := httptest.NewRecorder()
+
+	herr := service.Refresh("refresh", response)
+	assert.Equal(t, HERRNotAuthenticated, herr)
+	assert.Empty(t, getCookies(response))
+}
+
+func TestJWTRefreshUnknownToken(t *testing.T) {
+	sessionRepositoryMock, service, _ := setupJWTTest(t)
+	sessionRepositoryMock.On("Find", mock.Anything, mock.Anything, mock.Anything).
+		Return(pagination.NewPage([]*models.Session{}, 1, 10, 0), nil)
+
+	herr := service.Refresh("refresh", httptest.NewRecorder())
+	assert.Equal(t, HERRNotAuthenticated, herr)
+}
+
+func TestJWTRefreshExpiredSession(t *testing.T) {
+	sessionRepositoryMock, service, _ := setupJWTTest(t)
+	session := &models.Session{ExpiresAt: time.Now().Add(-time.Minute), TokenHash: hashToken("refresh")}
+	sessionRepositoryMock.On("Find", mock.Anything, mock.Anything, mock.Anything).
+		Return(pagination.NewPage([]*models.Session{session}, 1, 10, 1), nil)
+
+	herr := service.Refresh("refresh", httptest.NewRecorder())
+	assert.Equal(t, HERRSessionExpired, herr)
+}
+
+func TestJWTRotateSession(t *testing.T) {
+	sessionRepositoryMock, service, _ := setupJWTTest(t)
+	session := &models.Session{
+		BaseModel: models.BaseModel{ID: uuid.New()},
+		UserID:    uuid.New(),
+		TokenHash: hashToken("refresh"),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+	sessionRepositoryMock.On("GetByID", session.ID).Return(session, nil)
+	sessionRepositoryMock.On("UpdateColumns", squirrel.Eq{"id": session.ID.String(), "deleted_at": nil}, mock.Anything).
+		Return(uint(1), nil)
+	response := httptest.NewRecorder()
+
+	herr := service.RotateSession(makeSessionClaims(session), response)
+	require.Nil(t, herr)
+	cookies := getCookies(response)
+	require.Contains(t, cookies, RefreshTokenCookieName)
+	assert.Equal(t, hashToken(cookies[RefreshTokenCookieName].Value), session.TokenHash)
+	sessionClaims, herr := service.Authenticate(cookies["access_token"].Value)
+	require.Nil(t, herr)
+	assert.Equal(t, session.ID, sessionClaims.SessionUUID)
+}
+
+func TestJWTLogUserOut(t *testing.T) {
+	sessionRepositoryMock, service, _ := setupJWTTest(t)
+	session := &models.Session{BaseModel: models.BaseModel{ID: uuid.New()}}
+	sessionRepositoryMock.On("GetByID", session.ID).Return(session, nil)
+	sessionRepositoryMock.On("Delete", session).Return(nil)
+	response := httptest.NewRecorder()
+
+	herr := service.LogUserOut(&SessionClaims{SessionUUID: session.ID}, response)
+	require.Nil(t, herr)
+	assert.Equal(t, "", getCookies(response)[RefreshTokenCookieName].Value)
+}
+
+func TestJWTGetJWKS(t *testing.T) {
+	_, service, _ := setupJWTTest(t)
+	jwks := service.GetJWKS()
+	require.Len(t, jwks.Keys, 1)
+	assert.Equal(t, "ES256", jwks.Keys[0].Algorithm)
+	assert.True(t, jwks.Keys[0].IsPublic())
+}
+
+func TestLoadKeySetFromFiles(t *testing.T) {
+	_, service, _, sessionConfiguration := setupTest(t)
+	keyPaths := []string{}
+	for _, keyID := range []string{"new", "old"} {
+		privateKey, err := jwt.GenerateKey("EdDSA")
+		require.NoError(t, err)
+		der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+		require.NoError(t, err)
+		keyPath := filepath.Join(t.TempDir(), keyID+".pem")
+		require.NoError(t, os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600))
+		keyPaths = append(keyPaths, keyPath)
+	}
+	sessionConfiguration.On("GetJWTAlgorithm").Return("EdDSA")
+	sessionConfiguration.On("GetJWTKeys").Return([]configuration.SessionJWTKey{
+		{ID: "new", PrivateKey: keyPaths[0]},
+		{ID: "old", PrivateKey: keyPaths[1]},
+	})
+
+	keySet, err := loadKeySet(service)
+	require.NoError(t, err)
+	jwks := keySet.JWKS()
+	require.Len(t, jwks.Keys, 2)
+	assert.Equal(t, "new", jwks.Keys[0].KeyID)
+	assert.Equal(t, "old", jwks.Keys[1].KeyID)
+}
+
+func TestLoadKeySetMissingFile(t *testing.T) {
+	_, service, _, sessionConfiguration := setupTest(t)
+	sessionConfiguration.On("GetJWTAlgorithm").Return("ES256")
+	sessionConfiguration.On("GetJWTKeys").Return([]configuration.SessionJWTKey{
+		{ID: "missing", PrivateKey: filepath.Join(t.TempDir(), "missing.pem")},
+	})
+
+	_, err := loadKeySet(service)
+	assert.ErrorContains(t, err, `failed to read the jwt key "missing"`)
+}
+
+// make values for the jwt mode tests
+func setupJWTTest(
+	t *testing.T,
+) (
+	*repositorymocks.CRUDRepository[models.Session, uuid.UUID],
+	*jwtSessionService,
+	*configurationmocks.SessionConfiguration,
+) {
+	sessionRepositoryMock, service, _, sessionConfiguration := setupTest(t)
+	sessionConfiguration.On("GetMode").Return(configuration.SessionModeJWT).Maybe()
+	sessionConfiguration.On("GetSessionDuration").Return(time.Hour).Maybe()
+	sessionConfiguration.On("GetJWTAccessTokenDuration").Return(5 * time.Minute).Maybe()
+	privateKey, err := jwt.GenerateKey("ES256")
+	require.NoError(t, err)
+	keySet, err := jwt.NewKeySet("ES256", []jwt.Key{{ID: "test", PrivateKey: privateKey}})
+	require.NoError(t, err)
+	return sessionRepositoryMock, &jwtSessionService{
+		sessionServiceImpl: service,
+		keySet:             keySet,
+		lastSeen:           make(map[uuid.UUID]time.Time),
+	}, sessionConfiguration
+}
+
+// Return the cookies set in the response by name
+func getCookies(response *httptest.ResponseRecorder) map[string]*http.Cookie {
+	cookies := map[string]*http.Cookie{}
+	for _, cookie := range response.Result().Cookies() {
+		cookies[cookie.Name] = cookie
+	}
+	return cookies
+}
@@ -0,0 +1,104 @@
+package sessionservice
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ditrit/badaas/configuration"
+)
+
+// The minimal size of the secrets signing the session tokens
+const minSigningKeySize = 32
+
+// Issue the opaque session tokens and return the hash stored for them
+//
+// Without keys the tokens are random strings, with keys they are signed with the first key
+// as "<random>.<key id>.<hmac>" and the tokens that are not signed by one of the keys are refused
+// before reading the database.
+type tokenSigner struct {
+	keys         map[string][]byte
+	signingKeyID string
+}
+
+// Create a tokenSigner from the configured keys
+func newTokenSigner(configuredKeys []configuration.SessionSigningKey) (tokenSigner, error) {
+	signer := tokenSigner{keys: make(map[string][]byte, len(configuredKeys))}
+	for _, configuredKey := range configuredKeys {
+		if configuredKey.ID == "" || strings.Contains(configuredKey.ID, ".") {
+			return tokenSigner{}, fmt.Errorf("invalid session signing key id %q", configuredKey.ID)
+		}
+		if _, ok := signer.keys[configuredKey.ID]; ok {
+			return tokenSigner{}, fmt.Errorf("duplicated session signing key id %q", configuredKey.ID)
+		}
+		if len(configuredKey.Secret) < minSigningKeySize {
+			return tokenSigner{}, fmt.Errorf("the session signing key %q must be at least %d bytes long",
+				configuredKey.ID, minSigningKeySize)
+		}
+		signer.keys[configuredKey.ID] = []byte(configuredKey.Secret)
+	}
+	if len(configuredKeys) > 0 {
+		signer.signingKeyID = configuredKeys[0].ID
+	}
+	return signer, nil
+}
+
+// Generate a new token, return it with its hash
+func (signer tokenSigner) generate() (string, string, error) {
+	secret, err := generateToken()
+	if err != nil {
+		return "", "", err
+	}
+	if signer.signingKeyID == "" {
+		return secret, hashToken(secret), nil
+	}
+	token := secret + "." + signer.signingKeyID + "." + signer.sign(signer.signingKeyID, secret)
+	return token, hashToken(secret), nil
+}
+
+// Return the hash of a token, false if its signature is invalid
+func (signer tokenSigner) hash(token string) (string, bool) {
+	if signer.signingKeyID == "" {
+		if token == "" || strings.Contains(token, ".") {
+			return "", false
+		}
+		return hashToken(token), true
+	}
+	secret, keyID, signature, err := splitToken(token)
+	if err != nil {
+		return "", false
+	}
+	if _, ok := signer.keys[keyID]; !ok {
+		return "", false
+	}
+	if !hmac.Equal([]byte(signature), []byte(signer.sign(keyID, secret))) {
+		return "", false
+	}
+	return hashToken(secret), true
+}
+
+// Return the HMAC of the secret of a token with a key
+func (signer tokenSigner) sign(keyID, secret string) string {
+	mac := hmac.New(sha256.New, signer.keys[keyID])
+	mac.Write([]byte(keyID + "." + secret))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// Split a signed token into its secret, its key id and its signature
+func splitToken(token string) (string, string, string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] == "" {
+		return "", "", "", errors.New("malformed session token")
+	}
+	return parts[0], parts[1], parts[2], nil
+}
+
+// Hash a token, only the hash of the session tokens are stored
+func hashToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
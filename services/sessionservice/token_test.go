@@ -0,0 +1,90 @@
+package sessionservice
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ditrit/badaas/configuration"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSigningSecret = "a secret of at least thirty two bytes"
+
+func TestTokenSignerUnsigned(t *testing.T) {
+	signer, err := newTokenSigner(nil)
+	require.NoError(t, err)
+	token, tokenHash, err := signer.generate()
+	require.NoError(t, err)
+	assert.NotContains(t, token, ".")
+
+	hash, ok := signer.hash(token)
+	assert.True(t, ok)
+	assert.Equal(t, tokenHash, hash)
+	for _, invalidToken := range []string{"", "a.b.c"} {
+		_, ok = signer.hash(invalidToken)
+		assert.False(t, ok, invalidToken)
+	}
+}
+
+func TestTokenSignerSigned(t *testing.T) {
+	signer, err := newTokenSigner([]configuration.SessionSigningKey{{ID: "2024", Secret: testSigningSecret}})
+	require.NoError(t, err)
+	token, tokenHash, err := signer.generate()
+	require.NoError(t, err)
+	parts := strings.Split(token, ".")
+	require.Len(t, parts, 3)
+	assert.Equal(t, "2024", parts[1])
+
+	hash, ok := signer.hash(token)
+	assert.True(t, ok)
+	assert.Equal(t, tokenHash, hash)
+	assert.Equal(t, hashToken(parts[0]), hash)
+	for _, invalidToken := range []string{
+		parts[0],
+		parts[0] + ".2024." + parts[0],
+		"x" + token,
+		parts[0] + ".2023." + parts[2],
+	} {
+		_, ok = signer.hash(invalidToken)
+		assert.False(t, ok, invalidToken)
+	}
+}
+
+func TestTokenSignerRotatedKeys(t *testing.T) {
+	oldSigner, err := newTokenSigner([]configuration.SessionSigningKey{{ID: "2023", Secret: testSigningSecret}})
+	require.NoError(t, err)
+	token, tokenHash, err := oldSigner.generate()
+	require.NoError(t, err)
+	signer, err := newTokenSigner([]configuration.SessionSigningKey{
+		{ID: "2024", Secret: "another secret of thirty two bytes"},
+		{ID: "2023", Secret: testSigningSecret},
+	})
+	require.NoError(t, err)
+
+	hash, ok := signer.hash(token)
+	assert.True(t, ok)
+	assert.Equal(t, tokenHash, hash)
+	newToken, _, err := signer.generate()
+	require.NoError(t, err)
+	assert.Equal(t, "2024", strings.Split(newToken, ".")[1])
+}
+
+func TestNewTokenSignerInvalidKeys(t *testing.T) {
+	tests := []struct {
+		keys []configuration.SessionSigningKey
+		err  string
+	}{
+		{[]configuration.SessionSigningKey{{ID: "", Secret: testSigningSecret}}, `invalid session signing key id ""`},
+		{[]configuration.SessionSigningKey{{ID: "a.b", Secret: testSigningSecret}}, `invalid session signing key id "a.b"`},
+		{[]configuration.SessionSigningKey{{ID: "a", Secret: "short"}}, `the session signing key "a" must be at least 32 bytes long`},
+		{
+			[]configuration.SessionSigningKey{{ID: "a", Secret: testSigningSecret}, {ID: "a", Secret: testSigningSecret}},
+			`duplicated session signing key id "a"`,
+		},
+	}
+	for _, test := range tests {
+		_, err := newTokenSigner(test.keys)
+		assert.EqualError(t, err, test.err)
+	}
+}
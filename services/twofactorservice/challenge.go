@@ -0,0 +1,136 @@
+package twofactorservice
+
+import (
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/ditrit/badaas/httperrors"
+	"github.com/ditrit/badaas/persistence/models"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Errors
+var (
+	HERRInvalidChallenge = httperrors.NewUnauthorizedError("invalid challenge",
+		"the login challenge is invalid or expired, please log in again")
+	HERRInvalidCode = httperrors.NewUnauthorizedError("invalid code", "the code is incorrect or was already used")
+)
+
+// Create a challenge for a user whose password has been checked, and return its token
+//
+// An enrolment challenge is created for a user who has to enable a second factor to log in.
+func (twoFactorService *twoFactorServiceImpl) CreateChallenge(userID uuid.UUID, enrolment bool) (string, httperrors.HTTPError) {
+	token, err := generateToken()
+	if err != nil {
+		return "", httperrors.NewInternalServerError("token error", "failed to generate a token", err)
+	}
+	herr := twoFactorService.loginChallengeRepository.Create(&models.LoginChallenge{
+		UserID:    userID,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(twoFactorService.twoFactorConfiguration.GetChallengeDuration()),
+		Enrolment: enrolment,
+	})
+	if herr != nil {
+		return "", herr
+	}
+	return token, nil
+}
+
+// Return the user of a valid challenge
+func (twoFactorService *twoFactorServiceImpl) GetChallengeUserID(token string) (uuid.UUID, httperrors.HTTPError) {
+	challenge, herr := twoFactorService.getChallenge(token)
+	if herr != nil {
+		return uuid.Nil, herr
+	}
+	return challenge.UserID, nil
+}
+
+// Return the user of a valid enrolment challenge
+//
+// The challenges of the users who already have a second factor can't be used to enrol a new one.
+func (twoFactorService *twoFactorServiceImpl) GetEnrolmentChallengeUserID(token string) (uuid.UUID, httperrors.HTTPError) {
+	challenge, herr := twoFactorService.getChallenge(token)
+	if herr != nil {
+		return uuid.Nil, herr
+	}
+	if !challenge.Enrolment {
+		return uuid.Nil, HERRInvalidChallenge
+	}
+	return challenge.UserID, nil
+}
+
+// Check the code of the user of the challenge, the challenge can't be used again once successful
+//
+// The challenge is deleted after too many wrong codes.
+func (twoFactorService *twoFactorServiceImpl) VerifyChallenge(token, code string) (uuid.UUID, httperrors.HTTPError) {
+	challenge, herr := twoFactorService.getChallenge(token)
+	if herr != nil {
+		return uuid.Nil, herr
+	}
+	if challenge.Enrolment {
+		return uuid.Nil, HERRInvalidChallenge
+	}
+	device, herr := twoFactorService.getDevice(challenge.UserID)
+	if herr != nil {
+		return uuid.Nil, herr
+	}
+	if device == nil || !device.Confirmed {
+		return uuid.Nil, HERRInvalidChallenge
+	}
+	valid, herr := twoFactorService.checkCode(device, code)
+	if herr != nil {
+		return uuid.Nil, herr
+	}
+	if !valid {
+		challenge.FailedAttempts++
+		if challenge.FailedAttempts >= twoFactorService.twoFactorConfiguration.GetChallengeAttempts() {
+			twoFactorService.logger.Warn("Deleted a login challenge after too many wrong codes",
+				zap.String("userID", challenge.UserID.String()))
+			herr = twoFactorService.loginChallengeRepository.Delete(challenge)
+		} else {
+			herr = twoFactorService.loginChallengeRepository.Save(challenge)
+		}
+		if herr != nil {
+			return uuid.Nil, herr
+		}
+		return uuid.Nil, HERRInvalidCode
+	}
+	herr = twoFactorService.loginChallengeRepository.Delete(challenge)
+	if herr != nil {
+		return uuid.Nil, herr
+	}
+	return challenge.UserID, nil
+}
+
+// Delete a challenge
+func (twoFactorService *twoFactorServiceImpl) DeleteChallenge(token string) httperrors.HTTPError {
+	challenge, herr := twoFactorService.getChallenge(token)
+	if herr != nil {
+		return herr
+	}
+	return twoFactorService.loginChallengeRepository.Delete(challenge)
+}
+
+// Return the challenge of the token, or an error if it doesn't exist or is expired
+//
+// An expired challenge is deleted.
+func (twoFactorService *twoFactorServiceImpl) getChallenge(token string) (*models.LoginChallenge, httperrors.HTTPError) {
+	challenges, herr := twoFactorService.loginChallengeRepository.Find(
+		squirrel.Eq{"token_hash": hashToken(token)}, nil, nil)
+	if herr != nil {
+		return nil, herr
+	}
+	if !challenges.HasContent {
+		return nil, HERRInvalidChallenge
+	}
+	challenge := challenges.Ressources[0]
+	if challenge.IsExpired() {
+		herr = twoFactorService.loginChallengeRepository.Delete(challenge)
+		if herr != nil {
+			return nil, herr
+		}
+		return nil, HERRInvalidChallenge
+	}
+	return challenge, nil
+}
@@ -0,0 +1,49 @@
+package twofactorservice
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+)
+
+// The number of random bytes in a challenge token
+const tokenSize = 32
+
+// The number of random bytes in a recovery code (80 bits, 16 base32 characters)
+const recoveryCodeSize = 10
+
+// Generate a random challenge token
+func generateToken() (string, error) {
+	b := make([]byte, tokenSize)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Hash a token, only the hash of the tokens are stored
+func hashToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+// Generate a random recovery code, formatted as "xxxx-xxxx-xxxx-xxxx" to be easily copied
+func generateRecoveryCode() (string, error) {
+	b := make([]byte, recoveryCodeSize)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	encoded := strings.ToLower(base32.StdEncoding.EncodeToString(b))
+	return encoded[0:4] + "-" + encoded[4:8] + "-" + encoded[8:12] + "-" + encoded[12:16], nil
+}
+
+// Hash a recovery code, the case and the dashes are ignored
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	return hashToken(normalized)
+}
@@ -0,0 +1,357 @@
+package twofactorservice
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/ditrit/badaas/configuration"
+	"github.com/ditrit/badaas/httperrors"
+	"github.com/ditrit/badaas/persistence/models"
+	"github.com/ditrit/badaas/persistence/repository"
+	"github.com/ditrit/badaas/services/auth/protocols/totp"
+	"github.com/ditrit/badaas/services/rbacservice"
+	"github.com/ditrit/badaas/services/userservice"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Errors
+var (
+	HERRTwoFactorAlreadyEnabled = httperrors.NewHTTPError(http.StatusConflict, "two-factor already enabled",
+		"the two-factor authentication is already enabled", nil, false)
+	HERRTwoFactorNotEnabled = httperrors.NewHTTPError(http.StatusBadRequest, "two-factor not enabled",
+		"the two-factor authentication is not enabled", nil, false)
+	HERRNoPendingEnrolment = httperrors.NewHTTPError(http.StatusBadRequest, "no pending enrolment",
+		"no authenticator is waiting for a confirmation", nil, false)
+	HERRTwoFactorRequired = httperrors.NewForbiddenError("two-factor required",
+		"the roles of the user require the two-factor authentication")
+	HERRWrongCurrentCode = httperrors.NewForbiddenError("invalid code", "the code is incorrect or was already used")
+)
+
+// The result of the enrolment of a TOTP authenticator
+type TOTPEnrolment struct {
+	// The base32 secret, for the users who can't scan the QR code
+	Secret string
+	// The otpauth:// uri to display as a QR code
+	URI string
+}
+
+// TwoFactorService handle the TOTP authenticators, the recovery codes and the second step of the login
+type TwoFactorService interface {
+	// Return true if the user has a confirmed authenticator
+	IsEnabled(userID uuid.UUID) (bool, httperrors.HTTPError)
+	// Return true if one of the roles of the user requires a second factor
+	IsRequired(userID uuid.UUID) (bool, httperrors.HTTPError)
+	// Generate a new secret for the user, it is used once confirmed with a code
+	BeginEnrolment(userID uuid.UUID) (*TOTPEnrolment, httperrors.HTTPError)
+	// Confirm the pending authenticator of the user with a code and return new recovery codes
+	ConfirmEnrolment(userID uuid.UUID, code string) ([]string, httperrors.HTTPError)
+	// Remove the authenticator and the recovery codes of the user, a TOTP or recovery code is required
+	Disable(userID uuid.UUID, code string) httperrors.HTTPError
+	// Replace the recovery codes of the user, a TOTP or recovery code is required
+	RegenerateRecoveryCodes(userID uuid.UUID, code string) ([]string, httperrors.HTTPError)
+	// Remove the authenticator and the recovery codes of the user without code, for the administrators
+	Reset(userID uuid.UUID) httperrors.HTTPError
+
+	// Create a challenge for a user whose password has been checked, and return its token
+	//
+	// An enrolment challenge is created for a user who has to enable a second factor to log in.
+	CreateChallenge(userID uuid.UUID, enrolment bool) (string, httperrors.HTTPError)
+	// Return the user of a valid challenge
+	GetChallengeUserID(token string) (uuid.UUID, httperrors.HTTPError)
+	// Return the user of a valid enrolment challenge
+	GetEnrolmentChallengeUserID(token string) (uuid.UUID, httperrors.HTTPError)
+	// Check the code of the user of the challenge, the challenge can't be used again once successful
+	VerifyChallenge(token, code string) (uuid.UUID, httperrors.HTTPError)
+	// Delete a challenge
+	DeleteChallenge(token string) httperrors.HTTPError
+}
+
+// Check interface compliance
+var _ TwoFactorService = (*twoFactorServiceImpl)(nil)
+
+// TwoFactorService implementation
+type twoFactorServiceImpl struct {
+	logger                   *zap.Logger
+	twoFactorConfiguration   configuration.TwoFactorConfiguration
+	totpDeviceRepository     repository.CRUDRepository[models.TOTPDevice, uuid.UUID]
+	recoveryCodeRepository   repository.CRUDRepository[models.RecoveryCode, uuid.UUID]
+	loginChallengeRepository repository.CRUDRepository[models.LoginChallenge, uuid.UUID]
+	userService              userservice.UserService
+	rbacService              rbacservice.RBACService
+}
+
+// TwoFactorService constructor
+func NewTwoFactorService(
+	logger *zap.Logger,
+	twoFactorConfiguration configuration.TwoFactorConfiguration,
+	totpDeviceRepository repository.CRUDRepository[models.TOTPDevice, uuid.UUID],
+	recoveryCodeRepository repository.CRUDRepository[models.RecoveryCode, uuid.UUID],
+	loginChallengeRepository repository.CRUDRepository[models.LoginChallenge, uuid.UUID],
+	userService userservice.UserService,
+	rbacService rbacservice.RBACService,
+) TwoFactorService {
+	return &twoFactorServiceImpl{
+		logger:                   logger,
+		twoFactorConfiguration:   twoFactorConfiguration,
+		totpDeviceRepository:     totpDeviceRepository,
+		recoveryCodeRepository:   recoveryCodeRepository,
+		loginChallengeRepository: loginChallengeRepository,
+		userService:              userService,
+		rbacService:              rbacService,
+	}
+}
+
+// Return true if the user has a confirmed authenticator
+func (twoFactorService *twoFactorServiceImpl) IsEnabled(userID uuid.UUID) (bool, httperrors.HTTPError) {
+	device, herr := twoFactorService.getDevice(userID)
+	if herr != nil {
+		return false, herr
+	}
+	return device != nil && device.Confirmed, nil
+}
+
+// Return true if one of the roles of the user requires a second factor
+func (twoFactorService *twoFactorServiceImpl) IsRequired(userID uuid.UUID) (bool, httperrors.HTTPError) {
+	requiredRoles := twoFactorService.twoFactorConfiguration.GetRequiredRoles()
+	if len(requiredRoles) == 0 {
+		return false, nil
+	}
+	roles, herr := twoFactorService.rbacService.GetUserRoles(userID)
+	if herr != nil {
+		return false, herr
+	}
+	for _, role := range roles {
+		for _, requiredRole := range requiredRoles {
+			if role.Name == requiredRole {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// Generate a new secret for the user, it is used once confirmed with a code
+//
+// A previous unconfirmed secret is replaced.
+func (twoFactorService *twoFactorServiceImpl) BeginEnrolment(userID uuid.UUID) (*TOTPEnrolment, httperrors.HTTPError) {
+	device, herr := twoFactorService.getDevice(userID)
+	if herr != nil {
+		return nil, herr
+	}
+	if device != nil && device.Confirmed {
+		return nil, HERRTwoFactorAlreadyEnabled
+	}
+	user, herr := twoFactorService.userService.GetUserByID(userID)
+	if herr != nil {
+		return nil, herr
+	}
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, httperrors.NewInternalServerError("totp error", "failed to generate a secret", err)
+	}
+	if device == nil {
+		herr = twoFactorService.totpDeviceRepository.Create(&models.TOTPDevice{
+			UserID: userID,
+			Secret: secret,
+		})
+	} else {
+		device.Secret = secret
+		device.LastUsedStep = 0
+		herr = twoFactorService.totpDeviceRepository.Save(device)
+	}
+	if herr != nil {
+		return nil, herr
+	}
+	return &TOTPEnrolment{
+		Secret: secret,
+		URI:    totp.ProvisioningURI(twoFactorService.twoFactorConfiguration.GetIssuer(), user.Email, secret),
+	}, nil
+}
+
+// Confirm the pending authenticator of the user with a code and return new recovery codes
+func (twoFactorService *twoFactorServiceImpl) ConfirmEnrolment(userID uuid.UUID, code string) ([]string, httperrors.HTTPError) {
+	device, herr := twoFactorService.getDevice(userID)
+	if herr != nil {
+		return nil, herr
+	}
+	if device == nil || device.Confirmed {
+		return nil, HERRNoPendingEnrolment
+	}
+	step, ok := totp.Validate(device.Secret, strings.TrimSpace(code), time.Now(), device.LastUsedStep)
+	if !ok {
+		return nil, HERRWrongCurrentCode
+	}
+	device.Confirmed = true
+	device.LastUsedStep = step
+	herr = twoFactorService.totpDeviceRepository.Save(device)
+	if herr != nil {
+		return nil, herr
+	}
+	twoFactorService.logger.Info("Enabled the two-factor authentication", zap.String("userID", userID.String()))
+	return twoFactorService.replaceRecoveryCodes(userID)
+}
+
+// Remove the authenticator and the recovery codes of the user, a TOTP or recovery code is required
+//
+// The users whose roles require a second factor can't disable it.
+func (twoFactorService *twoFactorServiceImpl) Disable(userID uuid.UUID, code string) httperrors.HTTPError {
+	required, herr := twoFactorService.IsRequired(userID)
+	if herr != nil {
+		return herr
+	}
+	if required {
+		return HERRTwoFactorRequired
+	}
+	herr = twoFactorService.checkCurrentCode(userID, code)
+	if herr != nil {
+		return herr
+	}
+	return twoFactorService.Reset(userID)
+}
+
+// Replace the recovery codes of the user, a TOTP or recovery code is required
+func (twoFactorService *twoFactorServiceImpl) RegenerateRecoveryCodes(userID uuid.UUID, code string) ([]string, httperrors.HTTPError) {
+	herr := twoFactorService.checkCurrentCode(userID, code)
+	if herr != nil {
+		return nil, herr
+	}
+	return twoFactorService.replaceRecoveryCodes(userID)
+}
+
+// Remove the authenticator and the recovery codes of the user without code, for the administrators
+func (twoFactorService *twoFactorServiceImpl) Reset(userID uuid.UUID) httperrors.HTTPError {
+	device, herr := twoFactorService.getDevice(userID)
+	if herr != nil {
+		return herr
+	}
+	if device != nil {
+		herr = twoFactorService.totpDeviceRepository.Delete(device)
+		if herr != nil {
+			return herr
+		}
+	}
+	herr = twoFactorService.deleteRecoveryCodes(userID)
+	if herr != nil {
+		return herr
+	}
+	twoFactorService.logger.Info("Disabled the two-factor authentication", zap.String("userID", userID.String()))
+	return nil
+}
+
+// Return an error if the user has no confirmed authenticator or if the code is not valid
+func (twoFactorService *twoFactorServiceImpl) checkCurrentCode(userID uuid.UUID, code string) httperrors.HTTPError {
+	device, herr := twoFactorService.getDevice(userID)
+	if herr != nil {
+		return herr
+	}
+	if device == nil || !device.Confirmed {
+		return HERRTwoFactorNotEnabled
+	}
+	valid, herr := twoFactorService.checkCode(device, code)
+	if herr != nil {
+		return herr
+	}
+	if !valid {
+		return HERRWrongCurrentCode
+	}
+	return nil
+}
+
+// Check a TOTP code or a recovery code of the user of the device
+//
+// The TOTP code can't be replayed and the recovery code can't be used again.
+func (twoFactorService *twoFactorServiceImpl) checkCode(device *models.TOTPDevice, code string) (bool, httperrors.HTTPError) {
+	code = strings.TrimSpace(code)
+	if isTOTPCode(code) {
+		step, ok := totp.Validate(device.Secret, code, time.Now(), device.LastUsedStep)
+		if !ok {
+			return false, nil
+		}
+		device.LastUsedStep = step
+		return true, twoFactorService.totpDeviceRepository.Save(device)
+	}
+	recoveryCodes, herr := twoFactorService.recoveryCodeRepository.Find(squirrel.Eq{
+		"user_id":   device.UserID.String(),
+		"code_hash": hashRecoveryCode(code),
+	}, nil, nil)
+	if herr != nil {
+		return false, herr
+	}
+	if !recoveryCodes.HasContent {
+		return false, nil
+	}
+	herr = twoFactorService.recoveryCodeRepository.Delete(recoveryCodes.Ressources[0])
+	if herr != nil {
+		return false, herr
+	}
+	twoFactorService.logger.Info("Used a recovery code", zap.String("userID", device.UserID.String()))
+	return true, nil
+}
+
+// Replace the recovery codes of the user by new ones and return them
+func (twoFactorService *twoFactorServiceImpl) replaceRecoveryCodes(userID uuid.UUID) ([]string, httperrors.HTTPError) {
+	herr := twoFactorService.deleteRecoveryCodes(userID)
+	if herr != nil {
+		return nil, herr
+	}
+	codes := make([]string, 0, twoFactorService.twoFactorConfiguration.GetRecoveryCodes())
+	for i := uint(0); i < twoFactorService.twoFactorConfiguration.GetRecoveryCodes(); i++ {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return nil, httperrors.NewInternalServerError("token error", "failed to generate a recovery code", err)
+		}
+		herr = twoFactorService.recoveryCodeRepository.Create(&models.RecoveryCode{
+			UserID:   userID,
+			CodeHash: hashRecoveryCode(code),
+		})
+		if herr != nil {
+			return nil, herr
+		}
+		codes = append(codes, code)
+	}
+	return codes, nil
+}
+
+// Delete the recovery codes of the user
+func (twoFactorService *twoFactorServiceImpl) deleteRecoveryCodes(userID uuid.UUID) httperrors.HTTPError {
+	recoveryCodes, herr := twoFactorService.recoveryCodeRepository.Find(
+		squirrel.Eq{"user_id": userID.String()}, nil, nil)
+	if herr != nil {
+		return herr
+	}
+	for _, recoveryCode := range recoveryCodes.Ressources {
+		herr = twoFactorService.recoveryCodeRepository.Delete(recoveryCode)
+		if herr != nil {
+			return herr
+		}
+	}
+	return nil
+}
+
+// Return the authenticator of the user or nil if there is none
+func (twoFactorService *twoFactorServiceImpl) getDevice(userID uuid.UUID) (*models.TOTPDevice, httperrors.HTTPError) {
+	devices, herr := twoFactorService.totpDeviceRepository.Find(squirrel.Eq{"user_id": userID.String()}, nil, nil)
+	if herr != nil {
+		return nil, herr
+	}
+	if !devices.HasContent {
+		return nil, nil
+	}
+	return devices.Ressources[0], nil
+}
+
+// Return true if the code looks like a TOTP code rather than a recovery code
+func isTOTPCode(code string) bool {
+	if len(code) != totp.Digits {
+		return false
+	}
+	for _, r := range code {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
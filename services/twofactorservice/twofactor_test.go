@@ -0,0 +1,262 @@
+package twofactorservice_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	mocksConfiguration "github.com/ditrit/badaas/mocks/configuration"
+	mocksRepository "github.com/ditrit/badaas/mocks/persistence/repository"
+	mocksRBACService "github.com/ditrit/badaas/mocks/services/rbacservice"
+	mocksUserService "github.com/ditrit/badaas/mocks/services/userservice"
+	"github.com/ditrit/badaas/persistence/models"
+	"github.com/ditrit/badaas/persistence/pagination"
+	"github.com/ditrit/badaas/services/auth/protocols/totp"
+	"github.com/ditrit/badaas/services/twofactorservice"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const secret = "JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"
+
+type testSetup struct {
+	totpDeviceRepository     *mocksRepository.CRUDRepository[models.TOTPDevice, uuid.UUID]
+	recoveryCodeRepository   *mocksRepository.CRUDRepository[models.RecoveryCode, uuid.UUID]
+	loginChallengeRepository *mocksRepository.CRUDRepository[models.LoginChallenge, uuid.UUID]
+	userService              *mocksUserService.UserService
+	rbacService              *mocksRBACService.RBACService
+	service                  twofactorservice.TwoFactorService
+}
+
+func setupTest(t *testing.T, requiredRoles ...string) *testSetup {
+	twoFactorConfiguration := mocksConfiguration.NewTwoFactorConfiguration(t)
+	twoFactorConfiguration.On("GetIssuer").Return("badaas").Maybe()
+	twoFactorConfiguration.On("GetRecoveryCodes").Return(uint(3)).Maybe()
+	twoFactorConfiguration.On("GetChallengeDuration").Return(5 * time.Minute).Maybe()
+	twoFactorConfiguration.On("GetChallengeAttempts").Return(uint(2)).Maybe()
+	twoFactorConfiguration.On("GetRequiredRoles").Return(requiredRoles).Maybe()
+	setup := &testSetup{
+		totpDeviceRepository:     mocksRepository.NewCRUDRepository[models.TOTPDevice, uuid.UUID](t),
+		recoveryCodeRepository:   mocksRepository.NewCRUDRepository[models.RecoveryCode, uuid.UUID](t),
+		loginChallengeRepository: mocksRepository.NewCRUDRepository[models.LoginChallenge, uuid.UUID](t),
+		userService:              mocksUserService.NewUserService(t),
+		rbacService:              mocksRBACService.NewRBACService(t),
+	}
+	setup.service = twofactorservice.NewTwoFactorService(zap.NewNop(), twoFactorConfiguration,
+		setup.totpDeviceRepository, setup.recoveryCodeRepository, setup.loginChallengeRepository,
+		setup.userService, setup.rbacService)
+	return setup
+}
+
+func (setup *testSetup) onFindDevice(userID uuid.UUID, devices ...*models.TOTPDevice) {
+	setup.totpDeviceRepository.On("Find", squirrel.Eq{"user_id": userID.String()}, nil, nil).
+		Return(pagination.NewPage(devices, 1, 10, uint(len(devices))), nil)
+}
+
+func (setup *testSetup) onFindChallenge(token string, challenges ...*models.LoginChallenge) {
+	setup.loginChallengeRepository.On("Find", squirrel.Eq{"token_hash": sha256Hex(token)}, nil, nil).
+		Return(pagination.NewPage(challenges, 1, 10, uint(len(challenges))), nil)
+}
+
+func sha256Hex(value string) string {
+	hash := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(hash[:])
+}
+
+func currentCode(t *testing.T) string {
+	code, err := totp.GenerateCode(secret, time.Now())
+	require.NoError(t, err)
+	return code
+}
+
+func TestIsRequired(t *testing.T) {
+	setup := setupTest(t, "operator")
+	userID := uuid.New()
+	setup.rbacService.On("GetUserRoles", userID).Return([]*models.Role{{Name: "reader"}, {Name: "operator"}}, nil)
+
+	required, herr := setup.service.IsRequired(userID)
+	assert.Nil(t, herr)
+	assert.True(t, required)
+}
+
+func TestIsRequiredWithoutRequiredRoles(t *testing.T) {
+	setup := setupTest(t)
+
+	required, herr := setup.service.IsRequired(uuid.New())
+	assert.Nil(t, herr)
+	assert.False(t, required)
+}
+
+func TestBeginEnrolment(t *testing.T) {
+	setup := setupTest(t)
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Email: "bob@email.com"}
+	setup.onFindDevice(user.ID)
+	setup.userService.On("GetUserByID", user.ID).Return(user, nil)
+	setup.totpDeviceRepository.On("Create", mock.MatchedBy(func(device *models.TOTPDevice) bool {
+		return device.UserID == user.ID && !device.Confirmed && device.Secret != ""
+	})).Return(nil)
+
+	enrolment, herr := setup.service.BeginEnrolment(user.ID)
+	require.Nil(t, herr)
+	assert.True(t, strings.HasPrefix(enrolment.URI, "otpauth://totp/badaas:bob@email.com?"))
+	assert.Contains(t, enrolment.URI, "secret="+enrolment.Secret)
+}
+
+func TestBeginEnrolmentAlreadyEnabled(t *testing.T) {
+	setup := setupTest(t)
+	userID := uuid.New()
+	setup.onFindDevice(userID, &models.TOTPDevice{UserID: userID, Secret: secret, Confirmed: true})
+
+	_, herr := setup.service.BeginEnrolment(userID)
+	assert.Equal(t, twofactorservice.HERRTwoFactorAlreadyEnabled, herr)
+}
+
+func TestConfirmEnrolment(t *testing.T) {
+	setup := setupTest(t)
+	userID := uuid.New()
+	device := &models.TOTPDevice{UserID: userID, Secret: secret}
+	setup.onFindDevice(userID, device)
+	setup.totpDeviceRepository.On("Save", device).Return(nil)
+	setup.recoveryCodeRepository.On("Find", squirrel.Eq{"user_id": userID.String()}, nil, nil).
+		Return(pagination.NewPage([]*models.RecoveryCode{}, 1, 10, 0), nil)
+	setup.recoveryCodeRepository.On("Create", mock.AnythingOfType("*models.RecoveryCode")).Return(nil).Times(3)
+
+	recoveryCodes, herr := setup.service.ConfirmEnrolment(userID, currentCode(t))
+	require.Nil(t, herr)
+	assert.Len(t, recoveryCodes, 3)
+	assert.Regexp(t, "^[a-z2-7]{4}-[a-z2-7]{4}-[a-z2-7]{4}-[a-z2-7]{4}$", recoveryCodes[0])
+	assert.True(t, device.Confirmed)
+	assert.NotZero(t, device.LastUsedStep)
+}
+
+func TestConfirmEnrolmentWrongCode(t *testing.T) {
+	setup := setupTest(t)
+	userID := uuid.New()
+	setup.onFindDevice(userID, &models.TOTPDevice{UserID: userID, Secret: secret})
+
+	_, herr := setup.service.ConfirmEnrolment(userID, "abcdef")
+	assert.Equal(t, twofactorservice.HERRWrongCurrentCode, herr)
+}
+
+func TestDisableRequired(t *testing.T) {
+	setup := setupTest(t, "operator")
+	userID := uuid.New()
+	setup.rbacService.On("GetUserRoles", userID).Return([]*models.Role{{Name: "operator"}}, nil)
+
+	herr := setup.service.Disable(userID, "123456")
+	assert.Equal(t, twofactorservice.HERRTwoFactorRequired, herr)
+}
+
+func TestVerifyChallengeWithTOTPCode(t *testing.T) {
+	setup := setupTest(t)
+	userID := uuid.New()
+	challenge := &models.LoginChallenge{UserID: userID, ExpiresAt: time.Now().Add(time.Minute)}
+	setup.onFindChallenge("token", challenge)
+	device := &models.TOTPDevice{UserID: userID, Secret: secret, Confirmed: true}
+	setup.onFindDevice(userID, device)
+	setup.totpDeviceRepository.On("Save", device).Return(nil)
+	setup.loginChallengeRepository.On("Delete", challenge).Return(nil)
+
+	challengeUserID, herr := setup.service.VerifyChallenge("token", currentCode(t))
+	assert.Nil(t, herr)
+	assert.Equal(t, userID, challengeUserID)
+}
+
+func TestVerifyChallengeWithRecoveryCode(t *testing.T) {
+	setup := setupTest(t)
+	userID := uuid.New()
+	challenge := &models.LoginChallenge{UserID: userID, ExpiresAt: time.Now().Add(time.Minute)}
+	setup.onFindChallenge("token", challenge)
+	setup.onFindDevice(userID, &models.TOTPDevice{UserID: userID, Secret: secret, Confirmed: true})
+	recoveryCode := &models.RecoveryCode{UserID: userID, CodeHash: sha256Hex("abcdefghijklmnop")}
+	// the case and the dashes are ignored
+	setup.recoveryCodeRepository.On("Find", squirrel.Eq{
+		"user_id":   userID.String(),
+		"code_hash": sha256Hex("abcdefghijklmnop"),
+	}, nil, nil).Return(pagination.NewPage([]*models.RecoveryCode{recoveryCode}, 1, 10, 1), nil)
+	setup.recoveryCodeRepository.On("Delete", recoveryCode).Return(nil)
+	setup.loginChallengeRepository.On("Delete", challenge).Return(nil)
+
+	challengeUserID, herr := setup.service.VerifyChallenge("token", "ABCD-efgh-ijkl-mnop")
+	assert.Nil(t, herr)
+	assert.Equal(t, userID, challengeUserID)
+}
+
+func TestVerifyChallengeWrongCodes(t *testing.T) {
+	setup := setupTest(t)
+	userID := uuid.New()
+	challenge := &models.LoginChallenge{UserID: userID, ExpiresAt: time.Now().Add(time.Minute)}
+	setup.onFindChallenge("token", challenge)
+	setup.onFindDevice(userID, &models.TOTPDevice{UserID: userID, Secret: secret, Confirmed: true})
+	setup.loginChallengeRepository.On("Save", challenge).Return(nil).Once()
+	setup.loginChallengeRepository.On("Delete", challenge).Return(nil).Once()
+
+	setup.recoveryCodeRepository.On("Find", mock.Anything, nil, nil).
+		Return(pagination.NewPage([]*models.RecoveryCode{}, 1, 10, 0), nil)
+
+	_, herr := setup.service.VerifyChallenge("token", "wrong")
+	assert.Equal(t, twofactorservice.HERRInvalidCode, herr)
+	assert.Equal(t, uint(1), challenge.FailedAttempts)
+
+	// the challenge is deleted once the maximum number of attempts is reached
+	_, herr = setup.service.VerifyChallenge("token", "wrong")
+	assert.Equal(t, twofactorservice.HERRInvalidCode, herr)
+}
+
+func TestVerifyChallengeRefusesEnrolmentChallenges(t *testing.T) {
+	setup := setupTest(t)
+	setup.onFindChallenge("token", &models.LoginChallenge{
+		UserID: uuid.New(), ExpiresAt: time.Now().Add(time.Minute), Enrolment: true,
+	})
+
+	_, herr := setup.service.VerifyChallenge("token", "123456")
+	assert.Equal(t, twofactorservice.HERRInvalidChallenge, herr)
+}
+
+func TestGetEnrolmentChallengeUserID(t *testing.T) {
+	setup := setupTest(t)
+	userID := uuid.New()
+	setup.onFindChallenge("enrolment", &models.LoginChallenge{
+		UserID: userID, ExpiresAt: time.Now().Add(time.Minute), Enrolment: true,
+	})
+	setup.onFindChallenge("verification", &models.LoginChallenge{
+		UserID: userID, ExpiresAt: time.Now().Add(time.Minute),
+	})
+
+	challengeUserID, herr := setup.service.GetEnrolmentChallengeUserID("enrolment")
+	assert.Nil(t, herr)
+	assert.Equal(t, userID, challengeUserID)
+	_, herr = setup.service.GetEnrolmentChallengeUserID("verification")
+	assert.Equal(t, twofactorservice.HERRInvalidChallenge, herr)
+}
+
+func TestCreateChallenge(t *testing.T) {
+	setup := setupTest(t)
+	userID := uuid.New()
+	var challenge *models.LoginChallenge
+	setup.loginChallengeRepository.On("Create", mock.Anything).Run(func(args mock.Arguments) {
+		challenge = args.Get(0).(*models.LoginChallenge)
+	}).Return(nil)
+
+	token, herr := setup.service.CreateChallenge(userID, true)
+	require.Nil(t, herr)
+	assert.Equal(t, sha256Hex(token), challenge.TokenHash)
+	assert.Equal(t, userID, challenge.UserID)
+	assert.True(t, challenge.Enrolment)
+}
+
+func TestVerifyChallengeExpired(t *testing.T) {
+	setup := setupTest(t)
+	challenge := &models.LoginChallenge{UserID: uuid.New(), ExpiresAt: time.Now().Add(-time.Minute)}
+	setup.onFindChallenge("token", challenge)
+	setup.loginChallengeRepository.On("Delete", challenge).Return(nil)
+
+	_, herr := setup.service.VerifyChallenge("token", "123456")
+	assert.Equal(t, twofactorservice.HERRInvalidChallenge, herr)
+}
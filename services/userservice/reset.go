@@ -0,0 +1,114 @@
+package userservice
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/ditrit/badaas/httperrors"
+	"github.com/ditrit/badaas/persistence/models"
+	"github.com/ditrit/badaas/services/mailservice"
+	"go.uber.org/zap"
+)
+
+// Errors
+var (
+	HERRInvalidResetToken = httperrors.NewHTTPError(http.StatusBadRequest, "invalid token",
+		"the password reset token is invalid or expired", nil, false)
+)
+
+// Send a password reset token to the user of the email
+//
+// Nothing is done if there is no enabled user with this email and the failures are only logged,
+// so that the response doesn't tell if an account exists.
+// The previous tokens of the user can't be used anymore.
+func (userService *userServiceImpl) RequestPasswordReset(email string) httperrors.HTTPError {
+	user, herr := userService.GetUserByEmail(email)
+	if herr != nil {
+		if impl, ok := herr.(*httperrors.HTTPErrorImpl); !ok || impl.Status != http.StatusNotFound {
+			userService.logger.Error("Failed to get the user requesting a password reset", zap.Error(herr))
+		}
+		return nil
+	}
+	if user.Disabled {
+		return nil
+	}
+	herr = userService.sendPasswordReset(user)
+	if herr != nil {
+		userService.logger.Error("Failed to send a password reset",
+			zap.String("userID", user.ID.String()), zap.Error(herr))
+	}
+	return nil
+}
+
+// Change the password of the user of the token and revoke all its sessions, the token can't be used again
+func (userService *userServiceImpl) ResetPassword(token, password string) httperrors.HTTPError {
+	if password == "" {
+		return httperrors.NewHTTPError(http.StatusBadRequest, "invalid password", "the password can't be empty", nil, false)
+	}
+	tokens, herr := userService.passwordResetTokenRepository.Find(
+		squirrel.Eq{"token_hash": hashToken(token)}, nil, nil)
+	if herr != nil {
+		return herr
+	}
+	if !tokens.HasContent || tokens.Ressources[0].IsExpired() {
+		return HERRInvalidResetToken
+	}
+	user, herr := userService.GetUserByID(tokens.Ressources[0].UserID)
+	if herr != nil {
+		return herr
+	}
+	// the token is still valid if the password is refused
+	herr = userService.replacePassword(user, password)
+	if herr != nil {
+		return herr
+	}
+	herr = userService.deletePasswordResetTokens(user)
+	if herr != nil {
+		return herr
+	}
+	herr = userService.userRepository.Save(user)
+	if herr != nil {
+		return herr
+	}
+	userService.logger.Info("User reset its password", zap.String("userID", user.ID.String()))
+	return userService.sessionService.RevokeUserSessions(user.ID)
+}
+
+// Create a password reset token for the user and send it
+func (userService *userServiceImpl) sendPasswordReset(user *models.User) httperrors.HTTPError {
+	herr := userService.deletePasswordResetTokens(user)
+	if herr != nil {
+		return herr
+	}
+	token, err := generateToken()
+	if err != nil {
+		return httperrors.NewInternalServerError("token error", "failed to generate a token", err)
+	}
+	herr = userService.passwordResetTokenRepository.Create(&models.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(userService.passwordResetConfiguration.GetTokenDuration()),
+	})
+	if herr != nil {
+		return herr
+	}
+	userService.logger.Info("Sent a password reset", zap.String("userID", user.ID.String()))
+	return userService.sendTokenMail(user.Email, user, mailservice.TemplatePasswordReset, token)
+}
+
+// Delete the password reset tokens of a user
+func (userService *userServiceImpl) deletePasswordResetTokens(user *models.User) httperrors.HTTPError {
+	tokens, herr := userService.passwordResetTokenRepository.Find(
+		squirrel.Eq{"user_id": user.ID.String()}, nil, nil)
+	if herr != nil {
+		return herr
+	}
+	for _, token := range tokens.Ressources {
+		herr = userService.passwordResetTokenRepository.Delete(token)
+		if herr != nil {
+			return herr
+		}
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package userservice_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/ditrit/badaas/httperrors"
+	repositorymocks "github.com/ditrit/badaas/mocks/persistence/repository"
+	mailservicemocks "github.com/ditrit/badaas/mocks/services/mailservice"
+	sessionservicemocks "github.com/ditrit/badaas/mocks/services/sessionservice"
+	"github.com/ditrit/badaas/persistence/models"
+	"github.com/ditrit/badaas/persistence/pagination"
+	"github.com/ditrit/badaas/services/mailservice"
+	"github.com/ditrit/badaas/services/userservice"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func setupResetTest(t *testing.T) (
+	*repositorymocks.CRUDRepository[models.User, uuid.UUID],
+	*repositorymocks.CRUDRepository[models.PasswordResetToken, uuid.UUID],
+	*sessionservicemocks.SessionService,
+	*mailservicemocks.Mailer,
+	userservice.UserService,
+) {
+	userRepositoryMock := repositorymocks.NewCRUDRepository[models.User, uuid.UUID](t)
+	tokenRepositoryMock := repositorymocks.NewCRUDRepository[models.PasswordResetToken, uuid.UUID](t)
+	sessionService := sessionservicemocks.NewSessionService(t)
+	mailer := mailservicemocks.NewMailer(t)
+	userService := userservice.NewUserService(zap.L(), userRepositoryMock, sessionService,
+		repositorymocks.NewCRUDRepository[models.EmailChange, uuid.UUID](t),
+		repositorymocks.NewCRUDRepository[models.EmailVerificationToken, uuid.UUID](t), newEmailVerificationConfiguration(t, false),
+		tokenRepositoryMock, newPasswordResetConfiguration(t), mailer, newPasswordHasher(t), newPasswordPolicyService(t))
+	return userRepositoryMock, tokenRepositoryMock, sessionService, mailer, userService
+}
+
+func TestRequestPasswordReset(t *testing.T) {
+	userRepositoryMock, tokenRepositoryMock, _, mailer, userService := setupResetTest(t)
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Email: "bob@email.com"}
+	userRepositoryMock.On("Find", mock.Anything, nil, nil).
+		Return(pagination.NewPage([]*models.User{user}, 1, 10, 1), nil)
+	tokenRepositoryMock.On("Find", squirrel.Eq{"user_id": user.ID.String()}, nil, nil).
+		Return(pagination.NewPage([]*models.PasswordResetToken{}, 1, 10, 0), nil)
+	tokenRepositoryMock.On("Create", mock.Anything).Return(nil)
+	mailer.On("SendTemplate", "bob@email.com", "", mailservice.TemplatePasswordReset, mock.Anything).Return(nil)
+
+	err := userService.RequestPasswordReset("bob@email.com")
+	require.NoError(t, err)
+	token := tokenRepositoryMock.Calls[1].Arguments.Get(0).(*models.PasswordResetToken)
+	assert.Equal(t, user.ID, token.UserID)
+	assert.False(t, token.IsExpired())
+	// only the hash of the token is stored
+	assert.NotContains(t, fmt.Sprintf("%+v", mailer.Calls[0].Arguments.Get(3)), token.TokenHash)
+}
+
+func TestRequestPasswordResetUnknownEmail(t *testing.T) {
+	userRepositoryMock, tokenRepositoryMock, _, mailer, userService := setupResetTest(t)
+	userRepositoryMock.On("Find", mock.Anything, nil, nil).
+		Return(pagination.NewPage([]*models.User{}, 1, 10, 0), nil)
+
+	err := userService.RequestPasswordReset("nobody@email.com")
+	assert.NoError(t, err)
+	tokenRepositoryMock.AssertNotCalled(t, "Create", mock.Anything)
+	mailer.AssertNotCalled(t, "SendTemplate", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRequestPasswordResetDatabaseError(t *testing.T) {
+	userRepositoryMock, _, _, _, userService := setupResetTest(t)
+	userRepositoryMock.On("Find", mock.Anything, nil, nil).Return(nil, httperrors.AnError)
+
+	err := userService.RequestPasswordReset("bob@email.com")
+	assert.NoError(t, err)
+}
+
+func TestResetPassword(t *testing.T) {
+	userRepositoryMock, tokenRepositoryMock, sessionService, _, userService := setupResetTest(t)
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Email: "bob@email.com"}
+	token := &models.PasswordResetToken{UserID: user.ID, ExpiresAt: time.Now().Add(time.Hour)}
+	tokenRepositoryMock.On("Find", mock.Anything, nil, nil).
+		Return(pagination.NewPage([]*models.PasswordResetToken{token}, 1, 10, 1), nil)
+	tokenRepositoryMock.On("Delete", token).Return(nil)
+	userRepositoryMock.On("Find", mock.Anything, nil, nil).
+		Return(pagination.NewPage([]*models.User{user}, 1, 10, 1), nil)
+	userRepositoryMock.On("Save", user).Return(nil)
+	sessionService.On("RevokeUserSessions", user.ID).Return(nil)
+
+	err := userService.ResetPassword("token", "5678")
+	require.NoError(t, err)
+	assert.True(t, newPasswordHasher(t).Verify(user.Password, "5678"))
+}
+
+func TestResetPasswordExpiredToken(t *testing.T) {
+	userRepositoryMock, tokenRepositoryMock, _, _, userService := setupResetTest(t)
+	token := &models.PasswordResetToken{UserID: uuid.New(), ExpiresAt: time.Now().Add(-time.Hour)}
+	tokenRepositoryMock.On("Find", mock.Anything, nil, nil).
+		Return(pagination.NewPage([]*models.PasswordResetToken{token}, 1, 10, 1), nil)
+
+	err := userService.ResetPassword("token", "5678")
+	assert.Equal(t, userservice.HERRInvalidResetToken, err)
+	userRepositoryMock.AssertNotCalled(t, "Save", mock.Anything)
+}
@@ -1,23 +1,99 @@
 package userservice
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/Masterminds/squirrel"
+	"github.com/ditrit/badaas/configuration"
 	"github.com/ditrit/badaas/httperrors"
 	"github.com/ditrit/badaas/persistence/models"
 	"github.com/ditrit/badaas/persistence/models/dto"
+	"github.com/ditrit/badaas/persistence/pagination"
 	"github.com/ditrit/badaas/persistence/repository"
 	"github.com/ditrit/badaas/services/auth/protocols/basicauth"
+	"github.com/ditrit/badaas/services/mailservice"
+	"github.com/ditrit/badaas/services/passwordpolicyservice"
+	"github.com/ditrit/badaas/services/sessionservice"
 	validator "github.com/ditrit/badaas/validators"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
+// Allow to manage the user accounts
+const PermissionUsersManage string = "users:manage"
+
+// A language tag such as "fr" or "en-GB"
+var localeRegexp = regexp.MustCompile(`^[a-zA-Z]{2,8}([-_][a-zA-Z0-9]{1,8})*$`)
+
+// Return true if the locale is a language tag such as "fr" or "en-GB"
+func IsValidLocale(locale string) bool {
+	return localeRegexp.MatchString(locale)
+}
+
+// The duration during which an email change can be confirmed
+const EmailChangeLifetime = 24 * time.Hour
+
+// Errors
+var (
+	HERRUserDisabled         = httperrors.NewUnauthorizedError("user disabled", "the account of the user is disabled")
+	HERRWrongPassword        = httperrors.NewUnauthorizedError("wrong password", "the provided email or password is incorrect")
+	HERRWrongCurrentPassword = httperrors.NewForbiddenError("wrong password", "the current password is incorrect")
+	HERRPasswordTooLong      = httperrors.NewHTTPError(http.StatusBadRequest, "invalid password",
+		"the password is too long", nil, false)
+	HERRInvalidLocale = httperrors.NewHTTPError(http.StatusBadRequest, "invalid locale",
+		"the locale is not a valid language tag", nil, false)
+	HERRInvalidEmailToken = httperrors.NewHTTPError(http.StatusBadRequest, "invalid token",
+		"the email change token is invalid or expired", nil, false)
+)
+
 // UserService provide functions related to Users
 type UserService interface {
+	// Create a new user, the password must satisfy the password policy
 	NewUser(username, email, password string) (*models.User, error)
+	// Create a new user without checking the password policy, for the users whose password is set in the configuration
+	NewSystemUser(username, email, password string) (*models.User, error)
 	GetUser(dto.UserLoginDTO) (*models.User, httperrors.HTTPError)
+	// Return an error if the user can't log in, because it is disabled or its email is not verified
+	CheckCanLogIn(user *models.User) httperrors.HTTPError
+	GetUserByEmail(email string) (*models.User, httperrors.HTTPError)
+	GetUserByID(userID uuid.UUID) (*models.User, httperrors.HTTPError)
+	// Return a page of the users whose username or email contains the search string
+	GetUsers(search string, paginator pagination.Paginator) (*pagination.Page[models.User], httperrors.HTTPError)
+	// Update the username and the email of a user, empty values are left unchanged,
+	// a new email has to be verified again
+	UpdateUser(userID uuid.UUID, username, email string) (*models.User, httperrors.HTTPError)
+	// Change the password of a user and revoke its sessions
+	SetPassword(userID uuid.UUID, password string) httperrors.HTTPError
+	// Disable or enable a user, the sessions of a disabled user are revoked
+	SetDisabled(userID uuid.UUID, disabled bool) httperrors.HTTPError
+	// Set the locale of the mails sent to a user, the default one is used if empty
+	SetLocale(userID uuid.UUID, locale string) httperrors.HTTPError
+	// Delete a user with its credentials, identities, roles, memberships and pending tokens, and revoke its sessions
+	DeleteUser(userID uuid.UUID) httperrors.HTTPError
+	// Change the password of a user after checking the current one, the other sessions of the user are revoked
+	ChangePassword(userID, sessionUUID uuid.UUID, currentPassword, newPassword string) httperrors.HTTPError
+	// Start the change of the email of a user, it is applied once confirmed with ConfirmEmailChange
+	RequestEmailChange(userID uuid.UUID, email string) httperrors.HTTPError
+	// Apply the pending email change matching the token
+	ConfirmEmailChange(userID uuid.UUID, token string) (*models.User, httperrors.HTTPError)
+	// Send a verification token to the email of the user, return an error if the last one was sent too recently
+	SendEmailVerification(userID uuid.UUID) httperrors.HTTPError
+	// Send a new verification token to the user of the email, no error tells if there is an unverified user with this email
+	ResendEmailVerification(email string) httperrors.HTTPError
+	// Mark the email of the user of the token as verified, the token can't be used again
+	VerifyEmail(token string) (*models.User, httperrors.HTTPError)
+	// Mark the email of a user as verified without sending a token
+	MarkEmailVerified(userID uuid.UUID) httperrors.HTTPError
+	// Send a password reset token to the user of the email, nothing is done if there is no such user
+	RequestPasswordReset(email string) httperrors.HTTPError
+	// Change the password of the user of the token and revoke all its sessions, the token can't be used again
+	ResetPassword(token, password string) httperrors.HTTPError
 }
 
 // Check interface compliance
@@ -25,23 +101,63 @@ var _ UserService = (*userServiceImpl)(nil)
 
 // The UserService concrete implementation
 type userServiceImpl struct {
-	userRepository repository.CRUDRepository[models.User, uuid.UUID]
-	logger         *zap.Logger
+	userRepository                   repository.CRUDRepository[models.User, uuid.UUID]
+	emailChangeRepository            repository.CRUDRepository[models.EmailChange, uuid.UUID]
+	emailVerificationTokenRepository repository.CRUDRepository[models.EmailVerificationToken, uuid.UUID]
+	passwordResetTokenRepository     repository.CRUDRepository[models.PasswordResetToken, uuid.UUID]
+	sessionService                   sessionservice.SessionService
+	mailer                           mailservice.Mailer
+	passwordHasher                   basicauth.PasswordHasher
+	passwordPolicyService            passwordpolicyservice.PasswordPolicyService
+	emailVerificationConfiguration   configuration.EmailVerificationConfiguration
+	passwordResetConfiguration       configuration.PasswordResetConfiguration
+	logger                           *zap.Logger
+	// compared with the passwords of the unknown users
+	dummyPasswordHash     []byte
+	dummyPasswordHashOnce sync.Once
 }
 
 // UserService constructor
 func NewUserService(
 	logger *zap.Logger,
 	userRepository repository.CRUDRepository[models.User, uuid.UUID],
+	sessionService sessionservice.SessionService,
+	emailChangeRepository repository.CRUDRepository[models.EmailChange, uuid.UUID],
+	emailVerificationTokenRepository repository.CRUDRepository[models.EmailVerificationToken, uuid.UUID],
+	emailVerificationConfiguration configuration.EmailVerificationConfiguration,
+	passwordResetTokenRepository repository.CRUDRepository[models.PasswordResetToken, uuid.UUID],
+	passwordResetConfiguration configuration.PasswordResetConfiguration,
+	mailer mailservice.Mailer,
+	passwordHasher basicauth.PasswordHasher,
+	passwordPolicyService passwordpolicyservice.PasswordPolicyService,
 ) UserService {
 	return &userServiceImpl{
-		logger:         logger,
-		userRepository: userRepository,
+		logger:                           logger,
+		userRepository:                   userRepository,
+		sessionService:                   sessionService,
+		emailChangeRepository:            emailChangeRepository,
+		emailVerificationTokenRepository: emailVerificationTokenRepository,
+		emailVerificationConfiguration:   emailVerificationConfiguration,
+		passwordResetTokenRepository:     passwordResetTokenRepository,
+		passwordResetConfiguration:       passwordResetConfiguration,
+		mailer:                           mailer,
+		passwordHasher:                   passwordHasher,
+		passwordPolicyService:            passwordPolicyService,
 	}
 }
 
-// Create a new user
+// Create a new user, the password must satisfy the password policy
 func (userService *userServiceImpl) NewUser(username, email, password string) (*models.User, error) {
+	return userService.newUser(username, email, password, true)
+}
+
+// Create a new user without checking the password policy
+func (userService *userServiceImpl) NewSystemUser(username, email, password string) (*models.User, error) {
+	return userService.newUser(username, email, password, false)
+}
+
+// Create a new user, the password policy is only checked if checkPolicy is true
+func (userService *userServiceImpl) newUser(username, email, password string, checkPolicy bool) (*models.User, error) {
 	sanitizedEmail, err := validator.ValidEmail(email)
 	if err != nil {
 		return nil, fmt.Errorf("the provided email is not valid")
@@ -49,8 +165,18 @@ func (userService *userServiceImpl) NewUser(username, email, password string) (*
 	u := &models.User{
 		Username: username,
 		Email:    sanitizedEmail,
-		Password: basicauth.SaltAndHashPassword(password),
 	}
+	if checkPolicy {
+		herr := userService.passwordPolicyService.Validate(u, password)
+		if herr != nil {
+			return nil, herr
+		}
+	}
+	passwordHash, herr := userService.hashPassword(password)
+	if herr != nil {
+		return nil, herr
+	}
+	u.Password = passwordHash
 	httpError := userService.userRepository.Create(u)
 	if httpError != nil {
 		return nil, httpError
@@ -68,15 +194,462 @@ func (userService *userServiceImpl) GetUser(userLoginDTO dto.UserLoginDTO) (*mod
 		return nil, herr
 	}
 	if !users.HasContent {
-		return nil, httperrors.NewErrorNotFound("user",
-			fmt.Sprintf("no user found with email %q", userLoginDTO.Email))
+		// spend the same time as for a wrong password, so that the response doesn't tell if an account exists
+		userService.passwordHasher.Verify(userService.getDummyPasswordHash(), userLoginDTO.Password)
+		return nil, HERRWrongPassword
 	}
-
 	user := users.Ressources[0]
 
 	// Check password
-	if !basicauth.CheckUserPassword(user.Password, userLoginDTO.Password) {
-		return nil, httperrors.NewUnauthorizedError("wrong password", "the provided password is incorrect")
+	if !userService.passwordHasher.Verify(user.Password, userLoginDTO.Password) {
+		return nil, HERRWrongPassword
+	}
+	herr = userService.CheckCanLogIn(user)
+	if herr != nil {
+		return nil, herr
+	}
+	if userService.passwordHasher.NeedsRehash(user.Password) {
+		userService.rehashPassword(user, userLoginDTO.Password)
 	}
 	return user, nil
 }
+
+// Return an error if the user can't log in, because it is disabled or its email is not verified
+func (userService *userServiceImpl) CheckCanLogIn(user *models.User) httperrors.HTTPError {
+	if user.Disabled {
+		return HERRUserDisabled
+	}
+	if !user.EmailVerified && userService.emailVerificationConfiguration.GetRequired() {
+		return HERREmailNotVerified
+	}
+	return nil
+}
+
+// Return the hash of a random password, computed once with the current algorithm and parameters
+func (userService *userServiceImpl) getDummyPasswordHash() []byte {
+	userService.dummyPasswordHashOnce.Do(func() {
+		password, err := generateToken()
+		if err == nil {
+			userService.dummyPasswordHash, err = userService.passwordHasher.Hash(password)
+		}
+		if err != nil {
+			userService.logger.Warn("Failed to compute the dummy password hash", zap.Error(err))
+		}
+	})
+	return userService.dummyPasswordHash
+}
+
+// Hash again the password of a user with the current algorithm and parameters
+//
+// The login doesn't fail if the new hash can't be saved, the old one is still valid.
+func (userService *userServiceImpl) rehashPassword(user *models.User, password string) {
+	passwordHash, herr := userService.hashPassword(password)
+	if herr == nil {
+		user.Password = passwordHash
+		herr = userService.userRepository.Save(user)
+	}
+	if herr != nil {
+		userService.logger.Warn("Failed to rehash the password of the user",
+			zap.String("userID", user.ID.String()), zap.Error(herr))
+		return
+	}
+	userService.logger.Info("Rehashed the password of the user", zap.String("userID", user.ID.String()))
+}
+
+// Get user by email, return an error if not found.
+func (userService *userServiceImpl) GetUserByEmail(email string) (*models.User, httperrors.HTTPError) {
+	users, herr := userService.userRepository.Find(squirrel.Eq{"email": email}, nil, nil)
+	if herr != nil {
+		return nil, herr
+	}
+	if !users.HasContent {
+		return nil, httperrors.NewErrorNotFound("user",
+			fmt.Sprintf("no user found with email %q", email))
+	}
+	return users.Ressources[0], nil
+}
+
+// Get user by id, return an error if not found.
+func (userService *userServiceImpl) GetUserByID(userID uuid.UUID) (*models.User, httperrors.HTTPError) {
+	users, herr := userService.userRepository.Find(squirrel.Eq{"id": userID.String()}, nil, nil)
+	if herr != nil {
+		return nil, herr
+	}
+	if !users.HasContent {
+		return nil, httperrors.NewErrorNotFound("user",
+			fmt.Sprintf("no user found with id %q", userID))
+	}
+	return users.Ressources[0], nil
+}
+
+// Return a page of the users whose username or email contains the search string, sorted by email
+func (userService *userServiceImpl) GetUsers(
+	search string,
+	paginator pagination.Paginator,
+) (*pagination.Page[models.User], httperrors.HTTPError) {
+	var filters squirrel.Sqlizer = squirrel.Eq{}
+	search = strings.TrimSpace(search)
+	if search != "" {
+		pattern := "%" + escapeLikePattern(search) + "%"
+		filters = squirrel.Or{
+			squirrel.ILike{"username": pattern},
+			squirrel.ILike{"email": pattern},
+		}
+	}
+	return userService.userRepository.Find(filters, paginator, repository.NewSortOption("email", false))
+}
+
+// The models holding the tokens sent to the email of a user
+var emailTokens = []models.Tabler{
+	&models.EmailChange{},
+	&models.EmailVerificationToken{},
+	&models.PasswordResetToken{},
+}
+
+// Update the username and the email of a user, empty values are left unchanged
+//
+// A new email is not verified and the tokens sent to the previous one are deleted.
+func (userService *userServiceImpl) UpdateUser(userID uuid.UUID, username, email string) (*models.User, httperrors.HTTPError) {
+	user, herr := userService.GetUserByID(userID)
+	if herr != nil {
+		return nil, herr
+	}
+	if username = strings.TrimSpace(username); username != "" {
+		user.Username = username
+	}
+	emailChanged := false
+	if email != "" {
+		sanitizedEmail, err := validator.ValidEmail(email)
+		if err != nil {
+			return nil, httperrors.NewHTTPError(http.StatusBadRequest, "invalid email", "the provided email is not valid", nil, false)
+		}
+		if sanitizedEmail != user.Email {
+			herr = userService.checkEmailAvailable(sanitizedEmail)
+			if herr != nil {
+				return nil, herr
+			}
+			user.Email = sanitizedEmail
+			// the new email has not been verified by the user
+			user.EmailVerified = false
+			emailChanged = true
+		}
+	}
+	_, herr = userService.userRepository.Transaction(
+		func(userRepository repository.CRUDRepository[models.User, uuid.UUID]) (any, error) {
+			if emailChanged {
+				// the pending tokens were sent to the previous email
+				herr := userRepository.DeleteDependents(squirrel.Eq{"user_id": userID.String()}, emailTokens)
+				if herr != nil {
+					return nil, herr
+				}
+			}
+			herr := userRepository.Save(user)
+			if herr != nil {
+				return nil, herr
+			}
+			return nil, nil
+		},
+	)
+	if herr != nil {
+		return nil, herr
+	}
+	userService.logger.Info("Updated user",
+		zap.String("userID", userID.String()), zap.String("email", user.Email), zap.String("username", user.Username))
+	return user, nil
+}
+
+// Change the password of a user and revoke its sessions
+func (userService *userServiceImpl) SetPassword(userID uuid.UUID, password string) httperrors.HTTPError {
+	if password == "" {
+		return httperrors.NewHTTPError(http.StatusBadRequest, "invalid password", "the password can't be empty", nil, false)
+	}
+	user, herr := userService.GetUserByID(userID)
+	if herr != nil {
+		return herr
+	}
+	herr = userService.replacePassword(user, password)
+	if herr != nil {
+		return herr
+	}
+	herr = userService.userRepository.Save(user)
+	if herr != nil {
+		return herr
+	}
+	userService.logger.Info("Changed user password", zap.String("userID", userID.String()))
+	return userService.sessionService.RevokeUserSessions(userID)
+}
+
+// Set the locale of the mails sent to a user, the default one is used if empty
+func (userService *userServiceImpl) SetLocale(userID uuid.UUID, locale string) httperrors.HTTPError {
+	if locale != "" && !IsValidLocale(locale) {
+		return HERRInvalidLocale
+	}
+	user, herr := userService.GetUserByID(userID)
+	if herr != nil {
+		return herr
+	}
+	user.Locale = locale
+	return userService.userRepository.Save(user)
+}
+
+// Disable or enable a user, the sessions of a disabled user are revoked
+func (userService *userServiceImpl) SetDisabled(userID uuid.UUID, disabled bool) httperrors.HTTPError {
+	user, herr := userService.GetUserByID(userID)
+	if herr != nil {
+		return herr
+	}
+	user.Disabled = disabled
+	herr = userService.userRepository.Save(user)
+	if herr != nil {
+		return herr
+	}
+	userService.logger.Info("Changed user status",
+		zap.String("userID", userID.String()), zap.Bool("disabled", disabled))
+	if !disabled {
+		return nil
+	}
+	return userService.sessionService.RevokeUserSessions(userID)
+}
+
+// The models holding the data of a user, deleted with it
+var userDependents = []models.Tabler{
+	&models.APIKey{},
+	&models.UserRole{},
+	&models.GroupMember{},
+	&models.OrganisationMember{},
+	&models.TOTPDevice{},
+	&models.RecoveryCode{},
+	&models.WebAuthnCredential{},
+	&models.WebAuthnCeremony{},
+	&models.OIDCIdentity{},
+	&models.SAMLIdentity{},
+	&models.LDAPIdentity{},
+	&models.OAuthToken{},
+	&models.OAuthAuthorizationCode{},
+	&models.OAuthConsent{},
+	&models.EmailChange{},
+	&models.EmailVerificationToken{},
+	&models.PasswordResetToken{},
+	&models.LoginChallenge{},
+	&models.PasswordHistory{},
+}
+
+// Delete a user with its credentials, identities, roles, memberships and pending tokens, and revoke its sessions
+//
+// The sessions are revoked first, so that a failed deletion never leaves the user logged in.
+func (userService *userServiceImpl) DeleteUser(userID uuid.UUID) httperrors.HTTPError {
+	user, herr := userService.GetUserByID(userID)
+	if herr != nil {
+		return herr
+	}
+	herr = userService.sessionService.RevokeUserSessions(userID)
+	if herr != nil {
+		return herr
+	}
+	_, herr = userService.userRepository.Transaction(
+		func(userRepository repository.CRUDRepository[models.User, uuid.UUID]) (any, error) {
+			herr := userRepository.DeleteDependents(squirrel.Eq{"user_id": userID.String()}, userDependents)
+			if herr != nil {
+				return nil, herr
+			}
+			herr = userRepository.Delete(user)
+			if herr != nil {
+				return nil, herr
+			}
+			return nil, nil
+		},
+	)
+	if herr != nil {
+		return herr
+	}
+	userService.logger.Info("Deleted user", zap.String("userID", userID.String()), zap.String("email", user.Email))
+	return nil
+}
+
+// Change the password of a user after checking the current one, the other sessions of the user are revoked
+func (userService *userServiceImpl) ChangePassword(
+	userID, sessionUUID uuid.UUID,
+	currentPassword, newPassword string,
+) httperrors.HTTPError {
+	if newPassword == "" {
+		return httperrors.NewHTTPError(http.StatusBadRequest, "invalid password", "the password can't be empty", nil, false)
+	}
+	user, herr := userService.GetUserByID(userID)
+	if herr != nil {
+		return herr
+	}
+	if !userService.passwordHasher.Verify(user.Password, currentPassword) {
+		return HERRWrongCurrentPassword
+	}
+	herr = userService.replacePassword(user, newPassword)
+	if herr != nil {
+		return herr
+	}
+	herr = userService.userRepository.Save(user)
+	if herr != nil {
+		return herr
+	}
+	userService.logger.Info("User changed its password", zap.String("userID", userID.String()))
+	return userService.sessionService.RevokeOtherUserSessions(userID, sessionUUID)
+}
+
+// Start the change of the email of a user, it is applied once confirmed with ConfirmEmailChange
+//
+// A previous pending change of the user is replaced.
+func (userService *userServiceImpl) RequestEmailChange(userID uuid.UUID, email string) httperrors.HTTPError {
+	sanitizedEmail, err := validator.ValidEmail(email)
+	if err != nil {
+		return httperrors.NewHTTPError(http.StatusBadRequest, "invalid email", "the provided email is not valid", nil, false)
+	}
+	user, herr := userService.GetUserByID(userID)
+	if herr != nil {
+		return herr
+	}
+	if sanitizedEmail == user.Email {
+		return httperrors.NewHTTPError(http.StatusBadRequest, "invalid email", "the provided email is the current one", nil, false)
+	}
+	herr = userService.checkEmailAvailable(sanitizedEmail)
+	if herr != nil {
+		return herr
+	}
+	herr = userService.deleteEmailChanges(userID)
+	if herr != nil {
+		return herr
+	}
+	token, err := generateToken()
+	if err != nil {
+		return httperrors.NewInternalServerError("token error", "failed to generate a token", err)
+	}
+	herr = userService.emailChangeRepository.Create(&models.EmailChange{
+		UserID:    userID,
+		Email:     sanitizedEmail,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(EmailChangeLifetime),
+	})
+	if herr != nil {
+		return herr
+	}
+	userService.logger.Info("User requested an email change",
+		zap.String("userID", userID.String()), zap.String("email", sanitizedEmail))
+	return userService.sendTokenMail(sanitizedEmail, user, mailservice.TemplateEmailChange, token)
+}
+
+// Apply the pending email change matching the token
+func (userService *userServiceImpl) ConfirmEmailChange(userID uuid.UUID, token string) (*models.User, httperrors.HTTPError) {
+	emailChanges, herr := userService.emailChangeRepository.Find(squirrel.Eq{
+		"user_id":    userID.String(),
+		"token_hash": hashToken(token),
+	}, nil, nil)
+	if herr != nil {
+		return nil, herr
+	}
+	if !emailChanges.HasContent || emailChanges.Ressources[0].IsExpired() {
+		return nil, HERRInvalidEmailToken
+	}
+	emailChange := emailChanges.Ressources[0]
+	user, herr := userService.GetUserByID(userID)
+	if herr != nil {
+		return nil, herr
+	}
+	// the email may have been taken since the request
+	herr = userService.checkEmailAvailable(emailChange.Email)
+	if herr != nil {
+		return nil, herr
+	}
+	// the token was sent to the new email
+	user.Email = emailChange.Email
+	user.EmailVerified = true
+	herr = userService.userRepository.Save(user)
+	if herr != nil {
+		return nil, herr
+	}
+	herr = userService.deleteEmailChanges(userID)
+	if herr != nil {
+		return nil, herr
+	}
+	userService.logger.Info("User changed its email",
+		zap.String("userID", userID.String()), zap.String("email", user.Email))
+	return user, nil
+}
+
+// Delete the pending email changes of a user
+func (userService *userServiceImpl) deleteEmailChanges(userID uuid.UUID) httperrors.HTTPError {
+	emailChanges, herr := userService.emailChangeRepository.Find(squirrel.Eq{"user_id": userID.String()}, nil, nil)
+	if herr != nil {
+		return herr
+	}
+	for _, emailChange := range emailChanges.Ressources {
+		herr = userService.emailChangeRepository.Delete(emailChange)
+		if herr != nil {
+			return herr
+		}
+	}
+	return nil
+}
+
+// Return an HTTPError if a user already uses the email
+func (userService *userServiceImpl) checkEmailAvailable(email string) httperrors.HTTPError {
+	count, herr := userService.userRepository.Count(squirrel.Eq{"email": email})
+	if herr != nil {
+		return herr
+	}
+	if count > 0 {
+		return httperrors.NewHTTPError(http.StatusConflict, "email error",
+			fmt.Sprintf("the email %q is already used", email), nil, false)
+	}
+	return nil
+}
+
+// Replace the password of a user if the new one satisfies the password policy, the user is not saved
+//
+// The previous password is added to the history of the user.
+func (userService *userServiceImpl) replacePassword(user *models.User, password string) httperrors.HTTPError {
+	herr := userService.passwordPolicyService.Validate(user, password)
+	if herr != nil {
+		return herr
+	}
+	passwordHash, herr := userService.hashPassword(password)
+	if herr != nil {
+		return herr
+	}
+	herr = userService.passwordPolicyService.RecordPassword(user)
+	if herr != nil {
+		return herr
+	}
+	user.Password = passwordHash
+	return nil
+}
+
+// Salt and hash a password, return an HTTPError if it fails
+func (userService *userServiceImpl) hashPassword(password string) ([]byte, httperrors.HTTPError) {
+	passwordHash, err := userService.passwordHasher.Hash(password)
+	if errors.Is(err, basicauth.ErrPasswordTooLong) {
+		return nil, HERRPasswordTooLong
+	} else if err != nil {
+		return nil, httperrors.NewInternalServerError("password error", "failed to hash the password", err)
+	}
+	return passwordHash, nil
+}
+
+// The data of the templates of the mails containing a token
+type tokenMailData struct {
+	Username string
+	Token    string
+}
+
+// Send a mail containing a token in the locale of the user, return an HTTPError if it fails
+func (userService *userServiceImpl) sendTokenMail(to string, user *models.User, templateName, token string) httperrors.HTTPError {
+	err := userService.mailer.SendTemplate(to, user.Locale, templateName, tokenMailData{
+		Username: user.Username,
+		Token:    token,
+	})
+	if err != nil {
+		return httperrors.NewInternalServerError("mail error", "failed to send the mail", err)
+	}
+	return nil
+}
+
+// Escape the wildcards of a LIKE pattern
+func escapeLikePattern(pattern string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(pattern)
+}
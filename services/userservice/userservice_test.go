@@ -1,13 +1,24 @@
 package userservice_test
 
 import (
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/Masterminds/squirrel"
+	"github.com/ditrit/badaas/configuration"
 	"github.com/ditrit/badaas/httperrors"
+	configurationmocks "github.com/ditrit/badaas/mocks/configuration"
 	repositorymocks "github.com/ditrit/badaas/mocks/persistence/repository"
+	mailservicemocks "github.com/ditrit/badaas/mocks/services/mailservice"
+	passwordpolicyservicemocks "github.com/ditrit/badaas/mocks/services/passwordpolicyservice"
+	sessionservicemocks "github.com/ditrit/badaas/mocks/services/sessionservice"
 	"github.com/ditrit/badaas/persistence/models"
 	"github.com/ditrit/badaas/persistence/models/dto"
 	"github.com/ditrit/badaas/persistence/pagination"
+	"github.com/ditrit/badaas/persistence/repository"
+	"github.com/ditrit/badaas/services/apikeyservice"
+	"github.com/ditrit/badaas/services/auth/protocols/basicauth"
 	"github.com/ditrit/badaas/services/userservice"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -16,6 +27,7 @@ import (
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"go.uber.org/zap/zaptest/observer"
+	"golang.org/x/crypto/bcrypt"
 )
 
 func TestNewUserService(t *testing.T) {
@@ -25,7 +37,10 @@ func TestNewUserService(t *testing.T) {
 
 	userRespositoryMock := repositorymocks.NewCRUDRepository[models.User, uuid.UUID](t)
 	userRespositoryMock.On("Create", mock.Anything).Return(nil)
-	userService := userservice.NewUserService(observedLogger, userRespositoryMock)
+	userService := userservice.NewUserService(observedLogger, userRespositoryMock, sessionservicemocks.NewSessionService(t),
+		repositorymocks.NewCRUDRepository[models.EmailChange, uuid.UUID](t),
+		repositorymocks.NewCRUDRepository[models.EmailVerificationToken, uuid.UUID](t), newEmailVerificationConfiguration(t, false),
+		repositorymocks.NewCRUDRepository[models.PasswordResetToken, uuid.UUID](t), newPasswordResetConfiguration(t), newMailer(t), newPasswordHasher(t), newPasswordPolicyService(t))
 	user, err := userService.NewUser("bob", "bob@email.com", "1234")
 	assert.NoError(t, err)
 	assert.NotNil(t, user)
@@ -55,7 +70,10 @@ func TestNewUserServiceDatabaseError(t *testing.T) {
 	).Return(
 		httperrors.NewInternalServerError("database error", "test error", nil),
 	)
-	userService := userservice.NewUserService(observedLogger, userRespositoryMock)
+	userService := userservice.NewUserService(observedLogger, userRespositoryMock, sessionservicemocks.NewSessionService(t),
+		repositorymocks.NewCRUDRepository[models.EmailChange, uuid.UUID](t),
+		repositorymocks.NewCRUDRepository[models.EmailVerificationToken, uuid.UUID](t), newEmailVerificationConfiguration(t, false),
+		repositorymocks.NewCRUDRepository[models.PasswordResetToken, uuid.UUID](t), newPasswordResetConfiguration(t), newMailer(t), newPasswordHasher(t), newPasswordPolicyService(t))
 	user, err := userService.NewUser("bob", "bob@email.com", "1234")
 	assert.Error(t, err)
 	assert.Nil(t, user)
@@ -71,7 +89,10 @@ func TestNewUserServiceEmailNotValid(t *testing.T) {
 
 	userRespositoryMock := repositorymocks.NewCRUDRepository[models.User, uuid.UUID](t)
 
-	userService := userservice.NewUserService(observedLogger, userRespositoryMock)
+	userService := userservice.NewUserService(observedLogger, userRespositoryMock, sessionservicemocks.NewSessionService(t),
+		repositorymocks.NewCRUDRepository[models.EmailChange, uuid.UUID](t),
+		repositorymocks.NewCRUDRepository[models.EmailVerificationToken, uuid.UUID](t), newEmailVerificationConfiguration(t, false),
+		repositorymocks.NewCRUDRepository[models.PasswordResetToken, uuid.UUID](t), newPasswordResetConfiguration(t), newMailer(t), newPasswordHasher(t), newPasswordPolicyService(t))
 	user, err := userService.NewUser("bob", "bob@", "1234")
 	assert.Error(t, err)
 	assert.Nil(t, user)
@@ -86,7 +107,10 @@ func TestGetUser(t *testing.T) {
 	observedLogger := zap.New(observedZapCore)
 
 	userRespositoryMock := repositorymocks.NewCRUDRepository[models.User, uuid.UUID](t)
-	userService := userservice.NewUserService(observedLogger, userRespositoryMock)
+	userService := userservice.NewUserService(observedLogger, userRespositoryMock, sessionservicemocks.NewSessionService(t),
+		repositorymocks.NewCRUDRepository[models.EmailChange, uuid.UUID](t),
+		repositorymocks.NewCRUDRepository[models.EmailVerificationToken, uuid.UUID](t), newEmailVerificationConfiguration(t, false),
+		repositorymocks.NewCRUDRepository[models.PasswordResetToken, uuid.UUID](t), newPasswordResetConfiguration(t), newMailer(t), newPasswordHasher(t), newPasswordPolicyService(t))
 	userRespositoryMock.On(
 		"Create", mock.Anything,
 	).Return(
@@ -122,16 +146,20 @@ func TestGetUserNoUserFound(t *testing.T) {
 	observedLogger := zap.New(observedZapCore)
 
 	userRespositoryMock := repositorymocks.NewCRUDRepository[models.User, uuid.UUID](t)
-	userService := userservice.NewUserService(observedLogger, userRespositoryMock)
+	userService := userservice.NewUserService(observedLogger, userRespositoryMock, sessionservicemocks.NewSessionService(t),
+		repositorymocks.NewCRUDRepository[models.EmailChange, uuid.UUID](t),
+		repositorymocks.NewCRUDRepository[models.EmailVerificationToken, uuid.UUID](t), newEmailVerificationConfiguration(t, false),
+		repositorymocks.NewCRUDRepository[models.PasswordResetToken, uuid.UUID](t), newPasswordResetConfiguration(t), newMailer(t), newPasswordHasher(t), newPasswordPolicyService(t))
 	userRespositoryMock.On(
 		"Find", mock.Anything, nil, nil,
 	).Return(
+		pagination.NewPage([]*models.User{}, 1, 10, 0),
 		nil,
-		httperrors.NewErrorNotFound("user", "user with email bobnotfound@email.com"),
 	)
 
+	// an unknown email gets the same error as a wrong password
 	userFound, err := userService.GetUser(dto.UserLoginDTO{Email: "bobnotfound@email.com", Password: "1234"})
-	require.Error(t, err)
+	assert.Equal(t, userservice.HERRWrongPassword, err)
 	assert.Nil(t, userFound)
 }
 
@@ -147,7 +175,10 @@ func TestGetUserNotCorrect(t *testing.T) {
 	).Return(
 		nil,
 	)
-	userService := userservice.NewUserService(observedLogger, userRespositoryMock)
+	userService := userservice.NewUserService(observedLogger, userRespositoryMock, sessionservicemocks.NewSessionService(t),
+		repositorymocks.NewCRUDRepository[models.EmailChange, uuid.UUID](t),
+		repositorymocks.NewCRUDRepository[models.EmailVerificationToken, uuid.UUID](t), newEmailVerificationConfiguration(t, false),
+		repositorymocks.NewCRUDRepository[models.PasswordResetToken, uuid.UUID](t), newPasswordResetConfiguration(t), newMailer(t), newPasswordHasher(t), newPasswordPolicyService(t))
 	user, err := userService.NewUser("bob", "bob@email.com", "1234")
 
 	require.NoError(t, err)
@@ -175,7 +206,10 @@ func TestGetUserEmpty(t *testing.T) {
 	).Return(
 		nil,
 	)
-	userService := userservice.NewUserService(observedLogger, userRespositoryMock)
+	userService := userservice.NewUserService(observedLogger, userRespositoryMock, sessionservicemocks.NewSessionService(t),
+		repositorymocks.NewCRUDRepository[models.EmailChange, uuid.UUID](t),
+		repositorymocks.NewCRUDRepository[models.EmailVerificationToken, uuid.UUID](t), newEmailVerificationConfiguration(t, false),
+		repositorymocks.NewCRUDRepository[models.PasswordResetToken, uuid.UUID](t), newPasswordResetConfiguration(t), newMailer(t), newPasswordHasher(t), newPasswordPolicyService(t))
 	_, err := userService.NewUser("bob", "bob@email.com", "1234")
 
 	require.NoError(t, err)
@@ -190,3 +224,536 @@ func TestGetUserEmpty(t *testing.T) {
 	require.Error(t, err)
 	assert.Nil(t, userFound)
 }
+
+func TestGetUserByEmail(t *testing.T) {
+	// creating logger
+	observedZapCore, _ := observer.New(zap.DebugLevel)
+	observedLogger := zap.New(observedZapCore)
+
+	userRespositoryMock := repositorymocks.NewCRUDRepository[models.User, uuid.UUID](t)
+	userService := userservice.NewUserService(observedLogger, userRespositoryMock, sessionservicemocks.NewSessionService(t),
+		repositorymocks.NewCRUDRepository[models.EmailChange, uuid.UUID](t),
+		repositorymocks.NewCRUDRepository[models.EmailVerificationToken, uuid.UUID](t), newEmailVerificationConfiguration(t, false),
+		repositorymocks.NewCRUDRepository[models.PasswordResetToken, uuid.UUID](t), newPasswordResetConfiguration(t), newMailer(t), newPasswordHasher(t), newPasswordPolicyService(t))
+	user := &models.User{
+		Username: "bob",
+		Email:    "bob@email.com",
+	}
+	userRespositoryMock.On(
+		"Find", mock.Anything, nil, nil,
+	).Return(
+		pagination.NewPage([]*models.User{user}, 1, 10, 50),
+		nil,
+	)
+
+	userFound, err := userService.GetUserByEmail("bob@email.com")
+	require.NoError(t, err)
+	assert.Equal(t, user, userFound)
+}
+
+func TestGetUserByEmailNotFound(t *testing.T) {
+	// creating logger
+	observedZapCore, _ := observer.New(zap.DebugLevel)
+	observedLogger := zap.New(observedZapCore)
+
+	userRespositoryMock := repositorymocks.NewCRUDRepository[models.User, uuid.UUID](t)
+	userService := userservice.NewUserService(observedLogger, userRespositoryMock, sessionservicemocks.NewSessionService(t),
+		repositorymocks.NewCRUDRepository[models.EmailChange, uuid.UUID](t),
+		repositorymocks.NewCRUDRepository[models.EmailVerificationToken, uuid.UUID](t), newEmailVerificationConfiguration(t, false),
+		repositorymocks.NewCRUDRepository[models.PasswordResetToken, uuid.UUID](t), newPasswordResetConfiguration(t), newMailer(t), newPasswordHasher(t), newPasswordPolicyService(t))
+	userRespositoryMock.On(
+		"Find", mock.Anything, nil, nil,
+	).Return(
+		pagination.NewPage([]*models.User{}, 1, 10, 50),
+		nil,
+	)
+
+	userFound, err := userService.GetUserByEmail("bob@email.com")
+	require.Error(t, err)
+	assert.Nil(t, userFound)
+}
+
+func TestGetUserDisabled(t *testing.T) {
+	userRespositoryMock := repositorymocks.NewCRUDRepository[models.User, uuid.UUID](t)
+	userService := userservice.NewUserService(zap.L(), userRespositoryMock, sessionservicemocks.NewSessionService(t),
+		repositorymocks.NewCRUDRepository[models.EmailChange, uuid.UUID](t),
+		repositorymocks.NewCRUDRepository[models.EmailVerificationToken, uuid.UUID](t), newEmailVerificationConfiguration(t, false),
+		repositorymocks.NewCRUDRepository[models.PasswordResetToken, uuid.UUID](t), newPasswordResetConfiguration(t), newMailer(t), newPasswordHasher(t), newPasswordPolicyService(t))
+	user := &models.User{
+		Email:    "bob@email.com",
+		Password: hashPassword(t, "1234"),
+		Disabled: true,
+	}
+	userRespositoryMock.On("Find", mock.Anything, nil, nil).
+		Return(pagination.NewPage([]*models.User{user}, 1, 10, 1), nil)
+
+	userFound, err := userService.GetUser(dto.UserLoginDTO{Email: "bob@email.com", Password: "1234"})
+	assert.Equal(t, userservice.HERRUserDisabled, err)
+	assert.Nil(t, userFound)
+}
+
+func TestGetUserRehashesOutdatedPassword(t *testing.T) {
+	userRespositoryMock := repositorymocks.NewCRUDRepository[models.User, uuid.UUID](t)
+	passwordHashingConfiguration := configurationmocks.NewPasswordHashingConfiguration(t)
+	passwordHashingConfiguration.On("GetAlgorithm").Return(configuration.PasswordHashingArgon2id)
+	passwordHashingConfiguration.On("GetArgon2idMemory").Return(uint32(64))
+	passwordHashingConfiguration.On("GetArgon2idIterations").Return(uint32(1))
+	passwordHashingConfiguration.On("GetArgon2idParallelism").Return(uint8(1))
+	passwordHashingConfiguration.On("GetBcryptCost").Return(bcrypt.MinCost)
+	argon2idHasher, err := basicauth.NewPasswordHasher(passwordHashingConfiguration)
+	require.NoError(t, err)
+	userService := userservice.NewUserService(zap.L(), userRespositoryMock, sessionservicemocks.NewSessionService(t),
+		repositorymocks.NewCRUDRepository[models.EmailChange, uuid.UUID](t),
+		repositorymocks.NewCRUDRepository[models.EmailVerificationToken, uuid.UUID](t), newEmailVerificationConfiguration(t, false),
+		repositorymocks.NewCRUDRepository[models.PasswordResetToken, uuid.UUID](t), newPasswordResetConfiguration(t), newMailer(t), argon2idHasher, newPasswordPolicyService(t))
+	user := &models.User{
+		Email:    "bob@email.com",
+		Password: hashPassword(t, "1234"),
+	}
+	userRespositoryMock.On("Find", mock.Anything, nil, nil).
+		Return(pagination.NewPage([]*models.User{user}, 1, 10, 1), nil)
+	userRespositoryMock.On("Save", user).Return(nil)
+
+	userFound, herr := userService.GetUser(dto.UserLoginDTO{Email: "bob@email.com", Password: "1234"})
+	require.NoError(t, herr)
+	assert.Equal(t, user, userFound)
+	// the bcrypt hash is replaced by an argon2id hash
+	assert.False(t, argon2idHasher.NeedsRehash(user.Password))
+	assert.True(t, argon2idHasher.Verify(user.Password, "1234"))
+}
+
+func TestSetPasswordTooLong(t *testing.T) {
+	userRespositoryMock := repositorymocks.NewCRUDRepository[models.User, uuid.UUID](t)
+	userService := userservice.NewUserService(zap.L(), userRespositoryMock, sessionservicemocks.NewSessionService(t),
+		repositorymocks.NewCRUDRepository[models.EmailChange, uuid.UUID](t),
+		repositorymocks.NewCRUDRepository[models.EmailVerificationToken, uuid.UUID](t), newEmailVerificationConfiguration(t, false),
+		repositorymocks.NewCRUDRepository[models.PasswordResetToken, uuid.UUID](t), newPasswordResetConfiguration(t), newMailer(t), newPasswordHasher(t), newPasswordPolicyService(t))
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Email: "bob@email.com"}
+	userRespositoryMock.On("Find", mock.Anything, nil, nil).
+		Return(pagination.NewPage([]*models.User{user}, 1, 10, 1), nil)
+
+	herr := userService.SetPassword(user.ID, strings.Repeat("a", 100))
+	assert.Equal(t, userservice.HERRPasswordTooLong, herr)
+	userRespositoryMock.AssertNotCalled(t, "Save", mock.Anything)
+}
+
+func TestGetUsersSearch(t *testing.T) {
+	userRespositoryMock := repositorymocks.NewCRUDRepository[models.User, uuid.UUID](t)
+	userService := userservice.NewUserService(zap.L(), userRespositoryMock, sessionservicemocks.NewSessionService(t),
+		repositorymocks.NewCRUDRepository[models.EmailChange, uuid.UUID](t),
+		repositorymocks.NewCRUDRepository[models.EmailVerificationToken, uuid.UUID](t), newEmailVerificationConfiguration(t, false),
+		repositorymocks.NewCRUDRepository[models.PasswordResetToken, uuid.UUID](t), newPasswordResetConfiguration(t), newMailer(t), newPasswordHasher(t), newPasswordPolicyService(t))
+	paginator := pagination.NewPaginator(1, 10)
+	page := pagination.NewPage([]*models.User{}, 1, 10, 0)
+	userRespositoryMock.On("Find", squirrel.Or{
+		squirrel.ILike{"username": `%bob\_%`},
+		squirrel.ILike{"email": `%bob\_%`},
+	}, paginator, mock.Anything).Return(page, nil)
+
+	users, err := userService.GetUsers(" bob_ ", paginator)
+	require.NoError(t, err)
+	assert.Equal(t, page, users)
+}
+
+func TestUpdateUserEmailAlreadyUsed(t *testing.T) {
+	userRespositoryMock := repositorymocks.NewCRUDRepository[models.User, uuid.UUID](t)
+	userService := userservice.NewUserService(zap.L(), userRespositoryMock, sessionservicemocks.NewSessionService(t),
+		repositorymocks.NewCRUDRepository[models.EmailChange, uuid.UUID](t),
+		repositorymocks.NewCRUDRepository[models.EmailVerificationToken, uuid.UUID](t), newEmailVerificationConfiguration(t, false),
+		repositorymocks.NewCRUDRepository[models.PasswordResetToken, uuid.UUID](t), newPasswordResetConfiguration(t), newMailer(t), newPasswordHasher(t), newPasswordPolicyService(t))
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Username: "bob", Email: "bob@email.com"}
+	userRespositoryMock.On("Find", mock.Anything, nil, nil).
+		Return(pagination.NewPage([]*models.User{user}, 1, 10, 1), nil)
+	userRespositoryMock.On("Count", squirrel.Eq{"email": "alice@email.com"}).Return(uint(1), nil)
+
+	_, err := userService.UpdateUser(user.ID, "", "alice@email.com")
+	assert.Error(t, err)
+	userRespositoryMock.AssertNotCalled(t, "Save", mock.Anything)
+}
+
+func TestUpdateUser(t *testing.T) {
+	userRespositoryMock := repositorymocks.NewCRUDRepository[models.User, uuid.UUID](t)
+	userService := userservice.NewUserService(zap.L(), userRespositoryMock, sessionservicemocks.NewSessionService(t),
+		repositorymocks.NewCRUDRepository[models.EmailChange, uuid.UUID](t),
+		repositorymocks.NewCRUDRepository[models.EmailVerificationToken, uuid.UUID](t), newEmailVerificationConfiguration(t, false),
+		repositorymocks.NewCRUDRepository[models.PasswordResetToken, uuid.UUID](t), newPasswordResetConfiguration(t), newMailer(t), newPasswordHasher(t), newPasswordPolicyService(t))
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Username: "bob", Email: "bob@email.com"}
+	userRespositoryMock.On("Find", mock.Anything, nil, nil).
+		Return(pagination.NewPage([]*models.User{user}, 1, 10, 1), nil)
+	runTransactions(userRespositoryMock)
+	userRespositoryMock.On("Save", user).Return(nil)
+
+	updatedUser, err := userService.UpdateUser(user.ID, "robert", "")
+	require.NoError(t, err)
+	assert.Equal(t, "robert", updatedUser.Username)
+	assert.Equal(t, "bob@email.com", updatedUser.Email)
+	userRespositoryMock.AssertNotCalled(t, "DeleteDependents", mock.Anything, mock.Anything)
+}
+
+func TestUpdateUserEmail(t *testing.T) {
+	userRespositoryMock := repositorymocks.NewCRUDRepository[models.User, uuid.UUID](t)
+	userService := userservice.NewUserService(zap.L(), userRespositoryMock, sessionservicemocks.NewSessionService(t),
+		repositorymocks.NewCRUDRepository[models.EmailChange, uuid.UUID](t),
+		repositorymocks.NewCRUDRepository[models.EmailVerificationToken, uuid.UUID](t), newEmailVerificationConfiguration(t, false),
+		repositorymocks.NewCRUDRepository[models.PasswordResetToken, uuid.UUID](t), newPasswordResetConfiguration(t), newMailer(t), newPasswordHasher(t), newPasswordPolicyService(t))
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Username: "bob", Email: "bob@email.com", EmailVerified: true}
+	userRespositoryMock.On("Find", mock.Anything, nil, nil).
+		Return(pagination.NewPage([]*models.User{user}, 1, 10, 1), nil)
+	userRespositoryMock.On("Count", squirrel.Eq{"email": "robert@email.com"}).Return(uint(0), nil)
+	runTransactions(userRespositoryMock)
+	userRespositoryMock.On("DeleteDependents", squirrel.Eq{"user_id": user.ID.String()}, containsModels(
+		models.EmailChange{}, models.EmailVerificationToken{}, models.PasswordResetToken{},
+	)).Return(nil).Once()
+	userRespositoryMock.On("Save", user).Return(nil)
+
+	updatedUser, err := userService.UpdateUser(user.ID, "", "robert@email.com")
+	require.NoError(t, err)
+	assert.Equal(t, "robert@email.com", updatedUser.Email)
+	assert.False(t, updatedUser.EmailVerified)
+}
+
+func TestSetDisabledRevokesSessions(t *testing.T) {
+	userRespositoryMock := repositorymocks.NewCRUDRepository[models.User, uuid.UUID](t)
+	sessionService := sessionservicemocks.NewSessionService(t)
+	userService := userservice.NewUserService(zap.L(), userRespositoryMock, sessionService,
+		repositorymocks.NewCRUDRepository[models.EmailChange, uuid.UUID](t),
+		repositorymocks.NewCRUDRepository[models.EmailVerificationToken, uuid.UUID](t), newEmailVerificationConfiguration(t, false),
+		repositorymocks.NewCRUDRepository[models.PasswordResetToken, uuid.UUID](t), newPasswordResetConfiguration(t), newMailer(t), newPasswordHasher(t), newPasswordPolicyService(t))
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Email: "bob@email.com"}
+	userRespositoryMock.On("Find", mock.Anything, nil, nil).
+		Return(pagination.NewPage([]*models.User{user}, 1, 10, 1), nil)
+	userRespositoryMock.On("Save", user).Return(nil)
+	sessionService.On("RevokeUserSessions", user.ID).Return(nil)
+
+	err := userService.SetDisabled(user.ID, true)
+	require.NoError(t, err)
+	assert.True(t, user.Disabled)
+}
+
+func TestSetLocaleInvalid(t *testing.T) {
+	userRespositoryMock := repositorymocks.NewCRUDRepository[models.User, uuid.UUID](t)
+	userService := userservice.NewUserService(zap.L(), userRespositoryMock, sessionservicemocks.NewSessionService(t),
+		repositorymocks.NewCRUDRepository[models.EmailChange, uuid.UUID](t),
+		repositorymocks.NewCRUDRepository[models.EmailVerificationToken, uuid.UUID](t), newEmailVerificationConfiguration(t, false),
+		repositorymocks.NewCRUDRepository[models.PasswordResetToken, uuid.UUID](t), newPasswordResetConfiguration(t), newMailer(t), newPasswordHasher(t), newPasswordPolicyService(t))
+
+	err := userService.SetLocale(uuid.New(), "fr/../en")
+	assert.Equal(t, userservice.HERRInvalidLocale, err)
+	userRespositoryMock.AssertNotCalled(t, "Save", mock.Anything)
+}
+
+func TestSetPasswordEmpty(t *testing.T) {
+	userRespositoryMock := repositorymocks.NewCRUDRepository[models.User, uuid.UUID](t)
+	userService := userservice.NewUserService(zap.L(), userRespositoryMock, sessionservicemocks.NewSessionService(t),
+		repositorymocks.NewCRUDRepository[models.EmailChange, uuid.UUID](t),
+		repositorymocks.NewCRUDRepository[models.EmailVerificationToken, uuid.UUID](t), newEmailVerificationConfiguration(t, false),
+		repositorymocks.NewCRUDRepository[models.PasswordResetToken, uuid.UUID](t), newPasswordResetConfiguration(t), newMailer(t), newPasswordHasher(t), newPasswordPolicyService(t))
+
+	err := userService.SetPassword(uuid.New(), "")
+	assert.Error(t, err)
+}
+
+// Run the functions given to the Transaction of a repository mock with the mock itself
+func runTransactions[T models.Tabler](repositoryMock *repositorymocks.CRUDRepository[T, uuid.UUID]) {
+	var returnValue any
+	var herr httperrors.HTTPError
+	repositoryMock.On("Transaction", mock.Anything).Run(func(args mock.Arguments) {
+		transactionFunction := args.Get(0).(func(repository.CRUDRepository[T, uuid.UUID]) (any, error))
+		var err error
+		returnValue, err = transactionFunction(repositoryMock)
+		herr = nil
+		if err != nil {
+			herr = repository.DatabaseError("transaction failed", err)
+		}
+	}).Return(
+		func(func(repository.CRUDRepository[T, uuid.UUID]) (any, error)) any { return returnValue },
+		func(func(repository.CRUDRepository[T, uuid.UUID]) (any, error)) httperrors.HTTPError { return herr },
+	)
+}
+
+// Match the dependents containing all the models
+func containsModels(expectedModels ...models.Tabler) any {
+	return mock.MatchedBy(func(dependentModels []models.Tabler) bool {
+		tables := map[string]bool{}
+		for _, dependentModel := range dependentModels {
+			tables[dependentModel.TableName()] = true
+		}
+		for _, expectedModel := range expectedModels {
+			if !tables[expectedModel.TableName()] {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+func TestDeleteUser(t *testing.T) {
+	userRespositoryMock := repositorymocks.NewCRUDRepository[models.User, uuid.UUID](t)
+	sessionService := sessionservicemocks.NewSessionService(t)
+	userService := userservice.NewUserService(zap.L(), userRespositoryMock, sessionService,
+		repositorymocks.NewCRUDRepository[models.EmailChange, uuid.UUID](t),
+		repositorymocks.NewCRUDRepository[models.EmailVerificationToken, uuid.UUID](t), newEmailVerificationConfiguration(t, false),
+		repositorymocks.NewCRUDRepository[models.PasswordResetToken, uuid.UUID](t), newPasswordResetConfiguration(t), newMailer(t), newPasswordHasher(t), newPasswordPolicyService(t))
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Email: "bob@email.com"}
+	userRespositoryMock.On("Find", mock.Anything, nil, nil).
+		Return(pagination.NewPage([]*models.User{user}, 1, 10, 1), nil)
+	runTransactions(userRespositoryMock)
+	userRespositoryMock.On("DeleteDependents", squirrel.Eq{"user_id": user.ID.String()}, containsModels(
+		models.APIKey{}, models.UserRole{}, models.GroupMember{}, models.OrganisationMember{},
+		models.TOTPDevice{}, models.RecoveryCode{}, models.WebAuthnCredential{},
+		models.OIDCIdentity{}, models.SAMLIdentity{}, models.LDAPIdentity{},
+		models.EmailChange{}, models.EmailVerificationToken{}, models.PasswordResetToken{}, models.LoginChallenge{},
+		models.OAuthToken{}, models.OAuthAuthorizationCode{}, models.OAuthConsent{},
+	)).Return(nil).Once()
+	userRespositoryMock.On("Delete", user).Return(nil)
+	sessionService.On("RevokeUserSessions", user.ID).Return(nil)
+
+	assert.NoError(t, userService.DeleteUser(user.ID))
+}
+
+func TestDeleteUserKeepsTheUserOnError(t *testing.T) {
+	userRespositoryMock := repositorymocks.NewCRUDRepository[models.User, uuid.UUID](t)
+	sessionService := sessionservicemocks.NewSessionService(t)
+	userService := userservice.NewUserService(zap.L(), userRespositoryMock, sessionService,
+		repositorymocks.NewCRUDRepository[models.EmailChange, uuid.UUID](t),
+		repositorymocks.NewCRUDRepository[models.EmailVerificationToken, uuid.UUID](t), newEmailVerificationConfiguration(t, false),
+		repositorymocks.NewCRUDRepository[models.PasswordResetToken, uuid.UUID](t), newPasswordResetConfiguration(t), newMailer(t), newPasswordHasher(t), newPasswordPolicyService(t))
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Email: "bob@email.com"}
+	userRespositoryMock.On("Find", mock.Anything, nil, nil).
+		Return(pagination.NewPage([]*models.User{user}, 1, 10, 1), nil)
+	runTransactions(userRespositoryMock)
+	userRespositoryMock.On("DeleteDependents", mock.Anything, mock.Anything).Return(httperrors.AnError)
+	sessionService.On("RevokeUserSessions", user.ID).Return(nil)
+
+	assert.Error(t, userService.DeleteUser(user.ID))
+	userRespositoryMock.AssertNotCalled(t, "Delete", user)
+}
+
+func TestDeleteUserRevokesItsAPIKeys(t *testing.T) {
+	userRespositoryMock := repositorymocks.NewCRUDRepository[models.User, uuid.UUID](t)
+	sessionService := sessionservicemocks.NewSessionService(t)
+	userService := userservice.NewUserService(zap.L(), userRespositoryMock, sessionService,
+		repositorymocks.NewCRUDRepository[models.EmailChange, uuid.UUID](t),
+		repositorymocks.NewCRUDRepository[models.EmailVerificationToken, uuid.UUID](t), newEmailVerificationConfiguration(t, false),
+		repositorymocks.NewCRUDRepository[models.PasswordResetToken, uuid.UUID](t), newPasswordResetConfiguration(t), newMailer(t), newPasswordHasher(t), newPasswordPolicyService(t))
+	apiKeyRepository := repositorymocks.NewCRUDRepository[models.APIKey, uuid.UUID](t)
+	apiKeyService := apikeyservice.NewAPIKeyService(zap.NewNop(), apiKeyRepository, userService)
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Email: "bob@email.com", EmailVerified: true}
+	// the users and the api keys stored in the database
+	users := []*models.User{user}
+	apiKeys := []*models.APIKey{}
+	userRespositoryMock.On("Find", squirrel.Eq{"id": user.ID.String()}, nil, nil).Return(
+		func(squirrel.Sqlizer, pagination.Paginator, repository.SortOption) *pagination.Page[models.User] {
+			return pagination.NewPage(users, 1, 10, uint(len(users)))
+		},
+		nil,
+	)
+	apiKeyRepository.On("Create", mock.Anything).Run(func(args mock.Arguments) {
+		apiKeys = append(apiKeys, args.Get(0).(*models.APIKey))
+	}).Return(nil)
+	apiKeyRepository.On("Find", mock.Anything, nil, nil).Return(
+		func(squirrel.Sqlizer, pagination.Paginator, repository.SortOption) *pagination.Page[models.APIKey] {
+			return pagination.NewPage(apiKeys, 1, 10, uint(len(apiKeys)))
+		},
+		nil,
+	)
+	apiKeyRepository.On("Save", mock.Anything).Return(nil)
+	runTransactions(userRespositoryMock)
+	userRespositoryMock.On("DeleteDependents", squirrel.Eq{"user_id": user.ID.String()}, containsModels(models.APIKey{})).
+		Run(func(mock.Arguments) { apiKeys = []*models.APIKey{} }).Return(nil)
+	userRespositoryMock.On("Delete", user).Run(func(mock.Arguments) { users = []*models.User{} }).Return(nil)
+	sessionService.On("RevokeUserSessions", user.ID).Return(nil)
+	_, key, herr := apiKeyService.CreateAPIKey(user.ID, dto.DTOCreateAPIKey{Name: "ci"})
+	require.Nil(t, herr)
+	_, herr = apiKeyService.Authenticate(key)
+	require.Nil(t, herr)
+
+	require.Nil(t, userService.DeleteUser(user.ID))
+	_, herr = apiKeyService.Authenticate(key)
+	assert.Equal(t, apikeyservice.HERRInvalidAPIKey, herr)
+}
+
+func TestChangePasswordWrongCurrentPassword(t *testing.T) {
+	userRespositoryMock := repositorymocks.NewCRUDRepository[models.User, uuid.UUID](t)
+	userService := userservice.NewUserService(zap.L(), userRespositoryMock, sessionservicemocks.NewSessionService(t),
+		repositorymocks.NewCRUDRepository[models.EmailChange, uuid.UUID](t),
+		repositorymocks.NewCRUDRepository[models.EmailVerificationToken, uuid.UUID](t), newEmailVerificationConfiguration(t, false),
+		repositorymocks.NewCRUDRepository[models.PasswordResetToken, uuid.UUID](t), newPasswordResetConfiguration(t), newMailer(t), newPasswordHasher(t), newPasswordPolicyService(t))
+	user := &models.User{
+		BaseModel: models.BaseModel{ID: uuid.New()},
+		Email:     "bob@email.com",
+		Password:  hashPassword(t, "1234"),
+	}
+	userRespositoryMock.On("Find", mock.Anything, nil, nil).
+		Return(pagination.NewPage([]*models.User{user}, 1, 10, 1), nil)
+
+	err := userService.ChangePassword(user.ID, uuid.New(), "4321", "5678")
+	assert.Equal(t, userservice.HERRWrongCurrentPassword, err)
+	userRespositoryMock.AssertNotCalled(t, "Save", mock.Anything)
+}
+
+func TestChangePasswordRefusedByPolicy(t *testing.T) {
+	userRespositoryMock := repositorymocks.NewCRUDRepository[models.User, uuid.UUID](t)
+	passwordPolicyService := passwordpolicyservicemocks.NewPasswordPolicyService(t)
+	userService := userservice.NewUserService(zap.L(), userRespositoryMock, sessionservicemocks.NewSessionService(t),
+		repositorymocks.NewCRUDRepository[models.EmailChange, uuid.UUID](t),
+		repositorymocks.NewCRUDRepository[models.EmailVerificationToken, uuid.UUID](t), newEmailVerificationConfiguration(t, false),
+		repositorymocks.NewCRUDRepository[models.PasswordResetToken, uuid.UUID](t), newPasswordResetConfiguration(t), newMailer(t), newPasswordHasher(t), passwordPolicyService)
+	user := &models.User{
+		BaseModel: models.BaseModel{ID: uuid.New()},
+		Email:     "bob@email.com",
+		Password:  hashPassword(t, "1234"),
+	}
+	userRespositoryMock.On("Find", mock.Anything, nil, nil).
+		Return(pagination.NewPage([]*models.User{user}, 1, 10, 1), nil)
+	policyError := httperrors.NewValidationError("the password doesn't satisfy the policy",
+		map[string]string{"password": "the password must contain at least 8 characters"})
+	passwordPolicyService.On("Validate", user, "5678").Return(policyError)
+
+	err := userService.ChangePassword(user.ID, uuid.New(), "1234", "5678")
+	assert.Equal(t, policyError, err)
+	passwordPolicyService.AssertNotCalled(t, "RecordPassword", mock.Anything)
+	userRespositoryMock.AssertNotCalled(t, "Save", mock.Anything)
+}
+
+func TestChangePasswordRevokesOtherSessions(t *testing.T) {
+	userRespositoryMock := repositorymocks.NewCRUDRepository[models.User, uuid.UUID](t)
+	sessionService := sessionservicemocks.NewSessionService(t)
+	userService := userservice.NewUserService(zap.L(), userRespositoryMock, sessionService,
+		repositorymocks.NewCRUDRepository[models.EmailChange, uuid.UUID](t),
+		repositorymocks.NewCRUDRepository[models.EmailVerificationToken, uuid.UUID](t), newEmailVerificationConfiguration(t, false),
+		repositorymocks.NewCRUDRepository[models.PasswordResetToken, uuid.UUID](t), newPasswordResetConfiguration(t), newMailer(t), newPasswordHasher(t), newPasswordPolicyService(t))
+	sessionUUID := uuid.New()
+	user := &models.User{
+		BaseModel: models.BaseModel{ID: uuid.New()},
+		Email:     "bob@email.com",
+		Password:  hashPassword(t, "1234"),
+	}
+	userRespositoryMock.On("Find", mock.Anything, nil, nil).
+		Return(pagination.NewPage([]*models.User{user}, 1, 10, 1), nil)
+	userRespositoryMock.On("Save", user).Return(nil)
+	sessionService.On("RevokeOtherUserSessions", user.ID, sessionUUID).Return(nil)
+
+	err := userService.ChangePassword(user.ID, sessionUUID, "1234", "5678")
+	require.NoError(t, err)
+	assert.True(t, newPasswordHasher(t).Verify(user.Password, "5678"))
+}
+
+func TestRequestEmailChange(t *testing.T) {
+	userRespositoryMock := repositorymocks.NewCRUDRepository[models.User, uuid.UUID](t)
+	emailChangeRepositoryMock := repositorymocks.NewCRUDRepository[models.EmailChange, uuid.UUID](t)
+	userService := userservice.NewUserService(zap.L(), userRespositoryMock, sessionservicemocks.NewSessionService(t),
+		emailChangeRepositoryMock,
+		repositorymocks.NewCRUDRepository[models.EmailVerificationToken, uuid.UUID](t), newEmailVerificationConfiguration(t, false),
+		repositorymocks.NewCRUDRepository[models.PasswordResetToken, uuid.UUID](t), newPasswordResetConfiguration(t), newMailer(t), newPasswordHasher(t), newPasswordPolicyService(t))
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Email: "bob@email.com"}
+	previousChange := &models.EmailChange{UserID: user.ID, Email: "robert@email.com"}
+	userRespositoryMock.On("Find", mock.Anything, nil, nil).
+		Return(pagination.NewPage([]*models.User{user}, 1, 10, 1), nil)
+	userRespositoryMock.On("Count", squirrel.Eq{"email": "alice@email.com"}).Return(uint(0), nil)
+	emailChangeRepositoryMock.On("Find", squirrel.Eq{"user_id": user.ID.String()}, nil, nil).
+		Return(pagination.NewPage([]*models.EmailChange{previousChange}, 1, 10, 1), nil)
+	emailChangeRepositoryMock.On("Delete", previousChange).Return(nil)
+	emailChangeRepositoryMock.On("Create", mock.Anything).Return(nil)
+
+	err := userService.RequestEmailChange(user.ID, "alice@email.com")
+	require.NoError(t, err)
+	emailChange := emailChangeRepositoryMock.Calls[2].Arguments.Get(0).(*models.EmailChange)
+	assert.Equal(t, "alice@email.com", emailChange.Email)
+	assert.NotEmpty(t, emailChange.TokenHash)
+	assert.False(t, emailChange.IsExpired())
+	// the email is only changed once confirmed
+	assert.Equal(t, "bob@email.com", user.Email)
+}
+
+func TestRequestEmailChangeInvalidEmail(t *testing.T) {
+	userService := userservice.NewUserService(zap.L(), repositorymocks.NewCRUDRepository[models.User, uuid.UUID](t),
+		sessionservicemocks.NewSessionService(t), repositorymocks.NewCRUDRepository[models.EmailChange, uuid.UUID](t),
+		repositorymocks.NewCRUDRepository[models.EmailVerificationToken, uuid.UUID](t), newEmailVerificationConfiguration(t, false),
+		repositorymocks.NewCRUDRepository[models.PasswordResetToken, uuid.UUID](t), newPasswordResetConfiguration(t), newMailer(t), newPasswordHasher(t), newPasswordPolicyService(t))
+
+	err := userService.RequestEmailChange(uuid.New(), "bob@")
+	assert.Error(t, err)
+}
+
+func TestConfirmEmailChange(t *testing.T) {
+	userRespositoryMock := repositorymocks.NewCRUDRepository[models.User, uuid.UUID](t)
+	emailChangeRepositoryMock := repositorymocks.NewCRUDRepository[models.EmailChange, uuid.UUID](t)
+	userService := userservice.NewUserService(zap.L(), userRespositoryMock, sessionservicemocks.NewSessionService(t),
+		emailChangeRepositoryMock,
+		repositorymocks.NewCRUDRepository[models.EmailVerificationToken, uuid.UUID](t), newEmailVerificationConfiguration(t, false),
+		repositorymocks.NewCRUDRepository[models.PasswordResetToken, uuid.UUID](t), newPasswordResetConfiguration(t), newMailer(t), newPasswordHasher(t), newPasswordPolicyService(t))
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Email: "bob@email.com"}
+	emailChange := &models.EmailChange{UserID: user.ID, Email: "alice@email.com", ExpiresAt: time.Now().Add(time.Hour)}
+	emailChangeRepositoryMock.On("Find", mock.Anything, nil, nil).
+		Return(pagination.NewPage([]*models.EmailChange{emailChange}, 1, 10, 1), nil)
+	emailChangeRepositoryMock.On("Delete", emailChange).Return(nil)
+	userRespositoryMock.On("Find", mock.Anything, nil, nil).
+		Return(pagination.NewPage([]*models.User{user}, 1, 10, 1), nil)
+	userRespositoryMock.On("Count", squirrel.Eq{"email": "alice@email.com"}).Return(uint(0), nil)
+	userRespositoryMock.On("Save", user).Return(nil)
+
+	updatedUser, err := userService.ConfirmEmailChange(user.ID, "token")
+	require.NoError(t, err)
+	assert.Equal(t, "alice@email.com", updatedUser.Email)
+}
+
+func TestConfirmEmailChangeExpired(t *testing.T) {
+	emailChangeRepositoryMock := repositorymocks.NewCRUDRepository[models.EmailChange, uuid.UUID](t)
+	userService := userservice.NewUserService(zap.L(), repositorymocks.NewCRUDRepository[models.User, uuid.UUID](t),
+		sessionservicemocks.NewSessionService(t), emailChangeRepositoryMock,
+		repositorymocks.NewCRUDRepository[models.EmailVerificationToken, uuid.UUID](t), newEmailVerificationConfiguration(t, false),
+		repositorymocks.NewCRUDRepository[models.PasswordResetToken, uuid.UUID](t), newPasswordResetConfiguration(t), newMailer(t), newPasswordHasher(t), newPasswordPolicyService(t))
+	emailChange := &models.EmailChange{Email: "alice@email.com", ExpiresAt: time.Now().Add(-time.Hour)}
+	emailChangeRepositoryMock.On("Find", mock.Anything, nil, nil).
+		Return(pagination.NewPage([]*models.EmailChange{emailChange}, 1, 10, 1), nil)
+
+	user, err := userService.ConfirmEmailChange(uuid.New(), "token")
+	assert.Equal(t, userservice.HERRInvalidEmailToken, err)
+	assert.Nil(t, user)
+}
+
+// Create an email verification configuration, the verification is required if required is true
+func newEmailVerificationConfiguration(t *testing.T, required bool) *configurationmocks.EmailVerificationConfiguration {
+	emailVerificationConfiguration := configurationmocks.NewEmailVerificationConfiguration(t)
+	emailVerificationConfiguration.On("GetRequired").Return(required).Maybe()
+	emailVerificationConfiguration.On("GetTokenDuration").Return(time.Hour).Maybe()
+	emailVerificationConfiguration.On("GetResendInterval").Return(time.Minute).Maybe()
+	return emailVerificationConfiguration
+}
+
+// Create a password reset configuration
+func newPasswordResetConfiguration(t *testing.T) *configurationmocks.PasswordResetConfiguration {
+	passwordResetConfiguration := configurationmocks.NewPasswordResetConfiguration(t)
+	passwordResetConfiguration.On("GetTokenDuration").Return(time.Hour).Maybe()
+	return passwordResetConfiguration
+}
+
+// Create a mailer accepting every mail
+func newMailer(t *testing.T) *mailservicemocks.Mailer {
+	mailer := mailservicemocks.NewMailer(t)
+	mailer.On("SendTemplate", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	return mailer
+}
+
+// Create a fast password hasher
+func newPasswordHasher(t *testing.T) basicauth.PasswordHasher {
+	passwordHasher, err := basicauth.NewBcryptHasher(bcrypt.MinCost)
+	require.NoError(t, err)
+	return passwordHasher
+}
+
+// Hash the password with the fast password hasher
+func hashPassword(t *testing.T, password string) []byte {
+	passwordHash, err := newPasswordHasher(t).Hash(password)
+	require.NoError(t, err)
+	return passwordHash
+}
+
+// Create a password policy accepting every password
+func newPasswordPolicyService(t *testing.T) *passwordpolicyservicemocks.PasswordPolicyService {
+	passwordPolicyService := passwordpolicyservicemocks.NewPasswordPolicyService(t)
+	passwordPolicyService.On("Validate", mock.Anything, mock.Anything).Return(nil).Maybe()
+	passwordPolicyService.On("RecordPassword", mock.Anything).Return(nil).Maybe()
+	return passwordPolicyService
+}
@@ -0,0 +1,155 @@
+package userservice
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/ditrit/badaas/httperrors"
+	"github.com/ditrit/badaas/persistence/models"
+	"github.com/ditrit/badaas/services/mailservice"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Errors
+var (
+	HERREmailNotVerified         = httperrors.NewForbiddenError("email not verified", "the email of the user is not verified")
+	HERRInvalidVerificationToken = httperrors.NewHTTPError(http.StatusBadRequest, "invalid token",
+		"the verification token is invalid or expired", nil, false)
+	HERRVerificationThrottled = httperrors.NewHTTPError(http.StatusTooManyRequests, "too many requests",
+		"a verification email was sent recently, please retry later", nil, false)
+)
+
+// Send a verification token to the email of the user, return an error if the last one was sent too recently
+//
+// The previous tokens of the user can't be used anymore.
+func (userService *userServiceImpl) SendEmailVerification(userID uuid.UUID) httperrors.HTTPError {
+	user, herr := userService.GetUserByID(userID)
+	if herr != nil {
+		return herr
+	}
+	return userService.sendEmailVerification(user)
+}
+
+// Send a new verification token to the user of the email
+//
+// Nothing is done if there is no unverified user with this email, and the throttling and sending errors are only logged,
+// so that the response doesn't tell if an account exists.
+func (userService *userServiceImpl) ResendEmailVerification(email string) httperrors.HTTPError {
+	user, herr := userService.GetUserByEmail(email)
+	if herr != nil {
+		if impl, ok := herr.(*httperrors.HTTPErrorImpl); !ok || impl.Status != http.StatusNotFound {
+			userService.logger.Error("Failed to get the user requesting a verification email", zap.Error(herr))
+		}
+		return nil
+	}
+	if user.EmailVerified {
+		return nil
+	}
+	herr = userService.sendEmailVerification(user)
+	if herr == HERRVerificationThrottled {
+		userService.logger.Info("Verification email throttled", zap.String("userID", user.ID.String()))
+	} else if herr != nil {
+		userService.logger.Error("Failed to send a verification email",
+			zap.String("userID", user.ID.String()), zap.Error(herr))
+	}
+	return nil
+}
+
+// Mark the email of the user of the token as verified, the token can't be used again
+func (userService *userServiceImpl) VerifyEmail(token string) (*models.User, httperrors.HTTPError) {
+	tokens, herr := userService.emailVerificationTokenRepository.Find(
+		squirrel.Eq{"token_hash": hashToken(token)}, nil, nil)
+	if herr != nil {
+		return nil, herr
+	}
+	if !tokens.HasContent || tokens.Ressources[0].IsExpired() {
+		return nil, HERRInvalidVerificationToken
+	}
+	user, herr := userService.GetUserByID(tokens.Ressources[0].UserID)
+	if herr != nil {
+		return nil, herr
+	}
+	herr = userService.setEmailVerified(user)
+	if herr != nil {
+		return nil, herr
+	}
+	return user, nil
+}
+
+// Mark the email of a user as verified without sending a token
+func (userService *userServiceImpl) MarkEmailVerified(userID uuid.UUID) httperrors.HTTPError {
+	user, herr := userService.GetUserByID(userID)
+	if herr != nil {
+		return herr
+	}
+	if user.EmailVerified {
+		return nil
+	}
+	return userService.setEmailVerified(user)
+}
+
+// Create a verification token for the user and send it
+func (userService *userServiceImpl) sendEmailVerification(user *models.User) httperrors.HTTPError {
+	tokens, herr := userService.emailVerificationTokenRepository.Find(
+		squirrel.Eq{"user_id": user.ID.String()}, nil, nil)
+	if herr != nil {
+		return herr
+	}
+	resendInterval := userService.emailVerificationConfiguration.GetResendInterval()
+	for _, previousToken := range tokens.Ressources {
+		if time.Since(previousToken.CreatedAt) < resendInterval {
+			return HERRVerificationThrottled
+		}
+	}
+	herr = userService.deleteEmailVerificationTokens(tokens.Ressources)
+	if herr != nil {
+		return herr
+	}
+	token, err := generateToken()
+	if err != nil {
+		return httperrors.NewInternalServerError("token error", "failed to generate a token", err)
+	}
+	herr = userService.emailVerificationTokenRepository.Create(&models.EmailVerificationToken{
+		UserID:    user.ID,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(userService.emailVerificationConfiguration.GetTokenDuration()),
+	})
+	if herr != nil {
+		return herr
+	}
+	userService.logger.Info("Sent an email verification", zap.String("userID", user.ID.String()))
+	return userService.sendTokenMail(user.Email, user, mailservice.TemplateEmailVerification, token)
+}
+
+// Save the user with a verified email and delete its verification tokens
+func (userService *userServiceImpl) setEmailVerified(user *models.User) httperrors.HTTPError {
+	user.EmailVerified = true
+	herr := userService.userRepository.Save(user)
+	if herr != nil {
+		return herr
+	}
+	tokens, herr := userService.emailVerificationTokenRepository.Find(
+		squirrel.Eq{"user_id": user.ID.String()}, nil, nil)
+	if herr != nil {
+		return herr
+	}
+	herr = userService.deleteEmailVerificationTokens(tokens.Ressources)
+	if herr != nil {
+		return herr
+	}
+	userService.logger.Info("Verified user email", zap.String("userID", user.ID.String()), zap.String("email", user.Email))
+	return nil
+}
+
+// Delete verification tokens
+func (userService *userServiceImpl) deleteEmailVerificationTokens(tokens []*models.EmailVerificationToken) httperrors.HTTPError {
+	for _, token := range tokens {
+		herr := userService.emailVerificationTokenRepository.Delete(token)
+		if herr != nil {
+			return herr
+		}
+	}
+	return nil
+}
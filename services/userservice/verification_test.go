@@ -0,0 +1,128 @@
+package userservice_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	repositorymocks "github.com/ditrit/badaas/mocks/persistence/repository"
+	sessionservicemocks "github.com/ditrit/badaas/mocks/services/sessionservice"
+	"github.com/ditrit/badaas/persistence/models"
+	"github.com/ditrit/badaas/persistence/models/dto"
+	"github.com/ditrit/badaas/persistence/pagination"
+	"github.com/ditrit/badaas/services/userservice"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func setupVerificationTest(t *testing.T, required bool) (
+	*repositorymocks.CRUDRepository[models.User, uuid.UUID],
+	*repositorymocks.CRUDRepository[models.EmailVerificationToken, uuid.UUID],
+	userservice.UserService,
+) {
+	userRepositoryMock := repositorymocks.NewCRUDRepository[models.User, uuid.UUID](t)
+	tokenRepositoryMock := repositorymocks.NewCRUDRepository[models.EmailVerificationToken, uuid.UUID](t)
+	userService := userservice.NewUserService(zap.L(), userRepositoryMock, sessionservicemocks.NewSessionService(t),
+		repositorymocks.NewCRUDRepository[models.EmailChange, uuid.UUID](t),
+		tokenRepositoryMock, newEmailVerificationConfiguration(t, required),
+		repositorymocks.NewCRUDRepository[models.PasswordResetToken, uuid.UUID](t), newPasswordResetConfiguration(t), newMailer(t), newPasswordHasher(t), newPasswordPolicyService(t))
+	return userRepositoryMock, tokenRepositoryMock, userService
+}
+
+func TestGetUserEmailNotVerified(t *testing.T) {
+	userRepositoryMock, _, userService := setupVerificationTest(t, true)
+	user := &models.User{Email: "bob@email.com", Password: hashPassword(t, "1234")}
+	userRepositoryMock.On("Find", mock.Anything, nil, nil).
+		Return(pagination.NewPage([]*models.User{user}, 1, 10, 1), nil)
+
+	userFound, err := userService.GetUser(dto.UserLoginDTO{Email: "bob@email.com", Password: "1234"})
+	assert.Equal(t, userservice.HERREmailNotVerified, err)
+	assert.Nil(t, userFound)
+}
+
+func TestSendEmailVerification(t *testing.T) {
+	userRepositoryMock, tokenRepositoryMock, userService := setupVerificationTest(t, true)
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Email: "bob@email.com"}
+	oldToken := &models.EmailVerificationToken{BaseModel: models.BaseModel{CreatedAt: time.Now().Add(-time.Hour)}}
+	userRepositoryMock.On("Find", mock.Anything, nil, nil).
+		Return(pagination.NewPage([]*models.User{user}, 1, 10, 1), nil)
+	tokenRepositoryMock.On("Find", squirrel.Eq{"user_id": user.ID.String()}, nil, nil).
+		Return(pagination.NewPage([]*models.EmailVerificationToken{oldToken}, 1, 10, 1), nil)
+	tokenRepositoryMock.On("Delete", oldToken).Return(nil)
+	tokenRepositoryMock.On("Create", mock.Anything).Return(nil)
+
+	err := userService.SendEmailVerification(user.ID)
+	require.NoError(t, err)
+	token := tokenRepositoryMock.Calls[2].Arguments.Get(0).(*models.EmailVerificationToken)
+	assert.Equal(t, user.ID, token.UserID)
+	assert.NotEmpty(t, token.TokenHash)
+	assert.False(t, token.IsExpired())
+}
+
+func TestSendEmailVerificationThrottled(t *testing.T) {
+	userRepositoryMock, tokenRepositoryMock, userService := setupVerificationTest(t, true)
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Email: "bob@email.com"}
+	recentToken := &models.EmailVerificationToken{BaseModel: models.BaseModel{CreatedAt: time.Now()}}
+	userRepositoryMock.On("Find", mock.Anything, nil, nil).
+		Return(pagination.NewPage([]*models.User{user}, 1, 10, 1), nil)
+	tokenRepositoryMock.On("Find", mock.Anything, nil, nil).
+		Return(pagination.NewPage([]*models.EmailVerificationToken{recentToken}, 1, 10, 1), nil)
+
+	err := userService.SendEmailVerification(user.ID)
+	assert.Equal(t, userservice.HERRVerificationThrottled, err)
+	tokenRepositoryMock.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestResendEmailVerificationUnknownEmail(t *testing.T) {
+	userRepositoryMock, _, userService := setupVerificationTest(t, true)
+	userRepositoryMock.On("Find", mock.Anything, nil, nil).
+		Return(pagination.NewPage([]*models.User{}, 1, 10, 0), nil)
+
+	err := userService.ResendEmailVerification("nobody@email.com")
+	assert.NoError(t, err)
+}
+
+func TestResendEmailVerificationThrottled(t *testing.T) {
+	userRepositoryMock, tokenRepositoryMock, userService := setupVerificationTest(t, true)
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Email: "bob@email.com"}
+	recentToken := &models.EmailVerificationToken{BaseModel: models.BaseModel{CreatedAt: time.Now()}}
+	userRepositoryMock.On("Find", mock.Anything, nil, nil).
+		Return(pagination.NewPage([]*models.User{user}, 1, 10, 1), nil)
+	tokenRepositoryMock.On("Find", mock.Anything, nil, nil).
+		Return(pagination.NewPage([]*models.EmailVerificationToken{recentToken}, 1, 10, 1), nil)
+
+	// the response of an unknown email is the same
+	err := userService.ResendEmailVerification("bob@email.com")
+	assert.NoError(t, err)
+	tokenRepositoryMock.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestVerifyEmail(t *testing.T) {
+	userRepositoryMock, tokenRepositoryMock, userService := setupVerificationTest(t, true)
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Email: "bob@email.com"}
+	token := &models.EmailVerificationToken{UserID: user.ID, ExpiresAt: time.Now().Add(time.Hour)}
+	tokenRepositoryMock.On("Find", mock.Anything, nil, nil).
+		Return(pagination.NewPage([]*models.EmailVerificationToken{token}, 1, 10, 1), nil)
+	tokenRepositoryMock.On("Delete", token).Return(nil)
+	userRepositoryMock.On("Find", mock.Anything, nil, nil).
+		Return(pagination.NewPage([]*models.User{user}, 1, 10, 1), nil)
+	userRepositoryMock.On("Save", user).Return(nil)
+
+	verifiedUser, err := userService.VerifyEmail("token")
+	require.NoError(t, err)
+	assert.True(t, verifiedUser.EmailVerified)
+}
+
+func TestVerifyEmailExpiredToken(t *testing.T) {
+	_, tokenRepositoryMock, userService := setupVerificationTest(t, true)
+	token := &models.EmailVerificationToken{UserID: uuid.New(), ExpiresAt: time.Now().Add(-time.Hour)}
+	tokenRepositoryMock.On("Find", mock.Anything, nil, nil).
+		Return(pagination.NewPage([]*models.EmailVerificationToken{token}, 1, 10, 1), nil)
+
+	user, err := userService.VerifyEmail("token")
+	assert.Equal(t, userservice.HERRInvalidVerificationToken, err)
+	assert.Nil(t, user)
+}
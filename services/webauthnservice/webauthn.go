@@ -0,0 +1,357 @@
+package webauthnservice
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/ditrit/badaas/configuration"
+	"github.com/ditrit/badaas/httperrors"
+	"github.com/ditrit/badaas/persistence/models"
+	"github.com/ditrit/badaas/persistence/repository"
+	"github.com/ditrit/badaas/services/auth/protocols/webauthn"
+	"github.com/ditrit/badaas/services/userservice"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Errors
+var (
+	HERRInvalidCeremony = httperrors.NewUnauthorizedError("invalid ceremony",
+		"the WebAuthn ceremony is invalid or expired, please start again")
+	HERRInvalidCredential  = httperrors.NewUnauthorizedError("invalid credential", "the credential could not be verified")
+	HERRRegistrationFailed = httperrors.NewHTTPError(http.StatusBadRequest, "registration failed",
+		"the new credential could not be verified", nil, false)
+	HERRCredentialAlreadyRegistered = httperrors.NewHTTPError(http.StatusConflict, "credential already registered",
+		"the credential is already registered", nil, false)
+)
+
+// WebAuthnService handle the WebAuthn credentials of the users and their ceremonies
+//
+// A credential can be used as a passkey to log in without password, or as a second factor after the password.
+type WebAuthnService interface {
+	// Return the options of the registration of a new credential for the user
+	BeginRegistration(userID uuid.UUID) (*webauthn.CreationOptions, httperrors.HTTPError)
+	// Verify the response of the authenticator and store the new credential of the user
+	FinishRegistration(userID uuid.UUID, name string, response *webauthn.CredentialCreationResponse) (*models.WebAuthnCredential, httperrors.HTTPError)
+	// Return the credentials of the user
+	GetCredentials(userID uuid.UUID) ([]*models.WebAuthnCredential, httperrors.HTTPError)
+	// Return true if the user has at least one credential
+	HasCredentials(userID uuid.UUID) (bool, httperrors.HTTPError)
+	// Delete a credential of the user
+	DeleteCredential(userID, credentialID uuid.UUID) httperrors.HTTPError
+	// Return the options of a login, restricted to the credentials of the user if given,
+	// or accepting any passkey with user verification if nil
+	BeginLogin(userID *uuid.UUID) (*webauthn.RequestOptions, httperrors.HTTPError)
+	// Verify the assertion of the authenticator and return the user of the credential, the ceremony can't be used again
+	FinishLogin(response *webauthn.CredentialAssertionResponse) (uuid.UUID, httperrors.HTTPError)
+}
+
+// Check interface compliance
+var _ WebAuthnService = (*webAuthnServiceImpl)(nil)
+
+// WebAuthnService implementation
+type webAuthnServiceImpl struct {
+	logger                       *zap.Logger
+	webAuthnConfiguration        configuration.WebAuthnConfiguration
+	webAuthnCredentialRepository repository.CRUDRepository[models.WebAuthnCredential, uuid.UUID]
+	webAuthnCeremonyRepository   repository.CRUDRepository[models.WebAuthnCeremony, uuid.UUID]
+	userService                  userservice.UserService
+}
+
+// WebAuthnService constructor
+func NewWebAuthnService(
+	logger *zap.Logger,
+	webAuthnConfiguration configuration.WebAuthnConfiguration,
+	webAuthnCredentialRepository repository.CRUDRepository[models.WebAuthnCredential, uuid.UUID],
+	webAuthnCeremonyRepository repository.CRUDRepository[models.WebAuthnCeremony, uuid.UUID],
+	userService userservice.UserService,
+) WebAuthnService {
+	return &webAuthnServiceImpl{
+		logger:                       logger,
+		webAuthnConfiguration:        webAuthnConfiguration,
+		webAuthnCredentialRepository: webAuthnCredentialRepository,
+		webAuthnCeremonyRepository:   webAuthnCeremonyRepository,
+		userService:                  userService,
+	}
+}
+
+// Return the options of the registration of a new credential for the user
+func (webAuthnService *webAuthnServiceImpl) BeginRegistration(userID uuid.UUID) (*webauthn.CreationOptions, httperrors.HTTPError) {
+	user, herr := webAuthnService.userService.GetUserByID(userID)
+	if herr != nil {
+		return nil, herr
+	}
+	credentials, herr := webAuthnService.GetCredentials(userID)
+	if herr != nil {
+		return nil, herr
+	}
+	options, err := webAuthnService.relyingParty().NewCreationOptions(
+		webauthn.UserEntity{
+			// the user handle returned by the passkeys
+			ID:          user.ID[:],
+			Name:        user.Email,
+			DisplayName: user.Username,
+		},
+		makeDescriptors(credentials),
+		webAuthnService.userVerification(),
+	)
+	if err != nil {
+		return nil, httperrors.NewInternalServerError("challenge error", "failed to generate a challenge", err)
+	}
+	herr = webAuthnService.createCeremony(&userID, models.WebAuthnRegistration, options.Challenge)
+	if herr != nil {
+		return nil, herr
+	}
+	return options, nil
+}
+
+// Verify the response of the authenticator and store the new credential of the user
+func (webAuthnService *webAuthnServiceImpl) FinishRegistration(
+	userID uuid.UUID,
+	name string,
+	response *webauthn.CredentialCreationResponse,
+) (*models.WebAuthnCredential, httperrors.HTTPError) {
+	ceremony, herr := webAuthnService.getCeremony(response.Response.ClientDataJSON, models.WebAuthnRegistration)
+	if herr != nil {
+		return nil, herr
+	}
+	if ceremony.UserID == nil || *ceremony.UserID != userID {
+		return nil, HERRInvalidCeremony
+	}
+	herr = webAuthnService.webAuthnCeremonyRepository.Delete(ceremony)
+	if herr != nil {
+		return nil, herr
+	}
+	challenge, _ := base64.RawURLEncoding.DecodeString(ceremony.Challenge)
+	credential, err := webAuthnService.relyingParty().VerifyRegistration(challenge, response, webAuthnService.userVerification())
+	if err != nil {
+		webAuthnService.logger.Info("Rejected a WebAuthn registration",
+			zap.String("userID", userID.String()), zap.Error(err))
+		return nil, HERRRegistrationFailed
+	}
+	existingCredential, herr := webAuthnService.getCredentialByCredentialID(credential.ID)
+	if herr != nil {
+		return nil, herr
+	}
+	if existingCredential != nil {
+		return nil, HERRCredentialAlreadyRegistered
+	}
+	if name == "" {
+		name = fmt.Sprintf("Passkey created on %s", time.Now().Format("2006-01-02"))
+	}
+	webAuthnCredential := &models.WebAuthnCredential{
+		UserID:       userID,
+		CredentialID: credential.ID,
+		PublicKey:    credential.PublicKey,
+		SignCount:    credential.SignCount,
+		AAGUID:       credential.AAGUID,
+		Transports:   strings.Join(credential.Transports, ","),
+		Name:         name,
+	}
+	herr = webAuthnService.webAuthnCredentialRepository.Create(webAuthnCredential)
+	if herr != nil {
+		return nil, herr
+	}
+	webAuthnService.logger.Info("Registered a WebAuthn credential", zap.String("userID", userID.String()))
+	return webAuthnCredential, nil
+}
+
+// Return the credentials of the user
+func (webAuthnService *webAuthnServiceImpl) GetCredentials(userID uuid.UUID) ([]*models.WebAuthnCredential, httperrors.HTTPError) {
+	credentials, herr := webAuthnService.webAuthnCredentialRepository.Find(
+		squirrel.Eq{"user_id": userID.String()}, nil, nil)
+	if herr != nil {
+		return nil, herr
+	}
+	return credentials.Ressources, nil
+}
+
+// Return true if the user has at least one credential
+func (webAuthnService *webAuthnServiceImpl) HasCredentials(userID uuid.UUID) (bool, httperrors.HTTPError) {
+	credentials, herr := webAuthnService.GetCredentials(userID)
+	if herr != nil {
+		return false, herr
+	}
+	return len(credentials) > 0, nil
+}
+
+// Delete a credential of the user
+func (webAuthnService *webAuthnServiceImpl) DeleteCredential(userID, credentialID uuid.UUID) httperrors.HTTPError {
+	credential, herr := webAuthnService.webAuthnCredentialRepository.GetByID(credentialID)
+	if herr != nil {
+		return herr
+	}
+	if credential.UserID != userID {
+		return httperrors.NewErrorNotFound("credential", fmt.Sprintf("no credential found with id %q", credentialID))
+	}
+	return webAuthnService.webAuthnCredentialRepository.Delete(credential)
+}
+
+// Return the options of a login, restricted to the credentials of the user if given,
+// or accepting any passkey with user verification if nil
+func (webAuthnService *webAuthnServiceImpl) BeginLogin(userID *uuid.UUID) (*webauthn.RequestOptions, httperrors.HTTPError) {
+	// a passkey replaces both the password and the second factor
+	userVerification := webauthn.UserVerificationRequired
+	var allowCredentials []webauthn.CredentialDescriptor
+	if userID != nil {
+		credentials, herr := webAuthnService.GetCredentials(*userID)
+		if herr != nil {
+			return nil, herr
+		}
+		if len(credentials) == 0 {
+			return nil, HERRInvalidCredential
+		}
+		allowCredentials = makeDescriptors(credentials)
+		userVerification = webAuthnService.userVerification()
+	}
+	options, err := webAuthnService.relyingParty().NewRequestOptions(allowCredentials, userVerification)
+	if err != nil {
+		return nil, httperrors.NewInternalServerError("challenge error", "failed to generate a challenge", err)
+	}
+	herr := webAuthnService.createCeremony(userID, models.WebAuthnLogin, options.Challenge)
+	if herr != nil {
+		return nil, herr
+	}
+	return options, nil
+}
+
+// Verify the assertion of the authenticator and return the user of the credential, the ceremony can't be used again
+//
+// A credential whose signature counter went backwards is refused, its authenticator may have been cloned.
+func (webAuthnService *webAuthnServiceImpl) FinishLogin(response *webauthn.CredentialAssertionResponse) (uuid.UUID, httperrors.HTTPError) {
+	ceremony, herr := webAuthnService.getCeremony(response.Response.ClientDataJSON, models.WebAuthnLogin)
+	if herr != nil {
+		return uuid.Nil, herr
+	}
+	herr = webAuthnService.webAuthnCeremonyRepository.Delete(ceremony)
+	if herr != nil {
+		return uuid.Nil, herr
+	}
+	credential, herr := webAuthnService.getCredentialByCredentialID(response.RawID)
+	if herr != nil {
+		return uuid.Nil, herr
+	}
+	if credential == nil {
+		return uuid.Nil, HERRInvalidCredential
+	}
+	userVerification := webauthn.UserVerificationRequired
+	if ceremony.UserID != nil {
+		if *ceremony.UserID != credential.UserID {
+			return uuid.Nil, HERRInvalidCredential
+		}
+		userVerification = webAuthnService.userVerification()
+	} else if len(response.Response.UserHandle) != 0 && string(response.Response.UserHandle) != string(credential.UserID[:]) {
+		return uuid.Nil, HERRInvalidCredential
+	}
+
+	challenge, _ := base64.RawURLEncoding.DecodeString(ceremony.Challenge)
+	assertion, err := webAuthnService.relyingParty().VerifyAssertion(
+		challenge, credential.PublicKey, credential.SignCount, response, userVerification)
+	if errors.Is(err, webauthn.ErrSignCount) {
+		webAuthnService.logger.Warn("Refused a WebAuthn credential whose signature counter went backwards",
+			zap.String("userID", credential.UserID.String()), zap.String("credentialID", credential.ID.String()))
+		return uuid.Nil, HERRInvalidCredential
+	}
+	if err != nil {
+		webAuthnService.logger.Info("Rejected a WebAuthn assertion",
+			zap.String("userID", credential.UserID.String()), zap.Error(err))
+		return uuid.Nil, HERRInvalidCredential
+	}
+	now := time.Now()
+	credential.SignCount = assertion.SignCount
+	credential.LastUsedAt = &now
+	herr = webAuthnService.webAuthnCredentialRepository.Save(credential)
+	if herr != nil {
+		return uuid.Nil, herr
+	}
+	return credential.UserID, nil
+}
+
+// Store a ceremony waiting for the response of the authenticator
+func (webAuthnService *webAuthnServiceImpl) createCeremony(userID *uuid.UUID, kind string, challenge []byte) httperrors.HTTPError {
+	return webAuthnService.webAuthnCeremonyRepository.Create(&models.WebAuthnCeremony{
+		UserID:    userID,
+		Challenge: base64.RawURLEncoding.EncodeToString(challenge),
+		Kind:      kind,
+		ExpiresAt: time.Now().Add(webAuthnService.webAuthnConfiguration.GetTimeout()),
+	})
+}
+
+// Return the ceremony of the challenge signed in the client data, or an error if it doesn't exist or is expired
+//
+// An expired ceremony is deleted.
+func (webAuthnService *webAuthnServiceImpl) getCeremony(clientDataJSON []byte, kind string) (*models.WebAuthnCeremony, httperrors.HTTPError) {
+	challenge, err := webauthn.ParseChallenge(clientDataJSON)
+	if err != nil {
+		return nil, HERRInvalidCeremony
+	}
+	ceremonies, herr := webAuthnService.webAuthnCeremonyRepository.Find(squirrel.Eq{
+		"challenge": base64.RawURLEncoding.EncodeToString(challenge),
+		"kind":      kind,
+	}, nil, nil)
+	if herr != nil {
+		return nil, herr
+	}
+	if !ceremonies.HasContent {
+		return nil, HERRInvalidCeremony
+	}
+	ceremony := ceremonies.Ressources[0]
+	if ceremony.IsExpired() {
+		herr = webAuthnService.webAuthnCeremonyRepository.Delete(ceremony)
+		if herr != nil {
+			return nil, herr
+		}
+		return nil, HERRInvalidCeremony
+	}
+	return ceremony, nil
+}
+
+// Return the credential with the id given by the authenticator, or nil
+func (webAuthnService *webAuthnServiceImpl) getCredentialByCredentialID(credentialID []byte) (*models.WebAuthnCredential, httperrors.HTTPError) {
+	credentials, herr := webAuthnService.webAuthnCredentialRepository.Find(
+		squirrel.Eq{"credential_id": credentialID}, nil, nil)
+	if herr != nil {
+		return nil, herr
+	}
+	if !credentials.HasContent {
+		return nil, nil
+	}
+	return credentials.Ressources[0], nil
+}
+
+// Return the relying party described by the configuration
+func (webAuthnService *webAuthnServiceImpl) relyingParty() *webauthn.RelyingParty {
+	return &webauthn.RelyingParty{
+		ID:      webAuthnService.webAuthnConfiguration.GetRelyingPartyID(),
+		Name:    webAuthnService.webAuthnConfiguration.GetRelyingPartyName(),
+		Origins: webAuthnService.webAuthnConfiguration.GetOrigins(),
+		Timeout: webAuthnService.webAuthnConfiguration.GetTimeout(),
+	}
+}
+
+// Return the user verification asked for the registrations and the second factors
+func (webAuthnService *webAuthnServiceImpl) userVerification() webauthn.UserVerification {
+	return webauthn.UserVerification(webAuthnService.webAuthnConfiguration.GetUserVerification())
+}
+
+// Describe the credentials for the options of the ceremonies
+func makeDescriptors(credentials []*models.WebAuthnCredential) []webauthn.CredentialDescriptor {
+	descriptors := make([]webauthn.CredentialDescriptor, 0, len(credentials))
+	for _, credential := range credentials {
+		var transports []string
+		if credential.Transports != "" {
+			transports = strings.Split(credential.Transports, ",")
+		}
+		descriptors = append(descriptors, webauthn.CredentialDescriptor{
+			Type:       webauthn.PublicKeyCredentialType,
+			ID:         credential.CredentialID,
+			Transports: transports,
+		})
+	}
+	return descriptors
+}
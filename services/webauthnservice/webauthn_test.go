@@ -0,0 +1,242 @@
+package webauthnservice_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/ditrit/badaas/httperrors"
+	mocksConfiguration "github.com/ditrit/badaas/mocks/configuration"
+	mocksRepository "github.com/ditrit/badaas/mocks/persistence/repository"
+	mocksUserService "github.com/ditrit/badaas/mocks/services/userservice"
+	"github.com/ditrit/badaas/persistence/models"
+	"github.com/ditrit/badaas/persistence/pagination"
+	"github.com/ditrit/badaas/services/auth/protocols/webauthn"
+	"github.com/ditrit/badaas/services/auth/protocols/webauthn/webauthntest"
+	"github.com/ditrit/badaas/services/webauthnservice"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const origin = "https://badaas.example.com"
+
+type testSetup struct {
+	credentialRepository *mocksRepository.CRUDRepository[models.WebAuthnCredential, uuid.UUID]
+	ceremonyRepository   *mocksRepository.CRUDRepository[models.WebAuthnCeremony, uuid.UUID]
+	userService          *mocksUserService.UserService
+	service              webauthnservice.WebAuthnService
+	// the last ceremony created by the service
+	ceremony *models.WebAuthnCeremony
+}
+
+func setupTest(t *testing.T) *testSetup {
+	webAuthnConfiguration := mocksConfiguration.NewWebAuthnConfiguration(t)
+	webAuthnConfiguration.On("GetRelyingPartyID").Return("badaas.example.com").Maybe()
+	webAuthnConfiguration.On("GetRelyingPartyName").Return("badaas").Maybe()
+	webAuthnConfiguration.On("GetOrigins").Return([]string{origin}).Maybe()
+	webAuthnConfiguration.On("GetTimeout").Return(5 * time.Minute).Maybe()
+	webAuthnConfiguration.On("GetUserVerification").Return("preferred").Maybe()
+	setup := &testSetup{
+		credentialRepository: mocksRepository.NewCRUDRepository[models.WebAuthnCredential, uuid.UUID](t),
+		ceremonyRepository:   mocksRepository.NewCRUDRepository[models.WebAuthnCeremony, uuid.UUID](t),
+		userService:          mocksUserService.NewUserService(t),
+	}
+	setup.ceremonyRepository.On("Create", mock.AnythingOfType("*models.WebAuthnCeremony")).
+		Run(func(args mock.Arguments) {
+			setup.ceremony = args.Get(0).(*models.WebAuthnCeremony)
+		}).Return(nil).Maybe()
+	setup.service = webauthnservice.NewWebAuthnService(zap.NewNop(), webAuthnConfiguration,
+		setup.credentialRepository, setup.ceremonyRepository, setup.userService)
+	return setup
+}
+
+func (setup *testSetup) onFindCredentials(userID uuid.UUID, credentials ...*models.WebAuthnCredential) {
+	setup.credentialRepository.On("Find", squirrel.Eq{"user_id": userID.String()}, nil, nil).
+		Return(pagination.NewPage(credentials, 1, 10, uint(len(credentials))), nil)
+}
+
+func (setup *testSetup) onFindCredential(credentialID []byte, credentials ...*models.WebAuthnCredential) {
+	setup.credentialRepository.On("Find", squirrel.Eq{"credential_id": credentialID}, nil, nil).
+		Return(pagination.NewPage(credentials, 1, 10, uint(len(credentials))), nil)
+}
+
+// Return the last created ceremony when it is looked up, it can be deleted
+func (setup *testSetup) onFindCeremony(kind string) {
+	setup.ceremonyRepository.On("Find", mock.MatchedBy(func(eq squirrel.Eq) bool {
+		return eq["kind"] == kind && eq["challenge"] == setup.ceremony.Challenge
+	}), nil, nil).Return(pagination.NewPage([]*models.WebAuthnCeremony{setup.ceremony}, 1, 10, 1), nil)
+	setup.ceremonyRepository.On("Delete", mock.AnythingOfType("*models.WebAuthnCeremony")).Return(nil).Maybe()
+}
+
+// Register the credential of a new software authenticator for the user, without the service
+func registerCredential(t *testing.T, userID uuid.UUID) (*webauthntest.Authenticator, *models.WebAuthnCredential) {
+	relyingParty := &webauthn.RelyingParty{ID: "badaas.example.com", Name: "badaas", Origins: []string{origin}}
+	options, err := relyingParty.NewCreationOptions(webauthn.UserEntity{ID: userID[:]}, nil, webauthn.UserVerificationPreferred)
+	require.NoError(t, err)
+	authenticator := webauthntest.NewAuthenticator(origin)
+	response, err := authenticator.CreateCredential(options)
+	require.NoError(t, err)
+	credential, err := relyingParty.VerifyRegistration(options.Challenge, response, webauthn.UserVerificationPreferred)
+	require.NoError(t, err)
+	return authenticator, &models.WebAuthnCredential{
+		BaseModel:    models.BaseModel{ID: uuid.New()},
+		UserID:       userID,
+		CredentialID: credential.ID,
+		PublicKey:    credential.PublicKey,
+	}
+}
+
+func TestRegistration(t *testing.T) {
+	setup := setupTest(t)
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Username: "bob", Email: "bob@email.com"}
+	setup.userService.On("GetUserByID", user.ID).Return(user, nil)
+	setup.onFindCredentials(user.ID)
+
+	options, herr := setup.service.BeginRegistration(user.ID)
+	require.Nil(t, herr)
+	assert.Equal(t, "badaas.example.com", options.RelyingParty.ID)
+	assert.Equal(t, webauthn.URLEncodedBase64(user.ID[:]), options.User.ID)
+	assert.Equal(t, models.WebAuthnRegistration, setup.ceremony.Kind)
+
+	authenticator := webauthntest.NewAuthenticator(origin)
+	response, err := authenticator.CreateCredential(options)
+	require.NoError(t, err)
+	setup.onFindCeremony(models.WebAuthnRegistration)
+	setup.onFindCredential(authenticator.CredentialID)
+	setup.credentialRepository.On("Create", mock.AnythingOfType("*models.WebAuthnCredential")).Return(nil)
+
+	credential, herr := setup.service.FinishRegistration(user.ID, "My phone", response)
+	require.Nil(t, herr)
+	assert.Equal(t, user.ID, credential.UserID)
+	assert.Equal(t, authenticator.CredentialID, credential.CredentialID)
+	assert.Equal(t, "My phone", credential.Name)
+	assert.Equal(t, "internal", credential.Transports)
+}
+
+func TestRegistrationOfAnotherUserCeremony(t *testing.T) {
+	setup := setupTest(t)
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Email: "bob@email.com"}
+	setup.userService.On("GetUserByID", user.ID).Return(user, nil)
+	setup.onFindCredentials(user.ID)
+	options, herr := setup.service.BeginRegistration(user.ID)
+	require.Nil(t, herr)
+	response, err := webauthntest.NewAuthenticator(origin).CreateCredential(options)
+	require.NoError(t, err)
+	setup.onFindCeremony(models.WebAuthnRegistration)
+
+	_, herr = setup.service.FinishRegistration(uuid.New(), "", response)
+	assert.Equal(t, webauthnservice.HERRInvalidCeremony, herr)
+}
+
+func TestPasskeyLogin(t *testing.T) {
+	setup := setupTest(t)
+	userID := uuid.New()
+	authenticator, credential := registerCredential(t, userID)
+
+	options, herr := setup.service.BeginLogin(nil)
+	require.Nil(t, herr)
+	assert.Empty(t, options.AllowCredentials)
+	assert.Equal(t, webauthn.UserVerificationRequired, options.UserVerification)
+	assert.Nil(t, setup.ceremony.UserID)
+
+	response, err := authenticator.GetAssertion(options)
+	require.NoError(t, err)
+	setup.onFindCeremony(models.WebAuthnLogin)
+	setup.onFindCredential(authenticator.CredentialID, credential)
+	setup.credentialRepository.On("Save", credential).Return(nil)
+
+	loggedUserID, herr := setup.service.FinishLogin(response)
+	require.Nil(t, herr)
+	assert.Equal(t, userID, loggedUserID)
+	assert.Equal(t, uint32(1), credential.SignCount)
+	assert.NotNil(t, credential.LastUsedAt)
+}
+
+func TestPasskeyLoginWithoutUserVerification(t *testing.T) {
+	setup := setupTest(t)
+	authenticator, credential := registerCredential(t, uuid.New())
+	authenticator.UserVerification = false
+
+	options, herr := setup.service.BeginLogin(nil)
+	require.Nil(t, herr)
+	response, err := authenticator.GetAssertion(options)
+	require.NoError(t, err)
+	setup.onFindCeremony(models.WebAuthnLogin)
+	setup.onFindCredential(authenticator.CredentialID, credential)
+
+	_, herr = setup.service.FinishLogin(response)
+	assert.Equal(t, webauthnservice.HERRInvalidCredential, herr)
+}
+
+func TestSecondFactorLogin(t *testing.T) {
+	setup := setupTest(t)
+	userID := uuid.New()
+	authenticator, credential := registerCredential(t, userID)
+	// the user verification is only preferred for a second factor
+	authenticator.UserVerification = false
+	setup.onFindCredentials(userID, credential)
+
+	options, herr := setup.service.BeginLogin(&userID)
+	require.Nil(t, herr)
+	require.Len(t, options.AllowCredentials, 1)
+	assert.Equal(t, webauthn.URLEncodedBase64(authenticator.CredentialID), options.AllowCredentials[0].ID)
+
+	response, err := authenticator.GetAssertion(options)
+	require.NoError(t, err)
+	setup.onFindCeremony(models.WebAuthnLogin)
+	setup.onFindCredential(authenticator.CredentialID, credential)
+	setup.credentialRepository.On("Save", credential).Return(nil)
+
+	loggedUserID, herr := setup.service.FinishLogin(response)
+	require.Nil(t, herr)
+	assert.Equal(t, userID, loggedUserID)
+}
+
+func TestSecondFactorLoginWithAnotherUserCredential(t *testing.T) {
+	setup := setupTest(t)
+	userID := uuid.New()
+	_, credential := registerCredential(t, userID)
+	otherAuthenticator, otherCredential := registerCredential(t, uuid.New())
+	setup.onFindCredentials(userID, credential)
+
+	options, herr := setup.service.BeginLogin(&userID)
+	require.Nil(t, herr)
+	// a malicious client ignores the allowed credentials
+	options.AllowCredentials = nil
+	response, err := otherAuthenticator.GetAssertion(options)
+	require.NoError(t, err)
+	setup.onFindCeremony(models.WebAuthnLogin)
+	setup.onFindCredential(otherAuthenticator.CredentialID, otherCredential)
+
+	_, herr = setup.service.FinishLogin(response)
+	assert.Equal(t, webauthnservice.HERRInvalidCredential, herr)
+}
+
+func TestFinishLoginUnknownCeremony(t *testing.T) {
+	setup := setupTest(t)
+	authenticator, _ := registerCredential(t, uuid.New())
+	response, err := authenticator.GetAssertion(&webauthn.RequestOptions{
+		Challenge:      []byte("unknown challenge"),
+		RelyingPartyID: "badaas.example.com",
+	})
+	require.NoError(t, err)
+	setup.ceremonyRepository.On("Find", mock.Anything, nil, nil).
+		Return(pagination.NewPage([]*models.WebAuthnCeremony{}, 1, 10, 0), nil)
+
+	_, herr := setup.service.FinishLogin(response)
+	assert.Equal(t, webauthnservice.HERRInvalidCeremony, herr)
+}
+
+func TestDeleteCredentialOfAnotherUser(t *testing.T) {
+	setup := setupTest(t)
+	credential := &models.WebAuthnCredential{BaseModel: models.BaseModel{ID: uuid.New()}, UserID: uuid.New()}
+	setup.credentialRepository.On("GetByID", credential.ID).Return(credential, nil)
+
+	herr := setup.service.DeleteCredential(uuid.New(), credential.ID)
+	require.NotNil(t, herr)
+	assert.Equal(t, http.StatusNotFound, herr.(*httperrors.HTTPErrorImpl).Status)
+}